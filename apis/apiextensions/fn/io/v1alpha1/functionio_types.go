@@ -57,6 +57,70 @@ type FunctionIO struct {
 	// that that are not concerned with.
 	// +optional
 	Results []Result `json:"results,omitempty"`
+
+	// ExtraResources that Crossplane fetched from the API server in order to
+	// satisfy the requirements this function returned on a previous
+	// invocation. Functions must not mutate this state - any attempts to do
+	// so will be ignored.
+	// +optional
+	ExtraResources []ExtraResources `json:"extraResources,omitempty"`
+
+	// Requirements that this function needs Crossplane to satisfy before it
+	// can produce its final desired state. Crossplane will fetch any
+	// requested extra resources and invoke the function again, supplying
+	// them in the extraResources array.
+	// +optional
+	Requirements *Requirements `json:"requirements,omitempty"`
+}
+
+// Requirements that a function may return to ask Crossplane to supply it with
+// additional state.
+type Requirements struct {
+	// ExtraResources that the function requires. Each entry must have a name
+	// that is unique within the array.
+	// +optional
+	ExtraResources []ExtraResourceRequirement `json:"extraResources,omitempty"`
+}
+
+// An ExtraResourceRequirement requests extra resources of a particular kind,
+// selected either by name or by labels.
+type ExtraResourceRequirement struct {
+	// Name of the requirement. Must be unique within the array of extra
+	// resource requirements. Corresponds to the name entry of the
+	// extraResources array supplied to the function.
+	Name string `json:"name"`
+
+	// APIVersion of the required resources.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the required resources.
+	Kind string `json:"kind"`
+
+	// Namespace of the required resources. Omit for cluster scoped
+	// resources.
+	// +optional
+	Namespace *string `json:"namespace,omitempty"`
+
+	// MatchName selects the single resource with the supplied name.
+	// +optional
+	MatchName *string `json:"matchName,omitempty"`
+
+	// MatchLabels selects all resources with the supplied labels. Ignored if
+	// matchName is set.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+}
+
+// ExtraResources that were fetched to satisfy an ExtraResourceRequirement.
+type ExtraResources struct {
+	// Name of the requirement these resources satisfy.
+	Name string `json:"name"`
+
+	// Resources that matched the requirement. Empty if no resources matched.
+	// +optional
+	// +kubebuilder:validation:EmbeddedResource
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Resources []runtime.RawExtension `json:"resources,omitempty"`
 }
 
 // Observed state at the beginning of a function pipeline invocation.
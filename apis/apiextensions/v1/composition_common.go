@@ -61,6 +61,52 @@ type PatchSet struct {
 	Patches []Patch `json:"patches"`
 }
 
+// A RemovedResourcePolicy determines what happens to a composed resource that
+// its Composition no longer produces.
+type RemovedResourcePolicy string
+
+// RemovedResourcePolicy types.
+const (
+	// RemovedResourcePolicyDelete deletes composed resources that their
+	// Composition no longer produces.
+	RemovedResourcePolicyDelete RemovedResourcePolicy = "Delete"
+
+	// RemovedResourcePolicyOrphan stops tracking composed resources that their
+	// Composition no longer produces, but leaves them in place.
+	RemovedResourcePolicyOrphan RemovedResourcePolicy = "Orphan"
+)
+
+// A WriteConnectionSecretPolicy determines when a composite resource's
+// connection details are published.
+type WriteConnectionSecretPolicy string
+
+// WriteConnectionSecretPolicy types.
+const (
+	// WriteConnectionSecretPolicyIncremental publishes connection details as
+	// they become available.
+	WriteConnectionSecretPolicyIncremental WriteConnectionSecretPolicy = "Incremental"
+
+	// WriteConnectionSecretPolicyWhenReady publishes connection details only
+	// once all declared connection details are available and the composite
+	// resource is ready.
+	WriteConnectionSecretPolicyWhenReady WriteConnectionSecretPolicy = "WhenReady"
+)
+
+// A ComposedResourceManagementPolicy determines how a Composition manages a
+// composed resource.
+type ComposedResourceManagementPolicy string
+
+// ComposedResourceManagementPolicy types.
+const (
+	// ComposedResourceManagementPolicyDefault creates, updates, and deletes
+	// the composed resource.
+	ComposedResourceManagementPolicyDefault ComposedResourceManagementPolicy = "Default"
+
+	// ComposedResourceManagementPolicyObserveOnly observes an existing
+	// composed resource, but never creates, updates, or deletes it.
+	ComposedResourceManagementPolicyObserveOnly ComposedResourceManagementPolicy = "ObserveOnly"
+)
+
 // ComposedTemplate is used to provide information about how the composed resource
 // should be processed.
 type ComposedTemplate struct {
@@ -95,6 +141,36 @@ type ComposedTemplate struct {
 	// +optional
 	// +kubebuilder:default={{type:"MatchCondition",matchCondition:{type:"Ready",status:"True"}}}
 	ReadinessChecks []ReadinessCheck `json:"readinessChecks,omitempty"`
+
+	// DependsOn lists the names of other entries in the resources array that
+	// this resource depends on. When a composite resource is deleted a
+	// composed resource is not deleted until all resources that depend on it
+	// have been deleted. Resources are still created in parallel. Only named
+	// resources may specify or be referenced by DependsOn.
+	// +optional
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// ManagementPolicy determines how this resource is managed. Default
+	// resources are created, updated, and deleted. ObserveOnly resources must
+	// already exist. They are identified by the name of the base resource, or
+	// by its labels if it has no name. They are never created, patched, or
+	// deleted, but may be used to patch the composite resource and to derive
+	// its connection details and readiness. ObserveOnly is not supported by
+	// Compositions that use functions.
+	// +optional
+	// +kubebuilder:validation:Enum=Default;ObserveOnly
+	ManagementPolicy *ComposedResourceManagementPolicy `json:"managementPolicy,omitempty"`
+
+	// NameTemplate is a Go template used to name the composed resource,
+	// instead of generating a name from the composite resource's name. It
+	// may reference .CompositeName, .ClaimName, .ClaimNamespace, and
+	// .TemplateName, for example "{{ .ClaimNamespace }}-{{ .ClaimName }}-db".
+	// The rendered name must be a valid Kubernetes object name. An existing
+	// resource with the rendered name is never adopted. Composed resources
+	// that were already named, for example before the template was added,
+	// keep their name.
+	// +optional
+	NameTemplate *string `json:"nameTemplate,omitempty"`
 }
 
 // GetName returns the name of the composed template or an empty string if it is nil.
@@ -105,6 +181,11 @@ func (ct *ComposedTemplate) GetName() string {
 	return ""
 }
 
+// IsObserveOnly returns true if the composed resource should only be observed.
+func (ct *ComposedTemplate) IsObserveOnly() bool {
+	return ct.ManagementPolicy != nil && *ct.ManagementPolicy == ComposedResourceManagementPolicyObserveOnly
+}
+
 // ReadinessCheckType is used for readiness check types.
 type ReadinessCheckType string
 
@@ -50,6 +50,15 @@ type EnvironmentConfiguration struct {
 	// all EnvironmentSourceReferences in EnvironmentConfigs list.
 	// +optional
 	Policy *xpv1.Policy `json:"policy,omitempty"`
+
+	// RecordResolved specifies whether the resolved environment should be
+	// recorded at status.resolvedEnvironment of the composite resource. The
+	// resolved environment is recorded after environment patches are applied.
+	// Values with keys that look like they may contain secrets are redacted.
+	// This is intended for debugging environment patches, and is disabled by
+	// default.
+	// +optional
+	RecordResolved *bool `json:"recordResolved,omitempty"`
 }
 
 // Validate the EnvironmentConfiguration.
@@ -85,6 +94,12 @@ func (e *EnvironmentConfiguration) ShouldResolve(currentRefs []corev1.ObjectRefe
 	return e.Policy.IsResolvePolicyAlways()
 }
 
+// ShouldRecordResolved specifies whether the resolved environment should be
+// recorded in the status of the composite resource.
+func (e *EnvironmentConfiguration) ShouldRecordResolved() bool {
+	return e != nil && e.RecordResolved != nil && *e.RecordResolved
+}
+
 // IsRequired specifies whether EnvironmentConfiguration is required or not.
 func (e *EnvironmentConfiguration) IsRequired() bool {
 	if e == nil {
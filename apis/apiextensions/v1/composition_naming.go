@@ -0,0 +1,99 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errNoNameTemplate      = "resource template has no name template"
+	errParseNameTemplate   = "cannot parse name template"
+	errExecuteNameTemplate = "cannot execute name template"
+	errFmtInvalidName      = "rendered name %q is invalid: %s"
+)
+
+// NameTemplateParameters may be referenced by the NameTemplate of a
+// ComposedTemplate.
+// +kubebuilder:object:generate=false
+type NameTemplateParameters struct {
+	// CompositeName is the name of the composite resource.
+	CompositeName string
+
+	// ClaimName is the name of the claim the composite resource is bound
+	// to, if any.
+	ClaimName string
+
+	// ClaimNamespace is the namespace of the claim the composite resource is
+	// bound to, if any.
+	ClaimNamespace string
+
+	// TemplateName is the name of the resource template.
+	TemplateName string
+}
+
+// RenderName renders the name of a composed resource using the template's
+// NameTemplate and the supplied parameters. It returns an error if the
+// template has no NameTemplate, or if the rendered name isn't a valid object
+// name.
+func (ct *ComposedTemplate) RenderName(p NameTemplateParameters) (string, error) {
+	if ct.NameTemplate == nil {
+		return "", errors.New(errNoNameTemplate)
+	}
+	t, err := template.New("name").Option("missingkey=error").Parse(*ct.NameTemplate)
+	if err != nil {
+		return "", errors.Wrap(err, errParseNameTemplate)
+	}
+	b := &strings.Builder{}
+	if err := t.Execute(b, p); err != nil {
+		return "", errors.Wrap(err, errExecuteNameTemplate)
+	}
+	name := b.String()
+	if msgs := validation.IsDNS1123Subdomain(name); len(msgs) > 0 {
+		return "", errors.Errorf(errFmtInvalidName, name, strings.Join(msgs, "; "))
+	}
+	return name, nil
+}
+
+// validateNameTemplate returns an error if the template's NameTemplate can't
+// be rendered, or renders an invalid name given short parameters. Names that
+// are only too long given the parameters of a particular composite resource
+// are caught when it's composed.
+func (ct *ComposedTemplate) validateNameTemplate() *field.Error {
+	if ct.NameTemplate == nil {
+		return nil
+	}
+	if ct.IsObserveOnly() {
+		return field.Invalid(field.NewPath("nameTemplate"), *ct.NameTemplate, "cannot name resources that are only observed")
+	}
+	_, err := ct.RenderName(NameTemplateParameters{
+		CompositeName:  "xr",
+		ClaimName:      "claim",
+		ClaimNamespace: "default",
+		TemplateName:   "resource",
+	})
+	if err != nil {
+		return field.Invalid(field.NewPath("nameTemplate"), *ct.NameTemplate, err.Error())
+	}
+	return nil
+}
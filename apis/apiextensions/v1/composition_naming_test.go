@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/utils/pointer"
+)
+
+func TestComposedTemplateRenderName(t *testing.T) {
+	params := NameTemplateParameters{
+		CompositeName:  "cool-xr-8sj2n",
+		ClaimName:      "cool-claim",
+		ClaimNamespace: "default",
+		TemplateName:   "db",
+	}
+
+	type want struct {
+		name string
+		err  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		ct     ComposedTemplate
+		want   want
+	}{
+		"NoNameTemplate": {
+			reason: "We should return an error if the template has no name template.",
+			ct:     ComposedTemplate{},
+			want: want{
+				err: true,
+			},
+		},
+		"ParseError": {
+			reason: "We should return an error if the name template can't be parsed.",
+			ct:     ComposedTemplate{NameTemplate: pointer.String("{{ .ClaimName ")},
+			want: want{
+				err: true,
+			},
+		},
+		"UnknownParameter": {
+			reason: "We should return an error if the name template references an unknown parameter.",
+			ct:     ComposedTemplate{NameTemplate: pointer.String("{{ .Colour }}")},
+			want: want{
+				err: true,
+			},
+		},
+		"InvalidName": {
+			reason: "We should return an error if the rendered name isn't a valid object name.",
+			ct:     ComposedTemplate{NameTemplate: pointer.String("{{ .ClaimName }}_{{ .TemplateName }}")},
+			want: want{
+				err: true,
+			},
+		},
+		"TooLong": {
+			reason: "We should return an error if the rendered name is too long.",
+			ct:     ComposedTemplate{NameTemplate: pointer.String(strings.Repeat("a", 250) + "-{{ .CompositeName }}")},
+			want: want{
+				err: true,
+			},
+		},
+		"Success": {
+			reason: "We should render a name using the supplied parameters.",
+			ct:     ComposedTemplate{NameTemplate: pointer.String("{{ .ClaimNamespace }}-{{ .ClaimName }}-{{ .TemplateName }}")},
+			want: want{
+				name: "default-cool-claim-db",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.ct.RenderName(params)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Errorf("\n%s\nRenderName(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.name, got); diff != "" {
+				t.Errorf("\n%s\nRenderName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
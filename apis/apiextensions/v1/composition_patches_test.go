@@ -149,6 +149,119 @@ func TestPatchValidate(t *testing.T) {
 				},
 			},
 		},
+		"ValidCombine": {
+			reason: "Combine with as many variables as its format string consumes should be valid",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "metadata.name"},
+							{
+								FromFieldPath: "spec.region",
+								Transforms: []Transform{{
+									Type:   TransformTypeString,
+									String: &StringTransform{Type: StringTransformTypeHash, Hash: &StringTransformHash{Length: pointer.Int(8)}},
+								}},
+							},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%s-%s (100%%)"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+		},
+		"ValidCombineReordered": {
+			reason: "Combine with a format string that explicitly indexes its variables may reuse them",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "metadata.name"},
+							{FromFieldPath: "spec.region"},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%[1]s-%[2]s-%[1]s"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+		},
+		"InvalidCombineTooFewVariables": {
+			reason: "Combine with fewer variables than its format string consumes should return error",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "metadata.name"},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%s-%*d"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.string.fmt",
+				},
+			},
+		},
+		"InvalidCombineTooManyVariables": {
+			reason: "Combine with more variables than its format string consumes should return error",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{FromFieldPath: "metadata.name"},
+							{FromFieldPath: "spec.region"},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%s"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.string.fmt",
+				},
+			},
+		},
+		"InvalidCombineVariableTransform": {
+			reason: "Combine with an invalid variable transform should return error",
+			args: args{
+				patch: &Patch{
+					Type: PatchTypeCombineFromComposite,
+					Combine: &Combine{
+						Variables: []CombineVariable{
+							{
+								FromFieldPath: "metadata.name",
+								Transforms: []Transform{{
+									Type:   TransformTypeString,
+									String: &StringTransform{Type: StringTransformTypeHash, Hash: &StringTransformHash{Length: pointer.Int(65)}},
+								}},
+							},
+						},
+						Strategy: CombineStrategyString,
+						String:   &StringCombine{Format: "%s"},
+					},
+					ToFieldPath: pointer.String("metadata.name"),
+				},
+			},
+			want: want{
+				err: &field.Error{
+					Type:  field.ErrorTypeInvalid,
+					Field: "combine.variables[0].transforms[0].string.hash.length",
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
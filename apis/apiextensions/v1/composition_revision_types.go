@@ -82,6 +82,26 @@ type CompositionRevisionSpec struct {
 	// +kubebuilder:default={"name": "default"}
 	PublishConnectionDetailsWithStoreConfigRef *StoreConfigReference `json:"publishConnectionDetailsWithStoreConfigRef,omitempty"`
 
+	// RemovedResourcePolicy specifies what happens to a composed resource that
+	// this Composition no longer produces, for example because its resource
+	// template was removed or renamed. Delete, the default, deletes the
+	// composed resource. Orphan stops tracking the composed resource and
+	// removes the composite resource's ownership of it, but doesn't delete it.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	RemovedResourcePolicy *RemovedResourcePolicy `json:"removedResourcePolicy,omitempty"`
+
+	// WriteConnectionSecretPolicy specifies when the connection details of
+	// composite resources using this composition are published. Incremental,
+	// the default, publishes connection details as they become available.
+	// WhenReady publishes them only once every connection detail declared by
+	// the resource templates is available and the composite resource is
+	// ready. Until then any previously published connection details are left
+	// untouched.
+	// +optional
+	// +kubebuilder:validation:Enum=Incremental;WhenReady
+	WriteConnectionSecretPolicy *WriteConnectionSecretPolicy `json:"writeConnectionSecretPolicy,omitempty"`
+
 	// Revision number. Newer revisions have larger numbers.
 	// +immutable
 	Revision int64 `json:"revision"`
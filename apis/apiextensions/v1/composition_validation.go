@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -30,13 +32,28 @@ func (c *Composition) Validate() (warns []string, errs field.ErrorList) {
 	validations := []validationFunc{
 		c.validatePatchSets,
 		c.validateResources,
+		c.validateResourceDependencies,
+		c.validateObserveOnlyResources,
 		c.validateFunctions,
 		c.validateEnvironment,
 	}
 	for _, f := range validations {
 		errs = append(errs, f()...)
 	}
-	return nil, errs
+	return c.warnAnonymousResources(), errs
+}
+
+// warnAnonymousResources returns a warning for each anonymous resource
+// template. Anonymous templates are still supported, but they interact badly
+// with garbage collection of composed resources and with Composition Functions.
+func (c *Composition) warnAnonymousResources() (warns []string) {
+	for i, res := range c.Spec.Resources {
+		if res.GetName() != "" {
+			continue
+		}
+		warns = append(warns, fmt.Sprintf("%s: resource template is anonymous; all resource templates should have a unique name", field.NewPath("spec", "resources").Index(i).Child("name")))
+	}
+	return warns
 }
 
 func (c *Composition) validateFunctions() (errs field.ErrorList) {
@@ -83,6 +100,9 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 				errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i).Child("readinessChecks").Index(j)))
 			}
 		}
+		if err := res.validateNameTemplate(); err != nil {
+			errs = append(errs, verrors.WrapFieldError(err, field.NewPath("spec", "resources").Index(i)))
+		}
 		// TODO(phisco): we should validate also ConnectionDetails, but would need a major refactoring
 	}
 	return errs
@@ -99,8 +119,11 @@ func (c *Composition) validateResources() (errs field.ErrorList) {
 //  3. If the composition has any functions, it must have only named resources: This is necessary for the
 //     FunctionComposer to be able to associate entries in the spec.resources array with entries in a FunctionIO's observed
 //     and desired arrays
+//  4. If the composition patches to the environment, it must have only named resources: the environment is shared by
+//     all resources, so it's important to be able to tell which template a patch came from.
 func (c *Composition) validateResourceNames() (errs field.ErrorList) {
-	seen := map[string]bool{}
+	toEnvironment := c.hasToEnvironmentPatches()
+	seen := map[string]int{}
 	for resourceIndex, res := range c.Spec.Resources {
 		// Check that all resources have a name and that it is unique.
 		// If the composition has any functions, it must have only named resources.
@@ -111,6 +134,11 @@ func (c *Composition) validateResourceNames() (errs field.ErrorList) {
 				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(resourceIndex).Child("name"), "cannot have anonymous resources when composition has functions"))
 				continue
 			}
+			// If the composition patches to the environment, it must have only named resources.
+			if toEnvironment {
+				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(resourceIndex).Child("name"), "cannot have anonymous resources when composition patches to the environment"))
+				continue
+			}
 			// If it's not the first resource, and all previous one were named, then this is an error.
 			if resourceIndex != 0 && len(seen) != 0 {
 				errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(resourceIndex).Child("name"), "cannot mix named and anonymous resources, all resources must have a name or none must have a name"))
@@ -119,8 +147,10 @@ func (c *Composition) validateResourceNames() (errs field.ErrorList) {
 			continue
 		}
 		// Check that the name is unique
-		if seen[name] {
-			errs = append(errs, field.Duplicate(field.NewPath("spec", "resources").Index(resourceIndex).Child("name"), name))
+		if i, ok := seen[name]; ok {
+			err := field.Duplicate(field.NewPath("spec", "resources").Index(resourceIndex).Child("name"), name)
+			err.Detail = fmt.Sprintf("name is already used by %s", field.NewPath("spec", "resources").Index(i))
+			errs = append(errs, err)
 			continue
 		}
 		// If it's not the first resource, and all previous one were anonymous, then this is an error.
@@ -128,11 +158,143 @@ func (c *Composition) validateResourceNames() (errs field.ErrorList) {
 			errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(resourceIndex).Child("name"), name, "cannot mix named and anonymous resources, all resources must have a name or none must have a name"))
 			continue
 		}
-		seen[name] = true
+		seen[name] = resourceIndex
 	}
 	return errs
 }
 
+// hasToEnvironmentPatches returns true if any of the Composition's resource
+// templates or patch sets patch to the environment.
+func (c *Composition) hasToEnvironmentPatches() bool {
+	isToEnvironment := func(p Patch) bool {
+		return p.Type == PatchTypeToEnvironmentFieldPath || p.Type == PatchTypeCombineToEnvironment
+	}
+	for _, ps := range c.Spec.PatchSets {
+		for _, p := range ps.Patches {
+			if isToEnvironment(p) {
+				return true
+			}
+		}
+	}
+	for _, res := range c.Spec.Resources {
+		for _, p := range res.Patches {
+			if isToEnvironment(p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateResourceDependencies checks that resources only depend on other
+// named resources in the same Composition, and that their dependencies don't
+// form a cycle. A cycle would make it impossible to order deletion.
+func (c *Composition) validateResourceDependencies() (errs field.ErrorList) {
+	deps := make(map[string][]string, len(c.Spec.Resources))
+	for i, res := range c.Spec.Resources {
+		if len(res.DependsOn) > 0 && res.GetName() == "" {
+			errs = append(errs, field.Required(field.NewPath("spec", "resources").Index(i).Child("name"), "resources that specify dependsOn must have a name"))
+			continue
+		}
+		deps[res.GetName()] = res.DependsOn
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	for i, res := range c.Spec.Resources {
+		for j, d := range res.DependsOn {
+			p := field.NewPath("spec", "resources").Index(i).Child("dependsOn").Index(j)
+			if d == res.GetName() {
+				errs = append(errs, field.Invalid(p, d, "a resource cannot depend on itself"))
+				continue
+			}
+			if _, ok := deps[d]; !ok || d == "" {
+				errs = append(errs, field.NotFound(p, d))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// Walk the dependencies of each resource depth first, in the order they
+	// appear in the resources array so that errors are deterministic.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(deps))
+	var visit func(name string) bool
+	visit = func(name string) bool {
+		switch state[name] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		state[name] = visiting
+		for _, d := range deps[name] {
+			if !visit(d) {
+				return false
+			}
+		}
+		state[name] = visited
+		return true
+	}
+	for i, res := range c.Spec.Resources {
+		if state[res.GetName()] != unvisited {
+			continue
+		}
+		if !visit(res.GetName()) {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("dependsOn"), res.DependsOn, "resource dependencies must not contain a cycle"))
+			return errs
+		}
+	}
+	return errs
+}
+
+// validateObserveOnlyResources checks that no patch writes to an observe-only
+// resource, and that observe-only resources aren't used alongside functions.
+func (c *Composition) validateObserveOnlyResources() (errs field.ErrorList) {
+	patchSets := make(map[string][]Patch, len(c.Spec.PatchSets))
+	for _, ps := range c.Spec.PatchSets {
+		patchSets[ps.Name] = ps.Patches
+	}
+	for i, res := range c.Spec.Resources {
+		if !res.IsObserveOnly() {
+			continue
+		}
+		if len(c.Spec.Functions) != 0 {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("managementPolicy"), *res.ManagementPolicy, "cannot observe resources when composition has functions"))
+		}
+		for j, p := range res.Patches {
+			ps := []Patch{p}
+			if p.Type == PatchTypePatchSet && p.PatchSetName != nil {
+				ps = patchSets[*p.PatchSetName]
+			}
+			for _, pp := range ps {
+				if writesToComposed(pp.Type) {
+					errs = append(errs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("patches").Index(j).Child("type"), p.Type, "cannot patch an observe-only resource"))
+					break
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// writesToComposed returns true if a patch of the supplied type writes to a
+// composed resource. Patches default to FromCompositeFieldPath.
+func writesToComposed(t PatchType) bool {
+	switch t {
+	case "", PatchTypeFromCompositeFieldPath, PatchTypeCombineFromComposite, PatchTypeFromEnvironmentFieldPath, PatchTypeCombineFromEnvironment:
+		return true
+	}
+	return false
+}
+
 // validateEnvironment checks that the environment is logically valid.
 func (c *Composition) validateEnvironment() field.ErrorList {
 	if c.Spec.Environment == nil {
@@ -151,6 +151,58 @@ func TestCompositionValidateResourceName(t *testing.T) {
 				},
 			},
 		},
+		"InvalidAnonymousWithToEnvironmentPatches": {
+			reason: "anonymous resources are invalid if the composition patches to the environment",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{},
+						{
+							Patches: []Patch{{
+								Type:          PatchTypeToEnvironmentFieldPath,
+								FromFieldPath: pointer.String("status.cool"),
+							}},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].name",
+					},
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[1].name",
+					},
+				},
+			},
+		},
+		"InvalidAnonymousWithToEnvironmentPatchSets": {
+			reason: "anonymous resources are invalid if a patch set of the composition patches to the environment",
+			args: args{
+				spec: CompositionSpec{
+					PatchSets: []PatchSet{{
+						Name: "cool",
+						Patches: []Patch{{
+							Type: PatchTypeCombineToEnvironment,
+						}},
+					}},
+					Resources: []ComposedTemplate{
+						{},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[0].name",
+					},
+				},
+			},
+		},
 		"InvalidDuplicateNames": {
 			reason: "duplicate resource names are invalid",
 			args: args{
@@ -186,6 +238,283 @@ func TestCompositionValidateResourceName(t *testing.T) {
 	}
 }
 
+func TestCompositionWarnAnonymousResources(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		spec   CompositionSpec
+		want   []string
+	}{
+		"AllNamed": {
+			reason: "We should not warn about named resources.",
+			spec: CompositionSpec{
+				Resources: []ComposedTemplate{
+					{Name: pointer.String("foo")},
+				},
+			},
+		},
+		"Anonymous": {
+			reason: "We should warn about each anonymous resource, including its index.",
+			spec: CompositionSpec{
+				Resources: []ComposedTemplate{
+					{},
+					{},
+				},
+			},
+			want: []string{
+				"spec.resources[0].name: resource template is anonymous; all resource templates should have a unique name",
+				"spec.resources[1].name: resource template is anonymous; all resource templates should have a unique name",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Composition{Spec: tc.spec}
+			got := c.warnAnonymousResources()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("%s\nwarnAnonymousResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidateResourceDependencies(t *testing.T) {
+	type args struct {
+		spec CompositionSpec
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidNoDependencies": {
+			reason: "Resources without dependencies are valid",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{},
+						{},
+					},
+				},
+			},
+		},
+		"ValidDependencies": {
+			reason: "Resources that depend on other named resources are valid",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("zone")},
+						{Name: pointer.String("record"), DependsOn: []string{"zone"}},
+						{Name: pointer.String("other-record"), DependsOn: []string{"zone", "record"}},
+					},
+				},
+			},
+		},
+		"InvalidAnonymousDependent": {
+			reason: "Anonymous resources cannot specify dependencies",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{},
+						{DependsOn: []string{"zone"}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeRequired,
+						Field: "spec.resources[1].name",
+					},
+				},
+			},
+		},
+		"InvalidUnknownDependency": {
+			reason: "Resources cannot depend on a resource that doesn't exist",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("record"), DependsOn: []string{"zone"}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeNotFound,
+						Field: "spec.resources[0].dependsOn[0]",
+					},
+				},
+			},
+		},
+		"InvalidSelfDependency": {
+			reason: "Resources cannot depend on themselves",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("record"), DependsOn: []string{"record"}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].dependsOn[0]",
+					},
+				},
+			},
+		},
+		"InvalidCycle": {
+			reason: "Resource dependencies cannot contain a cycle",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{
+						{Name: pointer.String("a"), DependsOn: []string{"b"}},
+						{Name: pointer.String("b"), DependsOn: []string{"c"}},
+						{Name: pointer.String("c"), DependsOn: []string{"a"}},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].dependsOn",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Composition{
+				Spec: tc.args.spec,
+			}
+			gotErrs := c.validateResourceDependencies()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateResourceDependencies(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCompositionValidateObserveOnlyResources(t *testing.T) {
+	observeOnly := ComposedResourceManagementPolicyObserveOnly
+	type args struct {
+		spec CompositionSpec
+	}
+	type want struct {
+		output field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ValidToCompositePatches": {
+			reason: "Observe-only resources may patch the composite resource",
+			args: args{
+				spec: CompositionSpec{
+					PatchSets: []PatchSet{{
+						Name:    "to-xr",
+						Patches: []Patch{{Type: PatchTypeToCompositeFieldPath}},
+					}},
+					Resources: []ComposedTemplate{{
+						Name:             pointer.String("vpc"),
+						ManagementPolicy: &observeOnly,
+						Patches: []Patch{
+							{Type: PatchTypeToCompositeFieldPath},
+							{Type: PatchTypeCombineToComposite},
+							{Type: PatchTypePatchSet, PatchSetName: pointer.String("to-xr")},
+						},
+					}},
+				},
+			},
+		},
+		"ValidDefaultPolicy": {
+			reason: "Resources that aren't observe-only may be patched",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{{
+						Name:    pointer.String("vpc"),
+						Patches: []Patch{{Type: PatchTypeFromCompositeFieldPath}},
+					}},
+				},
+			},
+		},
+		"InvalidFromCompositePatches": {
+			reason: "Patches cannot write to observe-only resources",
+			args: args{
+				spec: CompositionSpec{
+					PatchSets: []PatchSet{{
+						Name:    "from-xr",
+						Patches: []Patch{{Type: PatchTypeFromCompositeFieldPath}},
+					}},
+					Resources: []ComposedTemplate{{
+						Name:             pointer.String("vpc"),
+						ManagementPolicy: &observeOnly,
+						Patches: []Patch{
+							{},
+							{Type: PatchTypeFromEnvironmentFieldPath},
+							{Type: PatchTypePatchSet, PatchSetName: pointer.String("from-xr")},
+						},
+					}},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[0].type",
+					},
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[1].type",
+					},
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].patches[2].type",
+					},
+				},
+			},
+		},
+		"InvalidWithFunctions": {
+			reason: "Observe-only resources cannot be used with functions",
+			args: args{
+				spec: CompositionSpec{
+					Resources: []ComposedTemplate{{
+						Name:             pointer.String("vpc"),
+						ManagementPolicy: &observeOnly,
+					}},
+					Functions: []Function{{Name: "fn"}},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].managementPolicy",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &Composition{
+				Spec: tc.args.spec,
+			}
+			gotErrs := c.validateObserveOnlyResources()
+			if diff := cmp.Diff(tc.want.output, gotErrs, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateObserveOnlyResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestCompositionValidatePatchSets(t *testing.T) {
 	type args struct {
 		comp *Composition
@@ -430,6 +759,8 @@ func TestCompositionValidateFunctions(t *testing.T) {
 }
 
 func TestCompositionValidateResources(t *testing.T) {
+	observeOnly := ComposedResourceManagementPolicyObserveOnly
+
 	type args struct {
 		comp *Composition
 	}
@@ -591,6 +922,68 @@ func TestCompositionValidateResources(t *testing.T) {
 				},
 			},
 		},
+		"ValidNameTemplate": {
+			reason: "a resource with a valid name template should be valid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:         pointer.String("foo"),
+								NameTemplate: pointer.String("{{ .ClaimNamespace }}-{{ .ClaimName }}-foo"),
+							},
+						},
+					},
+				},
+			},
+		},
+		"InvalidNameTemplate": {
+			reason: "a resource with a name template that renders an invalid name should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:         pointer.String("foo"),
+								NameTemplate: pointer.String("{{ .ClaimName }}_foo"),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].nameTemplate",
+					},
+				},
+			},
+		},
+		"InvalidObserveOnlyNameTemplate": {
+			reason: "an observe-only resource with a name template should be invalid",
+			args: args{
+				comp: &Composition{
+					Spec: CompositionSpec{
+						Resources: []ComposedTemplate{
+							{
+								Name:             pointer.String("foo"),
+								NameTemplate:     pointer.String("{{ .CompositeName }}-foo"),
+								ManagementPolicy: &observeOnly,
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				output: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.resources[0].nameTemplate",
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -40,6 +40,7 @@ const (
 	ReasonWatchingClaim     xpv1.ConditionReason = "WatchingCompositeResourceClaim"
 
 	ReasonTerminatingComposite xpv1.ConditionReason = "TerminatingCompositeResource"
+	ReasonDeletionBlocked      xpv1.ConditionReason = "DeletionBlocked"
 	ReasonTerminatingClaim     xpv1.ConditionReason = "TerminatingCompositeResourceClaim"
 )
 
@@ -65,6 +66,18 @@ func TerminatingComposite() xpv1.Condition {
 	}
 }
 
+// DeletionBlockedComposite indicates that Crossplane won't remove the definition
+// of a composite resource, because instances of it still exist and its
+// deletion policy is Block. The composite resource is still established.
+func DeletionBlockedComposite() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeEstablished,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDeletionBlocked,
+	}
+}
+
 // WatchingClaim indicates that Crossplane has defined and is watching for a
 // new kind of composite resource claim.
 func WatchingClaim() xpv1.Condition {
@@ -19,6 +19,7 @@ package v1
 import (
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
@@ -73,6 +74,14 @@ type CompositeResourceDefinitionSpec struct {
 	// +immutable
 	EnforcedCompositionRef *CompositionReference `json:"enforcedCompositionRef,omitempty"`
 
+	// EnforcedCompositionUpdatePolicy is the policy used by all composite
+	// instances whose schema is defined by this definition when updating
+	// composites after a new Composition Revision has been created. It
+	// overrides any policy specified on the composite or its claim.
+	// +optional
+	// +immutable
+	EnforcedCompositionUpdatePolicy *xpv1.UpdatePolicy `json:"enforcedCompositionUpdatePolicy,omitempty"`
+
 	// DefaultCompositionUpdatePolicy is the policy used when updating composites after a new
 	// Composition Revision has been created if no policy has been specified on the composite.
 	// +optional
@@ -96,9 +105,117 @@ type CompositeResourceDefinitionSpec struct {
 	// +optional
 	Conversion *extv1.CustomResourceConversion `json:"conversion,omitempty"`
 
+	// FieldConversions declaratively convert composite resources and claims
+	// between the versions of their schema, by moving fields. Crossplane
+	// serves a conversion webhook that applies them, so they can't be used
+	// with a Webhook conversion strategy. Fields that aren't moved by a
+	// conversion are preserved as is.
+	// +optional
+	FieldConversions []FieldConversion `json:"fieldConversions,omitempty"`
+
+	// ClaimValidation configures how Crossplane's claim validation webhook
+	// validates the claims defined by this XRD. It has no effect unless the
+	// webhook is enabled.
+	// +optional
+	ClaimValidation *ClaimValidation `json:"claimValidation,omitempty"`
+
+	// ClaimNamespaces restricts the namespaces in which claims of the kind
+	// this XRD defines may be created. Claims may be created in any namespace
+	// if it's omitted. New claims are rejected if Crossplane's claim
+	// validation webhook is enabled. Otherwise no composite resource is
+	// created for a claim in a namespace that isn't allowed. Existing claims
+	// in namespaces that are no longer allowed aren't deleted, but are marked
+	// with a condition.
+	// +optional
+	ClaimNamespaces *ClaimNamespaces `json:"claimNamespaces,omitempty"`
+
 	// Metadata specifies the desired metadata for the defined composite resource and claim CRD's.
 	// +optional
 	Metadata *CompositeResourceDefinitionSpecMetadata `json:"metadata,omitempty"`
+
+	// DeletionPolicy specifies what happens when this definition is deleted
+	// while composite resources of the kind it defines exist. Block refuses
+	// to delete the definition until they're deleted. DeleteInstances
+	// deletes them, and waits for them to be gone before removing the
+	// definition.
+	// +optional
+	// +kubebuilder:validation:Enum=Block;DeleteInstances
+	// +kubebuilder:default=DeleteInstances
+	DeletionPolicy *XRDDeletionPolicy `json:"deletionPolicy,omitempty"`
+}
+
+// An XRDDeletionPolicy specifies what happens when an XRD is deleted while
+// composite resources of the kind it defines exist.
+type XRDDeletionPolicy string
+
+// XRD deletion policies.
+const (
+	// XRDDeletionBlock refuses to delete an XRD while composite resources of
+	// the kind it defines exist.
+	XRDDeletionBlock XRDDeletionPolicy = "Block"
+
+	// XRDDeletionDeleteInstances deletes the composite resources of the kind
+	// an XRD defines before deleting the XRD.
+	XRDDeletionDeleteInstances XRDDeletionPolicy = "DeleteInstances"
+)
+
+// A FieldConversion moves a field when a composite resource or claim is
+// converted between two versions of its schema. The field is moved in the
+// opposite direction when converting from the ToVersion to the FromVersion.
+type FieldConversion struct {
+	// FromVersion is the version the field is moved from.
+	FromVersion string `json:"fromVersion"`
+
+	// ToVersion is the version the field is moved to.
+	ToVersion string `json:"toVersion"`
+
+	// FromFieldPath is the path of the field in the FromVersion schema.
+	FromFieldPath string `json:"fromFieldPath"`
+
+	// ToFieldPath is the path of the field in the ToVersion schema.
+	ToFieldPath string `json:"toFieldPath"`
+}
+
+// ClaimValidation configures how claims are validated.
+type ClaimValidation struct {
+	// Strict rejects claims that specify fields under their spec that aren't
+	// declared by their schema. Such fields are otherwise silently pruned.
+	// +optional
+	Strict bool `json:"strict,omitempty"`
+}
+
+// ClaimNamespaces specifies the namespaces in which claims may be created,
+// either by name or by label selector.
+type ClaimNamespaces struct {
+	// Names of the namespaces in which claims may be created.
+	// +optional
+	Names []string `json:"names,omitempty"`
+
+	// Selector selects the namespaces in which claims may be created by
+	// their labels.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// Allows returns true if claims may be created in the namespace with the
+// supplied name and labels. A nil ClaimNamespaces allows all namespaces.
+func (c *ClaimNamespaces) Allows(name string, l map[string]string) (bool, error) {
+	if c == nil {
+		return true, nil
+	}
+	for _, n := range c.Names {
+		if n == name {
+			return true, nil
+		}
+	}
+	if c.Selector == nil {
+		return false, nil
+	}
+	s, err := metav1.LabelSelectorAsSelector(c.Selector)
+	if err != nil {
+		return false, err
+	}
+	return s.Matches(labels.Set(l)), nil
 }
 
 // A CompositionReference references a Composition.
@@ -124,6 +241,15 @@ type CompositeResourceDefinitionSpecMetadata struct {
 	// More info: https://kubernetes.io/docs/concepts/overview/working-with-objects/annotations
 	// +optional
 	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// PropagateLabels is a list of key prefixes. Labels and annotations of a
+	// claim with a key that starts with one of these prefixes are propagated
+	// to its composite resource, and from the composite resource to its
+	// composed resources. Removing a label or annotation from the claim
+	// removes it from the composite and composed resources. Keys managed by
+	// Crossplane, i.e. in the crossplane.io domain, are never propagated.
+	// +optional
+	PropagateLabels []string `json:"propagateLabels,omitempty"`
 }
 
 // CompositeResourceDefinitionVersion describes a version of an XR.
@@ -279,3 +405,22 @@ func (in CompositeResourceDefinition) GetClaimGroupVersionKind() schema.GroupVer
 func (in *CompositeResourceDefinition) GetConnectionSecretKeys() []string {
 	return in.Spec.ConnectionSecretKeys
 }
+
+// GetDeletionPolicy returns the deletion policy of this XRD. It defaults to
+// DeleteInstances.
+func (in *CompositeResourceDefinition) GetDeletionPolicy() XRDDeletionPolicy {
+	if in.Spec.DeletionPolicy == nil {
+		return XRDDeletionDeleteInstances
+	}
+	return *in.Spec.DeletionPolicy
+}
+
+// GetPropagatedMetadataPrefixes returns the key prefixes of the claim labels
+// and annotations that should be propagated to composite and composed
+// resources.
+func (in *CompositeResourceDefinition) GetPropagatedMetadataPrefixes() []string {
+	if in.Spec.Metadata == nil {
+		return nil
+	}
+	return in.Spec.Metadata.PropagateLabels
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestClaimNamespacesAllows(t *testing.T) {
+	invalid := &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "tenant", Operator: "Nope"}}}
+	_, errInvalid := metav1.LabelSelectorAsSelector(invalid)
+
+	type args struct {
+		name   string
+		labels map[string]string
+	}
+	type want struct {
+		allowed bool
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      *ClaimNamespaces
+		args   args
+		want   want
+	}{
+		"Nil": {
+			reason: "Claims may be created in any namespace if claim namespaces aren't specified.",
+			args:   args{name: "default"},
+			want:   want{allowed: true},
+		},
+		"NameMatches": {
+			reason: "Claims may be created in a namespace that is explicitly listed.",
+			c:      &ClaimNamespaces{Names: []string{"tenant-a", "tenant-b"}},
+			args:   args{name: "tenant-b"},
+			want:   want{allowed: true},
+		},
+		"NameDoesNotMatch": {
+			reason: "Claims may not be created in a namespace that isn't listed.",
+			c:      &ClaimNamespaces{Names: []string{"tenant-a"}},
+			args:   args{name: "default"},
+			want:   want{allowed: false},
+		},
+		"SelectorMatches": {
+			reason: "Claims may be created in a namespace whose labels match the selector.",
+			c:      &ClaimNamespaces{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}}},
+			args:   args{name: "tenant-a", labels: map[string]string{"tenant": "true"}},
+			want:   want{allowed: true},
+		},
+		"SelectorDoesNotMatch": {
+			reason: "Claims may not be created in a namespace whose labels don't match the selector.",
+			c:      &ClaimNamespaces{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}}},
+			args:   args{name: "default"},
+			want:   want{allowed: false},
+		},
+		"InvalidSelector": {
+			reason: "We should return an error if the selector is invalid.",
+			c:      &ClaimNamespaces{Selector: invalid},
+			args:   args{name: "default"},
+			want:   want{allowed: false, err: errInvalid},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.c.Allows(tc.args.name, tc.args.labels)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nAllows(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.allowed, got); diff != "" {
+				t.Errorf("\n%s\nAllows(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
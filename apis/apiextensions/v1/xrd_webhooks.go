@@ -17,12 +17,17 @@ limitations under the License.
 package v1
 
 import (
+	"reflect"
+	"strings"
+
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	ctrl "sigs.k8s.io/controller-runtime"
+	"k8s.io/client-go/util/jsonpath"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 )
 
 const (
@@ -33,23 +38,40 @@ const (
 	errKindImmutable                   = "spec.names.kind is immutable"
 	errClaimPluralImmutable            = "spec.claimNames.plural is immutable"
 	errClaimKindImmutable              = "spec.claimNames.kind is immutable"
+	errEnforcedCompositionRefImmutable = "spec.enforcedCompositionRef is immutable"
+	errEnforcedUpdatePolicyImmutable   = "spec.enforcedCompositionUpdatePolicy is immutable"
 	errConversionWebhookConfigRequired = "spec.conversion.webhook is required when spec.conversion.strategy is 'Webhook'"
+	errFieldConversionsWithWebhook     = "spec.fieldConversions cannot be used when spec.conversion.strategy is 'Webhook'"
+	errClaimNamespacesNamesAndSelector = "spec.claimNamespaces.names and spec.claimNamespaces.selector are mutually exclusive"
+	errClaimNamespacesInvalidSelector  = "spec.claimNamespaces.selector is invalid"
+
+	errFmtInvalidPrinterColumnJSONPath = "spec.versions[%d].additionalPrinterColumns[%d].jsonPath is invalid: %s"
+	errPrinterColumnJSONPathPrefix     = "must start with '.'"
+	errFmtInvalidFieldConversion       = "spec.fieldConversions[%d] is invalid"
+	errFmtUnknownVersion               = "version %q is not defined"
+	errSameVersion                     = "fromVersion and toVersion must differ"
+	errFmtInvalidFieldPath             = "invalid field path %q"
+	errFmtFieldPathPrefix              = "field path %q must be within spec or status"
 )
 
-// NOTE(negz): We only validate updates because we're only using the validation
-// webhook to enforce a few immutable fields. We should look into using CEL per
+// NOTE(negz): We mostly use the validation webhook to enforce a few immutable
+// fields. We should look into using CEL per
 // https://github.com/crossplane/crossplane/issues/4128 instead.
 
-// +kubebuilder:webhook:verbs=update,path=/validate-apiextensions-crossplane-io-v1-compositeresourcedefinition,mutating=false,failurePolicy=fail,groups=apiextensions.crossplane.io,resources=compositeresourcedefinitions,versions=v1,name=compositeresourcedefinitions.apiextensions.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=update;create;delete,path=/validate-apiextensions-crossplane-io-v1-compositeresourcedefinition,mutating=false,failurePolicy=fail,groups=apiextensions.crossplane.io,resources=compositeresourcedefinitions,versions=v1,name=compositeresourcedefinitions.apiextensions.crossplane.io,sideEffects=None,admissionReviewVersions=v1
 
 // ValidateCreate is run for creation actions.
 func (in *CompositeResourceDefinition) ValidateCreate() (admission.Warnings, error) {
-	// TODO(negz): Does this code ever get exercised in reality? We don't
-	// register the update verb when we generate a configuration above.
 	if c := in.Spec.Conversion; c != nil && c.Strategy == extv1.WebhookConverter && c.Webhook == nil {
 		return nil, errors.New(errConversionWebhookConfigRequired)
 	}
-	return nil, nil
+	if err := in.validatePrinterColumns(); err != nil {
+		return nil, err
+	}
+	if err := in.validateClaimNamespaces(); err != nil {
+		return nil, err
+	}
+	return nil, in.validateFieldConversions()
 }
 
 // ValidateUpdate is run for update actions.
@@ -65,6 +87,10 @@ func (in *CompositeResourceDefinition) ValidateUpdate(old runtime.Object) (admis
 		return nil, errors.New(errPluralImmutable)
 	case in.Spec.Names.Kind != oldObj.Spec.Names.Kind:
 		return nil, errors.New(errKindImmutable)
+	case !reflect.DeepEqual(in.Spec.EnforcedCompositionRef, oldObj.Spec.EnforcedCompositionRef):
+		return nil, errors.New(errEnforcedCompositionRefImmutable)
+	case !reflect.DeepEqual(in.Spec.EnforcedCompositionUpdatePolicy, oldObj.Spec.EnforcedCompositionUpdatePolicy):
+		return nil, errors.New(errEnforcedUpdatePolicyImmutable)
 	}
 	if in.Spec.ClaimNames != nil && oldObj.Spec.ClaimNames != nil {
 		switch {
@@ -74,17 +100,93 @@ func (in *CompositeResourceDefinition) ValidateUpdate(old runtime.Object) (admis
 			return nil, errors.New(errClaimKindImmutable)
 		}
 	}
-	return nil, nil
+	if err := in.validatePrinterColumns(); err != nil {
+		return nil, err
+	}
+	if err := in.validateClaimNamespaces(); err != nil {
+		return nil, err
+	}
+	return nil, in.validateFieldConversions()
+}
+
+// validatePrinterColumns ensures the JSONPath of each additional printer column
+// is syntactically valid. The columns are used by the generated XR and claim
+// CRDs, which the API server would otherwise refuse to update.
+func (in *CompositeResourceDefinition) validatePrinterColumns() error {
+	for i, v := range in.Spec.Versions {
+		for j, c := range v.AdditionalPrinterColumns {
+			if !strings.HasPrefix(c.JSONPath, ".") {
+				return errors.Errorf(errFmtInvalidPrinterColumnJSONPath, i, j, errPrinterColumnJSONPathPrefix)
+			}
+			if err := jsonpath.New(c.Name).Parse("{" + c.JSONPath + "}"); err != nil {
+				return errors.Errorf(errFmtInvalidPrinterColumnJSONPath, i, j, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateClaimNamespaces ensures claim namespaces are specified either by
+// name or by label selector, but not both.
+func (in *CompositeResourceDefinition) validateClaimNamespaces() error {
+	c := in.Spec.ClaimNamespaces
+	if c == nil {
+		return nil
+	}
+	if len(c.Names) > 0 && c.Selector != nil {
+		return errors.New(errClaimNamespacesNamesAndSelector)
+	}
+	if c.Selector == nil {
+		return nil
+	}
+	_, err := metav1.LabelSelectorAsSelector(c.Selector)
+	return errors.Wrap(err, errClaimNamespacesInvalidSelector)
+}
+
+// validateFieldConversions ensures each field conversion moves a field within
+// the spec or status of a composite resource or claim between two different
+// versions of its schema.
+func (in *CompositeResourceDefinition) validateFieldConversions() error {
+	if len(in.Spec.FieldConversions) == 0 {
+		return nil
+	}
+	if c := in.Spec.Conversion; c != nil && c.Strategy == extv1.WebhookConverter {
+		return errors.New(errFieldConversionsWithWebhook)
+	}
+	versions := make(map[string]bool, len(in.Spec.Versions))
+	for _, v := range in.Spec.Versions {
+		versions[v.Name] = true
+	}
+	for i, fc := range in.Spec.FieldConversions {
+		if err := validateFieldConversion(fc, versions); err != nil {
+			return errors.Wrapf(err, errFmtInvalidFieldConversion, i)
+		}
+	}
+	return nil
+}
+
+func validateFieldConversion(fc FieldConversion, versions map[string]bool) error {
+	for _, v := range []string{fc.FromVersion, fc.ToVersion} {
+		if !versions[v] {
+			return errors.Errorf(errFmtUnknownVersion, v)
+		}
+	}
+	if fc.FromVersion == fc.ToVersion {
+		return errors.New(errSameVersion)
+	}
+	for _, p := range []string{fc.FromFieldPath, fc.ToFieldPath} {
+		s, err := fieldpath.Parse(p)
+		if err != nil {
+			return errors.Wrapf(err, errFmtInvalidFieldPath, p)
+		}
+		if len(s) < 2 || (s[0].Field != "spec" && s[0].Field != "status") {
+			return errors.Errorf(errFmtFieldPathPrefix, p)
+		}
+	}
+	return nil
 }
 
 // ValidateDelete is run for delete actions.
 func (in *CompositeResourceDefinition) ValidateDelete() (admission.Warnings, error) {
 	return nil, nil
 }
-
-// SetupWebhookWithManager sets up webhook with manager.
-func SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewWebhookManagedBy(mgr).
-		For(&CompositeResourceDefinition{}).
-		Complete()
-}
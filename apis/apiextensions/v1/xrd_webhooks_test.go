@@ -21,9 +21,11 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 )
@@ -138,6 +140,35 @@ func TestValidateUpdate(t *testing.T) {
 			},
 			err: errors.New(errClaimKindImmutable),
 		},
+		"EnforcedCompositionRefChanged": {
+			args: args{
+				old: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						EnforcedCompositionRef: &CompositionReference{Name: "b"},
+					},
+				},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						EnforcedCompositionRef: &CompositionReference{Name: "a"},
+					},
+				},
+			},
+			err: errors.New(errEnforcedCompositionRefImmutable),
+		},
+		"EnforcedCompositionUpdatePolicyAdded": {
+			args: args{
+				old: &CompositeResourceDefinition{},
+				new: &CompositeResourceDefinition{
+					Spec: CompositeResourceDefinitionSpec{
+						EnforcedCompositionUpdatePolicy: func() *xpv1.UpdatePolicy {
+							p := xpv1.UpdateManual
+							return &p
+						}(),
+					},
+				},
+			},
+			err: errors.New(errEnforcedUpdatePolicyImmutable),
+		},
 		"Success": {
 			args: args{
 				old: &CompositeResourceDefinition{
@@ -170,3 +201,144 @@ func TestValidateUpdate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateCreate(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		xrd    *CompositeResourceDefinition
+		err    error
+	}{
+		"ConversionWebhookRequired": {
+			reason: "A conversion webhook must be configured when the conversion strategy is Webhook.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Conversion: &extv1.CustomResourceConversion{Strategy: extv1.WebhookConverter},
+				},
+			},
+			err: errors.New(errConversionWebhookConfigRequired),
+		},
+		"ValidPrinterColumns": {
+			reason: "Printer columns with valid JSONPaths should be accepted.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{
+						AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+							{Name: "REGION", JSONPath: ".spec.parameters.region"},
+							{Name: "ENDPOINT", JSONPath: ".status.endpoints[0].address"},
+						},
+					}},
+				},
+			},
+		},
+		"PrinterColumnMissingPrefix": {
+			reason: "Printer column JSONPaths must start with a '.'.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{}, {
+						AdditionalPrinterColumns: []extv1.CustomResourceColumnDefinition{
+							{Name: "REGION", JSONPath: ".spec.parameters.region"},
+							{Name: "SIZE", JSONPath: "spec.parameters.size"},
+						},
+					}},
+				},
+			},
+			err: errors.Errorf(errFmtInvalidPrinterColumnJSONPath, 1, 1, errPrinterColumnJSONPathPrefix),
+		},
+		"ValidFieldConversions": {
+			reason: "Field conversions between defined versions within the spec or status should be accepted.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+					FieldConversions: []FieldConversion{
+						{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+						{FromVersion: "v2", ToVersion: "v1", FromFieldPath: "status.endpoints[0]", ToFieldPath: "status.endpoint"},
+					},
+				},
+			},
+		},
+		"FieldConversionsWithWebhook": {
+			reason: "Field conversions can't be used with a Webhook conversion strategy.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+					Conversion: &extv1.CustomResourceConversion{
+						Strategy: extv1.WebhookConverter,
+						Webhook:  &extv1.WebhookConversion{},
+					},
+					FieldConversions: []FieldConversion{
+						{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+					},
+				},
+			},
+			err: errors.New(errFieldConversionsWithWebhook),
+		},
+		"FieldConversionUnknownVersion": {
+			reason: "Field conversions must convert between defined versions.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+					FieldConversions: []FieldConversion{
+						{FromVersion: "v1", ToVersion: "v3", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+					},
+				},
+			},
+			err: errors.Wrapf(errors.Errorf(errFmtUnknownVersion, "v3"), errFmtInvalidFieldConversion, 0),
+		},
+		"FieldConversionSameVersion": {
+			reason: "Field conversions must convert between two different versions.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+					FieldConversions: []FieldConversion{
+						{FromVersion: "v1", ToVersion: "v1", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+					},
+				},
+			},
+			err: errors.Wrapf(errors.New(errSameVersion), errFmtInvalidFieldConversion, 0),
+		},
+		"FieldConversionOutsideSpec": {
+			reason: "Field conversions must only move fields within the spec or status.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					Versions: []CompositeResourceDefinitionVersion{{Name: "v1"}, {Name: "v2"}},
+					FieldConversions: []FieldConversion{
+						{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+						{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "metadata.name", ToFieldPath: "spec.name"},
+					},
+				},
+			},
+			err: errors.Wrapf(errors.Errorf(errFmtFieldPathPrefix, "metadata.name"), errFmtInvalidFieldConversion, 1),
+		},
+		"ClaimNamespacesNamesAndSelector": {
+			reason: "Claim namespaces can't be specified both by name and by label selector.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimNamespaces: &ClaimNamespaces{
+						Names:    []string{"tenant-a"},
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+					},
+				},
+			},
+			err: errors.New(errClaimNamespacesNamesAndSelector),
+		},
+		"ClaimNamespacesSelector": {
+			reason: "Claim namespaces may be specified by label selector alone.",
+			xrd: &CompositeResourceDefinition{
+				Spec: CompositeResourceDefinitionSpec{
+					ClaimNamespaces: &ClaimNamespaces{
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.xrd.ValidateCreate()
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -48,6 +48,18 @@ func (c *GeneratedRevisionSpecConverter) FromRevisionSpec(source CompositionRevi
 	}
 	v1CompositionSpec.WriteConnectionSecretsToNamespace = pString
 	v1CompositionSpec.PublishConnectionDetailsWithStoreConfigRef = c.pV1StoreConfigReferenceToPV1StoreConfigReference(source.PublishConnectionDetailsWithStoreConfigRef)
+	var pV1RemovedResourcePolicy *RemovedResourcePolicy
+	if source.RemovedResourcePolicy != nil {
+		v1RemovedResourcePolicy := RemovedResourcePolicy(*source.RemovedResourcePolicy)
+		pV1RemovedResourcePolicy = &v1RemovedResourcePolicy
+	}
+	v1CompositionSpec.RemovedResourcePolicy = pV1RemovedResourcePolicy
+	var pV1WriteConnectionSecretPolicy *WriteConnectionSecretPolicy
+	if source.WriteConnectionSecretPolicy != nil {
+		v1WriteConnectionSecretPolicy := WriteConnectionSecretPolicy(*source.WriteConnectionSecretPolicy)
+		pV1WriteConnectionSecretPolicy = &v1WriteConnectionSecretPolicy
+	}
+	v1CompositionSpec.WriteConnectionSecretPolicy = pV1WriteConnectionSecretPolicy
 	return v1CompositionSpec
 }
 func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec) CompositionRevisionSpec {
@@ -85,6 +97,18 @@ func (c *GeneratedRevisionSpecConverter) ToRevisionSpec(source CompositionSpec)
 	}
 	v1CompositionRevisionSpec.WriteConnectionSecretsToNamespace = pString
 	v1CompositionRevisionSpec.PublishConnectionDetailsWithStoreConfigRef = c.pV1StoreConfigReferenceToPV1StoreConfigReference(source.PublishConnectionDetailsWithStoreConfigRef)
+	var pV1RemovedResourcePolicy *RemovedResourcePolicy
+	if source.RemovedResourcePolicy != nil {
+		v1RemovedResourcePolicy := RemovedResourcePolicy(*source.RemovedResourcePolicy)
+		pV1RemovedResourcePolicy = &v1RemovedResourcePolicy
+	}
+	v1CompositionRevisionSpec.RemovedResourcePolicy = pV1RemovedResourcePolicy
+	var pV1WriteConnectionSecretPolicy *WriteConnectionSecretPolicy
+	if source.WriteConnectionSecretPolicy != nil {
+		v1WriteConnectionSecretPolicy := WriteConnectionSecretPolicy(*source.WriteConnectionSecretPolicy)
+		pV1WriteConnectionSecretPolicy = &v1WriteConnectionSecretPolicy
+	}
+	v1CompositionRevisionSpec.WriteConnectionSecretPolicy = pV1WriteConnectionSecretPolicy
 	return v1CompositionRevisionSpec
 }
 func (c *GeneratedRevisionSpecConverter) pRuntimeRawExtensionToPRuntimeRawExtension(source *runtime.RawExtension) *runtime.RawExtension {
@@ -232,6 +256,12 @@ func (c *GeneratedRevisionSpecConverter) pV1EnvironmentConfigurationToPV1Environ
 		}
 		v1EnvironmentConfiguration.Patches = v1EnvironmentPatchList
 		v1EnvironmentConfiguration.Policy = c.pV1PolicyToPV1Policy((*source).Policy)
+		var pBool *bool
+		if (*source).RecordResolved != nil {
+			xbool := *(*source).RecordResolved
+			pBool = &xbool
+		}
+		v1EnvironmentConfiguration.RecordResolved = pBool
 		pV1EnvironmentConfiguration = &v1EnvironmentConfiguration
 	}
 	return pV1EnvironmentConfiguration
@@ -410,6 +440,20 @@ func (c *GeneratedRevisionSpecConverter) pV1StringCombineToPV1StringCombine(sour
 	}
 	return pV1StringCombine
 }
+func (c *GeneratedRevisionSpecConverter) pV1StringTransformHashToPV1StringTransformHash(source *StringTransformHash) *StringTransformHash {
+	var pV1StringTransformHash *StringTransformHash
+	if source != nil {
+		var v1StringTransformHash StringTransformHash
+		var pInt *int
+		if (*source).Length != nil {
+			xint := *(*source).Length
+			pInt = &xint
+		}
+		v1StringTransformHash.Length = pInt
+		pV1StringTransformHash = &v1StringTransformHash
+	}
+	return pV1StringTransformHash
+}
 func (c *GeneratedRevisionSpecConverter) pV1StringTransformRegexpToPV1StringTransformRegexp(source *StringTransformRegexp) *StringTransformRegexp {
 	var pV1StringTransformRegexp *StringTransformRegexp
 	if source != nil {
@@ -449,6 +493,7 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(
 		}
 		v1StringTransform.Trim = pString2
 		v1StringTransform.Regexp = c.pV1StringTransformRegexpToPV1StringTransformRegexp((*source).Regexp)
+		v1StringTransform.Hash = c.pV1StringTransformHashToPV1StringTransformHash((*source).Hash)
 		pV1StringTransform = &v1StringTransform
 	}
 	return pV1StringTransform
@@ -456,6 +501,14 @@ func (c *GeneratedRevisionSpecConverter) pV1StringTransformToPV1StringTransform(
 func (c *GeneratedRevisionSpecConverter) v1CombineVariableToV1CombineVariable(source CombineVariable) CombineVariable {
 	var v1CombineVariable CombineVariable
 	v1CombineVariable.FromFieldPath = source.FromFieldPath
+	var v1TransformList []Transform
+	if source.Transforms != nil {
+		v1TransformList = make([]Transform, len(source.Transforms))
+		for i := 0; i < len(source.Transforms); i++ {
+			v1TransformList[i] = c.v1TransformToV1Transform(source.Transforms[i])
+		}
+	}
+	v1CombineVariable.Transforms = v1TransformList
 	return v1CombineVariable
 }
 func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(source ComposedTemplate) ComposedTemplate {
@@ -491,6 +544,26 @@ func (c *GeneratedRevisionSpecConverter) v1ComposedTemplateToV1ComposedTemplate(
 		}
 	}
 	v1ComposedTemplate.ReadinessChecks = v1ReadinessCheckList
+	var stringList []string
+	if source.DependsOn != nil {
+		stringList = make([]string, len(source.DependsOn))
+		for l := 0; l < len(source.DependsOn); l++ {
+			stringList[l] = source.DependsOn[l]
+		}
+	}
+	v1ComposedTemplate.DependsOn = stringList
+	var pV1ComposedResourceManagementPolicy *ComposedResourceManagementPolicy
+	if source.ManagementPolicy != nil {
+		v1ComposedResourceManagementPolicy := ComposedResourceManagementPolicy(*source.ManagementPolicy)
+		pV1ComposedResourceManagementPolicy = &v1ComposedResourceManagementPolicy
+	}
+	v1ComposedTemplate.ManagementPolicy = pV1ComposedResourceManagementPolicy
+	var pString2 *string
+	if source.NameTemplate != nil {
+		xstring2 := *source.NameTemplate
+		pString2 = &xstring2
+	}
+	v1ComposedTemplate.NameTemplate = pString2
 	return v1ComposedTemplate
 }
 func (c *GeneratedRevisionSpecConverter) v1ConnectionDetailToV1ConnectionDetail(source ConnectionDetail) ConnectionDetail {
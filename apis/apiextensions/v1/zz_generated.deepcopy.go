@@ -29,13 +29,55 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimNamespaces) DeepCopyInto(out *ClaimNamespaces) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimNamespaces.
+func (in *ClaimNamespaces) DeepCopy() *ClaimNamespaces {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimNamespaces)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimValidation) DeepCopyInto(out *ClaimValidation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimValidation.
+func (in *ClaimValidation) DeepCopy() *ClaimValidation {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimValidation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Combine) DeepCopyInto(out *Combine) {
 	*out = *in
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
 		*out = make([]CombineVariable, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.String != nil {
 		in, out := &in.String, &out.String
@@ -57,6 +99,13 @@ func (in *Combine) DeepCopy() *Combine {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CombineVariable) DeepCopyInto(out *CombineVariable) {
 	*out = *in
+	if in.Transforms != nil {
+		in, out := &in.Transforms, &out.Transforms
+		*out = make([]Transform, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CombineVariable.
@@ -99,6 +148,21 @@ func (in *ComposedTemplate) DeepCopyInto(out *ComposedTemplate) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ManagementPolicy != nil {
+		in, out := &in.ManagementPolicy, &out.ManagementPolicy
+		*out = new(ComposedResourceManagementPolicy)
+		**out = **in
+	}
+	if in.NameTemplate != nil {
+		in, out := &in.NameTemplate, &out.NameTemplate
+		*out = new(string)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComposedTemplate.
@@ -216,6 +280,11 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(CompositionReference)
 		**out = **in
 	}
+	if in.EnforcedCompositionUpdatePolicy != nil {
+		in, out := &in.EnforcedCompositionUpdatePolicy, &out.EnforcedCompositionUpdatePolicy
+		*out = new(commonv1.UpdatePolicy)
+		**out = **in
+	}
 	if in.DefaultCompositionUpdatePolicy != nil {
 		in, out := &in.DefaultCompositionUpdatePolicy, &out.DefaultCompositionUpdatePolicy
 		*out = new(commonv1.UpdatePolicy)
@@ -233,11 +302,31 @@ func (in *CompositeResourceDefinitionSpec) DeepCopyInto(out *CompositeResourceDe
 		*out = new(apiextensionsv1.CustomResourceConversion)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FieldConversions != nil {
+		in, out := &in.FieldConversions, &out.FieldConversions
+		*out = make([]FieldConversion, len(*in))
+		copy(*out, *in)
+	}
+	if in.ClaimValidation != nil {
+		in, out := &in.ClaimValidation, &out.ClaimValidation
+		*out = new(ClaimValidation)
+		**out = **in
+	}
+	if in.ClaimNamespaces != nil {
+		in, out := &in.ClaimNamespaces, &out.ClaimNamespaces
+		*out = new(ClaimNamespaces)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = new(CompositeResourceDefinitionSpecMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DeletionPolicy != nil {
+		in, out := &in.DeletionPolicy, &out.DeletionPolicy
+		*out = new(XRDDeletionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionSpec.
@@ -267,6 +356,11 @@ func (in *CompositeResourceDefinitionSpecMetadata) DeepCopyInto(out *CompositeRe
 			(*out)[key] = val
 		}
 	}
+	if in.PropagateLabels != nil {
+		in, out := &in.PropagateLabels, &out.PropagateLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositeResourceDefinitionSpecMetadata.
@@ -519,6 +613,16 @@ func (in *CompositionRevisionSpec) DeepCopyInto(out *CompositionRevisionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.RemovedResourcePolicy != nil {
+		in, out := &in.RemovedResourcePolicy, &out.RemovedResourcePolicy
+		*out = new(RemovedResourcePolicy)
+		**out = **in
+	}
+	if in.WriteConnectionSecretPolicy != nil {
+		in, out := &in.WriteConnectionSecretPolicy, &out.WriteConnectionSecretPolicy
+		*out = new(WriteConnectionSecretPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionRevisionSpec.
@@ -587,6 +691,16 @@ func (in *CompositionSpec) DeepCopyInto(out *CompositionSpec) {
 		*out = new(StoreConfigReference)
 		**out = **in
 	}
+	if in.RemovedResourcePolicy != nil {
+		in, out := &in.RemovedResourcePolicy, &out.RemovedResourcePolicy
+		*out = new(RemovedResourcePolicy)
+		**out = **in
+	}
+	if in.WriteConnectionSecretPolicy != nil {
+		in, out := &in.WriteConnectionSecretPolicy, &out.WriteConnectionSecretPolicy
+		*out = new(WriteConnectionSecretPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CompositionSpec.
@@ -811,6 +925,11 @@ func (in *EnvironmentConfiguration) DeepCopyInto(out *EnvironmentConfiguration)
 		*out = new(commonv1.Policy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RecordResolved != nil {
+		in, out := &in.RecordResolved, &out.RecordResolved
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EnvironmentConfiguration.
@@ -957,6 +1076,21 @@ func (in *EnvironmentSourceSelectorLabelMatcher) DeepCopy() *EnvironmentSourceSe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldConversion) DeepCopyInto(out *FieldConversion) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldConversion.
+func (in *FieldConversion) DeepCopy() *FieldConversion {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldConversion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Function) DeepCopyInto(out *Function) {
 	*out = *in
@@ -1280,6 +1414,11 @@ func (in *StringTransform) DeepCopyInto(out *StringTransform) {
 		*out = new(StringTransformRegexp)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Hash != nil {
+		in, out := &in.Hash, &out.Hash
+		*out = new(StringTransformHash)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransform.
@@ -1292,6 +1431,26 @@ func (in *StringTransform) DeepCopy() *StringTransform {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StringTransformHash) DeepCopyInto(out *StringTransformHash) {
+	*out = *in
+	if in.Length != nil {
+		in, out := &in.Length, &out.Length
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StringTransformHash.
+func (in *StringTransformHash) DeepCopy() *StringTransformHash {
+	if in == nil {
+		return nil
+	}
+	out := new(StringTransformHash)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StringTransformRegexp) DeepCopyInto(out *StringTransformRegexp) {
 	*out = *in
@@ -48,6 +48,15 @@ var (
 	EnvironmentConfigGroupVersionKind = SchemeGroupVersion.WithKind(EnvironmentConfigKind)
 )
 
+// Usage type metadata.
+var (
+	UsageKind             = reflect.TypeOf(Usage{}).Name()
+	UsageGroupKind        = schema.GroupKind{Group: Group, Kind: UsageKind}.String()
+	UsageKindAPIVersion   = UsageKind + "." + SchemeGroupVersion.String()
+	UsageGroupVersionKind = SchemeGroupVersion.WithKind(UsageKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&EnvironmentConfig{}, &EnvironmentConfigList{})
+	SchemeBuilder.Register(&Usage{}, &UsageList{})
 }
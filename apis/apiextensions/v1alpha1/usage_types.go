@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ResourceRef is a reference to a resource.
+type ResourceRef struct {
+	// Name of the referent.
+	Name string `json:"name"`
+}
+
+// ResourceSelector is used to select a resource.
+type ResourceSelector struct {
+	// MatchLabels ensures an object with matching labels is selected.
+	// +optional
+	MatchLabels map[string]string `json:"matchLabels,omitempty"`
+
+	// MatchControllerRef ensures an object with the same controller reference
+	// as the selecting Usage is selected.
+	// +optional
+	MatchControllerRef *bool `json:"matchControllerRef,omitempty"`
+}
+
+// A Resource is a cluster scoped resource that uses, or is used by, another.
+// +kubebuilder:validation:XValidation:rule="has(self.resourceRef) || has(self.resourceSelector)",message="either a resource reference or a resource selector should be set."
+type Resource struct {
+	// APIVersion of the referent.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referent.
+	Kind string `json:"kind"`
+
+	// ResourceRef is a reference to the resource.
+	// +optional
+	ResourceRef *ResourceRef `json:"resourceRef,omitempty"`
+
+	// ResourceSelector selects the resource. It is ignored if ResourceRef is
+	// set, and replaced by a ResourceRef once a resource is selected.
+	// +optional
+	ResourceSelector *ResourceSelector `json:"resourceSelector,omitempty"`
+}
+
+// UsageSpec defines the desired state of a Usage.
+// +kubebuilder:validation:XValidation:rule="has(self.by) || has(self.reason)",message="either \"spec.by\" or \"spec.reason\" must be specified."
+type UsageSpec struct {
+	// Of is the resource that is used. Deleting it is blocked while the Usage
+	// exists.
+	Of Resource `json:"of"`
+
+	// By is the resource that uses the other. The Usage is deleted once the
+	// resource that uses the other is deleted.
+	// +optional
+	By *Resource `json:"by,omitempty"`
+
+	// Reason the resource is used. Usually set when there is no resource that
+	// uses it.
+	// +optional
+	Reason *string `json:"reason,omitempty"`
+}
+
+// UsageStatus represents the observed state of a Usage.
+type UsageStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +genclient
+// +genclient:nonNamespaced
+
+// A Usage declares that a resource is used by another resource, or for a
+// reason. Deleting a resource is blocked while any Usage of it exists.
+// +kubebuilder:printcolumn:name="OF",type="string",JSONPath=".spec.of.resourceRef.name"
+// +kubebuilder:printcolumn:name="BY",type="string",JSONPath=".spec.by.resourceRef.name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories=crossplane
+// +kubebuilder:subresource:status
+type Usage struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   UsageSpec   `json:"spec"`
+	Status UsageStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// UsageList contains a list of Usages.
+type UsageList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Usage `json:"items"`
+}
+
+// GetCondition of this Usage.
+func (u *Usage) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+	return u.Status.GetCondition(ct)
+}
+
+// SetConditions of this Usage.
+func (u *Usage) SetConditions(c ...xpv1.Condition) {
+	u.Status.SetConditions(c...)
+}
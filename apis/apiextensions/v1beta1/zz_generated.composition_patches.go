@@ -20,6 +20,8 @@ package v1beta1
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
@@ -73,6 +75,15 @@ func (pp *PatchPolicy) GetFromFieldPathPolicy() FromFieldPathPolicy {
 	return *pp.FromFieldPath
 }
 
+// GetMergeOptions returns the MergeOptions for this PatchPolicy, or nil if not
+// specified.
+func (pp *PatchPolicy) GetMergeOptions() *xpv1.MergeOptions {
+	if pp == nil {
+		return nil
+	}
+	return pp.MergeOptions
+}
+
 // Patch objects are applied between composite and composed resources. Their
 // behaviour depends on the Type selected. The default Type,
 // FromCompositeFieldPath, copies a value from the composite resource to
@@ -158,6 +169,9 @@ func (p *Patch) Validate() *field.Error {
 		if p.ToFieldPath == nil {
 			return field.Required(field.NewPath("toFieldPath"), fmt.Sprintf("toFieldPath must be set for patch type %s", p.Type))
 		}
+		if err := p.Combine.Validate(); err != nil {
+			return verrors.WrapFieldError(err, field.NewPath("combine"))
+		}
 	default:
 		// Should never happen
 		return field.Invalid(field.NewPath("type"), p.Type, "unknown patch type")
@@ -178,6 +192,11 @@ type CombineVariable struct {
 	// FromFieldPath is the path of the field on the source whose value is
 	// to be used as input.
 	FromFieldPath string `json:"fromFieldPath"`
+
+	// Transforms are the list of functions that are used as a FIFO pipe for the
+	// value of the variable before it is combined with the others.
+	// +optional
+	Transforms []Transform `json:"transforms,omitempty"`
 }
 
 // A CombineStrategy determines what strategy will be applied to combine
@@ -208,6 +227,82 @@ type Combine struct {
 	String *StringCombine `json:"string,omitempty"`
 }
 
+// Validate the Combine object.
+func (c *Combine) Validate() *field.Error {
+	if len(c.Variables) == 0 {
+		return field.Required(field.NewPath("variables"), "at least one variable must be set")
+	}
+	for i, v := range c.Variables {
+		for j, t := range v.Transforms {
+			if err := t.Validate(); err != nil {
+				return verrors.WrapFieldError(err, field.NewPath("variables").Index(i).Child("transforms").Index(j))
+			}
+		}
+	}
+	switch c.Strategy {
+	case CombineStrategyString:
+		if c.String == nil {
+			return field.Required(field.NewPath("string"), fmt.Sprintf("string must be set for combine strategy %s", c.Strategy))
+		}
+		n, reordered := formatArgs(c.String.Format)
+		// A format string that explicitly indexes its arguments may use a
+		// variable more than once, or not at all.
+		if n > len(c.Variables) || (!reordered && n != len(c.Variables)) {
+			return field.Invalid(field.NewPath("string", "fmt"), c.String.Format, fmt.Sprintf("format requires %d variables, but %d are specified", n, len(c.Variables)))
+		}
+	default:
+		return field.Invalid(field.NewPath("strategy"), c.Strategy, "unknown combine strategy")
+	}
+	return nil
+}
+
+// formatArgs returns how many arguments the supplied Go format string
+// consumes, and whether it explicitly indexes its arguments.
+func formatArgs(format string) (n int, reordered bool) {
+	argNum := 0
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+		i++
+
+		// Skip any flags, argument indexes, widths, and precisions.
+	verb:
+		for ; i < len(format); i++ {
+			switch c := format[i]; {
+			case c == '[':
+				end := strings.IndexByte(format[i:], ']')
+				if end < 0 {
+					return n, reordered
+				}
+				if idx, err := strconv.Atoi(format[i+1 : i+end]); err == nil && idx > 0 {
+					argNum = idx - 1
+					reordered = true
+				}
+				i += end
+			case c == '*':
+				// A width or precision of * consumes an argument.
+				argNum++
+				if argNum > n {
+					n = argNum
+				}
+			case strings.IndexByte("+-# 0123456789.", c) >= 0:
+				// Flags, widths, and precisions don't consume arguments.
+			default:
+				break verb
+			}
+		}
+		if i >= len(format) || format[i] == '%' {
+			continue
+		}
+		argNum++
+		if argNum > n {
+			n = argNum
+		}
+	}
+	return n, reordered
+}
+
 // A StringCombine combines multiple input values into a single string.
 type StringCombine struct {
 	// Format the input using a Go format string. See
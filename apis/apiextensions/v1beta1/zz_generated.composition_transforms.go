@@ -341,6 +341,8 @@ const (
 	StringTransformTypeTrimPrefix StringTransformType = "TrimPrefix"
 	StringTransformTypeTrimSuffix StringTransformType = "TrimSuffix"
 	StringTransformTypeRegexp     StringTransformType = "Regexp"
+	StringTransformTypeHash       StringTransformType = "Hash"
+	StringTransformTypeURLEncode  StringTransformType = "URLEncode"
 )
 
 // StringConversionType converts a string.
@@ -364,7 +366,7 @@ type StringTransform struct {
 
 	// Type of the string transform to be run.
 	// +optional
-	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp
+	// +kubebuilder:validation:Enum=Format;Convert;TrimPrefix;TrimSuffix;Regexp;Hash;URLEncode
 	// +kubebuilder:default=Format
 	Type StringTransformType `json:"type,omitempty"`
 
@@ -390,6 +392,11 @@ type StringTransform struct {
 	// Extract a match from the input using a regular expression.
 	// +optional
 	Regexp *StringTransformRegexp `json:"regexp,omitempty"`
+
+	// Hash the input using SHA-256, optionally returning only a prefix of the
+	// hex encoded hash.
+	// +optional
+	Hash *StringTransformHash `json:"hash,omitempty"`
 }
 
 // Validate checks this StringTransform is valid.
@@ -419,6 +426,11 @@ func (s *StringTransform) Validate() *field.Error {
 		if _, err := regexp.Compile(s.Regexp.Match); err != nil {
 			return field.Invalid(field.NewPath("regexp", "match"), s.Regexp.Match, "invalid regexp")
 		}
+	case StringTransformTypeHash:
+		if s.Hash != nil && s.Hash.Length != nil && (*s.Hash.Length < 1 || *s.Hash.Length > 64) {
+			return field.Invalid(field.NewPath("hash", "length"), *s.Hash.Length, "hash length must be between 1 and 64")
+		}
+	case StringTransformTypeURLEncode:
 	default:
 		return field.Invalid(field.NewPath("type"), s.Type, "unknown string transform type")
 	}
@@ -438,6 +450,16 @@ type StringTransformRegexp struct {
 	Group *int `json:"group,omitempty"`
 }
 
+// A StringTransformHash hashes the input using SHA-256.
+type StringTransformHash struct {
+	// Length of the prefix of the hex encoded hash to return. The entire
+	// 64 character hash is returned by default.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	Length *int `json:"length,omitempty"`
+}
+
 // TransformIOType defines the type of a ConvertTransform.
 type TransformIOType string
 
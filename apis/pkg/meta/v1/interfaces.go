@@ -16,6 +16,8 @@ limitations under the License.
 
 package v1
 
+import "k8s.io/apimachinery/pkg/runtime"
+
 var _ Pkg = &Configuration{}
 var _ Pkg = &Provider{}
 
@@ -24,6 +26,7 @@ var _ Pkg = &Provider{}
 type Pkg interface {
 	GetCrossplaneConstraints() *CrossplaneConstraints
 	GetDependencies() []Dependency
+	GetExtraObjects() []runtime.RawExtension
 }
 
 // GetCrossplaneConstraints gets the Configuration package's Crossplane version
@@ -37,6 +40,11 @@ func (c *Configuration) GetDependencies() []Dependency {
 	return c.Spec.MetaSpec.DependsOn
 }
 
+// GetExtraObjects gets the Configuration package's extra objects.
+func (c *Configuration) GetExtraObjects() []runtime.RawExtension {
+	return c.Spec.MetaSpec.ExtraObjects
+}
+
 // GetCrossplaneConstraints gets the Provider package's Crossplane version
 // constraints.
 func (c *Provider) GetCrossplaneConstraints() *CrossplaneConstraints {
@@ -47,3 +55,8 @@ func (c *Provider) GetCrossplaneConstraints() *CrossplaneConstraints {
 func (c *Provider) GetDependencies() []Dependency {
 	return c.Spec.MetaSpec.DependsOn
 }
+
+// GetExtraObjects gets the Provider package's extra objects.
+func (c *Provider) GetExtraObjects() []runtime.RawExtension {
+	return c.Spec.MetaSpec.ExtraObjects
+}
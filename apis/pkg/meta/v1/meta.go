@@ -16,6 +16,8 @@ limitations under the License.
 
 package v1
 
+import "k8s.io/apimachinery/pkg/runtime"
+
 // MetaSpec are fields that every meta package type must implement.
 type MetaSpec struct {
 	// Semantic version constraints of Crossplane that package is compatible with.
@@ -23,6 +25,14 @@ type MetaSpec struct {
 
 	// Dependencies on other packages.
 	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// ExtraObjects are additional objects, for example a ConfigMap or a
+	// ServiceAccount, that are applied alongside the package's other objects.
+	// Namespaced objects that don't specify a namespace are created in the
+	// namespace Crossplane runs in.
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:EmbeddedResource
+	ExtraObjects []runtime.RawExtension `json:"extraObjects,omitempty"`
 }
 
 // CrossplaneConstraints specifies a packages compatibility with Crossplane versions.
@@ -19,6 +19,7 @@ package v1alpha1
 import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
@@ -36,7 +37,7 @@ const (
 //
 // goverter:converter
 // goverter:name GeneratedToHubConverter
-// goverter:extend ConvertObjectMeta
+// goverter:extend ConvertObjectMeta ConvertRawExtension
 // +k8s:deepcopy-gen=false
 type ToHubConverter interface {
 	Configuration(in *Configuration) *v1.Configuration
@@ -47,7 +48,7 @@ type ToHubConverter interface {
 //
 // goverter:converter
 // goverter:name GeneratedFromHubConverter
-// goverter:extend ConvertObjectMeta
+// goverter:extend ConvertObjectMeta ConvertRawExtension
 // +k8s:deepcopy-gen=false
 type FromHubConverter interface {
 	Configuration(in *v1.Configuration) *Configuration
@@ -62,6 +63,13 @@ func ConvertObjectMeta(in metav1.ObjectMeta) metav1.ObjectMeta {
 	return *out
 }
 
+// ConvertRawExtension 'converts' a RawExtension by producing a deepcopy. This
+// is necessary because goverter can't convert an embedded runtime.Object.
+func ConvertRawExtension(in runtime.RawExtension) runtime.RawExtension {
+	out := in.DeepCopy()
+	return *out
+}
+
 // ConvertTo converts this Configuration to the Hub version.
 func (c *Configuration) ConvertTo(hub conversion.Hub) error {
 	out, ok := hub.(*v1.Configuration)
@@ -6,6 +6,7 @@ import (
 	v1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v11 "k8s.io/api/rbac/v1"
 	v12 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 type GeneratedFromHubConverter struct{}
@@ -92,6 +93,14 @@ func (c *GeneratedFromHubConverter) v1MetaSpecToV1alpha1MetaSpec(source v1.MetaS
 		}
 	}
 	v1alpha1MetaSpec.DependsOn = v1alpha1DependencyList
+	var runtimeRawExtensionList []runtime.RawExtension
+	if source.ExtraObjects != nil {
+		runtimeRawExtensionList = make([]runtime.RawExtension, len(source.ExtraObjects))
+		for j := 0; j < len(source.ExtraObjects); j++ {
+			runtimeRawExtensionList[j] = ConvertRawExtension(source.ExtraObjects[j])
+		}
+	}
+	v1alpha1MetaSpec.ExtraObjects = runtimeRawExtensionList
 	return v1alpha1MetaSpec
 }
 func (c *GeneratedFromHubConverter) v1PolicyRuleToV1PolicyRule(source v11.PolicyRule) v11.PolicyRule {
@@ -285,6 +294,14 @@ func (c *GeneratedToHubConverter) v1alpha1MetaSpecToV1MetaSpec(source MetaSpec)
 		}
 	}
 	v1MetaSpec.DependsOn = v1DependencyList
+	var runtimeRawExtensionList []runtime.RawExtension
+	if source.ExtraObjects != nil {
+		runtimeRawExtensionList = make([]runtime.RawExtension, len(source.ExtraObjects))
+		for j := 0; j < len(source.ExtraObjects); j++ {
+			runtimeRawExtensionList[j] = ConvertRawExtension(source.ExtraObjects[j])
+		}
+	}
+	v1MetaSpec.ExtraObjects = runtimeRawExtensionList
 	return v1MetaSpec
 }
 func (c *GeneratedToHubConverter) v1alpha1ProviderSpecToV1ProviderSpec(source ProviderSpec) v1.ProviderSpec {
@@ -30,18 +30,52 @@ const (
 
 	// A TypeHealthy indicates whether a package is healthy.
 	TypeHealthy xpv1.ConditionType = "Healthy"
+
+	// A TypeObjectsExcluded indicates whether any of a package's objects
+	// were excluded by its object selector.
+	TypeObjectsExcluded xpv1.ConditionType = "ObjectsExcluded"
+
+	// A TypeRBACApplied indicates whether the RBAC manager applied the RBAC
+	// ClusterRoles of a provider revision.
+	TypeRBACApplied xpv1.ConditionType = "RBACApplied"
 )
 
 // Reasons a package is or is not installed.
 const (
 	ReasonUnpacking     xpv1.ConditionReason = "UnpackingPackage"
+	ReasonPulling       xpv1.ConditionReason = "PullingPackage"
 	ReasonInactive      xpv1.ConditionReason = "InactivePackageRevision"
 	ReasonActive        xpv1.ConditionReason = "ActivePackageRevision"
 	ReasonUnhealthy     xpv1.ConditionReason = "UnhealthyPackageRevision"
 	ReasonHealthy       xpv1.ConditionReason = "HealthyPackageRevision"
 	ReasonUnknownHealth xpv1.ConditionReason = "UnknownPackageRevisionHealth"
+	ReasonNotAPackage   xpv1.ConditionReason = "NotAPackage"
+
+	ReasonExtraObjectConflict xpv1.ConditionReason = "ExtraObjectConflict"
+	ReasonObjectConflict      xpv1.ConditionReason = "ObjectConflict"
+	ReasonDowngradePrevented  xpv1.ConditionReason = "DowngradePrevented"
+	ReasonAwaitingCRDs        xpv1.ConditionReason = "AwaitingEstablishedCRDs"
+	ReasonInstallTimeout      xpv1.ConditionReason = "InstallTimeout"
+	ReasonRunningUpgradeHook  xpv1.ConditionReason = "RunningUpgradeHook"
+	ReasonUpgradeHookFailed   xpv1.ConditionReason = "UpgradeHookFailed"
 )
 
+// Reasons a package's objects are or are not excluded.
+const (
+	ReasonExcludedBySelector xpv1.ConditionReason = "ExcludedByObjectSelector"
+	ReasonNoObjectsExcluded  xpv1.ConditionReason = "NoObjectsExcluded"
+)
+
+// Reasons a provider revision's RBAC ClusterRoles are or are not applied.
+const (
+	ReasonClusterRolesApplied     xpv1.ConditionReason = "ClusterRolesApplied"
+	ReasonApplyClusterRolesFailed xpv1.ConditionReason = "ApplyClusterRolesFailed"
+)
+
+// ReasonPrefixRevision prefixes the reasons of the conditions of an active
+// package revision when they are propagated to its package.
+const ReasonPrefixRevision = "Revision"
+
 // Unpacking indicates that the package manager is waiting for a package
 // revision to be unpacked.
 func Unpacking() xpv1.Condition {
@@ -53,6 +87,17 @@ func Unpacking() xpv1.Condition {
 	}
 }
 
+// Pulling indicates that the package manager is pulling a package revision's
+// image. The health of the package revision is unknown until it is pulled.
+func Pulling() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPulling,
+	}
+}
+
 // Inactive indicates that the package manager is waiting for a package
 // revision to be transitioned to an active state.
 func Inactive() xpv1.Condition {
@@ -64,6 +109,39 @@ func Inactive() xpv1.Condition {
 	}
 }
 
+// DowngradePrevented indicates that the package manager refused to activate a
+// package revision because it would downgrade the package.
+func DowngradePrevented() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonDowngradePrevented,
+	}
+}
+
+// RunningUpgradeHook indicates that the package manager is waiting for a
+// lifecycle hook to complete before it continues upgrading a package.
+func RunningUpgradeHook() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonRunningUpgradeHook,
+	}
+}
+
+// UpgradeHookFailed indicates that the package manager refused to continue
+// upgrading a package because one of its lifecycle hooks failed.
+func UpgradeHookFailed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeInstalled,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonUpgradeHookFailed,
+	}
+}
+
 // Active indicates that the package manager has installed and activated
 // a package revision.
 func Active() xpv1.Condition {
@@ -85,6 +163,41 @@ func Unhealthy() xpv1.Condition {
 	}
 }
 
+// NotAPackage indicates that the current revision is unhealthy because its
+// image is not a Crossplane package.
+func NotAPackage() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNotAPackage,
+	}
+}
+
+// ExtraObjectConflict indicates that the current revision is unhealthy
+// because one of the extra objects it declares already exists, and is not
+// owned by its package.
+func ExtraObjectConflict() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExtraObjectConflict,
+	}
+}
+
+// ObjectConflict indicates that the current revision is unhealthy because
+// some of the objects it declares are also declared by an active revision of
+// another package.
+func ObjectConflict() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonObjectConflict,
+	}
+}
+
 // Healthy indicates that the current revision is healthy.
 func Healthy() xpv1.Condition {
 	return xpv1.Condition{
@@ -104,3 +217,70 @@ func UnknownHealth() xpv1.Condition {
 		Reason:             ReasonUnknownHealth,
 	}
 }
+
+// AwaitingCRDs indicates that the health of the current revision is unknown
+// because some of its CustomResourceDefinitions are not yet established.
+func AwaitingCRDs() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAwaitingCRDs,
+	}
+}
+
+// InstallTimeout indicates that the package is unhealthy because its source
+// was not installed within its install timeout.
+func InstallTimeout() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeHealthy,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonInstallTimeout,
+	}
+}
+
+// ObjectsExcluded indicates that some of a package revision's objects were
+// excluded by its object selector, and thus were not applied.
+func ObjectsExcluded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeObjectsExcluded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonExcludedBySelector,
+	}
+}
+
+// NoObjectsExcluded indicates that all of a package revision's objects were
+// applied.
+func NoObjectsExcluded() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeObjectsExcluded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNoObjectsExcluded,
+	}
+}
+
+// ClusterRolesApplied indicates that the RBAC manager applied the RBAC
+// ClusterRoles of a provider revision.
+func ClusterRolesApplied() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRBACApplied,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonClusterRolesApplied,
+	}
+}
+
+// ApplyClusterRolesFailed indicates that the RBAC manager failed to apply the
+// RBAC ClusterRoles of a provider revision. The provider's controller may be
+// missing permissions it needs.
+func ApplyClusterRolesFailed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeRBACApplied,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonApplyClusterRolesFailed,
+	}
+}
@@ -52,6 +52,14 @@ type ConfigurationSpec struct {
 type ConfigurationStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
 	PackageStatus          `json:",inline"`
+
+	// DefaultCompositeResourceRef references the default, or 'entrypoint',
+	// CompositeResourceDefinition installed by this configuration. This is
+	// the definition named by the pkg.crossplane.io/default-composite-resource
+	// annotation of the configuration's package metadata, or the only
+	// definition the configuration installs. It is unset otherwise.
+	// +optional
+	DefaultCompositeResourceRef *xpv1.TypedReference `json:"defaultCompositeResourceRef,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -17,7 +17,15 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -41,6 +49,28 @@ const (
 	// revisions, and can be used to select all provider revisions that belong
 	// to a particular family. It is not added to providers, only revisions.
 	LabelProviderFamily = "pkg.crossplane.io/provider-family"
+
+	// AnnotationAllowDowngrade may be set to "true" on a package to allow it
+	// to be downgraded even if it specifies that downgrades should be
+	// prevented.
+	AnnotationAllowDowngrade = "pkg.crossplane.io/allow-downgrade"
+
+	// AnnotationRBACNamespaces is set by the package revision controller on
+	// provider revisions whose ControllerConfig scopes them to a set of
+	// namespaces. Its value is a comma separated list of namespaces. The RBAC
+	// manager grants these providers Roles in each namespace rather than
+	// ClusterRoles.
+	AnnotationRBACNamespaces = "pkg.crossplane.io/rbac-namespaces"
+
+	// AnnotationDefaultCompositeResource may be set on the metadata of a
+	// configuration package to the name of the CompositeResourceDefinition
+	// that is the configuration's default, or 'entrypoint', definition.
+	AnnotationDefaultCompositeResource = "pkg.crossplane.io/default-composite-resource"
+
+	// AnnotationAllowObjectConflicts may be set to "true" on a package to
+	// allow it to be installed even if it declares objects, such as CRDs, that
+	// are also declared by an active revision of another package.
+	AnnotationAllowObjectConflicts = "pkg.crossplane.io/allow-object-conflicts"
 )
 
 // RevisionActivationPolicy indicates how a package should activate its
@@ -56,6 +86,124 @@ var (
 	ManualActivation RevisionActivationPolicy = "Manual"
 )
 
+// RevisionUpdatePolicy indicates which fields of a package should be synced to
+// its existing current revision.
+type RevisionUpdatePolicy string
+
+const (
+	// RevisionUpdateAll indicates that all fields should be synced.
+	RevisionUpdateAll RevisionUpdatePolicy = "All"
+	// RevisionUpdatePullSecretOnly indicates that only package pull secrets
+	// should be synced.
+	RevisionUpdatePullSecretOnly RevisionUpdatePolicy = "PullSecretOnly"
+	// RevisionUpdateNone indicates that no fields should be synced.
+	RevisionUpdateNone RevisionUpdatePolicy = "None"
+)
+
+// UpdatePolicy determines whether the package manager tracks the digest a
+// package's source resolves to.
+type UpdatePolicy string
+
+const (
+	// UpdateContinuous indicates that a package should be updated whenever
+	// the digest its source resolves to changes.
+	UpdateContinuous UpdatePolicy = "Continuous"
+	// UpdateOnce indicates that a package's source should be resolved to a
+	// digest only when the source changes.
+	UpdateOnce UpdatePolicy = "Once"
+)
+
+// A PackageStatusSummary is a snapshot of the health of a package.
+// +kubebuilder:object:generate=false
+type PackageStatusSummary struct {
+	// Healthy is true if the package's Healthy condition is true.
+	Healthy bool
+
+	// Installed is true if the package's Installed condition is true.
+	Installed bool
+
+	// Degraded is true if the package is installed but not healthy.
+	Degraded bool
+
+	// CurrentRevision is the name of the package's current revision.
+	CurrentRevision string
+
+	// LastRevision is the name of the revision that was current before
+	// CurrentRevision.
+	LastRevision string
+
+	// DependencyStatus of the package's current revision.
+	DependencyStatus DependencyStatus
+}
+
+// summarize returns a PackageStatusSummary of the supplied package.
+func summarize(p Package) PackageStatusSummary {
+	s := PackageStatusSummary{
+		Healthy:         p.GetCondition(TypeHealthy).Status == corev1.ConditionTrue,
+		Installed:       p.GetCondition(TypeInstalled).Status == corev1.ConditionTrue,
+		CurrentRevision: p.GetCurrentRevision(),
+		LastRevision:    p.GetLastRevision(),
+	}
+	s.Degraded = s.Installed && !s.Healthy
+	if d := p.GetDependencyStatus(); d != nil {
+		s.DependencyStatus = *d
+	}
+	return s
+}
+
+// Package states reported by StatusSummary.
+const (
+	StateHealthy    = "Healthy"
+	StateInstalling = "Installing"
+	StateInactive   = "Inactive"
+	StateFailed     = "Failed"
+)
+
+// StatusSummary returns a single line summarizing the supplied package's
+// state, activation policy, current revision, and dependency status, e.g.
+//
+//	state=Healthy activation=Automatic revision=cool-1234567 dependencies=found:2,installed:2,invalid:0
+//
+// The line is a series of space separated key=value pairs in a fixed order,
+// suitable for parsing by scripts. A package with no current revision is
+// reported as revision=<none>.
+func StatusSummary(p Package) string {
+	s := p.GetStatusSummary()
+
+	state := StateInstalling
+	switch {
+	case p.GetCondition(TypeHealthy).Status == corev1.ConditionFalse:
+		state = StateFailed
+	case s.Installed && s.Healthy:
+		state = StateHealthy
+	case p.GetCondition(TypeInstalled).Reason == ReasonInactive:
+		state = StateInactive
+	}
+
+	activation := AutomaticActivation
+	if a := p.GetActivationPolicy(); a != nil {
+		activation = *a
+	}
+
+	rev := s.CurrentRevision
+	if rev == "" {
+		rev = "<none>"
+	}
+
+	d := s.DependencyStatus
+	return fmt.Sprintf("state=%s activation=%s revision=%s dependencies=found:%d,installed:%d,invalid:%d", state, activation, rev, d.Found, d.Installed, d.Invalid)
+}
+
+// A PackageType is a type of package. Its values match those of the package
+// types recorded in the v1beta1 Lock.
+type PackageType string
+
+// Types of packages.
+const (
+	ConfigurationPackageType PackageType = "Configuration"
+	ProviderPackageType      PackageType = "Provider"
+)
+
 // RefNames converts a slice of LocalObjectReferences to a slice of strings.
 func RefNames(refs []corev1.LocalObjectReference) []string {
 	stringRefs := make([]string, len(refs))
@@ -65,6 +213,22 @@ func RefNames(refs []corev1.LocalObjectReference) []string {
 	return stringRefs
 }
 
+// CRDNames returns the names of any CustomResourceDefinitions in the supplied
+// slice of object references.
+func CRDNames(refs []xpv1.TypedReference) []string {
+	names := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		// This would only return an error if the APIVersion contained more
+		// than one "/", in which case the reference can't be to a CRD.
+		gv, _ := schema.ParseGroupVersion(ref.APIVersion)
+		if gv.Group != "apiextensions.k8s.io" || ref.Kind != "CustomResourceDefinition" {
+			continue
+		}
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
 var _ Package = &Provider{}
 var _ Package = &Configuration{}
 
@@ -74,9 +238,15 @@ type Package interface {
 	resource.Object
 	resource.Conditioned
 
+	GetConditionedStatus() *xpv1.ConditionedStatus
+
+	GetPackageType() PackageType
+
 	GetSource() string
 	SetSource(s string)
 
+	GetEffectiveSource(mirrors map[string]string) string
+
 	GetActivationPolicy() *RevisionActivationPolicy
 	SetActivationPolicy(a *RevisionActivationPolicy)
 
@@ -89,14 +259,63 @@ type Package interface {
 	GetRevisionHistoryLimit() *int64
 	SetRevisionHistoryLimit(l *int64)
 
+	GetRevisionTTL() *metav1.Duration
+	SetRevisionTTL(d *metav1.Duration)
+
+	GetRevisionUpdatePolicy() *RevisionUpdatePolicy
+	SetRevisionUpdatePolicy(u *RevisionUpdatePolicy)
+
+	GetUpdatePolicy() *UpdatePolicy
+	SetUpdatePolicy(u *UpdatePolicy)
+
 	GetIgnoreCrossplaneConstraints() *bool
 	SetIgnoreCrossplaneConstraints(b *bool)
 
 	GetControllerConfigRef() *ControllerConfigReference
 	SetControllerConfigRef(r *ControllerConfigReference)
 
+	GetMaxUnavailable() *intstr.IntOrString
+	SetMaxUnavailable(m *intstr.IntOrString)
+
+	GetHealthCheck() *HealthCheck
+	SetHealthCheck(hc *HealthCheck)
+
+	GetPriorityClassName() *string
+	SetPriorityClassName(n *string)
+
+	GetPodTemplateAnnotations() map[string]string
+	SetPodTemplateAnnotations(a map[string]string)
+
+	GetTolerations() []corev1.Toleration
+	SetTolerations(t []corev1.Toleration)
+
+	GetNodeSelector() map[string]string
+	SetNodeSelector(s map[string]string)
+
+	GetProviderRevisionAffinity() *corev1.Affinity
+	SetProviderRevisionAffinity(a *corev1.Affinity)
+
+	GetReadinessGates() []corev1.PodReadinessGate
+	SetReadinessGates(g []corev1.PodReadinessGate)
+
+	GetMinReadySeconds() *int32
+	SetMinReadySeconds(s *int32)
+
+	GetReplicaCount() *int32
+	SetReplicaCount(n *int32)
+
+	GetLeaderElection() *bool
+	SetLeaderElection(b *bool)
+
+	GetExtraVolumes() []corev1.Volume
+	SetExtraVolumes(v []corev1.Volume)
+
+	GetExtraVolumeMounts() []corev1.VolumeMount
+	SetExtraVolumeMounts(vm []corev1.VolumeMount)
+
 	GetCurrentRevision() string
 	SetCurrentRevision(r string)
+	GetCurrentRevisionRef() *corev1.ObjectReference
 
 	GetCurrentIdentifier() string
 	SetCurrentIdentifier(r string)
@@ -104,8 +323,40 @@ type Package interface {
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(*bool)
 
+	GetPreventDowngrade() *bool
+	SetPreventDowngrade(b *bool)
+
+	GetObjectSelector() *ObjectSelector
+	SetObjectSelector(s *ObjectSelector)
+
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
+
+	GetProviderFamilyRef() *corev1.LocalObjectReference
+	SetProviderFamilyRef(r *corev1.LocalObjectReference)
+
+	GetDefaultCompositeResourceRef() *xpv1.TypedReference
+	SetDefaultCompositeResourceRef(r *xpv1.TypedReference)
+
+	GetInstallTimeout() *metav1.Duration
+	SetInstallTimeout(d *metav1.Duration)
+
+	GetReconcileInterval() *metav1.Duration
+	SetReconcileInterval(d *metav1.Duration)
+
+	GetLifecycleHooks() *LifecycleHooks
+	SetLifecycleHooks(h *LifecycleHooks)
+
+	GetInstallAttempt() *InstallAttempt
+	SetInstallAttempt(a *InstallAttempt)
+
+	GetLastRevision() string
+	SetLastRevision(r string)
+
+	GetDependencyStatus() *DependencyStatus
+	SetDependencyStatus(d *DependencyStatus)
+
+	GetStatusSummary() PackageStatusSummary
 }
 
 // GetCondition of this Provider.
@@ -118,6 +369,13 @@ func (p *Provider) SetConditions(c ...xpv1.Condition) {
 	p.Status.SetConditions(c...)
 }
 
+// GetConditionedStatus of this Provider. Conditions set on the returned status
+// are set on the Provider, allowing several conditions to be set before the
+// Provider's status is updated once.
+func (p *Provider) GetConditionedStatus() *xpv1.ConditionedStatus {
+	return &p.Status.ConditionedStatus
+}
+
 // GetSource of this Provider.
 func (p *Provider) GetSource() string {
 	return p.Spec.Package
@@ -128,6 +386,17 @@ func (p *Provider) SetSource(s string) {
 	p.Spec.Package = s
 }
 
+// GetEffectiveSource of this Provider, rewritten according to the supplied map of
+// source prefixes to their mirrors.
+func (p *Provider) GetEffectiveSource(mirrors map[string]string) string {
+	return EffectiveSource(p.GetSource(), mirrors)
+}
+
+// GetPackageType of this Provider.
+func (p *Provider) GetPackageType() PackageType {
+	return ProviderPackageType
+}
+
 // GetActivationPolicy of this Provider.
 func (p *Provider) GetActivationPolicy() *RevisionActivationPolicy {
 	return p.Spec.RevisionActivationPolicy
@@ -168,6 +437,36 @@ func (p *Provider) SetRevisionHistoryLimit(l *int64) {
 	p.Spec.RevisionHistoryLimit = l
 }
 
+// GetRevisionTTL of this Provider.
+func (p *Provider) GetRevisionTTL() *metav1.Duration {
+	return p.Spec.RevisionTTL
+}
+
+// SetRevisionTTL of this Provider.
+func (p *Provider) SetRevisionTTL(d *metav1.Duration) {
+	p.Spec.RevisionTTL = d
+}
+
+// GetRevisionUpdatePolicy of this Provider.
+func (p *Provider) GetRevisionUpdatePolicy() *RevisionUpdatePolicy {
+	return p.Spec.RevisionUpdatePolicy
+}
+
+// SetRevisionUpdatePolicy of this Provider.
+func (p *Provider) SetRevisionUpdatePolicy(u *RevisionUpdatePolicy) {
+	p.Spec.RevisionUpdatePolicy = u
+}
+
+// GetUpdatePolicy of this Provider.
+func (p *Provider) GetUpdatePolicy() *UpdatePolicy {
+	return p.Spec.UpdatePolicy
+}
+
+// SetUpdatePolicy of this Provider.
+func (p *Provider) SetUpdatePolicy(u *UpdatePolicy) {
+	p.Spec.UpdatePolicy = u
+}
+
 // GetIgnoreCrossplaneConstraints of this Provider.
 func (p *Provider) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -188,6 +487,137 @@ func (p *Provider) SetControllerConfigRef(r *ControllerConfigReference) {
 	p.Spec.ControllerConfigReference = r
 }
 
+// GetMaxUnavailable of this Provider.
+func (p *Provider) GetMaxUnavailable() *intstr.IntOrString {
+	return p.Spec.MaxUnavailable
+}
+
+// SetMaxUnavailable of this Provider.
+func (p *Provider) SetMaxUnavailable(m *intstr.IntOrString) {
+	p.Spec.MaxUnavailable = m
+}
+
+// GetHealthCheck of this Provider.
+func (p *Provider) GetHealthCheck() *HealthCheck {
+	return p.Spec.HealthCheck
+}
+
+// SetHealthCheck of this Provider.
+func (p *Provider) SetHealthCheck(hc *HealthCheck) {
+	p.Spec.HealthCheck = hc
+}
+
+// GetPriorityClassName of this Provider.
+func (p *Provider) GetPriorityClassName() *string {
+	return p.Spec.PriorityClassName
+}
+
+// SetPriorityClassName of this Provider.
+func (p *Provider) SetPriorityClassName(n *string) {
+	p.Spec.PriorityClassName = n
+}
+
+// GetPodTemplateAnnotations of this Provider.
+func (p *Provider) GetPodTemplateAnnotations() map[string]string {
+	return p.Spec.PodTemplateAnnotations
+}
+
+// SetPodTemplateAnnotations of this Provider.
+func (p *Provider) SetPodTemplateAnnotations(a map[string]string) {
+	p.Spec.PodTemplateAnnotations = a
+}
+
+// GetTolerations of this Provider.
+func (p *Provider) GetTolerations() []corev1.Toleration {
+	return p.Spec.Tolerations
+}
+
+// SetTolerations of this Provider.
+func (p *Provider) SetTolerations(t []corev1.Toleration) {
+	p.Spec.Tolerations = t
+}
+
+// GetNodeSelector of this Provider.
+func (p *Provider) GetNodeSelector() map[string]string {
+	return p.Spec.NodeSelector
+}
+
+// SetNodeSelector of this Provider.
+func (p *Provider) SetNodeSelector(s map[string]string) {
+	p.Spec.NodeSelector = s
+}
+
+// GetProviderRevisionAffinity returns the affinity of this Provider's pods,
+// which is propagated to its revisions.
+func (p *Provider) GetProviderRevisionAffinity() *corev1.Affinity {
+	return p.Spec.Affinity
+}
+
+// SetProviderRevisionAffinity sets the affinity of this Provider's pods.
+func (p *Provider) SetProviderRevisionAffinity(a *corev1.Affinity) {
+	p.Spec.Affinity = a
+}
+
+// GetReadinessGates of this Provider.
+func (p *Provider) GetReadinessGates() []corev1.PodReadinessGate {
+	return p.Spec.ReadinessGates
+}
+
+// SetReadinessGates of this Provider.
+func (p *Provider) SetReadinessGates(g []corev1.PodReadinessGate) {
+	p.Spec.ReadinessGates = g
+}
+
+// GetMinReadySeconds of this Provider.
+func (p *Provider) GetMinReadySeconds() *int32 {
+	return p.Spec.MinReadySeconds
+}
+
+// SetMinReadySeconds of this Provider.
+func (p *Provider) SetMinReadySeconds(s *int32) {
+	p.Spec.MinReadySeconds = s
+}
+
+// GetReplicaCount of this Provider.
+func (p *Provider) GetReplicaCount() *int32 {
+	return p.Spec.Replicas
+}
+
+// SetReplicaCount of this Provider.
+func (p *Provider) SetReplicaCount(n *int32) {
+	p.Spec.Replicas = n
+}
+
+// GetLeaderElection of this Provider.
+func (p *Provider) GetLeaderElection() *bool {
+	return p.Spec.LeaderElection
+}
+
+// SetLeaderElection of this Provider.
+func (p *Provider) SetLeaderElection(b *bool) {
+	p.Spec.LeaderElection = b
+}
+
+// GetExtraVolumes of this Provider.
+func (p *Provider) GetExtraVolumes() []corev1.Volume {
+	return p.Spec.ExtraVolumes
+}
+
+// SetExtraVolumes of this Provider.
+func (p *Provider) SetExtraVolumes(v []corev1.Volume) {
+	p.Spec.ExtraVolumes = v
+}
+
+// GetExtraVolumeMounts of this Provider.
+func (p *Provider) GetExtraVolumeMounts() []corev1.VolumeMount {
+	return p.Spec.ExtraVolumeMounts
+}
+
+// SetExtraVolumeMounts of this Provider.
+func (p *Provider) SetExtraVolumeMounts(vm []corev1.VolumeMount) {
+	p.Spec.ExtraVolumeMounts = vm
+}
+
 // GetCurrentRevision of this Provider.
 func (p *Provider) GetCurrentRevision() string {
 	return p.Status.CurrentRevision
@@ -198,6 +628,19 @@ func (p *Provider) SetCurrentRevision(s string) {
 	p.Status.CurrentRevision = s
 }
 
+// GetCurrentRevisionRef returns a reference to the current ProviderRevision of
+// this Provider, or nil if it has no current revision.
+func (p *Provider) GetCurrentRevisionRef() *corev1.ObjectReference {
+	if p.Status.CurrentRevision == "" {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: ProviderRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       ProviderRevisionKind,
+		Name:       p.Status.CurrentRevision,
+	}
+}
+
 // GetSkipDependencyResolution of this Provider.
 func (p *Provider) GetSkipDependencyResolution() *bool {
 	return p.Spec.SkipDependencyResolution
@@ -208,6 +651,16 @@ func (p *Provider) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetPreventDowngrade of this Provider.
+func (p *Provider) GetPreventDowngrade() *bool {
+	return p.Spec.PreventDowngrade
+}
+
+// SetPreventDowngrade of this Provider.
+func (p *Provider) SetPreventDowngrade(b *bool) {
+	p.Spec.PreventDowngrade = b
+}
+
 // GetCurrentIdentifier of this Provider.
 func (p *Provider) GetCurrentIdentifier() string {
 	return p.Status.CurrentIdentifier
@@ -218,6 +671,16 @@ func (p *Provider) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetObjectSelector of this Provider.
+func (p *Provider) GetObjectSelector() *ObjectSelector {
+	return p.Spec.ObjectSelector
+}
+
+// SetObjectSelector of this Provider.
+func (p *Provider) SetObjectSelector(s *ObjectSelector) {
+	p.Spec.ObjectSelector = s
+}
+
 // GetCommonLabels of this Provider.
 func (p *Provider) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -228,6 +691,91 @@ func (p *Provider) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetInstallTimeout of this Provider.
+func (p *Provider) GetInstallTimeout() *metav1.Duration {
+	return p.Spec.InstallTimeout
+}
+
+// SetInstallTimeout of this Provider.
+func (p *Provider) SetInstallTimeout(d *metav1.Duration) {
+	p.Spec.InstallTimeout = d
+}
+
+// GetReconcileInterval of this Provider.
+func (p *Provider) GetReconcileInterval() *metav1.Duration {
+	return p.Spec.ReconcileInterval
+}
+
+// SetReconcileInterval of this Provider.
+func (p *Provider) SetReconcileInterval(d *metav1.Duration) {
+	p.Spec.ReconcileInterval = d
+}
+
+// GetLifecycleHooks of this Provider.
+func (p *Provider) GetLifecycleHooks() *LifecycleHooks {
+	return p.Spec.LifecycleHooks
+}
+
+// SetLifecycleHooks of this Provider.
+func (p *Provider) SetLifecycleHooks(h *LifecycleHooks) {
+	p.Spec.LifecycleHooks = h
+}
+
+// GetInstallAttempt of this Provider.
+func (p *Provider) GetInstallAttempt() *InstallAttempt {
+	return p.Status.InstallAttempt
+}
+
+// SetInstallAttempt of this Provider.
+func (p *Provider) SetInstallAttempt(a *InstallAttempt) {
+	p.Status.InstallAttempt = a
+}
+
+// GetLastRevision of this Provider.
+func (p *Provider) GetLastRevision() string {
+	return p.Status.LastRevision
+}
+
+// SetLastRevision of this Provider.
+func (p *Provider) SetLastRevision(r string) {
+	p.Status.LastRevision = r
+}
+
+// GetDependencyStatus of this Provider.
+func (p *Provider) GetDependencyStatus() *DependencyStatus {
+	return p.Status.DependencyStatus
+}
+
+// SetDependencyStatus of this Provider.
+func (p *Provider) SetDependencyStatus(d *DependencyStatus) {
+	p.Status.DependencyStatus = d
+}
+
+// GetStatusSummary returns a snapshot of the health of this Provider.
+func (p *Provider) GetStatusSummary() PackageStatusSummary {
+	return summarize(p)
+}
+
+// GetProviderFamilyRef of this Provider.
+func (p *Provider) GetProviderFamilyRef() *corev1.LocalObjectReference {
+	return p.Status.ProviderFamilyRef
+}
+
+// SetProviderFamilyRef of this Provider.
+func (p *Provider) SetProviderFamilyRef(r *corev1.LocalObjectReference) {
+	p.Status.ProviderFamilyRef = r
+}
+
+// GetDefaultCompositeResourceRef of this Provider. Providers don't install
+// CompositeResourceDefinitions, so this always returns nil.
+func (p *Provider) GetDefaultCompositeResourceRef() *xpv1.TypedReference {
+	return nil
+}
+
+// SetDefaultCompositeResourceRef of this Provider. Providers don't install
+// CompositeResourceDefinitions, so this does nothing.
+func (p *Provider) SetDefaultCompositeResourceRef(_ *xpv1.TypedReference) {}
+
 // GetCondition of this Configuration.
 func (p *Configuration) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	return p.Status.GetCondition(ct)
@@ -238,6 +786,13 @@ func (p *Configuration) SetConditions(c ...xpv1.Condition) {
 	p.Status.SetConditions(c...)
 }
 
+// GetConditionedStatus of this Configuration. Conditions set on the returned status
+// are set on the Configuration, allowing several conditions to be set before the
+// Configuration's status is updated once.
+func (p *Configuration) GetConditionedStatus() *xpv1.ConditionedStatus {
+	return &p.Status.ConditionedStatus
+}
+
 // GetSource of this Configuration.
 func (p *Configuration) GetSource() string {
 	return p.Spec.Package
@@ -248,6 +803,17 @@ func (p *Configuration) SetSource(s string) {
 	p.Spec.Package = s
 }
 
+// GetEffectiveSource of this Configuration, rewritten according to the supplied map of
+// source prefixes to their mirrors.
+func (p *Configuration) GetEffectiveSource(mirrors map[string]string) string {
+	return EffectiveSource(p.GetSource(), mirrors)
+}
+
+// GetPackageType of this Configuration.
+func (p *Configuration) GetPackageType() PackageType {
+	return ConfigurationPackageType
+}
+
 // GetActivationPolicy of this Configuration.
 func (p *Configuration) GetActivationPolicy() *RevisionActivationPolicy {
 	return p.Spec.RevisionActivationPolicy
@@ -288,6 +854,36 @@ func (p *Configuration) SetRevisionHistoryLimit(l *int64) {
 	p.Spec.RevisionHistoryLimit = l
 }
 
+// GetRevisionTTL of this Configuration.
+func (p *Configuration) GetRevisionTTL() *metav1.Duration {
+	return p.Spec.RevisionTTL
+}
+
+// SetRevisionTTL of this Configuration.
+func (p *Configuration) SetRevisionTTL(d *metav1.Duration) {
+	p.Spec.RevisionTTL = d
+}
+
+// GetRevisionUpdatePolicy of this Configuration.
+func (p *Configuration) GetRevisionUpdatePolicy() *RevisionUpdatePolicy {
+	return p.Spec.RevisionUpdatePolicy
+}
+
+// SetRevisionUpdatePolicy of this Configuration.
+func (p *Configuration) SetRevisionUpdatePolicy(u *RevisionUpdatePolicy) {
+	p.Spec.RevisionUpdatePolicy = u
+}
+
+// GetUpdatePolicy of this Configuration.
+func (p *Configuration) GetUpdatePolicy() *UpdatePolicy {
+	return p.Spec.UpdatePolicy
+}
+
+// SetUpdatePolicy of this Configuration.
+func (p *Configuration) SetUpdatePolicy(u *UpdatePolicy) {
+	p.Spec.UpdatePolicy = u
+}
+
 // GetIgnoreCrossplaneConstraints of this Configuration.
 func (p *Configuration) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -298,14 +894,152 @@ func (p *Configuration) SetIgnoreCrossplaneConstraints(b *bool) {
 	p.Spec.IgnoreCrossplaneConstraints = b
 }
 
-// GetControllerConfigRef of this Configuration.
+// GetControllerConfigRef of this Configuration. Configurations don't support
+// controller configs, so this always returns nil.
+//
+// Deprecated: Configurations have no controller config. Use the controller
+// config reference of a ConfigurationRevision instead.
 func (p *Configuration) GetControllerConfigRef() *ControllerConfigReference {
 	return nil
 }
 
-// SetControllerConfigRef of this Configuration.
+// SetControllerConfigRef of this Configuration. Configurations don't support
+// controller configs, so this does nothing.
+//
+// Deprecated: Configurations have no controller config. Use the controller
+// config reference of a ConfigurationRevision instead.
 func (p *Configuration) SetControllerConfigRef(_ *ControllerConfigReference) {}
 
+// GetMaxUnavailable of this Configuration. Configurations don't have a
+// Deployment to roll out, so this always returns nil.
+func (p *Configuration) GetMaxUnavailable() *intstr.IntOrString {
+	return nil
+}
+
+// SetMaxUnavailable of this Configuration. Configurations don't have a
+// Deployment to roll out, so this does nothing.
+func (p *Configuration) SetMaxUnavailable(_ *intstr.IntOrString) {}
+
+// GetHealthCheck of this Configuration. Configurations don't have a
+// Deployment to probe, so this always returns nil.
+func (p *Configuration) GetHealthCheck() *HealthCheck {
+	return nil
+}
+
+// SetHealthCheck of this Configuration. Configurations don't have a
+// Deployment to probe, so this does nothing.
+func (p *Configuration) SetHealthCheck(_ *HealthCheck) {}
+
+// GetPriorityClassName of this Configuration. Configurations don't have a
+// Deployment to schedule, so this always returns nil.
+func (p *Configuration) GetPriorityClassName() *string {
+	return nil
+}
+
+// SetPriorityClassName of this Configuration. Configurations don't have a
+// Deployment to schedule, so this does nothing.
+func (p *Configuration) SetPriorityClassName(_ *string) {}
+
+// GetPodTemplateAnnotations of this Configuration. Configurations don't have a
+// Deployment to annotate, so this always returns nil.
+func (p *Configuration) GetPodTemplateAnnotations() map[string]string {
+	return nil
+}
+
+// SetPodTemplateAnnotations of this Configuration. Configurations don't have a
+// Deployment to annotate, so this does nothing.
+func (p *Configuration) SetPodTemplateAnnotations(_ map[string]string) {}
+
+// GetTolerations of this Configuration. Configurations don't have a Deployment
+// to schedule, so this always returns nil.
+func (p *Configuration) GetTolerations() []corev1.Toleration {
+	return nil
+}
+
+// SetTolerations of this Configuration. Configurations don't have a Deployment
+// to schedule, so this does nothing.
+func (p *Configuration) SetTolerations(_ []corev1.Toleration) {}
+
+// GetNodeSelector of this Configuration. Configurations don't have a
+// Deployment to schedule, so this always returns nil.
+func (p *Configuration) GetNodeSelector() map[string]string {
+	return nil
+}
+
+// SetNodeSelector of this Configuration. Configurations don't have a
+// Deployment to schedule, so this does nothing.
+func (p *Configuration) SetNodeSelector(_ map[string]string) {}
+
+// GetProviderRevisionAffinity of this Configuration. Configurations don't
+// have a Deployment to schedule, so this always returns nil.
+func (p *Configuration) GetProviderRevisionAffinity() *corev1.Affinity {
+	return nil
+}
+
+// SetProviderRevisionAffinity of this Configuration. Configurations don't
+// have a Deployment to schedule, so this does nothing.
+func (p *Configuration) SetProviderRevisionAffinity(_ *corev1.Affinity) {}
+
+// GetReadinessGates of this Configuration. Configurations don't have a
+// Deployment, so this always returns nil.
+func (p *Configuration) GetReadinessGates() []corev1.PodReadinessGate {
+	return nil
+}
+
+// SetReadinessGates of this Configuration. Configurations don't have a
+// Deployment, so this does nothing.
+func (p *Configuration) SetReadinessGates(_ []corev1.PodReadinessGate) {}
+
+// GetMinReadySeconds of this Configuration. Configurations don't have a
+// Deployment, so this always returns nil.
+func (p *Configuration) GetMinReadySeconds() *int32 {
+	return nil
+}
+
+// SetMinReadySeconds of this Configuration. Configurations don't have a
+// Deployment, so this does nothing.
+func (p *Configuration) SetMinReadySeconds(_ *int32) {}
+
+// GetReplicaCount of this Configuration. Configurations don't have a
+// Deployment, so this always returns nil.
+func (p *Configuration) GetReplicaCount() *int32 {
+	return nil
+}
+
+// SetReplicaCount of this Configuration. Configurations don't have a
+// Deployment, so this does nothing.
+func (p *Configuration) SetReplicaCount(_ *int32) {}
+
+// GetLeaderElection of this Configuration. Configurations don't have a
+// controller, so this always returns nil.
+func (p *Configuration) GetLeaderElection() *bool {
+	return nil
+}
+
+// SetLeaderElection of this Configuration. Configurations don't have a
+// controller, so this does nothing.
+func (p *Configuration) SetLeaderElection(_ *bool) {}
+
+// GetExtraVolumes of this Configuration. Configurations don't have a
+// Deployment, so this always returns nil.
+func (p *Configuration) GetExtraVolumes() []corev1.Volume {
+	return nil
+}
+
+// SetExtraVolumes of this Configuration. Configurations don't have a
+// Deployment, so this does nothing.
+func (p *Configuration) SetExtraVolumes(_ []corev1.Volume) {}
+
+// GetExtraVolumeMounts of this Configuration. Configurations don't have a
+// Deployment, so this always returns nil.
+func (p *Configuration) GetExtraVolumeMounts() []corev1.VolumeMount {
+	return nil
+}
+
+// SetExtraVolumeMounts of this Configuration. Configurations don't have a
+// Deployment, so this does nothing.
+func (p *Configuration) SetExtraVolumeMounts(_ []corev1.VolumeMount) {}
+
 // GetCurrentRevision of this Configuration.
 func (p *Configuration) GetCurrentRevision() string {
 	return p.Status.CurrentRevision
@@ -316,6 +1050,19 @@ func (p *Configuration) SetCurrentRevision(s string) {
 	p.Status.CurrentRevision = s
 }
 
+// GetCurrentRevisionRef returns a reference to the current ConfigurationRevision of
+// this Configuration, or nil if it has no current revision.
+func (p *Configuration) GetCurrentRevisionRef() *corev1.ObjectReference {
+	if p.Status.CurrentRevision == "" {
+		return nil
+	}
+	return &corev1.ObjectReference{
+		APIVersion: ConfigurationRevisionGroupVersionKind.GroupVersion().String(),
+		Kind:       ConfigurationRevisionKind,
+		Name:       p.Status.CurrentRevision,
+	}
+}
+
 // GetSkipDependencyResolution of this Configuration.
 func (p *Configuration) GetSkipDependencyResolution() *bool {
 	return p.Spec.SkipDependencyResolution
@@ -326,6 +1073,16 @@ func (p *Configuration) SetSkipDependencyResolution(b *bool) {
 	p.Spec.SkipDependencyResolution = b
 }
 
+// GetPreventDowngrade of this Configuration.
+func (p *Configuration) GetPreventDowngrade() *bool {
+	return p.Spec.PreventDowngrade
+}
+
+// SetPreventDowngrade of this Configuration.
+func (p *Configuration) SetPreventDowngrade(b *bool) {
+	p.Spec.PreventDowngrade = b
+}
+
 // GetCurrentIdentifier of this Configuration.
 func (p *Configuration) GetCurrentIdentifier() string {
 	return p.Status.CurrentIdentifier
@@ -336,6 +1093,16 @@ func (p *Configuration) SetCurrentIdentifier(s string) {
 	p.Status.CurrentIdentifier = s
 }
 
+// GetObjectSelector of this Configuration.
+func (p *Configuration) GetObjectSelector() *ObjectSelector {
+	return p.Spec.ObjectSelector
+}
+
+// SetObjectSelector of this Configuration.
+func (p *Configuration) SetObjectSelector(s *ObjectSelector) {
+	p.Spec.ObjectSelector = s
+}
+
 // GetCommonLabels of this Configuration.
 func (p *Configuration) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -346,6 +1113,91 @@ func (p *Configuration) SetCommonLabels(l map[string]string) {
 	p.Spec.CommonLabels = l
 }
 
+// GetInstallTimeout of this Configuration.
+func (p *Configuration) GetInstallTimeout() *metav1.Duration {
+	return p.Spec.InstallTimeout
+}
+
+// SetInstallTimeout of this Configuration.
+func (p *Configuration) SetInstallTimeout(d *metav1.Duration) {
+	p.Spec.InstallTimeout = d
+}
+
+// GetReconcileInterval of this Configuration.
+func (p *Configuration) GetReconcileInterval() *metav1.Duration {
+	return p.Spec.ReconcileInterval
+}
+
+// SetReconcileInterval of this Configuration.
+func (p *Configuration) SetReconcileInterval(d *metav1.Duration) {
+	p.Spec.ReconcileInterval = d
+}
+
+// GetLifecycleHooks of this Configuration.
+func (p *Configuration) GetLifecycleHooks() *LifecycleHooks {
+	return p.Spec.LifecycleHooks
+}
+
+// SetLifecycleHooks of this Configuration.
+func (p *Configuration) SetLifecycleHooks(h *LifecycleHooks) {
+	p.Spec.LifecycleHooks = h
+}
+
+// GetInstallAttempt of this Configuration.
+func (p *Configuration) GetInstallAttempt() *InstallAttempt {
+	return p.Status.InstallAttempt
+}
+
+// SetInstallAttempt of this Configuration.
+func (p *Configuration) SetInstallAttempt(a *InstallAttempt) {
+	p.Status.InstallAttempt = a
+}
+
+// GetLastRevision of this Configuration.
+func (p *Configuration) GetLastRevision() string {
+	return p.Status.LastRevision
+}
+
+// SetLastRevision of this Configuration.
+func (p *Configuration) SetLastRevision(r string) {
+	p.Status.LastRevision = r
+}
+
+// GetDependencyStatus of this Configuration.
+func (p *Configuration) GetDependencyStatus() *DependencyStatus {
+	return p.Status.DependencyStatus
+}
+
+// SetDependencyStatus of this Configuration.
+func (p *Configuration) SetDependencyStatus(d *DependencyStatus) {
+	p.Status.DependencyStatus = d
+}
+
+// GetStatusSummary returns a snapshot of the health of this Configuration.
+func (p *Configuration) GetStatusSummary() PackageStatusSummary {
+	return summarize(p)
+}
+
+// GetProviderFamilyRef of this Configuration. Configurations don't belong to
+// provider families, so this always returns nil.
+func (p *Configuration) GetProviderFamilyRef() *corev1.LocalObjectReference {
+	return nil
+}
+
+// SetProviderFamilyRef of this Configuration. Configurations don't belong to
+// provider families, so this does nothing.
+func (p *Configuration) SetProviderFamilyRef(_ *corev1.LocalObjectReference) {}
+
+// GetDefaultCompositeResourceRef of this Configuration.
+func (p *Configuration) GetDefaultCompositeResourceRef() *xpv1.TypedReference {
+	return p.Status.DefaultCompositeResourceRef
+}
+
+// SetDefaultCompositeResourceRef of this Configuration.
+func (p *Configuration) SetDefaultCompositeResourceRef(r *xpv1.TypedReference) {
+	p.Status.DefaultCompositeResourceRef = r
+}
+
 var _ PackageRevision = &ProviderRevision{}
 var _ PackageRevision = &ConfigurationRevision{}
 
@@ -357,6 +1209,7 @@ type PackageRevision interface {
 
 	GetObjects() []xpv1.TypedReference
 	SetObjects(c []xpv1.TypedReference)
+	GetInstalledCRDNames() []string
 
 	GetControllerReference() ControllerReference
 	SetControllerReference(c ControllerReference)
@@ -382,12 +1235,45 @@ type PackageRevision interface {
 	GetRevision() int64
 	SetRevision(r int64)
 
+	GetExpiresAt() *metav1.Time
+	SetExpiresAt(t *metav1.Time)
+
+	GetObservedGeneration() int64
+	SetObservedGeneration(g int64)
+
 	GetSkipDependencyResolution() *bool
 	SetSkipDependencyResolution(*bool)
 
 	GetDependencyStatus() (found, installed, invalid int64)
 	SetDependencyStatus(found, installed, invalid int64)
 
+	GetResolvedDependencies() []ResolvedDependency
+	SetResolvedDependencies(d []ResolvedDependency)
+
+	GetLastPullTime() *metav1.Time
+	SetLastPullTime(t *metav1.Time)
+
+	GetCrossplaneVersionConstraint() string
+	SetCrossplaneVersionConstraint(c string)
+
+	GetInstalledVersion() string
+	SetInstalledVersion(v string)
+
+	GetReconciliationFailureCount() int64
+	SetReconciliationFailureCount(n int64)
+
+	GetParseError() *PackageParseError
+	SetParseError(e *PackageParseError)
+
+	GetPackageMeta() *PackageMeta
+	SetPackageMeta(m *PackageMeta)
+
+	GetConditionHistory() []xpv1.Condition
+	SetConditionHistory(h []xpv1.Condition)
+
+	GetRequiredPermissions() []rbacv1.PolicyRule
+	SetRequiredPermissions(r []rbacv1.PolicyRule)
+
 	// These methods will be removed once we start to consume certificates generated per entities
 	GetWebhookTLSSecretName() *string
 	SetWebhookTLSSecretName(n *string)
@@ -395,15 +1281,66 @@ type PackageRevision interface {
 	GetCommonLabels() map[string]string
 	SetCommonLabels(l map[string]string)
 
-	// These methods will be removed once we start to consume certificates generated per entities
-	GetESSTLSSecretName() *string
-	SetESSTLSSecretName(s *string)
+	GetObjectSelector() *ObjectSelector
+	SetObjectSelector(s *ObjectSelector)
+
+	// These methods will be removed once we start to consume certificates generated per entities
+	GetESSTLSSecretName() *string
+	SetESSTLSSecretName(s *string)
+
+	GetTLSServerSecretName() *string
+	SetTLSServerSecretName(n *string)
+
+	GetTLSClientSecretName() *string
+	SetTLSClientSecretName(n *string)
+
+	GetVerificationCertRef() *corev1.SecretReference
+	SetVerificationCertRef(r *corev1.SecretReference)
+
+	GetMaxUnavailable() *intstr.IntOrString
+	SetMaxUnavailable(m *intstr.IntOrString)
+
+	GetHealthCheck() *HealthCheck
+	SetHealthCheck(hc *HealthCheck)
+
+	GetPriorityClassName() *string
+	SetPriorityClassName(n *string)
+
+	GetPodTemplateAnnotations() map[string]string
+	SetPodTemplateAnnotations(a map[string]string)
+
+	GetTolerations() []corev1.Toleration
+	SetTolerations(t []corev1.Toleration)
+
+	GetNodeSelector() map[string]string
+	SetNodeSelector(s map[string]string)
+
+	GetAffinity() *corev1.Affinity
+	SetAffinity(a *corev1.Affinity)
+
+	GetReadinessGates() []corev1.PodReadinessGate
+	SetReadinessGates(g []corev1.PodReadinessGate)
+
+	GetMinReadySeconds() *int32
+	SetMinReadySeconds(s *int32)
+
+	GetReplicaCount() *int32
+	SetReplicaCount(n *int32)
+
+	GetLeaderElection() *bool
+	SetLeaderElection(b *bool)
+
+	GetExtraVolumes() []corev1.Volume
+	SetExtraVolumes(v []corev1.Volume)
+
+	GetExtraVolumeMounts() []corev1.VolumeMount
+	SetExtraVolumeMounts(vm []corev1.VolumeMount)
 
-	GetTLSServerSecretName() *string
-	SetTLSServerSecretName(n *string)
+	GetAdmissionWebhookFailurePolicy() *admv1.FailurePolicyType
+	SetAdmissionWebhookFailurePolicy(fp *admv1.FailurePolicyType)
 
-	GetTLSClientSecretName() *string
-	SetTLSClientSecretName(n *string)
+	GetPodDisruptionBudget() *policyv1.PodDisruptionBudgetSpec
+	SetPodDisruptionBudget(pdb *policyv1.PodDisruptionBudgetSpec)
 }
 
 // GetCondition of this ProviderRevision.
@@ -426,6 +1363,12 @@ func (p *ProviderRevision) SetObjects(c []xpv1.TypedReference) {
 	p.Status.ObjectRefs = c
 }
 
+// GetInstalledCRDNames returns the names of the CustomResourceDefinitions
+// installed by this ProviderRevision.
+func (p *ProviderRevision) GetInstalledCRDNames() []string {
+	return CRDNames(p.Status.ObjectRefs)
+}
+
 // GetControllerReference of this ProviderRevision.
 func (p *ProviderRevision) GetControllerReference() ControllerReference {
 	return p.Status.ControllerRef
@@ -486,6 +1429,26 @@ func (p *ProviderRevision) SetRevision(r int64) {
 	p.Spec.Revision = r
 }
 
+// GetExpiresAt of this ProviderRevision.
+func (p *ProviderRevision) GetExpiresAt() *metav1.Time {
+	return p.Spec.ExpiresAt
+}
+
+// SetExpiresAt of this ProviderRevision.
+func (p *ProviderRevision) SetExpiresAt(t *metav1.Time) {
+	p.Spec.ExpiresAt = t
+}
+
+// GetObservedGeneration of this ProviderRevision.
+func (p *ProviderRevision) GetObservedGeneration() int64 {
+	return p.Status.ObservedGeneration
+}
+
+// SetObservedGeneration of this ProviderRevision.
+func (p *ProviderRevision) SetObservedGeneration(g int64) {
+	p.Status.ObservedGeneration = g
+}
+
 // GetDependencyStatus of this ProviderRevision.
 func (p *ProviderRevision) GetDependencyStatus() (found, installed, invalid int64) {
 	return p.Status.FoundDependencies, p.Status.InstalledDependencies, p.Status.InvalidDependencies
@@ -498,6 +1461,96 @@ func (p *ProviderRevision) SetDependencyStatus(found, installed, invalid int64)
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetResolvedDependencies of this ProviderRevision.
+func (p *ProviderRevision) GetResolvedDependencies() []ResolvedDependency {
+	return p.Status.ResolvedDependencies
+}
+
+// SetResolvedDependencies of this ProviderRevision.
+func (p *ProviderRevision) SetResolvedDependencies(d []ResolvedDependency) {
+	p.Status.ResolvedDependencies = d
+}
+
+// GetLastPullTime of this ProviderRevision.
+func (p *ProviderRevision) GetLastPullTime() *metav1.Time {
+	return p.Status.LastPullTime
+}
+
+// SetLastPullTime of this ProviderRevision.
+func (p *ProviderRevision) SetLastPullTime(t *metav1.Time) {
+	p.Status.LastPullTime = t
+}
+
+// GetCrossplaneVersionConstraint of this ProviderRevision.
+func (p *ProviderRevision) GetCrossplaneVersionConstraint() string {
+	return p.Status.CrossplaneVersionConstraint
+}
+
+// SetCrossplaneVersionConstraint of this ProviderRevision.
+func (p *ProviderRevision) SetCrossplaneVersionConstraint(c string) {
+	p.Status.CrossplaneVersionConstraint = c
+}
+
+// GetPackageMeta of this ProviderRevision.
+func (p *ProviderRevision) GetPackageMeta() *PackageMeta {
+	return p.Status.PackageMeta
+}
+
+// SetPackageMeta of this ProviderRevision.
+func (p *ProviderRevision) SetPackageMeta(m *PackageMeta) {
+	p.Status.PackageMeta = m
+}
+
+// GetConditionHistory of this ProviderRevision.
+func (p *ProviderRevision) GetConditionHistory() []xpv1.Condition {
+	return p.Status.ConditionHistory
+}
+
+// SetConditionHistory of this ProviderRevision.
+func (p *ProviderRevision) SetConditionHistory(h []xpv1.Condition) {
+	p.Status.ConditionHistory = h
+}
+
+// GetInstalledVersion of this ProviderRevision.
+func (p *ProviderRevision) GetInstalledVersion() string {
+	return p.Status.InstalledVersion
+}
+
+// SetInstalledVersion of this ProviderRevision.
+func (p *ProviderRevision) SetInstalledVersion(v string) {
+	p.Status.InstalledVersion = v
+}
+
+// GetReconciliationFailureCount of this ProviderRevision.
+func (p *ProviderRevision) GetReconciliationFailureCount() int64 {
+	return p.Status.ReconciliationFailureCount
+}
+
+// SetReconciliationFailureCount of this ProviderRevision.
+func (p *ProviderRevision) SetReconciliationFailureCount(n int64) {
+	p.Status.ReconciliationFailureCount = n
+}
+
+// GetParseError of this ProviderRevision.
+func (p *ProviderRevision) GetParseError() *PackageParseError {
+	return p.Status.ParseError
+}
+
+// SetParseError of this ProviderRevision.
+func (p *ProviderRevision) SetParseError(e *PackageParseError) {
+	p.Status.ParseError = e
+}
+
+// GetRequiredPermissions of this ProviderRevision.
+func (p *ProviderRevision) GetRequiredPermissions() []rbacv1.PolicyRule {
+	return p.Status.RequiredPermissions
+}
+
+// SetRequiredPermissions of this ProviderRevision.
+func (p *ProviderRevision) SetRequiredPermissions(r []rbacv1.PolicyRule) {
+	p.Status.RequiredPermissions = r
+}
+
 // GetIgnoreCrossplaneConstraints of this ProviderRevision.
 func (p *ProviderRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -563,11 +1616,181 @@ func (p *ProviderRevision) SetTLSClientSecretName(s *string) {
 	p.Spec.TLSClientSecretName = s
 }
 
+// GetVerificationCertRef of this ProviderRevision.
+func (p *ProviderRevision) GetVerificationCertRef() *corev1.SecretReference {
+	return p.Spec.VerificationCertRef
+}
+
+// SetVerificationCertRef of this ProviderRevision.
+func (p *ProviderRevision) SetVerificationCertRef(r *corev1.SecretReference) {
+	p.Spec.VerificationCertRef = r
+}
+
+// GetMaxUnavailable of this ProviderRevision.
+func (p *ProviderRevision) GetMaxUnavailable() *intstr.IntOrString {
+	return p.Spec.MaxUnavailable
+}
+
+// SetMaxUnavailable of this ProviderRevision.
+func (p *ProviderRevision) SetMaxUnavailable(m *intstr.IntOrString) {
+	p.Spec.MaxUnavailable = m
+}
+
+// GetHealthCheck of this ProviderRevision.
+func (p *ProviderRevision) GetHealthCheck() *HealthCheck {
+	return p.Spec.HealthCheck
+}
+
+// SetHealthCheck of this ProviderRevision.
+func (p *ProviderRevision) SetHealthCheck(hc *HealthCheck) {
+	p.Spec.HealthCheck = hc
+}
+
+// GetPriorityClassName of this ProviderRevision.
+func (p *ProviderRevision) GetPriorityClassName() *string {
+	return p.Spec.PriorityClassName
+}
+
+// SetPriorityClassName of this ProviderRevision.
+func (p *ProviderRevision) SetPriorityClassName(n *string) {
+	p.Spec.PriorityClassName = n
+}
+
+// GetPodTemplateAnnotations of this ProviderRevision.
+func (p *ProviderRevision) GetPodTemplateAnnotations() map[string]string {
+	return p.Spec.PodTemplateAnnotations
+}
+
+// SetPodTemplateAnnotations of this ProviderRevision.
+func (p *ProviderRevision) SetPodTemplateAnnotations(a map[string]string) {
+	p.Spec.PodTemplateAnnotations = a
+}
+
+// GetTolerations of this ProviderRevision.
+func (p *ProviderRevision) GetTolerations() []corev1.Toleration {
+	return p.Spec.Tolerations
+}
+
+// SetTolerations of this ProviderRevision.
+func (p *ProviderRevision) SetTolerations(t []corev1.Toleration) {
+	p.Spec.Tolerations = t
+}
+
+// GetNodeSelector of this ProviderRevision.
+func (p *ProviderRevision) GetNodeSelector() map[string]string {
+	return p.Spec.NodeSelector
+}
+
+// SetNodeSelector of this ProviderRevision.
+func (p *ProviderRevision) SetNodeSelector(s map[string]string) {
+	p.Spec.NodeSelector = s
+}
+
+// GetAffinity of this ProviderRevision.
+func (p *ProviderRevision) GetAffinity() *corev1.Affinity {
+	return p.Spec.Affinity
+}
+
+// SetAffinity of this ProviderRevision.
+func (p *ProviderRevision) SetAffinity(a *corev1.Affinity) {
+	p.Spec.Affinity = a
+}
+
+// GetReadinessGates of this ProviderRevision.
+func (p *ProviderRevision) GetReadinessGates() []corev1.PodReadinessGate {
+	return p.Spec.ReadinessGates
+}
+
+// SetReadinessGates of this ProviderRevision.
+func (p *ProviderRevision) SetReadinessGates(g []corev1.PodReadinessGate) {
+	p.Spec.ReadinessGates = g
+}
+
+// GetMinReadySeconds of this ProviderRevision.
+func (p *ProviderRevision) GetMinReadySeconds() *int32 {
+	return p.Spec.MinReadySeconds
+}
+
+// SetMinReadySeconds of this ProviderRevision.
+func (p *ProviderRevision) SetMinReadySeconds(s *int32) {
+	p.Spec.MinReadySeconds = s
+}
+
+// GetReplicaCount of this ProviderRevision.
+func (p *ProviderRevision) GetReplicaCount() *int32 {
+	return p.Spec.Replicas
+}
+
+// SetReplicaCount of this ProviderRevision.
+func (p *ProviderRevision) SetReplicaCount(n *int32) {
+	p.Spec.Replicas = n
+}
+
+// GetLeaderElection of this ProviderRevision.
+func (p *ProviderRevision) GetLeaderElection() *bool {
+	return p.Spec.LeaderElection
+}
+
+// SetLeaderElection of this ProviderRevision.
+func (p *ProviderRevision) SetLeaderElection(b *bool) {
+	p.Spec.LeaderElection = b
+}
+
+// GetExtraVolumes of this ProviderRevision.
+func (p *ProviderRevision) GetExtraVolumes() []corev1.Volume {
+	return p.Spec.ExtraVolumes
+}
+
+// SetExtraVolumes of this ProviderRevision.
+func (p *ProviderRevision) SetExtraVolumes(v []corev1.Volume) {
+	p.Spec.ExtraVolumes = v
+}
+
+// GetExtraVolumeMounts of this ProviderRevision.
+func (p *ProviderRevision) GetExtraVolumeMounts() []corev1.VolumeMount {
+	return p.Spec.ExtraVolumeMounts
+}
+
+// SetExtraVolumeMounts of this ProviderRevision.
+func (p *ProviderRevision) SetExtraVolumeMounts(vm []corev1.VolumeMount) {
+	p.Spec.ExtraVolumeMounts = vm
+}
+
+// GetAdmissionWebhookFailurePolicy of this ProviderRevision.
+func (p *ProviderRevision) GetAdmissionWebhookFailurePolicy() *admv1.FailurePolicyType {
+	return p.Spec.AdmissionWebhookFailurePolicy
+}
+
+// SetAdmissionWebhookFailurePolicy of this ProviderRevision.
+func (p *ProviderRevision) SetAdmissionWebhookFailurePolicy(fp *admv1.FailurePolicyType) {
+	p.Spec.AdmissionWebhookFailurePolicy = fp
+}
+
+// GetPodDisruptionBudget of this ProviderRevision.
+func (p *ProviderRevision) GetPodDisruptionBudget() *policyv1.PodDisruptionBudgetSpec {
+	return p.Spec.PodDisruptionBudget
+}
+
+// SetPodDisruptionBudget of this ProviderRevision.
+func (p *ProviderRevision) SetPodDisruptionBudget(pdb *policyv1.PodDisruptionBudgetSpec) {
+	p.Spec.PodDisruptionBudget = pdb
+}
+
 // SetESSTLSSecretName of this ProviderRevision.
 func (p *ProviderRevision) SetESSTLSSecretName(s *string) {
 	p.Spec.ESSTLSSecretName = s
 }
 
+// GetObjectSelector of this ProviderRevision.
+func (p *ProviderRevision) GetObjectSelector() *ObjectSelector {
+	return p.Spec.ObjectSelector
+}
+
+// SetObjectSelector of this ProviderRevision.
+func (p *ProviderRevision) SetObjectSelector(s *ObjectSelector) {
+	p.Spec.ObjectSelector = s
+}
+
 // GetCommonLabels of this ProviderRevision.
 func (p *ProviderRevision) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -598,6 +1821,12 @@ func (p *ConfigurationRevision) SetObjects(c []xpv1.TypedReference) {
 	p.Status.ObjectRefs = c
 }
 
+// GetInstalledCRDNames returns the names of the CustomResourceDefinitions
+// installed by this ConfigurationRevision.
+func (p *ConfigurationRevision) GetInstalledCRDNames() []string {
+	return CRDNames(p.Status.ObjectRefs)
+}
+
 // GetControllerReference of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetControllerReference() ControllerReference {
 	return p.Status.ControllerRef
@@ -658,6 +1887,26 @@ func (p *ConfigurationRevision) SetRevision(r int64) {
 	p.Spec.Revision = r
 }
 
+// GetExpiresAt of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetExpiresAt() *metav1.Time {
+	return p.Spec.ExpiresAt
+}
+
+// SetExpiresAt of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetExpiresAt(t *metav1.Time) {
+	p.Spec.ExpiresAt = t
+}
+
+// GetObservedGeneration of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetObservedGeneration() int64 {
+	return p.Status.ObservedGeneration
+}
+
+// SetObservedGeneration of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetObservedGeneration(g int64) {
+	p.Status.ObservedGeneration = g
+}
+
 // GetDependencyStatus of this v.
 func (p *ConfigurationRevision) GetDependencyStatus() (found, installed, invalid int64) {
 	return p.Status.FoundDependencies, p.Status.InstalledDependencies, p.Status.InvalidDependencies
@@ -670,6 +1919,96 @@ func (p *ConfigurationRevision) SetDependencyStatus(found, installed, invalid in
 	p.Status.InvalidDependencies = invalid
 }
 
+// GetResolvedDependencies of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetResolvedDependencies() []ResolvedDependency {
+	return p.Status.ResolvedDependencies
+}
+
+// SetResolvedDependencies of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetResolvedDependencies(d []ResolvedDependency) {
+	p.Status.ResolvedDependencies = d
+}
+
+// GetLastPullTime of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetLastPullTime() *metav1.Time {
+	return p.Status.LastPullTime
+}
+
+// SetLastPullTime of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetLastPullTime(t *metav1.Time) {
+	p.Status.LastPullTime = t
+}
+
+// GetCrossplaneVersionConstraint of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetCrossplaneVersionConstraint() string {
+	return p.Status.CrossplaneVersionConstraint
+}
+
+// SetCrossplaneVersionConstraint of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetCrossplaneVersionConstraint(c string) {
+	p.Status.CrossplaneVersionConstraint = c
+}
+
+// GetPackageMeta of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPackageMeta() *PackageMeta {
+	return p.Status.PackageMeta
+}
+
+// SetPackageMeta of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPackageMeta(m *PackageMeta) {
+	p.Status.PackageMeta = m
+}
+
+// GetConditionHistory of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetConditionHistory() []xpv1.Condition {
+	return p.Status.ConditionHistory
+}
+
+// SetConditionHistory of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetConditionHistory(h []xpv1.Condition) {
+	p.Status.ConditionHistory = h
+}
+
+// GetInstalledVersion of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetInstalledVersion() string {
+	return p.Status.InstalledVersion
+}
+
+// SetInstalledVersion of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetInstalledVersion(v string) {
+	p.Status.InstalledVersion = v
+}
+
+// GetReconciliationFailureCount of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetReconciliationFailureCount() int64 {
+	return p.Status.ReconciliationFailureCount
+}
+
+// SetReconciliationFailureCount of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetReconciliationFailureCount(n int64) {
+	p.Status.ReconciliationFailureCount = n
+}
+
+// GetParseError of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetParseError() *PackageParseError {
+	return p.Status.ParseError
+}
+
+// SetParseError of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetParseError(e *PackageParseError) {
+	p.Status.ParseError = e
+}
+
+// GetRequiredPermissions of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetRequiredPermissions() []rbacv1.PolicyRule {
+	return p.Status.RequiredPermissions
+}
+
+// SetRequiredPermissions of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetRequiredPermissions(r []rbacv1.PolicyRule) {
+	p.Status.RequiredPermissions = r
+}
+
 // GetIgnoreCrossplaneConstraints of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetIgnoreCrossplaneConstraints() *bool {
 	return p.Spec.IgnoreCrossplaneConstraints
@@ -740,6 +2079,176 @@ func (p *ConfigurationRevision) SetTLSClientSecretName(s *string) {
 	p.Spec.TLSClientSecretName = s
 }
 
+// GetVerificationCertRef of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetVerificationCertRef() *corev1.SecretReference {
+	return p.Spec.VerificationCertRef
+}
+
+// SetVerificationCertRef of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetVerificationCertRef(r *corev1.SecretReference) {
+	p.Spec.VerificationCertRef = r
+}
+
+// GetMaxUnavailable of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetMaxUnavailable() *intstr.IntOrString {
+	return p.Spec.MaxUnavailable
+}
+
+// SetMaxUnavailable of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetMaxUnavailable(m *intstr.IntOrString) {
+	p.Spec.MaxUnavailable = m
+}
+
+// GetHealthCheck of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetHealthCheck() *HealthCheck {
+	return p.Spec.HealthCheck
+}
+
+// SetHealthCheck of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetHealthCheck(hc *HealthCheck) {
+	p.Spec.HealthCheck = hc
+}
+
+// GetPriorityClassName of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPriorityClassName() *string {
+	return p.Spec.PriorityClassName
+}
+
+// SetPriorityClassName of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPriorityClassName(n *string) {
+	p.Spec.PriorityClassName = n
+}
+
+// GetPodTemplateAnnotations of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPodTemplateAnnotations() map[string]string {
+	return p.Spec.PodTemplateAnnotations
+}
+
+// SetPodTemplateAnnotations of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPodTemplateAnnotations(a map[string]string) {
+	p.Spec.PodTemplateAnnotations = a
+}
+
+// GetTolerations of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetTolerations() []corev1.Toleration {
+	return p.Spec.Tolerations
+}
+
+// SetTolerations of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetTolerations(t []corev1.Toleration) {
+	p.Spec.Tolerations = t
+}
+
+// GetNodeSelector of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetNodeSelector() map[string]string {
+	return p.Spec.NodeSelector
+}
+
+// SetNodeSelector of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetNodeSelector(s map[string]string) {
+	p.Spec.NodeSelector = s
+}
+
+// GetAffinity of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetAffinity() *corev1.Affinity {
+	return p.Spec.Affinity
+}
+
+// SetAffinity of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetAffinity(a *corev1.Affinity) {
+	p.Spec.Affinity = a
+}
+
+// GetReadinessGates of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetReadinessGates() []corev1.PodReadinessGate {
+	return p.Spec.ReadinessGates
+}
+
+// SetReadinessGates of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetReadinessGates(g []corev1.PodReadinessGate) {
+	p.Spec.ReadinessGates = g
+}
+
+// GetMinReadySeconds of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetMinReadySeconds() *int32 {
+	return p.Spec.MinReadySeconds
+}
+
+// SetMinReadySeconds of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetMinReadySeconds(s *int32) {
+	p.Spec.MinReadySeconds = s
+}
+
+// GetReplicaCount of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetReplicaCount() *int32 {
+	return p.Spec.Replicas
+}
+
+// SetReplicaCount of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetReplicaCount(n *int32) {
+	p.Spec.Replicas = n
+}
+
+// GetLeaderElection of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetLeaderElection() *bool {
+	return p.Spec.LeaderElection
+}
+
+// SetLeaderElection of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetLeaderElection(b *bool) {
+	p.Spec.LeaderElection = b
+}
+
+// GetExtraVolumes of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetExtraVolumes() []corev1.Volume {
+	return p.Spec.ExtraVolumes
+}
+
+// SetExtraVolumes of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetExtraVolumes(v []corev1.Volume) {
+	p.Spec.ExtraVolumes = v
+}
+
+// GetExtraVolumeMounts of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetExtraVolumeMounts() []corev1.VolumeMount {
+	return p.Spec.ExtraVolumeMounts
+}
+
+// SetExtraVolumeMounts of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetExtraVolumeMounts(vm []corev1.VolumeMount) {
+	p.Spec.ExtraVolumeMounts = vm
+}
+
+// GetAdmissionWebhookFailurePolicy of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetAdmissionWebhookFailurePolicy() *admv1.FailurePolicyType {
+	return p.Spec.AdmissionWebhookFailurePolicy
+}
+
+// SetAdmissionWebhookFailurePolicy of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetAdmissionWebhookFailurePolicy(fp *admv1.FailurePolicyType) {
+	p.Spec.AdmissionWebhookFailurePolicy = fp
+}
+
+// GetPodDisruptionBudget of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetPodDisruptionBudget() *policyv1.PodDisruptionBudgetSpec {
+	return p.Spec.PodDisruptionBudget
+}
+
+// SetPodDisruptionBudget of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetPodDisruptionBudget(pdb *policyv1.PodDisruptionBudgetSpec) {
+	p.Spec.PodDisruptionBudget = pdb
+}
+
+// GetObjectSelector of this ConfigurationRevision.
+func (p *ConfigurationRevision) GetObjectSelector() *ObjectSelector {
+	return p.Spec.ObjectSelector
+}
+
+// SetObjectSelector of this ConfigurationRevision.
+func (p *ConfigurationRevision) SetObjectSelector(s *ObjectSelector) {
+	p.Spec.ObjectSelector = s
+}
+
 // GetCommonLabels of this ConfigurationRevision.
 func (p *ConfigurationRevision) GetCommonLabels() map[string]string {
 	return p.Spec.CommonLabels
@@ -765,6 +2274,18 @@ type PackageRevisionList interface {
 	// a shared reconciler, consider refactoring the controller to use a
 	// reconciler for the specific type.
 	GetRevisions() []PackageRevision
+
+	// LatestRevision returns the PackageRevision with the highest revision
+	// number in a PackageRevisionList, or nil if the list is empty. If more
+	// than one PackageRevision has the highest revision number the one with
+	// the greatest name is returned, consistent with SortRevisionsByNumber.
+	// Unlike GetRevisions it does not allocate a slice of every revision.
+	LatestRevision() PackageRevision
+
+	// RevisionForNumber returns the PackageRevision with the supplied
+	// revision number in a PackageRevisionList, or nil if there is none.
+	// Unlike GetRevisions it does not allocate a slice of every revision.
+	RevisionForNumber(n int64) PackageRevision
 }
 
 // GetRevisions of this ProviderRevisionList.
@@ -777,6 +2298,38 @@ func (p *ProviderRevisionList) GetRevisions() []PackageRevision {
 	return prs
 }
 
+// LatestRevision of this ProviderRevisionList.
+func (p *ProviderRevisionList) LatestRevision() PackageRevision {
+	latest := -1
+	for i := range p.Items {
+		if latest < 0 || laterRevision(&p.Items[i], &p.Items[latest]) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return nil
+	}
+	return &p.Items[latest]
+}
+
+// laterRevision returns true if revision a is later than revision b.
+func laterRevision(a, b PackageRevision) bool {
+	if a.GetRevision() != b.GetRevision() {
+		return a.GetRevision() > b.GetRevision()
+	}
+	return a.GetName() > b.GetName()
+}
+
+// RevisionForNumber of this ProviderRevisionList.
+func (p *ProviderRevisionList) RevisionForNumber(n int64) PackageRevision {
+	for i := range p.Items {
+		if p.Items[i].GetRevision() == n {
+			return &p.Items[i]
+		}
+	}
+	return nil
+}
+
 // GetRevisions of this ConfigurationRevisionList.
 func (p *ConfigurationRevisionList) GetRevisions() []PackageRevision {
 	prs := make([]PackageRevision, len(p.Items))
@@ -786,3 +2339,27 @@ func (p *ConfigurationRevisionList) GetRevisions() []PackageRevision {
 	}
 	return prs
 }
+
+// LatestRevision of this ConfigurationRevisionList.
+func (p *ConfigurationRevisionList) LatestRevision() PackageRevision {
+	latest := -1
+	for i := range p.Items {
+		if latest < 0 || laterRevision(&p.Items[i], &p.Items[latest]) {
+			latest = i
+		}
+	}
+	if latest < 0 {
+		return nil
+	}
+	return &p.Items[latest]
+}
+
+// RevisionForNumber of this ConfigurationRevisionList.
+func (p *ConfigurationRevisionList) RevisionForNumber(n int64) PackageRevision {
+	for i := range p.Items {
+		if p.Items[i].GetRevision() == n {
+			return &p.Items[i]
+		}
+	}
+	return nil
+}
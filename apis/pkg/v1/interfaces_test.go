@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetStatusSummary(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      Package
+		want   PackageStatusSummary
+	}{
+		"Empty": {
+			reason: "A package with no status should be neither healthy, installed, nor degraded.",
+			p:      &Provider{},
+			want:   PackageStatusSummary{},
+		},
+		"Healthy": {
+			reason: "A healthy, installed package should not be degraded.",
+			p: func() Package {
+				p := &Configuration{}
+				p.SetConditions(Healthy(), Active())
+				p.SetCurrentRevision("cool-2")
+				p.SetLastRevision("cool-1")
+				p.SetDependencyStatus(&DependencyStatus{Found: 2, Installed: 2})
+				return p
+			}(),
+			want: PackageStatusSummary{
+				Healthy:          true,
+				Installed:        true,
+				CurrentRevision:  "cool-2",
+				LastRevision:     "cool-1",
+				DependencyStatus: DependencyStatus{Found: 2, Installed: 2},
+			},
+		},
+		"Degraded": {
+			reason: "An installed package that is not healthy should be degraded.",
+			p: func() Package {
+				p := &Provider{}
+				p.SetConditions(Unhealthy(), Active())
+				p.SetCurrentRevision("cool-1")
+				p.SetDependencyStatus(&DependencyStatus{Found: 2, Installed: 1, Invalid: 1})
+				return p
+			}(),
+			want: PackageStatusSummary{
+				Installed:        true,
+				Degraded:         true,
+				CurrentRevision:  "cool-1",
+				DependencyStatus: DependencyStatus{Found: 2, Installed: 1, Invalid: 1},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.p.GetStatusSummary()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nGetStatusSummary(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStatusSummary(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		p      Package
+		want   string
+	}{
+		"Installing": {
+			reason: "A package with no current revision should be reported as installing.",
+			p: func() Package {
+				p := &Provider{}
+				p.SetConditions(Unpacking())
+				return p
+			}(),
+			want: "state=Installing activation=Automatic revision=<none> dependencies=found:0,installed:0,invalid:0",
+		},
+		"Healthy": {
+			reason: "A healthy, installed package should be reported as healthy.",
+			p: func() Package {
+				p := &Configuration{}
+				p.SetActivationPolicy(&ManualActivation)
+				p.SetConditions(Healthy(), Active())
+				p.SetCurrentRevision("cool-1234567")
+				p.SetDependencyStatus(&DependencyStatus{Found: 2, Installed: 2})
+				return p
+			}(),
+			want: "state=Healthy activation=Manual revision=cool-1234567 dependencies=found:2,installed:2,invalid:0",
+		},
+		"Inactive": {
+			reason: "A package whose current revision is inactive should be reported as inactive.",
+			p: func() Package {
+				p := &Configuration{}
+				p.SetActivationPolicy(&ManualActivation)
+				p.SetConditions(UnknownHealth(), Inactive())
+				p.SetCurrentRevision("cool-1234567")
+				return p
+			}(),
+			want: "state=Inactive activation=Manual revision=cool-1234567 dependencies=found:0,installed:0,invalid:0",
+		},
+		"Failed": {
+			reason: "A package that is not healthy should be reported as failed.",
+			p: func() Package {
+				p := &Provider{}
+				p.SetConditions(Unhealthy(), Active())
+				p.SetCurrentRevision("cool-1234567")
+				p.SetDependencyStatus(&DependencyStatus{Found: 2, Installed: 1, Invalid: 1})
+				return p
+			}(),
+			want: "state=Failed activation=Automatic revision=cool-1234567 dependencies=found:2,installed:1,invalid:1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := StatusSummary(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nStatusSummary(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestLatestRevision(t *testing.T) {
+	rev := func(name string, n int64) ProviderRevision {
+		return ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: PackageRevisionSpec{Revision: n}}
+	}
+	crev := func(name string, n int64) ConfigurationRevision {
+		return ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: name}, Spec: PackageRevisionSpec{Revision: n}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		l      PackageRevisionList
+		want   string
+	}{
+		"Empty": {
+			reason: "An empty list should have no latest revision.",
+			l:      &ProviderRevisionList{},
+			want:   "",
+		},
+		"Unordered": {
+			reason: "The revision with the highest number should be the latest, regardless of its position in the list.",
+			l:      &ProviderRevisionList{Items: []ProviderRevision{rev("b", 2), rev("c", 10), rev("a", 1)}},
+			want:   "c",
+		},
+		"Ties": {
+			reason: "The revision with the greatest name should be the latest if more than one has the highest number.",
+			l:      &ProviderRevisionList{Items: []ProviderRevision{rev("c", 1), rev("b", 2), rev("a", 2)}},
+			want:   "b",
+		},
+		"Configurations": {
+			reason: "The revision with the highest number should be the latest configuration revision.",
+			l:      &ConfigurationRevisionList{Items: []ConfigurationRevision{crev("b", 3), crev("a", 1)}},
+			want:   "b",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ""
+			if pr := tc.l.LatestRevision(); pr != nil {
+				got = pr.GetName()
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nLatestRevision(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Matches returns true if the filter matches an object of the supplied kind
+// and name.
+func (f ObjectFilter) Matches(gvk schema.GroupVersionKind, name string) bool {
+	if f.APIVersion != "" && f.APIVersion != gvk.GroupVersion().String() {
+		return false
+	}
+	if f.Kind != "" && f.Kind != gvk.Kind {
+		return false
+	}
+	return f.Name == "" || f.Name == name
+}
+
+// Selects returns true if the selector selects an object of the supplied kind
+// and name. A nil selector selects all objects.
+func (s *ObjectSelector) Selects(gvk schema.GroupVersionKind, name string) bool {
+	if s == nil {
+		return true
+	}
+	for _, f := range s.Exclude {
+		if f.Matches(gvk, name) {
+			return false
+		}
+	}
+	if len(s.Include) == 0 {
+		return true
+	}
+	for _, f := range s.Include {
+		if f.Matches(gvk, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestObjectSelectorSelects(t *testing.T) {
+	comp := schema.GroupVersionKind{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "Composition"}
+	xrd := schema.GroupVersionKind{Group: "apiextensions.crossplane.io", Version: "v1", Kind: "CompositeResourceDefinition"}
+
+	type args struct {
+		gvk  schema.GroupVersionKind
+		name string
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      *ObjectSelector
+		args   args
+		want   bool
+	}{
+		"NilSelector": {
+			reason: "A nil selector should select all objects.",
+			args:   args{gvk: comp, name: "cool"},
+			want:   true,
+		},
+		"NoInclude": {
+			reason: "All objects should be included if there are no include filters.",
+			s:      &ObjectSelector{},
+			args:   args{gvk: comp, name: "cool"},
+			want:   true,
+		},
+		"IncludedByKind": {
+			reason: "An object that matches an include filter should be selected.",
+			s:      &ObjectSelector{Include: []ObjectFilter{{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition"}}},
+			args:   args{gvk: comp, name: "cool"},
+			want:   true,
+		},
+		"NotIncluded": {
+			reason: "An object that matches no include filter should not be selected.",
+			s:      &ObjectSelector{Include: []ObjectFilter{{Kind: "Composition"}}},
+			args:   args{gvk: xrd, name: "cool"},
+			want:   false,
+		},
+		"WrongAPIVersion": {
+			reason: "An object of a different API version should not match a filter.",
+			s:      &ObjectSelector{Include: []ObjectFilter{{APIVersion: "apiextensions.crossplane.io/v1beta1", Kind: "Composition"}}},
+			args:   args{gvk: comp, name: "cool"},
+			want:   false,
+		},
+		"ExcludedByName": {
+			reason: "An object that matches an exclude filter should not be selected.",
+			s:      &ObjectSelector{Exclude: []ObjectFilter{{Name: "cool"}}},
+			args:   args{gvk: comp, name: "cool"},
+			want:   false,
+		},
+		"ExcludeTakesPrecedence": {
+			reason: "An object that matches both an include and an exclude filter should not be selected.",
+			s: &ObjectSelector{
+				Include: []ObjectFilter{{Kind: "Composition"}},
+				Exclude: []ObjectFilter{{Kind: "Composition", Name: "cool"}},
+			},
+			args: args{gvk: comp, name: "cool"},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.s.Selects(tc.args.gvk, tc.args.name)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSelects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// PackageSpecEqual returns true if the supplied packages have equivalent
+// specs. It compares the fields a user configures - the package source, its
+// policies, pull secrets, lifecycle hooks, object selector, common labels, and
+// runtime configuration - and ignores metadata and status. Nil and empty pull secrets
+// or labels are considered equal.
+func PackageSpecEqual(a, b Package) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.GetSource() == b.GetSource() &&
+		equality.Semantic.DeepEqual(a.GetActivationPolicy(), b.GetActivationPolicy()) &&
+		equality.Semantic.DeepEqual(a.GetRevisionHistoryLimit(), b.GetRevisionHistoryLimit()) &&
+		equality.Semantic.DeepEqual(a.GetRevisionTTL(), b.GetRevisionTTL()) &&
+		equality.Semantic.DeepEqual(a.GetRevisionUpdatePolicy(), b.GetRevisionUpdatePolicy()) &&
+		equality.Semantic.DeepEqual(a.GetUpdatePolicy(), b.GetUpdatePolicy()) &&
+		equality.Semantic.DeepEqual(a.GetPackagePullPolicy(), b.GetPackagePullPolicy()) &&
+		equality.Semantic.DeepEqual(a.GetPackagePullSecrets(), b.GetPackagePullSecrets()) &&
+		equality.Semantic.DeepEqual(a.GetIgnoreCrossplaneConstraints(), b.GetIgnoreCrossplaneConstraints()) &&
+		equality.Semantic.DeepEqual(a.GetSkipDependencyResolution(), b.GetSkipDependencyResolution()) &&
+		equality.Semantic.DeepEqual(a.GetPreventDowngrade(), b.GetPreventDowngrade()) &&
+		equality.Semantic.DeepEqual(a.GetInstallTimeout(), b.GetInstallTimeout()) &&
+		equality.Semantic.DeepEqual(a.GetReconcileInterval(), b.GetReconcileInterval()) &&
+		equality.Semantic.DeepEqual(a.GetLifecycleHooks(), b.GetLifecycleHooks()) &&
+		equality.Semantic.DeepEqual(a.GetObjectSelector(), b.GetObjectSelector()) &&
+		equality.Semantic.DeepEqual(a.GetCommonLabels(), b.GetCommonLabels()) &&
+		equality.Semantic.DeepEqual(a.GetControllerConfigRef(), b.GetControllerConfigRef()) &&
+		equality.Semantic.DeepEqual(a.GetMaxUnavailable(), b.GetMaxUnavailable()) &&
+		equality.Semantic.DeepEqual(a.GetHealthCheck(), b.GetHealthCheck()) &&
+		equality.Semantic.DeepEqual(a.GetPriorityClassName(), b.GetPriorityClassName()) &&
+		equality.Semantic.DeepEqual(a.GetPodTemplateAnnotations(), b.GetPodTemplateAnnotations()) &&
+		equality.Semantic.DeepEqual(a.GetTolerations(), b.GetTolerations()) &&
+		equality.Semantic.DeepEqual(a.GetProviderRevisionAffinity(), b.GetProviderRevisionAffinity()) &&
+		equality.Semantic.DeepEqual(a.GetNodeSelector(), b.GetNodeSelector()) &&
+		equality.Semantic.DeepEqual(a.GetReadinessGates(), b.GetReadinessGates()) &&
+		equality.Semantic.DeepEqual(a.GetMinReadySeconds(), b.GetMinReadySeconds()) &&
+		equality.Semantic.DeepEqual(a.GetReplicaCount(), b.GetReplicaCount()) &&
+		equality.Semantic.DeepEqual(a.GetLeaderElection(), b.GetLeaderElection()) &&
+		equality.Semantic.DeepEqual(a.GetExtraVolumes(), b.GetExtraVolumes()) &&
+		equality.Semantic.DeepEqual(a.GetExtraVolumeMounts(), b.GetExtraVolumeMounts())
+}
@@ -0,0 +1,367 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+func TestPackageSpecEqual(t *testing.T) {
+	provider := func(m ...func(p *Provider)) *Provider {
+		p := &Provider{
+			ObjectMeta: metav1.ObjectMeta{Name: "cool-provider"},
+			Spec: ProviderSpec{
+				PackageSpec: PackageSpec{
+					Package:            "xpkg.upbound.io/crossplane/provider-nop:v0.2.0",
+					PackagePullSecrets: []corev1.LocalObjectReference{{Name: "cool-secret"}},
+					CommonLabels:       map[string]string{"cool": "label"},
+				},
+			},
+		}
+		for _, fn := range m {
+			fn(p)
+		}
+		return p
+	}
+	automatic := AutomaticActivation
+	manual := ManualActivation
+	always := corev1.PullAlways
+
+	type args struct {
+		a Package
+		b Package
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"Identical": {
+			reason: "Identical packages should be equal.",
+			args: args{
+				a: provider(),
+				b: provider(),
+			},
+			want: true,
+		},
+		"DifferentMetadataAndStatus": {
+			reason: "Packages that differ only in metadata and status should be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) {
+					p.SetName("cooler-provider")
+					p.SetLabels(map[string]string{"cool": "label"})
+					p.SetResourceVersion("42")
+					p.SetCurrentRevision("cool-revision")
+					p.SetConditions(xpv1.Available())
+				}),
+			},
+			want: true,
+		},
+		"NilAndEmptyCollections": {
+			reason: "Packages with nil and empty pull secrets and labels should be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.PackagePullSecrets = nil
+					p.Spec.CommonLabels = nil
+				}),
+				b: provider(func(p *Provider) {
+					p.Spec.PackagePullSecrets = []corev1.LocalObjectReference{}
+					p.Spec.CommonLabels = map[string]string{}
+				}),
+			},
+			want: true,
+		},
+		"DifferentSource": {
+			reason: "Packages with different sources should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.Package = "xpkg.upbound.io/crossplane/provider-nop:v0.3.0" }),
+			},
+			want: false,
+		},
+		"DifferentActivationPolicy": {
+			reason: "Packages with different activation policies should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) { p.Spec.RevisionActivationPolicy = &automatic }),
+				b: provider(func(p *Provider) { p.Spec.RevisionActivationPolicy = &manual }),
+			},
+			want: false,
+		},
+		"DifferentRevisionHistoryLimit": {
+			reason: "Packages with different revision history limits should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) { p.Spec.RevisionHistoryLimit = pointer.Int64(1) }),
+				b: provider(func(p *Provider) { p.Spec.RevisionHistoryLimit = pointer.Int64(2) }),
+			},
+			want: false,
+		},
+		"DifferentPullPolicy": {
+			reason: "Packages with different pull policies should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.PackagePullPolicy = &always }),
+			},
+			want: false,
+		},
+		"DifferentPullSecrets": {
+			reason: "Packages with different pull secrets should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.PackagePullSecrets = []corev1.LocalObjectReference{{Name: "cooler-secret"}} }),
+			},
+			want: false,
+		},
+		"DifferentIgnoreCrossplaneConstraints": {
+			reason: "Packages that differ in whether they ignore Crossplane constraints should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.IgnoreCrossplaneConstraints = pointer.Bool(true) }),
+			},
+			want: false,
+		},
+		"DifferentSkipDependencyResolution": {
+			reason: "Packages that differ in whether they skip dependency resolution should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.SkipDependencyResolution = pointer.Bool(true) }),
+			},
+			want: false,
+		},
+		"DifferentPreventDowngrade": {
+			reason: "Packages that differ in whether they prevent downgrades should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.PreventDowngrade = pointer.Bool(true) }),
+			},
+			want: false,
+		},
+		"DifferentInstallTimeout": {
+			reason: "Packages with different install timeouts should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.InstallTimeout = &metav1.Duration{Duration: time.Minute} }),
+			},
+			want: false,
+		},
+		"DifferentReconcileInterval": {
+			reason: "Packages with different reconcile intervals should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.ReconcileInterval = &metav1.Duration{Duration: time.Hour} }),
+			},
+			want: false,
+		},
+		"DifferentObjectSelector": {
+			reason: "Packages that select different objects should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) {
+					p.Spec.ObjectSelector = &ObjectSelector{Exclude: []ObjectFilter{{Kind: "Composition"}}}
+				}),
+			},
+			want: false,
+		},
+		"DifferentCommonLabels": {
+			reason: "Packages with different common labels should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.CommonLabels = map[string]string{"cool": "er"} }),
+			},
+			want: false,
+		},
+		"DifferentControllerConfigRef": {
+			reason: "Packages that reference different controller configs should not be equal.",
+			args: args{
+				a: provider(),
+				b: provider(func(p *Provider) { p.Spec.ControllerConfigReference = &ControllerConfigReference{Name: "cool-config"} }),
+			},
+			want: false,
+		},
+		"DifferentMaxUnavailable": {
+			reason: "Packages with a different maximum number of unavailable pods should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) { p.Spec.MaxUnavailable = &intstr.IntOrString{Type: intstr.Int, IntVal: 1} }),
+				b: provider(func(p *Provider) { p.Spec.MaxUnavailable = &intstr.IntOrString{Type: intstr.String, StrVal: "50%"} }),
+			},
+			want: false,
+		},
+		"DifferentHealthCheck": {
+			reason: "Packages with different health checks should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) { p.Spec.HealthCheck = &HealthCheck{Path: "/healthz", Port: 8081} }),
+				b: provider(func(p *Provider) { p.Spec.HealthCheck = &HealthCheck{Path: "/readyz", Port: 8081} }),
+			},
+			want: false,
+		},
+		"DifferentPriorityClassName": {
+			reason: "Packages with different priority classes should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) { p.Spec.PriorityClassName = pointer.String("high") }),
+				b: provider(func(p *Provider) {}),
+			},
+			want: false,
+		},
+		"DifferentPodTemplateAnnotations": {
+			reason: "Packages with different pod template annotations should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) { p.Spec.PodTemplateAnnotations = map[string]string{"cool": "annotation"} }),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentTolerations": {
+			reason: "Packages with different tolerations should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.Tolerations = []corev1.Toleration{{Key: "gpu", Operator: corev1.TolerationOpExists}}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentProviderRevisionAffinity": {
+			reason: "Packages with different affinities should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.Affinity = &corev1.Affinity{PodAffinity: &corev1.PodAffinity{}}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentUpdatePolicy": {
+			reason: "Packages with different update policies should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					u := UpdateOnce
+					p.Spec.UpdatePolicy = &u
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentLifecycleHooks": {
+			reason: "Packages with different lifecycle hooks should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.LifecycleHooks = &LifecycleHooks{PreUpgrade: &batchv1.JobSpec{BackoffLimit: pointer.Int32(1)}}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentNodeSelector": {
+			reason: "Packages with different node selectors should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.NodeSelector = map[string]string{"kubernetes.io/arch": "arm64"}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentReadinessGates": {
+			reason: "Packages with different readiness gates should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: "example.org/ready"}}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentMinReadySeconds": {
+			reason: "Packages with different minimum ready seconds should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.MinReadySeconds = pointer.Int32(10)
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentReplicas": {
+			reason: "Packages with different replicas should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.Replicas = pointer.Int32(3)
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentLeaderElection": {
+			reason: "Packages that differ in whether they use leader election should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.LeaderElection = pointer.Bool(true)
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentExtraVolumes": {
+			reason: "Packages with different extra volumes should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.ExtraVolumes = []corev1.Volume{{Name: "ca-bundle"}}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"DifferentExtraVolumeMounts": {
+			reason: "Packages with different extra volume mounts should not be equal.",
+			args: args{
+				a: provider(func(p *Provider) {
+					p.Spec.ExtraVolumeMounts = []corev1.VolumeMount{{Name: "ca-bundle", MountPath: "/certs"}}
+				}),
+				b: provider(),
+			},
+			want: false,
+		},
+		"Configurations": {
+			reason: "Configurations with equivalent specs should be equal.",
+			args: args{
+				a: &Configuration{Spec: ConfigurationSpec{PackageSpec: PackageSpec{Package: "xpkg.upbound.io/crossplane/configuration-nop:v0.1.0"}}},
+				b: &Configuration{Spec: ConfigurationSpec{PackageSpec: PackageSpec{Package: "xpkg.upbound.io/crossplane/configuration-nop:v0.1.0"}}},
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := PackageSpecEqual(tc.args.a, tc.args.b)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nPackageSpecEqual(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -16,7 +16,11 @@ limitations under the License.
 
 package v1
 
-import corev1 "k8s.io/api/core/v1"
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
 
 // PackageSpec specifies the desired state of a Package.
 type PackageSpec struct {
@@ -37,6 +41,33 @@ type PackageSpec struct {
 	// +kubebuilder:default=1
 	RevisionHistoryLimit *int64 `json:"revisionHistoryLimit,omitempty"`
 
+	// RevisionTTL is how long a package revision is kept after it is created.
+	// Inactive revisions are garbage collected once they expire, regardless
+	// of RevisionHistoryLimit. It only applies to revisions created after it
+	// is set. Revisions don't expire by default.
+	// +optional
+	RevisionTTL *metav1.Duration `json:"revisionTTL,omitempty"`
+
+	// RevisionUpdatePolicy specifies which fields the package controller
+	// should sync from the package to its existing current revision. Options
+	// are All, PullSecretOnly, or None. Fields that aren't synced may be
+	// overridden by updating the revision directly. New revisions are always
+	// created with all fields. Default is All.
+	// +optional
+	// +kubebuilder:validation:Enum=All;PullSecretOnly;None
+	// +kubebuilder:default=All
+	RevisionUpdatePolicy *RevisionUpdatePolicy `json:"revisionUpdatePolicy,omitempty"`
+
+	// UpdatePolicy specifies whether the package controller should track the
+	// digest its source resolves to. Options are Continuous or Once. When
+	// Continuous the package is updated whenever its source's tag moves to a
+	// new digest. When Once the source is resolved to a digest only when the
+	// source changes; tag movement is ignored. Default is Continuous.
+	// +optional
+	// +kubebuilder:validation:Enum=Continuous;Once
+	// +kubebuilder:default=Continuous
+	UpdatePolicy *UpdatePolicy `json:"updatePolicy,omitempty"`
+
 	// PackagePullSecrets are named secrets in the same namespace that can be used
 	// to fetch packages from private registries.
 	// +optional
@@ -63,6 +94,40 @@ type PackageSpec struct {
 	// +kubebuilder:default=false
 	SkipDependencyResolution *bool `json:"skipDependencyResolution,omitempty"`
 
+	// PreventDowngrade indicates to the package manager whether to refuse to
+	// activate a package revision with a lower semantic version than the
+	// currently active revision. A downgrade may be forced by annotating the
+	// package with pkg.crossplane.io/allow-downgrade: "true".
+	// Default is false.
+	// +optional
+	// +kubebuilder:default=false
+	PreventDowngrade *bool `json:"preventDowngrade,omitempty"`
+
+	// InstallTimeout is how long the package manager waits for a package to
+	// become healthy after it starts installing the package's source. Once
+	// the timeout elapses the package is marked unhealthy and the package
+	// manager stops retrying. The timeout is reset when the package's source
+	// changes. By default there is no timeout.
+	// +optional
+	InstallTimeout *metav1.Duration `json:"installTimeout,omitempty"`
+
+	// ReconcileInterval is how often the package manager re-syncs the package
+	// after it reconciles successfully, for example to check whether its
+	// source's tag has moved. It overrides the package manager's default.
+	// Intervals shorter than 30s are treated as 30s.
+	// +optional
+	ReconcileInterval *metav1.Duration `json:"reconcileInterval,omitempty"`
+
+	// LifecycleHooks are Jobs the package manager runs when the package is
+	// upgraded from one revision to the next.
+	// +optional
+	LifecycleHooks *LifecycleHooks `json:"lifecycleHooks,omitempty"`
+
+	// ObjectSelector selects which of the package's objects the package
+	// manager applies. By default all of the package's objects are applied.
+	// +optional
+	ObjectSelector *ObjectSelector `json:"objectSelector,omitempty"`
+
 	// Map of string keys and values that can be used to organize and categorize
 	// (scope and select) objects. May match selectors of replication controllers
 	// and services.
@@ -71,6 +136,38 @@ type PackageSpec struct {
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
 }
 
+// An ObjectSelector selects objects of a package. An object is selected if it
+// matches any of the include filters, or if there are no include filters, and
+// it matches none of the exclude filters.
+type ObjectSelector struct {
+	// Include selects the objects that match any of these filters. All
+	// objects are included if no filters are specified.
+	// +optional
+	Include []ObjectFilter `json:"include,omitempty"`
+
+	// Exclude deselects the objects that match any of these filters, even if
+	// they're included.
+	// +optional
+	Exclude []ObjectFilter `json:"exclude,omitempty"`
+}
+
+// An ObjectFilter matches objects of a package by their kind and name. Fields
+// that aren't specified match any object.
+type ObjectFilter struct {
+	// APIVersion of the objects to match, for example
+	// apiextensions.crossplane.io/v1.
+	// +optional
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Kind of the objects to match, for example Composition.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the object to match.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
 // PackageStatus represents the observed state of a Package.
 type PackageStatus struct {
 	// CurrentRevision is the name of the current package revision. It will
@@ -85,4 +182,68 @@ type PackageStatus struct {
 	// will cause the package manager to check that the current revision is
 	// correct for the given package source.
 	CurrentIdentifier string `json:"currentIdentifier,omitempty"`
+
+	// InstallAttempt records when the package manager started installing the
+	// package's current source. It is only set while a package with an
+	// installTimeout is not yet healthy.
+	// +optional
+	InstallAttempt *InstallAttempt `json:"installAttempt,omitempty"`
+
+	// LastRevision is the name of the package revision that was current
+	// before CurrentRevision.
+	// +optional
+	LastRevision string `json:"lastRevision,omitempty"`
+
+	// DependencyStatus of the current package revision.
+	// +optional
+	DependencyStatus *DependencyStatus `json:"dependencyStatus,omitempty"`
+}
+
+// DependencyStatus summarizes the dependencies of a package revision.
+type DependencyStatus struct {
+	// Found is the number of dependencies the package revision declares.
+	Found int64 `json:"found,omitempty"`
+
+	// Installed is the number of dependencies that are installed.
+	Installed int64 `json:"installed,omitempty"`
+
+	// Invalid is the number of dependencies that are installed at a version
+	// that doesn't satisfy the package revision's constraints.
+	Invalid int64 `json:"invalid,omitempty"`
+}
+
+// An InstallAttempt records when the package manager started installing a
+// package source.
+type InstallAttempt struct {
+	// Source is the package source being installed.
+	Source string `json:"source"`
+
+	// StartTime is the time at which the package manager started installing
+	// the source.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// LifecycleHooks are Jobs the package manager runs when a package is upgraded
+// from one revision to the next. Hooks run in the namespace Crossplane runs
+// packages in. Each hook runs once per revision, as a dedicated service
+// account without an API token. Any service account or host namespaces a hook
+// asks for are ignored, and hooks may not use hostPath volumes or privileged
+// containers.
+type LifecycleHooks struct {
+	// PreUpgrade is run before the package's new revision is activated. The
+	// package's previous revision remains active until the Job succeeds. The
+	// new revision isn't activated if the Job fails.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PreUpgrade *batchv1.JobSpec `json:"preUpgrade,omitempty"`
+
+	// PostUpgrade is run once the package's new revision is active and
+	// healthy.
+	// +optional
+	// +kubebuilder:validation:Schemaless
+	// +kubebuilder:validation:Type=object
+	// +kubebuilder:pruning:PreserveUnknownFields
+	PostUpgrade *batchv1.JobSpec `json:"postUpgrade,omitempty"`
 }
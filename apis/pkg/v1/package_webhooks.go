@@ -0,0 +1,30 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// +kubebuilder:webhook:verbs=update;create,path=/validate-pkg-crossplane-io-v1-provider,mutating=false,failurePolicy=fail,groups=pkg.crossplane.io,resources=providers,versions=v1,name=providers.pkg.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+// +kubebuilder:webhook:verbs=update;create,path=/validate-pkg-crossplane-io-v1-configuration,mutating=false,failurePolicy=fail,groups=pkg.crossplane.io,resources=configurations,versions=v1,name=configurations.pkg.crossplane.io,sideEffects=None,admissionReviewVersions=v1
+
+package v1
+
+const (
+	// ProviderValidatingWebhookPath is the path for the Provider's
+	// validating webhook, should be kept in sync with the annotation above.
+	ProviderValidatingWebhookPath = "/validate-pkg-crossplane-io-v1-provider"
+
+	// ConfigurationValidatingWebhookPath is the path for the Configuration's
+	// validating webhook, should be kept in sync with the annotation above.
+	ConfigurationValidatingWebhookPath = "/validate-pkg-crossplane-io-v1-configuration"
+)
@@ -17,7 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
@@ -51,6 +53,89 @@ type ProviderSpec struct {
 	// used to configure the packaged controller Deployment.
 	// +optional
 	ControllerConfigReference *ControllerConfigReference `json:"controllerConfigRef,omitempty"`
+
+	// MaxUnavailable is the maximum number of provider pods that can be
+	// unavailable while a new revision is rolled out. It may be an absolute
+	// number or a percentage of the desired pods. The Deployment default is
+	// used if it is not set.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	// +kubebuilder:validation:XValidation:rule="type(self) == int ? self >= 0 : self.matches('^[0-9]+%$')",message="maxUnavailable must be a non-negative integer or a percentage"
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// HealthCheck configures the HTTP endpoint the packaged controller serves
+	// its health on. When set, it's used for the readiness and liveness
+	// probes of the provider container. The container has no probes if it
+	// is not set.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the provider's
+	// pods, which determines whether they're evicted or preempted before
+	// other pods. The PriorityClass must exist. Pods have the default
+	// priority if it is not set.
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// PodTemplateAnnotations are added to the provider's pods. They may be
+	// used to configure workload identity, for example to associate the
+	// provider's pods with a cloud provider service account. Annotations set
+	// by a ControllerConfig take precedence.
+	// +optional
+	PodTemplateAnnotations map[string]string `json:"podTemplateAnnotations,omitempty"`
+
+	// Tolerations of the provider's pods, which allow them to be scheduled
+	// on nodes with matching taints. They're merged with any tolerations set
+	// by a ControllerConfig.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector constrains the provider's pods to nodes with matching
+	// labels. It's merged with any node selector set by a ControllerConfig,
+	// and takes precedence where both set the same label.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity of the provider's pods, for example to co-locate the providers
+	// of a family on the same nodes. An affinity set by a ControllerConfig
+	// takes precedence.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// ReadinessGates of the provider's pods. A pod isn't considered ready
+	// until each of the pod conditions they name is true, in addition to
+	// passing its readiness probe.
+	// +optional
+	ReadinessGates []corev1.PodReadinessGate `json:"readinessGates,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds a newly created pod of
+	// the provider must be ready, without any of its containers crashing, to
+	// be considered available.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// Replicas is the number of desired pods of the provider. Defaults to 1.
+	// A ControllerConfig that specifies replicas takes precedence.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// LeaderElection indicates whether the provider should use leader
+	// election. It must be enabled for a provider with more than one replica
+	// to run safely.
+	// +optional
+	LeaderElection *bool `json:"leaderElection,omitempty"`
+
+	// ExtraVolumes are added to the provider's pods, for example to supply a
+	// custom CA bundle or configuration file.
+	// +optional
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// ExtraVolumeMounts are mounted into the provider's container. They
+	// typically mount ExtraVolumes.
+	// +optional
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
 }
 
 // A ControllerConfigReference to a ControllerConfig resource that will be used
@@ -60,10 +145,29 @@ type ControllerConfigReference struct {
 	Name string `json:"name"`
 }
 
+// A HealthCheck configures the HTTP endpoint a packaged controller serves its
+// health on.
+type HealthCheck struct {
+	// Path of the health endpoint, for example /healthz.
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+
+	// Port of the health endpoint.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+}
+
 // ProviderStatus represents the observed state of a Provider.
 type ProviderStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
 	PackageStatus          `json:",inline"`
+
+	// ProviderFamilyRef references the family this provider belongs to, as
+	// declared by the pkg.crossplane.io/provider-family label of its current
+	// revision. It is unset if the provider does not belong to a family.
+	// +optional
+	ProviderFamilyRef *corev1.LocalObjectReference `json:"providerFamilyRef,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,61 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	errListRevisions         = "cannot list package revisions"
+	errFmtUnknownPackageType = "unknown package type %q"
+)
+
+// ActiveRevisionObjects returns references to the objects installed by the
+// active revision of the supplied package. It returns an empty slice if the
+// package doesn't have an active revision yet.
+func ActiveRevisionObjects(ctx context.Context, c client.Reader, p Package) ([]xpv1.TypedReference, error) {
+	var l PackageRevisionList
+	switch t := p.GetPackageType(); t {
+	case ProviderPackageType:
+		l = &ProviderRevisionList{}
+	case ConfigurationPackageType:
+		l = &ConfigurationRevisionList{}
+	default:
+		return nil, errors.Errorf(errFmtUnknownPackageType, t)
+	}
+
+	if err := c.List(ctx, l, client.MatchingLabels{LabelParentPackage: p.GetName()}); err != nil {
+		return nil, errors.Wrap(err, errListRevisions)
+	}
+
+	for _, r := range l.GetRevisions() {
+		if r.GetDesiredState() != PackageRevisionActive {
+			continue
+		}
+		if objs := r.GetObjects(); objs != nil {
+			return objs, nil
+		}
+		break
+	}
+	return []xpv1.TypedReference{}, nil
+}
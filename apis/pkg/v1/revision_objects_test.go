@@ -0,0 +1,124 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestActiveRevisionObjects(t *testing.T) {
+	errBoom := errors.New("boom")
+	objs := []xpv1.TypedReference{{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "cool.example.org"}}
+
+	type args struct {
+		client client.Reader
+		p      Package
+	}
+	type want struct {
+		objs []xpv1.TypedReference
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return an error if we can't list the package's revisions.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				p:      &Provider{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRevisions),
+			},
+		},
+		"NoActiveRevision": {
+			reason: "We should return an empty slice if the package has no active revision.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*ProviderRevisionList).Items = []ProviderRevision{{
+						Spec:   PackageRevisionSpec{DesiredState: PackageRevisionInactive},
+						Status: PackageRevisionStatus{ObjectRefs: objs},
+					}}
+					return nil
+				})},
+				p: &Provider{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				objs: []xpv1.TypedReference{},
+			},
+		},
+		"ActiveProviderRevision": {
+			reason: "We should return the objects of a provider's active revision.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*ProviderRevisionList).Items = []ProviderRevision{
+						{Spec: PackageRevisionSpec{DesiredState: PackageRevisionInactive}},
+						{
+							Spec:   PackageRevisionSpec{DesiredState: PackageRevisionActive},
+							Status: PackageRevisionStatus{ObjectRefs: objs},
+						},
+					}
+					return nil
+				})},
+				p: &Provider{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				objs: objs,
+			},
+		},
+		"ActiveConfigurationRevision": {
+			reason: "We should return the objects of a configuration's active revision.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*ConfigurationRevisionList).Items = []ConfigurationRevision{{
+						Spec:   PackageRevisionSpec{DesiredState: PackageRevisionActive},
+						Status: PackageRevisionStatus{ObjectRefs: objs},
+					}}
+					return nil
+				})},
+				p: &Configuration{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+			want: want{
+				objs: objs,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ActiveRevisionObjects(context.Background(), tc.args.client, tc.args.p)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nActiveRevisionObjects(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.objs, got); diff != "" {
+				t.Errorf("\n%s\nActiveRevisionObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
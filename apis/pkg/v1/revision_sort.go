@@ -0,0 +1,58 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"sort"
+)
+
+// SortRevisionsByNumber sorts the supplied revisions in place by their
+// revision number, in ascending order unless descending is true. Revisions
+// with the same number are sorted by name.
+func SortRevisionsByNumber(revs []PackageRevision, descending bool) {
+	sort.SliceStable(revs, func(i, j int) bool {
+		a, b := revs[i], revs[j]
+		if descending {
+			a, b = b, a
+		}
+		if a.GetRevision() != b.GetRevision() {
+			return a.GetRevision() < b.GetRevision()
+		}
+		return a.GetName() < b.GetName()
+	})
+}
+
+// SortRevisionsByCreationTimestamp sorts the supplied revisions in place by
+// their creation timestamp, in ascending order unless descending is true.
+// Creation timestamps have a resolution of one second, so revisions created
+// in the same second are sorted by revision number, then by name.
+func SortRevisionsByCreationTimestamp(revs []PackageRevision, descending bool) {
+	sort.SliceStable(revs, func(i, j int) bool {
+		a, b := revs[i], revs[j]
+		if descending {
+			a, b = b, a
+		}
+		ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+		if !ta.Equal(&tb) {
+			return ta.Before(&tb)
+		}
+		if a.GetRevision() != b.GetRevision() {
+			return a.GetRevision() < b.GetRevision()
+		}
+		return a.GetName() < b.GetName()
+	})
+}
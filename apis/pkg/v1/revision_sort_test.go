@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func revision(name string, n int64, created time.Time) PackageRevision {
+	return &ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(created)},
+		Spec:       PackageRevisionSpec{Revision: n},
+	}
+}
+
+func names(revs []PackageRevision) []string {
+	n := make([]string, len(revs))
+	for i := range revs {
+		n[i] = revs[i].GetName()
+	}
+	return n
+}
+
+func TestSortRevisionsByNumber(t *testing.T) {
+	now := time.Now()
+
+	type args struct {
+		revs       []PackageRevision
+		descending bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Ascending": {
+			reason: "Revisions should be sorted by ascending revision number.",
+			args: args{
+				revs: []PackageRevision{revision("b", 2, now), revision("c", 3, now), revision("a", 1, now)},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		"Descending": {
+			reason: "Revisions should be sorted by descending revision number.",
+			args: args{
+				revs:       []PackageRevision{revision("b", 2, now), revision("c", 3, now), revision("a", 1, now)},
+				descending: true,
+			},
+			want: []string{"c", "b", "a"},
+		},
+		"SameNumber": {
+			reason: "Revisions with the same number should be sorted by name.",
+			args: args{
+				revs: []PackageRevision{revision("b", 1, now), revision("a", 1, now)},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SortRevisionsByNumber(tc.args.revs, tc.args.descending)
+			if diff := cmp.Diff(tc.want, names(tc.args.revs)); diff != "" {
+				t.Errorf("\n%s\nSortRevisionsByNumber(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSortRevisionsByCreationTimestamp(t *testing.T) {
+	now := time.Now()
+	earlier := now.Add(-1 * time.Hour)
+	later := now.Add(1 * time.Hour)
+
+	type args struct {
+		revs       []PackageRevision
+		descending bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"Ascending": {
+			reason: "Revisions should be sorted by ascending creation timestamp, regardless of their revision number.",
+			args: args{
+				revs: []PackageRevision{revision("b", 1, now), revision("c", 2, later), revision("a", 3, earlier)},
+			},
+			want: []string{"a", "b", "c"},
+		},
+		"Descending": {
+			reason: "Revisions should be sorted by descending creation timestamp, regardless of their revision number.",
+			args: args{
+				revs:       []PackageRevision{revision("b", 1, now), revision("c", 2, later), revision("a", 3, earlier)},
+				descending: true,
+			},
+			want: []string{"c", "b", "a"},
+		},
+		"SameTimestamp": {
+			reason: "Revisions created at the same time should be sorted by revision number.",
+			args: args{
+				revs: []PackageRevision{revision("a", 2, now), revision("b", 1, now)},
+			},
+			want: []string{"b", "a"},
+		},
+		"SameTimestampAndNumber": {
+			reason: "Revisions created at the same time with the same number should be sorted by name.",
+			args: args{
+				revs: []PackageRevision{revision("b", 1, now), revision("a", 1, now)},
+			},
+			want: []string{"a", "b"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			SortRevisionsByCreationTimestamp(tc.args.revs, tc.args.descending)
+			if diff := cmp.Diff(tc.want, names(tc.args.revs)); diff != "" {
+				t.Errorf("\n%s\nSortRevisionsByCreationTimestamp(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
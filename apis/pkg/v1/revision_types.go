@@ -17,8 +17,12 @@ limitations under the License.
 package v1
 
 import (
+	admv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
@@ -65,6 +69,12 @@ type PackageRevisionSpec struct {
 	// based on the parent's RevisionHistoryLimit.
 	Revision int64 `json:"revision"`
 
+	// ExpiresAt is the time after which the revision is garbage collected if
+	// it is inactive. It is set when the revision is created, based on the
+	// parent's RevisionTTL. The revision does not expire if it is unset.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
 	// IgnoreCrossplaneConstraints indicates to the package manager whether to
 	// honor Crossplane version constrains specified by the package.
 	// Default is false.
@@ -97,6 +107,13 @@ type PackageRevisionSpec struct {
 	// +optional
 	CommonLabels map[string]string `json:"commonLabels,omitempty"`
 
+	// ObjectSelector selects which of the package's objects the package
+	// manager applies. By default all of the package's objects are applied.
+	// Objects that aren't selected are neither created nor updated, and any
+	// that already exist are left as they are.
+	// +optional
+	ObjectSelector *ObjectSelector `json:"objectSelector,omitempty"`
+
 	// ESSTLSSecretName is the secret name of the TLS certificates that will be used
 	// by the provider for External Secret Stores.
 	// +optional
@@ -111,12 +128,110 @@ type PackageRevisionSpec struct {
 	// certificates of the Provider.
 	// +optional
 	TLSClientSecretName *string `json:"tlsClientSecretName,omitempty"`
+
+	// VerificationCertRef references a Secret containing the certificate the
+	// Provider uses to verify the identity of Crossplane when Crossplane calls
+	// into it, in its tls.crt key. The Secret must be in the namespace
+	// Crossplane runs packages in; a Secret in any other namespace is
+	// rejected.
+	// +optional
+	VerificationCertRef *corev1.SecretReference `json:"verificationCertRef,omitempty"`
+
+	// MaxUnavailable is the maximum number of pods of the packaged controller
+	// Deployment that can be unavailable while it is rolled out.
+	// +optional
+	// +kubebuilder:validation:XIntOrString
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// HealthCheck configures the HTTP endpoint the packaged controller serves
+	// its health on, which is used for the readiness and liveness probes of
+	// the packaged controller Deployment.
+	// +optional
+	HealthCheck *HealthCheck `json:"healthCheck,omitempty"`
+
+	// PriorityClassName is the name of the PriorityClass of the pods of the
+	// packaged controller Deployment.
+	// +optional
+	PriorityClassName *string `json:"priorityClassName,omitempty"`
+
+	// PodTemplateAnnotations are added to the pods of the packaged controller
+	// Deployment.
+	// +optional
+	PodTemplateAnnotations map[string]string `json:"podTemplateAnnotations,omitempty"`
+
+	// Tolerations of the pods of the packaged controller Deployment.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// NodeSelector of the pods of the packaged controller Deployment.
+	// +optional
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Affinity of the pods of the packaged controller Deployment.
+	// +optional
+	Affinity *corev1.Affinity `json:"affinity,omitempty"`
+
+	// ReadinessGates of the pods of the packaged controller Deployment.
+	// +optional
+	ReadinessGates []corev1.PodReadinessGate `json:"readinessGates,omitempty"`
+
+	// MinReadySeconds is the minimum number of seconds a newly created pod of
+	// the packaged controller Deployment must be ready, without any of its
+	// containers crashing, to be considered available.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MinReadySeconds *int32 `json:"minReadySeconds,omitempty"`
+
+	// Replicas is the number of desired pods of the packaged controller
+	// Deployment. Defaults to 1. A ControllerConfig that specifies replicas
+	// takes precedence.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// LeaderElection indicates whether the packaged controller should use
+	// leader election. It must be enabled for a controller with more than one
+	// replica to run safely.
+	// +optional
+	LeaderElection *bool `json:"leaderElection,omitempty"`
+
+	// ExtraVolumes are added to the pod of the packaged controller
+	// Deployment, for example to supply a custom CA bundle or configuration
+	// file.
+	// +optional
+	ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty"`
+
+	// ExtraVolumeMounts are mounted into the container of the packaged
+	// controller Deployment. They typically mount ExtraVolumes.
+	// +optional
+	ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty"`
+
+	// AdmissionWebhookFailurePolicy overrides the failure policy of the
+	// admission webhooks of the packaged controller. Setting it to Ignore
+	// can avoid admission failures while a new revision's webhook server
+	// starts, for example during an upgrade. Defaults to the failure policy
+	// set by the package.
+	// +optional
+	// +kubebuilder:validation:Enum=Ignore;Fail
+	AdmissionWebhookFailurePolicy *admv1.FailurePolicyType `json:"admissionWebhookFailurePolicy,omitempty"`
+
+	// PodDisruptionBudget configures a PodDisruptionBudget for the packaged
+	// controller Deployment, which limits how many of its pods may be
+	// evicted at once, for example during a node drain. The budget's
+	// selector is ignored; it always selects the Deployment's pods.
+	// +optional
+	PodDisruptionBudget *policyv1.PodDisruptionBudgetSpec `json:"podDisruptionBudget,omitempty"`
 }
 
 // PackageRevisionStatus represents the observed state of a PackageRevision.
 type PackageRevisionStatus struct {
 	xpv1.ConditionedStatus `json:",inline"`
 
+	// ObservedGeneration is the generation of the revision's spec that its
+	// conditions were last set against.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// ControllerRef references the controller (e.g. Deployment), if any, that
 	// is responsible for reconciling the objects this package revision
 	// installed.
@@ -130,10 +245,161 @@ type PackageRevisionStatus struct {
 	InstalledDependencies int64 `json:"installedDependencies,omitempty"`
 	InvalidDependencies   int64 `json:"invalidDependencies,omitempty"`
 
+	// ResolvedDependencies records the version of the installed package that
+	// each of the package's direct dependencies resolved to.
+	// +optional
+	ResolvedDependencies []ResolvedDependency `json:"resolvedDependencies,omitempty"`
+
 	// PermissionRequests made by this package. The package declares that its
 	// controller needs these permissions to run. The RBAC manager is
 	// responsible for granting them.
 	PermissionRequests []rbacv1.PolicyRule `json:"permissionRequests,omitempty"`
+
+	// AcceptedPermissionRequests are the PermissionRequests the RBAC manager
+	// granted. They are included in the provider's system ClusterRole.
+	// +optional
+	AcceptedPermissionRequests []rbacv1.PolicyRule `json:"acceptedPermissionRequests,omitempty"`
+
+	// RejectedPermissionRequests are the PermissionRequests the RBAC manager
+	// refused to grant because they are not allowed by the cluster's
+	// permission requests policy. The provider runs without them.
+	// +optional
+	RejectedPermissionRequests []rbacv1.PolicyRule `json:"rejectedPermissionRequests,omitempty"`
+
+	// RequiredPermissions are the RBAC rules the package's controller will be
+	// granted if this revision is activated. They're derived from the package
+	// when it is parsed, so they may be reviewed before activation.
+	// +optional
+	RequiredPermissions []rbacv1.PolicyRule `json:"requiredPermissions,omitempty"`
+
+	// LastPullTime is the time at which the package image was last
+	// successfully pulled from its registry.
+	// +optional
+	LastPullTime *metav1.Time `json:"lastPullTime,omitempty"`
+
+	// CrossplaneVersionConstraint is the semantic version constraint on
+	// Crossplane declared by the package's metadata, if any.
+	// +optional
+	CrossplaneVersionConstraint string `json:"crossplaneVersionConstraint,omitempty"`
+
+	// InstalledVersion is the semantic version of the installed package,
+	// parsed from the tag of its source image. It is empty if the source is
+	// not tagged with a semantic version.
+	// +optional
+	InstalledVersion string `json:"installedVersion,omitempty"`
+
+	// ReconciliationFailureCount is the number of consecutive times the
+	// package revision has failed to reconcile. It is reset to zero when the
+	// package revision reconciles successfully.
+	// +optional
+	ReconciliationFailureCount int64 `json:"reconciliationFailureCount,omitempty"`
+
+	// ParseError describes why the package could not be parsed, if it could
+	// not. It is cleared once the package is parsed successfully.
+	// +optional
+	ParseError *PackageParseError `json:"parseError,omitempty"`
+
+	// PackageMeta summarizes the metadata the package declares in its
+	// crossplane.yaml, so that it may be inspected without pulling the
+	// package.
+	// +optional
+	PackageMeta *PackageMeta `json:"packageMeta,omitempty"`
+
+	// ConditionHistory records the package revision's most recent condition
+	// transitions, oldest first, to help debug conditions that flap. The
+	// number of transitions recorded is limited by the package manager.
+	// +optional
+	ConditionHistory []xpv1.Condition `json:"conditionHistory,omitempty"`
+}
+
+// A ResolvedDependency is a direct dependency of a package, and the version of
+// the installed package it resolved to.
+type ResolvedDependency struct {
+	// Package is the OCI image name of the dependency, without a tag or
+	// digest.
+	Package string `json:"package"`
+
+	// Constraint is the semantic version constraint the dependency must
+	// satisfy.
+	// +optional
+	Constraint string `json:"constraint,omitempty"`
+
+	// Resolved is the version of the installed package that the dependency
+	// resolved to. It is empty if the dependency is not installed.
+	// +optional
+	Resolved string `json:"resolved,omitempty"`
+}
+
+// PackageMeta summarizes the metadata a package declares in its
+// crossplane.yaml.
+type PackageMeta struct {
+	// Maintainer of the package, from its meta.crossplane.io/maintainer
+	// annotation.
+	// +optional
+	Maintainer string `json:"maintainer,omitempty"`
+
+	// Description of the package, from its meta.crossplane.io/description
+	// annotation.
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// Crossplane is the semantic version constraint on Crossplane declared by
+	// the package, if any.
+	// +optional
+	Crossplane string `json:"crossplane,omitempty"`
+
+	// DependsOn lists the packages the package declares it depends on.
+	// +optional
+	DependsOn []PackageMetaDependency `json:"dependsOn,omitempty"`
+}
+
+// A PackageMetaDependency is a dependency declared by a package.
+type PackageMetaDependency struct {
+	// Package is the OCI image name of the dependency.
+	Package string `json:"package"`
+
+	// Type is the type of the dependency, e.g. Provider or Configuration.
+	Type string `json:"type"`
+
+	// Version is the semantic version constraint the dependency must
+	// satisfy.
+	// +optional
+	Version string `json:"version,omitempty"`
+}
+
+// A PackageParsePhase is the phase of package parsing that failed.
+type PackageParsePhase string
+
+// Package parse phases.
+const (
+	// PackageParsePhaseParse indicates the package's contents could not be
+	// parsed, for example because they're not valid YAML or contain an
+	// object of an unknown kind.
+	PackageParsePhaseParse PackageParsePhase = "Parse"
+
+	// PackageParsePhaseMetadata indicates the package does not contain
+	// exactly one valid package metadata object (i.e. crossplane.yaml).
+	PackageParsePhaseMetadata PackageParsePhase = "Metadata"
+
+	// PackageParsePhaseLint indicates the package's contents were parsed,
+	// but are not valid for the kind of package, for example because it
+	// contains an invalid CRD or an object it may not install.
+	PackageParsePhaseLint PackageParsePhase = "Lint"
+)
+
+// A PackageParseError describes why a package could not be parsed. It allows
+// parse failures to be told apart without parsing condition messages.
+type PackageParseError struct {
+	// Phase of package parsing that failed.
+	// +kubebuilder:validation:Enum=Parse;Metadata;Lint
+	Phase PackageParsePhase `json:"phase"`
+
+	// Detail describes what failed.
+	Detail string `json:"detail"`
+
+	// CausedBy is the underlying error that caused parsing to fail.
+	// +optional
+	CausedBy string `json:"causedBy,omitempty"`
 }
 
 // A ControllerReference references the controller (e.g. Deployment), if any,
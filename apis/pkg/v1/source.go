@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+)
+
+// EffectiveSource returns the supplied package source, rewritten according to
+// the supplied map of source prefixes to their mirrors. The longest prefix
+// that matches the source is replaced by its mirror. A prefix only matches at
+// a boundary of the source's repository path, so xpkg.example.org matches
+// xpkg.example.org/cool:v1 but not xpkg.example.org.evil/cool:v1. The source
+// is returned unchanged if no prefix matches.
+func EffectiveSource(source string, mirrors map[string]string) string {
+	longest := ""
+	for prefix := range mirrors {
+		if len(prefix) <= len(longest) || !hasSourcePrefix(source, prefix) {
+			continue
+		}
+		longest = prefix
+	}
+	if longest == "" {
+		return source
+	}
+	return mirrors[longest] + strings.TrimPrefix(source, longest)
+}
+
+func hasSourcePrefix(source, prefix string) bool {
+	if prefix == "" || !strings.HasPrefix(source, prefix) {
+		return false
+	}
+	if len(source) == len(prefix) || strings.HasSuffix(prefix, "/") {
+		return true
+	}
+	switch source[len(prefix)] {
+	case '/', ':', '@':
+		return true
+	}
+	return false
+}
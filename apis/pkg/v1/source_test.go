@@ -0,0 +1,96 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestEffectiveSource(t *testing.T) {
+	type args struct {
+		source  string
+		mirrors map[string]string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   string
+	}{
+		"NoMirrors": {
+			reason: "The source should be unchanged if there are no mirrors.",
+			args: args{
+				source: "xpkg.example.org/cool/provider:v1",
+			},
+			want: "xpkg.example.org/cool/provider:v1",
+		},
+		"NoMatch": {
+			reason: "The source should be unchanged if no prefix matches.",
+			args: args{
+				source:  "xpkg.example.org/cool/provider:v1",
+				mirrors: map[string]string{"index.docker.io": "mirror.internal"},
+			},
+			want: "xpkg.example.org/cool/provider:v1",
+		},
+		"Registry": {
+			reason: "A matching registry should be replaced by its mirror.",
+			args: args{
+				source:  "xpkg.example.org/cool/provider:v1",
+				mirrors: map[string]string{"xpkg.example.org": "mirror.internal"},
+			},
+			want: "mirror.internal/cool/provider:v1",
+		},
+		"LongestPrefix": {
+			reason: "The longest matching prefix should be replaced by its mirror.",
+			args: args{
+				source: "xpkg.example.org/cool/provider:v1",
+				mirrors: map[string]string{
+					"xpkg.example.org":      "mirror.internal",
+					"xpkg.example.org/cool": "mirror.internal/cooler",
+				},
+			},
+			want: "mirror.internal/cooler/provider:v1",
+		},
+		"NotAtBoundary": {
+			reason: "A prefix should only match at a boundary of the source's repository path.",
+			args: args{
+				source:  "xpkg.example.org.evil/cool/provider:v1",
+				mirrors: map[string]string{"xpkg.example.org": "mirror.internal"},
+			},
+			want: "xpkg.example.org.evil/cool/provider:v1",
+		},
+		"WholeRepository": {
+			reason: "A prefix that is a whole repository should match a source with a digest.",
+			args: args{
+				source:  "xpkg.example.org/cool/provider@sha256:c5ea",
+				mirrors: map[string]string{"xpkg.example.org/cool/provider": "mirror.internal/provider"},
+			},
+			want: "mirror.internal/provider@sha256:c5ea",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := EffectiveSource(tc.args.source, tc.args.mirrors)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nEffectiveSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -149,6 +149,15 @@ type ControllerConfigSpec struct {
 	// Cannot be updated.
 	// +optional
 	VolumeMounts []corev1.VolumeMount `json:"volumeMounts,omitempty"`
+	// RBACNamespaces scopes the provider to the supplied namespaces. If
+	// specified, the RBAC manager grants the provider access to the namespaced
+	// resources it needs using a Role and RoleBinding in each of these
+	// namespaces, rather than a ClusterRole and ClusterRoleBinding. The
+	// provider is still granted access to the cluster scoped resources it
+	// defines using a ClusterRole and ClusterRoleBinding. The provider must be
+	// configured to watch only these namespaces, for example using Args.
+	// +optional
+	RBACNamespaces []string `json:"rbacNamespaces,omitempty"`
 }
 
 // PodObjectMeta is metadata that is added to the Pods in a provider's
@@ -45,7 +45,9 @@ type LockPackage struct {
 	// Source is the OCI image name without a tag or digest.
 	Source string `json:"source"`
 
-	// Version is the tag or digest of the OCI image.
+	// Version is the semantic version of the package, parsed from the tag of
+	// its OCI image. It's the image's tag or digest if the tag isn't a
+	// semantic version, and empty for packages read from a ConfigMap.
 	Version string `json:"version"`
 
 	// Dependencies are the list of dependencies of this package. The order of
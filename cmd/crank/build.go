@@ -20,6 +20,8 @@ import (
 	"context"
 	"path/filepath"
 
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/spf13/afero"
 
@@ -35,6 +37,11 @@ const (
 	errBuildPackage    = "failed to build package"
 	errImageDigest     = "failed to get package digest"
 	errCreatePackage   = "failed to create package file"
+	errGetDependencies = "failed to get dependencies from crossplane.yaml"
+	errFmtResolveDep   = "failed to resolve dependency %d"
+	errFmtFetchDep     = "failed to fetch dependency %s"
+	errEmbedDeps       = "failed to record embedded dependencies"
+	errPackageTag      = "failed to tag package image"
 )
 
 // buildCmd builds a package.
@@ -42,8 +49,9 @@ type buildCmd struct {
 	Configuration buildConfigCmd   `cmd:"" help:"Build a Configuration package."`
 	Provider      buildProviderCmd `cmd:"" help:"Build a Provider package."`
 
-	PackageRoot string   `short:"f" help:"Path to package directory." default:"."`
-	Ignore      []string `help:"Paths, specified relative to --package-root, to exclude from the package."`
+	PackageRoot       string   `short:"f" help:"Path to package directory." default:"."`
+	Ignore            []string `help:"Paths, specified relative to --package-root, to exclude from the package."`
+	EmbedDependencies bool     `help:"Pull the package's dependencies and write them to the package file, for installing the package without access to their registries."`
 }
 
 // Run runs the build cmd.
@@ -75,6 +83,14 @@ func (c *buildCmd) Run(child *buildChild, logger logging.Logger) error {
 	}
 	logger.Debug("Successfully built package")
 
+	var deps map[name.Tag]v1.Image
+	if c.EmbedDependencies {
+		img, deps, err = embedDependencies(context.Background(), child, img, filepath.Join(root, xpkg.MetaFile), logger)
+		if err != nil {
+			return err
+		}
+	}
+
 	hash, err := img.Digest()
 	if err != nil {
 		logger.Debug(errImageDigest, "error", err)
@@ -99,13 +115,80 @@ func (c *buildCmd) Run(child *buildChild, logger logging.Logger) error {
 	}
 	logger.Debug("Successfully created package image file")
 	defer func() { _ = f.Close() }()
-	if err := tarball.Write(nil, img, f); err != nil {
+	if len(deps) == 0 {
+		if err := tarball.Write(nil, img, f); err != nil {
+			logger.Debug("Failed to write package image", "error", err)
+			return err
+		}
+		return nil
+	}
+
+	// A package file with embedded dependencies holds several images, so each
+	// must be tagged. The package's tag is only used to find it in the file;
+	// push identifies it by the dependencies it records.
+	tag, err := name.NewTag(pkgName)
+	if err != nil {
+		logger.Debug(errPackageTag, "error", err)
+		return errors.Wrap(err, errPackageTag)
+	}
+	refs := map[name.Reference]v1.Image{tag: img}
+	for t, dimg := range deps {
+		refs[t] = dimg
+	}
+	if err := tarball.MultiRefWrite(refs, f); err != nil {
 		logger.Debug("Failed to write package image", "error", err)
 		return err
 	}
 	return nil
 }
 
+// embedDependencies resolves each of the dependencies declared by the supplied
+// meta file to a digest, and fetches its image. It returns the package image
+// labelled with the resolved digests, and the dependency images keyed by the
+// tags they were resolved from.
+func embedDependencies(ctx context.Context, child *buildChild, img v1.Image, metaPath string, logger logging.Logger) (v1.Image, map[name.Tag]v1.Image, error) {
+	deps, err := xpkg.ParseDependenciesFromMeta(child.fs, metaPath)
+	if err != nil {
+		logger.Debug(errGetDependencies, "error", err)
+		return nil, nil, errors.Wrap(err, errGetDependencies)
+	}
+
+	f := child.fetcher
+	if f == nil {
+		// A fetcher without a Kubernetes client authenticates using the local
+		// Docker config and credential helpers.
+		if f, err = xpkg.NewK8sFetcher(nil); err != nil {
+			logger.Debug(errCreateFetcher, "error", err)
+			return nil, nil, errors.Wrap(err, errCreateFetcher)
+		}
+	}
+
+	imgs := make(map[name.Tag]v1.Image, len(deps))
+	digests := make([]name.Digest, 0, len(deps))
+	for i, dep := range deps {
+		r, err := xpkg.ResolveDependency(ctx, f, dep)
+		if err != nil {
+			logger.Debug("Failed to resolve dependency", "error", err)
+			return nil, nil, errors.Wrapf(err, errFmtResolveDep, i)
+		}
+		dimg, err := f.Fetch(ctx, r.Digest)
+		if err != nil {
+			logger.Debug("Failed to fetch dependency", "error", err)
+			return nil, nil, errors.Wrapf(err, errFmtFetchDep, r.Digest)
+		}
+		logger.Debug("Embedding dependency", "tag", r.Tag.String(), "digest", r.Digest.String())
+		imgs[r.Tag] = dimg
+		digests = append(digests, r.Digest)
+	}
+
+	img, err = xpkg.LabelEmbeddedDependencyDigests(img, digests)
+	if err != nil {
+		logger.Debug(errEmbedDeps, "error", err)
+		return nil, nil, errors.Wrap(err, errEmbedDeps)
+	}
+	return img, imgs, nil
+}
+
 // default build filters skip directories, empty files, and files without YAML
 // extension in addition to any paths specified.
 func buildFilters(root string, skips []string) []parser.FilterFn {
@@ -123,9 +206,10 @@ func buildFilters(root string, skips []string) []parser.FilterFn {
 }
 
 type buildChild struct {
-	name   string
-	linter parser.Linter
-	fs     afero.Fs
+	name    string
+	linter  parser.Linter
+	fs      afero.Fs
+	fetcher xpkg.Fetcher
 }
 
 // buildConfigCmd builds a Configuration.
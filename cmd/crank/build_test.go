@@ -21,6 +21,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/spf13/afero"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -29,13 +31,25 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
 )
 
+const testMetaWithDependencies = `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: test
+spec:
+  dependsOn:
+  - provider: xpkg.upbound.io/crossplane/provider-aws
+    version: ">=v1.0.0"
+`
+
 func TestBuild(t *testing.T) {
 	type args struct {
 		child  *buildChild
 		root   string
 		ignore []string
+		embed  bool
 	}
 
 	cases := map[string]struct {
@@ -54,6 +68,47 @@ func TestBuild(t *testing.T) {
 				root: "/",
 			},
 		},
+		"SuccessfulEmbedDependencies": {
+			reason: "We should write the package and its resolved dependencies to the package file.",
+			args: args{
+				child: &buildChild{
+					name:   "test",
+					linter: parser.NewPackageLinter(nil, nil, nil),
+					fs: func() afero.Fs {
+						fs := afero.NewMemMapFs()
+						_ = afero.WriteFile(fs, "/crossplane.yaml", []byte(testMetaWithDependencies), 0o600)
+						return fs
+					}(),
+					fetcher: &fake.MockFetcher{
+						MockTags:  fake.NewMockTagsFn([]string{"v1.0.0", "v1.1.0"}, nil),
+						MockHead:  fake.NewMockHeadFn(&v1.Descriptor{Digest: v1.Hash{Algorithm: "sha256", Hex: "8f7b3fc4bd7b6e0b9b6e8bdf9c2f5a5a1e2d6b0d5c0a9c8b7a6f5e4d3c2b1a09"}}, nil),
+						MockFetch: fake.NewMockFetchFn(empty.Image, nil),
+					},
+				},
+				root:  "/",
+				embed: true,
+			},
+		},
+		"ErrEmbedDependencies": {
+			reason: "We should return any error encountered resolving dependencies.",
+			args: args{
+				child: &buildChild{
+					name:   "test",
+					linter: parser.NewPackageLinter(nil, nil, nil),
+					fs: func() afero.Fs {
+						fs := afero.NewMemMapFs()
+						_ = afero.WriteFile(fs, "/crossplane.yaml", []byte(testMetaWithDependencies), 0o600)
+						return fs
+					}(),
+					fetcher: &fake.MockFetcher{
+						MockTags: fake.NewMockTagsFn([]string{"v0.1.0"}, nil),
+					},
+				},
+				root:  "/",
+				embed: true,
+			},
+			want: errors.Wrapf(errors.Errorf("no version of %s satisfies constraints %q", "xpkg.upbound.io/crossplane/provider-aws", ">=v1.0.0"), errFmtResolveDep, 0),
+		},
 		"ErrNoNameNoMeta": {
 			reason: "",
 			args: args{
@@ -70,8 +125,9 @@ func TestBuild(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			b := buildCmd{
-				PackageRoot: tc.args.root,
-				Ignore:      tc.args.ignore,
+				PackageRoot:       tc.args.root,
+				Ignore:            tc.args.ignore,
+				EmbedDependencies: tc.args.embed,
 			}
 			err := b.Run(tc.args.child, logging.NewNopLogger())
 
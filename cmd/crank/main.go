@@ -24,6 +24,7 @@ import (
 	"github.com/spf13/afero"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
 	"github.com/crossplane/crossplane/internal/version"
@@ -62,6 +63,7 @@ var cli struct {
 	Install installCmd `cmd:"" help:"Install Crossplane packages."`
 	Update  updateCmd  `cmd:"" help:"Update Crossplane packages."`
 	Push    pushCmd    `cmd:"" help:"Push Crossplane packages."`
+	Xpkg    xpkgCmd    `cmd:"" help:"Work with Crossplane packages."`
 }
 
 func main() {
@@ -81,5 +83,10 @@ func main() {
 		kong.BindTo(logger, (*logging.Logger)(nil)),
 		kong.UsageOnError())
 	err := ctx.Run()
+	var ec interface{ ExitCode() int }
+	if errors.As(err, &ec) {
+		ctx.Errorf("%s", err)
+		ctx.Exit(ec.ExitCode())
+	}
 	ctx.FatalIfErrorf(err)
 }
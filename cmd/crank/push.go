@@ -17,11 +17,17 @@ limitations under the License.
 package main
 
 import (
+	"io"
+	"net/http"
 	"os"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
 	"github.com/google/go-containerregistry/pkg/v1/tarball"
 	"github.com/spf13/afero"
 
@@ -34,6 +40,29 @@ import (
 const (
 	errGetwd           = "failed to get working directory while searching for package"
 	errFindPackageinWd = "failed to find a package in current working directory"
+	errLoadManifest    = "failed to load package file manifest"
+	errNoPackageImage  = "failed to find a package that records embedded dependencies in package file"
+	errParseCreated    = "failed to parse --created as an RFC 3339 timestamp"
+	errPushAuth        = "registry rejected credentials"
+	errPushTagConflict = "registry refused to update tag"
+)
+
+const (
+	// exitCodePushAuth is the exit code when a push fails because the
+	// registry rejected our credentials.
+	exitCodePushAuth = 2
+
+	// exitCodePushTagConflict is the exit code when a push fails because the
+	// registry refused to update a tag, for example because it is immutable.
+	exitCodePushTagConflict = 3
+)
+
+// OCI annotations that may be stamped onto a pushed package's manifest.
+// https://github.com/opencontainers/image-spec/blob/main/annotations.md
+const (
+	annotationRevision = "org.opencontainers.image.revision"
+	annotationSource   = "org.opencontainers.image.source"
+	annotationCreated  = "org.opencontainers.image.created"
 )
 
 // pushCmd pushes a package.
@@ -41,7 +70,28 @@ type pushCmd struct {
 	Configuration pushConfigCmd   `cmd:"" help:"Push a Configuration package."`
 	Provider      pushProviderCmd `cmd:"" help:"Push a Provider package."`
 
-	Package string `short:"f" help:"Path to package. If not specified and only one package exists in current directory it will be used."`
+	Package  string   `short:"f" help:"Path to package. If not specified and only one package exists in current directory it will be used. Dependencies embedded in the package are pushed to the same registry."`
+	Tags     []string `name:"tag" short:"t" help:"Additional tags to push the package to, in the same repository. May be repeated or comma separated. The package's contents are only uploaded once."`
+	Revision string   `help:"Source control revision the package was built from. Recorded as the org.opencontainers.image.revision annotation."`
+	Source   string   `help:"URL of the source code the package was built from. Recorded as the org.opencontainers.image.source annotation."`
+	Created  string   `help:"Time the package was built, as an RFC 3339 timestamp. Recorded as the org.opencontainers.image.created annotation."`
+}
+
+// An exitError is an error that should cause the command to exit with a
+// specific code.
+type exitError struct {
+	error
+	code int
+}
+
+// ExitCode returns the code the command should exit with.
+func (e *exitError) ExitCode() int {
+	return e.code
+}
+
+// Unwrap returns the underlying error.
+func (e *exitError) Unwrap() error {
+	return e.error
 }
 
 // Run runs the push cmd.
@@ -52,6 +102,14 @@ func (c *pushCmd) Run(child *pushChild, logger logging.Logger) error {
 		logger.Debug("Failed to create tag for package", "error", err)
 		return err
 	}
+	tags := []name.Tag{tag}
+	for _, t := range c.Tags {
+		tags = append(tags, tag.Context().Tag(t))
+	}
+	annotations, err := c.annotations()
+	if err != nil {
+		return err
+	}
 
 	// If package is not defined, attempt to find single package in current
 	// directory.
@@ -70,18 +128,127 @@ func (c *pushCmd) Run(child *pushChild, logger logging.Logger) error {
 		c.Package = path
 		logger.Debug("Found package in directory", "path", path)
 	}
-	img, err := tarball.ImageFromPath(c.Package, nil)
+	opener := func() (io.ReadCloser, error) { return child.fs.Open(c.Package) }
+	m, err := tarball.LoadManifest(opener)
+	if err != nil {
+		logger.Debug(errLoadManifest, "error", err)
+		return errors.Wrap(err, errLoadManifest)
+	}
+	if len(m) > 1 {
+		return pushEmbedded(opener, m, tags, annotations, logger)
+	}
+	img, err := tarball.Image(opener, nil)
 	if err != nil {
 		logger.Debug("Failed to create image from package tarball", "error", err)
 		return err
 	}
-	if err := remote.Write(tag, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+	return pushPackage(img, tags, annotations, logger)
+}
+
+// annotations returns the OCI annotations to stamp onto the pushed package.
+func (c *pushCmd) annotations() (map[string]string, error) {
+	a := map[string]string{}
+	if c.Revision != "" {
+		a[annotationRevision] = c.Revision
+	}
+	if c.Source != "" {
+		a[annotationSource] = c.Source
+	}
+	if c.Created != "" {
+		t, err := time.Parse(time.RFC3339, c.Created)
+		if err != nil {
+			return nil, errors.Wrap(err, errParseCreated)
+		}
+		a[annotationCreated] = t.Format(time.RFC3339)
+	}
+	return a, nil
+}
+
+// pushPackage pushes the supplied package image to each of the supplied tags,
+// stamping the supplied annotations onto its manifest. The package's blobs are
+// uploaded when it is pushed to the first tag; only its manifest is uploaded
+// for the remaining tags.
+func pushPackage(img v1.Image, tags []name.Tag, annotations map[string]string, logger logging.Logger) error {
+	if len(annotations) > 0 {
+		img = mutate.Annotations(img, annotations).(v1.Image)
+	}
+	if err := remote.Write(tags[0], img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
 		logger.Debug("Failed to push created image to remote location", "error", err)
-		return err
+		return pushError(err)
+	}
+	for _, t := range tags[1:] {
+		if err := remote.Tag(t, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+			logger.Debug("Failed to tag pushed image", "error", err, "tag", t.String())
+			return pushError(err)
+		}
 	}
 	return nil
 }
 
+// pushError returns an error that exits with a distinct code if the supplied
+// error indicates the registry rejected our credentials, or refused to update
+// a tag.
+func pushError(err error) error {
+	terr := &transport.Error{}
+	if !errors.As(err, &terr) {
+		return err
+	}
+	switch terr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &exitError{error: errors.Wrap(err, errPushAuth), code: exitCodePushAuth}
+	case http.StatusConflict, http.StatusPreconditionFailed:
+		return &exitError{error: errors.Wrap(err, errPushTagConflict), code: exitCodePushTagConflict}
+	}
+	for _, d := range terr.Errors {
+		if d.Code == transport.UnauthorizedErrorCode || d.Code == transport.DeniedErrorCode {
+			return &exitError{error: errors.Wrap(err, errPushAuth), code: exitCodePushAuth}
+		}
+	}
+	return err
+}
+
+// pushEmbedded pushes a package file that was built with its dependencies
+// embedded. Each dependency is pushed to the registry of the supplied tag,
+// keeping its repository and tag, e.g. xpkg.upbound.io/crossplane/provider-aws:v1.0.0
+// is pushed to registry.example.org/crossplane/provider-aws:v1.0.0. The
+// package itself is pushed to the supplied tags once all of its dependencies
+// have been pushed.
+func pushEmbedded(opener tarball.Opener, m tarball.Manifest, tags []name.Tag, annotations map[string]string, logger logging.Logger) error {
+	var pkg v1.Image
+	for _, d := range m {
+		if len(d.RepoTags) == 0 {
+			continue
+		}
+		t, err := name.NewTag(d.RepoTags[0])
+		if err != nil {
+			return err
+		}
+		img, err := tarball.Image(opener, &t)
+		if err != nil {
+			logger.Debug("Failed to create image from package tarball", "error", err, "image", t.String())
+			return err
+		}
+		_, isPkg, err := xpkg.EmbeddedDependencyDigests(img)
+		if err != nil {
+			return err
+		}
+		if isPkg {
+			pkg = img
+			continue
+		}
+		dst := tags[0].Context().Registry.Repo(t.RepositoryStr()).Tag(t.TagStr())
+		if err := remote.Write(dst, img, remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+			logger.Debug("Failed to push embedded dependency to remote location", "error", err, "tag", dst.String())
+			return pushError(err)
+		}
+		logger.Debug("Pushed embedded dependency", "tag", dst.String())
+	}
+	if pkg == nil {
+		return errors.New(errNoPackageImage)
+	}
+	return pushPackage(pkg, tags, annotations, logger)
+}
+
 type pushChild struct {
 	tag string
 	fs  afero.Fs
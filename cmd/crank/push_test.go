@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/spf13/afero"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+func TestPush(t *testing.T) {
+	// rejectManifest returns a handler that responds to any attempt to push a
+	// manifest for the supplied tag with the supplied status code.
+	rejectManifest := func(tag string, code int) func(http.Handler) http.Handler {
+		return func(h http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/manifests/"+tag) {
+					w.WriteHeader(code)
+					return
+				}
+				h.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	// pull pulls the image with the supplied tag.
+	pull := func(tag string) (v1.Image, error) {
+		ref, err := name.NewTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		return remote.Image(ref)
+	}
+
+	type want struct {
+		code        int
+		uploads     int32
+		annotations map[string]string
+	}
+
+	cases := map[string]struct {
+		reason  string
+		cmd     pushCmd
+		handler func(http.Handler) http.Handler
+		want    want
+	}{
+		"MultipleTags": {
+			reason: "We should upload the package's blobs once, tag its manifest with every tag, and stamp it with annotations.",
+			cmd: pushCmd{
+				Tags:     []string{"v1.2", "latest"},
+				Revision: "6c5b5a1",
+				Source:   "https://github.com/crossplane/example",
+				Created:  "2023-10-15T12:00:00Z",
+			},
+			want: want{
+				// One layer and one config blob.
+				uploads: 2,
+				annotations: map[string]string{
+					annotationRevision: "6c5b5a1",
+					annotationSource:   "https://github.com/crossplane/example",
+					annotationCreated:  "2023-10-15T12:00:00Z",
+				},
+			},
+		},
+		"AuthFailure": {
+			reason:  "We should exit with a distinct code when the registry rejects our credentials.",
+			cmd:     pushCmd{},
+			handler: rejectManifest("v1.2.3", http.StatusUnauthorized),
+			want:    want{code: exitCodePushAuth},
+		},
+		"TagConflict": {
+			reason:  "We should exit with a distinct code when the registry refuses to update a tag.",
+			cmd:     pushCmd{Tags: []string{"latest"}},
+			handler: rejectManifest("latest", http.StatusConflict),
+			want:    want{code: exitCodePushTagConflict},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var uploads int32
+			var reg http.Handler = registry.New(registry.Logger(log.New(io.Discard, "", 0)))
+			if tc.handler != nil {
+				reg = tc.handler(reg)
+			}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/blobs/uploads/") {
+					atomic.AddInt32(&uploads, 1)
+				}
+				reg.ServeHTTP(w, r)
+			}))
+			defer srv.Close()
+
+			fs := afero.NewMemMapFs()
+			img, err := random.Image(1024, 1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, err := fs.Create("/package.xpkg")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tarball.Write(nil, img, f); err != nil {
+				t.Fatal(err)
+			}
+			_ = f.Close()
+
+			repo := strings.TrimPrefix(srv.URL, "http://") + "/crossplane/example"
+			tc.cmd.Package = "/package.xpkg"
+			err = tc.cmd.Run(&pushChild{tag: repo + ":v1.2.3", fs: fs}, logging.NewNopLogger())
+
+			code := 0
+			if ee, ok := err.(*exitError); ok { //nolint:errorlint // We want exactly this type.
+				code = ee.ExitCode()
+			} else if err != nil {
+				t.Fatalf("\n%s\nRun(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.code, code); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want exit code, +got exit code:\n%s", tc.reason, diff)
+			}
+			if code != 0 {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want.uploads, atomic.LoadInt32(&uploads)); diff != "" {
+				t.Errorf("\n%s\nRun(...): -want blob uploads, +got blob uploads:\n%s", tc.reason, diff)
+			}
+			var digest string
+			for _, tag := range append([]string{"v1.2.3"}, tc.cmd.Tags...) {
+				pushed, err := pull(repo + ":" + tag)
+				if err != nil {
+					t.Fatalf("\n%s\nRun(...): tag %s was not pushed: %s", tc.reason, tag, err)
+				}
+				d, _ := pushed.Digest()
+				if digest != "" && d.String() != digest {
+					t.Errorf("\n%s\nRun(...): tag %s has digest %s, want %s", tc.reason, tag, d, digest)
+				}
+				digest = d.String()
+				m, err := pushed.Manifest()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if diff := cmp.Diff(tc.want.annotations, m.Annotations); diff != "" {
+					t.Errorf("\n%s\nRun(...): -want annotations, +got annotations:\n%s", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
@@ -74,8 +74,13 @@ func (c *updateConfigCmd) Run(k *kong.Context, logger logging.Logger) error {
 	} else {
 		newPkg = pkgReference.Context().Tag(c.Tag).Name()
 	}
-	prevConf.Spec.Package = newPkg
-	req, err := json.Marshal(prevConf)
+	desired := prevConf.DeepCopy()
+	desired.Spec.Package = newPkg
+	if v1.PackageSpecEqual(prevConf, desired) {
+		_, err = fmt.Fprintf(k.Stdout, "%s/%s unchanged\n", strings.ToLower(v1.ConfigurationGroupKind), prevConf.GetName())
+		return err
+	}
+	req, err := json.Marshal(desired)
 	if err != nil {
 		err = warnIfNotFound(err)
 		logger.Debug("Failed to update configuration", "error", err)
@@ -131,8 +136,13 @@ func (c *updateProviderCmd) Run(k *kong.Context, logger logging.Logger) error {
 	} else {
 		newPkg = pkgReference.Context().Tag(c.Tag).Name()
 	}
-	preProv.Spec.Package = newPkg
-	req, err := json.Marshal(preProv)
+	desired := preProv.DeepCopy()
+	desired.Spec.Package = newPkg
+	if v1.PackageSpecEqual(preProv, desired) {
+		_, err = fmt.Fprintf(k.Stdout, "%s/%s unchanged\n", strings.ToLower(v1.ProviderGroupKind), preProv.GetName())
+		return err
+	}
+	req, err := json.Marshal(desired)
 	if err != nil {
 		err = warnIfNotFound(err)
 		logger.Debug("Failed to update provider", "error", err)
@@ -0,0 +1,85 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alecthomas/kong"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	"github.com/crossplane/crossplane/internal/controller/pkg/revision"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errCreateFetcher = "cannot create package fetcher"
+	errDiffPackages  = "cannot diff packages"
+)
+
+// xpkgCmd works with Crossplane packages.
+type xpkgCmd struct {
+	Diff xpkgDiffCmd `cmd:"" help:"Show the objects added, removed, and changed by upgrading a package."`
+}
+
+// xpkgDiffCmd diffs the objects of two package images.
+type xpkgDiffCmd struct {
+	From string `arg:"" help:"Package image to upgrade from, e.g. crossplane/getting-started-with-aws:v1.0.0."`
+	To   string `arg:"" help:"Package image to upgrade to, e.g. crossplane/getting-started-with-aws:v1.1.0."`
+
+	Registry string `short:"r" help:"Default registry used to fetch packages when not specified in their image."`
+}
+
+// Run runs the xpkg diff cmd.
+func (c *xpkgDiffCmd) Run(k *kong.Context, logger logging.Logger) error {
+	logger = logger.WithValues("from", c.From, "to", c.To)
+
+	// We don't need a Kubernetes client to pull public packages, or packages
+	// we have local credentials for.
+	f, err := xpkg.NewK8sFetcher(nil)
+	if err != nil {
+		logger.Debug(errCreateFetcher, "error", err)
+		return errors.Wrap(err, errCreateFetcher)
+	}
+
+	added, removed, changed, err := revision.UpgradeDiff(context.Background(), f, c.Registry, c.From, c.To)
+	if err != nil {
+		logger.Debug(errDiffPackages, "error", err)
+		return errors.Wrap(err, errDiffPackages)
+	}
+	logger.Debug("Diffed packages", "added", len(added), "removed", len(removed), "changed", len(changed))
+
+	if len(added)+len(removed)+len(changed) == 0 {
+		_, err := fmt.Fprintln(k.Stdout, "No differences")
+		return err
+	}
+	for _, d := range []struct {
+		prefix string
+		refs   []xpv1.TypedReference
+	}{{"+", added}, {"-", removed}, {"~", changed}} {
+		for _, ref := range d.refs {
+			if _, err := fmt.Fprintf(k.Stdout, "%s %s/%s\n", d.prefix, ref.Kind, ref.Name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
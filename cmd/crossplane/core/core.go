@@ -22,13 +22,17 @@ import (
 	"net/http/pprof"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/afero"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/leaderelection/resourcelock"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -41,15 +45,20 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
-	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions"
 	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
 	"github.com/crossplane/crossplane/internal/controller/pkg"
 	pkgcontroller "github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/conversion/apiextensions/v1/xrd"
 	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/initializer"
 	"github.com/crossplane/crossplane/internal/transport"
+	"github.com/crossplane/crossplane/internal/usage"
+	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/claim"
 	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/composition"
+	"github.com/crossplane/crossplane/internal/validation/apiextensions/v1/definition"
+	"github.com/crossplane/crossplane/internal/validation/pkg/v1/source"
+	"github.com/crossplane/crossplane/internal/xcrd"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -88,6 +97,12 @@ type startCommand struct {
 	WebhookTLSCertDir    string `help:"The directory of TLS certificate that will be used by the webhook server of core Crossplane. There should be tls.crt and tls.key files." env:"WEBHOOK_TLS_CERT_DIR"`
 	UserAgent            string `help:"The User-Agent header that will be set on all package requests." default:"${default_user_agent}" env:"USER_AGENT"`
 
+	WebhookServiceName      string `help:"The name of the Service that serves the webhooks of core Crossplane. Required to convert composite resources and claims using field conversions." env:"WEBHOOK_SERVICE_NAME"`
+	WebhookServiceNamespace string `help:"The namespace of the Service that serves the webhooks of core Crossplane." env:"WEBHOOK_SERVICE_NAMESPACE"`
+	WebhookServicePort      int32  `help:"The port of the Service that serves the webhooks of core Crossplane." default:"9443" env:"WEBHOOK_SERVICE_PORT"`
+
+	PackageSourcePolicyConfigMap string `help:"The name of a ConfigMap in Crossplane's namespace that constrains the registries packages may be installed from." default:"package-source-policy" env:"PACKAGE_SOURCE_POLICY_CONFIG_MAP"`
+
 	SyncInterval        time.Duration `short:"s" help:"How often all resources will be double-checked for drift from the desired state." default:"1h"`
 	PollInterval        time.Duration `help:"How often individual resources will be checked for drift from the desired state." default:"1m"`
 	MaxReconcileRate    int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
@@ -98,10 +113,37 @@ type startCommand struct {
 	TLSClientSecretName string        `help:"The name of the TLS Secret that will be store Crossplane's client certificate." env:"TLS_CLIENT_SECRET_NAME"`
 	TLSClientCertsDir   string        `help:"The path of the folder which will store TLS client certificate of Crossplane." env:"TLS_CLIENT_CERTS_DIR"`
 
-	EnableEnvironmentConfigs                 bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
-	EnableExternalSecretStores               bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
-	EnableCompositionFunctions               bool `group:"Alpha Features:" help:"Enable support for Composition Functions."`
-	EnableCompositionWebhookSchemaValidation bool `group:"Alpha Features:" help:"Enable support for Composition validation using schemas."`
+	MaxExtraResources          int      `help:"The maximum number of extra resources that will be fetched to satisfy the requirements of a single Composition Function." default:"100"`
+	ExtraResourcesAllowedKinds []string `help:"Kinds of extra resources Composition Functions may require, in the form apiVersion/kind (e.g. v1/ConfigMap). Functions may require any kind if unset." placeholder:"apiVersion/kind"`
+
+	ComposedDeletionTimeout time.Duration `help:"How long to wait for a composed resource to be deleted before deleting the composed resources it depends on anyway." default:"5m"`
+
+	CompositionRevisionHistoryLimit int64 `help:"The maximum number of revisions of each Composition to keep. Revisions still used by a composite resource are never deleted. All revisions are kept if set to 0." default:"10"`
+
+	MaxComposedResourceWatches int `help:"The maximum number of kinds of composed resource that may be watched at once when realtime compositions are enabled. Composite resources that compose other kinds are polled. The number of watches is not limited if set to 0." default:"100"`
+
+	PropagateClaimMetadataPrefixes []string `help:"Key prefixes of claim labels and annotations that are propagated to composite and composed resources, in addition to any specified by an XRD."`
+
+	DisableCompositionMetricsLabel bool `help:"Don't label composite resource and claim metrics with the name of the Composition. Use this to bound the cardinality of metrics in control planes with many Compositions."`
+
+	PackageLockCompactionInterval time.Duration `help:"How often stale entries are removed from the package lock. The lock is not compacted if unset." default:"0"`
+
+	PackageRevisionEventCompactionWindow time.Duration `help:"Identical consecutive events about a package revision recorded within this window are collapsed into one. Events are not compacted if set to 0." default:"1m"`
+
+	PackageRevisionConditionHistoryLimit int `help:"The number of condition transitions recorded in the status of each package revision. Condition history is not recorded if set to 0." default:"10"`
+
+	RegistryAuthRefreshInterval time.Duration `help:"How long credentials read from package pull secrets are cached before they're read again. Credentials are read every time a package is pulled if set to 0." default:"0"`
+
+	EnableEnvironmentConfigs                   bool `group:"Alpha Features:" help:"Enable support for EnvironmentConfigs."`
+	EnableExternalSecretStores                 bool `group:"Alpha Features:" help:"Enable support for External Secret Stores."`
+	EnableCompositionFunctions                 bool `group:"Alpha Features:" help:"Enable support for Composition Functions."`
+	EnableCompositionWebhookSchemaValidation   bool `group:"Alpha Features:" help:"Enable support for Composition validation using schemas."`
+	EnableComposedResourceStatus               bool `group:"Alpha Features:" help:"Enable reporting the status of each composed resource in composite resource status."`
+	EnableClaimCrossNamespaceConnectionSecrets bool `group:"Alpha Features:" help:"Enable claims to write their connection secret to a namespace other than their own."`
+	EnableUsages                               bool `group:"Alpha Features:" help:"Enable support for deletion ordering and resource protection with Usages."`
+	EnableClaimValidationWebhook               bool `group:"Alpha Features:" help:"Enable validating claims against the schema of their XRD using a webhook."`
+	EnablePackageConfigMapSources              bool `group:"Alpha Features:" help:"Enable installing packages from a ConfigMap, using a source like configmap://namespace/name. Intended for testing."`
+	EnableRealtimeCompositions                 bool `group:"Alpha Features:" help:"Enable watching composed resources, so that composite resources are reconciled as soon as a composed resource changes rather than being polled."`
 
 	// These are GA features that previously had alpha or beta feature flags.
 	// You can't turn off a GA feature. We maintain the flags to avoid breaking
@@ -187,6 +229,30 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		feats.Enable(features.EnableAlphaCompositionWebhookSchemaValidation)
 		log.Info("Alpha feature enabled", "flag", features.EnableAlphaCompositionWebhookSchemaValidation)
 	}
+	if c.EnableComposedResourceStatus {
+		feats.Enable(features.EnableAlphaComposedResourceStatus)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaComposedResourceStatus)
+	}
+	if c.EnableClaimCrossNamespaceConnectionSecrets {
+		feats.Enable(features.EnableAlphaClaimCrossNamespaceConnectionSecrets)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaClaimCrossNamespaceConnectionSecrets)
+	}
+	if c.EnableUsages {
+		feats.Enable(features.EnableAlphaUsages)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaUsages)
+	}
+	if c.EnableClaimValidationWebhook {
+		feats.Enable(features.EnableAlphaClaimValidationWebhook)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaClaimValidationWebhook)
+	}
+	if c.EnablePackageConfigMapSources {
+		feats.Enable(features.EnableAlphaPackageConfigMapSources)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaPackageConfigMapSources)
+	}
+	if c.EnableRealtimeCompositions {
+		feats.Enable(features.EnableAlphaRealtimeCompositions)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaRealtimeCompositions)
+	}
 	if !c.EnableCompositionRevisions {
 		log.Info("CompositionRevisions feature is GA and cannot be disabled. The --enable-composition-revisions flag will be removed in a future release.")
 	}
@@ -215,11 +281,43 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		}
 	}
 
+	allowed := make([]schema.GroupVersionKind, 0, len(c.ExtraResourcesAllowedKinds))
+	for _, k := range c.ExtraResourcesAllowedKinds {
+		i := strings.LastIndex(k, "/")
+		if i < 1 || i == len(k)-1 {
+			return errors.Errorf("invalid extra resource kind %q: must be of the form apiVersion/kind", k)
+		}
+		allowed = append(allowed, schema.FromAPIVersionAndKind(k[:i], k[i+1:]))
+	}
+
 	ao := apiextensionscontroller.Options{
-		Options:        o,
-		Namespace:      c.Namespace,
-		ServiceAccount: c.ServiceAccount,
-		Registry:       c.Registry,
+		Options:                         o,
+		Namespace:                       c.Namespace,
+		ServiceAccount:                  c.ServiceAccount,
+		Registry:                        c.Registry,
+		MaxExtraResources:               c.MaxExtraResources,
+		ExtraResourcesAllowedKinds:      allowed,
+		ComposedDeletionTimeout:         c.ComposedDeletionTimeout,
+		CompositionRevisionHistoryLimit: c.CompositionRevisionHistoryLimit,
+		MaxComposedResourceWatches:      c.MaxComposedResourceWatches,
+		PropagateMetadataPrefixes:       c.PropagateClaimMetadataPrefixes,
+		DisableCompositionMetricsLabel:  c.DisableCompositionMetricsLabel,
+	}
+
+	if c.WebhookTLSCertDir != "" && c.WebhookServiceName != "" {
+		ca, err := os.ReadFile(filepath.Join(c.WebhookTLSCertDir, corev1.TLSCertKey))
+		if err != nil {
+			return errors.Wrap(err, "Cannot read webhook TLS certificate")
+		}
+		svc := extv1.ServiceReference{
+			Name:      c.WebhookServiceName,
+			Namespace: c.WebhookServiceNamespace,
+			Port:      &c.WebhookServicePort,
+		}
+		ao.ConversionWebhook = &xcrd.ConversionWebhook{Service: svc, CABundle: ca}
+		if feats.Enabled(features.EnableAlphaClaimValidationWebhook) {
+			ao.ClaimValidationWebhook = &xcrd.ClaimValidationWebhook{Service: svc, CABundle: ca}
+		}
 	}
 
 	if err := apiextensions.Setup(mgr, ao); err != nil {
@@ -233,10 +331,14 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		ServiceAccount:       c.ServiceAccount,
 		DefaultRegistry:      c.Registry,
 		Features:             feats,
-		FetcherOptions:       []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent)},
+		FetcherOptions:       []xpkg.FetcherOpt{xpkg.WithUserAgent(c.UserAgent), xpkg.WithAuthRefreshInterval(c.RegistryAuthRefreshInterval)},
 		WebhookTLSSecretName: c.WebhookTLSSecretName,
 		TLSServerSecretName:  c.TLSServerSecretName,
 		TLSClientSecretName:  c.TLSClientSecretName,
+
+		LockCompactionInterval:        c.PackageLockCompactionInterval,
+		RevisionEventCompactionWindow: c.PackageRevisionEventCompactionWindow,
+		RevisionConditionHistoryLimit: c.PackageRevisionConditionHistoryLimit,
 	}
 
 	if c.CABundlePath != "" {
@@ -257,12 +359,29 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error { //noli
 		// TODO(muvaf): Once the implementation of other webhook handlers are
 		// fleshed out, implement a registration pattern similar to scheme
 		// registrations.
-		if err := apiextensionsv1.SetupWebhookWithManager(mgr); err != nil {
+		if err := definition.SetupWebhookWithManager(mgr); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositeresourcedefinitions")
 		}
 		if err := composition.SetupWebhookWithManager(mgr, o); err != nil {
 			return errors.Wrap(err, "cannot setup webhook for compositions")
 		}
+		if err := xrd.SetupWebhookWithManager(mgr); err != nil {
+			return errors.Wrap(err, "cannot setup conversion webhook for composite resources and claims")
+		}
+		if o.Features.Enabled(features.EnableAlphaClaimValidationWebhook) {
+			if err := claim.SetupWebhookWithManager(mgr); err != nil {
+				return errors.Wrap(err, "cannot setup webhook for claims")
+			}
+		}
+		pg := source.NewConfigMapPolicyGetter(mgr.GetAPIReader(), types.NamespacedName{Namespace: c.Namespace, Name: c.PackageSourcePolicyConfigMap})
+		if err := source.SetupWebhookWithManager(mgr, pg, c.Registry); err != nil {
+			return errors.Wrap(err, "cannot setup webhook for packages")
+		}
+		if o.Features.Enabled(features.EnableAlphaUsages) {
+			if err := usage.SetupWebhookWithManager(mgr, log); err != nil {
+				return errors.Wrap(err, "cannot setup webhook for usages")
+			}
+		}
 	}
 
 	return errors.Wrap(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
@@ -81,6 +81,11 @@ type startCommand struct {
 	ManagementPolicy    string `name:"manage" short:"m" help:"RBAC management policy - Basic or All." default:"${rbac_manage_default_var}" enum:"${rbac_manage_enum_var}"`
 	Registry            string `short:"r" help:"Default registry used to fetch packages when not specified in tag." default:"${default_registry}" env:"REGISTRY"`
 
+	ProviderAllowedAPIGroups []string `name:"provider-allowed-api-groups" help:"API groups providers may be granted access to. Providers may be granted access to any API group if unset. Use \"\" or core for the core API group."`
+	ProviderDeniedAPIGroups  []string `name:"provider-denied-api-groups" help:"API groups providers may not be granted access to, even if they define or request them. Takes precedence over --provider-allowed-api-groups. Use \"\" or core for the core API group."`
+
+	DisableOfferedRoleBindings bool `name:"disable-offered-role-bindings" help:"Don't bind the ClusterRoles that grant access to offered claims in namespaces labelled rbac.crossplane.io/self-service. Only applies when the management policy is All."`
+
 	SyncInterval     time.Duration `short:"s" help:"How often all resources will be double-checked for drift from the desired state." default:"1h"`
 	PollInterval     time.Duration `help:"How often individual resources will be checked for drift from the desired state." default:"1m"`
 	MaxReconcileRate int           `help:"The global maximum rate per second at which resources may checked for drift from the desired state." default:"10"`
@@ -142,6 +147,11 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 		AllowClusterRole: c.ProviderClusterRole,
 		ManagementPolicy: rbaccontroller.ManagementPolicy(c.ManagementPolicy),
 		DefaultRegistry:  c.Registry,
+
+		AllowedProviderAPIGroups: apiGroups(c.ProviderAllowedAPIGroups),
+		DeniedProviderAPIGroups:  apiGroups(c.ProviderDeniedAPIGroups),
+
+		DisableOfferedRoleBindings: c.DisableOfferedRoleBindings,
 	}
 
 	if err := rbac.Setup(mgr, o); err != nil {
@@ -150,3 +160,17 @@ func (c *startCommand) Run(s *runtime.Scheme, log logging.Logger) error {
 
 	return errors.Wrap(mgr.Start(ctrl.SetupSignalHandler()), "cannot start controller manager")
 }
+
+// apiGroups returns the supplied API groups, replacing "core" with the empty
+// string that denotes the core API group in RBAC rules. It's hard to pass an
+// empty string as a flag value.
+func apiGroups(in []string) []string {
+	out := make([]string, len(in))
+	for i, g := range in {
+		if g == "core" {
+			g = ""
+		}
+		out[i] = g
+	}
+	return out
+}
@@ -29,10 +29,12 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 // Error strings.
@@ -41,8 +43,11 @@ const (
 	errBindClaimConflict    = "cannot bind claim that references a different composite resource"
 	errGetSecret            = "cannot get composite resource's connection secret"
 	errGetXRD               = "cannot get composite resource definition"
+	errGetNamespace         = "cannot get claim namespace"
 	errSecretConflict       = "cannot establish control of existing connection secret"
 	errCreateOrUpdateSecret = "cannot create or update connection secret"
+	errDeleteSecret         = "cannot delete connection secret"
+	errCrossNamespaceSecret = "cannot write connection secret to a namespace other than the claim's: cross namespace connection secrets are not enabled"
 
 	reasonCompositeDeletePolicy event.Reason = "CompositeDeletePolicy"
 )
@@ -83,14 +88,30 @@ func (a *APIBinder) Bind(ctx context.Context, cm resource.CompositeClaim, cp res
 // An APIConnectionPropagator propagates connection details by reading
 // them from and writing them to a Kubernetes API server.
 type APIConnectionPropagator struct {
-	client resource.ClientApplicator
+	client         resource.ClientApplicator
+	crossNamespace bool
+}
+
+// An APIConnectionPropagatorOption configures an APIConnectionPropagator.
+type APIConnectionPropagatorOption func(*APIConnectionPropagator)
+
+// WithCrossNamespaceConnectionSecrets allows claims to specify that their
+// connection secret should be written to a namespace other than their own.
+func WithCrossNamespaceConnectionSecrets() APIConnectionPropagatorOption {
+	return func(a *APIConnectionPropagator) {
+		a.crossNamespace = true
+	}
 }
 
 // NewAPIConnectionPropagator returns a new APIConnectionPropagator.
-func NewAPIConnectionPropagator(c client.Client) *APIConnectionPropagator {
-	return &APIConnectionPropagator{
+func NewAPIConnectionPropagator(c client.Client, o ...APIConnectionPropagatorOption) *APIConnectionPropagator {
+	a := &APIConnectionPropagator{
 		client: resource.ClientApplicator{Client: c, Applicator: resource.NewAPIUpdatingApplicator(c)},
 	}
+	for _, fn := range o {
+		fn(a)
+	}
+	return a
 }
 
 // PropagateConnection details from the supplied resource.
@@ -117,10 +138,18 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 	}
 
 	ts := resource.LocalConnectionSecretFor(to, to.GetObjectKind().GroupVersionKind())
+	owned := resource.ConnectionSecretMustBeControllableBy(to.GetUID())
+	if ns := connectionSecretNamespace(to); ns != to.GetNamespace() {
+		if !a.crossNamespace {
+			return false, errors.New(errCrossNamespaceSecret)
+		}
+		ts = crossNamespaceConnectionSecretFor(to, ns)
+		owned = connectionSecretMustBeFor(to)
+	}
 	ts.Data = fs.Data
 
 	err := a.client.Apply(ctx, ts,
-		resource.ConnectionSecretMustBeControllableBy(to.GetUID()),
+		owned,
 		resource.AllowUpdateIf(func(current, desired runtime.Object) bool {
 			// We consider the update to be a no-op and don't allow it if the
 			// current and existing secret data are identical.
@@ -138,6 +167,58 @@ func (a *APIConnectionPropagator) PropagateConnection(ctx context.Context, to re
 	return true, nil
 }
 
+// connectionSecretNamespace returns the namespace the supplied claim's
+// connection secret should be written to. This is the claim's own namespace
+// unless its connection secret reference specifies another.
+func connectionSecretNamespace(cm resource.LocalConnectionSecretOwner) string {
+	u, ok := cm.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return cm.GetNamespace()
+	}
+	ns, _ := fieldpath.Pave(u.UnstructuredContent()).GetString("spec.writeConnectionSecretToRef.namespace")
+	if ns == "" {
+		return cm.GetNamespace()
+	}
+	return ns
+}
+
+// crossNamespaceConnectionSecretFor returns a connection secret for the
+// supplied claim in the supplied namespace. Owner references can't cross
+// namespaces, so the secret is labelled with the claim it belongs to instead.
+func crossNamespaceConnectionSecretFor(cm resource.LocalConnectionSecretOwner, ns string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      cm.GetWriteConnectionSecretToReference().Name,
+			Labels: map[string]string{
+				xcrd.LabelKeyClaimName:      cm.GetName(),
+				xcrd.LabelKeyClaimNamespace: cm.GetNamespace(),
+			},
+		},
+		Type: resource.SecretTypeConnection,
+	}
+}
+
+// isConnectionSecretFor returns true if the supplied secret is labelled as
+// belonging to the supplied claim.
+func isConnectionSecretFor(s metav1.Object, cm resource.LocalConnectionSecretOwner) bool {
+	l := s.GetLabels()
+	return l[xcrd.LabelKeyClaimName] == cm.GetName() && l[xcrd.LabelKeyClaimNamespace] == cm.GetNamespace()
+}
+
+// connectionSecretMustBeFor requires that any existing connection secret is
+// labelled as belonging to the supplied claim. This prevents a claim from
+// overwriting a secret in another namespace that it does not own.
+func connectionSecretMustBeFor(cm resource.LocalConnectionSecretOwner) resource.ApplyOption {
+	return func(_ context.Context, current, _ runtime.Object) error {
+		s, ok := current.(*corev1.Secret)
+		if !ok || !isConnectionSecretFor(s, cm) {
+			return errors.New(errSecretConflict)
+		}
+		return nil
+	}
+}
+
 // NewAPIDefaultSelector returns a APIDefaultSelector.
 func NewAPIDefaultSelector(c client.Client, ref corev1.ObjectReference, r event.Recorder) *APIDefaultSelector {
 	return &APIDefaultSelector{client: c, defRef: ref, recorder: r}
@@ -165,3 +246,37 @@ func (s *APIDefaultSelector) SelectDefaults(ctx context.Context, cm resource.Com
 	s.recorder.Event(cm, event.Normal(reasonCompositeDeletePolicy, "Default composite delete policy has been selected"))
 	return nil
 }
+
+// NewAPINamespaceChecker returns an APINamespaceChecker.
+func NewAPINamespaceChecker(c client.Client, ref corev1.ObjectReference) *APINamespaceChecker {
+	return &APINamespaceChecker{client: c, defRef: ref}
+}
+
+// An APINamespaceChecker determines whether the claim namespaces of the
+// definition of a claim allow claims in its namespace.
+type APINamespaceChecker struct {
+	client client.Client
+	defRef corev1.ObjectReference
+}
+
+// NamespaceAllowed returns true if the definition of the supplied claim allows
+// claims in its namespace.
+func (c *APINamespaceChecker) NamespaceAllowed(ctx context.Context, cm resource.CompositeClaim) (bool, error) {
+	def := &v1.CompositeResourceDefinition{}
+	if err := c.client.Get(ctx, meta.NamespacedNameOf(&c.defRef), def); err != nil {
+		return false, errors.Wrap(err, errGetXRD)
+	}
+	cn := def.Spec.ClaimNamespaces
+	if cn == nil {
+		return true, nil
+	}
+	var l map[string]string
+	if cn.Selector != nil {
+		ns := &corev1.Namespace{}
+		if err := c.client.Get(ctx, types.NamespacedName{Name: cm.GetNamespace()}, ns); err != nil {
+			return false, errors.Wrap(err, errGetNamespace)
+		}
+		l = ns.GetLabels()
+	}
+	return cn.Allows(cm.GetNamespace(), l)
+}
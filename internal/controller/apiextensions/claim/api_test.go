@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -32,9 +33,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	"github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 var (
@@ -175,8 +178,27 @@ func TestPropagateConnection(t *testing.T) {
 		},
 	}
 
+	// A claim that wants its connection secret written to another namespace.
+	xcm := &claim.Unstructured{
+		Unstructured: unstructured.Unstructured{
+			Object: map[string]any{
+				"metadata": map[string]any{
+					"namespace": cmcsns,
+					"name":      "coolclaim",
+				},
+				"spec": map[string]any{
+					"writeConnectionSecretToRef": map[string]any{
+						"name":      cmcsname,
+						"namespace": "othernamespace",
+					},
+				},
+			},
+		},
+	}
+
 	type fields struct {
-		client resource.ClientApplicator
+		client         resource.ClientApplicator
+		crossNamespace bool
 	}
 
 	type args struct {
@@ -335,11 +357,75 @@ func TestPropagateConnection(t *testing.T) {
 				propagated: true,
 			},
 		},
+		"CrossNamespaceSecretNotEnabled": {
+			reason: "We should return an error if the claim wants its secret in another namespace, but cross namespace secrets are not enabled",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							s := resource.ConnectionSecretFor(cp, schema.GroupVersionKind{})
+							*o.(*corev1.Secret) = *s
+							return nil
+						}),
+					},
+				},
+			},
+			args: args{
+				to:   xcm,
+				from: cp,
+			},
+			want: want{
+				err: errors.New(errCrossNamespaceSecret),
+			},
+		},
+		"SuccessfulCrossNamespacePublish": {
+			reason: "The claim secret should be written to the namespace the claim specifies, labelled with the claim it belongs to",
+			fields: fields{
+				client: resource.ClientApplicator{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+							s := resource.ConnectionSecretFor(cp, schema.GroupVersionKind{})
+							s.Data = mgcsdata
+
+							*o.(*corev1.Secret) = *s
+							return nil
+						}),
+					},
+					Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+						want := &corev1.Secret{
+							ObjectMeta: metav1.ObjectMeta{
+								Namespace: "othernamespace",
+								Name:      cmcsname,
+								Labels: map[string]string{
+									xcrd.LabelKeyClaimName:      "coolclaim",
+									xcrd.LabelKeyClaimNamespace: cmcsns,
+								},
+							},
+							Type: resource.SecretTypeConnection,
+							Data: mgcsdata,
+						}
+						if diff := cmp.Diff(want, o); diff != "" {
+							t.Errorf("-want, +got:\n %s", diff)
+						}
+
+						return nil
+					}),
+				},
+				crossNamespace: true,
+			},
+			args: args{
+				to:   xcm,
+				from: cp,
+			},
+			want: want{
+				propagated: true,
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			api := &APIConnectionPropagator{client: tc.fields.client}
+			api := &APIConnectionPropagator{client: tc.fields.client, crossNamespace: tc.fields.crossNamespace}
 			got, err := api.PropagateConnection(tc.args.ctx, tc.args.to, tc.args.from)
 			if diff := cmp.Diff(tc.want.propagated, got); diff != "" {
 				t.Errorf("\n%s\napi.PropagateConnection(...): -want, +got:\n%s", tc.reason, diff)
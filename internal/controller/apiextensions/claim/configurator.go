@@ -52,14 +52,46 @@ const (
 // perform a dry-run create against an API server in order to name and validate
 // the configured resource.
 type APIDryRunCompositeConfigurator struct {
-	client client.Client
+	client    client.Client
+	propagate []string
+
+	enforcedRef    *corev1.ObjectReference
+	enforcedPolicy *xpv1.UpdatePolicy
+}
+
+// An APIDryRunCompositeConfiguratorOption configures an
+// APIDryRunCompositeConfigurator.
+type APIDryRunCompositeConfiguratorOption func(*APIDryRunCompositeConfigurator)
+
+// WithPropagatedMetadata configures the key prefixes of the claim
+// labels and annotations that the composite resource should track. A label or
+// annotation with one of these prefixes is removed from the composite resource
+// when it is removed from the claim.
+func WithPropagatedMetadata(p ...string) APIDryRunCompositeConfiguratorOption {
+	return func(c *APIDryRunCompositeConfigurator) {
+		c.propagate = p
+	}
+}
+
+// WithEnforcedComposition configures the Composition and composition update
+// policy that the composite resource must use, if any. These override any
+// composition reference, selector, or update policy specified by the claim.
+func WithEnforcedComposition(ref *corev1.ObjectReference, p *xpv1.UpdatePolicy) APIDryRunCompositeConfiguratorOption {
+	return func(c *APIDryRunCompositeConfigurator) {
+		c.enforcedRef = ref
+		c.enforcedPolicy = p
+	}
 }
 
 // NewAPIDryRunCompositeConfigurator returns a Configurator of composite
 // resources that may perform a dry-run create against an API server in order to
 // name and validate the configured resource.
-func NewAPIDryRunCompositeConfigurator(c client.Client) *APIDryRunCompositeConfigurator {
-	return &APIDryRunCompositeConfigurator{client: c}
+func NewAPIDryRunCompositeConfigurator(c client.Client, o ...APIDryRunCompositeConfiguratorOption) *APIDryRunCompositeConfigurator {
+	cc := &APIDryRunCompositeConfigurator{client: c}
+	for _, fn := range o {
+		fn(cc)
+	}
+	return cc
 }
 
 // Configure the supplied composite resource by propagating configuration from
@@ -94,7 +126,11 @@ func (c *APIDryRunCompositeConfigurator) Configure(ctx context.Context, cm resou
 	// external name.
 	en := meta.GetExternalName(ucp)
 
-	meta.AddAnnotations(ucp, ucm.GetAnnotations())
+	// Propagated labels and annotations are owned by the claim, so we remove
+	// any that are no longer present on the claim before we add those that
+	// are.
+	removeStaleMetadata(ucm, ucp, c.propagate)
+	meta.AddAnnotations(ucp, withoutPauseAnnotation(ucm.GetAnnotations()))
 	meta.AddLabels(ucp, cm.GetLabels())
 	meta.AddLabels(ucp, map[string]string{
 		xcrd.LabelKeyClaimName:      ucm.GetName(),
@@ -130,6 +166,18 @@ func (c *APIDryRunCompositeConfigurator) Configure(ctx context.Context, cm resou
 	claimSpecFilter := xcrd.GetPropFields(wellKnownClaimFields)
 	ucp.Object["spec"] = filter(spec, claimSpecFilter...)
 
+	// The composite resource's definition may enforce the Composition it
+	// uses. We override whatever the claim asked for here, rather than
+	// relying on the composite resource reconciler to do so, to avoid
+	// fighting over the composite resource's spec.
+	if c.enforcedRef != nil {
+		ucp.SetCompositionReference(c.enforcedRef)
+		delete(ucp.Object["spec"].(map[string]any), "compositionSelector")
+	}
+	if c.enforcedPolicy != nil {
+		ucp.SetCompositionUpdatePolicy(c.enforcedPolicy)
+	}
+
 	// Note that we overwrite the entire composite spec above, so we wait
 	// until this point to set the claim reference. We compute the reference
 	// earlier so we can return early if it would not be allowed.
@@ -148,6 +196,33 @@ func (c *APIDryRunCompositeConfigurator) Configure(ctx context.Context, cm resou
 	return nil
 }
 
+// withoutPauseAnnotation returns the supplied annotations without the pause
+// annotation. Pausing a claim pauses propagation to its composite resource,
+// but must not pause (or resume) the composite resource itself.
+func withoutPauseAnnotation(a map[string]string) map[string]string {
+	if _, ok := a[meta.AnnotationKeyReconciliationPaused]; !ok {
+		return a
+	}
+	out := make(map[string]string, len(a))
+	for k, v := range a {
+		if k == meta.AnnotationKeyReconciliationPaused {
+			continue
+		}
+		out[k] = v
+	}
+	return out
+}
+
+// removeStaleMetadata removes labels and annotations with a propagated key from
+// the supplied composite resource if they're not present on the supplied claim.
+func removeStaleMetadata(cm resource.CompositeClaim, cp resource.Composite, prefixes []string) {
+	if len(prefixes) == 0 {
+		return
+	}
+	meta.RemoveLabels(cp, xcrd.StaleMetadataKeys(cp.GetLabels(), cm.GetLabels(), prefixes)...)
+	meta.RemoveAnnotations(cp, xcrd.StaleMetadataKeys(cp.GetAnnotations(), cm.GetAnnotations(), prefixes)...)
+}
+
 func filter(in map[string]any, keys ...string) map[string]any {
 	filter := map[string]bool{}
 	for _, k := range keys {
@@ -169,12 +244,29 @@ func filter(in map[string]any, keys ...string) map[string]any {
 // from the composite. This includes late-initializing spec values
 // and updating status fields in claim.
 type APIClaimConfigurator struct {
-	client client.Client
+	client        client.Client
+	compositeOnly []string
+}
+
+// An APIClaimConfiguratorOption configures an APIClaimConfigurator.
+type APIClaimConfiguratorOption func(*APIClaimConfigurator)
+
+// WithCompositeOnlyStatusFields configures the status fields that Crossplane
+// adds to the composite resource but not to the claim. These fields are not
+// propagated from the composite resource to the claim.
+func WithCompositeOnlyStatusFields(f ...string) APIClaimConfiguratorOption {
+	return func(c *APIClaimConfigurator) {
+		c.compositeOnly = f
+	}
 }
 
 // NewAPIClaimConfigurator returns a APIClaimConfigurator.
-func NewAPIClaimConfigurator(client client.Client) *APIClaimConfigurator {
-	return &APIClaimConfigurator{client: client}
+func NewAPIClaimConfigurator(client client.Client, o ...APIClaimConfiguratorOption) *APIClaimConfigurator {
+	cc := &APIClaimConfigurator{client: client}
+	for _, fn := range o {
+		fn(cc)
+	}
+	return cc
 }
 
 // Configure the supplied claims with fields from the composite.
@@ -189,10 +281,14 @@ func (c *APIClaimConfigurator) Configure(ctx context.Context, cm resource.Compos
 		return nil
 	}
 
+	// The claim only summarizes the composite's composed resources, so we
+	// don't propagate status fields that only the composite has, like the
+	// state of each composed resource.
+	filter := append(xcrd.GetPropFields(xcrd.CompositeResourceStatusProps()), c.compositeOnly...)
 	if err := merge(ucm.Object["status"], ucp.Object["status"],
 		// Status fields from composite overwrite non-empty fields in claim
 		withMergeOptions(mergo.WithOverride),
-		withSrcFilter(xcrd.GetPropFields(xcrd.CompositeResourceStatusProps())...)); err != nil {
+		withSrcFilter(filter...)); err != nil {
 		return errors.Wrap(err, errMergeClaimStatus)
 	}
 
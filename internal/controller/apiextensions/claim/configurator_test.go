@@ -22,10 +22,12 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -59,6 +61,7 @@ func TestCompositeConfigure(t *testing.T) {
 	cases := map[string]struct {
 		reason string
 		c      client.Client
+		o      []APIDryRunCompositeConfiguratorOption
 		args   args
 		want   want
 	}{
@@ -372,6 +375,76 @@ func TestCompositeConfigure(t *testing.T) {
 				},
 			},
 		},
+		"PauseAnnotationNotPropagated": {
+			reason: "The pause annotation of a claim should not be propagated to its composite resource, which may be paused independently",
+			args: args{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"apiVersion": apiVersion,
+							"kind":       kind,
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+								"annotations": map[string]any{
+									meta.AnnotationKeyReconciliationPaused: "false",
+									"xrc":                                  "annotation",
+								},
+							},
+							"spec": map[string]any{},
+						},
+					},
+				},
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"name": name,
+								"creationTimestamp": func() string {
+									b, _ := now.MarshalJSON()
+									return strings.Trim(string(b), "\"")
+								}(),
+								"annotations": map[string]any{
+									meta.AnnotationKeyReconciliationPaused: "true",
+								},
+							},
+							"spec": map[string]any{},
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"name": name,
+								"creationTimestamp": func() string {
+									b, _ := now.MarshalJSON()
+									return strings.Trim(string(b), "\"")
+								}(),
+								"labels": map[string]any{
+									xcrd.LabelKeyClaimNamespace: ns,
+									xcrd.LabelKeyClaimName:      name,
+								},
+								"annotations": map[string]any{
+									meta.AnnotationKeyReconciliationPaused: "true",
+									"xrc":                                  "annotation",
+								},
+							},
+							"spec": map[string]any{
+								"claimRef": map[string]any{
+									"apiVersion": apiVersion,
+									"kind":       kind,
+									"namespace":  ns,
+									"name":       name,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"UpdatePolicyAutomatic": {
 			reason: "CompositionRevision of composite should NOT be set by the claim",
 			args: args{
@@ -588,11 +661,163 @@ func TestCompositeConfigure(t *testing.T) {
 				},
 			},
 		},
+		"RemoveStalePropagatedMetadata": {
+			reason: "Propagated labels and annotations that were removed from the claim should be removed from the composite.",
+			o:      []APIDryRunCompositeConfiguratorOption{WithPropagatedMetadata("example.org/")},
+			args: args{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"apiVersion": apiVersion,
+							"kind":       kind,
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+								"labels": map[string]any{
+									"example.org/cost-center": "cool",
+								},
+							},
+							"spec": map[string]any{},
+						},
+					},
+				},
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"name": name + "-12345",
+								"creationTimestamp": func() string {
+									b, _ := now.MarshalJSON()
+									return strings.Trim(string(b), "\"")
+								}(),
+								"labels": map[string]any{
+									"example.org/cost-center":   "lame",
+									"example.org/team":          "cool",
+									"other":                     "cool",
+									xcrd.LabelKeyClaimNamespace: ns,
+									xcrd.LabelKeyClaimName:      name,
+								},
+								"annotations": map[string]any{
+									"example.org/owner": "cool",
+								},
+							},
+							"spec": map[string]any{},
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"name": name + "-12345",
+								"creationTimestamp": func() string {
+									b, _ := now.MarshalJSON()
+									return strings.Trim(string(b), "\"")
+								}(),
+								"labels": map[string]any{
+									"example.org/cost-center":   "cool",
+									"other":                     "cool",
+									xcrd.LabelKeyClaimNamespace: ns,
+									xcrd.LabelKeyClaimName:      name,
+								},
+								"annotations": map[string]any{},
+							},
+							"spec": map[string]any{
+								"claimRef": map[string]any{
+									"apiVersion": apiVersion,
+									"kind":       kind,
+									"namespace":  ns,
+									"name":       name,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"EnforcedComposition": {
+			reason: "An enforced composition and update policy should override those requested by the claim.",
+			o: []APIDryRunCompositeConfiguratorOption{WithEnforcedComposition(&corev1.ObjectReference{Name: "enforced"}, func() *xpv1.UpdatePolicy {
+				p := xpv1.UpdateManual
+				return &p
+			}())},
+			args: args{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"apiVersion": apiVersion,
+							"kind":       kind,
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"spec": map[string]any{
+								"compositionRef": map[string]any{
+									"name": "requested",
+								},
+								"compositionSelector": map[string]any{
+									"matchLabels": map[string]any{
+										"cool": "true",
+									},
+								},
+								"compositionUpdatePolicy": "Automatic",
+							},
+						},
+					},
+				},
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"name": name + "-12345",
+								"creationTimestamp": func() string {
+									b, _ := now.MarshalJSON()
+									return strings.Trim(string(b), "\"")
+								}(),
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"name": name + "-12345",
+								"creationTimestamp": func() string {
+									b, _ := now.MarshalJSON()
+									return strings.Trim(string(b), "\"")
+								}(),
+								"labels": map[string]any{
+									xcrd.LabelKeyClaimNamespace: ns,
+									xcrd.LabelKeyClaimName:      name,
+								},
+							},
+							"spec": map[string]any{
+								"compositionRef": map[string]any{
+									"name": "enforced",
+								},
+								"compositionUpdatePolicy": "Manual",
+								"claimRef": map[string]any{
+									"apiVersion": apiVersion,
+									"kind":       kind,
+									"namespace":  ns,
+									"name":       name,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			c := NewAPIDryRunCompositeConfigurator(tc.c)
+			c := NewAPIDryRunCompositeConfigurator(tc.c, tc.o...)
 			got := c.Configure(tc.args.ctx, tc.args.cm, tc.args.cp)
 			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
 				t.Errorf("Configure(...): %s\n-want error, +got error:\n%s\n", tc.reason, diff)
@@ -614,6 +839,7 @@ func TestClaimConfigure(t *testing.T) {
 		cm     resource.CompositeClaim
 		cp     resource.Composite
 		client client.Client
+		o      []APIClaimConfiguratorOption
 	}
 
 	type want struct {
@@ -941,6 +1167,127 @@ func TestClaimConfigure(t *testing.T) {
 				},
 			},
 		},
+		"ConfigureStatusComposedResources": {
+			reason: "The composite's readiness summary should be propagated to the claim, but not its composed resource list",
+			args: args{
+				client: test.NewMockClient(),
+				o:      []APIClaimConfiguratorOption{WithCompositeOnlyStatusFields("resources")},
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"spec": map[string]any{
+								"resourceRef": "ref",
+							},
+							"status": map[string]any{},
+						},
+					},
+				},
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name + "-12345",
+							},
+							"status": map[string]any{
+								"resourcesReady": "1/2 resources ready",
+								"resources": []any{
+									map[string]any{
+										"name":  "cool-resource",
+										"ready": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"spec": map[string]any{
+								"resourceRef": "ref",
+							},
+							"status": map[string]any{
+								"resourcesReady": "1/2 resources ready",
+							},
+						},
+					},
+				},
+			},
+		},
+		"ConfigureStatusUnfilteredResources": {
+			reason: "Status fields that Crossplane doesn't add only to the composite, like a resources field defined by the XRD, should be propagated to the claim",
+			args: args{
+				client: test.NewMockClient(),
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"spec": map[string]any{
+								"resourceRef": "ref",
+							},
+							"status": map[string]any{},
+						},
+					},
+				},
+				cp: &composite.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name + "-12345",
+							},
+							"status": map[string]any{
+								"resourcesReady": "1/2 resources ready",
+								"resources": []any{
+									map[string]any{
+										"name":  "cool-resource",
+										"ready": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				cm: &claim.Unstructured{
+					Unstructured: unstructured.Unstructured{
+						Object: map[string]any{
+							"metadata": map[string]any{
+								"namespace": ns,
+								"name":      name,
+							},
+							"spec": map[string]any{
+								"resourceRef": "ref",
+							},
+							"status": map[string]any{
+								"resourcesReady": "1/2 resources ready",
+								"resources": []any{
+									map[string]any{
+										"name":  "cool-resource",
+										"ready": true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 		"UpdatePolicyManual": {
 			reason: "CompositionRevision of claim should NOT overwritten by the composite",
 			args: args{
@@ -1117,7 +1464,7 @@ func TestClaimConfigure(t *testing.T) {
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			c := NewAPIClaimConfigurator(tc.args.client)
+			c := NewAPIClaimConfigurator(tc.args.client, tc.args.o...)
 			got := c.Configure(context.Background(), tc.args.cm, tc.args.cp)
 			if diff := cmp.Diff(tc.want.err, got, test.EquateErrors()); diff != "" {
 				t.Errorf("c.Configure(...): %s\n-want error, +got error:\n%s\n", tc.reason, diff)
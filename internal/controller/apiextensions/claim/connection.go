@@ -3,7 +3,13 @@ package claim
 import (
 	"context"
 
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 )
@@ -48,6 +54,48 @@ func (u *SecretStoreConnectionUnpublisher) UnpublishConnection(ctx context.Conte
 	return u.publisher.UnpublishConnection(ctx, newClaimAsSecretOwner(so), c)
 }
 
+// An APICrossNamespaceConnectionUnpublisher deletes connection secrets that
+// claims wrote to a namespace other than their own. Secrets in the claim's
+// own namespace are owned by the claim, and thus garbage collected by
+// Kubernetes.
+type APICrossNamespaceConnectionUnpublisher struct {
+	client client.Client
+}
+
+// NewAPICrossNamespaceConnectionUnpublisher returns a new
+// APICrossNamespaceConnectionUnpublisher.
+func NewAPICrossNamespaceConnectionUnpublisher(c client.Client) *APICrossNamespaceConnectionUnpublisher {
+	return &APICrossNamespaceConnectionUnpublisher{client: c}
+}
+
+// UnpublishConnection deletes the supplied claim's connection secret if it was
+// written to a namespace other than the claim's.
+func (u *APICrossNamespaceConnectionUnpublisher) UnpublishConnection(ctx context.Context, so resource.LocalConnectionSecretOwner, _ managed.ConnectionDetails) error {
+	ref := so.GetWriteConnectionSecretToReference()
+	if ref == nil {
+		return nil
+	}
+	ns := connectionSecretNamespace(so)
+	if ns == so.GetNamespace() {
+		return nil
+	}
+
+	s := &corev1.Secret{}
+	err := u.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: ref.Name}, s)
+	if kerrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, errGetSecret)
+	}
+
+	// Don't delete a secret this claim didn't write.
+	if !isConnectionSecretFor(s, so) {
+		return nil
+	}
+	return errors.Wrap(resource.IgnoreNotFound(u.client.Delete(ctx, s)), errDeleteSecret)
+}
+
 // soClaim is a type that enables using claim type with Secret Store
 // UnpublishConnection method by satisfyng resource.ConnectionSecretOwner
 // interface.
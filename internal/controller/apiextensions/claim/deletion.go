@@ -0,0 +1,55 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+const errFmtGetComposed = "cannot get composed resource %s %q"
+
+// RemainingComposedResources returns the composed resources of the supplied
+// composite resource that still exist. A composite resource that is being
+// deleted in the foreground won't be gone until they are.
+func RemainingComposedResources(ctx context.Context, c client.Reader, cp resource.Composite) ([]*kunstructured.Unstructured, error) {
+	remaining := make([]*kunstructured.Unstructured, 0, len(cp.GetResourceReferences()))
+	for _, ref := range cp.GetResourceReferences() {
+		if ref.Name == "" {
+			continue
+		}
+		cd := &kunstructured.Unstructured{}
+		cd.SetGroupVersionKind(ref.GroupVersionKind())
+		err := c.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) || kmeta.IsNoMatchError(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtGetComposed, ref.Kind, ref.Name)
+		}
+		remaining = append(remaining, cd)
+	}
+	return remaining, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRemainingComposedResources(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := composite.New()
+	xr.SetResourceReferences([]corev1.ObjectReference{
+		{APIVersion: "example.org/v1", Kind: "Cool", Name: "stuck"},
+		{APIVersion: "example.org/v1", Kind: "Cool", Name: "gone"},
+		{APIVersion: "example.org/v1", Kind: "Cooler", Name: "unknown"},
+		{APIVersion: "example.org/v1", Kind: "Cool"},
+	})
+
+	stuck := &kunstructured.Unstructured{}
+	stuck.SetAPIVersion("example.org/v1")
+	stuck.SetKind("Cool")
+	stuck.SetName("stuck")
+
+	type want struct {
+		remaining []*kunstructured.Unstructured
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   want
+	}{
+		"GetError": {
+			reason: "We should return any error encountered getting a composed resource.",
+			c:      &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetComposed, "Cool", "stuck"),
+			},
+		},
+		"Remaining": {
+			reason: "We should return only the composed resources that still exist.",
+			c: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+				switch key.Name {
+				case "gone":
+					return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+				case "unknown":
+					return &kmeta.NoKindMatchError{}
+				}
+				obj.SetName(key.Name)
+				return nil
+			}},
+			want: want{
+				remaining: []*kunstructured.Unstructured{stuck},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := RemainingComposedResources(context.Background(), tc.c, xr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nRemainingComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.remaining, got); diff != "" {
+				t.Errorf("\n%s\nRemainingComposedResources(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+var reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "crossplane_claim_reconcile_duration_seconds",
+	Help:    "How long it took to reconcile a composite resource claim.",
+	Buckets: prometheus.DefBuckets,
+}, []string{"claim", "composite", "composition"})
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration)
+}
+
+// metricLabels returns the labels with which metrics about the supplied claim
+// are recorded. The name of the claim's Composition is omitted unless
+// composition is true.
+func metricLabels(cm resource.CompositeClaim, xr schema.GroupVersionKind, composition bool) []string {
+	name := ""
+	if ref := cm.GetCompositionReference(); ref != nil && composition {
+		name = ref.Name
+	}
+	return []string{kindLabel(cm.GetObjectKind().GroupVersionKind()), kindLabel(xr), name}
+}
+
+func kindLabel(gvk schema.GroupVersionKind) string {
+	return gvk.Kind + "." + gvk.GroupVersion().String()
+}
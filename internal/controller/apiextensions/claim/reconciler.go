@@ -19,6 +19,7 @@ package claim
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
@@ -41,11 +42,15 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/claim"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 const (
 	finalizer        = "finalizer.apiextensions.crossplane.io"
 	reconcileTimeout = 1 * time.Minute
+
+	defaultForegroundDeletionTimeout = 10 * time.Minute
 )
 
 // Error strings.
@@ -63,6 +68,11 @@ const (
 	errApplyComposite     = "cannot apply composite resource"
 	errConfigureClaim     = "cannot configure composite resource claim"
 	errPropagateCDs       = "cannot propagate connection details from composite"
+	errGetRemaining       = "cannot get remaining composed resources"
+	errCheckNamespace     = "cannot determine whether claims are allowed in this namespace"
+
+	errFmtStuckComposed  = "composed resource %s %q has not been deleted after %s; it may be blocked by its finalizers %v"
+	errFmtStuckComposite = "composite resource %q has not been deleted after %s; it may be blocked by its finalizers %v"
 
 	errUpdateClaimStatus = "cannot update composite resource claim status"
 )
@@ -76,6 +86,17 @@ const (
 	reasonClaimSelectDefaults event.Reason = "SelectClaimDefaults"
 	reasonPropagate           event.Reason = "PropagateConnectionSecret"
 	reasonPaused              event.Reason = "ReconciliationPaused"
+	reasonNamespace           event.Reason = "CheckClaimNamespace"
+)
+
+// TypeNamespaceAllowed indicates whether the composite resource claim's
+// CompositeResourceDefinition allows claims in its namespace.
+const TypeNamespaceAllowed xpv1.ConditionType = "NamespaceAllowed"
+
+// Reasons a composite resource claim is or is not allowed in its namespace.
+const (
+	ReasonNamespaceAllowed    xpv1.ConditionReason = "NamespaceAllowed"
+	ReasonNamespaceNotAllowed xpv1.ConditionReason = "NamespaceNotAllowed"
 )
 
 // ControllerName returns the recommended name for controllers that use this
@@ -164,6 +185,21 @@ func (fn ConnectionUnpublisherFn) UnpublishConnection(ctx context.Context, so re
 	return fn(ctx, so, c)
 }
 
+// A ConnectionUnpublisherChain runs multiple connection unpublishers.
+type ConnectionUnpublisherChain []ConnectionUnpublisher
+
+// UnpublishConnection details of a local connection secret owner. This method
+// calls UnpublishConnection for all ConnectionUnpublishers in the chain, and
+// returns the first error encountered.
+func (uc ConnectionUnpublisherChain) UnpublishConnection(ctx context.Context, so resource.LocalConnectionSecretOwner, c managed.ConnectionDetails) error {
+	for _, u := range uc {
+		if err := u.UnpublishConnection(ctx, so, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // A DefaultsSelector copies default values from the CompositeResourceDefinition when the corresponding field
 // in the Claim is not set.
 type DefaultsSelector interface {
@@ -179,6 +215,24 @@ func (fn DefaultsSelectorFn) SelectDefaults(ctx context.Context, cm resource.Com
 	return fn(ctx, cm)
 }
 
+// A NamespaceChecker determines whether a claim's CompositeResourceDefinition
+// allows claims in its namespace.
+type NamespaceChecker interface {
+	// NamespaceAllowed returns true if claims are allowed in the namespace of
+	// the supplied claim.
+	NamespaceAllowed(ctx context.Context, cm resource.CompositeClaim) (bool, error)
+}
+
+// A NamespaceCheckerFn determines whether a claim's
+// CompositeResourceDefinition allows claims in its namespace.
+type NamespaceCheckerFn func(ctx context.Context, cm resource.CompositeClaim) (bool, error)
+
+// NamespaceAllowed returns true if claims are allowed in the namespace of the
+// supplied claim.
+func (fn NamespaceCheckerFn) NamespaceAllowed(ctx context.Context, cm resource.CompositeClaim) (bool, error) {
+	return fn(ctx, cm)
+}
+
 // A Reconciler reconciles composite resource claims by creating exactly one kind of
 // concrete composite resource. Each composite resource claim kind should create an instance
 // of this controller for each composite resource kind they can bind to, using
@@ -199,6 +253,11 @@ type Reconciler struct {
 	log          logging.Logger
 	record       event.Recorder
 	pollInterval time.Duration
+
+	propagate []string
+
+	foregroundDeletionTimeout time.Duration
+	compositionLabel          bool
 }
 
 type crComposite struct {
@@ -219,6 +278,7 @@ type crClaim struct {
 	Configurator
 	ConnectionUnpublisher
 	DefaultsSelector
+	NamespaceChecker
 }
 
 func defaultCRClaim(c client.Client) crClaim {
@@ -227,6 +287,7 @@ func defaultCRClaim(c client.Client) crClaim {
 		Binder:                NewAPIBinder(c),
 		Configurator:          NewAPIClaimConfigurator(c),
 		ConnectionUnpublisher: NewNopConnectionUnpublisher(),
+		NamespaceChecker:      NamespaceCheckerFn(func(_ context.Context, _ resource.CompositeClaim) (bool, error) { return true, nil }),
 	}
 }
 
@@ -289,6 +350,14 @@ func WithDefaultsSelector(d DefaultsSelector) ReconcilerOption {
 	}
 }
 
+// WithNamespaceChecker specifies how the Reconciler should determine whether
+// claims are allowed in their namespace.
+func WithNamespaceChecker(c NamespaceChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.claim.NamespaceChecker = c
+	}
+}
+
 // WithClaimFinalizer specifies which ClaimFinalizer should be used to finalize
 // claims when they are deleted.
 func WithClaimFinalizer(f resource.Finalizer) ReconcilerOption {
@@ -322,6 +391,36 @@ func WithPollInterval(after time.Duration) ReconcilerOption {
 	}
 }
 
+// WithPropagatedMetadataPrefixes specifies the key prefixes of the claim labels
+// and annotations that are propagated to the composite resource. Propagated
+// labels and annotations that the composite Configurator removes from the
+// composite resource are removed when it is applied.
+func WithPropagatedMetadataPrefixes(p ...string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.propagate = p
+	}
+}
+
+// WithForegroundDeletionTimeout specifies how long the Reconciler should wait
+// for a composite resource that is being deleted in the foreground to be gone
+// before it emits events pointing at the resources that are blocking its
+// deletion. The Reconciler keeps waiting after the timeout. No events are
+// emitted if the timeout is zero.
+func WithForegroundDeletionTimeout(t time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.foregroundDeletionTimeout = t
+	}
+}
+
+// WithoutCompositionMetricsLabel configures the Reconciler not to label its
+// metrics with the name of the claim's Composition. This bounds the
+// cardinality of metrics in control planes with many Compositions.
+func WithoutCompositionMetricsLabel() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.compositionLabel = false
+	}
+}
+
 // NewReconciler returns a Reconciler that reconciles composite resource claims of
 // the supplied CompositeClaimKind with resources of the supplied CompositeKind.
 // The returned Reconciler will apply only the ObjectMetaConfigurator by
@@ -344,6 +443,9 @@ func NewReconciler(m manager.Manager, of resource.CompositeClaimKind, with resou
 		claim:     defaultCRClaim(c),
 		log:       logging.NewNopLogger(),
 		record:    event.NewNopRecorder(),
+
+		foregroundDeletionTimeout: defaultForegroundDeletionTimeout,
+		compositionLabel:          true,
 	}
 
 	for _, ro := range o {
@@ -359,6 +461,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
+	start := time.Now()
+
 	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
 	defer cancel()
 
@@ -370,6 +474,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetClaim)
 	}
 
+	xrKind := r.newComposite().GetObjectKind().GroupVersionKind()
+	defer func() {
+		reconcileDuration.WithLabelValues(metricLabels(cm, xrKind, r.compositionLabel)...).Observe(time.Since(start).Seconds())
+	}()
+
 	record := r.record.WithAnnotations("external-name", meta.GetExternalName(cm))
 	log = log.WithValues(
 		"uid", cm.GetUID(),
@@ -411,11 +520,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			if cdp := cm.GetCompositeDeletePolicy(); cdp != nil && *cdp == xpv1.CompositeDeleteForeground {
 				requiresForegroundDeletion = true
 			}
-			if meta.WasDeleted(cp) {
-				if requiresForegroundDeletion {
-					log.Debug("Waiting for the Composite to finish deleting (foreground deletion)")
-					return reconcile.Result{Requeue: true}, nil
-				}
+			if meta.WasDeleted(cp) && requiresForegroundDeletion {
+				return r.waitForComposite(ctx, cm, cp, log, record)
 			}
 			ref := cp.GetClaimReference()
 			want := meta.ReferenceTo(cm, cm.GetObjectKind().GroupVersionKind())
@@ -445,8 +551,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 				return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 			}
 			if requiresForegroundDeletion {
-				log.Debug("Requeue to wait for the Composite to finish deleting (foreground deletion)")
-				return reconcile.Result{Requeue: true}, nil
+				return r.waitForComposite(ctx, cm, cp, log, record)
 			}
 		}
 
@@ -493,6 +598,35 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
+	// Claims that are already bound to a composite resource in a namespace
+	// their XRD no longer allows continue to be reconciled, but are marked so
+	// that they may be moved. We don't create a composite resource for a claim
+	// in a namespace that isn't allowed.
+	allowed, err := r.claim.NamespaceAllowed(ctx, cm)
+	if err != nil {
+		log.Debug(errCheckNamespace, "error", err)
+		err = errors.Wrap(err, errCheckNamespace)
+		record.Event(cm, event.Warning(reasonNamespace, err))
+		cm.SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
+	}
+	switch {
+	case !allowed && cm.GetResourceReference() == nil:
+		log.Debug("Claims are not allowed in this namespace; not creating a composite resource")
+		record.Event(cm, event.Warning(reasonNamespace, errors.New("claims of this kind are not allowed in this namespace")))
+		cm.SetConditions(NamespaceNotAllowed())
+
+		// Check again later, in case the XRD is changed to allow claims in
+		// this namespace.
+		return reconcile.Result{RequeueAfter: r.pollInterval}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
+	case !allowed:
+		log.Debug("Claims are not allowed in this namespace")
+		record.Event(cm, event.Warning(reasonNamespace, errors.New("claims of this kind are no longer allowed in this namespace")))
+		cm.SetConditions(NamespaceNotAllowed())
+	case cm.GetCondition(TypeNamespaceAllowed).Reason == ReasonNamespaceNotAllowed:
+		cm.SetConditions(NamespaceAllowed())
+	}
+
 	if err := r.composite.Configure(ctx, cm, cp); err != nil {
 		log.Debug(errConfigureComposite, "error", err)
 		err = errors.Wrap(err, errConfigureComposite)
@@ -521,7 +655,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
 	}
 
-	err := r.client.Apply(ctx, cp, resource.AllowUpdateIf(func(old, obj runtime.Object) bool { return !cmp.Equal(old, obj) }))
+	ao := []resource.ApplyOption{resource.AllowUpdateIf(func(old, obj runtime.Object) bool { return !cmp.Equal(old, obj) })}
+	if len(r.propagate) > 0 {
+		ao = append(ao, xcrd.RemoveStaleMetadata(r.propagate...))
+	}
+	err = r.client.Apply(ctx, cp, ao...)
 	switch {
 	case resource.IsNotAllowed(err):
 		log.Debug("Skipped no-op composite resource apply")
@@ -590,3 +728,62 @@ func Waiting() xpv1.Condition {
 		Message:            "Composite resource claim is waiting for composite resource to become Ready",
 	}
 }
+
+// NamespaceAllowed returns a condition that indicates the composite resource
+// claim's CompositeResourceDefinition allows claims in its namespace.
+func NamespaceAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeNamespaceAllowed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNamespaceAllowed,
+	}
+}
+
+// NamespaceNotAllowed returns a condition that indicates the composite
+// resource claim's CompositeResourceDefinition doesn't allow claims in its
+// namespace. A claim that is bound to a composite resource continues to be
+// reconciled.
+func NamespaceNotAllowed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeNamespaceAllowed,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonNamespaceNotAllowed,
+		Message:            "Claims of this kind are not allowed in this namespace; the claim should be moved to an allowed namespace",
+	}
+}
+
+// waitForComposite waits for a composite resource that is being deleted in the
+// foreground to be gone, reporting how many of its composed resources remain.
+// If it takes too long we emit events pointing at the resources that are
+// probably blocking its deletion, typically because of a stuck finalizer.
+func (r *Reconciler) waitForComposite(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite, log logging.Logger, record event.Recorder) (reconcile.Result, error) {
+	remaining, err := RemainingComposedResources(ctx, r.client, cp)
+	if err != nil {
+		log.Debug(errGetRemaining, "error", err)
+		err = errors.Wrap(err, errGetRemaining)
+		record.Event(cm, event.Warning(reasonDelete, err))
+		cm.SetConditions(xpv1.ReconcileError(err))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
+	}
+
+	log.Debug("Waiting for the composite resource to finish deleting (foreground deletion)", "remaining", len(remaining))
+	cm.SetConditions(
+		xpv1.Deleting().WithMessage(fmt.Sprintf("Waiting for composite resource %q to be deleted; %d composed resources remain", cp.GetName(), len(remaining))),
+		xpv1.ReconcileSuccess(),
+	)
+
+	if dt := cp.GetDeletionTimestamp(); dt != nil && r.foregroundDeletionTimeout > 0 && time.Since(dt.Time) > r.foregroundDeletionTimeout {
+		for _, cd := range remaining {
+			record.Event(cm, event.Warning(reasonDelete, errors.Errorf(errFmtStuckComposed, cd.GetKind(), cd.GetName(), r.foregroundDeletionTimeout, cd.GetFinalizers())))
+		}
+		// If no composed resources remain the composite resource itself
+		// must be blocked.
+		if len(remaining) == 0 {
+			record.Event(cm, event.Warning(reasonDelete, errors.Errorf(errFmtStuckComposite, cp.GetName(), r.foregroundDeletionTimeout, cp.GetFinalizers())))
+		}
+	}
+
+	return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, cm), errUpdateClaimStatus)
+}
@@ -27,6 +27,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -302,6 +303,7 @@ func TestReconcile(t *testing.T) {
 							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
 								if o, ok := obj.(*composite.Unstructured); ok {
 									o.SetCreationTimestamp(metav1.Now())
+									o.SetName("cool-composite")
 									o.SetClaimReference(&corev1.ObjectReference{Name: name})
 								}
 								return nil
@@ -327,6 +329,7 @@ func TestReconcile(t *testing.T) {
 					o.SetResourceReference(&corev1.ObjectReference{})
 					fg := xpv1.CompositeDeleteForeground
 					o.SetCompositeDeletePolicy(&fg)
+					o.SetConditions(xpv1.Deleting().WithMessage(`Waiting for composite resource "cool-composite" to be deleted; 0 composed resources remain`), xpv1.ReconcileSuccess())
 				}),
 				r: reconcile.Result{Requeue: true},
 			},
@@ -343,6 +346,7 @@ func TestReconcile(t *testing.T) {
 								if o, ok := obj.(*composite.Unstructured); ok {
 									o.SetCreationTimestamp(now)
 									o.SetDeletionTimestamp(&now)
+									o.SetName("cool-composite")
 									o.SetClaimReference(&corev1.ObjectReference{Name: name})
 								}
 								return nil
@@ -368,6 +372,97 @@ func TestReconcile(t *testing.T) {
 					o.SetResourceReference(&corev1.ObjectReference{})
 					fg := xpv1.CompositeDeleteForeground
 					o.SetCompositeDeletePolicy(&fg)
+					o.SetConditions(xpv1.Deleting().WithMessage(`Waiting for composite resource "cool-composite" to be deleted; 0 composed resources remain`), xpv1.ReconcileSuccess())
+				}),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ForegroundDeleteWaitForComposedResources": {
+			reason: "We should report how many composed resources remain while waiting for a composite resource to be deleted in the foreground",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+								switch o := obj.(type) {
+								case *composite.Unstructured:
+									o.SetName("cool-composite")
+									o.SetCreationTimestamp(now)
+									o.SetDeletionTimestamp(&metav1.Time{Time: now.Add(-1 * time.Hour)})
+									o.SetClaimReference(&corev1.ObjectReference{Name: name})
+									o.SetResourceReferences([]corev1.ObjectReference{
+										{APIVersion: "example.org/v1", Kind: "Cool", Name: "stuck"},
+										{APIVersion: "example.org/v1", Kind: "Cool", Name: "gone"},
+									})
+								case *kunstructured.Unstructured:
+									if key.Name == "gone" {
+										return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+									}
+								}
+								return nil
+							},
+						},
+					}),
+				},
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetName(name)
+					o.SetDeletionTimestamp(&now)
+					fg := xpv1.CompositeDeleteForeground
+					o.SetCompositeDeletePolicy(&fg)
+					o.SetResourceReference(&corev1.ObjectReference{})
+				}),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetName(name)
+					o.SetDeletionTimestamp(&now)
+					o.SetResourceReference(&corev1.ObjectReference{})
+					fg := xpv1.CompositeDeleteForeground
+					o.SetCompositeDeletePolicy(&fg)
+					o.SetConditions(xpv1.Deleting().WithMessage(`Waiting for composite resource "cool-composite" to be deleted; 1 composed resources remain`), xpv1.ReconcileSuccess())
+				}),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"ForegroundDeleteGetRemainingError": {
+			reason: "We should return any error we encounter getting the composed resources of a composite resource being deleted in the foreground",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								switch o := obj.(type) {
+								case *composite.Unstructured:
+									o.SetCreationTimestamp(now)
+									o.SetDeletionTimestamp(&now)
+									o.SetClaimReference(&corev1.ObjectReference{Name: name})
+									o.SetResourceReferences([]corev1.ObjectReference{{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool"}})
+								case *kunstructured.Unstructured:
+									return errBoom
+								}
+								return nil
+							}),
+						},
+					}),
+				},
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetName(name)
+					o.SetDeletionTimestamp(&now)
+					fg := xpv1.CompositeDeleteForeground
+					o.SetCompositeDeletePolicy(&fg)
+					o.SetResourceReference(&corev1.ObjectReference{})
+				}),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetName(name)
+					o.SetDeletionTimestamp(&now)
+					o.SetResourceReference(&corev1.ObjectReference{})
+					fg := xpv1.CompositeDeleteForeground
+					o.SetCompositeDeletePolicy(&fg)
+					o.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errors.Wrapf(errBoom, errFmtGetComposed, "Cool", "cool"), errGetRemaining)))
 				}),
 				r: reconcile.Result{Requeue: true},
 			},
@@ -415,6 +510,99 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"CheckNamespaceError": {
+			reason: "We should return any error we encounter determining whether claims are allowed in the claim's namespace",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(ctx context.Context, obj resource.Object) error { return nil },
+					}),
+					WithDefaultsSelector(DefaultsSelectorFn(func(ctx context.Context, cm resource.CompositeClaim) error { return nil })),
+					WithNamespaceChecker(NamespaceCheckerFn(func(ctx context.Context, cm resource.CompositeClaim) (bool, error) { return false, errBoom })),
+				},
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{})
+				}),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{})
+					o.SetConditions(xpv1.ReconcileError(errors.Wrap(errBoom, errCheckNamespace)))
+				}),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"NamespaceNotAllowed": {
+			reason: "We should mark, but continue to reconcile, a claim in a namespace that is no longer allowed",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(ctx context.Context, obj resource.Object) error { return nil },
+					}),
+					WithCompositeConfigurator(ConfiguratorFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return errBoom })),
+					WithDefaultsSelector(DefaultsSelectorFn(func(ctx context.Context, cm resource.CompositeClaim) error { return nil })),
+					WithNamespaceChecker(NamespaceCheckerFn(func(ctx context.Context, cm resource.CompositeClaim) (bool, error) { return false, nil })),
+				},
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{})
+				}),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{})
+					o.SetConditions(NamespaceNotAllowed(), xpv1.ReconcileError(errors.Wrap(errBoom, errConfigureComposite)))
+				}),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"NamespaceNotAllowedUnbound": {
+			reason: "We should mark, and not create a composite resource for, a claim that isn't bound to one in a namespace that isn't allowed",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(ctx context.Context, obj resource.Object) error { return nil },
+					}),
+					WithCompositeConfigurator(ConfiguratorFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return errBoom })),
+					WithDefaultsSelector(DefaultsSelectorFn(func(ctx context.Context, cm resource.CompositeClaim) error { return nil })),
+					WithNamespaceChecker(NamespaceCheckerFn(func(ctx context.Context, cm resource.CompositeClaim) (bool, error) { return false, nil })),
+					WithPollInterval(time.Minute),
+				},
+				claim: withClaim(),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetConditions(NamespaceNotAllowed())
+				}),
+				r: reconcile.Result{RequeueAfter: time.Minute},
+			},
+		},
+		"NamespaceAllowedAgain": {
+			reason: "We should mark a claim whose namespace is allowed again",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClaimFinalizer(resource.FinalizerFns{
+						AddFinalizerFn: func(ctx context.Context, obj resource.Object) error { return nil },
+					}),
+					WithCompositeConfigurator(ConfiguratorFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return errBoom })),
+					WithDefaultsSelector(DefaultsSelectorFn(func(ctx context.Context, cm resource.CompositeClaim) error { return nil })),
+				},
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{})
+					o.SetConditions(NamespaceNotAllowed())
+				}),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{})
+					o.SetConditions(NamespaceAllowed(), xpv1.ReconcileError(errors.Wrap(errBoom, errConfigureComposite)))
+				}),
+				r: reconcile.Result{Requeue: true},
+			},
+		},
 
 		"ConfigureError": {
 			reason: "We should return any error we encounter configuring the composite resource",
@@ -686,6 +874,36 @@ func TestReconcile(t *testing.T) {
 				}),
 			},
 		},
+		"ReconciliationPausedDoesNotConfigureComposite": {
+			reason: `If a composite resource claim is paused, its composite resource should be neither configured nor paused.`,
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet:          test.NewMockGetFn(errBoom),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(c context.Context, r client.Object, ao ...resource.ApplyOption) error {
+							return errBoom
+						}),
+					}),
+					WithCompositeConfigurator(ConfiguratorFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return errBoom })),
+					WithBinder(BinderFn(func(ctx context.Context, cm resource.CompositeClaim, cp resource.Composite) error { return errBoom })),
+				},
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{Name: "cool-composite"})
+					o.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+				}),
+			},
+			want: want{
+				claim: withClaim(func(o *claim.Unstructured) {
+					o.SetResourceReference(&corev1.ObjectReference{Name: "cool-composite"})
+					o.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: "true"})
+					o.SetConditions(xpv1.ReconcilePaused())
+				}),
+			},
+		},
 		"ReconciliationResumes": {
 			reason: `If a composite resource claim has the pause annotation with some value other than "true" and the Synced=False/ReconcilePaused status condition, claim should acquire Synced=True/ReconcileSuccess.`,
 			args: args{
@@ -309,7 +309,9 @@ type EnforcedCompositionSelector struct {
 	recorder event.Recorder
 }
 
-// SelectComposition selects the enforced composition if it's given in definition.
+// SelectComposition selects the enforced composition if it's given in
+// definition. Any composition reference or selector specified by the composite
+// resource is overridden.
 func (s *EnforcedCompositionSelector) SelectComposition(_ context.Context, cp resource.Composite) error {
 	// We don't need to fetch the CompositeResourceDefinition at every reconcile
 	// because enforced composition ref is immutable as opposed to default
@@ -317,12 +319,18 @@ func (s *EnforcedCompositionSelector) SelectComposition(_ context.Context, cp re
 	if s.def.Spec.EnforcedCompositionRef == nil {
 		return nil
 	}
+	name := s.def.Spec.EnforcedCompositionRef.Name
+	meta.AddAnnotations(cp, map[string]string{AnnotationKeyEnforcedComposition: name})
+	if cp.GetCompositionSelector() != nil {
+		cp.SetCompositionSelector(nil)
+	}
+
 	// If the composition is already chosen, we don't need to check for compatibility
 	// as its target type reference is immutable.
-	if cp.GetCompositionReference() != nil && cp.GetCompositionReference().Name == s.def.Spec.EnforcedCompositionRef.Name {
+	if cp.GetCompositionReference() != nil && cp.GetCompositionReference().Name == name {
 		return nil
 	}
-	cp.SetCompositionReference(&corev1.ObjectReference{Name: s.def.Spec.EnforcedCompositionRef.Name})
+	cp.SetCompositionReference(&corev1.ObjectReference{Name: name})
 	s.recorder.Event(cp, event.Normal(reasonCompositionSelection, "Enforced composition has been selected"))
 	return nil
 }
@@ -400,6 +408,58 @@ func (c *APINamingConfigurator) Configure(ctx context.Context, cp resource.Compo
 	return errors.Wrap(c.client.Update(ctx, cp), errUpdateComposite)
 }
 
+// NewCompositionUpdatePolicySelectorChain returns a new
+// CompositionUpdatePolicySelectorChain.
+func NewCompositionUpdatePolicySelectorChain(list ...CompositionUpdatePolicySelector) *CompositionUpdatePolicySelectorChain {
+	return &CompositionUpdatePolicySelectorChain{list: list}
+}
+
+// CompositionUpdatePolicySelectorChain calls the given list of
+// CompositionUpdatePolicySelectors in order.
+type CompositionUpdatePolicySelectorChain struct {
+	list []CompositionUpdatePolicySelector
+}
+
+// SelectCompositionUpdatePolicy calls all SelectCompositionUpdatePolicy
+// functions of CompositionUpdatePolicySelectors in the list.
+func (r *CompositionUpdatePolicySelectorChain) SelectCompositionUpdatePolicy(ctx context.Context, cp resource.Composite) error {
+	for _, s := range r.list {
+		if err := s.SelectCompositionUpdatePolicy(ctx, cp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewEnforcedCompositionUpdatePolicySelector returns an
+// EnforcedCompositionUpdatePolicySelector.
+func NewEnforcedCompositionUpdatePolicySelector(def v1.CompositeResourceDefinition, r event.Recorder) *EnforcedCompositionUpdatePolicySelector {
+	return &EnforcedCompositionUpdatePolicySelector{def: def, recorder: r}
+}
+
+// EnforcedCompositionUpdatePolicySelector , if it's given, selects the enforced
+// composition update policy on the definition for all composite instances.
+type EnforcedCompositionUpdatePolicySelector struct {
+	def      v1.CompositeResourceDefinition
+	recorder event.Recorder
+}
+
+// SelectCompositionUpdatePolicy selects the enforced composition update policy
+// if it's given in definition. Any policy specified by the composite resource
+// is overridden.
+func (s *EnforcedCompositionUpdatePolicySelector) SelectCompositionUpdatePolicy(_ context.Context, cp resource.Composite) error {
+	p := s.def.Spec.EnforcedCompositionUpdatePolicy
+	if p == nil {
+		return nil
+	}
+	if cp.GetCompositionUpdatePolicy() != nil && *cp.GetCompositionUpdatePolicy() == *p {
+		return nil
+	}
+	cp.SetCompositionUpdatePolicy(p)
+	s.recorder.Event(cp, event.Normal(reasonCompositionUpdatePolicy, "Enforced composition update policy has been selected"))
+	return nil
+}
+
 // NewAPIDefaultCompositionUpdatePolicySelector returns a APIDefaultCompositionUpdatePolicySelector.
 func NewAPIDefaultCompositionUpdatePolicySelector(c client.Client, ref corev1.ObjectReference, r event.Recorder) *APIDefaultCompositionUpdatePolicySelector {
 	return &APIDefaultCompositionUpdatePolicySelector{client: c, defRef: ref, recorder: r}
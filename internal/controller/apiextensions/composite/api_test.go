@@ -899,6 +899,7 @@ func TestAPIEnforcedCompositionSelector(t *testing.T) {
 			},
 			want: want{
 				cp: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyEnforcedComposition: comp.Name}},
 					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
 				},
 			},
@@ -913,6 +914,7 @@ func TestAPIEnforcedCompositionSelector(t *testing.T) {
 			},
 			want: want{
 				cp: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyEnforcedComposition: comp.Name}},
 					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
 				},
 			},
@@ -929,6 +931,24 @@ func TestAPIEnforcedCompositionSelector(t *testing.T) {
 			},
 			want: want{
 				cp: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyEnforcedComposition: comp.Name}},
+					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
+				},
+			},
+		},
+		"SuccessOverrideSelector": {
+			reason: "Successfully set the enforced composition reference and remove any composition selector",
+			args: args{
+				def: v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{EnforcedCompositionRef: &v1.CompositionReference{Name: comp.Name}},
+				},
+				cp: &fake.Composite{
+					CompositionSelector: fake.CompositionSelector{Sel: &metav1.LabelSelector{MatchLabels: map[string]string{"cool": "true"}}},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta:            metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyEnforcedComposition: comp.Name}},
 					CompositionReferencer: fake.CompositionReferencer{Ref: &corev1.ObjectReference{Name: comp.Name}},
 				},
 			},
@@ -1093,3 +1113,77 @@ func TestAPIDefaultCompositionUpdatePolicySelector(t *testing.T) {
 		})
 	}
 }
+
+func TestEnforcedCompositionUpdatePolicySelector(t *testing.T) {
+	manual := xpv1.UpdateManual
+	auto := xpv1.UpdateAutomatic
+	type args struct {
+		def v1.CompositeResourceDefinition
+		cp  resource.Composite
+	}
+	type want struct {
+		cp  resource.Composite
+		err error
+	}
+	cases := map[string]struct {
+		reason string
+		args
+		want
+	}{
+		"NoEnforced": {
+			reason: "Should be no-op if no enforced composition update policy is given in definition",
+			args: args{
+				cp: &fake.Composite{
+					CompositionUpdater: fake.CompositionUpdater{Policy: &auto},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionUpdater: fake.CompositionUpdater{Policy: &auto},
+				},
+			},
+		},
+		"Success": {
+			reason: "Successfully set the enforced composition update policy",
+			args: args{
+				def: v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{EnforcedCompositionUpdatePolicy: &manual},
+				},
+				cp: &fake.Composite{},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionUpdater: fake.CompositionUpdater{Policy: &manual},
+				},
+			},
+		},
+		"SuccessOverride": {
+			reason: "Successfully set the enforced composition update policy even if another one was set",
+			args: args{
+				def: v1.CompositeResourceDefinition{
+					Spec: v1.CompositeResourceDefinitionSpec{EnforcedCompositionUpdatePolicy: &manual},
+				},
+				cp: &fake.Composite{
+					CompositionUpdater: fake.CompositionUpdater{Policy: &auto},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					CompositionUpdater: fake.CompositionUpdater{Policy: &manual},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewEnforcedCompositionUpdatePolicySelector(tc.args.def, event.NewNopRecorder())
+			err := c.SelectCompositionUpdatePolicy(context.Background(), tc.args.cp)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSelectCompositionUpdatePolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cp, tc.args.cp); diff != "" {
+				t.Errorf("\n%s\nSelectCompositionUpdatePolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
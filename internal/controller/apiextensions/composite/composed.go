@@ -16,6 +16,17 @@ specific language governing permissions and limitations under the License.
 package composite
 
 import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
@@ -34,6 +45,80 @@ type ComposedResource struct {
 	// Ready indicates whether this composed resource is ready - i.e. whether
 	// all of its readiness checks passed.
 	Ready bool
+
+	// Reference to the composed resource. It is empty if the composed resource
+	// has not yet been named.
+	Reference corev1.ObjectReference
+
+	// SyncError describes why this composed resource is not synced - either
+	// because it could not be rendered, or because its Synced condition is
+	// False. It is empty if the composed resource is synced.
+	SyncError string
+}
+
+// MaxComposedResourceMessageLength is the maximum length of the message
+// recorded for each composed resource in a composite resource's status.
+const MaxComposedResourceMessageLength = 256
+
+// A composedResourceStatus summarizes the state of a composed resource in the
+// status of its composite resource.
+type composedResourceStatus struct {
+	Name         string `json:"name"`
+	APIVersion   string `json:"apiVersion,omitempty"`
+	Kind         string `json:"kind,omitempty"`
+	ResourceName string `json:"resourceName,omitempty"`
+	Ready        bool   `json:"ready"`
+	Synced       bool   `json:"synced"`
+	Message      string `json:"message,omitempty"`
+}
+
+// SetComposedResourceStatus summarizes the supplied composed resources in the
+// status of the supplied composite resource. It records the state of each
+// composed resource at status.resources, and how many are ready at
+// status.resourcesReady. It does nothing if the composite resource is not
+// unstructured.
+func SetComposedResourceStatus(xr resource.Composite, cds []ComposedResource) error {
+	u, ok := xr.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return nil
+	}
+
+	ready := 0
+	rs := make([]composedResourceStatus, len(cds))
+	for i, cd := range cds {
+		// Specifying a name for P&T templates is optional but encouraged.
+		// If there was no name, fall back to using the index.
+		name := cd.ResourceName
+		if name == "" {
+			name = strconv.Itoa(i)
+		}
+		rs[i] = composedResourceStatus{
+			Name:         name,
+			APIVersion:   cd.Reference.APIVersion,
+			Kind:         cd.Reference.Kind,
+			ResourceName: cd.Reference.Name,
+			Ready:        cd.Ready,
+			Synced:       cd.SyncError == "",
+			Message:      truncate(cd.SyncError, MaxComposedResourceMessageLength),
+		}
+		if cd.Ready {
+			ready++
+		}
+	}
+
+	p := fieldpath.Pave(u.UnstructuredContent())
+	if err := p.SetValue("status.resources", rs); err != nil {
+		return err
+	}
+	return p.SetString("status.resourcesReady", fmt.Sprintf("%d/%d resources ready", ready, len(cds)))
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-3]) + "..."
 }
 
 // ComposedResourceState tracks the state of a composed resource through the
@@ -52,6 +137,30 @@ type ComposedResourceState struct {
 	ConnectionDetails managed.ConnectionDetails
 }
 
+// Summary returns the ComposedResource output by the composition process for
+// this state, including a reference to the composed resource and why it is not
+// synced, if it isn't.
+func (s ComposedResourceState) Summary() ComposedResource {
+	out := s.ComposedResource
+	if s.Resource != nil && s.Resource.GetName() != "" {
+		out.Reference = *meta.ReferenceTo(s.Resource, s.Resource.GetObjectKind().GroupVersionKind())
+	}
+	if s.TemplateRenderErr != nil {
+		out.SyncError = s.TemplateRenderErr.Error()
+		return out
+	}
+	if s.Resource == nil {
+		return out
+	}
+	if c := s.Resource.GetCondition(xpv1.TypeSynced); c.Status == corev1.ConditionFalse {
+		out.SyncError = c.Message
+		if out.SyncError == "" {
+			out.SyncError = string(c.Reason)
+		}
+	}
+	return out
+}
+
 // ComposedResourceStates is a map of (Composition) resource name to state. The
 // key corresponds to the ResourceName field of the ComposedResource type.
 type ComposedResourceStates map[string]ComposedResourceState
@@ -97,3 +206,31 @@ func MergeComposedResourceStates(old, new ComposedResourceState) ComposedResourc
 
 	return out
 }
+
+// RemoveComposedResource removes a composed resource that the supplied
+// composite resource no longer desires, according to the supplied policy. By
+// default the composed resource is deleted. If the policy is to orphan the
+// composed resource any owner references to the composite resource are removed
+// instead, so that the composed resource outlives the composite resource.
+func RemoveComposedResource(ctx context.Context, c client.Writer, xr metav1.Object, cd resource.Composed, p *v1.RemovedResourcePolicy) error {
+	if p == nil || *p != v1.RemovedResourcePolicyOrphan {
+		return resource.IgnoreNotFound(c.Delete(ctx, cd))
+	}
+
+	orig := cd.DeepCopyObject().(client.Object)
+	refs := make([]metav1.OwnerReference, 0, len(cd.GetOwnerReferences()))
+	for _, ref := range cd.GetOwnerReferences() {
+		if ref.UID == xr.GetUID() {
+			continue
+		}
+		refs = append(refs, ref)
+	}
+	if len(refs) == len(cd.GetOwnerReferences()) {
+		return nil
+	}
+	cd.SetOwnerReferences(refs)
+
+	// We patch rather than update to avoid persisting any other changes that
+	// may have been rendered into our in-memory copy of the composed resource.
+	return resource.IgnoreNotFound(c.Patch(ctx, cd, client.MergeFrom(orig)))
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestSetComposedResourceStatus(t *testing.T) {
+	long := strings.Repeat("a", MaxComposedResourceMessageLength+10)
+
+	type args struct {
+		xr  resource.Composite
+		cds []ComposedResource
+	}
+	type want struct {
+		xr  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotUnstructured": {
+			reason: "We should do nothing if the composite resource is not unstructured.",
+			args: args{
+				xr:  &fake.Composite{},
+				cds: []ComposedResource{{ResourceName: "cool-resource", Ready: true}},
+			},
+			want: want{
+				xr: &fake.Composite{},
+			},
+		},
+		"Summarize": {
+			reason: "We should record the state of each composed resource and how many are ready.",
+			args: args{
+				xr: composite.New(),
+				cds: []ComposedResource{
+					{
+						ResourceName: "cool-resource",
+						Ready:        true,
+						Reference:    corev1.ObjectReference{APIVersion: "example.org/v1", Kind: "Cool", Name: "cool-xr-abcde"},
+					},
+					{
+						// Unnamed resources fall back to their index.
+						SyncError: long,
+					},
+				},
+			},
+			want: want{
+				xr: func() resource.Composite {
+					xr := composite.New()
+					xr.Object["status"] = map[string]any{
+						"resourcesReady": "1/2 resources ready",
+						"resources": []any{
+							map[string]any{
+								"name":         "cool-resource",
+								"apiVersion":   "example.org/v1",
+								"kind":         "Cool",
+								"resourceName": "cool-xr-abcde",
+								"ready":        true,
+								"synced":       true,
+							},
+							map[string]any{
+								"name":    "1",
+								"ready":   false,
+								"synced":  false,
+								"message": long[:MaxComposedResourceMessageLength-3] + "...",
+							},
+						},
+					}
+					return xr
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetComposedResourceStatus(tc.args.xr, tc.args.cds)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetComposedResourceStatus(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.xr, tc.args.xr); diff != "" {
+				t.Errorf("\n%s\nSetComposedResourceStatus(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestComposedResourceStateSummary(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+	cool := func(name string) *composed.Unstructured {
+		cd := composed.New()
+		cd.SetGroupVersionKind(gvk)
+		cd.SetName(name)
+		return cd
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      ComposedResourceState
+		want   ComposedResource
+	}{
+		"Unnamed": {
+			reason: "We should not reference a composed resource that has not yet been named.",
+			s: ComposedResourceState{
+				ComposedResource: ComposedResource{ResourceName: "cool-resource"},
+				Resource:         cool(""),
+			},
+			want: ComposedResource{ResourceName: "cool-resource"},
+		},
+		"RenderError": {
+			reason: "We should report why a composed resource could not be rendered.",
+			s: ComposedResourceState{
+				ComposedResource:  ComposedResource{ResourceName: "cool-resource"},
+				TemplateRenderErr: errors.New("boom"),
+			},
+			want: ComposedResource{ResourceName: "cool-resource", SyncError: "boom"},
+		},
+		"NotSynced": {
+			reason: "We should report the message of a composed resource's Synced condition if it is False.",
+			s: ComposedResourceState{
+				ComposedResource: ComposedResource{ResourceName: "cool-resource", Ready: true},
+				Resource: func() resource.Composed {
+					cd := cool("cool")
+					cd.SetConditions(xpv1.ReconcileError(errors.New("boom")))
+					return cd
+				}(),
+			},
+			want: ComposedResource{
+				ResourceName: "cool-resource",
+				Ready:        true,
+				Reference:    corev1.ObjectReference{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: "cool"},
+				SyncError:    "boom",
+			},
+		},
+		"Synced": {
+			reason: "We should not report a sync error for a composed resource that is synced.",
+			s: ComposedResourceState{
+				ComposedResource: ComposedResource{ResourceName: "cool-resource"},
+				Resource: func() resource.Composed {
+					cd := cool("cool")
+					cd.SetConditions(xpv1.ReconcileSuccess())
+					return cd
+				}(),
+			},
+			want: ComposedResource{
+				ResourceName: "cool-resource",
+				Reference:    corev1.ObjectReference{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: "cool"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.s.Summary()
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSummary(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
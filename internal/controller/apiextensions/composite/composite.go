@@ -27,6 +27,11 @@ import (
 // Annotation keys.
 const (
 	AnnotationKeyCompositionResourceName = "crossplane.io/composition-resource-name"
+
+	// AnnotationKeyEnforcedComposition is set on composite resources whose
+	// definition enforces the Composition they use. Its value is the name of
+	// the enforced Composition.
+	AnnotationKeyEnforcedComposition = "crossplane.io/enforced-composition"
 )
 
 // SetCompositionResourceName sets the name of the composition template used to
@@ -39,6 +39,7 @@ const (
 	errFmtCombineStrategyNotSupported = "combine strategy %s is not supported"
 	errFmtCombineConfigMissing        = "given combine strategy %s requires configuration"
 	errFmtCombineStrategyFailed       = "%s strategy could not combine"
+	errFmtCombineVariableTransform    = "cannot transform variable at index %d"
 	errFmtExpandingArrayFieldPaths    = "cannot expand ToFieldPath %s"
 )
 
@@ -112,8 +113,12 @@ func filterPatch(p v1.Patch, only ...v1.PatchType) bool {
 
 // ResolveTransforms applies a list of transforms to a patch value.
 func ResolveTransforms(c v1.Patch, input any) (any, error) {
+	return resolveTransforms(c.Transforms, input)
+}
+
+func resolveTransforms(ts []v1.Transform, input any) (any, error) {
 	var err error
-	for i, t := range c.Transforms {
+	for i, t := range ts {
 		if input, err = Resolve(t, input); err != nil {
 			// TODO(negz): Including the type might help find the offending transform faster.
 			return nil, errors.Wrapf(err, errFmtTransformAtIndex, i)
@@ -175,11 +180,6 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 		return err
 	}
 
-	var mo *xpv1.MergeOptions
-	if p.Policy != nil {
-		mo = p.Policy.MergeOptions
-	}
-
 	// Apply transform pipeline
 	out, err := ResolveTransforms(p, in)
 	if err != nil {
@@ -188,10 +188,10 @@ func ApplyFromFieldPathPatch(p v1.Patch, from, to runtime.Object) error {
 
 	// Patch all expanded fields if the ToFieldPath contains wildcards
 	if strings.Contains(*p.ToFieldPath, "[*]") {
-		return patchFieldValueToMultiple(*p.ToFieldPath, out, to, mo)
+		return patchFieldValueToMultiple(*p.ToFieldPath, out, to, p.Policy.GetMergeOptions())
 	}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, mo)
+	return patchFieldValueToObject(*p.ToFieldPath, out, to, p.Policy.GetMergeOptions())
 }
 
 // ApplyCombineFromVariablesPatch patches the "to" resource, taking a list of
@@ -241,7 +241,9 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 		if err != nil {
 			return err
 		}
-		in[i] = iv
+		if in[i], err = resolveTransforms(sp.Transforms, iv); err != nil {
+			return errors.Wrapf(err, errFmtCombineVariableTransform, i)
+		}
 	}
 
 	// Combine input values
@@ -256,7 +258,7 @@ func ApplyCombineFromVariablesPatch(p v1.Patch, from, to runtime.Object) error {
 		return err
 	}
 
-	return patchFieldValueToObject(*p.ToFieldPath, out, to, nil)
+	return patchFieldValueToObject(*p.ToFieldPath, out, to, p.Policy.GetMergeOptions())
 }
 
 // IsOptionalFieldPathNotFound returns true if the supplied error indicates a
@@ -401,6 +401,86 @@ func TestPatchApply(t *testing.T) {
 				err: nil,
 			},
 		},
+		"MergeOptionsAppendSlice": {
+			reason: "Setting mergeOptions.appendSlice = true appends new slice elements to existing ones, without duplicates",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.finalizers"),
+					Policy: &v1.PatchPolicy{
+						MergeOptions: &xpv1.MergeOptions{
+							AppendSlice: pointer.Bool(true),
+						},
+					},
+					ToFieldPath: pointer.String("objectMeta.finalizers"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cp",
+						Finalizers: []string{"one", "two"},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"zero", "one"},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:       "cd",
+						Finalizers: []string{"zero", "one", "two"},
+					},
+				},
+				err: nil,
+			},
+		},
+		"MergeOptionsConflictingMapValues": {
+			reason: "Setting mergeOptions without keepMapValues merges maps, taking the patch's value for conflicting keys",
+			args: args{
+				patch: v1.Patch{
+					Type:          v1.PatchTypeFromCompositeFieldPath,
+					FromFieldPath: pointer.String("objectMeta.labels"),
+					Policy: &v1.PatchPolicy{
+						MergeOptions: &xpv1.MergeOptions{},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"labelone": "foo",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"labelone": "bar",
+							"labeltwo": "baz",
+						},
+					},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"labelone": "foo",
+							"labeltwo": "baz",
+						},
+					},
+				},
+				err: nil,
+			},
+		},
 		"FilterExcludeCompositeFieldPathPatch": {
 			reason: "Should not apply the patch as the v1.PatchType is not present in filter.",
 			args: args{
@@ -955,6 +1035,65 @@ func TestPatchApply(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ValidCombineFromCompositeWithVariableTransforms": {
+			reason: "Should transform each variable of a CombineFromComposite patch before combining them",
+			args: args{
+				patch: v1.Patch{
+					Type: v1.PatchTypeCombineFromComposite,
+					Combine: &v1.Combine{
+						Variables: []v1.CombineVariable{
+							{FromFieldPath: "objectMeta.labels.source1"},
+							{
+								FromFieldPath: "objectMeta.labels.source2",
+								Transforms: []v1.Transform{{
+									Type: v1.TransformTypeString,
+									String: &v1.StringTransform{
+										Type: v1.StringTransformTypeHash,
+										Hash: &v1.StringTransformHash{Length: pointer.Int(8)},
+									},
+								}},
+							},
+						},
+						Strategy: v1.CombineStrategyString,
+						String:   &v1.StringCombine{Format: "%s-%s"},
+					},
+					ToFieldPath: pointer.String("objectMeta.labels.destination"),
+				},
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{Name: "cd"},
+				},
+			},
+			want: want{
+				cp: &fake.Composite{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cp",
+						Labels: map[string]string{
+							"source1": "foo",
+							"source2": "bar",
+						},
+					},
+					ConnectionDetailsLastPublishedTimer: lpt,
+				},
+				cd: &fake.Composed{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cd",
+						Labels: map[string]string{
+							"destination": "foo-fcde2b2e",
+						}},
+				},
+				err: nil,
+			},
+		},
 		"ValidCombineToComposite": {
 			reason: "Should correctly apply a CombineToComposite patch with valid settings",
 			args: args{
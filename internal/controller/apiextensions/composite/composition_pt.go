@@ -23,6 +23,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/json"
 	"k8s.io/utils/pointer"
@@ -57,9 +59,15 @@ const (
 	errInline           = "cannot inline Composition patch sets"
 	errRenderCR         = "cannot render composite resource"
 	errSetControllerRef = "cannot set controller reference"
-
-	errFmtResourceName = "composed resource %q"
-	errFmtPatch        = "cannot apply the patch at index %d"
+	errGetObserved      = "cannot get observe-only composed resource"
+	errListObserved     = "cannot list observe-only composed resources"
+	errObserveNoID      = "observe-only base resource must have a name or labels"
+	errRenderName       = "cannot render composed resource name"
+
+	errFmtResourceName     = "composed resource %q"
+	errFmtPatch            = "cannot apply the patch at index %d"
+	errFmtObservedMatching = "observe-only base resource labels must match exactly one resource, matched %d"
+	errFmtNotControlled    = "existing resource %q is not controlled by the composite resource"
 )
 
 // TODO(negz): Move P&T Composition logic into its own package?
@@ -116,6 +124,23 @@ func WithComposedConnectionDetailsExtractor(e ConnectionDetailsExtractor) PTComp
 	}
 }
 
+// WithPropagatedMetadataPrefixes configures a PatchAndTransformComposer to
+// propagate the composite resource's labels and annotations with the supplied
+// key prefixes to its composed resources.
+func WithPropagatedMetadataPrefixes(p ...string) PTComposerOption {
+	return func(c *PTComposer) {
+		c.propagate = p
+	}
+}
+
+// WithPTComposerMetrics configures how a PatchAndTransformComposer records
+// metrics about the composite resources it composes.
+func WithPTComposerMetrics(m *CompositionMetrics) PTComposerOption {
+	return func(c *PTComposer) {
+		c.metrics = m
+	}
+}
+
 type composedResource struct {
 	Renderer
 	managed.ConnectionDetailsFetcher
@@ -133,6 +158,9 @@ type PTComposer struct {
 	composite   Renderer
 	composition CompositionTemplateAssociator
 	composed    composedResource
+
+	propagate []string
+	metrics   *CompositionMetrics
 }
 
 // NewPTComposer returns a Composer that composes resources using Patch and
@@ -158,6 +186,7 @@ func NewPTComposer(kube client.Client, o ...PTComposerOption) *PTComposer {
 			ConnectionDetailsFetcher:   NewSecretConnectionDetailsFetcher(kube),
 			ConnectionDetailsExtractor: ConnectionDetailsExtractorFn(ExtractConnectionDetails),
 		},
+		metrics: NewCompositionMetrics(),
 	}
 
 	for _, fn := range o {
@@ -176,7 +205,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		return CompositionResult{}, errors.Wrap(err, errInline)
 	}
 
-	tas, err := c.composition.AssociateTemplates(ctx, xr, ct)
+	tas, err := c.composition.AssociateTemplates(ctx, xr, ct, req.Revision.Spec.RemovedResourcePolicy)
 	if err != nil {
 		return CompositionResult{}, errors.Wrap(err, errAssociate)
 	}
@@ -186,6 +215,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 	if req.Environment != nil && req.Revision.Spec.Environment != nil {
 		for i, p := range req.Revision.Spec.Environment.Patches {
 			if err := ApplyEnvironmentPatch(p, xr, req.Environment); err != nil {
+				c.metrics.CountError(xr, req.Revision, StagePatch)
 				return CompositionResult{}, errors.Wrapf(err, errFmtPatchEnvironment, i)
 			}
 		}
@@ -207,8 +237,15 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		name := pointer.StringDeref(ta.Template.Name, strconv.Itoa(i))
 		r := composed.New(composed.FromReference(ta.Reference))
 
-		rerr := c.composed.Render(ctx, xr, r, ta.Template, req.Environment)
+		var rerr error
+		if ta.Template.IsObserveOnly() {
+			// Observe-only resources are never rendered, only loaded.
+			rerr = ObserveExisting(ctx, c.client, r, ta.Template)
+		} else {
+			rerr = c.composed.Render(ctx, xr, r, ta.Template, req.Environment)
+		}
 		if rerr != nil {
+			c.metrics.CountError(xr, req.Revision, StageRender)
 			events = append(events, event.Warning(reasonCompose, errors.Wrapf(rerr, errFmtResourceName, name)))
 		}
 
@@ -233,18 +270,37 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 	// We apply all of our composed resources before we observe them and update
 	// in the loop below. This ensures that issues observing and processing one
 	// composed resource won't block the application of another.
+	applied := 0
 	for _, cd := range cds {
 		// If we were unable to render the composed resource we should not try
 		// and apply it.
 		if cd.TemplateRenderErr != nil {
 			continue
 		}
+		// We never create or update observe-only resources.
+		if cd.Template.IsObserveOnly() {
+			continue
+		}
 		o := []resource.ApplyOption{resource.MustBeControllableBy(xr.GetUID())}
+		if cd.Template.NameTemplate != nil {
+			o = append(o, mustBeControlledBy(xr.GetUID()))
+		}
 		o = append(o, mergeOptions(filterPatches(cd.Template.Patches, patchTypesFromXR()...))...)
+		if len(c.propagate) > 0 {
+			// Propagated labels and annotations are owned by the XR,
+			// so they take precedence over those rendered from the
+			// template, and are removed when removed from the XR.
+			meta.AddLabels(cd.Resource, xcrd.PropagatedMetadata(xr.GetLabels(), c.propagate))
+			meta.AddAnnotations(cd.Resource, xcrd.PropagatedMetadata(xr.GetAnnotations(), c.propagate))
+			o = append(o, xcrd.RemoveStaleMetadata(c.propagate...))
+		}
 		if err := c.client.Apply(ctx, cd.Resource, o...); err != nil {
+			c.metrics.ObserveApplied(xr, req.Revision, applied)
 			return CompositionResult{}, errors.Wrap(err, errApply)
 		}
+		applied++
 	}
+	c.metrics.ObserveApplied(xr, req.Revision, applied)
 
 	conn := managed.ConnectionDetails{}
 	for i := range cds {
@@ -255,6 +311,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 		}
 
 		if err := c.composite.Render(ctx, xr, cds[i].Resource, *cds[i].Template, req.Environment); err != nil {
+			c.metrics.CountError(xr, req.Revision, StagePatch)
 			return CompositionResult{}, errors.Wrap(err, errRenderCR)
 		}
 
@@ -298,7 +355,7 @@ func (c *PTComposer) Compose(ctx context.Context, xr resource.Composite, req Com
 
 	out := make([]ComposedResource, len(cds))
 	for i := range cds {
-		out[i] = cds[i].ComposedResource
+		out[i] = cds[i].Summary()
 	}
 
 	return CompositionResult{ConnectionDetails: conn, Composed: out, Events: events}, nil
@@ -361,16 +418,18 @@ func AssociateByOrder(t []v1.ComposedTemplate, r []corev1.ObjectReference) []Tem
 }
 
 // A CompositionTemplateAssociator returns an array of template associations.
+// Composed resources that don't correspond to any template are handled
+// according to the supplied RemovedResourcePolicy, if any.
 type CompositionTemplateAssociator interface {
-	AssociateTemplates(context.Context, resource.Composite, []v1.ComposedTemplate) ([]TemplateAssociation, error)
+	AssociateTemplates(context.Context, resource.Composite, []v1.ComposedTemplate, *v1.RemovedResourcePolicy) ([]TemplateAssociation, error)
 }
 
 // A CompositionTemplateAssociatorFn returns an array of template associations.
-type CompositionTemplateAssociatorFn func(context.Context, resource.Composite, []v1.ComposedTemplate) ([]TemplateAssociation, error)
+type CompositionTemplateAssociatorFn func(context.Context, resource.Composite, []v1.ComposedTemplate, *v1.RemovedResourcePolicy) ([]TemplateAssociation, error)
 
 // AssociateTemplates with composed resources.
-func (fn CompositionTemplateAssociatorFn) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
-	return fn(ctx, cr, ct)
+func (fn CompositionTemplateAssociatorFn) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+	return fn(ctx, cr, ct, p)
 }
 
 // A GarbageCollectingAssociator associates a Composition's resource templates
@@ -379,7 +438,7 @@ func (fn CompositionTemplateAssociatorFn) AssociateTemplates(ctx context.Context
 // template or existing composed resource can't be associated by name it falls
 // back to associating them by order. If it encounters a referenced resource
 // that corresponds to a non-existent template the resource will be garbage
-// collected (i.e. deleted).
+// collected (i.e. deleted or orphaned, depending on the supplied policy).
 type GarbageCollectingAssociator struct {
 	client client.Client
 }
@@ -391,7 +450,7 @@ func NewGarbageCollectingAssociator(c client.Client) *GarbageCollectingAssociato
 }
 
 // AssociateTemplates with composed resources.
-func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) { //nolint:gocyclo // Only slightly over (13).
+func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) { //nolint:gocyclo // Only slightly over (13).
 	templates := map[string]int{}
 	for i, t := range ct {
 		if t.Name == nil {
@@ -428,6 +487,13 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 
 		name := GetCompositionResourceName(cd)
 		if name == "" {
+			// We didn't render this resource, so it can't be one we composed
+			// before our templates were named. It's most likely an existing
+			// resource that one of our templates observes.
+			if c := metav1.GetControllerOf(cd); c == nil || c.UID != cr.GetUID() {
+				continue
+			}
+
 			// All of our templates are named, but this existing composed
 			// resource is not associated with a named template. It's likely
 			// that our Composition was just migrated from anonymous to named
@@ -446,17 +512,16 @@ func (a *GarbageCollectingAssociator) AssociateTemplates(ctx context.Context, cr
 			continue
 		}
 
-		// TODO(negz): Below should be || not &&. If the controller ref is nil
-		// we don't control the resource and shouldn't delete it.
-
 		// We want to garbage collect this resource, but we don't control it.
-		if c := metav1.GetControllerOf(cd); c != nil && c.UID != cr.GetUID() {
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != cr.GetUID() {
 			continue
 		}
 
 		// This existing resource does not correspond to an extant template. It
-		// should be garbage collected.
-		if err := a.client.Delete(ctx, cd); resource.IgnoreNotFound(err) != nil {
+		// should be garbage collected. Note that we only get here if we could
+		// read the resource; if we can't tell whether a resource is still
+		// desired we return an error rather than assuming it isn't.
+		if err := RemoveComposedResource(ctx, a.client, cr, cd, p); err != nil {
 			return nil, errors.Wrap(err, errGCComposed)
 		}
 	}
@@ -496,7 +561,7 @@ func NewAPIDryRunRenderer(c client.Client) *APIDryRunRenderer {
 // Render the supplied composed resource using the supplied composite resource
 // and template. The rendered resource may be submitted to an API server via a
 // dry run create in order to name and validate it.
-func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error { //nolint:gocyclo // Only slightly over (11).
+func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error { //nolint:gocyclo // Only slightly over (13).
 	kind := cd.GetObjectKind().GroupVersionKind().Kind
 	name := cd.GetName()
 	namespace := cd.GetNamespace()
@@ -524,6 +589,22 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	cd.SetName(name)
 	cd.SetNamespace(namespace)
 
+	// Composed resources that were named before the template gained a name
+	// template keep their existing name. Otherwise we'd abandon them.
+	if name == "" && t.NameTemplate != nil {
+		n, err := t.RenderName(v1.NameTemplateParameters{
+			CompositeName:  cp.GetName(),
+			ClaimName:      cp.GetLabels()[xcrd.LabelKeyClaimName],
+			ClaimNamespace: cp.GetLabels()[xcrd.LabelKeyClaimNamespace],
+			TemplateName:   pointer.StringDeref(t.Name, ""),
+		})
+		if err != nil {
+			return errors.Wrap(err, errRenderName)
+		}
+		cd.SetName(n)
+		cd.SetGenerateName("")
+	}
+
 	for i := range t.Patches {
 		if err := Apply(t.Patches[i], cp, cd, patchTypesFromXR()...); err != nil {
 			return errors.Wrapf(err, errFmtPatch, i)
@@ -570,6 +651,57 @@ func (r *APIDryRunRenderer) Render(ctx context.Context, cp resource.Composite, c
 	return errors.Wrap(r.client.Create(ctx, cd, client.DryRunAll), errName)
 }
 
+// mustBeControlledBy requires that the current object is controlled by an
+// object with the supplied UID. Unlike resource.MustBeControllableBy it won't
+// adopt an existing object that has no controller. We use it for composed
+// resources with templated names, which could otherwise take over an unrelated
+// resource that happens to have the same name.
+func mustBeControlledBy(u types.UID) resource.ApplyOption {
+	return func(_ context.Context, current, _ runtime.Object) error {
+		o, ok := current.(metav1.Object)
+		if !ok {
+			return nil
+		}
+		if c := metav1.GetControllerOf(o); c == nil || c.UID != u {
+			return errors.Errorf(errFmtNotControlled, o.GetName())
+		}
+		return nil
+	}
+}
+
+// ObserveExisting loads the existing resource the supplied observe-only
+// template refers to into the supplied composed resource. The existing
+// resource is identified by the name of the template's base resource, or by its
+// labels if it has no name.
+func ObserveExisting(ctx context.Context, c client.Reader, cd *composed.Unstructured, t v1.ComposedTemplate) error {
+	base := composed.New()
+	if err := json.Unmarshal(t.Base.Raw, base); err != nil {
+		return errors.Wrap(err, errUnmarshal)
+	}
+
+	if base.GetName() != "" {
+		cd.SetGroupVersionKind(base.GetObjectKind().GroupVersionKind())
+		nn := types.NamespacedName{Namespace: base.GetNamespace(), Name: base.GetName()}
+		return errors.Wrap(c.Get(ctx, nn, cd), errGetObserved)
+	}
+
+	if len(base.GetLabels()) == 0 {
+		return errors.New(errObserveNoID)
+	}
+
+	gvk := base.GetObjectKind().GroupVersionKind()
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+	if err := c.List(ctx, l, client.InNamespace(base.GetNamespace()), client.MatchingLabels(base.GetLabels())); err != nil {
+		return errors.Wrap(err, errListObserved)
+	}
+	if len(l.Items) != 1 {
+		return errors.Errorf(errFmtObservedMatching, len(l.Items))
+	}
+	cd.Unstructured = l.Items[0]
+	return nil
+}
+
 // RenderComposite renders the supplied composite resource using the supplied composed
 // resource and template.
 func RenderComposite(_ context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, _ *env.Environment) error {
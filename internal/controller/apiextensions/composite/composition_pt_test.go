@@ -25,6 +25,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +38,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -47,6 +49,7 @@ import (
 func TestPTCompose(t *testing.T) {
 	errBoom := errors.New("boom")
 	details := managed.ConnectionDetails{"a": []byte("b")}
+	observeOnly := v1.ComposedResourceManagementPolicyObserveOnly
 
 	type params struct {
 		kube client.Client
@@ -94,7 +97,7 @@ func TestPTCompose(t *testing.T) {
 			reason: "We should return any error encountered while associating Composition templates with composed resources.",
 			params: params{
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						return nil, errBoom
 					})),
 				},
@@ -120,7 +123,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -149,6 +152,7 @@ func TestPTCompose(t *testing.T) {
 				res: CompositionResult{
 					Composed: []ComposedResource{{
 						ResourceName: "cool-resource",
+						SyncError:    errBoom.Error(),
 					}},
 					ConnectionDetails: managed.ConnectionDetails{},
 					Events: []event.Event{
@@ -164,7 +168,7 @@ func TestPTCompose(t *testing.T) {
 					MockUpdate: test.NewMockUpdateFn(errBoom),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -197,7 +201,7 @@ func TestPTCompose(t *testing.T) {
 					MockGet: test.NewMockGetFn(errBoom),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -231,7 +235,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -268,7 +272,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -308,7 +312,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -351,7 +355,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -399,7 +403,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						return nil, nil
 					})),
 					WithCompositeRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
@@ -417,6 +421,108 @@ func TestPTCompose(t *testing.T) {
 				err: errors.Wrap(errors.Wrap(errBoom, "cannot get object"), errUpdate),
 			},
 		},
+		"ObserveOnlyError": {
+			reason: "We should include any error encountered while loading an observe-only resource as a warning, not as the returned error.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						if _, ok := obj.(*fake.Composite); ok {
+							return nil
+						}
+						return errBoom
+					}),
+					MockPatch: test.NewMockPatchFn(nil),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:             pointer.String("cool-resource"),
+								ManagementPolicy: &observeOnly,
+								Base:             runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"shared"}}`)},
+							},
+						}}
+						return tas, nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						SyncError:    errors.Wrap(errBoom, errGetObserved).Error(),
+					}},
+					ConnectionDetails: managed.ConnectionDetails{},
+					Events: []event.Event{
+						event.Warning(reasonCompose, errors.Wrapf(errors.Wrap(errBoom, errGetObserved), errFmtResourceName, "cool-resource")),
+					},
+				},
+			},
+		},
+		"ObserveOnly": {
+			reason: "We should observe, but never render or apply, observe-only resources.",
+			params: params{
+				kube: &test.MockClient{
+					MockUpdate: test.NewMockUpdateFn(nil),
+					MockGet:    test.NewMockGetFn(nil),
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						if _, ok := obj.(*fake.Composite); !ok {
+							t.Errorf("unexpected patch of observe-only resource %q", obj.GetName())
+						}
+						return nil
+					}),
+				},
+				o: []PTComposerOption{
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
+						tas := []TemplateAssociation{{
+							Template: v1.ComposedTemplate{
+								Name:             pointer.String("cool-resource"),
+								ManagementPolicy: &observeOnly,
+								Base:             runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"shared"}}`)},
+							},
+						}}
+						return tas, nil
+					})),
+					WithComposedRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return errBoom
+					})),
+					WithCompositeRenderer(RendererFn(func(ctx context.Context, cp resource.Composite, cd resource.Composed, t v1.ComposedTemplate, env *env.Environment) error {
+						return nil
+					})),
+					WithComposedConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
+						return nil, nil
+					})),
+					WithComposedConnectionDetailsExtractor(ConnectionDetailsExtractorFn(func(cd resource.Composed, conn managed.ConnectionDetails, cfg ...ConnectionDetailExtractConfig) (managed.ConnectionDetails, error) {
+						return details, nil
+					})),
+					WithComposedReadinessChecker(ReadinessCheckerFn(func(ctx context.Context, o ConditionedObject, rc ...ReadinessCheck) (ready bool, err error) {
+						return true, nil
+					})),
+				},
+			},
+			args: args{
+				xr: &fake.Composite{},
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{},
+				},
+			},
+			want: want{
+				res: CompositionResult{
+					Composed: []ComposedResource{{
+						ResourceName: "cool-resource",
+						Ready:        true,
+					}},
+					ConnectionDetails: details,
+				},
+			},
+		},
 		"Success": {
 			reason: "We should return the resources we composed, and our derived connection details.",
 			params: params{
@@ -428,7 +534,7 @@ func TestPTCompose(t *testing.T) {
 					MockPatch: test.NewMockPatchFn(nil),
 				},
 				o: []PTComposerOption{
-					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate) ([]TemplateAssociation, error) {
+					WithTemplateAssociator(CompositionTemplateAssociatorFn(func(ctx context.Context, c resource.Composite, ct []v1.ComposedTemplate, p *v1.RemovedResourcePolicy) ([]TemplateAssociation, error) {
 						tas := []TemplateAssociation{{
 							Template: v1.ComposedTemplate{
 								Name: pointer.String("cool-resource"),
@@ -611,6 +717,76 @@ func TestRender(t *testing.T) {
 				}},
 			},
 		},
+		"NameTemplate": {
+			reason: "A composed resource should be named using its template's name template, without a dry-run create",
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Name: "xr", Labels: map[string]string{
+					xcrd.LabelKeyNamePrefixForComposed: "ola",
+					xcrd.LabelKeyClaimName:             "rola",
+					xcrd.LabelKeyClaimNamespace:        "rolans",
+				}}},
+				cd: &fake.Composed{},
+				t: v1.ComposedTemplate{
+					Name:         pointer.String("db"),
+					NameTemplate: pointer.String("{{ .ClaimNamespace }}-{{ .ClaimName }}-{{ .TemplateName }}"),
+					Base:         runtime.RawExtension{Raw: tmpl},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Name: "rolans-rola-db",
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "rola",
+						xcrd.LabelKeyClaimNamespace:        "rolans",
+					},
+					Annotations:     map[string]string{AnnotationKeyCompositionResourceName: "db"},
+					OwnerReferences: []metav1.OwnerReference{{Name: "xr", Controller: &ctrl, BlockOwnerDeletion: &ctrl}},
+				}},
+			},
+		},
+		"NameTemplateExistingName": {
+			reason: "A composed resource that is already named should keep its name even if its template has a name template",
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Name: "xr", Labels: map[string]string{
+					xcrd.LabelKeyNamePrefixForComposed: "ola",
+				}}},
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+				t: v1.ComposedTemplate{
+					NameTemplate: pointer.String("{{ .CompositeName }}-db"),
+					Base:         runtime.RawExtension{Raw: tmpl},
+				},
+			},
+			want: want{
+				cd: &fake.Composed{ObjectMeta: metav1.ObjectMeta{
+					Name:         "cd",
+					GenerateName: "ola-",
+					Labels: map[string]string{
+						xcrd.LabelKeyNamePrefixForComposed: "ola",
+						xcrd.LabelKeyClaimName:             "",
+						xcrd.LabelKeyClaimNamespace:        "",
+					},
+					OwnerReferences: []metav1.OwnerReference{{Name: "xr", Controller: &ctrl, BlockOwnerDeletion: &ctrl}},
+				}},
+			},
+		},
+		"NameTemplateError": {
+			reason: "Errors rendering a name template should be returned",
+			args: args{
+				cp: &fake.Composite{ObjectMeta: metav1.ObjectMeta{Name: "xr", Labels: map[string]string{
+					xcrd.LabelKeyNamePrefixForComposed: "ola",
+				}}},
+				cd: &fake.Composed{},
+				t: v1.ComposedTemplate{
+					NameTemplate: pointer.String("{{ .CompositeName }}_db"),
+					Base:         runtime.RawExtension{Raw: tmpl},
+				},
+			},
+			want: want{
+				cd:  &fake.Composed{ObjectMeta: metav1.ObjectMeta{GenerateName: "ola-"}},
+				err: errors.Wrap(errors.New(`rendered name "xr_db" is invalid: a lowercase RFC 1123 subdomain must consist of lower case alphanumeric characters, '-' or '.', and must start and end with an alphanumeric character (e.g. 'example.com', regex used for validation is '[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*')`), errRenderName),
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -626,6 +802,173 @@ func TestRender(t *testing.T) {
 	}
 }
 
+func TestMustBeControlledBy(t *testing.T) {
+	ctrl := true
+	uid := types.UID("xr")
+
+	cases := map[string]struct {
+		reason  string
+		current client.Object
+		want    error
+	}{
+		"NoController": {
+			reason:  "We should refuse to adopt a resource that has no controller.",
+			current: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd"}},
+			want:    errors.Errorf(errFmtNotControlled, "cd"),
+		},
+		"OtherController": {
+			reason: "We should refuse to apply a resource that is controlled by another resource.",
+			current: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", OwnerReferences: []metav1.OwnerReference{
+				{Controller: &ctrl, UID: "other"},
+			}}},
+			want: errors.Errorf(errFmtNotControlled, "cd"),
+		},
+		"Controlled": {
+			reason: "We should apply a resource that is controlled by the supplied UID.",
+			current: &fake.Composed{ObjectMeta: metav1.ObjectMeta{Name: "cd", OwnerReferences: []metav1.OwnerReference{
+				{Controller: &ctrl, UID: uid},
+			}}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := mustBeControlledBy(uid)(context.Background(), tc.current, nil)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nmustBeControlledBy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveExisting(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	named := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"shared","namespace":"default"}}`)}}
+	labelled := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"labels":{"shared":"true"}}}`)}}
+	anonymous := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap"}`)}}
+
+	existing := func() kunstructured.Unstructured {
+		u := kunstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetName("shared")
+		return u
+	}
+
+	type args struct {
+		ctx context.Context
+		c   client.Reader
+		t   v1.ComposedTemplate
+	}
+	type want struct {
+		cd  *composed.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InvalidTemplate": {
+			reason: "We should return an error if the base resource can't be unmarshalled.",
+			args: args{
+				t: v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("olala")}},
+			},
+			want: want{
+				cd:  composed.New(),
+				err: errors.Wrap(errors.New("invalid character 'o' looking for beginning of value"), errUnmarshal),
+			},
+		},
+		"GetError": {
+			reason: "We should return any error encountered getting a named resource.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				t: named,
+			},
+			want: want{
+				cd:  composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap"})),
+				err: errors.Wrap(errBoom, errGetObserved),
+			},
+		},
+		"NoNameOrLabels": {
+			reason: "We should return an error if the base resource has neither a name nor labels.",
+			args: args{
+				t: anonymous,
+			},
+			want: want{
+				cd:  composed.New(),
+				err: errors.New(errObserveNoID),
+			},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing labelled resources.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				t: labelled,
+			},
+			want: want{
+				cd:  composed.New(),
+				err: errors.Wrap(errBoom, errListObserved),
+			},
+		},
+		"AmbiguousLabels": {
+			reason: "We should return an error if the base resource's labels don't match exactly one resource.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{existing(), existing()}
+					return nil
+				})},
+				t: labelled,
+			},
+			want: want{
+				cd:  composed.New(),
+				err: errors.Errorf(errFmtObservedMatching, 2),
+			},
+		},
+		"SuccessByName": {
+			reason: "We should get the resource named by the base resource.",
+			args: args{
+				c: &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+					if key != (client.ObjectKey{Namespace: "default", Name: "shared"}) {
+						return errBoom
+					}
+					return nil
+				}},
+				t: named,
+			},
+			want: want{
+				cd: composed.New(composed.FromReference(corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap"})),
+			},
+		},
+		"SuccessByLabels": {
+			reason: "We should load the only resource matching the base resource's labels.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					obj.(*kunstructured.UnstructuredList).Items = []kunstructured.Unstructured{existing()}
+					return nil
+				})},
+				t: labelled,
+			},
+			want: want{
+				cd: &composed.Unstructured{Unstructured: existing()},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cd := composed.New()
+			err := ObserveExisting(tc.args.ctx, tc.args.c, cd, tc.args.t)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nObserveExisting(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.cd, cd); diff != "" {
+				t.Errorf("\n%s\nObserveExisting(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestAssociateByOrder(t *testing.T) {
 	t0 := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("zero")}}
 	t1 := v1.ComposedTemplate{Base: runtime.RawExtension{Raw: []byte("one")}}
@@ -689,10 +1032,17 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 
 	r0 := corev1.ObjectReference{Name: n0}
 
+	ctrl := true
+	controlledBy := func(uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Controller: &ctrl, BlockOwnerDeletion: &ctrl, UID: uid}}
+	}
+	orphan := v1.RemovedResourcePolicyOrphan
+
 	type args struct {
 		ctx context.Context
 		cr  resource.Composite
 		ct  []v1.ComposedTemplate
+		p   *v1.RemovedResourcePolicy
 	}
 
 	type want struct {
@@ -747,13 +1097,17 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 			},
 		},
 		"AnonymousResource": {
-			reason: "We should fall back to associating templates with references by order if any resource is not annotated with its template name.",
+			reason: "We should fall back to associating templates with references by order if any resource we control is not annotated with its template name.",
 			c: &test.MockClient{
-				// Return an empty (and thus unannotated) composed resource.
-				MockGet: test.NewMockGetFn(nil),
+				// Return an unannotated composed resource.
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.SetOwnerReferences(controlledBy("very-unique"))
+					return nil
+				}),
 			},
 			args: args{
 				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
 					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
 				},
 				ct: []v1.ComposedTemplate{t0},
@@ -762,6 +1116,24 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0, Reference: r0}},
 			},
 		},
+		"ObservedResource": {
+			reason: "We should skip unannotated resources we don't control, such as those observed by an observe-only template.",
+			c: &test.MockClient{
+				// Return an empty (and thus unannotated) composed resource.
+				MockGet:    test.NewMockGetFn(nil),
+				MockDelete: test.NewMockDeleteFn(errors.New("Delete should not be called")),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: t0}},
+			},
+		},
 		"AssociatedResource": {
 			reason: "We should associate referenced resources by their template name annotation.",
 			c: &test.MockClient{
@@ -808,18 +1180,41 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0}},
 			},
 		},
+		"UnownedResource": {
+			reason: "We should not garbage collect a resource that has no controller.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					// The template used to create this resource is no longer known to us.
+					SetCompositionResourceName(obj, "unknown")
+					return nil
+				}),
+				MockDelete: test.NewMockDeleteFn(errors.New("Delete should not be called")),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: t0}},
+			},
+		},
 		"GarbageCollectionError": {
 			reason: "We should return errors encountered while garbage collecting a composed resource.",
 			c: &test.MockClient{
 				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
 					// The template used to create this resource is no longer known to us.
 					SetCompositionResourceName(obj, "unknown")
+					obj.SetOwnerReferences(controlledBy("very-unique"))
 					return nil
 				}),
 				MockDelete: test.NewMockDeleteFn(errBoom),
 			},
 			args: args{
 				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
 					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
 				},
 				ct: []v1.ComposedTemplate{t0},
@@ -834,12 +1229,14 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
 					// The template used to create this resource is no longer known to us.
 					SetCompositionResourceName(obj, "unknown")
+					obj.SetOwnerReferences(controlledBy("very-unique"))
 					return nil
 				}),
 				MockDelete: test.NewMockDeleteFn(nil),
 			},
 			args: args{
 				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
 					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
 				},
 				ct: []v1.ComposedTemplate{t0},
@@ -848,12 +1245,92 @@ func TestGarbageCollectingAssociator(t *testing.T) {
 				tas: []TemplateAssociation{{Template: t0}},
 			},
 		},
+		"RenamedTemplate": {
+			reason: "We should garbage collect a resource created by a template that was since renamed, and not associate the renamed template with it.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					// This resource was created by the template before it was
+					// renamed from "zero" to "one".
+					SetCompositionResourceName(obj, n0)
+					obj.SetOwnerReferences(controlledBy("very-unique"))
+					return nil
+				}),
+				MockDelete: test.NewMockDeleteFn(nil, func(obj client.Object) error {
+					if GetCompositionResourceName(obj) != n0 {
+						t.Errorf("Delete(...): want resource created by template %q, got %q", n0, GetCompositionResourceName(obj))
+					}
+					return nil
+				}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{{Name: pointer.String("one")}},
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: v1.ComposedTemplate{Name: pointer.String("one")}}},
+			},
+		},
+		"OrphanedResource": {
+			reason: "We should orphan rather than delete a resource when the RemovedResourcePolicy is Orphan.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					// The template used to create this resource is no longer known to us.
+					SetCompositionResourceName(obj, "unknown")
+					obj.SetOwnerReferences(controlledBy("very-unique"))
+					return nil
+				}),
+				MockDelete: test.NewMockDeleteFn(errors.New("Delete should not be called")),
+				MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+					if len(obj.GetOwnerReferences()) != 0 {
+						t.Errorf("Patch(...): want no owner references, got %v", obj.GetOwnerReferences())
+					}
+					return nil
+				}),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+				p:  &orphan,
+			},
+			want: want{
+				tas: []TemplateAssociation{{Template: t0}},
+			},
+		},
+		"OrphanError": {
+			reason: "We should return errors encountered while orphaning a composed resource.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					// The template used to create this resource is no longer known to us.
+					SetCompositionResourceName(obj, "unknown")
+					obj.SetOwnerReferences(controlledBy("very-unique"))
+					return nil
+				}),
+				MockPatch: test.NewMockPatchFn(errBoom),
+			},
+			args: args{
+				cr: &fake.Composite{
+					ObjectMeta:                  metav1.ObjectMeta{UID: types.UID("very-unique")},
+					ComposedResourcesReferencer: fake.ComposedResourcesReferencer{Refs: []corev1.ObjectReference{r0}},
+				},
+				ct: []v1.ComposedTemplate{t0},
+				p:  &orphan,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGCComposed),
+			},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 			a := NewGarbageCollectingAssociator(tc.c)
-			got, err := a.AssociateTemplates(tc.args.ctx, tc.args.cr, tc.args.ct)
+			got, err := a.AssociateTemplates(tc.args.ctx, tc.args.cr, tc.args.ct, tc.args.p)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nAssociateTemplates(...): -want, +got:\n%s", tc.reason, diff)
@@ -17,7 +17,9 @@ package composite
 
 import (
 	"context"
+	"reflect"
 	"sort"
+	"time"
 
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -78,11 +80,13 @@ const (
 	errCloseRunner              = "cannot close connection to container runner"
 	errUnmarshalFnIO            = "cannot unmarshal output FunctionIO"
 	errFatalResult              = "fatal function pipeline result"
+	errFetchExtraResources      = "cannot fetch extra resources"
 
 	errFmtApplyCD                  = "cannot apply composed resource %q"
 	errFmtFetchCDConnectionDetails = "cannot fetch connection details for composed resource %q (a %s named %s)"
 	errFmtRenderXR                 = "cannot render composite resource from composed resource %q (a %s named %s)"
 	errFmtRunFn                    = "cannot run function %q"
+	errFmtRequirementsUnstable     = "function requirements did not stabilize after %d iterations"
 
 	errFmtUnsupportedFnType        = "unsupported function type %q"
 	errFmtParseDesiredCD           = "cannot parse desired composed resource %q from FunctionIO"
@@ -104,6 +108,7 @@ type PTFComposer struct {
 
 	composite   ptfComposite
 	composition ptfComposition
+	metrics     *CompositionMetrics
 }
 
 type ptfComposite struct {
@@ -133,15 +138,15 @@ func (fn ComposedResourceGetterFn) GetComposedResources(ctx context.Context, xr
 
 // A ComposedResourceDeleter deletes composed resources (and their state).
 type ComposedResourceDeleter interface {
-	DeleteComposedResources(ctx context.Context, s *PTFCompositionState) error
+	DeleteComposedResources(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error
 }
 
 // A ComposedResourceDeleterFn deletes composed resources (and their state).
-type ComposedResourceDeleterFn func(ctx context.Context, s *PTFCompositionState) error
+type ComposedResourceDeleterFn func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error
 
 // DeleteComposedResources deletes composed resources (and their state).
-func (fn ComposedResourceDeleterFn) DeleteComposedResources(ctx context.Context, s *PTFCompositionState) error {
-	return fn(ctx, s)
+func (fn ComposedResourceDeleterFn) DeleteComposedResources(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
+	return fn(ctx, req, s)
 }
 
 // A ComposedResourceObserver derives additional state by observing composed
@@ -237,6 +242,14 @@ func WithFunctionPipelineRunner(r FunctionPipelineRunner) PTFComposerOption {
 	}
 }
 
+// WithPTFComposerMetrics configures how the PTFComposer should record metrics
+// about the composite resources it composes.
+func WithPTFComposerMetrics(m *CompositionMetrics) PTFComposerOption {
+	return func(p *PTFComposer) {
+		p.metrics = m
+	}
+}
+
 // NewPTFComposer returns a new Composer that supports composing resources using
 // both Patch and Transform (P&T) logic and a pipeline of Composition Functions.
 func NewPTFComposer(kube client.Client, o ...PTFComposerOption) *PTFComposer {
@@ -262,6 +275,7 @@ func NewPTFComposer(kube client.Client, o ...PTFComposerOption) *PTFComposer {
 			PatchAndTransformer:    NewXRCDPatchAndTransformer(RendererFn(RenderComposite), NewAPIDryRunRenderer(kube)),
 			FunctionPipelineRunner: NewFunctionPipeline(ContainerFunctionRunnerFn(RunFunction)),
 		},
+		metrics: NewCompositionMetrics(),
 	}
 
 	for _, fn := range o {
@@ -309,8 +323,14 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 
 	// Run P&T logic, updating the composition state accordingly.
 	if err := c.composition.PatchAndTransform(ctx, req, state); err != nil {
+		c.metrics.CountError(xr, req.Revision, StagePatch)
 		return CompositionResult{}, errors.Wrap(err, errPatchAndTransform)
 	}
+	for _, cd := range state.ComposedResources {
+		if cd.TemplateRenderErr != nil {
+			c.metrics.CountError(xr, req.Revision, StageRender)
+		}
+	}
 
 	// Build the initial desired state to be passed to our Composition Function
 	// pipeline. It's expected that each function in the pipeline will mutate
@@ -324,13 +344,14 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 	// Note that this will replace state.Composite with a new object that was
 	// unmarshalled from the function pipeline's desired state.
 	if err := c.composition.RunFunctionPipeline(ctx, req, state, o, d); err != nil {
+		c.metrics.CountError(xr, req.Revision, StageFunction)
 		return CompositionResult{}, errors.Wrap(err, errRunFunctionPipeline)
 	}
 
 	// Garbage collect any resources that aren't part of our final desired
 	// state. We must do this before we update the XR's resource references to
 	// ensure that we don't forget and leak them if a delete fails.
-	if err := c.composite.DeleteComposedResources(ctx, state); err != nil {
+	if err := c.composite.DeleteComposedResources(ctx, req, state); err != nil {
 		return CompositionResult{}, errors.Wrap(err, errDeleteUndesiredCDs)
 	}
 
@@ -354,6 +375,7 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 	// We apply all of our composed resources before we observe them and update
 	// in the loop below. This ensures that issues observing and processing one
 	// composed resource won't block the application of another.
+	applied := 0
 	for _, cd := range state.ComposedResources {
 		// Don't try to apply this resource if we didn't render it successfully
 		// during Patch & Transform Composition. It's possible that cd.Resource
@@ -367,13 +389,19 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 		}
 
 		ao := []resource.ApplyOption{resource.MustBeControllableBy(state.Composite.GetUID())}
+		if cd.Template != nil && cd.Template.NameTemplate != nil {
+			ao = append(ao, mustBeControlledBy(state.Composite.GetUID()))
+		}
 		if cd.Template != nil {
 			ao = append(ao, mergeOptions(filterPatches(cd.Template.Patches, patchTypesFromXR()...))...)
 		}
 		if err := c.client.Apply(ctx, cd.Resource, ao...); err != nil {
+			c.metrics.ObserveApplied(xr, req.Revision, applied)
 			return CompositionResult{}, errors.Wrapf(err, errFmtApplyCD, cd.ResourceName)
 		}
+		applied++
 	}
+	c.metrics.ObserveApplied(xr, req.Revision, applied)
 
 	// Observe all existing composed resources. This derives the XR's connection
 	// details from those of the composed resources. It also runs any readiness
@@ -384,7 +412,7 @@ func (c *PTFComposer) Compose(ctx context.Context, xr resource.Composite, req Co
 
 	out := make([]ComposedResource, 0, len(state.ComposedResources))
 	for _, cd := range state.ComposedResources {
-		out = append(out, cd.ComposedResource)
+		out = append(out, cd.Summary())
 	}
 
 	return CompositionResult{ConnectionDetails: state.ConnectionDetails, Composed: out, Events: state.Events}, nil
@@ -718,16 +746,55 @@ func (fn ContainerFunctionRunnerFn) RunFunction(ctx context.Context, fnio *iov1a
 	return fn(ctx, fnio, fnc, o...)
 }
 
+// MaxRequirementsIterations is the maximum number of times a Composition
+// Function will be run in order to satisfy the requirements it returns. The
+// function is first run without extra resources, then re-run each time it
+// returns requirements that differ from those it returned previously.
+const MaxRequirementsIterations = 5
+
 // A FunctionPipeline runs a pipeline of Composition Functions.
 type FunctionPipeline struct {
 	container     ContainerFunctionRunner
 	containerOpts []ContainerFunctionRunnerOption
+	extra         ExtraResourcesFetcher
+	metrics       *CompositionMetrics
+}
+
+// A FunctionPipelineOption configures a FunctionPipeline.
+type FunctionPipelineOption func(p *FunctionPipeline)
+
+// WithContainerFunctionRunnerOptions configures the options a FunctionPipeline
+// passes to its ContainerFunctionRunner.
+func WithContainerFunctionRunnerOptions(o ...ContainerFunctionRunnerOption) FunctionPipelineOption {
+	return func(p *FunctionPipeline) {
+		p.containerOpts = o
+	}
+}
+
+// WithExtraResourcesFetcher configures how a FunctionPipeline fetches the extra
+// resources Composition Functions require.
+func WithExtraResourcesFetcher(f ExtraResourcesFetcher) FunctionPipelineOption {
+	return func(p *FunctionPipeline) {
+		p.extra = f
+	}
+}
+
+// WithFunctionPipelineMetrics configures how a FunctionPipeline records how
+// long each Composition Function takes to run.
+func WithFunctionPipelineMetrics(m *CompositionMetrics) FunctionPipelineOption {
+	return func(p *FunctionPipeline) {
+		p.metrics = m
+	}
 }
 
 // NewFunctionPipeline returns a FunctionPipeline that runs functions using the
 // supplied ContainerFunctionRunner.
-func NewFunctionPipeline(c ContainerFunctionRunner, o ...ContainerFunctionRunnerOption) *FunctionPipeline {
-	return &FunctionPipeline{container: c, containerOpts: o}
+func NewFunctionPipeline(c ContainerFunctionRunner, o ...FunctionPipelineOption) *FunctionPipeline {
+	p := &FunctionPipeline{container: c, extra: NewNopExtraResourcesFetcher(), metrics: NewCompositionMetrics()}
+	for _, fn := range o {
+		fn(p)
+	}
+	return p
 }
 
 // RunFunctionPipeline runs a pipeline of Composition Functions.
@@ -736,7 +803,9 @@ func (p *FunctionPipeline) RunFunctionPipeline(ctx context.Context, req Composit
 	for _, fn := range req.Revision.Spec.Functions {
 		switch fn.Type {
 		case v1.FunctionTypeContainer:
-			fnio, err := p.container.RunFunction(ctx, &iov1alpha1.FunctionIO{Config: fn.Config, Observed: o, Desired: d, Results: r}, fn.Container, p.containerOpts...)
+			start := time.Now()
+			fnio, err := p.runContainerFunction(ctx, &iov1alpha1.FunctionIO{Config: fn.Config, Observed: o, Desired: d, Results: r}, fn.Container)
+			p.metrics.ObserveFunction(req.Revision, fn.Name, time.Since(start))
 			if err != nil {
 				return errors.Wrapf(err, errFmtRunFn, fn.Name)
 			}
@@ -788,6 +857,40 @@ func (p *FunctionPipeline) RunFunctionPipeline(ctx context.Context, req Composit
 	return nil
 }
 
+// runContainerFunction runs the supplied containerized function until the
+// requirements it returns stabilize, supplying it with any extra resources it
+// requires.
+func (p *FunctionPipeline) runContainerFunction(ctx context.Context, in *iov1alpha1.FunctionIO, fn *v1.ContainerFunction) (*iov1alpha1.FunctionIO, error) {
+	var rq *iov1alpha1.Requirements
+	for i := 0; i < MaxRequirementsIterations; i++ {
+		out, err := p.container.RunFunction(ctx, in, fn, p.containerOpts...)
+		if err != nil {
+			return nil, err
+		}
+
+		// The function is done once it stops asking for anything new. This
+		// includes the case where it never asked for anything at all.
+		if out.Requirements == nil || len(out.Requirements.ExtraResources) == 0 || reflect.DeepEqual(rq, out.Requirements) {
+			return out, nil
+		}
+		rq = out.Requirements
+
+		extra, err := p.extra.Fetch(ctx, rq)
+		if err != nil {
+			return nil, errors.Wrap(err, errFetchExtraResources)
+		}
+
+		// We only supply the function with its original input and the extra
+		// resources it asked for. Any desired state it returned while its
+		// requirements were unsatisfied is discarded.
+		next := in.DeepCopy()
+		next.ExtraResources = extra
+		in = next
+	}
+
+	return nil, errors.Errorf(errFmtRequirementsUnstable, MaxRequirementsIterations)
+}
+
 // RunFunction calls an external container function runner via gRPC.
 func RunFunction(ctx context.Context, fnio *iov1alpha1.FunctionIO, fn *v1.ContainerFunction, o ...ContainerFunctionRunnerOption) (*iov1alpha1.FunctionIO, error) {
 	in, err := yaml.Marshal(fnio)
@@ -945,8 +1048,9 @@ func NewUndesiredComposedResourceDeleter(c client.Writer) *UndesiredComposedReso
 // DeleteComposedResources deletes any composed resource that didn't come out the other
 // end of the Composition Function pipeline (i.e. that wasn't in the final
 // desired state after running the pipeline). Composed resources are deleted
-// from both the supposed composition state and from the API server.
-func (d *UndesiredComposedResourceDeleter) DeleteComposedResources(ctx context.Context, s *PTFCompositionState) error {
+// from both the supposed composition state and from the API server, unless the
+// Composition's RemovedResourcePolicy is to orphan them.
+func (d *UndesiredComposedResourceDeleter) DeleteComposedResources(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 	for name, cd := range s.ComposedResources {
 		// We know this resource is still desired because we recorded its
 		// desired state after running the FunctionIO pipeline. Don't garbage
@@ -968,7 +1072,7 @@ func (d *UndesiredComposedResourceDeleter) DeleteComposedResources(ctx context.C
 			continue
 		}
 
-		if err := d.client.Delete(ctx, cd.Resource); resource.IgnoreNotFound(err) != nil {
+		if err := RemoveComposedResource(ctx, d.client, s.Composite, cd.Resource, req.Revision.Spec.RemovedResourcePolicy); err != nil {
 			return errors.Wrapf(err, errFmtDeleteCD, cd.ResourceName, cd.Resource.GetObjectKind().GroupVersionKind().Kind, cd.Resource.GetName())
 		}
 	}
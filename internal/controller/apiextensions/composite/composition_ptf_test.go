@@ -18,6 +18,7 @@ package composite
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"sync"
 	"testing"
@@ -187,7 +188,7 @@ func TestPTFCompose(t *testing.T) {
 			},
 		},
 		"RunFunctionPipelineError": {
-			reason: "We should return any error encountered while running the Composition Function pipeline.",
+			reason: "We should return any error encountered while running the Composition Function pipeline, without garbage collecting any composed resources.",
 			params: params{
 				o: []PTFComposerOption{
 					WithCompositeConnectionDetailsFetcher(ConnectionDetailsFetcherFn(func(ctx context.Context, o resource.ConnectionSecretOwner) (managed.ConnectionDetails, error) {
@@ -202,6 +203,12 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return errBoom
 					})),
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
+						// A partial render doesn't tell us which composed
+						// resources are no longer desired.
+						t.Errorf("DeleteComposedResources should not be called when the function pipeline fails")
+						return nil
+					})),
 				},
 			},
 			args: args{
@@ -227,7 +234,7 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return nil
 					})),
-					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, s *PTFCompositionState) error {
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 						return errBoom
 					})),
 				},
@@ -259,7 +266,7 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return nil
 					})),
-					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, s *PTFCompositionState) error {
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 						return nil
 					})),
 				},
@@ -311,7 +318,7 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return nil
 					})),
-					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, s *PTFCompositionState) error {
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 						return nil
 					})),
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, s *PTFCompositionState) error {
@@ -323,7 +330,7 @@ func TestPTFCompose(t *testing.T) {
 				xr: &fake.Composite{},
 			},
 			want: want{
-				res: CompositionResult{Composed: []ComposedResource{{ResourceName: "cool-resource"}}},
+				res: CompositionResult{Composed: []ComposedResource{{ResourceName: "cool-resource", SyncError: errBoom.Error()}}},
 				err: nil,
 			},
 		},
@@ -364,7 +371,7 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return nil
 					})),
-					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, s *PTFCompositionState) error {
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 						return nil
 					})),
 				},
@@ -397,7 +404,7 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return nil
 					})),
-					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, s *PTFCompositionState) error {
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 						return nil
 					})),
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, s *PTFCompositionState) error {
@@ -441,7 +448,7 @@ func TestPTFCompose(t *testing.T) {
 					WithFunctionPipelineRunner(FunctionPipelineRunnerFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState, o iov1alpha1.Observed, d iov1alpha1.Desired) error {
 						return nil
 					})),
-					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, s *PTFCompositionState) error {
+					WithComposedResourceDeleter(ComposedResourceDeleterFn(func(ctx context.Context, req CompositionRequest, s *PTFCompositionState) error {
 						return nil
 					})),
 					WithComposedResourceObserver(ComposedResourceObserverFn(func(ctx context.Context, s *PTFCompositionState) error {
@@ -1231,6 +1238,7 @@ func TestRunFunctionPipeline(t *testing.T) {
 
 	type params struct {
 		c ContainerFunctionRunner
+		o []FunctionPipelineOption
 	}
 
 	type args struct {
@@ -1414,6 +1422,141 @@ func TestRunFunctionPipeline(t *testing.T) {
 				err: errors.Wrapf(errors.Wrap(json.Unmarshal([]byte("}"), nil), errUnmarshalDesiredCD), errFmtParseDesiredCD, "cool-resource"),
 			},
 		},
+		"RequirementsUnstableError": {
+			reason: "We should return an error if a function's requirements never stabilize.",
+			params: params{
+				c: func() ContainerFunctionRunnerFn {
+					i := 0
+					return func(ctx context.Context, fnio *iov1alpha1.FunctionIO, fn *v1.ContainerFunction, o ...ContainerFunctionRunnerOption) (*iov1alpha1.FunctionIO, error) {
+						// Ask for a different resource every time.
+						i++
+						return &iov1alpha1.FunctionIO{
+							Requirements: &iov1alpha1.Requirements{
+								ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+									{
+										Name:       "cool-extra",
+										APIVersion: "v1",
+										Kind:       "ConfigMap",
+										MatchName:  pointer.String(fmt.Sprintf("cool-cm-%d", i)),
+									},
+								},
+							},
+						}, nil
+					}
+				}(),
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Functions: []v1.Function{
+								{
+									Name: "cool-fn",
+									Type: v1.FunctionTypeContainer,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf(errFmtRequirementsUnstable, MaxRequirementsIterations), errFmtRunFn, "cool-fn"),
+			},
+		},
+		"FetchExtraResourcesError": {
+			reason: "We should return an error if we can't fetch the extra resources a function requires.",
+			params: params{
+				c: ContainerFunctionRunnerFn(func(ctx context.Context, fnio *iov1alpha1.FunctionIO, fn *v1.ContainerFunction, o ...ContainerFunctionRunnerOption) (*iov1alpha1.FunctionIO, error) {
+					return &iov1alpha1.FunctionIO{
+						Requirements: &iov1alpha1.Requirements{
+							ExtraResources: []iov1alpha1.ExtraResourceRequirement{{Name: "cool-extra"}},
+						},
+					}, nil
+				}),
+				o: []FunctionPipelineOption{
+					WithExtraResourcesFetcher(ExtraResourcesFetcherFn(func(ctx context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error) {
+						return nil, errBoom
+					})),
+				},
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Functions: []v1.Function{
+								{
+									Name: "cool-fn",
+									Type: v1.FunctionTypeContainer,
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, errFetchExtraResources), errFmtRunFn, "cool-fn"),
+			},
+		},
+		"RequirementsSatisfied": {
+			reason: "We should re-run a function with the extra resources it requires, and use the desired state it returns once its requirements stabilize.",
+			params: params{
+				c: ContainerFunctionRunnerFn(func(ctx context.Context, fnio *iov1alpha1.FunctionIO, fn *v1.ContainerFunction, o ...ContainerFunctionRunnerOption) (*iov1alpha1.FunctionIO, error) {
+					out := &iov1alpha1.FunctionIO{
+						Requirements: &iov1alpha1.Requirements{
+							ExtraResources: []iov1alpha1.ExtraResourceRequirement{{Name: "cool-extra"}},
+						},
+					}
+					// Only return our desired XR once we have what we need.
+					if len(fnio.ExtraResources) == 1 && len(fnio.ExtraResources[0].Resources) == 1 {
+						out.Desired = iov1alpha1.Desired{
+							Composite: iov1alpha1.DesiredComposite{
+								Resource: fnio.ExtraResources[0].Resources[0],
+							},
+						}
+					}
+					return out, nil
+				}),
+				o: []FunctionPipelineOption{
+					WithExtraResourcesFetcher(ExtraResourcesFetcherFn(func(ctx context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error) {
+						return []iov1alpha1.ExtraResources{
+							{
+								Name:      "cool-extra",
+								Resources: []runtime.RawExtension{{Raw: []byte(`{"apiVersion":"a/v1","kind":"XR"}`)}},
+							},
+						}, nil
+					})),
+				},
+			},
+			args: args{
+				req: CompositionRequest{
+					Revision: &v1.CompositionRevision{
+						Spec: v1.CompositionRevisionSpec{
+							Functions: []v1.Function{
+								{
+									Name: "cool-fn",
+									Type: v1.FunctionTypeContainer,
+								},
+							},
+						},
+					},
+				},
+				s: &PTFCompositionState{
+					ComposedResources: ComposedResourceStates{},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite: func() *composite.Unstructured {
+						xr := composite.New()
+						xr.SetAPIVersion("a/v1")
+						xr.SetKind("XR")
+						return xr
+					}(),
+					ConnectionDetails: managed.ConnectionDetails{},
+					ComposedResources: ComposedResourceStates{},
+				},
+			},
+		},
 		"Success": {
 			reason: "We should update our CompositionState with the results of the pipeline.",
 			params: params{
@@ -1509,7 +1652,7 @@ func TestRunFunctionPipeline(t *testing.T) {
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
 
-			r := NewFunctionPipeline(tc.params.c)
+			r := NewFunctionPipeline(tc.params.c, tc.params.o...)
 			err := r.RunFunctionPipeline(tc.args.ctx, tc.args.req, tc.args.s, tc.args.o, tc.args.d)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
@@ -1905,6 +2048,7 @@ func TestRunFunctionConfig(t *testing.T) {
 
 func TestDeleteComposedResources(t *testing.T) {
 	errBoom := errors.New("boom")
+	orphan := v1.RemovedResourcePolicyOrphan
 
 	type params struct {
 		client client.Writer
@@ -1912,6 +2056,7 @@ func TestDeleteComposedResources(t *testing.T) {
 
 	type args struct {
 		ctx context.Context
+		req CompositionRequest
 		s   *PTFCompositionState
 	}
 
@@ -1929,6 +2074,7 @@ func TestDeleteComposedResources(t *testing.T) {
 		"HasDesired": {
 			reason: "Desired resources should not be deleted.",
 			args: args{
+				req: CompositionRequest{Revision: &v1.CompositionRevision{}},
 				s: &PTFCompositionState{
 					ComposedResources: ComposedResourceStates{
 						"desired-resource": ComposedResourceState{
@@ -1962,6 +2108,7 @@ func TestDeleteComposedResources(t *testing.T) {
 				},
 			},
 			args: args{
+				req: CompositionRequest{Revision: &v1.CompositionRevision{}},
 				s: &PTFCompositionState{
 					ComposedResources: ComposedResourceStates{
 						"undesired-resource": ComposedResourceState{
@@ -1986,6 +2133,7 @@ func TestDeleteComposedResources(t *testing.T) {
 				},
 			},
 			args: args{
+				req: CompositionRequest{Revision: &v1.CompositionRevision{}},
 				s: &PTFCompositionState{
 					Composite: &fake.Composite{
 						ObjectMeta: metav1.ObjectMeta{
@@ -2024,6 +2172,7 @@ func TestDeleteComposedResources(t *testing.T) {
 				},
 			},
 			args: args{
+				req: CompositionRequest{Revision: &v1.CompositionRevision{}},
 				s: &PTFCompositionState{
 					Composite: &fake.Composite{
 						ObjectMeta: metav1.ObjectMeta{
@@ -2063,6 +2212,61 @@ func TestDeleteComposedResources(t *testing.T) {
 				err: errors.Wrapf(errBoom, errFmtDeleteCD, "undesired-resource", "", ""),
 			},
 		},
+		"Orphan": {
+			reason: "We should orphan rather than delete the resource when the RemovedResourcePolicy is Orphan.",
+			params: params{
+				client: &test.MockClient{
+					MockDelete: test.NewMockDeleteFn(errors.New("Delete should not be called")),
+					MockPatch: test.NewMockPatchFn(nil, func(obj client.Object) error {
+						if len(obj.GetOwnerReferences()) != 0 {
+							t.Errorf("Patch(...): want no owner references, got %v", obj.GetOwnerReferences())
+						}
+						return nil
+					}),
+				},
+			},
+			args: args{
+				req: CompositionRequest{Revision: &v1.CompositionRevision{
+					Spec: v1.CompositionRevisionSpec{RemovedResourcePolicy: &orphan},
+				}},
+				s: &PTFCompositionState{
+					Composite: &fake.Composite{
+						ObjectMeta: metav1.ObjectMeta{
+							UID: "cool-xr",
+						},
+					},
+					ComposedResources: ComposedResourceStates{
+						"undesired-resource": ComposedResourceState{
+							ComposedResource: ComposedResource{
+								ResourceName: "undesired-resource",
+							},
+							Resource: &fake.Composed{
+								ObjectMeta: metav1.ObjectMeta{
+									// This resource exists in the API server.
+									CreationTimestamp: metav1.Now(),
+
+									// This resource is controlled by the XR.
+									OwnerReferences: []metav1.OwnerReference{{
+										Controller: pointer.Bool(true),
+										UID:        "cool-xr",
+									}},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: want{
+				s: &PTFCompositionState{
+					Composite: &fake.Composite{
+						ObjectMeta: metav1.ObjectMeta{
+							UID: "cool-xr",
+						},
+					},
+					ComposedResources: ComposedResourceStates{},
+				},
+			},
+		},
 		"Success": {
 			reason: "We should successfully delete the resource from the API server and state.",
 			params: params{
@@ -2071,6 +2275,7 @@ func TestDeleteComposedResources(t *testing.T) {
 				},
 			},
 			args: args{
+				req: CompositionRequest{Revision: &v1.CompositionRevision{}},
 				s: &PTFCompositionState{
 					Composite: &fake.Composite{
 						ObjectMeta: metav1.ObjectMeta{
@@ -2115,7 +2320,7 @@ func TestDeleteComposedResources(t *testing.T) {
 		t.Run(name, func(t *testing.T) {
 
 			d := NewUndesiredComposedResourceDeleter(tc.params.client)
-			err := d.DeleteComposedResources(tc.args.ctx, tc.args.s)
+			err := d.DeleteComposedResources(tc.args.ctx, tc.args.req, tc.args.s)
 
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\nDeleteComposedResources(...): -want, +got:\n%s", tc.reason, diff)
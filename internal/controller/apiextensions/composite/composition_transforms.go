@@ -25,6 +25,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"hash/adler32"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
@@ -303,6 +304,10 @@ func ResolveString(t v1.StringTransform, input any) (string, error) {
 			return "", errors.Errorf(errStringTransformTypeRegexp, string(t.Type))
 		}
 		return stringRegexpTransform(input, *t.Regexp)
+	case v1.StringTransformTypeHash:
+		return stringHashTransform(input, t.Hash)
+	case v1.StringTransformTypeURLEncode:
+		return url.QueryEscape(fmt.Sprintf("%v", input)), nil
 	default:
 		return "", errors.Errorf(errStringTransformTypeFailed, string(t.Type))
 	}
@@ -367,6 +372,18 @@ func stringTrimTransform(input any, t v1.StringTransformType, trim string) strin
 	return str
 }
 
+func stringHashTransform(input any, h *v1.StringTransformHash) (string, error) {
+	hash, err := stringGenerateHash(input, sha256.Sum256)
+	if err != nil {
+		return "", errors.Wrap(err, errHash)
+	}
+	out := hex.EncodeToString(hash[:])
+	if h != nil && h.Length != nil && *h.Length > 0 && *h.Length < len(out) {
+		return out[:*h.Length], nil
+	}
+	return out, nil
+}
+
 func stringRegexpTransform(input any, r v1.StringTransformRegexp) (string, error) {
 	re, err := regexp.Compile(r.Match)
 	if err != nil {
@@ -653,6 +653,7 @@ func TestStringResolve(t *testing.T) {
 		convert *v1.StringConversionType
 		trim    *string
 		regexp  *v1.StringTransformRegexp
+		hash    *v1.StringTransformHash
 		i       any
 	}
 	type want struct {
@@ -992,6 +993,34 @@ func TestStringResolve(t *testing.T) {
 				err: errors.Errorf(errStringTransformTypeRegexpNoMatch, "my-([0-9]+)-string", 2),
 			},
 		},
+		"Hash": {
+			args: args{
+				stype: v1.StringTransformTypeHash,
+				i:     "Crossplane",
+			},
+			want: want{
+				o: "19c8a7c24ed0067f606815b59e5b82d92935ff69deed04171457a55018e31224",
+			},
+		},
+		"HashPrefix": {
+			args: args{
+				stype: v1.StringTransformTypeHash,
+				hash:  &v1.StringTransformHash{Length: pointer.Int(8)},
+				i:     "Crossplane",
+			},
+			want: want{
+				o: "19c8a7c2",
+			},
+		},
+		"URLEncode": {
+			args: args{
+				stype: v1.StringTransformTypeURLEncode,
+				i:     "cool/resource name?",
+			},
+			want: want{
+				o: "cool%2Fresource+name%3F",
+			},
+		},
 		"ConvertToJSONSuccess": {
 			args: args{
 				stype:   v1.StringTransformTypeConvert,
@@ -1026,6 +1055,7 @@ func TestStringResolve(t *testing.T) {
 				Convert: tc.convert,
 				Trim:    tc.trim,
 				Regexp:  tc.regexp,
+				Hash:    tc.hash,
 			}
 
 			got, err := ResolveString(tr, tc.i)
@@ -178,6 +178,41 @@ func (c *SecretStoreConnectionDetailsConfigurator) Configure(ctx context.Context
 	return errors.Wrap(c.client.Update(ctx, cp), errUpdateComposite)
 }
 
+// ShouldPublishConnection returns true if the supplied connection details of a
+// composite resource should be published, per the WriteConnectionSecretPolicy
+// of the supplied CompositionRevision. Connection details are always
+// published unless the policy is WhenReady, in which case they're published
+// only once every connection detail declared by the revision's resource
+// templates is present and all composed resources are ready.
+func ShouldPublishConnection(rev *v1.CompositionRevision, conn managed.ConnectionDetails, ready bool) bool {
+	p := rev.Spec.WriteConnectionSecretPolicy
+	if p == nil || *p != v1.WriteConnectionSecretPolicyWhenReady {
+		return true
+	}
+	if !ready {
+		return false
+	}
+	for i := range rev.Spec.Resources {
+		for _, cfg := range ExtractConfigsFromTemplate(&rev.Spec.Resources[i]) {
+			if _, ok := conn[cfg.Name]; cfg.Name != "" && !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// SetConnectionDetailsCount records how many connection details were published
+// for the supplied composite resource at status.connectionDetails.count. It
+// does nothing if the composite resource is not unstructured.
+func SetConnectionDetailsCount(xr resource.Composite, n int) error {
+	u, ok := xr.(interface{ UnstructuredContent() map[string]any })
+	if !ok {
+		return nil
+	}
+	return fieldpath.Pave(u.UnstructuredContent()).SetNumber("status.connectionDetails.count", float64(n))
+}
+
 // ConnectionDetailsExtractor extracts the connection details of a resource.
 type ConnectionDetailsExtractor interface {
 	// ExtractConnection of the supplied resource.
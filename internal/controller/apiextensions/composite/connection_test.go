@@ -598,3 +598,76 @@ func TestConnectionDetailType(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldPublishConnection(t *testing.T) {
+	incremental := v1.WriteConnectionSecretPolicyIncremental
+	whenReady := v1.WriteConnectionSecretPolicyWhenReady
+	resources := []v1.ComposedTemplate{{
+		ConnectionDetails: []v1.ConnectionDetail{
+			{Name: pointer.String("username")},
+			{FromConnectionSecretKey: pointer.String("password")},
+		},
+	}}
+
+	type args struct {
+		rev   *v1.CompositionRevision
+		conn  managed.ConnectionDetails
+		ready bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoPolicy": {
+			reason: "We should publish connection details incrementally by default.",
+			args: args{
+				rev: &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Resources: resources}},
+			},
+			want: true,
+		},
+		"Incremental": {
+			reason: "We should publish incomplete connection details if the policy is Incremental.",
+			args: args{
+				rev: &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Resources: resources, WriteConnectionSecretPolicy: &incremental}},
+			},
+			want: true,
+		},
+		"WhenReadyNotReady": {
+			reason: "We should not publish connection details if the policy is WhenReady and the XR is not ready.",
+			args: args{
+				rev:  &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Resources: resources, WriteConnectionSecretPolicy: &whenReady}},
+				conn: managed.ConnectionDetails{"username": []byte("cool"), "password": []byte("secret")},
+			},
+			want: false,
+		},
+		"WhenReadyMissingKey": {
+			reason: "We should not publish connection details if the policy is WhenReady and a declared key is missing.",
+			args: args{
+				rev:   &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Resources: resources, WriteConnectionSecretPolicy: &whenReady}},
+				conn:  managed.ConnectionDetails{"username": []byte("cool")},
+				ready: true,
+			},
+			want: false,
+		},
+		"WhenReadyComplete": {
+			reason: "We should publish connection details if the policy is WhenReady, all declared keys are present, and the XR is ready.",
+			args: args{
+				rev:   &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{Resources: resources, WriteConnectionSecretPolicy: &whenReady}},
+				conn:  managed.ConnectionDetails{"username": []byte("cool"), "password": []byte("secret")},
+				ready: true,
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ShouldPublishConnection(tc.args.rev, tc.args.conn, tc.args.ready)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nShouldPublishConnection(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// DefaultDeletionTierTimeout is the default amount of time we'll wait for a
+// composed resource to be deleted before we delete the resources it depends
+// on anyway.
+const DefaultDeletionTierTimeout = 5 * time.Minute
+
+// Error strings.
+const (
+	errDeleteComposed = "cannot delete composed resource"
+)
+
+// A DeletionOrderer deletes the resources composed by a composite resource
+// that is being deleted, in order.
+type DeletionOrderer interface {
+	// DeleteInOrder deletes composed resources that are ready to be deleted.
+	// It returns the number of composed resources that still exist. The
+	// composite resource should not be deleted until none remain.
+	DeleteInOrder(ctx context.Context, xr resource.Composite) (remaining int, err error)
+}
+
+// A DeletionOrdererFn deletes the resources composed by a composite resource
+// that is being deleted, in order.
+type DeletionOrdererFn func(ctx context.Context, xr resource.Composite) (int, error)
+
+// DeleteInOrder deletes composed resources that are ready to be deleted.
+func (fn DeletionOrdererFn) DeleteInOrder(ctx context.Context, xr resource.Composite) (int, error) {
+	return fn(ctx, xr)
+}
+
+// An APIDeletionOrdererOption configures an APIDeletionOrderer.
+type APIDeletionOrdererOption func(d *APIDeletionOrderer)
+
+// WithDeletionTierTimeout configures how long an APIDeletionOrderer waits for
+// a composed resource to be deleted before it deletes the resources it depends
+// on anyway.
+func WithDeletionTierTimeout(t time.Duration) APIDeletionOrdererOption {
+	return func(d *APIDeletionOrderer) {
+		d.timeout = t
+	}
+}
+
+// An APIDeletionOrderer deletes composed resources in the order described by
+// the dependsOn fields of the CompositionRevision a composite resource uses. A
+// composed resource is only deleted once all of the composed resources that
+// depend on it are gone.
+type APIDeletionOrderer struct {
+	client  client.Client
+	timeout time.Duration
+}
+
+// NewAPIDeletionOrderer returns a DeletionOrderer that deletes composed
+// resources in dependency order.
+func NewAPIDeletionOrderer(c client.Client, o ...APIDeletionOrdererOption) *APIDeletionOrderer {
+	d := &APIDeletionOrderer{client: c, timeout: DefaultDeletionTierTimeout}
+	for _, fn := range o {
+		fn(d)
+	}
+	return d
+}
+
+// DeleteInOrder deletes the composed resources of the supplied composite
+// resource that no remaining composed resource depends on. It does nothing if
+// the CompositionRevision the composite resource uses doesn't order deletion,
+// leaving composed resources to be garbage collected once the composite
+// resource is gone.
+func (d *APIDeletionOrderer) DeleteInOrder(ctx context.Context, xr resource.Composite) (int, error) { //nolint:gocyclo // Only slightly over.
+	ref := xr.GetCompositionRevisionReference()
+	if ref == nil {
+		return 0, nil
+	}
+
+	rev := &v1.CompositionRevision{}
+	if err := d.client.Get(ctx, types.NamespacedName{Name: ref.Name}, rev); err != nil {
+		// We can't order deletion without the revision. Fall back to garbage
+		// collection rather than blocking deletion forever.
+		return 0, errors.Wrap(resource.IgnoreNotFound(err), errGetCompositionRevision)
+	}
+
+	// dependents maps each composed resource name to the names of the
+	// composed resources that depend on it.
+	dependents := map[string][]string{}
+	for _, t := range rev.Spec.Resources {
+		for _, dep := range t.DependsOn {
+			dependents[dep] = append(dependents[dep], t.GetName())
+		}
+	}
+	if len(dependents) == 0 {
+		return 0, nil
+	}
+
+	existing := map[string]*composed.Unstructured{}
+	for _, ref := range xr.GetResourceReferences() {
+		if ref.Name == "" {
+			continue
+		}
+		cd := composed.New(composed.FromReference(ref))
+		err := d.client.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cd)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, errors.Wrap(err, errGetComposed)
+		}
+		if c := metav1.GetControllerOf(cd); c == nil || c.UID != xr.GetUID() {
+			// We don't control this resource, so it's not ours to delete.
+			continue
+		}
+		existing[deletionKey(cd)] = cd
+	}
+
+	for name, cd := range existing {
+		if cd.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if d.blocked(name, existing, dependents) {
+			continue
+		}
+		if err := d.client.Delete(ctx, cd); resource.IgnoreNotFound(err) != nil {
+			return 0, errors.Wrap(err, errDeleteComposed)
+		}
+	}
+
+	return len(existing), nil
+}
+
+// deletionKey returns the key a composed resource is tracked by while its
+// deletion is ordered. This is usually its composition resource name. Anonymous
+// composed resources have no name, and nothing can depend on them, so they're
+// keyed by their GVK and name instead.
+func deletionKey(cd *composed.Unstructured) string {
+	if n := GetCompositionResourceName(cd); n != "" {
+		return n
+	}
+	return cd.GetObjectKind().GroupVersionKind().String() + "/" + cd.GetName()
+}
+
+// blocked returns true if any of the resources that depend on the named
+// resource still exist. A dependent that has been deleting for longer than the
+// tier timeout no longer blocks deletion.
+func (d *APIDeletionOrderer) blocked(name string, existing map[string]*composed.Unstructured, dependents map[string][]string) bool {
+	for _, dep := range dependents[name] {
+		cd, ok := existing[dep]
+		if !ok {
+			continue
+		}
+		dt := cd.GetDeletionTimestamp()
+		if dt == nil || time.Since(dt.Time) < d.timeout {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestDeleteInOrder(t *testing.T) {
+	errBoom := errors.New("boom")
+	uid := types.UID("cool-xr")
+	now := metav1.Now()
+	anHourAgo := metav1.NewTime(now.Add(-1 * time.Hour))
+
+	xr := func() *composite.Unstructured {
+		xr := composite.New()
+		xr.SetUID(uid)
+		xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: "cool-rev"})
+		xr.SetResourceReferences([]corev1.ObjectReference{
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "zone"},
+			{APIVersion: "v1", Kind: "ConfigMap", Name: "record"},
+		})
+		return xr
+	}
+
+	// A revision in which the record depends on the zone.
+	rev := func(obj client.Object) {
+		obj.(*v1.CompositionRevision).Spec.Resources = []v1.ComposedTemplate{
+			{Name: pointer.String("zone")},
+			{Name: pointer.String("record"), DependsOn: []string{"zone"}},
+		}
+	}
+
+	// withComposed returns a MockGetFn that returns the revision, and the
+	// supplied composed resources by name.
+	withComposed := func(cds map[string]*metav1.Time) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if _, ok := obj.(*v1.CompositionRevision); ok {
+				rev(obj)
+				return nil
+			}
+			dt, ok := cds[key.Name]
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			obj.SetName(key.Name)
+			obj.SetDeletionTimestamp(dt)
+			obj.SetOwnerReferences([]metav1.OwnerReference{{UID: uid, Controller: pointer.Bool(true)}})
+			SetCompositionResourceName(obj, key.Name)
+			return nil
+		}
+	}
+
+	type params struct {
+		c client.Client
+		o []APIDeletionOrdererOption
+	}
+	type args struct {
+		ctx context.Context
+		xr  resource.Composite
+	}
+	type want struct {
+		remaining int
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"NoCompositionRevision": {
+			reason: "We should not order deletion if the XR doesn't reference a CompositionRevision.",
+			params: params{
+				c: &test.MockClient{},
+			},
+			args: args{
+				xr: composite.New(),
+			},
+			want: want{
+				remaining: 0,
+			},
+		},
+		"CompositionRevisionNotFound": {
+			reason: "We should not order deletion if the CompositionRevision doesn't exist.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-rev")),
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 0,
+			},
+		},
+		"GetCompositionRevisionError": {
+			reason: "We should return any error encountered getting the CompositionRevision.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCompositionRevision),
+			},
+		},
+		"NoDependencies": {
+			reason: "We should not order deletion if the CompositionRevision has no dependencies.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 0,
+			},
+		},
+		"GetComposedError": {
+			reason: "We should return any error encountered getting a composed resource.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+						if _, ok := obj.(*v1.CompositionRevision); ok {
+							rev(obj)
+							return nil
+						}
+						return errBoom
+					},
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetComposed),
+			},
+		},
+		"DeleteDependentsFirst": {
+			reason: "We should only delete composed resources that nothing depends on.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: withComposed(map[string]*metav1.Time{"zone": nil, "record": nil}),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() != "record" {
+							t.Errorf("Delete(...): deleted %q before the resources that depend on it", obj.GetName())
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 2,
+			},
+		},
+		"WaitForDependents": {
+			reason: "We should not delete a composed resource while the resources that depend on it are being deleted.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: withComposed(map[string]*metav1.Time{"zone": nil, "record": &now}),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						t.Errorf("Delete(...): unexpectedly deleted %q", obj.GetName())
+						return nil
+					},
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 2,
+			},
+		},
+		"DependentsTimedOut": {
+			reason: "We should delete a composed resource if the resources that depend on it have been deleting for longer than the tier timeout.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: withComposed(map[string]*metav1.Time{"zone": nil, "record": &anHourAgo}),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() != "zone" {
+							t.Errorf("Delete(...): unexpectedly deleted %q", obj.GetName())
+						}
+						return nil
+					},
+				},
+				o: []APIDeletionOrdererOption{WithDeletionTierTimeout(1 * time.Minute)},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 2,
+			},
+		},
+		"DependentsGone": {
+			reason: "We should delete a composed resource once the resources that depend on it are gone.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: withComposed(map[string]*metav1.Time{"zone": nil}),
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() != "zone" {
+							t.Errorf("Delete(...): unexpectedly deleted %q", obj.GetName())
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 1,
+			},
+		},
+		"AnonymousResources": {
+			reason: "We should track each anonymous composed resource separately, and delete them without waiting for anything.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+						if _, ok := obj.(*v1.CompositionRevision); ok {
+							rev(obj)
+							return nil
+						}
+						obj.SetName(key.Name)
+						obj.SetOwnerReferences([]metav1.OwnerReference{{UID: uid, Controller: pointer.Bool(true)}})
+						if key.Name == "zone" || key.Name == "record" {
+							SetCompositionResourceName(obj, key.Name)
+						}
+						return nil
+					},
+					MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+						if obj.GetName() == "zone" {
+							t.Errorf("Delete(...): deleted %q before the resources that depend on it", obj.GetName())
+						}
+						return nil
+					},
+				},
+			},
+			args: args{
+				xr: func() *composite.Unstructured {
+					xr := xr()
+					xr.SetResourceReferences(append(xr.GetResourceReferences(),
+						corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "anonymous-a"},
+						corev1.ObjectReference{APIVersion: "v1", Kind: "ConfigMap", Name: "anonymous-b"},
+					))
+					return xr
+				}(),
+			},
+			want: want{
+				remaining: 4,
+			},
+		},
+		"DeleteComposedError": {
+			reason: "We should return any error encountered deleting a composed resource.",
+			params: params{
+				c: &test.MockClient{
+					MockGet:    withComposed(map[string]*metav1.Time{"zone": nil}),
+					MockDelete: test.NewMockDeleteFn(errBoom),
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteComposed),
+			},
+		},
+		"AllGone": {
+			reason: "We should return that no composed resources remain once they're all gone.",
+			params: params{
+				c: &test.MockClient{
+					MockGet: withComposed(map[string]*metav1.Time{}),
+				},
+			},
+			args: args{
+				xr: xr(),
+			},
+			want: want{
+				remaining: 0,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			d := NewAPIDeletionOrderer(tc.params.c, tc.params.o...)
+			remaining, err := d.DeleteInOrder(tc.args.ctx, tc.args.xr)
+
+			if diff := cmp.Diff(tc.want.remaining, remaining); diff != "" {
+				t.Errorf("\n%s\nDeleteInOrder(...): -want remaining, +got remaining:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nDeleteInOrder(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
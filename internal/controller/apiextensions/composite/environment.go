@@ -0,0 +1,109 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+// MaxResolvedEnvironmentSize is the maximum size, in bytes of JSON, of the
+// resolved environment recorded in a composite resource's status. Larger
+// environments are recorded only by their hash.
+const MaxResolvedEnvironmentSize = 4096
+
+// RedactedValue replaces values of the resolved environment that may contain
+// secrets.
+const RedactedValue = "REDACTED"
+
+// Values with keys that match this pattern are redacted from the recorded
+// resolved environment.
+var sensitiveKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|credential|private[-_]?key|api[-_]?key)`)
+
+// A resolvedEnvironment records the environment used to compose a composite
+// resource in its status.
+type resolvedEnvironment struct {
+	Data      map[string]any `json:"data,omitempty"`
+	Hash      string         `json:"hash"`
+	Truncated bool           `json:"truncated,omitempty"`
+}
+
+// SetResolvedEnvironment records the supplied environment at
+// status.resolvedEnvironment of the supplied composite resource. Values with
+// keys that may contain secrets are redacted. The environment's data is omitted
+// and only its hash is recorded if it's larger than MaxResolvedEnvironmentSize.
+// It does nothing if the composite resource is not unstructured, or the
+// environment is nil.
+func SetResolvedEnvironment(xr resource.Composite, e *env.Environment) error {
+	u, ok := xr.(interface{ UnstructuredContent() map[string]any })
+	if !ok || e == nil {
+		return nil
+	}
+
+	data := make(map[string]any, len(e.Object))
+	for k, v := range e.Object {
+		// The environment's apiVersion and kind are only used for patching.
+		if k == "apiVersion" || k == "kind" {
+			continue
+		}
+		data[k] = redact(k, v)
+	}
+
+	j, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	re := resolvedEnvironment{Hash: fmt.Sprintf("%x", sha256.Sum256(j))}
+	if len(j) > MaxResolvedEnvironmentSize {
+		re.Truncated = true
+	} else {
+		re.Data = data
+	}
+
+	return fieldpath.Pave(u.UnstructuredContent()).SetValue("status.resolvedEnvironment", re)
+}
+
+// redact returns a copy of the supplied value, found at the supplied key, with
+// any values whose keys may contain secrets replaced by RedactedValue.
+func redact(key string, v any) any {
+	if sensitiveKey.MatchString(key) {
+		return RedactedValue
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, v := range t {
+			out[k] = redact(k, v)
+		}
+		return out
+	case []any:
+		out := make([]any, len(t))
+		for i, v := range t {
+			out[i] = redact(key, v)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	env "github.com/crossplane/crossplane/internal/controller/apiextensions/composite/environment"
+)
+
+func TestSetResolvedEnvironment(t *testing.T) {
+	hash := func(data map[string]any) string {
+		j, _ := json.Marshal(data)
+		return fmt.Sprintf("%x", sha256.Sum256(j))
+	}
+	large := strings.Repeat("a", MaxResolvedEnvironmentSize)
+
+	type args struct {
+		xr  resource.Composite
+		env *env.Environment
+	}
+	type want struct {
+		xr  resource.Composite
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotUnstructured": {
+			reason: "We should do nothing if the composite resource is not unstructured.",
+			args: args{
+				xr:  &fake.Composite{},
+				env: &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{"cool": "env"}}},
+			},
+			want: want{
+				xr: &fake.Composite{},
+			},
+		},
+		"NilEnvironment": {
+			reason: "We should do nothing if there is no environment.",
+			args: args{
+				xr: composite.New(),
+			},
+			want: want{
+				xr: composite.New(),
+			},
+		},
+		"Redacted": {
+			reason: "We should record the environment, redacting values with keys that may contain secrets.",
+			args: args{
+				xr: composite.New(),
+				env: &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "internal.crossplane.io/v1alpha1",
+					"kind":       "Environment",
+					"region":     "us-west-2",
+					"database": map[string]any{
+						"host":     "db.example.org",
+						"password": "hunter2",
+					},
+					"users": []any{
+						map[string]any{"name": "cool", "apiToken": "supersecret"},
+					},
+				}}},
+			},
+			want: want{
+				xr: func() resource.Composite {
+					data := map[string]any{
+						"region": "us-west-2",
+						"database": map[string]any{
+							"host":     "db.example.org",
+							"password": RedactedValue,
+						},
+						"users": []any{
+							map[string]any{"name": "cool", "apiToken": RedactedValue},
+						},
+					}
+					xr := composite.New()
+					xr.Object["status"] = map[string]any{
+						"resolvedEnvironment": map[string]any{
+							"data": data,
+							"hash": hash(data),
+						},
+					}
+					return xr
+				}(),
+			},
+		},
+		"Truncated": {
+			reason: "We should record only the hash of an environment that is too large.",
+			args: args{
+				xr: composite.New(),
+				env: &env.Environment{Unstructured: unstructured.Unstructured{Object: map[string]any{
+					"large": large,
+				}}},
+			},
+			want: want{
+				xr: func() resource.Composite {
+					xr := composite.New()
+					xr.Object["status"] = map[string]any{
+						"resolvedEnvironment": map[string]any{
+							"hash":      hash(map[string]any{"large": large}),
+							"truncated": true,
+						},
+					}
+					return xr
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := SetResolvedEnvironment(tc.args.xr, tc.args.env)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nSetResolvedEnvironment(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.xr, tc.args.xr); diff != "" {
+				t.Errorf("\n%s\nSetResolvedEnvironment(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/json"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+)
+
+// DefaultMaxExtraResources is the default maximum number of extra resources
+// that may be fetched to satisfy the requirements of a single function.
+const DefaultMaxExtraResources = 100
+
+// Error strings.
+const (
+	errFmtExtraResourceKindNotAllowed = "extra resource requirement %q requests kind %s, which is not allowed"
+	errFmtExtraResourceNoSelector     = "extra resource requirement %q must specify either matchName or matchLabels"
+	errFmtGetExtraResource            = "cannot get extra resource for requirement %q"
+	errFmtListExtraResources          = "cannot list extra resources for requirement %q"
+	errFmtMarshalExtraResource        = "cannot marshal extra resource for requirement %q"
+	errFmtTooManyExtraResources       = "extra resource requirements matched more than the maximum of %d resources"
+)
+
+// An ExtraResourcesFetcher fetches the extra resources a Composition Function
+// requires.
+type ExtraResourcesFetcher interface {
+	Fetch(ctx context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error)
+}
+
+// An ExtraResourcesFetcherFn fetches the extra resources a Composition
+// Function requires.
+type ExtraResourcesFetcherFn func(ctx context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error)
+
+// Fetch the extra resources a Composition Function requires.
+func (fn ExtraResourcesFetcherFn) Fetch(ctx context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error) {
+	return fn(ctx, rq)
+}
+
+// A NopExtraResourcesFetcher satisfies any requirement with an empty set of
+// resources.
+type NopExtraResourcesFetcher struct{}
+
+// NewNopExtraResourcesFetcher returns an ExtraResourcesFetcher that never
+// fetches any resources.
+func NewNopExtraResourcesFetcher() *NopExtraResourcesFetcher {
+	return &NopExtraResourcesFetcher{}
+}
+
+// Fetch returns an empty set of resources for each requirement.
+func (f *NopExtraResourcesFetcher) Fetch(_ context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error) {
+	if rq == nil {
+		return nil, nil
+	}
+	out := make([]iov1alpha1.ExtraResources, 0, len(rq.ExtraResources))
+	for _, er := range rq.ExtraResources {
+		out = append(out, iov1alpha1.ExtraResources{Name: er.Name})
+	}
+	return out, nil
+}
+
+// An APIExtraResourcesFetcherOption configures an APIExtraResourcesFetcher.
+type APIExtraResourcesFetcherOption func(f *APIExtraResourcesFetcher)
+
+// WithMaxExtraResources configures the maximum number of resources an
+// APIExtraResourcesFetcher will fetch to satisfy one set of requirements.
+func WithMaxExtraResources(n int) APIExtraResourcesFetcherOption {
+	return func(f *APIExtraResourcesFetcher) {
+		f.max = n
+	}
+}
+
+// WithAllowedExtraResourceKinds configures the kinds of resources an
+// APIExtraResourcesFetcher will fetch. Any kind may be fetched if no kinds
+// are supplied.
+func WithAllowedExtraResourceKinds(gvks ...schema.GroupVersionKind) APIExtraResourcesFetcherOption {
+	return func(f *APIExtraResourcesFetcher) {
+		f.allowed = make(map[schema.GroupVersionKind]bool, len(gvks))
+		for _, gvk := range gvks {
+			f.allowed[gvk] = true
+		}
+	}
+}
+
+// An APIExtraResourcesFetcher fetches the extra resources a Composition
+// Function requires from the API server. It only ever reads resources.
+type APIExtraResourcesFetcher struct {
+	client  client.Reader
+	max     int
+	allowed map[schema.GroupVersionKind]bool
+}
+
+// NewAPIExtraResourcesFetcher returns an ExtraResourcesFetcher that fetches
+// extra resources from the API server.
+func NewAPIExtraResourcesFetcher(c client.Reader, o ...APIExtraResourcesFetcherOption) *APIExtraResourcesFetcher {
+	f := &APIExtraResourcesFetcher{client: c, max: DefaultMaxExtraResources}
+	for _, fn := range o {
+		fn(f)
+	}
+	return f
+}
+
+// Fetch the extra resources that satisfy the supplied requirements. Resources
+// that are requested by name but don't exist are omitted. It returns an error
+// if the requirements match more than the maximum number of resources.
+func (f *APIExtraResourcesFetcher) Fetch(ctx context.Context, rq *iov1alpha1.Requirements) ([]iov1alpha1.ExtraResources, error) { //nolint:gocyclo // Only slightly over (11).
+	if rq == nil {
+		return nil, nil
+	}
+
+	total := 0
+	out := make([]iov1alpha1.ExtraResources, 0, len(rq.ExtraResources))
+	for _, er := range rq.ExtraResources {
+		gvk := schema.FromAPIVersionAndKind(er.APIVersion, er.Kind)
+		if len(f.allowed) > 0 && !f.allowed[gvk] {
+			return nil, errors.Errorf(errFmtExtraResourceKindNotAllowed, er.Name, gvk)
+		}
+
+		ns := ""
+		if er.Namespace != nil {
+			ns = *er.Namespace
+		}
+
+		var items []kunstructured.Unstructured
+		switch {
+		case er.MatchName != nil:
+			u := &kunstructured.Unstructured{}
+			u.SetGroupVersionKind(gvk)
+			err := f.client.Get(ctx, types.NamespacedName{Namespace: ns, Name: *er.MatchName}, u)
+			if kerrors.IsNotFound(err) {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtGetExtraResource, er.Name)
+			}
+			items = append(items, *u)
+		case len(er.MatchLabels) > 0:
+			l := &kunstructured.UnstructuredList{}
+			l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+			// We only need to list one more resource than we're allowed to
+			// fetch to know the requirements match too many resources.
+			limit := client.Limit(int64(f.max - total + 1))
+			if err := f.client.List(ctx, l, client.InNamespace(ns), client.MatchingLabels(er.MatchLabels), limit); err != nil {
+				return nil, errors.Wrapf(err, errFmtListExtraResources, er.Name)
+			}
+			items = l.Items
+		default:
+			return nil, errors.Errorf(errFmtExtraResourceNoSelector, er.Name)
+		}
+
+		total += len(items)
+		if total > f.max {
+			return nil, errors.Errorf(errFmtTooManyExtraResources, f.max)
+		}
+
+		rs := iov1alpha1.ExtraResources{Name: er.Name, Resources: make([]runtime.RawExtension, 0, len(items))}
+		for i := range items {
+			raw, err := json.Marshal(&items[i])
+			if err != nil {
+				return nil, errors.Wrapf(err, errFmtMarshalExtraResource, er.Name)
+			}
+			rs.Resources = append(rs.Resources, runtime.RawExtension{Raw: raw})
+		}
+		out = append(out, rs)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	iov1alpha1 "github.com/crossplane/crossplane/apis/apiextensions/fn/io/v1alpha1"
+)
+
+func TestAPIExtraResourcesFetcher(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type params struct {
+		c client.Reader
+		o []APIExtraResourcesFetcherOption
+	}
+	type args struct {
+		ctx context.Context
+		rq  *iov1alpha1.Requirements
+	}
+	type want struct {
+		er  []iov1alpha1.ExtraResources
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		params params
+		args   args
+		want   want
+	}{
+		"NilRequirements": {
+			reason: "We should return early if there are no requirements.",
+			params: params{
+				c: &test.MockClient{},
+			},
+			args: args{},
+			want: want{},
+		},
+		"KindNotAllowed": {
+			reason: "We should return an error if a requirement requests a kind that is not allowed.",
+			params: params{
+				c: &test.MockClient{},
+				o: []APIExtraResourcesFetcherOption{
+					WithAllowedExtraResourceKinds(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}),
+				},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "Secret", MatchName: pointer.String("cool-secret")},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtExtraResourceKindNotAllowed, "cool-extra", schema.GroupVersionKind{Version: "v1", Kind: "Secret"}),
+			},
+		},
+		"NoSelector": {
+			reason: "We should return an error if a requirement specifies neither matchName nor matchLabels.",
+			params: params{
+				c: &test.MockClient{},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap"},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtExtraResourceNoSelector, "cool-extra"),
+			},
+		},
+		"GetError": {
+			reason: "We should return an error if we can't get a resource by name.",
+			params: params{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap", MatchName: pointer.String("cool-cm")},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtGetExtraResource, "cool-extra"),
+			},
+		},
+		"GetNotFound": {
+			reason: "We should omit a resource requested by name that doesn't exist.",
+			params: params{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool-cm"))},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap", MatchName: pointer.String("cool-cm")},
+					},
+				},
+			},
+			want: want{
+				er: []iov1alpha1.ExtraResources{{Name: "cool-extra", Resources: []runtime.RawExtension{}}},
+			},
+		},
+		"GetSuccess": {
+			reason: "We should return a resource requested by name.",
+			params: params{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.SetName("cool-cm")
+					return nil
+				})},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap", MatchName: pointer.String("cool-cm")},
+					},
+				},
+			},
+			want: want{
+				er: []iov1alpha1.ExtraResources{{
+					Name:      "cool-extra",
+					Resources: []runtime.RawExtension{{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cool-cm"}}`)}},
+				}},
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list resources by label.",
+			params: params{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap", MatchLabels: map[string]string{"cool": "very"}},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errFmtListExtraResources, "cool-extra"),
+			},
+		},
+		"TooManyResources": {
+			reason: "We should return an error if the requirements match more than the maximum number of resources.",
+			params: params{
+				c: &test.MockClient{MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					if lo.Limit != 2 {
+						t.Errorf("List(...): want limit 2, got %d", lo.Limit)
+					}
+					l := obj.(*kunstructured.UnstructuredList)
+					l.Items = make([]kunstructured.Unstructured, lo.Limit)
+					return nil
+				}},
+				o: []APIExtraResourcesFetcherOption{WithMaxExtraResources(1)},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap", MatchLabels: map[string]string{"cool": "very"}},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtTooManyExtraResources, 1),
+			},
+		},
+		"ListSuccess": {
+			reason: "We should return all resources matching the requested labels.",
+			params: params{
+				c: &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+					l := obj.(*kunstructured.UnstructuredList)
+					cm := kunstructured.Unstructured{}
+					cm.SetAPIVersion("v1")
+					cm.SetKind("ConfigMap")
+					cm.SetName("cool-cm")
+					l.Items = []kunstructured.Unstructured{cm}
+					return nil
+				})},
+				o: []APIExtraResourcesFetcherOption{
+					WithAllowedExtraResourceKinds(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}),
+				},
+			},
+			args: args{
+				rq: &iov1alpha1.Requirements{
+					ExtraResources: []iov1alpha1.ExtraResourceRequirement{
+						{Name: "cool-extra", APIVersion: "v1", Kind: "ConfigMap", MatchLabels: map[string]string{"cool": "very"}},
+					},
+				},
+			},
+			want: want{
+				er: []iov1alpha1.ExtraResources{{
+					Name:      "cool-extra",
+					Resources: []runtime.RawExtension{{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cool-cm"}}`)}},
+				}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			f := NewAPIExtraResourcesFetcher(tc.params.c, tc.params.o...)
+			er, err := f.Fetch(tc.args.ctx, tc.args.rq)
+
+			if diff := cmp.Diff(tc.want.er, er); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nFetch(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
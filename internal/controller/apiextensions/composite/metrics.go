@@ -0,0 +1,144 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Composition stages that may encounter errors.
+const (
+	StageRender   = "render"
+	StagePatch    = "patch"
+	StageFunction = "function"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crossplane_composite_reconcile_duration_seconds",
+		Help:    "How long it took to reconcile a composite resource.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"composite", "composition"})
+
+	composedResourcesApplied = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crossplane_composite_composed_resources_applied",
+		Help:    "The number of composed resources applied by each reconcile of a composite resource.",
+		Buckets: []float64{0, 1, 2, 5, 10, 25, 50, 100},
+	}, []string{"composite", "composition"})
+
+	compositionErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crossplane_composition_errors_total",
+		Help: "The number of errors encountered rendering, patching, or running the functions of a Composition.",
+	}, []string{"composite", "composition", "stage"})
+
+	functionDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "crossplane_composition_function_duration_seconds",
+		Help:    "How long it took to run each Composition Function in a pipeline.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"composite", "composition", "function"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(reconcileDuration, composedResourcesApplied, compositionErrors, functionDuration)
+}
+
+// CompositionMetrics records metrics about the composition of composite
+// resources. Metrics are labelled by the kind of composite resource, and
+// optionally by the name of the Composition it uses.
+type CompositionMetrics struct {
+	composition bool
+}
+
+// A CompositionMetricsOption configures CompositionMetrics.
+type CompositionMetricsOption func(m *CompositionMetrics)
+
+// WithoutCompositionLabel configures CompositionMetrics not to label metrics
+// with the name of the Composition. This bounds the cardinality of metrics
+// in control planes with many Compositions.
+func WithoutCompositionLabel() CompositionMetricsOption {
+	return func(m *CompositionMetrics) {
+		m.composition = false
+	}
+}
+
+// NewCompositionMetrics returns CompositionMetrics that label metrics with
+// the name of the Composition by default.
+func NewCompositionMetrics(o ...CompositionMetricsOption) *CompositionMetrics {
+	m := &CompositionMetrics{composition: true}
+	for _, fn := range o {
+		fn(m)
+	}
+	return m
+}
+
+// ObserveReconcile records how long the supplied composite resource took to
+// reconcile.
+func (m *CompositionMetrics) ObserveReconcile(xr resource.Composite, d time.Duration) {
+	name := ""
+	if ref := xr.GetCompositionReference(); ref != nil {
+		name = ref.Name
+	}
+	reconcileDuration.WithLabelValues(CompositeLabel(xr.GetObjectKind().GroupVersionKind()), m.compositionLabel(name)).Observe(d.Seconds())
+}
+
+// ObserveApplied records how many composed resources were applied while
+// composing the supplied composite resource.
+func (m *CompositionMetrics) ObserveApplied(xr resource.Composite, rev *v1.CompositionRevision, n int) {
+	composedResourcesApplied.WithLabelValues(m.labels(xr, rev)...).Observe(float64(n))
+}
+
+// CountError records an error composing the supplied composite resource at the
+// supplied stage of composition.
+func (m *CompositionMetrics) CountError(xr resource.Composite, rev *v1.CompositionRevision, stage string) {
+	compositionErrors.WithLabelValues(append(m.labels(xr, rev), stage)...).Inc()
+}
+
+// ObserveFunction records how long the named Composition Function of the
+// supplied CompositionRevision took to run.
+func (m *CompositionMetrics) ObserveFunction(rev *v1.CompositionRevision, fn string, d time.Duration) {
+	xr := schema.FromAPIVersionAndKind(rev.Spec.CompositeTypeRef.APIVersion, rev.Spec.CompositeTypeRef.Kind)
+	functionDuration.WithLabelValues(CompositeLabel(xr), m.compositionLabel(rev.GetLabels()[v1.LabelCompositionName]), fn).Observe(d.Seconds())
+}
+
+func (m *CompositionMetrics) labels(xr resource.Composite, rev *v1.CompositionRevision) []string {
+	name := ""
+	if rev != nil {
+		name = rev.GetLabels()[v1.LabelCompositionName]
+	}
+	return []string{CompositeLabel(xr.GetObjectKind().GroupVersionKind()), m.compositionLabel(name)}
+}
+
+func (m *CompositionMetrics) compositionLabel(name string) string {
+	if !m.composition {
+		return ""
+	}
+	return name
+}
+
+// CompositeLabel returns the value with which metrics about the supplied kind
+// of composite resource are labelled, e.g. XPostgreSQLInstance.example.org/v1.
+func CompositeLabel(gvk schema.GroupVersionKind) string {
+	return gvk.Kind + "." + gvk.GroupVersion().String()
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestCompositionMetricsLabels(t *testing.T) {
+	xr := composite.New(composite.WithGroupVersionKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR"}))
+	rev := &v1.CompositionRevision{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{v1.LabelCompositionName: "cool"}}}
+
+	type args struct {
+		o   []CompositionMetricsOption
+		rev *v1.CompositionRevision
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"CompositionLabel": {
+			reason: "Metrics should be labelled with the composite resource's kind and the Composition's name by default.",
+			args: args{
+				rev: rev,
+			},
+			want: []string{"XR.example.org/v1", "cool"},
+		},
+		"WithoutCompositionLabel": {
+			reason: "Metrics should not be labelled with the Composition's name when the label is disabled.",
+			args: args{
+				o:   []CompositionMetricsOption{WithoutCompositionLabel()},
+				rev: rev,
+			},
+			want: []string{"XR.example.org/v1", ""},
+		},
+		"NoRevision": {
+			reason: "Metrics should have an empty Composition label when there is no CompositionRevision.",
+			want:   []string{"XR.example.org/v1", ""},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewCompositionMetrics(tc.args.o...).labels(xr, tc.args.rev)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nlabels(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
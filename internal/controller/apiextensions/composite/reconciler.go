@@ -46,28 +46,34 @@ import (
 const (
 	timeout             = 2 * time.Minute
 	defaultPollInterval = 1 * time.Minute
+	deletePollInterval  = 10 * time.Second
 	finalizer           = "composite.apiextensions.crossplane.io"
 )
 
 // Error strings
 const (
-	errGet                    = "cannot get composite resource"
-	errUpdate                 = "cannot update composite resource"
-	errUpdateStatus           = "cannot update composite resource status"
-	errAddFinalizer           = "cannot add composite resource finalizer"
-	errRemoveFinalizer        = "cannot remove composite resource finalizer"
-	errSelectComp             = "cannot select Composition"
-	errSelectCompUpdatePolicy = "cannot select CompositionUpdatePolicy"
-	errFetchComp              = "cannot fetch Composition"
-	errConfigure              = "cannot configure composite resource"
-	errPublish                = "cannot publish connection details"
-	errUnpublish              = "cannot unpublish connection details"
-	errValidate               = "refusing to use invalid Composition"
-	errAssociate              = "cannot associate composed resources with Composition resource templates"
-	errFetchEnvironment       = "cannot fetch environment"
-	errSelectEnvironment      = "cannot select environment"
-	errCompose                = "cannot compose resources"
-	errRenderCD               = "cannot render composed resource"
+	errGet                     = "cannot get composite resource"
+	errUpdate                  = "cannot update composite resource"
+	errUpdateStatus            = "cannot update composite resource status"
+	errAddFinalizer            = "cannot add composite resource finalizer"
+	errRemoveFinalizer         = "cannot remove composite resource finalizer"
+	errSelectComp              = "cannot select Composition"
+	errSelectCompUpdatePolicy  = "cannot select CompositionUpdatePolicy"
+	errFetchComp               = "cannot fetch Composition"
+	errConfigure               = "cannot configure composite resource"
+	errPublish                 = "cannot publish connection details"
+	errUnpublish               = "cannot unpublish connection details"
+	errSetConnectionCount      = "cannot record how many connection details were published"
+	errDeleteComposedResources = "cannot delete composed resources"
+	errValidate                = "refusing to use invalid Composition"
+	errAssociate               = "cannot associate composed resources with Composition resource templates"
+	errFetchEnvironment        = "cannot fetch environment"
+	errSelectEnvironment       = "cannot select environment"
+	errCompose                 = "cannot compose resources"
+	errRenderCD                = "cannot render composed resource"
+	errSetComposedStatus       = "cannot set composed resource status"
+	errSetResolvedEnvironment  = "cannot record resolved environment"
+	errWatchComposed           = "cannot watch composed resources"
 
 	errFmtPatchEnvironment = "cannot apply environment patch at index %d"
 )
@@ -245,6 +251,22 @@ func WithPollInterval(after time.Duration) ReconcilerOption {
 	}
 }
 
+// WithComposedResourceStatus specifies whether the Reconciler should summarize
+// the state of each composed resource in the status of the composite resource.
+func WithComposedResourceStatus(enabled bool) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composedStatus = enabled
+	}
+}
+
+// WithCompositionMetrics specifies how the Reconciler should record metrics
+// about the composite resources it reconciles.
+func WithCompositionMetrics(m *CompositionMetrics) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.metrics = m
+	}
+}
+
 // WithClient specifies how the Reconciler should interact with the Kubernetes
 // API.
 func WithClient(c client.Client) ReconcilerOption {
@@ -279,6 +301,14 @@ func WithCompositeFinalizer(f resource.Finalizer) ReconcilerOption {
 	}
 }
 
+// WithDeletionOrderer specifies how the Reconciler should order the deletion
+// of composed resources when their composite resource is deleted.
+func WithDeletionOrderer(d DeletionOrderer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.composite.DeletionOrderer = d
+	}
+}
+
 // WithCompositionSelector specifies how the composition to be used should be
 // selected.
 func WithCompositionSelector(s CompositionSelector) ReconcilerOption {
@@ -334,6 +364,15 @@ func WithComposer(c Composer) ReconcilerOption {
 	}
 }
 
+// WithComposedResourceWatcher specifies how the Reconciler should watch
+// composed resources. Composite resources are polled to notice changes to their
+// composed resources if they can't be watched.
+func WithComposedResourceWatcher(w ComposedResourceWatcher) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.watcher = w
+	}
+}
+
 type revision struct {
 	CompositionRevisionFetcher
 	CompositionRevisionValidator
@@ -359,6 +398,7 @@ type environment struct {
 
 type compositeResource struct {
 	resource.Finalizer
+	DeletionOrderer
 	CompositionSelector
 	CompositionUpdatePolicySelector
 	EnvironmentSelector
@@ -398,6 +438,7 @@ func NewReconciler(mgr manager.Manager, of resource.CompositeKind, opts ...Recon
 
 		composite: compositeResource{
 			Finalizer:           resource.NewAPIFinalizer(kube, finalizer),
+			DeletionOrderer:     NewAPIDeletionOrderer(kube),
 			CompositionSelector: NewAPILabelSelectorResolver(kube),
 			EnvironmentSelector: env.NewNoopEnvironmentSelector(),
 			Configurator:        NewConfiguratorChain(NewAPINamingConfigurator(kube), NewAPIConfigurator(kube)),
@@ -409,9 +450,11 @@ func NewReconciler(mgr manager.Manager, of resource.CompositeKind, opts ...Recon
 		},
 
 		resource: NewPTComposer(kube),
+		watcher:  NopComposedResourceWatcher{},
 
-		log:    logging.NewNopLogger(),
-		record: event.NewNopRecorder(),
+		log:     logging.NewNopLogger(),
+		record:  event.NewNopRecorder(),
+		metrics: NewCompositionMetrics(),
 
 		pollInterval: defaultPollInterval,
 	}
@@ -433,11 +476,14 @@ type Reconciler struct {
 	composite compositeResource
 
 	resource Composer
+	watcher  ComposedResourceWatcher
 
-	log    logging.Logger
-	record event.Recorder
+	log     logging.Logger
+	record  event.Recorder
+	metrics *CompositionMetrics
 
-	pollInterval time.Duration
+	pollInterval   time.Duration
+	composedStatus bool
 }
 
 // Reconcile a composite resource.
@@ -445,6 +491,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
+	start := time.Now()
+
 	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
@@ -454,6 +502,8 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGet)
 	}
 
+	defer func() { r.metrics.ObserveReconcile(xr, time.Since(start)) }()
+
 	log = log.WithValues(
 		"uid", xr.GetUID(),
 		"version", xr.GetResourceVersion(),
@@ -475,6 +525,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log = log.WithValues("deletion-timestamp", xr.GetDeletionTimestamp())
 
 		xr.SetConditions(xpv1.Deleting())
+
+		remaining, err := r.composite.DeleteInOrder(ctx, xr)
+		if err != nil {
+			log.Debug(errDeleteComposedResources, "error", err)
+			err = errors.Wrap(err, errDeleteComposedResources)
+			r.record.Event(xr, event.Warning(reasonDelete, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+		if remaining > 0 {
+			// Some composed resources must be deleted before others. We
+			// requeue to wait for them to disappear, since we can't watch
+			// them.
+			log.Debug("Waiting for composed resources to be deleted", "remaining", remaining)
+			xr.SetConditions(xpv1.Deleting().WithMessage(fmt.Sprintf("Waiting for %d composed resources to be deleted", remaining)), xpv1.ReconcileSuccess())
+			return reconcile.Result{RequeueAfter: deletePollInterval}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+
 		if err := r.composite.UnpublishConnection(ctx, xr, nil); err != nil {
 			log.Debug(errUnpublish, "error", err)
 			err = errors.Wrap(err, errUnpublish)
@@ -491,6 +559,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 		}
 
+		// Release any watches on the composed resources we deleted.
+		if err := r.watcher.WatchComposedResources(ctx, xr.GetUID()); err != nil {
+			log.Debug(errWatchComposed, "error", err)
+		}
+
 		log.Debug("Successfully deleted composite resource")
 		xr.SetConditions(xpv1.ReconcileSuccess())
 		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
@@ -578,16 +651,41 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
 	}
 
-	published, err := r.composite.PublishConnection(ctx, xr, res.ConnectionDetails)
-	if err != nil {
-		log.Debug(errPublish, "error", err)
-		err = errors.Wrap(err, errPublish)
-		r.record.Event(xr, event.Warning(reasonPublish, err))
-		xr.SetConditions(xpv1.ReconcileError(err))
-		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+	// We can't know what kinds of resource we'll compose until we've composed
+	// them. If we can't watch them we'll still notice changes when we poll.
+	if err := r.watcher.WatchComposedResources(ctx, xr.GetUID(), ComposedKinds(xr.GetResourceReferences())...); err != nil {
+		log.Debug(errWatchComposed, "error", err)
+		r.record.Event(xr, event.Warning(reasonCompose, errors.Wrap(err, errWatchComposed)))
+	}
+
+	allReady := true
+	for _, cd := range res.Composed {
+		allReady = allReady && cd.Ready
+	}
+
+	// Depending on the Composition's WriteConnectionSecretPolicy we may leave
+	// any previously published connection details untouched until they're
+	// complete and the XR is ready.
+	published := false
+	if ShouldPublishConnection(rev, res.ConnectionDetails, allReady) {
+		published, err = r.composite.PublishConnection(ctx, xr, res.ConnectionDetails)
+		if err != nil {
+			log.Debug(errPublish, "error", err)
+			err = errors.Wrap(err, errPublish)
+			r.record.Event(xr, event.Warning(reasonPublish, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
 	}
 	if published {
 		xr.SetConnectionDetailsLastPublishedTime(&metav1.Time{Time: time.Now()})
+		if err := SetConnectionDetailsCount(xr, len(res.ConnectionDetails)); err != nil {
+			log.Debug(errSetConnectionCount, "error", err)
+			err = errors.Wrap(err, errSetConnectionCount)
+			r.record.Event(xr, event.Warning(reasonPublish, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
 		log.Debug("Successfully published connection details")
 		r.record.Event(xr, event.Normal(reasonPublish, "Successfully published connection details"))
 	}
@@ -627,6 +725,28 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		ready++
 	}
 
+	// The environment may have been patched while composing resources, so we
+	// record it only once they're composed.
+	if rev.Spec.Environment.ShouldRecordResolved() {
+		if err := SetResolvedEnvironment(xr, env); err != nil {
+			log.Debug(errSetResolvedEnvironment, "error", err)
+			err = errors.Wrap(err, errSetResolvedEnvironment)
+			r.record.Event(xr, event.Warning(reasonCompose, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+	}
+
+	if r.composedStatus {
+		if err := SetComposedResourceStatus(xr, res.Composed); err != nil {
+			log.Debug(errSetComposedStatus, "error", err)
+			err = errors.Wrap(err, errSetComposedStatus)
+			r.record.Event(xr, event.Warning(reasonCompose, err))
+			xr.SetConditions(xpv1.ReconcileError(err))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, xr), errUpdateStatus)
+		}
+	}
+
 	xr.SetConditions(xpv1.ReconcileSuccess())
 
 	// TODO(muvaf): If a resource becomes Unavailable at some point, should we
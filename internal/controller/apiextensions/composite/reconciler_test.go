@@ -27,6 +27,7 @@ import (
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -94,6 +95,58 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGet),
 			},
 		},
+		"DeleteInOrderError": {
+			reason: "We should return any error encountered while deleting composed resources in order.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetDeletionTimestamp(&now)
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+							want.SetDeletionTimestamp(&now)
+							want.SetConditions(xpv1.Deleting(), xpv1.ReconcileError(errors.Wrap(errBoom, errDeleteComposedResources)))
+						})),
+					}),
+					WithDeletionOrderer(DeletionOrdererFn(func(ctx context.Context, xr resource.Composite) (int, error) {
+						return 0, errBoom
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: true},
+			},
+		},
+		"WaitingForComposedResourceDeletion": {
+			reason: "We should requeue without removing our finalizer while composed resources are being deleted in order.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: WithComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetDeletionTimestamp(&now)
+						})),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(want resource.Composite) {
+							want.SetDeletionTimestamp(&now)
+							want.SetConditions(xpv1.Deleting().WithMessage("Waiting for 2 composed resources to be deleted"), xpv1.ReconcileSuccess())
+						})),
+					}),
+					WithDeletionOrderer(DeletionOrdererFn(func(ctx context.Context, xr resource.Composite) (int, error) {
+						return 2, nil
+					})),
+					WithCompositeFinalizer(resource.FinalizerFns{
+						RemoveFinalizerFn: func(ctx context.Context, obj resource.Object) error {
+							t.Errorf("RemoveFinalizer should not be called while composed resources remain")
+							return nil
+						},
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: deletePollInterval},
+			},
+		},
 		"UnpublishConnectionError": {
 			reason: "We should return any error encountered while unpublishing connection details.",
 			args: args{
@@ -565,6 +618,7 @@ func TestReconcile(t *testing.T) {
 							cr.SetCompositionReference(&corev1.ObjectReference{})
 							cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 							cr.SetConnectionDetailsLastPublishedTime(&now)
+							_ = SetConnectionDetailsCount(cr, 1)
 						})),
 					}),
 					WithCompositeFinalizer(resource.NewNopFinalizer()),
@@ -601,6 +655,53 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{RequeueAfter: defaultPollInterval},
 			},
 		},
+		"ConnectionDetailsIncomplete": {
+			reason: "We should not publish connection details until all declared connection details are present if the write connection secret policy is WhenReady.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClient(&test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
+							cr.SetCompositionReference(&corev1.ObjectReference{})
+							cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
+						})),
+					}),
+					WithCompositeFinalizer(resource.NewNopFinalizer()),
+					WithCompositionSelector(CompositionSelectorFn(func(_ context.Context, cr resource.Composite) error {
+						cr.SetCompositionReference(&corev1.ObjectReference{})
+						return nil
+					})),
+					WithCompositionRevisionFetcher(CompositionRevisionFetcherFn(func(_ context.Context, _ resource.Composite) (*v1.CompositionRevision, error) {
+						p := v1.WriteConnectionSecretPolicyWhenReady
+						c := &v1.CompositionRevision{Spec: v1.CompositionRevisionSpec{
+							Resources: []v1.ComposedTemplate{{
+								ConnectionDetails: []v1.ConnectionDetail{{Name: pointer.String("missing")}},
+							}},
+							WriteConnectionSecretPolicy: &p,
+						}}
+						return c, nil
+					})),
+					WithCompositionRevisionValidator(CompositionRevisionValidatorFn(func(_ *v1.CompositionRevision) error { return nil })),
+					WithConfigurator(ConfiguratorFn(func(_ context.Context, _ resource.Composite, _ *v1.CompositionRevision) error {
+						return nil
+					})),
+					WithComposer(ComposerFn(func(ctx context.Context, xr resource.Composite, req CompositionRequest) (CompositionResult, error) {
+						return CompositionResult{ConnectionDetails: cd}, nil
+					})),
+					WithConnectionPublishers(managed.ConnectionPublisherFns{
+						PublishConnectionFn: func(ctx context.Context, o resource.ConnectionSecretOwner, got managed.ConnectionDetails) (published bool, err error) {
+							t.Errorf("PublishConnection(...): unexpected call")
+							return true, nil
+						},
+					}),
+					WithCompositionUpdatePolicySelector(CompositionUpdatePolicySelectorFn(func(ctx context.Context, cr resource.Composite) error { return nil })),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: defaultPollInterval},
+			},
+		},
 		"ReconciliationPausedSuccessful": {
 			reason: `If a composite resource has the pause annotation with value "true", there should be no further requeue requests.`,
 			args: args{
@@ -652,6 +753,7 @@ func TestReconcile(t *testing.T) {
 							cr.SetAnnotations(map[string]string{meta.AnnotationKeyReconciliationPaused: ""})
 							cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 							cr.SetConnectionDetailsLastPublishedTime(&now)
+							_ = SetConnectionDetailsCount(cr, 0)
 							cr.SetCompositionReference(&corev1.ObjectReference{})
 						})),
 					}),
@@ -699,6 +801,7 @@ func TestReconcile(t *testing.T) {
 						MockStatusUpdate: WantComposite(t, NewComposite(func(cr resource.Composite) {
 							cr.SetConditions(xpv1.ReconcileSuccess(), xpv1.Available())
 							cr.SetConnectionDetailsLastPublishedTime(&now)
+							_ = SetConnectionDetailsCount(cr, 0)
 							cr.SetCompositionReference(&corev1.ObjectReference{})
 						})),
 					}),
@@ -0,0 +1,341 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// Error strings.
+const (
+	errCreateWatchCache = "cannot create composed resource watch cache"
+	errStartWatch       = "cannot start composed resource watch"
+
+	errFmtNoController = "cannot watch composed resources: composite resource controller %q has not been created"
+	errFmtMaxWatches   = "cannot watch composed resources of kind %s: the maximum of %d composed resource watches are already running"
+)
+
+var composedResourceWatches = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "crossplane_composed_resource_watches",
+	Help: "The number of kinds of composed resource watched by each composite resource controller.",
+}, []string{"controller"})
+
+func init() {
+	metrics.Registry.MustRegister(composedResourceWatches)
+}
+
+// A ComposedResourceWatcher watches the composed resources of composite
+// resources, so that a composite resource is reconciled as soon as one of its
+// composed resources changes.
+type ComposedResourceWatcher interface {
+	// WatchComposedResources ensures the supplied kinds of composed resource
+	// are watched on behalf of the supplied composite resource. Any kinds the
+	// composite resource previously composed but no longer does are released.
+	WatchComposedResources(ctx context.Context, xr types.UID, kinds ...schema.GroupVersionKind) error
+}
+
+// A ComposedResourceWatcherFn watches the composed resources of composite
+// resources.
+type ComposedResourceWatcherFn func(ctx context.Context, xr types.UID, kinds ...schema.GroupVersionKind) error
+
+// WatchComposedResources ensures the supplied kinds of composed resource are
+// watched on behalf of the supplied composite resource.
+func (fn ComposedResourceWatcherFn) WatchComposedResources(ctx context.Context, xr types.UID, kinds ...schema.GroupVersionKind) error {
+	return fn(ctx, xr, kinds...)
+}
+
+// A NopComposedResourceWatcher does nothing. Composite resources are polled
+// to notice changes to their composed resources.
+type NopComposedResourceWatcher struct{}
+
+// WatchComposedResources does nothing.
+func (NopComposedResourceWatcher) WatchComposedResources(_ context.Context, _ types.UID, _ ...schema.GroupVersionKind) error {
+	return nil
+}
+
+// ComposedKinds returns the distinct kinds of the supplied composed resource
+// references, in the order they're first referenced.
+func ComposedKinds(refs []corev1.ObjectReference) []schema.GroupVersionKind {
+	seen := make(map[schema.GroupVersionKind]bool, len(refs))
+	kinds := make([]schema.GroupVersionKind, 0, len(refs))
+	for _, ref := range refs {
+		k := ref.GroupVersionKind()
+		if k.Kind == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		kinds = append(kinds, k)
+	}
+	return kinds
+}
+
+// A ComposedWatchEngine starts and stops watches on the composed resources of
+// composite resources. A composite resource controller can't know what kinds
+// of resource it will compose when it's started, so the engine adds a watch to
+// the controller the first time one of its composite resources composes a new
+// kind. Each watch has its own cache, which is stopped to free its memory once
+// no composite resource composes that kind.
+type ComposedWatchEngine struct {
+	mgr      manager.Manager
+	newCache controller.NewCacheFn
+	max      int
+	log      logging.Logger
+
+	controllers map[string]kcontroller.Controller
+	watches     map[string]map[schema.GroupVersionKind]*composedWatch
+	running     int
+	mx          sync.Mutex
+}
+
+type composedWatch struct {
+	stop  context.CancelFunc
+	users map[types.UID]bool
+}
+
+// A ComposedWatchEngineOption configures a ComposedWatchEngine.
+type ComposedWatchEngineOption func(*ComposedWatchEngine)
+
+// WithMaxComposedWatches limits the number of composed resource watches that
+// may run at once, across all composite resource controllers. Composite
+// resources that compose a kind that can't be watched fall back to polling.
+// The number of watches is not limited if max is zero.
+func WithMaxComposedWatches(max int) ComposedWatchEngineOption {
+	return func(e *ComposedWatchEngine) {
+		e.max = max
+	}
+}
+
+// WithComposedWatchCacheFn configures how the ComposedWatchEngine creates the
+// cache of each watch. controller.DefaultNewCacheFn is used by default.
+func WithComposedWatchCacheFn(fn controller.NewCacheFn) ComposedWatchEngineOption {
+	return func(e *ComposedWatchEngine) {
+		e.newCache = fn
+	}
+}
+
+// WithComposedWatchLogger specifies how the ComposedWatchEngine should log
+// messages.
+func WithComposedWatchLogger(l logging.Logger) ComposedWatchEngineOption {
+	return func(e *ComposedWatchEngine) {
+		e.log = l
+	}
+}
+
+// NewComposedWatchEngine returns a new ComposedWatchEngine.
+func NewComposedWatchEngine(mgr manager.Manager, o ...ComposedWatchEngineOption) *ComposedWatchEngine {
+	e := &ComposedWatchEngine{
+		mgr:      mgr,
+		newCache: controller.DefaultNewCacheFn,
+		log:      logging.NewNopLogger(),
+
+		controllers: make(map[string]kcontroller.Controller),
+		watches:     make(map[string]map[schema.GroupVersionKind]*composedWatch),
+	}
+
+	for _, fn := range o {
+		fn(e)
+	}
+
+	return e
+}
+
+// NewController creates a new composite resource controller. It satisfies
+// controller.NewControllerFn, and should be used by the controller.Engine that
+// starts composite resource controllers so that the ComposedWatchEngine can
+// add watches to them.
+func (e *ComposedWatchEngine) NewController(name string, m manager.Manager, o kcontroller.Options) (kcontroller.Controller, error) {
+	c, err := controller.DefaultNewControllerFn(name, m, o)
+	if err != nil {
+		return nil, err
+	}
+
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	// Any existing watches were added to the controller this one replaces.
+	for k := range e.watches[name] {
+		e.release(name, k)
+	}
+	e.controllers[name] = c
+
+	return c, nil
+}
+
+// For returns a ComposedResourceWatcher that adds watches to the named
+// composite resource controller, which reconciles the supplied kind of
+// composite resource.
+func (e *ComposedWatchEngine) For(name string, of resource.CompositeKind) ComposedResourceWatcher {
+	return ComposedResourceWatcherFn(func(_ context.Context, xr types.UID, kinds ...schema.GroupVersionKind) error {
+		return e.watch(name, of, xr, kinds...)
+	})
+}
+
+func (e *ComposedWatchEngine) watch(name string, of resource.CompositeKind, xr types.UID, kinds ...schema.GroupVersionKind) error {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	want := make(map[schema.GroupVersionKind]bool, len(kinds))
+	for _, k := range kinds {
+		want[k] = true
+	}
+
+	// Release any kinds this composite resource no longer composes, stopping
+	// their watch if no other composite resource composes them.
+	for k, w := range e.watches[name] {
+		if want[k] {
+			continue
+		}
+		delete(w.users, xr)
+		if len(w.users) == 0 {
+			e.release(name, k)
+		}
+	}
+
+	if len(kinds) == 0 {
+		return nil
+	}
+
+	c, ok := e.controllers[name]
+	if !ok {
+		return errors.Errorf(errFmtNoController, name)
+	}
+
+	for _, k := range kinds {
+		if w, ok := e.watches[name][k]; ok {
+			w.users[xr] = true
+			continue
+		}
+
+		if e.max > 0 && e.running >= e.max {
+			return errors.Errorf(errFmtMaxWatches, k, e.max)
+		}
+
+		w, err := e.start(c, name, of, k)
+		if err != nil {
+			return err
+		}
+		w.users[xr] = true
+
+		if e.watches[name] == nil {
+			e.watches[name] = make(map[schema.GroupVersionKind]*composedWatch)
+		}
+		e.watches[name][k] = w
+		e.running++
+		composedResourceWatches.WithLabelValues(name).Inc()
+		e.log.Debug("Started composed resource watch", "controller", name, "kind", k)
+	}
+
+	return nil
+}
+
+func (e *ComposedWatchEngine) start(c kcontroller.Controller, name string, of resource.CompositeKind, k schema.GroupVersionKind) (*composedWatch, error) {
+	ca, err := e.newCache(e.mgr.GetConfig(), cache.Options{Scheme: e.mgr.GetScheme(), Mapper: e.mgr.GetRESTMapper()})
+	if err != nil {
+		return nil, errors.Wrap(err, errCreateWatchCache)
+	}
+
+	ctx, stop := context.WithCancel(context.Background())
+	w := &composedWatch{stop: stop, users: make(map[types.UID]bool)}
+
+	cd := &kunstructured.Unstructured{}
+	cd.SetGroupVersionKind(k)
+
+	xr := &kunstructured.Unstructured{}
+	xr.SetGroupVersionKind(schema.GroupVersionKind(of))
+
+	// Composed resources are controlled by their composite resource.
+	h := handler.EnqueueRequestForOwner(e.mgr.GetScheme(), e.mgr.GetRESTMapper(), xr, handler.OnlyControllerOwner())
+
+	// The controller starts the watch using its own context. We start it using
+	// ours instead so that we can stop it without stopping the controller,
+	// and stop it ourselves when the controller stops.
+	src := source.Func(func(cctx context.Context, h handler.EventHandler, q workqueue.RateLimitingInterface, p ...predicate.Predicate) error {
+		go func() {
+			select {
+			case <-cctx.Done():
+				e.stopped(name, k, w)
+			case <-ctx.Done():
+			}
+		}()
+		return source.Kind(ca, cd).Start(ctx, h, q, p...)
+	})
+
+	if err := c.Watch(src, h); err != nil {
+		stop()
+		return nil, errors.Wrap(err, errStartWatch)
+	}
+
+	go func() {
+		select {
+		case <-e.mgr.Elected():
+		case <-ctx.Done():
+			return
+		}
+		if err := ca.Start(ctx); err != nil {
+			e.log.Debug("Composed resource watch stopped", "controller", name, "kind", k, "error", err)
+		}
+		// Forget the watch if its cache stopped unexpectedly, for example
+		// because its kind no longer exists, so that it may be restarted.
+		e.stopped(name, k, w)
+	}()
+
+	return w, nil
+}
+
+// stopped releases the supplied watch, if it is still running.
+func (e *ComposedWatchEngine) stopped(name string, k schema.GroupVersionKind, w *composedWatch) {
+	e.mx.Lock()
+	defer e.mx.Unlock()
+
+	if e.watches[name][k] != w {
+		return
+	}
+	e.release(name, k)
+}
+
+// release stops and forgets the supplied watch. The caller must hold the lock.
+func (e *ComposedWatchEngine) release(name string, k schema.GroupVersionKind) {
+	w, ok := e.watches[name][k]
+	if !ok {
+		return
+	}
+	w.stop()
+	delete(e.watches[name], k)
+	e.running--
+	composedResourceWatches.WithLabelValues(name).Dec()
+	e.log.Debug("Stopped composed resource watch", "controller", name, "kind", k)
+}
@@ -0,0 +1,255 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composite
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestComposedKinds(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		refs   []corev1.ObjectReference
+		want   []schema.GroupVersionKind
+	}{
+		"NoReferences": {
+			reason: "A composite resource that composes nothing composes no kinds.",
+			want:   []schema.GroupVersionKind{},
+		},
+		"DistinctKinds": {
+			reason: "Each kind should be returned once, in the order it was first referenced.",
+			refs: []corev1.ObjectReference{
+				{APIVersion: "example.org/v1", Kind: "Cool", Name: "a"},
+				{APIVersion: "example.org/v1", Kind: "Cooler", Name: "b"},
+				{APIVersion: "example.org/v1", Kind: "Cool", Name: "c"},
+				{Name: "unknown"},
+			},
+			want: []schema.GroupVersionKind{
+				{Group: "example.org", Version: "v1", Kind: "Cool"},
+				{Group: "example.org", Version: "v1", Kind: "Cooler"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ComposedKinds(tc.refs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nComposedKinds(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+type MockController struct {
+	kcontroller.Controller
+
+	MockWatch func(src source.Source, h handler.EventHandler, p ...predicate.Predicate) error
+}
+
+func (c *MockController) Watch(src source.Source, h handler.EventHandler, p ...predicate.Predicate) error {
+	return c.MockWatch(src, h, p...)
+}
+
+// A MockCache blocks until it is stopped, like a real cache.
+type MockCache struct {
+	cache.Cache
+}
+
+func (c *MockCache) Start(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func TestComposedWatchEngineWatch(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	xr := resource.CompositeKind(schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "XR"})
+	cool := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cool"}
+	cooler := schema.GroupVersionKind{Group: "example.org", Version: "v1", Kind: "Cooler"}
+
+	ctrl := &MockController{MockWatch: func(_ source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error { return nil }}
+
+	type call struct {
+		xr    types.UID
+		kinds []schema.GroupVersionKind
+	}
+
+	type args struct {
+		controllers map[string]kcontroller.Controller
+		o           []ComposedWatchEngineOption
+		calls       []call
+	}
+	type want struct {
+		err      error
+		watching []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoController": {
+			reason: "We should return an error if the named controller hasn't been created.",
+			args: args{
+				calls: []call{{xr: "a", kinds: []schema.GroupVersionKind{cool}}},
+			},
+			want: want{
+				err:      errors.Errorf(errFmtNoController, "cool"),
+				watching: []string{},
+			},
+		},
+		"WatchError": {
+			reason: "We should return an error if we can't add a watch to the controller.",
+			args: args{
+				controllers: map[string]kcontroller.Controller{"cool": &MockController{MockWatch: func(_ source.Source, _ handler.EventHandler, _ ...predicate.Predicate) error { return errBoom }}},
+				calls:       []call{{xr: "a", kinds: []schema.GroupVersionKind{cool}}},
+			},
+			want: want{
+				err:      errors.Wrap(errBoom, errStartWatch),
+				watching: []string{},
+			},
+		},
+		"CacheError": {
+			reason: "We should return an error if we can't create a cache for a watch.",
+			args: args{
+				controllers: map[string]kcontroller.Controller{"cool": ctrl},
+				o: []ComposedWatchEngineOption{WithComposedWatchCacheFn(func(_ *rest.Config, _ cache.Options) (cache.Cache, error) {
+					return nil, errBoom
+				})},
+				calls: []call{{xr: "a", kinds: []schema.GroupVersionKind{cool}}},
+			},
+			want: want{
+				err:      errors.Wrap(errBoom, errCreateWatchCache),
+				watching: []string{},
+			},
+		},
+		"StartWatches": {
+			reason: "We should start one watch per kind, no matter how many composite resources compose it.",
+			args: args{
+				controllers: map[string]kcontroller.Controller{"cool": ctrl},
+				calls: []call{
+					{xr: "a", kinds: []schema.GroupVersionKind{cool}},
+					{xr: "b", kinds: []schema.GroupVersionKind{cool, cooler}},
+				},
+			},
+			want: want{
+				watching: []string{cool.String(), cooler.String()},
+			},
+		},
+		"KeepUsedWatches": {
+			reason: "We should keep watching a kind while any composite resource composes it.",
+			args: args{
+				controllers: map[string]kcontroller.Controller{"cool": ctrl},
+				calls: []call{
+					{xr: "a", kinds: []schema.GroupVersionKind{cool}},
+					{xr: "b", kinds: []schema.GroupVersionKind{cool, cooler}},
+					{xr: "a"},
+				},
+			},
+			want: want{
+				watching: []string{cool.String(), cooler.String()},
+			},
+		},
+		"StopUnusedWatches": {
+			reason: "We should stop watching a kind once no composite resource composes it.",
+			args: args{
+				controllers: map[string]kcontroller.Controller{"cool": ctrl},
+				calls: []call{
+					{xr: "a", kinds: []schema.GroupVersionKind{cool}},
+					{xr: "b", kinds: []schema.GroupVersionKind{cool, cooler}},
+					{xr: "a"},
+					{xr: "b", kinds: []schema.GroupVersionKind{cooler}},
+				},
+			},
+			want: want{
+				watching: []string{cooler.String()},
+			},
+		},
+		"MaxWatches": {
+			reason: "We should return an error if starting a watch would exceed the maximum number of watches.",
+			args: args{
+				controllers: map[string]kcontroller.Controller{"cool": ctrl},
+				o:           []ComposedWatchEngineOption{WithMaxComposedWatches(1)},
+				calls: []call{
+					{xr: "a", kinds: []schema.GroupVersionKind{cool, cooler}},
+				},
+			},
+			want: want{
+				err:      errors.Errorf(errFmtMaxWatches, cooler, 1),
+				watching: []string{cool.String()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			o := append([]ComposedWatchEngineOption{
+				WithComposedWatchCacheFn(func(_ *rest.Config, _ cache.Options) (cache.Cache, error) { return &MockCache{}, nil }),
+			}, tc.args.o...)
+			e := NewComposedWatchEngine(&fake.Manager{Scheme: runtime.NewScheme()}, o...)
+			for n, c := range tc.args.controllers {
+				e.controllers[n] = c
+			}
+
+			var err error
+			for _, c := range tc.args.calls {
+				err = e.For("cool", xr).WatchComposedResources(context.Background(), c.xr, c.kinds...)
+			}
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nWatchComposedResources(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			e.mx.Lock()
+			watching := make([]string, 0, len(e.watches["cool"]))
+			for k := range e.watches["cool"] {
+				watching = append(watching, k.String())
+			}
+			running := e.running
+			e.mx.Unlock()
+			sort.Strings(watching)
+
+			if diff := cmp.Diff(tc.want.watching, watching); diff != "" {
+				t.Errorf("\n%s\nWatchComposedResources(...): -want watching, +got watching:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(len(tc.want.watching), running); diff != "" {
+				t.Errorf("\n%s\nWatchComposedResources(...): -want running, +got running:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,11 +19,15 @@ package composition
 
 import (
 	"context"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -36,6 +40,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
@@ -52,12 +57,15 @@ const (
 	errCreateRev       = "cannot create CompositionRevision"
 	errUpdateRevStatus = "cannot update CompositionRevision status"
 	errUpdateRevSpec   = "cannot update CompositionRevision spec"
+	errDeleteRev       = "cannot delete CompositionRevision"
+	errListXRs         = "cannot list composite resources"
 )
 
 // Event reasons.
 const (
 	reasonCreateRev event.Reason = "CreateRevision"
 	reasonUpdateRev event.Reason = "UpdateRevision"
+	reasonDeleteRev event.Reason = "DeleteRevision"
 )
 
 // Setup adds a controller that reconciles Compositions by creating new
@@ -67,7 +75,8 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithRevisionHistoryLimit(o.CompositionRevisionHistoryLimit))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -94,6 +103,15 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 	}
 }
 
+// WithRevisionHistoryLimit specifies how many revisions of each Composition
+// the Reconciler should keep. Older revisions are deleted unless a composite
+// resource still uses them. All revisions are kept if the limit is zero.
+func WithRevisionHistoryLimit(l int64) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.historyLimit = l
+	}
+}
+
 // NewReconciler returns a Reconciler of Compositions.
 func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 	kube := unstructured.NewClient(mgr.GetClient())
@@ -115,6 +133,8 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 type Reconciler struct {
 	client client.Client
 
+	historyLimit int64
+
 	log    logging.Logger
 	record event.Recorder
 }
@@ -191,6 +211,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// We start from revision 1, so 0 indicates we didn't find one.
 	if existingRev > 0 {
 		log.Debug("No new revision needed.", "current-revision", existingRev)
+
+		// We only prune once the current revision exists. Creating a new
+		// revision will trigger another reconcile, which will prune.
+		if err := r.pruneRevisions(ctx, comp, rl.Items); err != nil {
+			log.Debug(errDeleteRev, "error", err)
+			r.record.Event(comp, event.Warning(reasonDeleteRev, err))
+			return reconcile.Result{}, err
+		}
 		return reconcile.Result{}, nil
 	}
 
@@ -204,3 +232,69 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	r.record.Event(comp, event.Normal(reasonCreateRev, "Created new revision", "revision", strconv.FormatInt(latestRev+1, 10)))
 	return reconcile.Result{}, nil
 }
+
+// pruneRevisions deletes the oldest of the supplied Composition's revisions
+// beyond the revision history limit, except those still used by a composite
+// resource.
+func (r *Reconciler) pruneRevisions(ctx context.Context, comp *v1.Composition, revs []v1.CompositionRevision) error {
+	if r.historyLimit <= 0 {
+		return nil
+	}
+
+	owned := make([]*v1.CompositionRevision, 0, len(revs))
+	for i := range revs {
+		if metav1.IsControlledBy(&revs[i], comp) {
+			owned = append(owned, &revs[i])
+		}
+	}
+	if int64(len(owned)) <= r.historyLimit {
+		return nil
+	}
+
+	// Newest revisions first. The current revision always has the highest
+	// revision number, so it's never pruned.
+	sort.Slice(owned, func(i, j int) bool { return owned[i].Spec.Revision > owned[j].Spec.Revision })
+
+	inUse, err := r.revisionsInUse(ctx, comp)
+	if err != nil {
+		return errors.Wrap(err, errListXRs)
+	}
+
+	for _, rev := range owned[r.historyLimit:] {
+		if inUse[rev.GetName()] {
+			continue
+		}
+		if err := r.client.Delete(ctx, rev); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteRev)
+		}
+		r.log.Debug("Deleted old revision", "revision", rev.Spec.Revision)
+		r.record.Event(comp, event.Normal(reasonDeleteRev, "Deleted old revision", "revision", strconv.FormatInt(rev.Spec.Revision, 10)))
+	}
+	return nil
+}
+
+// revisionsInUse returns the names of the CompositionRevisions referenced by
+// composite resources of the supplied Composition's composite type. Composite
+// resource types are defined at runtime and aren't cached, so we can't index
+// them by revision. Instead we list them once and index them in memory, which
+// costs one List per prune rather than one per revision.
+func (r *Reconciler) revisionsInUse(ctx context.Context, comp *v1.Composition) (map[string]bool, error) {
+	gvk := schema.FromAPIVersionAndKind(comp.Spec.CompositeTypeRef.APIVersion, comp.Spec.CompositeTypeRef.Kind)
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+
+	// If the composite resource type isn't defined there can't be any
+	// composite resources using our revisions.
+	if err := r.client.List(ctx, l); err != nil && !kmeta.IsNoMatchError(err) {
+		return nil, err
+	}
+
+	inUse := make(map[string]bool, len(l.Items))
+	for i := range l.Items {
+		xr := &composite.Unstructured{Unstructured: l.Items[i]}
+		if ref := xr.GetCompositionRevisionReference(); ref != nil {
+			inUse[ref.Name] = true
+		}
+	}
+	return inUse, nil
+}
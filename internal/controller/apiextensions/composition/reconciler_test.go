@@ -22,8 +22,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -34,6 +36,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composite"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
@@ -121,6 +124,40 @@ func TestReconcile(t *testing.T) {
 		Spec: v1.CompositionRevisionSpec{Revision: 3},
 	}
 
+	// Owned by the above composition with an 'older' hash, but still used by
+	// a composite resource.
+	rev0 := &v1.CompositionRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: compDev.GetName() + "-0",
+			OwnerReferences: []metav1.OwnerReference{{
+				UID:                compDev.GetUID(),
+				Controller:         &ctrl,
+				BlockOwnerDeletion: &ctrl,
+			}},
+			Labels: map[string]string{
+				v1.LabelCompositionHash: "some-even-older-hash",
+				v1.LabelCompositionName: compDev.Name,
+			},
+		},
+		Spec: v1.CompositionRevisionSpec{Revision: 0},
+	}
+
+	withRevisions := func(revs ...*v1.CompositionRevision) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			switch l := obj.(type) {
+			case *v1.CompositionRevisionList:
+				for _, rev := range revs {
+					l.Items = append(l.Items, *rev)
+				}
+			case *kunstructured.UnstructuredList:
+				xr := composite.New()
+				xr.SetCompositionRevisionReference(&corev1.ObjectReference{Name: rev0.GetName()})
+				l.Items = []kunstructured.Unstructured{xr.Unstructured}
+			}
+			return nil
+		})
+	}
+
 	type args struct {
 		mgr  manager.Manager
 		opts []ReconcilerOption
@@ -353,6 +390,91 @@ func TestReconcile(t *testing.T) {
 				err: nil,
 			},
 		},
+		"SuccessfulPrune": {
+			reason: "We should delete the oldest revisions beyond the history limit that aren't used by a composite resource.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							*obj.(*v1.Composition) = *compDev
+							return nil
+						}),
+						MockList: withRevisions(rev0, rev1, rev2, rev3),
+						MockDelete: test.NewMockDeleteFn(nil, func(obj client.Object) error {
+							if diff := cmp.Diff(rev2.GetName(), obj.GetName()); diff != "" {
+								t.Errorf("Delete(): -want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{WithRevisionHistoryLimit(1)},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"NoPruneWithinLimit": {
+			reason: "We should not delete any revisions if there are no more than the history limit.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							*obj.(*v1.Composition) = *compDev
+							return nil
+						}),
+						MockList:   withRevisions(rev0, rev1, rev2, rev3),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					},
+				},
+				opts: []ReconcilerOption{WithRevisionHistoryLimit(3)},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ListCompositeResourcesError": {
+			reason: "We should return any error encountered while listing the composite resources that may use a revision.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							*obj.(*v1.Composition) = *compDev
+							return nil
+						}),
+						MockList: func(_ context.Context, obj client.ObjectList, _ ...client.ListOption) error {
+							if _, ok := obj.(*kunstructured.UnstructuredList); ok {
+								return errBoom
+							}
+							return withRevisions(rev2, rev3)(context.Background(), obj)
+						},
+					},
+				},
+				opts: []ReconcilerOption{WithRevisionHistoryLimit(1)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListXRs),
+			},
+		},
+		"DeleteCompositionRevisionError": {
+			reason: "We should return any error encountered while deleting a CompositionRevision.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							*obj.(*v1.Composition) = *compDev
+							return nil
+						}),
+						MockList:   withRevisions(rev2, rev3),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					},
+				},
+				opts: []ReconcilerOption{WithRevisionHistoryLimit(1)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteRev),
+			},
+		},
 	}
 
 	for name, tc := range cases {
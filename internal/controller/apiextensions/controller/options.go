@@ -18,9 +18,21 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
+const errNoConversionWebhook = "cannot convert between versions using field conversions: Crossplane's webhook server is not enabled"
+
 // Options specific to pkg controllers.
 type Options struct {
 	controller.Options
@@ -36,4 +48,83 @@ type Options struct {
 	// Registry is the default registry to use when pulling containers for
 	// Composition Functions
 	Registry string
+
+	// MaxExtraResources is the maximum number of extra resources that will be
+	// fetched to satisfy the requirements of a single Composition Function.
+	MaxExtraResources int
+
+	// ExtraResourcesAllowedKinds are the kinds of extra resources Composition
+	// Functions may require. Functions may require any kind if none are
+	// specified.
+	ExtraResourcesAllowedKinds []schema.GroupVersionKind
+
+	// ComposedDeletionTimeout is how long to wait for a composed resource to
+	// be deleted before deleting the composed resources it depends on anyway.
+	ComposedDeletionTimeout time.Duration
+
+	// CompositionRevisionHistoryLimit is the maximum number of revisions of
+	// each Composition to keep. Older revisions are deleted unless a composite
+	// resource still uses them. All revisions are kept if it is zero.
+	CompositionRevisionHistoryLimit int64
+
+	// MaxComposedResourceWatches is the maximum number of kinds of composed
+	// resource that may be watched at once when realtime compositions are
+	// enabled. The number of watches is not limited if it is zero.
+	MaxComposedResourceWatches int
+
+	// PropagateMetadataPrefixes are the key prefixes of the claim labels and
+	// annotations that are propagated to composite resources, and from there
+	// to composed resources, in addition to any specified by an XRD.
+	PropagateMetadataPrefixes []string
+
+	// DisableCompositionMetricsLabel stops composite resource and claim
+	// controllers labelling their metrics with the name of the Composition,
+	// bounding the cardinality of those metrics.
+	DisableCompositionMetricsLabel bool
+
+	// ConversionWebhook configures how the API server calls Crossplane's
+	// conversion webhook to apply the field conversions of an XRD. XRDs with
+	// field conversions aren't supported if it is nil.
+	ConversionWebhook *xcrd.ConversionWebhook
+
+	// ClaimValidationWebhook configures how the API server calls Crossplane's
+	// claim validation webhook. Claims are validated only by the API server
+	// if it is nil.
+	ClaimValidationWebhook *xcrd.ClaimValidationWebhook
+}
+
+// PropagatedMetadataPrefixes returns the key prefixes of the claim labels and
+// annotations that should be propagated for the supplied XRD.
+func (o Options) PropagatedMetadataPrefixes(d *v1.CompositeResourceDefinition) []string {
+	p := make([]string, 0, len(o.PropagateMetadataPrefixes))
+	p = append(p, o.PropagateMetadataPrefixes...)
+	return append(p, d.GetPropagatedMetadataPrefixes()...)
+}
+
+// CRDOptions returns how the composite resource and claim CRDs of XRDs should
+// be derived. Status fields that Crossplane only populates when an alpha
+// feature is enabled are only added to CRDs when it is, so as not to override
+// fields of the same name that an XRD defines.
+func (o Options) CRDOptions() []xcrd.CRDOption {
+	opts := make([]xcrd.CRDOption, 0)
+	if o.Features.Enabled(features.EnableAlphaComposedResourceStatus) {
+		opts = append(opts, xcrd.WithComposedResourceStatus())
+	}
+	if o.Features.Enabled(features.EnableAlphaEnvironmentConfigs) {
+		opts = append(opts, xcrd.WithResolvedEnvironment())
+	}
+	return opts
+}
+
+// CustomResourceConversion returns how the composite resource and claim CRDs
+// of the supplied XRD should convert between versions. CRDs of XRDs with field
+// conversions call Crossplane's conversion webhook.
+func (o Options) CustomResourceConversion(d *v1.CompositeResourceDefinition) (*extv1.CustomResourceConversion, error) {
+	if len(d.Spec.FieldConversions) == 0 {
+		return d.Spec.Conversion, nil
+	}
+	if o.ConversionWebhook == nil {
+		return nil, errors.New(errNoConversionWebhook)
+	}
+	return o.ConversionWebhook.For(d), nil
 }
@@ -19,6 +19,7 @@ package definition
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -61,6 +62,7 @@ const (
 
 	errGetXRD          = "cannot get CompositeResourceDefinition"
 	errRenderCRD       = "cannot render composite resource CustomResourceDefinition"
+	errConvertCRD      = "cannot configure conversion of composite resource CustomResourceDefinition"
 	errGetCRD          = "cannot get composite resource CustomResourceDefinition"
 	errApplyCRD        = "cannot apply rendered composite resource CustomResourceDefinition"
 	errUpdateStatus    = "cannot update status of CompositeResourceDefinition"
@@ -70,12 +72,17 @@ const (
 	errDeleteCRD       = "cannot delete composite resource CustomResourceDefinition"
 	errListCRs         = "cannot list defined composite resources"
 	errDeleteCRs       = "cannot delete defined composite resources"
+
+	errFmtDeletionBlocked = "cannot delete CompositeResourceDefinition with deletion policy Block: %d composite resources still exist"
 )
 
 // Wait strings.
 const (
 	waitCRDelete     = "waiting for defined composite resources to be deleted"
 	waitCRDEstablish = "waiting for composite resource CustomResourceDefinition to be established"
+	waitCRBlocked    = "waiting for defined composite resources to be deleted before deleting CompositeResourceDefinition"
+
+	fmtRemainingCRs = "Waiting for %d composite resources to be deleted"
 )
 
 // Event reasons.
@@ -93,6 +100,12 @@ type ControllerEngine interface {
 	Err(name string) error
 }
 
+// A ComposedWatchEngine watches composed resources on behalf of composite
+// resource controllers.
+type ComposedWatchEngine interface {
+	For(name string, of resource.CompositeKind) composite.ComposedResourceWatcher
+}
+
 // A CRDRenderer renders a CompositeResourceDefinition's corresponding
 // CustomResourceDefinition.
 type CRDRenderer interface {
@@ -114,10 +127,28 @@ func (fn CRDRenderFn) Render(d *v1.CompositeResourceDefinition) (*extv1.CustomRe
 func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	name := "defined/" + strings.ToLower(v1.CompositeResourceDefinitionGroupKind)
 
-	r := NewReconciler(mgr,
+	ro := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		WithOptions(o))
+		WithCRDRenderer(CRDRenderFn(func(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+			return xcrd.ForCompositeResource(xrd, o.CRDOptions()...)
+		})),
+		WithOptions(o),
+	}
+
+	// Composite resource controllers are started with a watch on their
+	// composed resources only if realtime compositions are enabled. Otherwise
+	// they poll their composite resources to notice composed resource changes.
+	if o.Features.Enabled(features.EnableAlphaRealtimeCompositions) {
+		w := composite.NewComposedWatchEngine(mgr,
+			composite.WithMaxComposedWatches(o.MaxComposedResourceWatches),
+			composite.WithComposedWatchLogger(o.Logger.WithValues("controller", name)))
+		ro = append(ro,
+			WithControllerEngine(controller.NewEngine(mgr, controller.WithNewControllerFn(w.NewController))),
+			WithComposedWatchEngine(w))
+	}
+
+	r := NewReconciler(mgr, ro...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -168,6 +199,15 @@ func WithControllerEngine(c ControllerEngine) ReconcilerOption {
 	}
 }
 
+// WithComposedWatchEngine specifies how composite resource controllers should
+// watch their composed resources. The ComposedWatchEngine must be used to
+// create the controllers started by the ControllerEngine.
+func WithComposedWatchEngine(w ComposedWatchEngine) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.watches = w
+	}
+}
+
 // WithCRDRenderer specifies how the Reconciler should render an
 // CompositeResourceDefinition's corresponding CustomResourceDefinition.
 func WithCRDRenderer(c CRDRenderer) ReconcilerOption {
@@ -203,7 +243,9 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		},
 
 		composite: definition{
-			CRDRenderer:      CRDRenderFn(xcrd.ForCompositeResource),
+			CRDRenderer: CRDRenderFn(func(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+				return xcrd.ForCompositeResource(xrd)
+			}),
 			ControllerEngine: controller.NewEngine(mgr),
 			Finalizer:        resource.NewAPIFinalizer(kube, finalizer),
 		},
@@ -228,6 +270,7 @@ type Reconciler struct {
 	mgr    manager.Manager
 
 	composite definition
+	watches   ComposedWatchEngine
 
 	log    logging.Logger
 	record event.Recorder
@@ -270,6 +313,33 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	r.record.Event(d, event.Normal(reasonRenderCRD, "Rendered composite resource CustomResourceDefinition"))
 
 	if meta.WasDeleted(d) {
+		// An XRD with the Block deletion policy isn't deleted until its
+		// composite resources have been deleted by someone else. We keep
+		// the composite resource controller running in the meantime so
+		// that those deletions are processed.
+		if d.GetDeletionPolicy() == v1.XRDDeletionBlock {
+			l := &kunstructured.UnstructuredList{}
+			l.SetGroupVersionKind(d.GetCompositeGroupVersionKind())
+			if err := r.client.List(ctx, l); resource.Ignore(kmeta.IsNoMatchError, err) != nil {
+				log.Debug(errListCRs, "error", err)
+				err = errors.Wrap(err, errListCRs)
+				r.record.Event(d, event.Warning(reasonTerminateXR, err))
+				return reconcile.Result{}, err
+			}
+			if n := len(l.Items); n > 0 {
+				if err := r.startController(d); err != nil {
+					log.Debug(errStartController, "error", err)
+					err = errors.Wrap(err, errStartController)
+					r.record.Event(d, event.Warning(reasonTerminateXR, err))
+					return reconcile.Result{}, err
+				}
+				log.Debug(waitCRBlocked, "remaining", n)
+				r.record.Event(d, event.Warning(reasonTerminateXR, errors.Errorf(errFmtDeletionBlocked, n)))
+				d.Status.SetConditions(v1.DeletionBlockedComposite().WithMessage(fmt.Sprintf(errFmtDeletionBlocked, n)))
+				return reconcile.Result{RequeueAfter: r.options.PollInterval}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
+			}
+		}
+
 		d.Status.SetConditions(v1.TerminatingComposite())
 		if err := r.client.Status().Update(ctx, d); err != nil {
 			log.Debug(errUpdateStatus, "error", err)
@@ -341,10 +411,22 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		// Controller should be stopped only after all instances are
 		// gone so that deletion logic of the instances are processed by
 		// the controller.
-		if len(l.Items) > 0 {
-			log.Debug(waitCRDelete)
+		if n := len(l.Items); n > 0 {
+			// The composite resource controller may not be running, for
+			// example if Crossplane restarted while we were waiting.
+			// Without it the composite resources' finalizers would
+			// never be removed.
+			if err := r.startController(d); err != nil {
+				log.Debug(errStartController, "error", err)
+				err = errors.Wrap(err, errStartController)
+				r.record.Event(d, event.Warning(reasonTerminateXR, err))
+				return reconcile.Result{}, err
+			}
+
+			log.Debug(waitCRDelete, "remaining", n)
 			r.record.Event(d, event.Normal(reasonTerminateXR, waitCRDelete))
-			return reconcile.Result{Requeue: true}, nil
+			d.Status.SetConditions(v1.TerminatingComposite().WithMessage(fmt.Sprintf(fmtRemainingCRs, n)))
+			return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 		}
 
 		// The controller should be stopped before the deletion of CRD
@@ -368,6 +450,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if crd.Spec.Conversion, err = r.options.CustomResourceConversion(d); err != nil {
+		log.Debug(errConvertCRD, "error", err)
+		err = errors.Wrap(err, errConvertCRD)
+		r.record.Event(d, event.Warning(reasonEstablishXR, err))
+		return reconcile.Result{}, err
+	}
+
 	if err := r.composite.AddFinalizer(ctx, d); err != nil {
 		log.Debug(errAddFinalizer, "error", err)
 		err = errors.Wrap(err, errAddFinalizer)
@@ -405,15 +494,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			"desired-version", desired.APIVersion))
 	}
 
-	ro := CompositeReconcilerOptions(r.options, d, r.client, r.log, r.record)
-	cr := composite.NewReconciler(r.mgr, resource.CompositeKind(d.GetCompositeGroupVersionKind()), ro...)
-	ko := r.options.ForControllerRuntime()
-	ko.Reconciler = ratelimiter.NewReconciler(composite.ControllerName(d.GetName()), cr, r.options.GlobalRateLimiter)
-
-	u := &kunstructured.Unstructured{}
-	u.SetGroupVersionKind(d.GetCompositeGroupVersionKind())
-
-	if err := r.composite.Start(composite.ControllerName(d.GetName()), ko, controller.For(u, &handler.EnqueueRequestForObject{})); err != nil {
+	if err := r.startController(d); err != nil {
 		log.Debug(errStartController, "error", err)
 		err = errors.Wrap(err, errStartController)
 		r.record.Event(d, event.Warning(reasonEstablishXR, err))
@@ -426,9 +507,34 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, d), errUpdateStatus)
 }
 
+// startController starts the controller that reconciles the composite
+// resources defined by the supplied XRD. Starting a controller that is already
+// running is a no-op.
+func (r *Reconciler) startController(d *v1.CompositeResourceDefinition) error {
+	ro := CompositeReconcilerOptions(r.options, d, r.client, r.log, r.record)
+	if r.watches != nil {
+		ro = append(ro, composite.WithComposedResourceWatcher(r.watches.For(composite.ControllerName(d.GetName()), resource.CompositeKind(d.GetCompositeGroupVersionKind()))))
+	}
+	cr := composite.NewReconciler(r.mgr, resource.CompositeKind(d.GetCompositeGroupVersionKind()), ro...)
+	ko := r.options.ForControllerRuntime()
+	ko.Reconciler = ratelimiter.NewReconciler(composite.ControllerName(d.GetName()), cr, r.options.GlobalRateLimiter)
+
+	u := &kunstructured.Unstructured{}
+	u.SetGroupVersionKind(d.GetCompositeGroupVersionKind())
+
+	return r.composite.Start(composite.ControllerName(d.GetName()), ko, controller.For(u, &handler.EnqueueRequestForObject{}))
+}
+
 // CompositeReconcilerOptions builds the options for a composite resource
 // reconciler. The options vary based on the supplied feature flags.
 func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.CompositeResourceDefinition, c client.Client, l logging.Logger, e event.Recorder) []composite.ReconcilerOption {
+	// Labelling metrics with the name of the Composition may produce too
+	// many series in control planes with many Compositions.
+	m := composite.NewCompositionMetrics()
+	if co.DisableCompositionMetricsLabel {
+		m = composite.NewCompositionMetrics(composite.WithoutCompositionLabel())
+	}
+
 	// The default set of reconciler options when no feature flags are enabled.
 	o := []composite.ReconcilerOption{
 		composite.WithConnectionPublishers(composite.NewAPIFilteredSecretPublisher(c, d.GetConnectionSecretKeys())),
@@ -437,10 +543,15 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 			composite.NewAPIDefaultCompositionSelector(c, *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), e),
 			composite.NewAPILabelSelectorResolver(c),
 		)),
-		composite.WithCompositionUpdatePolicySelector(composite.NewAPIDefaultCompositionUpdatePolicySelector(c, *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), e)),
+		composite.WithCompositionUpdatePolicySelector(composite.NewCompositionUpdatePolicySelectorChain(
+			composite.NewEnforcedCompositionUpdatePolicySelector(*d, e),
+			composite.NewAPIDefaultCompositionUpdatePolicySelector(c, *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), e),
+		)),
 		composite.WithLogger(l.WithValues("controller", composite.ControllerName(d.GetName()))),
 		composite.WithRecorder(e.WithAnnotations("controller", composite.ControllerName(d.GetName()))),
 		composite.WithPollInterval(co.PollInterval),
+		composite.WithDeletionOrderer(composite.NewAPIDeletionOrderer(c, composite.WithDeletionTierTimeout(co.ComposedDeletionTimeout))),
+		composite.WithCompositionMetrics(m),
 	}
 
 	// We only want to enable Composition environment support if the relevant
@@ -453,6 +564,17 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 			composite.WithEnvironmentFetcher(environment.NewAPIEnvironmentFetcher(c)))
 	}
 
+	// Summarizing each composed resource in the XR's status increases the size
+	// of the XR, so we only do it if the relevant feature flag is enabled.
+	if co.Features.Enabled(features.EnableAlphaComposedResourceStatus) {
+		o = append(o, composite.WithComposedResourceStatus(true))
+	}
+
+	// Claim labels and annotations with these key prefixes are propagated to
+	// composite resources, and from there to composed resources.
+	propagate := co.PropagatedMetadataPrefixes(d)
+	o = append(o, composite.WithComposer(composite.NewPTComposer(c, composite.WithPropagatedMetadataPrefixes(propagate...), composite.WithPTComposerMetrics(m))))
+
 	// If external secret stores aren't enabled we just fetch connection details
 	// from Kubernetes secrets.
 	var fetcher managed.ConnectionDetailsFetcher = composite.NewSecretConnectionDetailsFetcher(c)
@@ -486,7 +608,7 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 		o = append(o,
 			composite.WithConnectionPublishers(pc...),
 			composite.WithConfigurator(cc),
-			composite.WithComposer(composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher))))
+			composite.WithComposer(composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher), composite.WithPropagatedMetadataPrefixes(propagate...), composite.WithPTComposerMetrics(m))))
 	}
 
 	// If Composition Functions are enabled we want to try to use the
@@ -503,12 +625,18 @@ func CompositeReconcilerOptions(co apiextensionscontroller.Options, d *v1.Compos
 			composite.NewPTFComposer(c,
 				composite.WithComposedResourceGetter(composite.NewExistingComposedResourceGetter(c, fetcher)),
 				composite.WithCompositeConnectionDetailsFetcher(fetcher),
+				composite.WithPTFComposerMetrics(m),
 				composite.WithFunctionPipelineRunner(composite.NewFunctionPipeline(
 					composite.ContainerFunctionRunnerFn(composite.RunFunction),
-					composite.WithKubernetesAuthentication(c, co.Namespace, co.ServiceAccount, co.Registry),
+					composite.WithContainerFunctionRunnerOptions(composite.WithKubernetesAuthentication(c, co.Namespace, co.ServiceAccount, co.Registry)),
+					composite.WithExtraResourcesFetcher(composite.NewAPIExtraResourcesFetcher(c,
+						composite.WithMaxExtraResources(co.MaxExtraResources),
+						composite.WithAllowedExtraResourceKinds(co.ExtraResourcesAllowedKinds...),
+					)),
+					composite.WithFunctionPipelineMetrics(m),
 				)),
 			),
-			composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher)),
+			composite.NewPTComposer(c, composite.WithComposedConnectionDetailsFetcher(fetcher), composite.WithPropagatedMetadataPrefixes(propagate...), composite.WithPTComposerMetrics(m)),
 			composite.FallBackForAnonymousTemplates(c),
 		)
 
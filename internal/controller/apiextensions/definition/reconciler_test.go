@@ -18,6 +18,7 @@ package definition
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"testing"
 
@@ -28,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	kcontroller "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
@@ -42,6 +44,8 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	apiextensionscontroller "github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/xcrd"
 )
 
 type MockEngine struct {
@@ -69,6 +73,7 @@ func TestReconcile(t *testing.T) {
 	now := metav1.Now()
 	owner := types.UID("definitely-a-uuid")
 	ctrlr := true
+	block := v1.XRDDeletionBlock
 
 	type args struct {
 		mgr  manager.Manager
@@ -356,12 +361,107 @@ func TestReconcile(t *testing.T) {
 					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
 						return &extv1.CustomResourceDefinition{}, nil
 					})),
+					WithControllerEngine(&MockEngine{
+						MockStart: func(_ string, _ kcontroller.Options, _ ...controller.Watch) error { return nil },
+					}),
 				},
 			},
 			want: want{
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"WaitForDeleteAllOfStartControllerError": {
+			reason: "We should return any error we encounter while making sure the controller is running to process the deletion of defined resources.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch v := o.(type) {
+								case *v1.CompositeResourceDefinition:
+									d := v1.CompositeResourceDefinition{}
+									d.SetUID(owner)
+									d.SetDeletionTimestamp(&now)
+									*v = d
+								case *extv1.CustomResourceDefinition:
+									crd := extv1.CustomResourceDefinition{}
+									crd.SetCreationTimestamp(now)
+									crd.SetOwnerReferences([]metav1.OwnerReference{{UID: owner, Controller: &ctrlr}})
+									*v = crd
+								}
+								return nil
+							}),
+							MockDeleteAllOf: test.NewMockDeleteAllOfFn(nil),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								v := o.(*unstructured.UnstructuredList)
+								*v = unstructured.UnstructuredList{
+									Items: []unstructured.Unstructured{{}, {}},
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithControllerEngine(&MockEngine{
+						MockStart: func(_ string, _ kcontroller.Options, _ ...controller.Watch) error { return errBoom },
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errStartController),
+			},
+		},
+		"DeletionBlocked": {
+			reason: "We should not delete defined resources or the CRD while defined resources exist if the deletion policy is Block.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								if v, ok := o.(*v1.CompositeResourceDefinition); ok {
+									d := v1.CompositeResourceDefinition{Spec: v1.CompositeResourceDefinitionSpec{DeletionPolicy: &block}}
+									d.SetUID(owner)
+									d.SetDeletionTimestamp(&now)
+									*v = d
+								}
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								v := o.(*unstructured.UnstructuredList)
+								*v = unstructured.UnstructuredList{
+									Items: []unstructured.Unstructured{{}, {}},
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.CompositeResourceDefinition{Spec: v1.CompositeResourceDefinitionSpec{DeletionPolicy: &block}}
+								want.SetUID(owner)
+								want.SetDeletionTimestamp(&now)
+								want.Status.SetConditions(v1.DeletionBlockedComposite().WithMessage(fmt.Sprintf(errFmtDeletionBlocked, 2)))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("MockStatusUpdate: -want, +got:\n%s\n", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithControllerEngine(&MockEngine{
+						MockStart: func(_ string, _ kcontroller.Options, _ ...controller.Watch) error { return nil },
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: controller.DefaultOptions().PollInterval},
+			},
+		},
 		"DeleteCustomResourceDefinitionError": {
 			reason: "We should return any error we encounter while deleting the CRD we created.",
 			args: args{
@@ -447,6 +547,55 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: true},
 			},
 		},
+		"ConfigureConversionWebhook": {
+			reason: "We should configure our CRD to call Crossplane's conversion webhook if the XRD has field conversions.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+								d := obj.(*v1.CompositeResourceDefinition)
+								d.SetName("cool")
+								d.Spec.FieldConversions = []v1.FieldConversion{{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.a", ToFieldPath: "spec.b"}}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, obj client.Object, _ ...resource.ApplyOption) error {
+							want := &extv1.CustomResourceConversion{
+								Strategy: extv1.WebhookConverter,
+								Webhook: &extv1.WebhookConversion{
+									ClientConfig: &extv1.WebhookClientConfig{
+										Service:  &extv1.ServiceReference{Name: "crossplane-webhooks", Path: pointer.String("/convert/cool")},
+										CABundle: []byte("cool-ca"),
+									},
+									ConversionReviewVersions: []string{"v1"},
+								},
+							}
+							if diff := cmp.Diff(want, obj.(*extv1.CustomResourceDefinition).Spec.Conversion); diff != "" {
+								t.Errorf("Apply(...): -want conversion, +got conversion:\n%s", diff)
+							}
+							return errBoom
+						}),
+					}),
+					WithCRDRenderer(CRDRenderFn(func(_ *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+						return &extv1.CustomResourceDefinition{}, nil
+					})),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithOptions(apiextensionscontroller.Options{
+						ConversionWebhook: &xcrd.ConversionWebhook{
+							Service:  extv1.ServiceReference{Name: "crossplane-webhooks"},
+							CABundle: []byte("cool-ca"),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyCRD),
+			},
+		},
 		"AddFinalizerError": {
 			reason: "We should return any error we encounter while adding a finalizer.",
 			args: args{
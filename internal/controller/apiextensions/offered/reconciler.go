@@ -22,6 +22,8 @@ import (
 	"strings"
 	"time"
 
+	admv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kmeta "k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -60,6 +62,7 @@ const (
 const (
 	errGetXRD          = "cannot get CompositeResourceDefinition"
 	errRenderCRD       = "cannot render composite resource claim CustomResourceDefinition"
+	errConvertCRD      = "cannot configure conversion of composite resource claim CustomResourceDefinition"
 	errGetCRD          = "cannot get composite resource claim CustomResourceDefinition"
 	errApplyCRD        = "cannot apply rendered composite resource claim CustomResourceDefinition"
 	errUpdateStatus    = "cannot update status of CompositeResourceDefinition"
@@ -69,6 +72,9 @@ const (
 	errDeleteCRD       = "cannot delete composite resource claim CustomResourceDefinition"
 	errListCRs         = "cannot list defined composite resource claims"
 	errDeleteCR        = "cannot delete defined composite resource claim"
+	errApplyWebhook    = "cannot apply composite resource claim ValidatingWebhookConfiguration"
+	errGetWebhook      = "cannot get composite resource claim ValidatingWebhookConfiguration"
+	errDeleteWebhook   = "cannot delete composite resource claim ValidatingWebhookConfiguration"
 )
 
 // Wait strings.
@@ -117,6 +123,9 @@ func Setup(mgr ctrl.Manager, o apiextensionscontroller.Options) error {
 	r := NewReconciler(mgr,
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithCRDRenderer(CRDRenderFn(func(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+			return xcrd.ForCompositeResourceClaim(xrd, o.CRDOptions()...)
+		})),
 		WithOptions(o))
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -198,7 +207,9 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		},
 
 		claim: definition{
-			CRDRenderer:      CRDRenderFn(xcrd.ForCompositeResourceClaim),
+			CRDRenderer: CRDRenderFn(func(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+				return xcrd.ForCompositeResourceClaim(xrd)
+			}),
 			ControllerEngine: controller.NewEngine(mgr),
 			Finalizer:        resource.NewAPIFinalizer(kube, finalizer),
 		},
@@ -371,6 +382,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	if crd.Spec.Conversion, err = r.options.CustomResourceConversion(d); err != nil {
+		log.Debug(errConvertCRD, "error", err)
+		err = errors.Wrap(err, errConvertCRD)
+		r.record.Event(d, event.Warning(reasonOfferXRC, err))
+		return reconcile.Result{}, err
+	}
+
 	if err := r.claim.AddFinalizer(ctx, d); err != nil {
 		log.Debug(errAddFinalizer, "error", err)
 		err = errors.Wrap(err, errAddFinalizer)
@@ -392,27 +410,92 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// Claims are validated by Crossplane's claim validation webhook only if
+	// it's enabled. We delete the webhook's configuration otherwise, so that
+	// the API server doesn't call a webhook that isn't served. We only delete
+	// a configuration that this XRD controls.
+	if w := r.options.ClaimValidationWebhook; w != nil {
+		if err := r.client.Apply(ctx, w.For(d), resource.MustBeControllableBy(d.GetUID())); err != nil {
+			log.Debug(errApplyWebhook, "error", err)
+			err = errors.Wrap(err, errApplyWebhook)
+			r.record.Event(d, event.Warning(reasonOfferXRC, err))
+			return reconcile.Result{}, err
+		}
+	} else {
+		wc := &admv1.ValidatingWebhookConfiguration{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: xcrd.ClaimValidationWebhookConfigurationName(d.GetName())}, wc)
+		if resource.IgnoreNotFound(err) != nil {
+			log.Debug(errGetWebhook, "error", err)
+			err = errors.Wrap(err, errGetWebhook)
+			r.record.Event(d, event.Warning(reasonOfferXRC, err))
+			return reconcile.Result{}, err
+		}
+		if err == nil && metav1.IsControlledBy(wc, d) {
+			if err := r.client.Delete(ctx, wc); resource.IgnoreNotFound(err) != nil {
+				log.Debug(errDeleteWebhook, "error", err)
+				err = errors.Wrap(err, errDeleteWebhook)
+				r.record.Event(d, event.Warning(reasonOfferXRC, err))
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
 	o := []claim.ReconcilerOption{
 		claim.WithLogger(log.WithValues("controller", claim.ControllerName(d.GetName()))),
 		claim.WithRecorder(r.record.WithAnnotations("controller", claim.ControllerName(d.GetName()))),
 		claim.WithPollInterval(r.options.PollInterval),
 		claim.WithDefaultsSelector(claim.NewAPIDefaultSelector(r.client, *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind), r.record.WithAnnotations("controller", claim.ControllerName(d.GetName())))),
+		claim.WithNamespaceChecker(claim.NewAPINamespaceChecker(r.client, *meta.ReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind))),
+	}
+
+	// Claims may only write their connection secret to another namespace if
+	// the relevant feature flag is enabled. Such secrets can't be owned by
+	// the claim, so they must be explicitly cleaned up.
+	var po []claim.APIConnectionPropagatorOption
+	uc := claim.ConnectionUnpublisherChain{}
+	if r.options.Features.Enabled(features.EnableAlphaClaimCrossNamespaceConnectionSecrets) {
+		po = append(po, claim.WithCrossNamespaceConnectionSecrets())
+		uc = append(uc, claim.NewAPICrossNamespaceConnectionUnpublisher(r.client))
 	}
+	pc := claim.ConnectionPropagatorChain{claim.NewAPIConnectionPropagator(r.client, po...)}
 
 	// We only want to enable ExternalSecretStore support if the relevant
 	// feature flag is enabled. Otherwise, we start the Claim reconcilers with
 	// their default Connection Propagator.
 	if r.options.Features.Enabled(features.EnableAlphaExternalSecretStores) {
-		pc := claim.ConnectionPropagatorChain{
-			claim.NewAPIConnectionPropagator(r.client),
-			connection.NewDetailsManager(r.client, secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)),
-		}
+		pc = append(pc, connection.NewDetailsManager(r.client, secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))
+		uc = append(uc, claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.client,
+			secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig))))
+	}
+
+	o = append(o, claim.WithConnectionPropagator(pc), claim.WithConnectionUnpublisher(uc))
 
-		o = append(o, claim.WithConnectionPropagator(pc), claim.WithConnectionUnpublisher(
-			claim.NewSecretStoreConnectionUnpublisher(connection.NewDetailsManager(r.client,
-				secretsv1alpha1.StoreConfigGroupVersionKind, connection.WithTLSConfig(r.options.ESSOptions.TLSConfig)))))
+	if r.options.DisableCompositionMetricsLabel {
+		o = append(o, claim.WithoutCompositionMetricsLabel())
 	}
 
+	// Claim labels and annotations with these key prefixes are removed from
+	// the composite resource when they're removed from the claim.
+	var co []claim.APIDryRunCompositeConfiguratorOption
+	if p := r.options.PropagatedMetadataPrefixes(d); len(p) > 0 {
+		co = append(co, claim.WithPropagatedMetadata(p...))
+		o = append(o, claim.WithPropagatedMetadataPrefixes(p...))
+	}
+
+	// The XRD may enforce the Composition used by the claim's composite
+	// resource, regardless of what the claim asks for.
+	var enforced *corev1.ObjectReference
+	if ref := d.Spec.EnforcedCompositionRef; ref != nil {
+		enforced = &corev1.ObjectReference{Name: ref.Name}
+	}
+	co = append(co, claim.WithEnforcedComposition(enforced, d.Spec.EnforcedCompositionUpdatePolicy))
+	o = append(o, claim.WithCompositeConfigurator(claim.NewAPIDryRunCompositeConfigurator(r.client, co...)))
+
+	// Status fields Crossplane only adds to the composite resource aren't
+	// propagated to the claim.
+	o = append(o, claim.WithClaimConfigurator(claim.NewAPIClaimConfigurator(r.client,
+		claim.WithCompositeOnlyStatusFields(xcrd.CompositeResourceOnlyStatusFields(r.options.CRDOptions()...)...))))
+
 	cr := claim.NewReconciler(r.mgr,
 		resource.CompositeClaimKind(d.GetClaimGroupVersionKind()),
 		resource.CompositeKind(d.GetCompositeGroupVersionKind()), o...)
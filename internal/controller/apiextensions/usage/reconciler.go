@@ -0,0 +1,361 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage manages the lifecycle of Usage objects.
+package usage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/controller/apiextensions/controller"
+	"github.com/crossplane/crossplane/internal/usage"
+)
+
+const (
+	timeout   = 2 * time.Minute
+	finalizer = "usage.apiextensions.crossplane.io"
+)
+
+// Error strings.
+const (
+	errAddIndex           = "cannot add Usage index"
+	errGetUsage           = "cannot get Usage"
+	errResolveSelectors   = "cannot resolve selectors"
+	errAddFinalizer       = "cannot add finalizer"
+	errRemoveFinalizer    = "cannot remove finalizer"
+	errGetUsed            = "cannot get used resource"
+	errGetUsing           = "cannot get using resource"
+	errAddInUseLabel      = "cannot add in-use label to used resource"
+	errRemoveInUseLabel   = "cannot remove in-use label from used resource"
+	errAddOwnerToUsage    = "cannot add owner reference to Usage"
+	errListUsages         = "cannot list Usages"
+	errDeleteUsed         = "cannot replay deletion of used resource"
+	errUpdateStatus       = "cannot update Usage status"
+	errWaitForUsingDelete = "waiting for the using resource to be deleted"
+)
+
+// Event reasons.
+const (
+	reasonResolveSelectors event.Reason = "ResolveSelectors"
+	reasonListUsages       event.Reason = "ListUsages"
+	reasonGetUsed          event.Reason = "GetUsedResource"
+	reasonGetUsing         event.Reason = "GetUsingResource"
+	reasonAddInUseLabel    event.Reason = "AddInUseLabel"
+	reasonRemoveInUseLabel event.Reason = "RemoveInUseLabel"
+	reasonAddOwnerRef      event.Reason = "AddOwnerReference"
+	reasonAddFinalizer     event.Reason = "AddFinalizer"
+	reasonRemoveFinalizer  event.Reason = "RemoveFinalizer"
+	reasonReplayDeletion   event.Reason = "ReplayDeletion"
+	reasonUsageConfigured  event.Reason = "UsageConfigured"
+	reasonWaitUsing        event.Reason = "WaitingUsingDeleted"
+)
+
+// Setup adds a controller that reconciles Usages by labelling the resources
+// they are of as in use, and cleaning up once the using resource is deleted.
+func Setup(mgr ctrl.Manager, o controller.Options) error {
+	name := "usage/" + strings.ToLower(v1alpha1.UsageGroupKind)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Usage{}, usage.InUseIndexKey, usage.IndexUsageOf); err != nil {
+		return errors.Wrap(err, errAddIndex)
+	}
+
+	r := NewReconciler(mgr,
+		WithLogger(o.Logger.WithValues("controller", name)),
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithPollInterval(o.PollInterval))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		For(&v1alpha1.Usage{}).
+		WithOptions(o.ForControllerRuntime()).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// ReconcilerOption is used to configure the Reconciler.
+type ReconcilerOption func(*Reconciler)
+
+// WithLogger specifies how the Reconciler should log messages.
+func WithLogger(log logging.Logger) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.log = log
+	}
+}
+
+// WithRecorder specifies how the Reconciler should record Kubernetes events.
+func WithRecorder(er event.Recorder) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.record = er
+	}
+}
+
+// WithPollInterval specifies how long the Reconciler should wait before
+// checking again whether the using resource of a deleted Usage is gone.
+func WithPollInterval(after time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.pollInterval = after
+	}
+}
+
+// WithSelectorResolver specifies how the Reconciler should resolve the
+// selectors of a Usage.
+func WithSelectorResolver(sr SelectorResolver) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.usage.SelectorResolver = sr
+	}
+}
+
+// WithFinalizer specifies how the Reconciler should add and remove finalizers.
+func WithFinalizer(f resource.Finalizer) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.usage.Finalizer = f
+	}
+}
+
+type usageResource struct {
+	SelectorResolver
+	resource.Finalizer
+}
+
+// NewReconciler returns a Reconciler of Usages.
+func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
+	kube := mgr.GetClient()
+
+	r := &Reconciler{
+		client: kube,
+		usage: usageResource{
+			SelectorResolver: NewAPISelectorResolver(kube),
+			Finalizer:        resource.NewAPIFinalizer(kube, finalizer),
+		},
+		log:          logging.NewNopLogger(),
+		record:       event.NewNopRecorder(),
+		pollInterval: 30 * time.Second,
+	}
+
+	for _, f := range opts {
+		f(r)
+	}
+	return r
+}
+
+// A Reconciler reconciles Usages.
+type Reconciler struct {
+	client client.Client
+	usage  usageResource
+
+	log    logging.Logger
+	record event.Recorder
+
+	pollInterval time.Duration
+}
+
+// Reconcile a Usage. The resource a Usage is of is labelled as in use, which
+// causes the admission webhook to reject its deletion. The Usage is owned by
+// the resource that uses the other, so it is garbage collected once that
+// resource is deleted. Once no Usages of a resource remain its label is
+// removed, and any deletion of it that was rejected while it was in use is
+// replayed.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo // Reconcilers are typically complex.
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	u := &v1alpha1.Usage{}
+	if err := r.client.Get(ctx, req.NamespacedName, u); err != nil {
+		log.Debug(errGetUsage, "error", err)
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetUsage)
+	}
+
+	log = log.WithValues(
+		"uid", u.GetUID(),
+		"version", u.GetResourceVersion(),
+		"name", u.GetName(),
+	)
+
+	if err := r.usage.ResolveSelectors(ctx, u); err != nil {
+		log.Debug(errResolveSelectors, "error", err)
+		err = errors.Wrap(err, errResolveSelectors)
+		r.record.Event(u, event.Warning(reasonResolveSelectors, err))
+		return reconcile.Result{}, err
+	}
+
+	of := u.Spec.Of
+	by := u.Spec.By
+
+	used := &kunstructured.Unstructured{}
+	used.SetGroupVersionKind(schema.FromAPIVersionAndKind(of.APIVersion, of.Kind))
+	usedErr := r.client.Get(ctx, types.NamespacedName{Name: of.ResourceRef.Name}, used)
+	if usedErr != nil && !kerrors.IsNotFound(usedErr) {
+		log.Debug(errGetUsed, "error", usedErr)
+		err := errors.Wrap(usedErr, errGetUsed)
+		r.record.Event(u, event.Warning(reasonGetUsed, err))
+		return reconcile.Result{}, err
+	}
+
+	if meta.WasDeleted(u) {
+		if by != nil {
+			using := &kunstructured.Unstructured{}
+			using.SetGroupVersionKind(schema.FromAPIVersionAndKind(by.APIVersion, by.Kind))
+			err := r.client.Get(ctx, types.NamespacedName{Name: by.ResourceRef.Name}, using)
+			if resource.IgnoreNotFound(err) != nil {
+				log.Debug(errGetUsing, "error", err)
+				err = errors.Wrap(err, errGetUsing)
+				r.record.Event(u, event.Warning(reasonGetUsing, err))
+				return reconcile.Result{}, err
+			}
+			if err == nil {
+				// The Usage was deleted while the resource that uses the other
+				// still exists. We wait for the using resource to be deleted.
+				log.Debug(errWaitForUsingDelete)
+				r.record.Event(u, event.Normal(reasonWaitUsing, errWaitForUsingDelete))
+				return reconcile.Result{RequeueAfter: r.pollInterval}, nil
+			}
+		}
+
+		if usedErr == nil {
+			ul := &v1alpha1.UsageList{}
+			if err := r.client.List(ctx, ul, client.MatchingFields{usage.InUseIndexKey: usage.IndexValueForObject(used)}); err != nil {
+				log.Debug(errListUsages, "error", err)
+				err = errors.Wrap(err, errListUsages)
+				r.record.Event(u, event.Warning(reasonListUsages, err))
+				return reconcile.Result{}, err
+			}
+
+			if !otherUsages(ul.Items, u) {
+				meta.RemoveLabels(used, usage.LabelKeyInUse)
+				if err := r.client.Update(ctx, used); err != nil {
+					log.Debug(errRemoveInUseLabel, "error", err)
+					err = errors.Wrap(err, errRemoveInUseLabel)
+					r.record.Event(u, event.Warning(reasonRemoveInUseLabel, err))
+					return reconcile.Result{}, err
+				}
+
+				if policy, ok := u.GetAnnotations()[usage.AnnotationKeyDeletionAttempt]; ok {
+					p := metav1.DeletionPropagation(policy)
+					if err := r.client.Delete(ctx, used, &client.DeleteOptions{PropagationPolicy: &p}); resource.IgnoreNotFound(err) != nil {
+						log.Debug(errDeleteUsed, "error", err)
+						err = errors.Wrap(err, errDeleteUsed)
+						r.record.Event(u, event.Warning(reasonReplayDeletion, err))
+						return reconcile.Result{}, err
+					}
+					log.Debug("Replayed deletion of used resource", "policy", policy)
+				}
+			}
+		}
+
+		if err := r.usage.RemoveFinalizer(ctx, u); err != nil {
+			log.Debug(errRemoveFinalizer, "error", err)
+			err = errors.Wrap(err, errRemoveFinalizer)
+			r.record.Event(u, event.Warning(reasonRemoveFinalizer, err))
+			return reconcile.Result{}, err
+		}
+
+		log.Debug("Successfully deleted Usage")
+		return reconcile.Result{}, nil
+	}
+
+	if err := r.usage.AddFinalizer(ctx, u); err != nil {
+		log.Debug(errAddFinalizer, "error", err)
+		err = errors.Wrap(err, errAddFinalizer)
+		r.record.Event(u, event.Warning(reasonAddFinalizer, err))
+		return reconcile.Result{}, err
+	}
+
+	if usedErr != nil {
+		log.Debug(errGetUsed, "error", usedErr)
+		err := errors.Wrap(usedErr, errGetUsed)
+		r.record.Event(u, event.Warning(reasonGetUsed, err))
+		return reconcile.Result{}, err
+	}
+
+	if used.GetLabels()[usage.LabelKeyInUse] != "true" {
+		meta.AddLabels(used, map[string]string{usage.LabelKeyInUse: "true"})
+		if err := r.client.Update(ctx, used); err != nil {
+			log.Debug(errAddInUseLabel, "error", err)
+			err = errors.Wrap(err, errAddInUseLabel)
+			r.record.Event(u, event.Warning(reasonAddInUseLabel, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	if by != nil {
+		using := &kunstructured.Unstructured{}
+		using.SetGroupVersionKind(schema.FromAPIVersionAndKind(by.APIVersion, by.Kind))
+		if err := r.client.Get(ctx, types.NamespacedName{Name: by.ResourceRef.Name}, using); err != nil {
+			log.Debug(errGetUsing, "error", err)
+			err = errors.Wrap(err, errGetUsing)
+			r.record.Event(u, event.Warning(reasonGetUsing, err))
+			return reconcile.Result{}, err
+		}
+
+		// The Usage is owned by the using resource, so that it is garbage
+		// collected once the using resource is deleted.
+		owner := meta.AsOwner(meta.TypedReferenceTo(using, using.GroupVersionKind()))
+		if !hasOwner(u, owner.UID) {
+			meta.AddOwnerReference(u, owner)
+			if err := r.client.Update(ctx, u); err != nil {
+				log.Debug(errAddOwnerToUsage, "error", err)
+				err = errors.Wrap(err, errAddOwnerToUsage)
+				r.record.Event(u, event.Warning(reasonAddOwnerRef, err))
+				return reconcile.Result{}, err
+			}
+		}
+	}
+
+	r.record.Event(u, event.Normal(reasonUsageConfigured, "Usage configured successfully."))
+	u.Status.SetConditions(xpv1.Available())
+	return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, u), errUpdateStatus)
+}
+
+func otherUsages(usages []v1alpha1.Usage, u *v1alpha1.Usage) bool {
+	for i := range usages {
+		if usages[i].GetUID() != u.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+func hasOwner(o metav1.Object, uid types.UID) bool {
+	for _, ref := range o.GetOwnerReferences() {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,349 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+	"github.com/crossplane/crossplane/internal/usage"
+)
+
+type resolverFn func(ctx context.Context, u *v1alpha1.Usage) error
+
+func (fn resolverFn) ResolveSelectors(ctx context.Context, u *v1alpha1.Usage) error {
+	return fn(ctx, u)
+}
+
+var _ SelectorResolver = resolverFn(nil)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	newUsage := func(deleted bool, annotations map[string]string) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			u := obj.(*v1alpha1.Usage)
+			u.SetUID("usage-uid")
+			u.SetAnnotations(annotations)
+			u.Spec.Of = v1alpha1.Resource{APIVersion: "example.org/v1", Kind: "Used", ResourceRef: &v1alpha1.ResourceRef{Name: "used"}}
+			u.Spec.By = &v1alpha1.Resource{APIVersion: "example.org/v1", Kind: "Using", ResourceRef: &v1alpha1.ResourceRef{Name: "using"}}
+			if deleted {
+				u.SetDeletionTimestamp(&now)
+			}
+			return nil
+		}
+	}
+	noop := resolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return nil })
+
+	type args struct {
+		mgr  manager.Manager
+		opts []ReconcilerOption
+	}
+	type want struct {
+		r   reconcile.Result
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UsageNotFound": {
+			reason: "We should not return an error if the Usage was not found.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"ResolveSelectorsError": {
+			reason: "We should return an error if we can't resolve selectors.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(resolverFn(func(_ context.Context, _ *v1alpha1.Usage) error { return errBoom })),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errResolveSelectors),
+			},
+		},
+		"AddFinalizerError": {
+			reason: "We should return an error if we can't add the finalizer.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							if u, ok := obj.(*v1alpha1.Usage); ok {
+								return newUsage(false, nil)(u)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return errBoom }}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errAddFinalizer),
+			},
+		},
+		"GetUsedError": {
+			reason: "We should return an error if we can't get the used resource.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							if u, ok := obj.(*v1alpha1.Usage); ok {
+								return newUsage(false, nil)(u)
+							}
+							return errBoom
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetUsed),
+			},
+		},
+		"SuccessfulConfigure": {
+			reason: "We should label the used resource as in use and make the using resource the owner of the Usage.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha1.Usage:
+								return newUsage(false, nil)(o)
+							case *kunstructured.Unstructured:
+								o.SetName(o.GetKind())
+								o.SetUID(types.UID(o.GetKind() + "-uid"))
+							}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *kunstructured.Unstructured:
+								if diff := cmp.Diff(map[string]string{usage.LabelKeyInUse: "true"}, o.GetLabels()); diff != "" {
+									t.Errorf("Update(...): -want labels, +got labels:\n%s", diff)
+								}
+							case *v1alpha1.Usage:
+								want := []metav1.OwnerReference{{APIVersion: "example.org/v1", Kind: "Using", Name: "Using", UID: "Using-uid"}}
+								if diff := cmp.Diff(want, o.GetOwnerReferences()); diff != "" {
+									t.Errorf("Update(...): -want owner references, +got owner references:\n%s", diff)
+								}
+							}
+							return nil
+						}),
+						MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(obj client.Object) error {
+							want := xpv1.Available()
+							if diff := cmp.Diff(want, obj.(*v1alpha1.Usage).GetCondition(xpv1.TypeReady), test.EquateConditions()); diff != "" {
+								t.Errorf("Status().Update(...): -want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletedWaitingForUsing": {
+			reason: "We should wait for the using resource to be deleted before removing the finalizer of a deleted Usage.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							if u, ok := obj.(*v1alpha1.Usage); ok {
+								return newUsage(true, nil)(u)
+							}
+							return nil
+						}),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+					WithPollInterval(10 * time.Second),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: 10 * time.Second},
+			},
+		},
+		"DeletedOtherUsages": {
+			reason: "We should leave the used resource in use if other Usages of it exist.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha1.Usage:
+								return newUsage(true, map[string]string{usage.AnnotationKeyDeletionAttempt: string(metav1.DeletePropagationBackground)})(o)
+							case *kunstructured.Unstructured:
+								if o.GetKind() == "Using" {
+									return kerrors.NewNotFound(schema.GroupResource{}, "")
+								}
+							}
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+							obj.(*v1alpha1.UsageList).Items = []v1alpha1.Usage{{ObjectMeta: metav1.ObjectMeta{UID: "usage-uid"}}, {ObjectMeta: metav1.ObjectMeta{UID: "other-uid"}}}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(errBoom),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+					WithFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletedReplayDeletion": {
+			reason: "We should remove the in-use label and replay deletion of the used resource once no other Usages of it exist.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha1.Usage:
+								return newUsage(true, map[string]string{usage.AnnotationKeyDeletionAttempt: string(metav1.DeletePropagationBackground)})(o)
+							case *kunstructured.Unstructured:
+								if o.GetKind() == "Using" {
+									return kerrors.NewNotFound(schema.GroupResource{}, "")
+								}
+								o.SetLabels(map[string]string{usage.LabelKeyInUse: "true"})
+							}
+							return nil
+						}),
+						MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+							obj.(*v1alpha1.UsageList).Items = []v1alpha1.Usage{{ObjectMeta: metav1.ObjectMeta{UID: "usage-uid"}}}
+							return nil
+						}),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							if diff := cmp.Diff(map[string]string{}, obj.GetLabels()); diff != "" {
+								t.Errorf("Update(...): -want labels, +got labels:\n%s", diff)
+							}
+							return nil
+						}),
+						MockDelete: func(_ context.Context, _ client.Object, opts ...client.DeleteOption) error {
+							p := metav1.DeletePropagationBackground
+							want := []client.DeleteOption{&client.DeleteOptions{PropagationPolicy: &p}}
+							if diff := cmp.Diff(want, opts); diff != "" {
+								t.Errorf("Delete(...): -want options, +got options:\n%s", diff)
+							}
+							return nil
+						},
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+					WithFinalizer(resource.FinalizerFns{RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil }}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
+		"DeletedReplayDeletionError": {
+			reason: "We should return an error if we can't replay deletion of the used resource.",
+			args: args{
+				mgr: &fake.Manager{
+					Client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+							switch o := obj.(type) {
+							case *v1alpha1.Usage:
+								return newUsage(true, map[string]string{usage.AnnotationKeyDeletionAttempt: string(metav1.DeletePropagationBackground)})(o)
+							case *kunstructured.Unstructured:
+								if o.GetKind() == "Using" {
+									return kerrors.NewNotFound(schema.GroupResource{}, "")
+								}
+							}
+							return nil
+						}),
+						MockList:   test.NewMockListFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil),
+						MockDelete: test.NewMockDeleteFn(errBoom),
+					},
+				},
+				opts: []ReconcilerOption{
+					WithSelectorResolver(noop),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteUsed),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewReconciler(tc.args.mgr, tc.args.opts...)
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.r, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
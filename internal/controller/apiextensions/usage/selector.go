@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+// Error strings.
+const (
+	errUpdateAfterResolveSelector      = "cannot update usage after resolving selector"
+	errResolveSelectorForUsedResource  = "cannot resolve selector for used resource"
+	errResolveSelectorForUsingResource = "cannot resolve selector for using resource"
+	errNoSelectorToResolve             = "no selector defined for resolving"
+	errListResourceMatchingLabels      = "cannot list resources matching labels"
+
+	errFmtResourcesNotFound                  = "no %q found matching labels: %q"
+	errFmtResourcesNotFoundWithControllerRef = "no %q found matching labels: %q and with same controller reference"
+)
+
+// A SelectorResolver resolves the selectors of a Usage to references.
+type SelectorResolver interface {
+	ResolveSelectors(ctx context.Context, u *v1alpha1.Usage) error
+}
+
+// An APISelectorResolver resolves the selectors of a Usage by listing
+// resources from the API server.
+type APISelectorResolver struct {
+	client client.Client
+}
+
+// NewAPISelectorResolver returns a SelectorResolver that lists and updates
+// resources using the supplied client.
+func NewAPISelectorResolver(c client.Client) *APISelectorResolver {
+	return &APISelectorResolver{client: c}
+}
+
+// ResolveSelectors of the supplied Usage. The first resource matching each
+// selector is referenced by name, and the Usage is updated. Selectors are
+// ignored if the resource is already referenced by name.
+func (r *APISelectorResolver) ResolveSelectors(ctx context.Context, u *v1alpha1.Usage) error {
+	of := &u.Spec.Of
+	by := u.Spec.By
+
+	ofRequired := of.ResourceRef == nil || of.ResourceRef.Name == ""
+	byRequired := by != nil && (by.ResourceRef == nil || by.ResourceRef.Name == "")
+	if !ofRequired && !byRequired {
+		return nil
+	}
+
+	if ofRequired {
+		if err := r.resolveSelector(ctx, of, u); err != nil {
+			return errors.Wrap(err, errResolveSelectorForUsedResource)
+		}
+	}
+	if byRequired {
+		if err := r.resolveSelector(ctx, by, u); err != nil {
+			return errors.Wrap(err, errResolveSelectorForUsingResource)
+		}
+	}
+
+	return errors.Wrap(r.client.Update(ctx, u), errUpdateAfterResolveSelector)
+}
+
+func (r *APISelectorResolver) resolveSelector(ctx context.Context, rs *v1alpha1.Resource, u *v1alpha1.Usage) error {
+	if rs.ResourceSelector == nil {
+		return errors.New(errNoSelectorToResolve)
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(schema.FromAPIVersionAndKind(rs.APIVersion, rs.Kind+"List"))
+	if err := r.client.List(ctx, l, client.MatchingLabels(rs.ResourceSelector.MatchLabels)); err != nil {
+		return errors.Wrap(err, errListResourceMatchingLabels)
+	}
+
+	matchControllerRef := rs.ResourceSelector.MatchControllerRef != nil && *rs.ResourceSelector.MatchControllerRef
+	for i := range l.Items {
+		o := &l.Items[i]
+		if matchControllerRef && !sameController(o, u) {
+			continue
+		}
+		rs.ResourceRef = &v1alpha1.ResourceRef{Name: o.GetName()}
+		return nil
+	}
+
+	if matchControllerRef {
+		return errors.Errorf(errFmtResourcesNotFoundWithControllerRef, rs.Kind, rs.ResourceSelector.MatchLabels)
+	}
+	return errors.Errorf(errFmtResourcesNotFound, rs.Kind, rs.ResourceSelector.MatchLabels)
+}
+
+func sameController(a, b metav1.Object) bool {
+	ac := metav1.GetControllerOf(a)
+	bc := metav1.GetControllerOf(b)
+	return ac != nil && bc != nil && ac.UID == bc.UID
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+func TestResolveSelectors(t *testing.T) {
+	errBoom := errors.New("boom")
+	valueTrue := true
+	controller := metav1.OwnerReference{UID: "controller-uid", Controller: &valueTrue}
+
+	item := func(name string, ctrl *metav1.OwnerReference) kunstructured.Unstructured {
+		u := kunstructured.Unstructured{}
+		u.SetName(name)
+		if ctrl != nil {
+			u.SetOwnerReferences([]metav1.OwnerReference{*ctrl})
+		}
+		return u
+	}
+	list := func(items ...kunstructured.Unstructured) test.MockListFn {
+		return test.NewMockListFn(nil, func(obj client.ObjectList) error {
+			obj.(*kunstructured.UnstructuredList).Items = items
+			return nil
+		})
+	}
+
+	type args struct {
+		client client.Client
+		u      *v1alpha1.Usage
+	}
+	type want struct {
+		u   *v1alpha1.Usage
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AlreadyResolved": {
+			reason: "We should not list or update anything if all resources are referenced by name.",
+			args: args{
+				u: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{
+					Of: v1alpha1.Resource{ResourceRef: &v1alpha1.ResourceRef{Name: "used"}},
+				}},
+			},
+			want: want{
+				u: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{
+					Of: v1alpha1.Resource{ResourceRef: &v1alpha1.ResourceRef{Name: "used"}},
+				}},
+			},
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list resources matching a selector.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				u: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{
+					Of: v1alpha1.Resource{ResourceSelector: &v1alpha1.ResourceSelector{}},
+				}},
+			},
+			want: want{
+				u: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{
+					Of: v1alpha1.Resource{ResourceSelector: &v1alpha1.ResourceSelector{}},
+				}},
+				err: errors.Wrap(errors.Wrap(errBoom, errListResourceMatchingLabels), errResolveSelectorForUsedResource),
+			},
+		},
+		"NoMatchWithControllerRef": {
+			reason: "We should return an error if no resource with the same controller matches a selector.",
+			args: args{
+				client: &test.MockClient{MockList: list(item("used", nil))},
+				u: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{
+					Of: v1alpha1.Resource{Kind: "Used", ResourceSelector: &v1alpha1.ResourceSelector{MatchControllerRef: &valueTrue}},
+				}},
+			},
+			want: want{
+				u: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{
+					Of: v1alpha1.Resource{Kind: "Used", ResourceSelector: &v1alpha1.ResourceSelector{MatchControllerRef: &valueTrue}},
+				}},
+				err: errors.Wrap(errors.Errorf(errFmtResourcesNotFoundWithControllerRef, "Used", map[string]string(nil)), errResolveSelectorForUsedResource),
+			},
+		},
+		"Resolved": {
+			reason: "We should reference the first resource matching each selector and update the Usage.",
+			args: args{
+				client: &test.MockClient{
+					MockList:   list(item("other", nil), item("cool", &controller)),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+				u: &v1alpha1.Usage{
+					ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controller}},
+					Spec: v1alpha1.UsageSpec{
+						Of: v1alpha1.Resource{ResourceSelector: &v1alpha1.ResourceSelector{MatchControllerRef: &valueTrue}},
+						By: &v1alpha1.Resource{ResourceSelector: &v1alpha1.ResourceSelector{}},
+					},
+				},
+			},
+			want: want{
+				u: &v1alpha1.Usage{
+					ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{controller}},
+					Spec: v1alpha1.UsageSpec{
+						Of: v1alpha1.Resource{
+							ResourceRef:      &v1alpha1.ResourceRef{Name: "cool"},
+							ResourceSelector: &v1alpha1.ResourceSelector{MatchControllerRef: &valueTrue},
+						},
+						By: &v1alpha1.Resource{
+							ResourceRef:      &v1alpha1.ResourceRef{Name: "other"},
+							ResourceSelector: &v1alpha1.ResourceSelector{},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewAPISelectorResolver(tc.args.client)
+			err := r.ResolveSelectors(context.Background(), tc.args.u)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.ResolveSelectors(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.u, tc.args.u); diff != "" {
+				t.Errorf("\n%s\nr.ResolveSelectors(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package controller
 
 import (
+	"time"
+
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/feature"
 
@@ -58,6 +60,20 @@ type Options struct {
 	// and ESS plugins.
 	TLSClientSecretName string
 
+	// LockCompactionInterval is how often stale entries are removed from the
+	// package Lock. The Lock is not compacted if it is zero.
+	LockCompactionInterval time.Duration
+
+	// RevisionEventCompactionWindow is the window within which identical
+	// consecutive events about a package revision are collapsed into one.
+	// Events are not compacted if it is zero.
+	RevisionEventCompactionWindow time.Duration
+
+	// RevisionConditionHistoryLimit is the number of condition transitions
+	// recorded in the status of each package revision. Condition history is
+	// not recorded if it is zero.
+	RevisionConditionHistoryLimit int
+
 	// Features that should be enabled.
 	Features *feature.Flags
 }
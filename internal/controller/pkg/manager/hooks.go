@@ -0,0 +1,201 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errGetHookJob       = "cannot get lifecycle hook job"
+	errCreateHookJob    = "cannot create lifecycle hook job"
+	errCreateHookSA     = "cannot create lifecycle hook service account"
+	errInvalidHookJob   = "invalid lifecycle hook job"
+	errHookHostPath     = "lifecycle hook jobs may not mount hostPath volumes"
+	errHookPrivileged   = "lifecycle hook jobs may not run privileged containers"
+	errFmtHookJobFailed = "%s lifecycle hook job %s failed"
+)
+
+// maxHookJobNameLength is the maximum length of a lifecycle hook Job's name.
+// The Job controller labels the Job's pods with its name, so it must be a
+// valid label value.
+const maxHookJobNameLength = 63
+
+// hookJobNameHashLength is the length of the hash that replaces the end of a
+// revision name that is too long to be part of a hook Job's name.
+const hookJobNameHashLength = 12
+
+// An UpgradeHook is run when a package is upgraded from one revision to the
+// next.
+type UpgradeHook string
+
+// Upgrade hooks.
+const (
+	UpgradeHookPreUpgrade  UpgradeHook = "pre-upgrade"
+	UpgradeHookPostUpgrade UpgradeHook = "post-upgrade"
+)
+
+// An UpgradeHookRunner runs a package's lifecycle hooks.
+type UpgradeHookRunner interface {
+	// Run the supplied hook of the supplied package for the supplied
+	// revision. Run returns true once the hook has completed successfully,
+	// and an error if it has failed. A hook is run at most once per
+	// revision.
+	Run(ctx context.Context, p v1.Package, revisionName string, h UpgradeHook, spec *batchv1.JobSpec) (bool, error)
+}
+
+// An UpgradeHookRunnerFn is a function that satisfies UpgradeHookRunner.
+type UpgradeHookRunnerFn func(ctx context.Context, p v1.Package, revisionName string, h UpgradeHook, spec *batchv1.JobSpec) (bool, error)
+
+// Run the supplied hook.
+func (fn UpgradeHookRunnerFn) Run(ctx context.Context, p v1.Package, revisionName string, h UpgradeHook, spec *batchv1.JobSpec) (bool, error) {
+	return fn(ctx, p, revisionName, h, spec)
+}
+
+// A JobUpgradeHookRunner runs lifecycle hooks as Jobs.
+type JobUpgradeHookRunner struct {
+	client    client.Client
+	namespace string
+}
+
+// NewJobUpgradeHookRunner returns an UpgradeHookRunner that runs lifecycle
+// hooks as Jobs in the supplied namespace.
+func NewJobUpgradeHookRunner(c client.Client, namespace string) *JobUpgradeHookRunner {
+	return &JobUpgradeHookRunner{client: c, namespace: namespace}
+}
+
+// Run the supplied hook as a Job, creating the Job if it doesn't exist. The Job
+// is owned by the supplied package, and named for the supplied revision. It
+// runs as a service account of the same name.
+func (r *JobUpgradeHookRunner) Run(ctx context.Context, p v1.Package, revisionName string, h UpgradeHook, spec *batchv1.JobSpec) (bool, error) {
+	j := &batchv1.Job{}
+	nn := types.NamespacedName{Namespace: r.namespace, Name: hookJobName(revisionName, h)}
+	err := r.client.Get(ctx, nn, j)
+	if kerrors.IsNotFound(err) {
+		if err := validateHookPodSpec(&spec.Template.Spec); err != nil {
+			return false, errors.Wrap(err, errInvalidHookJob)
+		}
+		om := metav1.ObjectMeta{
+			Namespace: nn.Namespace,
+			Name:      nn.Name,
+			Labels:    map[string]string{v1.LabelParentPackage: p.GetName()},
+		}
+		meta.AddOwnerReference(&om, meta.AsOwner(meta.TypedReferenceTo(p, p.GetObjectKind().GroupVersionKind())))
+
+		// Hooks run as a dedicated service account that isn't bound to any
+		// roles, so a package can't use a hook to act as Crossplane or as
+		// another service account in Crossplane's namespace.
+		sa := &corev1.ServiceAccount{ObjectMeta: *om.DeepCopy(), AutomountServiceAccountToken: pointer.Bool(false)}
+		if err := r.client.Create(ctx, sa); resource.Ignore(kerrors.IsAlreadyExists, err) != nil {
+			return false, errors.Wrap(err, errCreateHookSA)
+		}
+
+		j = &batchv1.Job{ObjectMeta: om, Spec: *spec.DeepCopy()}
+		restrictHookPodSpec(&j.Spec.Template.Spec, sa.GetName())
+		return false, errors.Wrap(r.client.Create(ctx, j), errCreateHookJob)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, errGetHookJob)
+	}
+	for _, c := range j.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type { //nolint:exhaustive // Only completion and failure matter.
+		case batchv1.JobComplete:
+			return true, nil
+		case batchv1.JobFailed:
+			return false, errors.Errorf(errFmtHookJobFailed, h, j.GetName())
+		}
+	}
+	return false, nil
+}
+
+// NopUpgradeHookRunner completes all lifecycle hooks without running them.
+type NopUpgradeHookRunner struct{}
+
+// NewNopUpgradeHookRunner returns an UpgradeHookRunner that does nothing.
+func NewNopUpgradeHookRunner() *NopUpgradeHookRunner {
+	return &NopUpgradeHookRunner{}
+}
+
+// Run returns true and no error.
+func (r *NopUpgradeHookRunner) Run(context.Context, v1.Package, string, UpgradeHook, *batchv1.JobSpec) (bool, error) {
+	return true, nil
+}
+
+// hookJobName returns the name of the Job that runs the supplied hook for the
+// supplied revision.
+func hookJobName(revisionName string, h UpgradeHook) string {
+	suffix := "-" + string(h)
+	if len(revisionName)+len(suffix) <= maxHookJobNameLength {
+		return revisionName + suffix
+	}
+
+	// Revision names usually end with the digest that distinguishes them, so
+	// rather than cutting it off we replace the end of a long revision name
+	// with a hash of the whole name.
+	d := sha256.Sum256([]byte(revisionName))
+	hash := hex.EncodeToString(d[:])[:hookJobNameHashLength]
+	prefix := strings.TrimRight(revisionName[:maxHookJobNameLength-len(suffix)-len(hash)-1], "-.")
+	return prefix + "-" + hash + suffix
+}
+
+// validateHookPodSpec returns an error if the supplied hook pod spec asks for
+// access to its node that can't be safely overridden.
+func validateHookPodSpec(spec *corev1.PodSpec) error {
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			return errors.New(errHookHostPath)
+		}
+	}
+	for _, c := range append(append([]corev1.Container{}, spec.InitContainers...), spec.Containers...) {
+		if c.SecurityContext != nil && pointer.BoolDeref(c.SecurityContext.Privileged, false) {
+			return errors.New(errHookPrivileged)
+		}
+	}
+	return nil
+}
+
+// restrictHookPodSpec overrides any service account and host namespaces the
+// supplied hook pod spec asks for. Hooks run as the supplied service account,
+// without its token, and in their own network, PID, and IPC namespaces.
+func restrictHookPodSpec(spec *corev1.PodSpec, serviceAccountName string) {
+	spec.ServiceAccountName = serviceAccountName
+	spec.DeprecatedServiceAccount = ""
+	spec.AutomountServiceAccountToken = pointer.Bool(false)
+	spec.HostNetwork = false
+	spec.HostPID = false
+	spec.HostIPC = false
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package manager
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestJobUpgradeHookRunner(t *testing.T) {
+	errBoom := errors.New("boom")
+	spec := &batchv1.JobSpec{BackoffLimit: pointer.Int32(1)}
+
+	p := &v1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "cool", UID: "cool-uid"}}
+	p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+
+	job := func(cs ...batchv1.JobCondition) func(o client.Object) error {
+		return func(o client.Object) error {
+			o.(*batchv1.Job).Status.Conditions = cs
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Client
+		h      UpgradeHook
+		spec   *batchv1.JobSpec
+	}
+	type want struct {
+		done bool
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrGetJob": {
+			reason: "We should return any error encountered getting the Job.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				h:      UpgradeHookPreUpgrade,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetHookJob),
+			},
+		},
+		"CreateJob": {
+			reason: "We should create a Job owned by the package, and a service account for it to run as, if it doesn't exist.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+						om := metav1.ObjectMeta{
+							Namespace: "crossplane-system",
+							Name:      "cool-1234567-pre-upgrade",
+							Labels:    map[string]string{v1.LabelParentPackage: "cool"},
+							OwnerReferences: []metav1.OwnerReference{{
+								APIVersion: v1.ConfigurationGroupVersionKind.GroupVersion().String(),
+								Kind:       v1.ConfigurationKind,
+								Name:       "cool",
+								UID:        "cool-uid",
+							}},
+						}
+						var want client.Object = &corev1.ServiceAccount{ObjectMeta: om, AutomountServiceAccountToken: pointer.Bool(false)}
+						if _, ok := o.(*batchv1.Job); ok {
+							want = &batchv1.Job{
+								ObjectMeta: om,
+								Spec: batchv1.JobSpec{
+									BackoffLimit: pointer.Int32(1),
+									Template: corev1.PodTemplateSpec{
+										Spec: corev1.PodSpec{
+											ServiceAccountName:           "cool-1234567-pre-upgrade",
+											AutomountServiceAccountToken: pointer.Bool(false),
+										},
+									},
+								},
+							}
+						}
+						if diff := cmp.Diff(want, o); diff != "" {
+							t.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				h: UpgradeHookPreUpgrade,
+			},
+			want: want{
+				done: false,
+			},
+		},
+		"OverrideServiceAccountAndHostNamespaces": {
+			reason: "We should run the Job as its own service account without host namespaces, regardless of what the hook asks for.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+						j, ok := o.(*batchv1.Job)
+						if !ok {
+							return nil
+						}
+						want := corev1.PodSpec{
+							ServiceAccountName:           "cool-1234567-pre-upgrade",
+							AutomountServiceAccountToken: pointer.Bool(false),
+						}
+						if diff := cmp.Diff(want, j.Spec.Template.Spec); diff != "" {
+							t.Errorf("-want, +got:\n%s", diff)
+						}
+						return nil
+					}),
+				},
+				h: UpgradeHookPreUpgrade,
+				spec: &batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName:           "crossplane",
+							DeprecatedServiceAccount:     "crossplane",
+							AutomountServiceAccountToken: pointer.Bool(true),
+							HostNetwork:                  true,
+							HostPID:                      true,
+							HostIPC:                      true,
+						},
+					},
+				},
+			},
+			want: want{
+				done: false,
+			},
+		},
+		"RejectHostPath": {
+			reason: "We should refuse to create a Job that mounts a hostPath volume.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				h: UpgradeHookPreUpgrade,
+				spec: &batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Volumes: []corev1.Volume{{Name: "host", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}}},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(errHookHostPath), errInvalidHookJob),
+			},
+		},
+		"RejectPrivileged": {
+			reason: "We should refuse to create a Job that runs a privileged container.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				h: UpgradeHookPreUpgrade,
+				spec: &batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							Containers: []corev1.Container{{Name: "hook", SecurityContext: &corev1.SecurityContext{Privileged: pointer.Bool(true)}}},
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(errHookPrivileged), errInvalidHookJob),
+			},
+		},
+		"ErrCreateServiceAccount": {
+			reason: "We should return any error encountered creating the service account.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:    test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(errBoom),
+				},
+				h: UpgradeHookPreUpgrade,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateHookSA),
+			},
+		},
+		"ErrCreateJob": {
+			reason: "We should return any error encountered creating the Job.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+					MockCreate: test.NewMockCreateFn(nil, func(o client.Object) error {
+						if _, ok := o.(*corev1.ServiceAccount); ok {
+							return kerrors.NewAlreadyExists(schema.GroupResource{}, "")
+						}
+						return errBoom
+					}),
+				},
+				h: UpgradeHookPreUpgrade,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errCreateHookJob),
+			},
+		},
+		"JobRunning": {
+			reason: "We should return false if the Job is neither complete nor failed.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, job(batchv1.JobCondition{Type: batchv1.JobSuspended, Status: corev1.ConditionTrue}))},
+				h:      UpgradeHookPostUpgrade,
+			},
+			want: want{
+				done: false,
+			},
+		},
+		"JobComplete": {
+			reason: "We should return true if the Job is complete.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, job(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}))},
+				h:      UpgradeHookPostUpgrade,
+			},
+			want: want{
+				done: true,
+			},
+		},
+		"JobFailed": {
+			reason: "We should return an error if the Job failed.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					o.SetName("cool-1234567-post-upgrade")
+					return job(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue})(o)
+				})},
+				h: UpgradeHookPostUpgrade,
+			},
+			want: want{
+				err: errors.Errorf(errFmtHookJobFailed, UpgradeHookPostUpgrade, "cool-1234567-post-upgrade"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewJobUpgradeHookRunner(tc.args.client, "crossplane-system")
+			s := spec
+			if tc.args.spec != nil {
+				s = tc.args.spec
+			}
+			done, err := r.Run(context.Background(), p, "cool-1234567", tc.args.h, s)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Run(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.done, done); diff != "" {
+				t.Errorf("\n%s\nr.Run(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHookJobName(t *testing.T) {
+	cases := map[string]struct {
+		reason   string
+		revision string
+		want     string
+	}{
+		"Short": {
+			reason:   "A short revision name should be used as is.",
+			revision: "cool-1234567",
+			want:     "cool-1234567-post-upgrade",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := hookJobName(tc.revision, UpgradeHookPostUpgrade)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nhookJobName(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	// Long revision names that differ only in their digest should not produce
+	// the same Job name.
+	a := hookJobName(strings.Repeat("a", 50)+"-1111111111111", UpgradeHookPostUpgrade)
+	b := hookJobName(strings.Repeat("a", 50)+"-2222222222222", UpgradeHookPostUpgrade)
+	for _, got := range []string{a, b} {
+		if len(got) > maxHookJobNameLength || !strings.HasSuffix(got, "-post-upgrade") {
+			t.Errorf("hookJobName(...): got %q, want at most %d characters ending in -post-upgrade", got, maxHookJobNameLength)
+		}
+	}
+	if a == b {
+		t.Errorf("hookJobName(...): got %q for revisions with different digests, want different names", a)
+	}
+}
@@ -25,13 +25,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/utils/pointer"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
@@ -39,8 +43,11 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/features"
+	"github.com/crossplane/crossplane/internal/initializer"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
@@ -51,6 +58,14 @@ const (
 	// updated content for the given package reference. This behavior is only
 	// enabled when the packagePullPolicy is Always.
 	pullWait = 1 * time.Minute
+
+	// hookWait is how often the package manager checks whether a running
+	// lifecycle hook has completed.
+	hookWait = 10 * time.Second
+
+	// minReconcileInterval is the shortest interval at which a package may
+	// ask to be re-synced after a successful reconcile.
+	minReconcileInterval = 30 * time.Second
 )
 
 func pullBasedRequeue(p *corev1.PullPolicy) reconcile.Result {
@@ -60,6 +75,20 @@ func pullBasedRequeue(p *corev1.PullPolicy) reconcile.Result {
 	return reconcile.Result{Requeue: false}
 }
 
+// successfulRequeue returns when the supplied package should be re-synced
+// after a successful reconcile. The package's reconcile interval, if any,
+// takes precedence over its pull policy.
+func successfulRequeue(p v1.Package) reconcile.Result {
+	i := p.GetReconcileInterval()
+	if i == nil {
+		return pullBasedRequeue(p.GetPackagePullPolicy())
+	}
+	if i.Duration < minReconcileInterval {
+		return reconcile.Result{RequeueAfter: minReconcileInterval}
+	}
+	return reconcile.Result{RequeueAfter: i.Duration}
+}
+
 const (
 	errGetPackage           = "cannot get package"
 	errListRevisions        = "cannot list revisions for package"
@@ -73,6 +102,12 @@ const (
 	errUnhealthyPackageRevision     = "current package revision is unhealthy"
 	errUnknownPackageRevisionHealth = "current package revision health is unknown"
 
+	errFmtDowngrade      = "refusing to downgrade from %s to %s; set the %s annotation to allow it"
+	errFmtInstallTimeout = "package was not installed within its install timeout of %s"
+
+	errPreUpgradeHook  = "cannot run pre-upgrade hook"
+	errPostUpgradeHook = "cannot run post-upgrade hook"
+
 	errCreateK8sClient = "failed to initialize clientset"
 	errBuildFetcher    = "cannot build fetcher"
 )
@@ -84,6 +119,7 @@ const (
 	reasonTransitionRevision event.Reason = "TransitionRevision"
 	reasonGarbageCollect     event.Reason = "GarbageCollect"
 	reasonInstall            event.Reason = "InstallPackageRevision"
+	reasonUpgradeHook        event.Reason = "RunUpgradeHook"
 )
 
 const (
@@ -155,6 +191,24 @@ func WithRevisioner(d Revisioner) ReconcilerOption {
 	}
 }
 
+// WithRevisionConditionPropagation specifies that the Reconciler should copy
+// the Healthy and Installed conditions of a package's active revision to the
+// package. The reasons of propagated conditions are prefixed with
+// v1.ReasonPrefixRevision.
+func WithRevisionConditionPropagation() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.propagateRevisionConditions = true
+	}
+}
+
+// WithUpgradeHookRunner specifies how the Reconciler should run a package's
+// lifecycle hooks.
+func WithUpgradeHookRunner(h UpgradeHookRunner) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.hooks = h
+	}
+}
+
 // WithLogger specifies how the Reconciler should log messages.
 func WithLogger(log logging.Logger) ReconcilerOption {
 	return func(r *Reconciler) {
@@ -173,6 +227,7 @@ func WithRecorder(er event.Recorder) ReconcilerOption {
 type Reconciler struct {
 	client               resource.ClientApplicator
 	pkg                  Revisioner
+	hooks                UpgradeHookRunner
 	log                  logging.Logger
 	record               event.Recorder
 	webhookTLSSecretName *string
@@ -180,6 +235,8 @@ type Reconciler struct {
 	tlsServerSecretName  *string
 	tlsClientSecretName  *string
 
+	propagateRevisionConditions bool
+
 	newPackage             func() v1.Package
 	newPackageRevision     func() v1.PackageRevision
 	newPackageRevisionList func() v1.PackageRevisionList
@@ -201,11 +258,17 @@ func SetupProvider(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, errBuildFetcher)
 	}
 
+	var rv Revisioner = NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))
+	if o.Features.Enabled(features.EnableAlphaPackageConfigMapSources) {
+		rv = NewConfigMapRevisioner(mgr.GetAPIReader(), rv)
+	}
+
 	opts := []ReconcilerOption{
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(rv),
+		WithUpgradeHookRunner(NewJobUpgradeHookRunner(mgr.GetClient(), o.Namespace)),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	}
@@ -246,11 +309,17 @@ func SetupConfiguration(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, "cannot build fetcher")
 	}
 
+	var rv Revisioner = NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))
+	if o.Features.Enabled(features.EnableAlphaPackageConfigMapSources) {
+		rv = NewConfigMapRevisioner(mgr.GetAPIReader(), rv)
+	}
+
 	r := NewReconciler(mgr,
 		WithNewPackageFn(np),
 		WithNewPackageRevisionFn(nr),
 		WithNewPackageRevisionListFn(nrl),
-		WithRevisioner(NewPackageRevisioner(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithRevisioner(rv),
+		WithUpgradeHookRunner(NewJobUpgradeHookRunner(mgr.GetClient(), o.Namespace)),
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 	)
@@ -271,6 +340,7 @@ func NewReconciler(mgr ctrl.Manager, opts ...ReconcilerOption) *Reconciler {
 			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
 		},
 		pkg:    NewNopRevisioner(),
+		hooks:  NewNopUpgradeHookRunner(),
 		log:    logging.NewNopLogger(),
 		record: event.NewNopRecorder(),
 	}
@@ -304,6 +374,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"name", p.GetName(),
 	)
 
+	// A package that is already healthy at its current source has finished
+	// installing, so there's no need to time its install.
+	installed := p.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue && p.GetCurrentIdentifier() == p.GetSource()
+	timedOut := !installed && installTimedOut(p, time.Now())
+
 	// Get existing package revisions.
 	prs := r.newPackageRevisionList()
 	if err := r.client.List(ctx, prs, client.MatchingLabels(map[string]string{v1.LabelParentPackage: p.GetName()})); resource.IgnoreNotFound(err) != nil {
@@ -320,6 +395,13 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		p.SetConditions(v1.Unpacking().WithMessage(err.Error()))
 		r.record.Event(p, event.Warning(reasonUnpack, err))
 
+		// Stop retrying once the install has timed out. We'll try again
+		// if the package's source changes.
+		if timedOut {
+			p.SetConditions(installTimeout(p))
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+
 		if updateErr := r.client.Status().Update(ctx, p); updateErr != nil {
 			return reconcile.Result{}, errors.Wrap(updateErr, errUpdateStatus)
 		}
@@ -330,38 +412,71 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if revisionName == "" {
 		p.SetConditions(v1.Unpacking())
 		r.record.Event(p, event.Normal(reasonUnpack, "Waiting for unpack to complete"))
+		if timedOut {
+			p.SetConditions(installTimeout(p))
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
 		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
 	}
 
-	// Set the current revision and identifier.
+	if from := activeDowngradeSource(p, revisionName, prs.GetRevisions()); from != "" {
+		err := errors.Errorf(errFmtDowngrade, from, p.GetSource(), v1.AnnotationAllowDowngrade)
+		log.Debug("Refusing to downgrade package", "error", err)
+		p.SetConditions(v1.DowngradePrevented().WithMessage(err.Error()))
+		r.record.Event(p, event.Warning(reasonTransitionRevision, err))
+		return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	}
+
+	// Set the current revision and identifier, remembering the revision we're
+	// moving from.
+	if c := p.GetCurrentRevision(); c != "" && c != revisionName {
+		p.SetLastRevision(c)
+	}
 	p.SetCurrentRevision(revisionName)
 	p.SetCurrentIdentifier(p.GetSource())
 
+	// Run the package's pre-upgrade hook, if any, before we deactivate the
+	// revision we're upgrading from. The previous revision remains active
+	// until the hook completes, and the upgrade stops if it fails.
+	if h := p.GetLifecycleHooks(); h != nil && h.PreUpgrade != nil && upgrading(revisionName, prs.GetRevisions()) {
+		done, err := r.hooks.Run(ctx, p, revisionName, UpgradeHookPreUpgrade, h.PreUpgrade)
+		if err != nil {
+			log.Debug(errPreUpgradeHook, "error", err)
+			err = errors.Wrap(err, errPreUpgradeHook)
+			p.SetConditions(v1.UpgradeHookFailed().WithMessage(err.Error()))
+			r.record.Event(p, event.Warning(reasonUpgradeHook, err))
+			return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+		if !done {
+			p.SetConditions(v1.RunningUpgradeHook())
+			return reconcile.Result{RequeueAfter: hookWait}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
 	pr := r.newPackageRevision()
-	maxRevision := int64(0)
+	exists := false
 	oldestRevision := int64(math.MaxInt64)
-	oldestRevisionIndex := -1
 	revisions := prs.GetRevisions()
 
+	// Set max revision to the highest numbered existing revision.
+	maxRevision := int64(0)
+	if latest := prs.LatestRevision(); latest != nil && latest.GetRevision() > 0 {
+		maxRevision = latest.GetRevision()
+	}
+
 	// Check to see if revision already exists.
-	for index, rev := range revisions {
+	for _, rev := range revisions {
 		revisionNum := rev.GetRevision()
 
-		// Set max revision to the highest numbered existing revision.
-		if revisionNum > maxRevision {
-			maxRevision = revisionNum
-		}
-
-		// Set oldest revision to the lowest numbered revision and
-		// record its index.
+		// Set oldest revision to the lowest numbered revision.
 		if revisionNum < oldestRevision {
 			oldestRevision = revisionNum
-			oldestRevisionIndex = index
 		}
 		// If revision name is same as current revision, then revision
 		// already exists.
 		if rev.GetName() == p.GetCurrentRevision() {
 			pr = rev
+			exists = true
 			// Finish iterating through all revisions to make sure
 			// all non-current revisions are inactive.
 			continue
@@ -389,9 +504,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	if p.GetRevisionHistoryLimit() != nil &&
 		*p.GetRevisionHistoryLimit() != 0 &&
 		len(revisions) > (int(*p.GetRevisionHistoryLimit())+1) {
-		gcRev := revisions[oldestRevisionIndex]
 		// Find the oldest revision and delete it.
-		if err := r.client.Delete(ctx, gcRev); err != nil {
+		if err := r.client.Delete(ctx, prs.RevisionForNumber(oldestRevision)); err != nil {
+			log.Debug(errGCPackageRevision, "error", err)
+			err = errors.Wrap(err, errGCPackageRevision)
+			r.record.Event(p, event.Warning(reasonGarbageCollect, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	// Inactive revisions are also garbage collected once they expire.
+	for _, rev := range expiredRevisions(revisions, p.GetCurrentRevision(), time.Now()) {
+		if err := r.client.Delete(ctx, rev); resource.IgnoreNotFound(err) != nil {
 			log.Debug(errGCPackageRevision, "error", err)
 			err = errors.Wrap(err, errGCPackageRevision)
 			r.record.Event(p, event.Warning(reasonGarbageCollect, err))
@@ -401,6 +525,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	if pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
 		p.SetConditions(v1.Healthy())
+		p.SetInstallAttempt(nil)
 		r.record.Event(p, event.Normal(reasonInstall, "Successfully installed package revision"))
 	}
 	if pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionFalse {
@@ -411,21 +536,45 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		p.SetConditions(v1.UnknownHealth())
 		r.record.Event(p, event.Warning(reasonInstall, errors.New(errUnknownPackageRevisionHealth)))
 	}
+	if timedOut && pr.GetCondition(v1.TypeHealthy).Status != corev1.ConditionTrue {
+		p.SetConditions(installTimeout(p))
+	}
+
+	// The current revision is labelled with its family, if any, once it
+	// has been unpacked. We record it as a typed reference on the package.
+	p.SetProviderFamilyRef(nil)
+	if family := pr.GetLabels()[v1.LabelProviderFamily]; family != "" {
+		p.SetProviderFamilyRef(&corev1.LocalObjectReference{Name: family})
+	}
+
+	// Likewise we record the default CompositeResourceDefinition installed by
+	// the current revision, if any.
+	p.SetDefaultCompositeResourceRef(defaultCompositeResourceRef(pr))
 
 	// Create the non-existent package revision.
 	pr.SetName(revisionName)
 	pr.SetLabels(map[string]string{v1.LabelParentPackage: p.GetName()})
 	pr.SetSource(p.GetSource())
-	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
-	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
-	pr.SetIgnoreCrossplaneConstraints(p.GetIgnoreCrossplaneConstraints())
-	pr.SetSkipDependencyResolution(p.GetSkipDependencyResolution())
-	pr.SetControllerConfigRef(p.GetControllerConfigRef())
 	pr.SetWebhookTLSSecretName(r.webhookTLSSecretName)
 	pr.SetESSTLSSecretName(r.essTLSSecretName)
 	pr.SetTLSServerSecretName(getSecretName(p.GetName(), fmtTLSServerSecretName))
 	pr.SetTLSClientSecretName(getSecretName(p.GetName(), fmtTLSClientSecretName))
-	pr.SetCommonLabels(p.GetCommonLabels())
+	pr.SetVerificationCertRef(&corev1.SecretReference{Name: initializer.RootCACertSecretName})
+
+	// A revision's expiry is fixed when it is created.
+	if ttl := p.GetRevisionTTL(); !exists && ttl != nil {
+		pr.SetExpiresAt(&metav1.Time{Time: time.Now().Add(ttl.Duration)})
+	}
+
+	// New revisions always get all of the package's fields, but we only sync
+	// the fields allowed by the package's update policy to existing ones.
+	syncAll := !exists || shouldSyncAll(p.GetRevisionUpdatePolicy())
+	switch {
+	case syncAll:
+		syncRevisionSpec(p, pr)
+	case *p.GetRevisionUpdatePolicy() == v1.RevisionUpdatePullSecretOnly:
+		pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
+	}
 
 	// If current revision is not active and we have an automatic or
 	// undefined activation policy, always activate.
@@ -445,7 +594,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	// Handle changes in labels
 	same := reflect.DeepEqual(pr.GetCommonLabels(), p.GetCommonLabels())
-	if !same {
+	if syncAll && !same {
 		pr.SetCommonLabels(p.GetCommonLabels())
 		if err := r.client.Update(ctx, pr); err != nil {
 			log.Debug(errApplyPackageRevision, "error", err)
@@ -457,16 +606,180 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	p.SetConditions(v1.Active())
 
+	found, inst, invalid := pr.GetDependencyStatus()
+	p.SetDependencyStatus(&v1.DependencyStatus{Found: found, Installed: inst, Invalid: invalid})
+
 	// If current revision is still not active, the package is inactive.
 	if pr.GetDesiredState() != v1.PackageRevisionActive {
 		p.SetConditions(v1.Inactive())
 	}
 
+	if r.propagateRevisionConditions && pr.GetDesiredState() == v1.PackageRevisionActive {
+		propagateRevisionConditions(p, pr)
+	}
+
+	// Run the package's post-upgrade hook, if any, once a revision that
+	// replaced an earlier revision is active and healthy.
+	if h := p.GetLifecycleHooks(); h != nil && h.PostUpgrade != nil && pr.GetRevision() > 1 &&
+		pr.GetDesiredState() == v1.PackageRevisionActive && pr.GetCondition(v1.TypeHealthy).Status == corev1.ConditionTrue {
+		done, err := r.hooks.Run(ctx, p, revisionName, UpgradeHookPostUpgrade, h.PostUpgrade)
+		if err != nil {
+			// The new revision is already active, so there's nothing to
+			// stop. We just let the user know the hook failed.
+			log.Debug(errPostUpgradeHook, "error", err)
+			r.record.Event(p, event.Warning(reasonUpgradeHook, errors.Wrap(err, errPostUpgradeHook)))
+		}
+		if err == nil && !done {
+			return reconcile.Result{RequeueAfter: hookWait}, errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+		}
+	}
+
 	// NOTE(hasheddan): when the first package revision is created for a
 	// package, the health of the package is not set until the revision reports
 	// its health. If updating from an existing revision, the package health
 	// will match the health of the old revision until the next reconcile.
-	return pullBasedRequeue(p.GetPackagePullPolicy()), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+	return successfulRequeue(p), errors.Wrap(r.client.Status().Update(ctx, p), errUpdateStatus)
+}
+
+// upgrading returns true if a revision other than the named revision is
+// active, i.e. if activating the named revision would upgrade the package.
+func upgrading(revisionName string, revs []v1.PackageRevision) bool {
+	for _, rev := range revs {
+		if rev.GetName() != revisionName && rev.GetDesiredState() == v1.PackageRevisionActive {
+			return true
+		}
+	}
+	return false
+}
+
+// installTimedOut returns true if the supplied package has an install timeout
+// that has elapsed since it started installing its current source. It starts
+// timing the install if the package has no install attempt, or if its source
+// has changed since its install attempt started.
+func installTimedOut(p v1.Package, now time.Time) bool {
+	t := p.GetInstallTimeout()
+	if t == nil {
+		p.SetInstallAttempt(nil)
+		return false
+	}
+	a := p.GetInstallAttempt()
+	if a == nil || a.Source != p.GetSource() {
+		p.SetInstallAttempt(&v1.InstallAttempt{Source: p.GetSource(), StartTime: metav1.NewTime(now)})
+		return false
+	}
+	return now.Sub(a.StartTime.Time) > t.Duration
+}
+
+// expiredRevisions returns the supplied revisions that are inactive, aren't
+// the named current revision, and expired before the supplied time.
+func expiredRevisions(revs []v1.PackageRevision, current string, now time.Time) []v1.PackageRevision {
+	expired := make([]v1.PackageRevision, 0)
+	for _, rev := range revs {
+		if rev.GetName() == current || rev.GetDesiredState() != v1.PackageRevisionInactive {
+			continue
+		}
+		if e := rev.GetExpiresAt(); e != nil && e.Time.Before(now) {
+			expired = append(expired, rev)
+		}
+	}
+	return expired
+}
+
+// installTimeout returns an InstallTimeout condition for the supplied package.
+func installTimeout(p v1.Package) xpv1.Condition {
+	return v1.InstallTimeout().WithMessage(errors.Errorf(errFmtInstallTimeout, p.GetInstallTimeout().Duration).Error())
+}
+
+// shouldSyncAll returns true if the supplied update policy allows all fields
+// of a package to be synced to its existing current revision. A nil policy
+// allows all fields to be synced.
+func shouldSyncAll(u *v1.RevisionUpdatePolicy) bool {
+	return u == nil || *u == v1.RevisionUpdateAll
+}
+
+// syncRevisionSpec copies the fields of the supplied package that may be
+// overridden by updating its revision to the supplied revision.
+func syncRevisionSpec(p v1.Package, pr v1.PackageRevision) {
+	pr.SetPackagePullPolicy(p.GetPackagePullPolicy())
+	pr.SetPackagePullSecrets(p.GetPackagePullSecrets())
+	pr.SetIgnoreCrossplaneConstraints(p.GetIgnoreCrossplaneConstraints())
+	pr.SetSkipDependencyResolution(p.GetSkipDependencyResolution())
+	pr.SetControllerConfigRef(p.GetControllerConfigRef())
+	pr.SetMaxUnavailable(p.GetMaxUnavailable())
+	pr.SetHealthCheck(p.GetHealthCheck())
+	pr.SetPriorityClassName(p.GetPriorityClassName())
+	pr.SetPodTemplateAnnotations(p.GetPodTemplateAnnotations())
+	pr.SetTolerations(p.GetTolerations())
+	pr.SetAffinity(p.GetProviderRevisionAffinity())
+	pr.SetNodeSelector(p.GetNodeSelector())
+	pr.SetReadinessGates(p.GetReadinessGates())
+	pr.SetMinReadySeconds(p.GetMinReadySeconds())
+	pr.SetReplicaCount(p.GetReplicaCount())
+	pr.SetLeaderElection(p.GetLeaderElection())
+	pr.SetExtraVolumes(p.GetExtraVolumes())
+	pr.SetExtraVolumeMounts(p.GetExtraVolumeMounts())
+	pr.SetObjectSelector(p.GetObjectSelector())
+	pr.SetCommonLabels(p.GetCommonLabels())
+}
+
+// propagateRevisionConditions copies the Healthy and Installed conditions of
+// the supplied revision to the supplied package, prefixing their reasons.
+// Conditions the revision hasn't set are not copied, so they don't overwrite
+// the package's own conditions.
+func propagateRevisionConditions(p v1.Package, pr v1.PackageRevision) {
+	for _, t := range []xpv1.ConditionType{v1.TypeHealthy, v1.TypeInstalled} {
+		c := pr.GetCondition(t)
+		if c.Reason == "" {
+			continue
+		}
+		c.Reason = v1.ReasonPrefixRevision + c.Reason
+		p.SetConditions(c)
+	}
+}
+
+// activeDowngradeSource returns the source of the active package revision if
+// moving from it to the package's current source would be a semantic version
+// downgrade that the package prevents. It returns an empty string otherwise.
+func activeDowngradeSource(p v1.Package, revisionName string, revs []v1.PackageRevision) string {
+	if p.GetPreventDowngrade() == nil || !*p.GetPreventDowngrade() {
+		return ""
+	}
+	if p.GetAnnotations()[v1.AnnotationAllowDowngrade] == "true" {
+		return ""
+	}
+	for _, rev := range revs {
+		if rev.GetName() == revisionName || rev.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		if isDowngrade(rev.GetSource(), p.GetSource()) {
+			return rev.GetSource()
+		}
+	}
+	return ""
+}
+
+// isDowngrade returns true if the tag of package source to is a lower semantic
+// version than the tag of package source from. Sources that aren't tagged with
+// a semantic version (e.g. digests or 'latest') are never considered a
+// downgrade.
+func isDowngrade(from, to string) bool {
+	fv, err := semverTag(from)
+	if err != nil {
+		return false
+	}
+	tv, err := semverTag(to)
+	if err != nil {
+		return false
+	}
+	return tv.LessThan(fv)
+}
+
+func semverTag(source string) (*semver.Version, error) {
+	t, err := name.NewTag(source)
+	if err != nil {
+		return nil, err
+	}
+	return semver.NewVersion(t.TagStr())
 }
 
 // a k8s secret name can be at most 253 characters long
@@ -479,3 +792,31 @@ func getSecretName(name, suffix string) *string {
 
 	return &s
 }
+
+// defaultCompositeResourceRef returns a reference to the default
+// CompositeResourceDefinition installed by the supplied revision. This is the
+// definition named by the revision's default composite resource annotation, or
+// the only definition the revision installs. It returns nil if there is no
+// default definition.
+func defaultCompositeResourceRef(pr v1.PackageRevision) *xpv1.TypedReference {
+	xrds := make([]xpv1.TypedReference, 0)
+	for _, ref := range pr.GetObjects() {
+		if ref.APIVersion == apiextensionsv1.SchemeGroupVersion.String() && ref.Kind == apiextensionsv1.CompositeResourceDefinitionKind {
+			xrds = append(xrds, ref)
+		}
+	}
+
+	if name := pr.GetAnnotations()[v1.AnnotationDefaultCompositeResource]; name != "" {
+		for i := range xrds {
+			if xrds[i].Name == name {
+				return &xrds[i]
+			}
+		}
+		return nil
+	}
+
+	if len(xrds) != 1 {
+		return nil
+	}
+	return &xrds[0]
+}
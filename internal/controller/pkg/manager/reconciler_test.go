@@ -20,8 +20,10 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,13 +33,16 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
+	apiextensionsv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/initializer"
 )
 
 var _ Revisioner = &MockRevisioner{}
@@ -64,8 +69,13 @@ func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
 	pullAlways := corev1.PullAlways
+	pullSecretOnly := v1.RevisionUpdatePullSecretOnly
 	trueVal := true
 	revHistory := int64(1)
+	source := "xpkg.upbound.io/crossplane/cool:v1"
+	timeout := &metav1.Duration{Duration: time.Minute}
+	attempt := &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(time.Now().Add(-1 * time.Hour))}
+	hooks := &v1.LifecycleHooks{PreUpgrade: &batchv1.JobSpec{}}
 
 	type args struct {
 		req reconcile.Request
@@ -169,6 +179,48 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errUnpack),
 			},
 		},
+		"ErrFetchRevisionInstallTimeout": {
+			reason: "We should stop retrying if fetching the revision for a package fails after its install timeout has elapsed.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetSource(source)
+								p.SetInstallTimeout(timeout)
+								p.SetInstallAttempt(attempt)
+								return nil
+							}),
+							MockList: test.NewMockListFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetSource(source)
+								want.SetInstallTimeout(timeout)
+								want.SetInstallAttempt(attempt)
+								want.SetConditions(v1.Unpacking().WithMessage(errors.Wrap(errBoom, errUnpack).Error()))
+								want.SetConditions(v1.InstallTimeout().WithMessage(errors.Errorf(errFmtInstallTimeout, time.Minute).Error()))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("", errBoom),
+					},
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulNoExistingRevisionsAutoActivate": {
 			reason: "We should be active and not requeue on successful creation of the first revision with auto activation.",
 			args: args{
@@ -195,6 +247,7 @@ func TestReconcile(t *testing.T) {
 								want.SetActivationPolicy(&v1.AutomaticActivation)
 								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -244,6 +297,7 @@ func TestReconcile(t *testing.T) {
 								want.SetPackagePullPolicy(&pullAlways)
 								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -291,6 +345,7 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.UnknownHealth())
 								want.SetConditions(v1.Inactive())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -353,6 +408,69 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulActiveRevisionInFamily": {
+			reason: "We should reference the family of the current revision when it belongs to one.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Provider{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ProviderRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ProviderRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Provider)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ProviderRevisionList)
+								pr := v1.ProviderRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name:   "test-1234567",
+										Labels: map[string]string{v1.LabelProviderFamily: "family-cool"},
+									},
+									Spec: v1.PackageRevisionSpec{
+										TLSServerSecretName: &tlsServerSecret,
+										TLSClientSecretName: &tlsClientSecret,
+									},
+								}
+								pr.SetConditions(v1.Healthy())
+								*l = v1.ProviderRevisionList{Items: []v1.ProviderRevision{pr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Provider{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetProviderFamilyRef(&corev1.LocalObjectReference{Name: "family-cool"})
+								want.SetConditions(v1.Healthy())
+								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -418,6 +536,7 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -441,6 +560,7 @@ func TestReconcile(t *testing.T) {
 							want.SetRevision(1)
 							want.SetTLSServerSecretName(&tlsServerSecret)
 							want.SetTLSClientSecretName(&tlsClientSecret)
+							want.SetVerificationCertRef(&corev1.SecretReference{Name: initializer.RootCACertSecretName})
 							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 								t.Errorf("-want, +got:\n%s", diff)
 							}
@@ -556,6 +676,140 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Unhealthy())
 								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulTransitionUnhealthyInstallTimeout": {
+			reason: "If the current revision is unhealthy after the package's install timeout has elapsed the package should report the timeout.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetSource(source)
+								p.SetInstallTimeout(timeout)
+								p.SetInstallAttempt(attempt)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Unhealthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetSource(source)
+								want.SetInstallTimeout(timeout)
+								want.SetInstallAttempt(attempt)
+								want.SetCurrentRevision("test-1234567")
+								want.SetCurrentIdentifier(source)
+								want.SetConditions(v1.InstallTimeout().WithMessage(errors.Errorf(errFmtInstallTimeout, time.Minute).Error()))
+								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulPropagateHealthyRevisionConditions": {
+			reason: "If revision conditions are propagated the package should be healthy with the reason of its healthy active revision.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(func() xpv1.Condition {
+									c := v1.Healthy()
+									c.Reason = v1.ReasonPrefixRevision + v1.ReasonHealthy
+									return c
+								}())
+								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -571,6 +825,75 @@ func TestReconcile(t *testing.T) {
 					},
 					log:    testLog,
 					record: event.NewNopRecorder(),
+
+					propagateRevisionConditions: true,
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulPropagateUnhealthyRevisionConditions": {
+			reason: "If revision conditions are propagated the package should be unhealthy with the reason and message of its unhealthy active revision, without overwriting its own Installed condition.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.ExtraObjectConflict().WithMessage("conflict"))
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								c := v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								*l = c
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								want.SetCurrentRevision("test-1234567")
+								want.SetConditions(func() xpv1.Condition {
+									c := v1.ExtraObjectConflict().WithMessage("conflict")
+									c.Reason = v1.ReasonPrefixRevision + v1.ReasonExtraObjectConflict
+									return c
+								}())
+								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+
+					propagateRevisionConditions: true,
 				},
 			},
 			want: want{
@@ -635,6 +958,7 @@ func TestReconcile(t *testing.T) {
 								want.SetCurrentRevision("test-1234567")
 								want.SetConditions(v1.Healthy())
 								want.SetConditions(v1.Active())
+								want.SetDependencyStatus(&v1.DependencyStatus{})
 								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
@@ -659,6 +983,7 @@ func TestReconcile(t *testing.T) {
 							want.SetRevision(3)
 							want.SetTLSServerSecretName(&tlsServerSecret)
 							want.SetTLSClientSecretName(&tlsClientSecret)
+							want.SetVerificationCertRef(&corev1.SecretReference{Name: initializer.RootCACertSecretName})
 							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
 								t.Errorf("-want, +got:\n%s", diff)
 							}
@@ -676,8 +1001,8 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
-		"ErrGC": {
-			reason: "Failure to garbage collect old package revision should cause return an error.",
+		"RevisionUpdatePolicyPullSecretOnly": {
+			reason: "We should only sync pull secrets to an existing revision if the package's update policy is PullSecretOnly.",
 			args: args{
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
 				rec: &Reconciler{
@@ -690,7 +1015,10 @@ func TestReconcile(t *testing.T) {
 								p := o.(*v1.Configuration)
 								p.SetName("test")
 								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
-								p.SetRevisionHistoryLimit(&revHistory)
+								p.SetRevisionUpdatePolicy(&pullSecretOnly)
+								p.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "new-secret"}})
+								p.SetIgnoreCrossplaneConstraints(&trueVal)
+								p.SetCommonLabels(map[string]string{"cool": "label"})
 								return nil
 							}),
 							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
@@ -700,10 +1028,80 @@ func TestReconcile(t *testing.T) {
 										Name: "test-1234567",
 									},
 								}
-								cr.SetRevision(3)
+								cr.SetRevision(1)
 								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								cr.SetConditions(v1.Healthy())
-								cr.SetDesiredState(v1.PackageRevisionInactive)
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								cr.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "old-secret"}})
+								*l = v1.ConfigurationRevisionList{Items: []v1.ConfigurationRevision{cr}}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := &v1.ConfigurationRevision{}
+							want.SetLabels(map[string]string{"pkg.crossplane.io/package": "test"})
+							want.SetName("test-1234567")
+							want.SetOwnerReferences([]metav1.OwnerReference{{
+								APIVersion:         v1.SchemeGroupVersion.String(),
+								Kind:               v1.ConfigurationKind,
+								Name:               "test",
+								Controller:         &trueVal,
+								BlockOwnerDeletion: &trueVal,
+							}})
+							want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+							want.SetDesiredState(v1.PackageRevisionActive)
+							want.SetConditions(v1.Healthy())
+							want.SetRevision(1)
+							want.SetPackagePullSecrets([]corev1.LocalObjectReference{{Name: "new-secret"}})
+							want.SetTLSServerSecretName(&tlsServerSecret)
+							want.SetTLSClientSecretName(&tlsClientSecret)
+							want.SetVerificationCertRef(&corev1.SecretReference{Name: initializer.RootCACertSecretName})
+							if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-1234567", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ErrGC": {
+			reason: "Failure to garbage collect old package revision should cause return an error.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetGroupVersionKind(v1.ConfigurationGroupVersionKind)
+								p.SetRevisionHistoryLimit(&revHistory)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetRevision(3)
+								cr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								cr.SetConditions(v1.Healthy())
+								cr.SetDesiredState(v1.PackageRevisionInactive)
 								c := v1.ConfigurationRevisionList{
 									Items: []v1.ConfigurationRevision{
 										cr,
@@ -755,6 +1153,191 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errGCPackageRevision),
 			},
 		},
+		"DowngradePrevented": {
+			reason: "We should not create a revision and should report a condition when downgrading a package that prevents downgrades.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetSource("xpkg.example.org/test/test:v1.0.0")
+								p.SetPreventDowngrade(&trueVal)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetSource("xpkg.example.org/test/test:v1.1.0")
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetSource("xpkg.example.org/test/test:v1.0.0")
+								want.SetPreventDowngrade(&trueVal)
+								want.SetConditions(v1.DowngradePrevented().WithMessage(errors.Errorf(errFmtDowngrade, "xpkg.example.org/test/test:v1.1.0", "xpkg.example.org/test/test:v1.0.0", v1.AnnotationAllowDowngrade).Error()))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("unexpected call to Apply")
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"PreUpgradeHookRunning": {
+			reason: "We should not deactivate the active revision or create a new one while the pre-upgrade hook is running.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetLifecycleHooks(hooks)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetLifecycleHooks(hooks)
+								want.SetCurrentRevision("test-7654321")
+								want.SetConditions(v1.RunningUpgradeHook())
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("unexpected call to Apply")
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					hooks: UpgradeHookRunnerFn(func(_ context.Context, _ v1.Package, revisionName string, h UpgradeHook, _ *batchv1.JobSpec) (bool, error) {
+						if revisionName != "test-7654321" || h != UpgradeHookPreUpgrade {
+							t.Errorf("unexpected hook %s for revision %s", h, revisionName)
+						}
+						return false, nil
+					}),
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: hookWait},
+			},
+		},
+		"PreUpgradeHookFailed": {
+			reason: "We should not deactivate the active revision or create a new one if the pre-upgrade hook fails.",
+			args: args{
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: &Reconciler{
+					newPackage:             func() v1.Package { return &v1.Configuration{} },
+					newPackageRevision:     func() v1.PackageRevision { return &v1.ConfigurationRevision{} },
+					newPackageRevisionList: func() v1.PackageRevisionList { return &v1.ConfigurationRevisionList{} },
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								p := o.(*v1.Configuration)
+								p.SetName("test")
+								p.SetLifecycleHooks(hooks)
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								l := o.(*v1.ConfigurationRevisionList)
+								cr := v1.ConfigurationRevision{
+									ObjectMeta: metav1.ObjectMeta{
+										Name: "test-1234567",
+									},
+								}
+								cr.SetDesiredState(v1.PackageRevisionActive)
+								*l = v1.ConfigurationRevisionList{
+									Items: []v1.ConfigurationRevision{cr},
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.Configuration{}
+								want.SetName("test")
+								want.SetLifecycleHooks(hooks)
+								want.SetCurrentRevision("test-7654321")
+								want.SetConditions(v1.UpgradeHookFailed().WithMessage(errors.Wrap(errBoom, errPreUpgradeHook).Error()))
+								if diff := cmp.Diff(want, o, test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, _ client.Object, _ ...resource.ApplyOption) error {
+							t.Errorf("unexpected call to Apply")
+							return nil
+						}),
+					},
+					pkg: &MockRevisioner{
+						MockRevision: NewMockRevisionFn("test-7654321", nil),
+					},
+					hooks: UpgradeHookRunnerFn(func(_ context.Context, _ v1.Package, revisionName string, h UpgradeHook, _ *batchv1.JobSpec) (bool, error) {
+						if revisionName != "test-7654321" || h != UpgradeHookPreUpgrade {
+							t.Errorf("unexpected hook %s for revision %s", h, revisionName)
+						}
+						return false, errBoom
+					}),
+					log:    testLog,
+					record: event.NewNopRecorder(),
+				},
+			},
+			want: want{
+				r: reconcile.Result{},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -770,3 +1353,320 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallTimedOut(t *testing.T) {
+	now := time.Now()
+	source := "xpkg.upbound.io/crossplane/cool:v1"
+	timeout := &metav1.Duration{Duration: time.Minute}
+
+	type want struct {
+		timedOut bool
+		attempt  *v1.InstallAttempt
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1.Package
+		want   want
+	}{
+		"NoTimeout": {
+			reason: "A package without an install timeout should never time out, and should have no install attempt.",
+			p: &v1.Configuration{
+				Spec:   v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{Package: source}},
+				Status: v1.ConfigurationStatus{PackageStatus: v1.PackageStatus{InstallAttempt: &v1.InstallAttempt{Source: source}}},
+			},
+			want: want{
+				timedOut: false,
+			},
+		},
+		"StartAttempt": {
+			reason: "A package with an install timeout but no install attempt should start one.",
+			p: &v1.Configuration{
+				Spec: v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{Package: source, InstallTimeout: timeout}},
+			},
+			want: want{
+				timedOut: false,
+				attempt:  &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(now)},
+			},
+		},
+		"SourceChanged": {
+			reason: "A package whose source has changed since its install attempt started should start a new one.",
+			p: &v1.Configuration{
+				Spec:   v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{Package: source, InstallTimeout: timeout}},
+				Status: v1.ConfigurationStatus{PackageStatus: v1.PackageStatus{InstallAttempt: &v1.InstallAttempt{Source: "old", StartTime: metav1.NewTime(now.Add(-1 * time.Hour))}}},
+			},
+			want: want{
+				timedOut: false,
+				attempt:  &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(now)},
+			},
+		},
+		"NotTimedOut": {
+			reason: "A package that started installing its source within its install timeout should not time out.",
+			p: &v1.Configuration{
+				Spec:   v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{Package: source, InstallTimeout: timeout}},
+				Status: v1.ConfigurationStatus{PackageStatus: v1.PackageStatus{InstallAttempt: &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(now.Add(-1 * time.Second))}}},
+			},
+			want: want{
+				timedOut: false,
+				attempt:  &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(now.Add(-1 * time.Second))},
+			},
+		},
+		"TimedOut": {
+			reason: "A package that started installing its source longer ago than its install timeout should time out.",
+			p: &v1.Configuration{
+				Spec:   v1.ConfigurationSpec{PackageSpec: v1.PackageSpec{Package: source, InstallTimeout: timeout}},
+				Status: v1.ConfigurationStatus{PackageStatus: v1.PackageStatus{InstallAttempt: &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(now.Add(-1 * time.Hour))}}},
+			},
+			want: want{
+				timedOut: true,
+				attempt:  &v1.InstallAttempt{Source: source, StartTime: metav1.NewTime(now.Add(-1 * time.Hour))},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := installTimedOut(tc.p, now)
+			if diff := cmp.Diff(tc.want.timedOut, got); diff != "" {
+				t.Errorf("\n%s\ninstallTimedOut(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.attempt, tc.p.GetInstallAttempt()); diff != "" {
+				t.Errorf("\n%s\ninstallTimedOut(...): -want attempt, +got attempt:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSuccessfulRequeue(t *testing.T) {
+	always := corev1.PullAlways
+	pkg := func(pp *corev1.PullPolicy, i *metav1.Duration) v1.Package {
+		return &v1.Provider{Spec: v1.ProviderSpec{PackageSpec: v1.PackageSpec{PackagePullPolicy: pp, ReconcileInterval: i}}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1.Package
+		want   reconcile.Result
+	}{
+		"Default": {
+			reason: "A package without a reconcile interval or Always pull policy should not be requeued.",
+			p:      pkg(nil, nil),
+			want:   reconcile.Result{Requeue: false},
+		},
+		"PullAlways": {
+			reason: "A package with an Always pull policy and no reconcile interval should be requeued after the pull wait.",
+			p:      pkg(&always, nil),
+			want:   reconcile.Result{RequeueAfter: pullWait},
+		},
+		"ReconcileInterval": {
+			reason: "A package's reconcile interval should override its pull policy.",
+			p:      pkg(&always, &metav1.Duration{Duration: 10 * time.Minute}),
+			want:   reconcile.Result{RequeueAfter: 10 * time.Minute},
+		},
+		"ShortReconcileInterval": {
+			reason: "A reconcile interval shorter than the minimum should be raised to the minimum.",
+			p:      pkg(nil, &metav1.Duration{Duration: time.Second}),
+			want:   reconcile.Result{RequeueAfter: minReconcileInterval},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := successfulRequeue(tc.p)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nsuccessfulRequeue(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDefaultCompositeResourceRef(t *testing.T) {
+	xrd := func(name string) xpv1.TypedReference {
+		return xpv1.TypedReference{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: apiextensionsv1.CompositeResourceDefinitionKind, Name: name}
+	}
+	comp := xpv1.TypedReference{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: apiextensionsv1.CompositionKind, Name: "cool"}
+	rev := func(annotation string, refs ...xpv1.TypedReference) v1.PackageRevision {
+		pr := &v1.ConfigurationRevision{}
+		if annotation != "" {
+			pr.SetAnnotations(map[string]string{v1.AnnotationDefaultCompositeResource: annotation})
+		}
+		pr.SetObjects(refs)
+		return pr
+	}
+	ref := func(r xpv1.TypedReference) *xpv1.TypedReference { return &r }
+
+	cases := map[string]struct {
+		reason string
+		pr     v1.PackageRevision
+		want   *xpv1.TypedReference
+	}{
+		"NoDefinitions": {
+			reason: "A revision that installs no definitions should have no default definition.",
+			pr:     rev("", comp),
+		},
+		"OneDefinition": {
+			reason: "A revision that installs only one definition should default to it.",
+			pr:     rev("", comp, xrd("xcools.example.org")),
+			want:   ref(xrd("xcools.example.org")),
+		},
+		"ManyDefinitions": {
+			reason: "A revision that installs many definitions and names none should have no default definition.",
+			pr:     rev("", xrd("xcools.example.org"), xrd("xwarms.example.org")),
+		},
+		"Annotated": {
+			reason: "A revision should default to the definition named by its annotation.",
+			pr:     rev("xwarms.example.org", xrd("xcools.example.org"), xrd("xwarms.example.org")),
+			want:   ref(xrd("xwarms.example.org")),
+		},
+		"AnnotatedNotInstalled": {
+			reason: "A revision whose annotation names a definition it doesn't install should have no default definition.",
+			pr:     rev("xhots.example.org", xrd("xcools.example.org")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := defaultCompositeResourceRef(tc.pr)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndefaultCompositeResourceRef(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIsDowngrade(t *testing.T) {
+	type args struct {
+		from string
+		to   string
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"LowerVersion": {
+			reason: "Moving to a lower semantic version is a downgrade.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.1.0",
+				to:   "xpkg.example.org/test/test:v1.0.0",
+			},
+			want: true,
+		},
+		"HigherVersion": {
+			reason: "Moving to a higher semantic version is not a downgrade.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.0.0",
+				to:   "xpkg.example.org/test/test:v1.1.0",
+			},
+			want: false,
+		},
+		"SameVersion": {
+			reason: "Moving to the same semantic version is not a downgrade.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.0.0",
+				to:   "xpkg.example.org/test/test:v1.0.0",
+			},
+			want: false,
+		},
+		"ReleaseToPreRelease": {
+			reason: "A pre-release has lower precedence than its associated release.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.0.0",
+				to:   "xpkg.example.org/test/test:v1.0.0-rc.1",
+			},
+			want: true,
+		},
+		"PreReleaseToRelease": {
+			reason: "A release has higher precedence than its pre-releases.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.0.0-rc.1",
+				to:   "xpkg.example.org/test/test:v1.0.0",
+			},
+			want: false,
+		},
+		"NotSemver": {
+			reason: "Tags that are not semantic versions are never a downgrade.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.0.0",
+				to:   "xpkg.example.org/test/test:latest",
+			},
+			want: false,
+		},
+		"Digest": {
+			reason: "Digests are never a downgrade.",
+			args: args{
+				from: "xpkg.example.org/test/test:v1.0.0",
+				to:   "xpkg.example.org/test/test@sha256:ecc25c121431dfc7058754427f97c034ecde26d4aafa0da16d2ad1eb1b7ec0b2",
+			},
+			want: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := isDowngrade(tc.args.from, tc.args.to)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nisDowngrade(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExpiredRevisions(t *testing.T) {
+	now := time.Now()
+	rev := func(name string, state v1.PackageRevisionDesiredState, expiresAt *time.Time) v1.PackageRevision {
+		pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		pr.SetDesiredState(state)
+		if expiresAt != nil {
+			pr.SetExpiresAt(&metav1.Time{Time: *expiresAt})
+		}
+		return pr
+	}
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	type args struct {
+		revs    []v1.PackageRevision
+		current string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoExpiry": {
+			reason: "Revisions without an expiry should never expire.",
+			args: args{
+				revs:    []v1.PackageRevision{rev("cool-1", v1.PackageRevisionInactive, nil)},
+				current: "cool-2",
+			},
+			want: []string{},
+		},
+		"Expired": {
+			reason: "Only inactive, non-current revisions whose expiry has passed should be expired.",
+			args: args{
+				revs: []v1.PackageRevision{
+					rev("cool-1", v1.PackageRevisionInactive, &past),
+					rev("cool-2", v1.PackageRevisionInactive, &future),
+					rev("cool-3", v1.PackageRevisionActive, &past),
+					rev("cool-4", v1.PackageRevisionInactive, &past),
+				},
+				current: "cool-4",
+			},
+			want: []string{"cool-1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := make([]string, 0)
+			for _, rev := range expiredRevisions(tc.args.revs, tc.args.current, now) {
+				got = append(got, rev.GetName())
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nexpiredRevisions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,9 +18,12 @@ package manager
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 
 	"github.com/google/go-containerregistry/pkg/name"
 	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 
@@ -31,6 +34,8 @@ import (
 const (
 	errBadReference = "package tag is not a valid reference"
 	errFetchPackage = "failed to fetch package digest from remote"
+
+	errReadConfigMapPackage = "cannot read package from ConfigMap"
 )
 
 // Revisioner extracts a revision name for a package source.
@@ -76,6 +81,13 @@ func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string,
 			return p.GetCurrentRevision(), nil
 		}
 	}
+	// A package that is only updated once ignores tag movement until its
+	// source changes.
+	if u := p.GetUpdatePolicy(); u != nil && *u == v1.UpdateOnce {
+		if p.GetCurrentRevision() != "" && p.GetCurrentIdentifier() == p.GetSource() {
+			return p.GetCurrentRevision(), nil
+		}
+	}
 	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(r.registry))
 	if err != nil {
 		return "", errors.Wrap(err, errBadReference)
@@ -87,6 +99,35 @@ func (r *PackageRevisioner) Revision(ctx context.Context, p v1.Package) (string,
 	return xpkg.FriendlyID(p.GetName(), d.Digest.Hex), nil
 }
 
+// ConfigMapRevisioner extracts a revision name for packages whose source
+// refers to a ConfigMap, per xpkg.ConfigMapSourcePrefix. The revision name is
+// derived from the digest of the package the ConfigMap contains, so updating
+// the ConfigMap results in a new revision. It passes packages with any other
+// source to its wrapped Revisioner.
+type ConfigMapRevisioner struct {
+	client  client.Reader
+	wrapped Revisioner
+}
+
+// NewConfigMapRevisioner returns a ConfigMapRevisioner that reads ConfigMaps
+// using the supplied client.
+func NewConfigMapRevisioner(c client.Reader, wrapped Revisioner) *ConfigMapRevisioner {
+	return &ConfigMapRevisioner{client: c, wrapped: wrapped}
+}
+
+// Revision extracts a revision name for a package source.
+func (r *ConfigMapRevisioner) Revision(ctx context.Context, p v1.Package) (string, error) {
+	if !xpkg.IsConfigMapSource(p.GetSource()) {
+		return r.wrapped.Revision(ctx, p)
+	}
+	b, err := xpkg.ReadConfigMapPackage(ctx, r.client, p.GetSource())
+	if err != nil {
+		return "", errors.Wrap(err, errReadConfigMapPackage)
+	}
+	d := sha256.Sum256(b)
+	return xpkg.FriendlyID(p.GetName(), hex.EncodeToString(d[:])), nil
+}
+
 // NopRevisioner returns an empty revision name.
 type NopRevisioner struct{}
 
@@ -18,11 +18,15 @@ package manager
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
@@ -36,6 +40,31 @@ func TestPackageRevisioner(t *testing.T) {
 	errBoom := errors.New("boom")
 	pullNever := corev1.PullNever
 	pullIfNotPresent := corev1.PullIfNotPresent
+	updateOnce := v1.UpdateOnce
+	updateContinuous := v1.UpdateContinuous
+
+	// The tag the package's source refers to has moved to this digest since
+	// the package's current revision was created.
+	moved := &ociv1.Descriptor{Digest: ociv1.Hash{Algorithm: "sha256", Hex: "moved"}}
+	movingTag := func(u *v1.UpdatePolicy, source string) *v1.Provider {
+		return &v1.Provider{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "provider-aws",
+			},
+			Spec: v1.ProviderSpec{
+				PackageSpec: v1.PackageSpec{
+					Package:      source,
+					UpdatePolicy: u,
+				},
+			},
+			Status: v1.ProviderStatus{
+				PackageStatus: v1.PackageStatus{
+					CurrentRevision:   "provider-aws-original",
+					CurrentIdentifier: "crossplane/provider-aws:latest",
+				},
+			},
+		}
+	}
 
 	type args struct {
 		f   xpkg.Fetcher
@@ -96,6 +125,46 @@ func TestPackageRevisioner(t *testing.T) {
 				digest: "return-me",
 			},
 		},
+		"UpdateContinuousMovingTag": {
+			reason: "Should return a new revision if the tag moved and the update policy is Continuous.",
+			args: args{
+				f:   &fake.MockFetcher{MockHead: fake.NewMockHeadFn(moved, nil)},
+				pkg: movingTag(&updateContinuous, "crossplane/provider-aws:latest"),
+			},
+			want: want{
+				digest: xpkg.FriendlyID("provider-aws", "moved"),
+			},
+		},
+		"UpdateDefaultMovingTag": {
+			reason: "Should return a new revision if the tag moved and there is no update policy.",
+			args: args{
+				f:   &fake.MockFetcher{MockHead: fake.NewMockHeadFn(moved, nil)},
+				pkg: movingTag(nil, "crossplane/provider-aws:latest"),
+			},
+			want: want{
+				digest: xpkg.FriendlyID("provider-aws", "moved"),
+			},
+		},
+		"UpdateOnceMovingTag": {
+			reason: "Should return the existing revision if the tag moved but the update policy is Once.",
+			args: args{
+				f:   &fake.MockFetcher{MockHead: fake.NewMockHeadFn(moved, nil)},
+				pkg: movingTag(&updateOnce, "crossplane/provider-aws:latest"),
+			},
+			want: want{
+				digest: "provider-aws-original",
+			},
+		},
+		"UpdateOnceSourceChanged": {
+			reason: "Should resolve the source again if it changed and the update policy is Once.",
+			args: args{
+				f:   &fake.MockFetcher{MockHead: fake.NewMockHeadFn(moved, nil)},
+				pkg: movingTag(&updateOnce, "crossplane/provider-aws:v1.0.0"),
+			},
+			want: want{
+				digest: xpkg.FriendlyID("provider-aws", "moved"),
+			},
+		},
 		"ErrParseRef": {
 			reason: "Should return an error if we cannot parse reference from package source image.",
 			args: args{
@@ -145,3 +214,95 @@ func TestPackageRevisioner(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigMapRevisioner(t *testing.T) {
+	errBoom := errors.New("boom")
+	pkg := []byte("cool-package")
+	d := sha256.Sum256(pkg)
+
+	type args struct {
+		client  client.Reader
+		wrapped Revisioner
+		pkg     v1.Package
+	}
+
+	type want struct {
+		err    error
+		digest string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotAConfigMapSource": {
+			reason: "Should pass packages that aren't read from a ConfigMap to the wrapped revisioner.",
+			args: args{
+				wrapped: &MockRevisioner{MockRevision: NewMockRevisionFn("wrapped", nil)},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "test/test:test",
+						},
+					},
+				},
+			},
+			want: want{
+				digest: "wrapped",
+			},
+		},
+		"ErrReadConfigMap": {
+			reason: "Should return an error if we fail to read the package from its ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				pkg: &v1.Provider{
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://default/cool",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get package ConfigMap"), errReadConfigMapPackage),
+			},
+		},
+		"Successful": {
+			reason: "Should return a friendly identifier derived from the digest of the package in the ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					obj.(*corev1.ConfigMap).BinaryData = map[string][]byte{xpkg.ConfigMapPackageKey: pkg}
+					return nil
+				})},
+				pkg: &v1.Provider{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "provider-cool",
+					},
+					Spec: v1.ProviderSpec{
+						PackageSpec: v1.PackageSpec{
+							Package: "configmap://default/cool",
+						},
+					},
+				},
+			},
+			want: want{
+				digest: xpkg.FriendlyID("provider-cool", hex.EncodeToString(d[:])),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewConfigMapRevisioner(tc.args.client, tc.args.wrapped)
+			h, err := r.Revision(context.TODO(), tc.args.pkg)
+
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Revision(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.digest, h); diff != "" {
+				t.Errorf("\n%s\nr.Revision(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
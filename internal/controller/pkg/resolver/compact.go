@@ -0,0 +1,118 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+const (
+	errGetRevision = "cannot get package revision"
+	errUpdateLock  = "cannot update package lock"
+)
+
+var lockEntriesPruned = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "crossplane_package_lock_entries_pruned_total",
+	Help: "The number of stale entries pruned from the package lock.",
+})
+
+func init() {
+	metrics.Registry.MustRegister(lockEntriesPruned)
+}
+
+// A Compactor removes stale entries from the Lock.
+type Compactor interface {
+	// Compact the supplied Lock, returning how many entries were pruned.
+	Compact(ctx context.Context, l *v1beta1.Lock) (int, error)
+}
+
+// A CompactorFn removes stale entries from the Lock.
+type CompactorFn func(ctx context.Context, l *v1beta1.Lock) (int, error)
+
+// Compact the supplied Lock, returning how many entries were pruned.
+func (fn CompactorFn) Compact(ctx context.Context, l *v1beta1.Lock) (int, error) {
+	return fn(ctx, l)
+}
+
+// An APICompactor removes Lock entries that have no corresponding package
+// revision from the Lock.
+type APICompactor struct {
+	client client.Client
+	reader client.Reader
+}
+
+// NewAPICompactor returns a Compactor that reads package revisions using the
+// supplied reader, and updates the Lock using the supplied client. The reader
+// should not be backed by a cache; a cache may not yet know about a package
+// revision that has just added itself to the Lock.
+func NewAPICompactor(c client.Client, r client.Reader) *APICompactor {
+	return &APICompactor{client: c, reader: r}
+}
+
+// Compact the supplied Lock, returning how many entries were pruned.
+func (c *APICompactor) Compact(ctx context.Context, l *v1beta1.Lock) (int, error) {
+	keep := make([]v1beta1.LockPackage, 0, len(l.Packages))
+	for _, p := range l.Packages {
+		var pr client.Object
+		switch p.Type {
+		case v1beta1.ProviderPackageType:
+			pr = &v1.ProviderRevision{}
+		case v1beta1.ConfigurationPackageType:
+			pr = &v1.ConfigurationRevision{}
+		default:
+			// We don't know how to find the revision for this
+			// entry, so we can't tell whether it's stale.
+			keep = append(keep, p)
+			continue
+		}
+
+		err := c.reader.Get(ctx, types.NamespacedName{Name: p.Name}, pr)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return 0, errors.Wrap(err, errGetRevision)
+		}
+		keep = append(keep, p)
+	}
+
+	pruned := len(l.Packages) - len(keep)
+	if pruned == 0 {
+		return 0, nil
+	}
+
+	// Package revisions add themselves to and remove themselves from the Lock
+	// concurrently. Our update will fail if the Lock changed since we read
+	// it, so we can't undo their changes. We'll compact again later.
+	l.Packages = keep
+	if err := c.client.Update(ctx, l); err != nil {
+		return 0, errors.Wrap(err, errUpdateLock)
+	}
+	lockEntriesPruned.Add(float64(pruned))
+	return pruned, nil
+}
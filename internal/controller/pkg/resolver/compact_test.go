@@ -0,0 +1,148 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resolver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+var (
+	_ Compactor = CompactorFn(nil)
+	_ Compactor = &APICompactor{}
+)
+
+func TestAPICompactorCompact(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	provider := v1beta1.LockPackage{Name: "cool-provider", Type: v1beta1.ProviderPackageType}
+	config := v1beta1.LockPackage{Name: "cool-config", Type: v1beta1.ConfigurationPackageType}
+	unknown := v1beta1.LockPackage{Name: "cool-unknown", Type: v1beta1.PackageType("Unknown")}
+
+	// notFound returns NotFound for the named revision, and nil otherwise.
+	notFound := func(name string) test.MockGetFn {
+		return func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+			if key.Name == name {
+				return kerrors.NewNotFound(schema.GroupResource{}, name)
+			}
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Client
+		reader client.Reader
+		lock   *v1beta1.Lock
+	}
+	type want struct {
+		pruned   int
+		packages []v1beta1.LockPackage
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NothingToPrune": {
+			reason: "We should not update the Lock if every entry has a revision.",
+			args: args{
+				// Calling Update would panic.
+				client: &test.MockClient{},
+				reader: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+				lock:   &v1beta1.Lock{Packages: []v1beta1.LockPackage{provider, config}},
+			},
+			want: want{
+				packages: []v1beta1.LockPackage{provider, config},
+			},
+		},
+		"PruneStaleEntries": {
+			reason: "We should remove entries whose revision no longer exists.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(nil)},
+				reader: &test.MockClient{MockGet: notFound(provider.Name)},
+				lock:   &v1beta1.Lock{Packages: []v1beta1.LockPackage{provider, config}},
+			},
+			want: want{
+				pruned:   1,
+				packages: []v1beta1.LockPackage{config},
+			},
+		},
+		"KeepUnknownTypes": {
+			reason: "We should keep entries whose revision type we don't know how to look up.",
+			args: args{
+				client: &test.MockClient{},
+				reader: &test.MockClient{},
+				lock:   &v1beta1.Lock{Packages: []v1beta1.LockPackage{unknown}},
+			},
+			want: want{
+				packages: []v1beta1.LockPackage{unknown},
+			},
+		},
+		"GetRevisionError": {
+			reason: "We should return any error other than NotFound encountered getting a revision.",
+			args: args{
+				client: &test.MockClient{},
+				reader: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				lock:   &v1beta1.Lock{Packages: []v1beta1.LockPackage{provider}},
+			},
+			want: want{
+				packages: []v1beta1.LockPackage{provider},
+				err:      errors.Wrap(errBoom, errGetRevision),
+			},
+		},
+		"UpdateLockError": {
+			reason: "We should return any error encountered updating the Lock.",
+			args: args{
+				client: &test.MockClient{MockUpdate: test.NewMockUpdateFn(errBoom)},
+				reader: &test.MockClient{MockGet: notFound(config.Name)},
+				lock:   &v1beta1.Lock{Packages: []v1beta1.LockPackage{provider, config}},
+			},
+			want: want{
+				packages: []v1beta1.LockPackage{provider},
+				err:      errors.Wrap(errBoom, errUpdateLock),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := NewAPICompactor(tc.args.client, tc.args.reader)
+			pruned, err := c.Compact(context.Background(), tc.args.lock)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCompact(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pruned, pruned); diff != "" {
+				t.Errorf("\n%s\nCompact(...): -want pruned, +got pruned:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.packages, tc.args.lock.Packages); diff != "" {
+				t.Errorf("\n%s\nCompact(...): -want packages, +got packages:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -66,6 +66,7 @@ const (
 	errFmtNoValidVersion    = "dependency (%s) does not have version in constraints (%s)"
 	errInvalidPackageType   = "cannot create invalid package dependency type"
 	errCreateDependency     = "cannot create dependency package"
+	errCompactLock          = "cannot compact package lock"
 )
 
 // ReconcilerOption is used to configure the Reconciler.
@@ -99,6 +100,16 @@ func WithFetcher(f xpkg.Fetcher) ReconcilerOption {
 	}
 }
 
+// WithCompactor specifies how the Reconciler should remove stale entries from
+// the Lock, and how often it should do so. The Lock is not compacted unless
+// this option is supplied.
+func WithCompactor(c Compactor, interval time.Duration) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.compactor = c
+		r.compactInterval = interval
+	}
+}
+
 // Reconciler reconciles packages.
 type Reconciler struct {
 	client  client.Client
@@ -106,6 +117,10 @@ type Reconciler struct {
 	lock    resource.Finalizer
 	newDag  dag.NewDAGFn
 	fetcher xpkg.Fetcher
+
+	compactor       Compactor
+	compactInterval time.Duration
+	lastCompacted   time.Time
 }
 
 // Setup adds a controller that reconciles the Lock.
@@ -121,10 +136,15 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, "cannot build fetcher")
 	}
 
-	r := NewReconciler(mgr,
+	opts := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithFetcher(f),
-	)
+	}
+	if o.LockCompactionInterval > 0 {
+		opts = append(opts, WithCompactor(NewAPICompactor(mgr.GetClient(), mgr.GetAPIReader()), o.LockCompactionInterval))
+	}
+
+	r := NewReconciler(mgr, opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
@@ -152,8 +172,22 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 	return r
 }
 
-// Reconcile package revision.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo // Reconcilers are complex. Be wary of adding more.
+// Reconcile the package lock.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.resolve(ctx, req)
+	if err != nil || r.compactor == nil {
+		return result, err
+	}
+
+	// Make sure we're requeued to compact the Lock again, even if nothing
+	// changes in the meantime.
+	if !result.Requeue && result.RequeueAfter == 0 {
+		result.RequeueAfter = r.compactInterval
+	}
+	return result, nil
+}
+
+func (r *Reconciler) resolve(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo // Reconcilers are complex. Be wary of adding more.
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
@@ -191,6 +225,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		"name", lock.GetName(),
 	)
 
+	// Remove stale entries from the Lock, if it's time to do so. This is the
+	// only time the resolver modifies the Lock.
+	if r.compactor != nil && time.Since(r.lastCompacted) >= r.compactInterval {
+		pruned, err := r.compactor.Compact(ctx, lock)
+		if err != nil {
+			log.Debug(errCompactLock, "error", err)
+			return reconcile.Result{}, errors.Wrap(err, errCompactLock)
+		}
+		r.lastCompacted = time.Now()
+		if pruned > 0 {
+			log.Debug("Pruned stale entries from package lock", "pruned", pruned)
+		}
+	}
+
 	dag := r.newDag()
 	implied, err := dag.Init(v1beta1.ToNodes(lock.Packages...))
 	if err != nil {
@@ -211,7 +259,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	}
 
 	// If we are missing a node, we want to create it. The resolver never
-	// modifies the Lock. We only create the first implied node as we will
+	// modifies the Lock, except to compact it. We only create the first implied node as we will
 	// be requeued when it adds itself to the Lock, at which point we will
 	// check for missing nodes again.
 	dep, ok := implied[0].(*v1beta1.Dependency)
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errReadConfigMapPackage = "cannot read package from ConfigMap"
+	errLoadConfigMapPackage = "cannot load package image from ConfigMap"
+)
+
+// ConfigMapBackend is a backend for parser that reads packages whose source
+// refers to a ConfigMap, per xpkg.ConfigMapSourcePrefix. It passes packages
+// with any other source to its wrapped backend.
+type ConfigMapBackend struct {
+	client  client.Reader
+	wrapped parser.Backend
+}
+
+// NewConfigMapBackend returns a backend that reads packages from ConfigMaps
+// using the supplied client, and all other packages using the supplied
+// backend.
+func NewConfigMapBackend(c client.Reader, wrapped parser.Backend) *ConfigMapBackend {
+	return &ConfigMapBackend{client: c, wrapped: wrapped}
+}
+
+// Init initializes a ConfigMapBackend.
+func (b *ConfigMapBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) {
+	n := &nestedBackend{}
+	for _, o := range bo {
+		o(n)
+	}
+	if n.pr == nil || !xpkg.IsConfigMapSource(n.pr.GetSource()) {
+		return b.wrapped.Init(ctx, bo...)
+	}
+
+	pkg, err := xpkg.ReadConfigMapPackage(ctx, b.client, n.pr.GetSource())
+	if err != nil {
+		return nil, errors.Wrap(err, errReadConfigMapPackage)
+	}
+
+	// A compiled package is an image tarball.
+	img, err := tarball.Image(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(pkg)), nil
+	}, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errLoadConfigMapPackage)
+	}
+	return readPackageStream(img, n.progress)
+}
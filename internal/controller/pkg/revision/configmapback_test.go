@@ -0,0 +1,146 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+type MockBackend struct {
+	MockInit func(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error)
+}
+
+func (m *MockBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) {
+	return m.MockInit(ctx, bo...)
+}
+
+func TestConfigMapBackend(t *testing.T) {
+	errBoom := errors.New("boom")
+	stream := "somestreamofyaml"
+
+	// Build a compiled package, i.e. an image tarball with an annotated base
+	// layer that contains the package's YAML stream.
+	layer := &bytes.Buffer{}
+	tw := tar.NewWriter(layer)
+	_ = tw.WriteHeader(&tar.Header{Name: xpkg.StreamFile, Mode: int64(xpkg.StreamFileMode), Size: int64(len(stream))})
+	_, _ = io.WriteString(tw, stream)
+	_ = tw.Close()
+	l, _ := tarball.LayerFromReader(bytes.NewReader(layer.Bytes()))
+	img, _ := mutate.Append(empty.Image, mutate.Addendum{
+		Layer:       l,
+		Annotations: map[string]string{layerAnnotation: baseAnnotationValue},
+	})
+	pkg := &bytes.Buffer{}
+	_ = tarball.Write(name.MustParseReference("example.org/cool:v1"), img, pkg)
+
+	withPackage := test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.ConfigMap).BinaryData = map[string][]byte{xpkg.ConfigMapPackageKey: pkg.Bytes()}
+		return nil
+	})
+
+	type args struct {
+		client  client.Reader
+		wrapped parser.Backend
+		opts    []parser.BackendOption
+	}
+	type want struct {
+		stream string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotAConfigMapSource": {
+			reason: "We should pass packages that aren't read from a ConfigMap to the wrapped backend.",
+			args: args{
+				wrapped: &MockBackend{MockInit: func(_ context.Context, _ ...parser.BackendOption) (io.ReadCloser, error) {
+					return io.NopCloser(bytes.NewReader([]byte("wrapped"))), nil
+				}},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{Package: "example.org/cool:v1"},
+				})},
+			},
+			want: want{
+				stream: "wrapped",
+			},
+		},
+		"ReadError": {
+			reason: "We should return an error if we can't read the package from its ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{Package: "configmap://default/cool"},
+				})},
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, "cannot get package ConfigMap"), errReadConfigMapPackage),
+			},
+		},
+		"Success": {
+			reason: "We should return the YAML stream of the package in the ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: withPackage},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{Package: "configmap://default/cool"},
+				})},
+			},
+			want: want{
+				stream: stream,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := NewConfigMapBackend(tc.args.client, tc.args.wrapped)
+			rc, err := b.Init(context.Background(), tc.args.opts...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if rc == nil {
+				return
+			}
+			got, _ := io.ReadAll(rc)
+			_ = rc.Close()
+			if diff := cmp.Diff(tc.want.stream, string(got)); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want stream, +got stream:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
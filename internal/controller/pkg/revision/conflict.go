@@ -0,0 +1,128 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errFmtObjectConflicts = "package declares objects that are also declared by active revisions of other packages (set the %s annotation on the package to allow this): %s"
+)
+
+// ConflictingObjects returns a sorted description of each of the supplied
+// objects of the supplied revision that is also one of the objects of an
+// active revision of another package, e.g.
+// "CustomResourceDefinition.apiextensions.k8s.io buckets.example.org". Two
+// objects conflict if they have the same group, kind, and name.
+func ConflictingObjects(pr v1.PackageRevision, objs []runtime.Object, others []v1.PackageRevision) []string {
+	declared := make(map[string]bool)
+	parent := pr.GetLabels()[v1.LabelParentPackage]
+	for _, o := range others {
+		if o.GetUID() == pr.GetUID() || o.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		if parent != "" && o.GetLabels()[v1.LabelParentPackage] == parent {
+			continue
+		}
+		for _, ref := range o.GetObjects() {
+			declared[describeObject(ref.GroupVersionKind().GroupKind(), ref.Name)] = true
+		}
+	}
+
+	conflicts := make([]string, 0)
+	for _, obj := range objs {
+		o, ok := obj.(client.Object)
+		if !ok {
+			continue
+		}
+		k := describeObject(o.GetObjectKind().GroupVersionKind().GroupKind(), o.GetName())
+		if declared[k] {
+			conflicts = append(conflicts, k)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+func describeObject(gk schema.GroupKind, name string) string {
+	return gk.String() + " " + name
+}
+
+// A ConflictChecker checks whether the objects of a package revision conflict
+// with those of other packages.
+type ConflictChecker interface {
+	// CheckConflicts returns an error if any of the supplied objects of the
+	// supplied package revision conflict with those of other packages.
+	CheckConflicts(ctx context.Context, pr v1.PackageRevision, objs []runtime.Object) error
+}
+
+// NopConflictChecker does not check for conflicts.
+type NopConflictChecker struct{}
+
+// CheckConflicts does nothing.
+func (NopConflictChecker) CheckConflicts(_ context.Context, _ v1.PackageRevision, _ []runtime.Object) error {
+	return nil
+}
+
+// An APIConflictChecker checks for conflicts with the objects of the active
+// revisions of other packages.
+type APIConflictChecker struct {
+	client client.Reader
+}
+
+// NewAPIConflictChecker returns a ConflictChecker that checks for conflicts
+// with the objects of the active revisions of other packages.
+func NewAPIConflictChecker(c client.Reader) *APIConflictChecker {
+	return &APIConflictChecker{client: c}
+}
+
+// CheckConflicts returns an error listing any of the supplied objects that
+// are also declared by active revisions of other packages, unless the
+// revision's package allows conflicts.
+func (c *APIConflictChecker) CheckConflicts(ctx context.Context, pr v1.PackageRevision, objs []runtime.Object) error {
+	p, err := OwnerPackage(ctx, c.client, pr)
+	if err != nil && !IsOwnerPackageNotFound(err) {
+		return err
+	}
+	if p != nil && p.GetAnnotations()[v1.AnnotationAllowObjectConflicts] == "true" {
+		return nil
+	}
+
+	others := make([]v1.PackageRevision, 0)
+	for _, l := range []v1.PackageRevisionList{&v1.ProviderRevisionList{}, &v1.ConfigurationRevisionList{}} {
+		if err := c.client.List(ctx, l); err != nil {
+			return errors.Wrap(err, errListRevisions)
+		}
+		others = append(others, l.GetRevisions()...)
+	}
+
+	if conflicts := ConflictingObjects(pr, objs, others); len(conflicts) > 0 {
+		return errors.Errorf(errFmtObjectConflicts, v1.AnnotationAllowObjectConflicts, strings.Join(conflicts, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,176 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestConflictingObjects(t *testing.T) {
+	crd := func(name string) runtime.Object {
+		return &extv1.CustomResourceDefinition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+		}
+	}
+	rev := func(uid types.UID, parent string, state v1.PackageRevisionDesiredState, crds ...string) v1.PackageRevision {
+		pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{UID: uid, Labels: map[string]string{v1.LabelParentPackage: parent}}}
+		pr.SetDesiredState(state)
+		refs := make([]xpv1.TypedReference, 0, len(crds))
+		for _, n := range crds {
+			refs = append(refs, xpv1.TypedReference{APIVersion: "apiextensions.k8s.io/v1beta1", Kind: "CustomResourceDefinition", Name: n})
+		}
+		pr.SetObjects(refs)
+		return pr
+	}
+
+	type args struct {
+		pr     v1.PackageRevision
+		objs   []runtime.Object
+		others []v1.PackageRevision
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []string
+	}{
+		"NoConflicts": {
+			reason: "Objects that no other package declares should not conflict.",
+			args: args{
+				pr:     rev("ours", "cool", v1.PackageRevisionActive),
+				objs:   []runtime.Object{crd("coolthings.example.org")},
+				others: []v1.PackageRevision{rev("theirs", "other", v1.PackageRevisionActive, "otherthings.example.org")},
+			},
+			want: []string{},
+		},
+		"Conflicts": {
+			reason: "Objects declared by an active revision of another package should conflict, regardless of API version.",
+			args: args{
+				pr:   rev("ours", "cool", v1.PackageRevisionActive),
+				objs: []runtime.Object{crd("b.example.org"), crd("a.example.org"), crd("c.example.org")},
+				others: []v1.PackageRevision{
+					rev("theirs", "other", v1.PackageRevisionActive, "a.example.org", "b.example.org"),
+				},
+			},
+			want: []string{
+				"CustomResourceDefinition.apiextensions.k8s.io a.example.org",
+				"CustomResourceDefinition.apiextensions.k8s.io b.example.org",
+			},
+		},
+		"IgnoreInactiveAndSamePackage": {
+			reason: "Objects declared by inactive revisions, or revisions of the same package, should not conflict.",
+			args: args{
+				pr:   rev("ours", "cool", v1.PackageRevisionActive),
+				objs: []runtime.Object{crd("a.example.org")},
+				others: []v1.PackageRevision{
+					rev("ours", "cool", v1.PackageRevisionActive, "a.example.org"),
+					rev("older", "cool", v1.PackageRevisionActive, "a.example.org"),
+					rev("theirs", "other", v1.PackageRevisionInactive, "a.example.org"),
+				},
+			},
+			want: []string{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ConflictingObjects(tc.args.pr, tc.args.objs, tc.args.others)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nConflictingObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIConflictCheckerCheckConflicts(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{UID: "ours", Labels: map[string]string{v1.LabelParentPackage: "cool"}}}
+	pr.SetDesiredState(v1.PackageRevisionActive)
+	objs := []runtime.Object{&extv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "a.example.org"},
+	}}
+	theirs := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{UID: "theirs", Labels: map[string]string{v1.LabelParentPackage: "other"}}}
+	theirs.SetDesiredState(v1.PackageRevisionActive)
+	theirs.SetObjects([]xpv1.TypedReference{{APIVersion: extv1.SchemeGroupVersion.String(), Kind: "CustomResourceDefinition", Name: "a.example.org"}})
+
+	list := test.NewMockListFn(nil, func(o client.ObjectList) error {
+		if l, ok := o.(*v1.ProviderRevisionList); ok {
+			l.Items = []v1.ProviderRevision{theirs}
+		}
+		return nil
+	})
+
+	cases := map[string]struct {
+		reason string
+		c      client.Reader
+		want   error
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing package revisions.",
+			c: &test.MockClient{
+				MockGet:  test.NewMockGetFn(nil),
+				MockList: test.NewMockListFn(errBoom),
+			},
+			want: errors.Wrap(errBoom, errListRevisions),
+		},
+		"Conflict": {
+			reason: "We should return an error listing conflicting objects.",
+			c: &test.MockClient{
+				MockGet:  test.NewMockGetFn(nil),
+				MockList: list,
+			},
+			want: errors.Errorf(errFmtObjectConflicts, v1.AnnotationAllowObjectConflicts, "CustomResourceDefinition.apiextensions.k8s.io a.example.org"),
+		},
+		"ConflictsAllowed": {
+			reason: "We should not return an error if the package allows conflicts.",
+			c: &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+					o.SetAnnotations(map[string]string{v1.AnnotationAllowObjectConflicts: "true"})
+					return nil
+				}),
+				MockList: list,
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := NewAPIConflictChecker(tc.c).CheckConflicts(context.Background(), pr, objs)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nCheckConflicts(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,6 +18,8 @@ package revision
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
@@ -38,6 +40,10 @@ import (
 const (
 	lockName = "lock"
 
+	// maxDescribedDependencies is the maximum number of resolved dependencies
+	// described by the event emitted when dependencies are resolved.
+	maxDescribedDependencies = 3
+
 	errNotMeta                   = "meta type is not a valid package"
 	errGetOrCreateLock           = "cannot get or create lock"
 	errInitDAG                   = "cannot initialize dependency graph from the packages in the lock"
@@ -109,9 +115,18 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.Wrap(err, errGetOrCreateLock)
 	}
 
-	prRef, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
-	if err != nil {
-		return found, installed, invalid, err
+	// Packages read from a ConfigMap have no image reference, so they're
+	// locked by their source.
+	lockRef, version := pr.GetSource(), pr.GetInstalledVersion()
+	if !xpkg.IsConfigMapSource(pr.GetSource()) {
+		prRef, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry(""))
+		if err != nil {
+			return found, installed, invalid, err
+		}
+		lockRef = xpkg.ParsePackageSourceFromReference(prRef)
+		if version == "" {
+			version = prRef.Identifier()
+		}
 	}
 
 	d := m.newDag()
@@ -120,14 +135,19 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 		return found, installed, invalid, errors.Wrap(err, errInitDAG)
 	}
 
-	lockRef := xpkg.ParsePackageSourceFromReference(prRef)
+	// Record what each of our direct dependencies resolved to, whether or not
+	// they're all installed.
+	defer func() {
+		pr.SetResolvedDependencies(resolvedDependencies(d, sources))
+	}()
+
 	// NOTE(hasheddan): consider adding health of package to lock so that it can
 	// be rolled up to any dependent packages.
 	self := v1beta1.LockPackage{
 		Name:         pr.GetName(),
 		Type:         m.packageType,
 		Source:       lockRef,
-		Version:      prRef.Identifier(),
+		Version:      version,
 		Dependencies: sources,
 	}
 
@@ -213,6 +233,41 @@ func (m *PackageDependencyManager) Resolve(ctx context.Context, pkg runtime.Obje
 	return found, installed, invalid, nil
 }
 
+// resolvedDependencies returns the version of the package in the supplied
+// dependency graph that each of the supplied dependencies resolved to.
+func resolvedDependencies(d dag.DAG, deps []v1beta1.Dependency) []v1.ResolvedDependency {
+	resolved := make([]v1.ResolvedDependency, len(deps))
+	for i, dep := range deps {
+		resolved[i] = v1.ResolvedDependency{Package: dep.Package, Constraint: dep.Constraints}
+		n, err := d.GetNode(dep.Identifier())
+		if err != nil {
+			continue
+		}
+		if lp, ok := n.(*v1beta1.LockPackage); ok {
+			resolved[i].Resolved = lp.Version
+		}
+	}
+	return resolved
+}
+
+// describeResolvedDependencies returns a human readable description of the
+// first few of the supplied resolved dependencies.
+func describeResolvedDependencies(deps []v1.ResolvedDependency) string {
+	described := make([]string, 0, maxDescribedDependencies)
+	for i, dep := range deps {
+		if i == maxDescribedDependencies {
+			described = append(described, fmt.Sprintf("and %d more", len(deps)-i))
+			break
+		}
+		if dep.Resolved == "" {
+			described = append(described, fmt.Sprintf("%s (unresolved)", dep.Package))
+			continue
+		}
+		described = append(described, fmt.Sprintf("%s@%s", dep.Package, dep.Resolved))
+	}
+	return strings.Join(described, ", ")
+}
+
 // RemoveSelf removes a package from the lock.
 func (m *PackageDependencyManager) RemoveSelf(ctx context.Context, pr v1.PackageRevision) error {
 	// Get the lock.
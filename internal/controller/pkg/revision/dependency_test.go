@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -54,6 +55,7 @@ func TestResolve(t *testing.T) {
 		total     int
 		installed int
 		invalid   int
+		resolved  []v1.ResolvedDependency
 	}
 
 	cases := map[string]struct {
@@ -302,6 +304,51 @@ func TestResolve(t *testing.T) {
 			},
 			want: want{},
 		},
+		"SuccessfulConfigMapSourceNoDependencies": {
+			reason: "Should lock a package read from a ConfigMap by its source.",
+			args: args{
+				dep: &PackageDependencyManager{
+					client: &test.MockClient{
+						MockGet: test.NewMockGetFn(nil),
+						MockUpdate: test.NewMockUpdateFn(nil, func(obj client.Object) error {
+							want := []v1beta1.LockPackage{{
+								Name:   "config-nop-a-abc123",
+								Source: "configmap://default/config-nop-a",
+							}}
+							if diff := cmp.Diff(want, obj.(*v1beta1.Lock).Packages, cmpopts.EquateEmpty()); diff != "" {
+								t.Errorf("Update(...): -want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					},
+					newDag: func() dag.DAG {
+						return &dagfake.MockDag{
+							MockInit: func(nodes []dag.Node) ([]dag.Node, error) {
+								return nil, nil
+							},
+							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+							MockTraceNode: func(s string) (map[string]dag.Node, error) {
+								if s != "configmap://default/config-nop-a" {
+									return nil, errBoom
+								}
+								return nil, nil
+							},
+						}
+					},
+				},
+				meta: &pkgmetav1.Configuration{},
+				pr: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "config-nop-a-abc123",
+					},
+					Spec: v1.PackageRevisionSpec{
+						Package:      "configmap://default/config-nop-a",
+						DesiredState: v1.PackageRevisionActive,
+					},
+				},
+			},
+			want: want{},
+		},
 		"ErrorSelfNotExistMissingDirectDependencies": {
 			reason: "Should return error if self does not exist and missing direct dependencies.",
 			args: args{
@@ -324,6 +371,9 @@ func TestResolve(t *testing.T) {
 								return nil
 							},
 							MockAddOrUpdateNodes: func(_ ...dag.Node) {},
+							MockGetNode: func(s string) (dag.Node, error) {
+								return nil, errBoom
+							},
 						}
 					},
 				},
@@ -354,6 +404,10 @@ func TestResolve(t *testing.T) {
 			want: want{
 				total: 2,
 				err:   errors.Errorf(errFmtMissingDependencies, []string{"not-here-1", "not-here-2"}),
+				resolved: []v1.ResolvedDependency{
+					{Package: "not-here-1"},
+					{Package: "not-here-2"},
+				},
 			},
 		},
 		"ErrorSelfExistMissingDependencies": {
@@ -411,6 +465,15 @@ func TestResolve(t *testing.T) {
 									"not-here-3": &v1beta1.Dependency{},
 								}, nil
 							},
+							MockGetNode: func(s string) (dag.Node, error) {
+								if s == "not-here-1" {
+									return &v1beta1.LockPackage{
+										Source:  "not-here-1",
+										Version: "v0.0.1",
+									}, nil
+								}
+								return &v1beta1.Dependency{Package: s}, nil
+							},
 						}
 					},
 				},
@@ -442,6 +505,10 @@ func TestResolve(t *testing.T) {
 				total:     3,
 				installed: 1,
 				err:       errors.Errorf(errFmtMissingDependencies, []string{"not-here-2", "not-here-3"}),
+				resolved: []v1.ResolvedDependency{
+					{Package: "not-here-1", Resolved: "v0.0.1"},
+					{Package: "not-here-2"},
+				},
 			},
 		},
 		"ErrorSelfExistInvalidDependencies": {
@@ -541,6 +608,10 @@ func TestResolve(t *testing.T) {
 				installed: 3,
 				invalid:   2,
 				err:       errors.Errorf(errFmtIncompatibleDependency, []string{"not-here-1", "not-here-2"}),
+				resolved: []v1.ResolvedDependency{
+					{Package: "not-here-1", Constraint: ">=v0.1.0", Resolved: "v0.0.1"},
+					{Package: "not-here-2", Constraint: ">=v0.1.0", Resolved: "v0.0.1"},
+				},
 			},
 		},
 		"SuccessfulSelfExistValidDependencies": {
@@ -642,6 +713,10 @@ func TestResolve(t *testing.T) {
 				total:     3,
 				installed: 3,
 				invalid:   0,
+				resolved: []v1.ResolvedDependency{
+					{Package: "not-here-1", Constraint: ">=v0.1.0", Resolved: "v0.20.0"},
+					{Package: "not-here-2", Constraint: ">=v0.1.0", Resolved: "v0.100.1"},
+				},
 			},
 		},
 	}
@@ -662,6 +737,46 @@ func TestResolve(t *testing.T) {
 			if diff := cmp.Diff(tc.want.invalid, invalid); diff != "" {
 				t.Errorf("\n%s\nInvalid(...): -want, +got:\n%s", tc.reason, diff)
 			}
+			if diff := cmp.Diff(tc.want.resolved, tc.args.pr.GetResolvedDependencies(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nGetResolvedDependencies(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDescribeResolvedDependencies(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		deps   []v1.ResolvedDependency
+		want   string
+	}{
+		"FewDependencies": {
+			reason: "All dependencies should be described if there are only a few.",
+			deps: []v1.ResolvedDependency{
+				{Package: "example.org/a", Resolved: "v1.0.0"},
+				{Package: "example.org/b"},
+			},
+			want: "example.org/a@v1.0.0, example.org/b (unresolved)",
+		},
+		"ManyDependencies": {
+			reason: "Only the first few dependencies should be described.",
+			deps: []v1.ResolvedDependency{
+				{Package: "example.org/a", Resolved: "v1.0.0"},
+				{Package: "example.org/b", Resolved: "v2.0.0"},
+				{Package: "example.org/c", Resolved: "v3.0.0"},
+				{Package: "example.org/d", Resolved: "v4.0.0"},
+				{Package: "example.org/e", Resolved: "v5.0.0"},
+			},
+			want: "example.org/a@v1.0.0, example.org/b@v2.0.0, example.org/c@v3.0.0, and 2 more",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := describeResolvedDependencies(tc.deps)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ndescribeResolvedDependencies(...): -want, +got:\n%s", tc.reason, diff)
+			}
 		})
 	}
 }
@@ -17,11 +17,18 @@ limitations under the License.
 package revision
 
 import (
+	"strconv"
+	"strings"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
 
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
@@ -30,6 +37,15 @@ import (
 	"github.com/crossplane/crossplane/internal/initializer"
 )
 
+// Error strings.
+const (
+	errFmtNegativeMaxUnavailable = "maxUnavailable must not be negative, got %d"
+	errFmtInvalidMaxUnavailable  = "maxUnavailable must be an integer or a percentage, got %q"
+	errFmtInvalidHealthCheckPort = "healthCheck port must be between 1 and 65535, got %d"
+	errFmtInvalidHealthCheckPath = "healthCheck path must be an absolute path, got %q"
+	errFmtInvalidNodeSelectorKey = "nodeSelector key %q is not a valid label key: %s"
+)
+
 var (
 	replicas                 = int32(1)
 	runAsUser                = int64(2000)
@@ -62,6 +78,12 @@ const (
 	tlsClientCertDirEnvVar   = "TLS_CLIENT_CERTS_DIR"
 	tlsClientCertsVolumeName = "tls-client-certs"
 	tlsClientCertsDir        = "/tls/client"
+
+	tlsVerificationCertDirEnvVar   = "TLS_VERIFICATION_CERTS_DIR"
+	tlsVerificationCertsVolumeName = "tls-verification-certs"
+	tlsVerificationCertsDir        = "/tls/verification"
+
+	leaderElectionEnvVar = "LEADER_ELECTION"
 )
 
 // Returns the service account, deployment, service, server and client TLS secrets of the provider.
@@ -226,6 +248,36 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 			append(d.Spec.Template.Spec.Containers[0].Env, envs...)
 	}
 
+	if ref := revision.GetVerificationCertRef(); ref != nil {
+		v := corev1.Volume{
+			Name: tlsVerificationCertsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: ref.Name,
+					Items: []corev1.KeyToPath{
+						// Only the certificate is mounted, never its key.
+						{Key: corev1.TLSCertKey, Path: initializer.SecretKeyCACert},
+					},
+				},
+			},
+		}
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, v)
+
+		vm := corev1.VolumeMount{
+			Name:      tlsVerificationCertsVolumeName,
+			ReadOnly:  true,
+			MountPath: tlsVerificationCertsDir,
+		}
+		d.Spec.Template.Spec.Containers[0].VolumeMounts =
+			append(d.Spec.Template.Spec.Containers[0].VolumeMounts, vm)
+
+		envs := []corev1.EnvVar{
+			{Name: tlsVerificationCertDirEnvVar, Value: tlsVerificationCertsDir},
+		}
+		d.Spec.Template.Spec.Containers[0].Env =
+			append(d.Spec.Template.Spec.Containers[0].Env, envs...)
+	}
+
 	if revision.GetWebhookTLSSecretName() != nil {
 		v := corev1.Volume{
 			Name: webhookVolumeName,
@@ -296,6 +348,50 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 			append(d.Spec.Template.Spec.Containers[0].Env, envs...)
 	}
 
+	if mu := revision.GetMaxUnavailable(); mu != nil {
+		d.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: mu},
+		}
+	}
+
+	if mrs := revision.GetMinReadySeconds(); mrs != nil {
+		d.Spec.MinReadySeconds = *mrs
+	}
+
+	if rc := revision.GetReplicaCount(); rc != nil {
+		n := *rc
+		d.Spec.Replicas = &n
+	}
+
+	if le := revision.GetLeaderElection(); le != nil && *le {
+		d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{Name: leaderElectionEnvVar, Value: "true"})
+	}
+
+	if vs := revision.GetExtraVolumes(); len(vs) > 0 {
+		d.Spec.Template.Spec.Volumes = append(d.Spec.Template.Spec.Volumes, vs...)
+	}
+
+	if vms := revision.GetExtraVolumeMounts(); len(vms) > 0 {
+		d.Spec.Template.Spec.Containers[0].VolumeMounts =
+			append(d.Spec.Template.Spec.Containers[0].VolumeMounts, vms...)
+	}
+
+	if hc := revision.GetHealthCheck(); hc != nil {
+		probe := &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: hc.Path, Port: intstr.FromInt(int(hc.Port))},
+			},
+		}
+		d.Spec.Template.Spec.Containers[0].ReadinessProbe = probe
+		d.Spec.Template.Spec.Containers[0].LivenessProbe = probe.DeepCopy()
+	}
+
+	if pcn := revision.GetPriorityClassName(); pcn != nil {
+		d.Spec.Template.Spec.PriorityClassName = *pcn
+	}
+
 	templateLabels := make(map[string]string)
 	if cc != nil {
 		s.Labels = cc.Labels
@@ -380,6 +476,48 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 				append(d.Spec.Template.Spec.Containers[0].VolumeMounts, cc.Spec.VolumeMounts...)
 		}
 	}
+
+	// Like the rest of a ControllerConfig, any pod template annotations it
+	// sets take precedence over those of the revision.
+	if a := revision.GetPodTemplateAnnotations(); len(a) > 0 {
+		annotations := make(map[string]string, len(a)+len(d.Spec.Template.Annotations))
+		for k, v := range a {
+			annotations[k] = v
+		}
+		for k, v := range d.Spec.Template.Annotations {
+			annotations[k] = v
+		}
+		d.Spec.Template.Annotations = annotations
+	}
+
+	// Like the rest of a ControllerConfig, an affinity it sets takes
+	// precedence over that of the revision.
+	if a := revision.GetAffinity(); a != nil && d.Spec.Template.Spec.Affinity == nil {
+		d.Spec.Template.Spec.Affinity = a
+	}
+
+	// Tolerations only ever allow pods to be scheduled on more nodes, so we
+	// merge those of the revision with any set by a ControllerConfig.
+	d.Spec.Template.Spec.Tolerations = mergeTolerations(d.Spec.Template.Spec.Tolerations, revision.GetTolerations())
+
+	// Unlike most of a ControllerConfig, the node selector of the revision
+	// is merged over that of a ControllerConfig so that the revision may add
+	// to or override its labels.
+	if ns := revision.GetNodeSelector(); ns != nil {
+		selector := make(map[string]string, len(ns)+len(d.Spec.Template.Spec.NodeSelector))
+		for k, v := range d.Spec.Template.Spec.NodeSelector {
+			selector[k] = v
+		}
+		for k, v := range ns {
+			selector[k] = v
+		}
+		d.Spec.Template.Spec.NodeSelector = selector
+	}
+
+	if g := revision.GetReadinessGates(); len(g) > 0 {
+		d.Spec.Template.Spec.ReadinessGates = g
+	}
+
 	for k, v := range d.Spec.Selector.MatchLabels { // ensure the template matches the selector
 		templateLabels[k] = v
 	}
@@ -406,3 +544,89 @@ func buildProviderDeployment(provider *pkgmetav1.Provider, revision v1.PackageRe
 	}
 	return s, d, svc, secSer, secCli
 }
+
+// buildProviderPodDisruptionBudget returns the PodDisruptionBudget of the
+// supplied Deployment. The budget always selects the Deployment's pods,
+// regardless of any selector configured by the revision.
+func buildProviderPodDisruptionBudget(revision v1.PackageRevision, d *appsv1.Deployment) *policyv1.PodDisruptionBudget {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            d.GetName(),
+			Namespace:       d.GetNamespace(),
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(revision, v1.ProviderRevisionGroupVersionKind))},
+		},
+	}
+	if spec := revision.GetPodDisruptionBudget(); spec != nil {
+		pdb.Spec = *spec.DeepCopy()
+	}
+	pdb.Spec.Selector = d.Spec.Selector.DeepCopy()
+	return pdb
+}
+
+// validateMaxUnavailable returns an error if the supplied maximum number of
+// unavailable pods is neither a non-negative integer nor a percentage. A nil
+// value is valid.
+func validateMaxUnavailable(mu *intstr.IntOrString) error {
+	if mu == nil {
+		return nil
+	}
+	if mu.Type == intstr.Int {
+		if mu.IntVal < 0 {
+			return errors.Errorf(errFmtNegativeMaxUnavailable, mu.IntVal)
+		}
+		return nil
+	}
+	v, err := strconv.Atoi(strings.TrimSuffix(mu.StrVal, "%"))
+	if !strings.HasSuffix(mu.StrVal, "%") || err != nil || v < 0 {
+		return errors.Errorf(errFmtInvalidMaxUnavailable, mu.StrVal)
+	}
+	return nil
+}
+
+// validateHealthCheck returns an error if the supplied health check's port is
+// not a valid port number, or its path is not absolute. A nil health check is
+// valid.
+func validateHealthCheck(hc *v1.HealthCheck) error {
+	if hc == nil {
+		return nil
+	}
+	if hc.Port < 1 || hc.Port > 65535 {
+		return errors.Errorf(errFmtInvalidHealthCheckPort, hc.Port)
+	}
+	if !strings.HasPrefix(hc.Path, "/") {
+		return errors.Errorf(errFmtInvalidHealthCheckPath, hc.Path)
+	}
+	return nil
+}
+
+// validateNodeSelector returns an error if any key of the supplied node
+// selector is not a valid label key. A nil node selector is valid.
+func validateNodeSelector(ns map[string]string) error {
+	for k := range ns {
+		if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+			return errors.Errorf(errFmtInvalidNodeSelectorKey, k, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// mergeTolerations returns the supplied existing tolerations, followed by any
+// of the supplied additional tolerations that aren't already present.
+func mergeTolerations(existing, additional []corev1.Toleration) []corev1.Toleration {
+	out := existing
+	for _, t := range additional {
+		if !hasToleration(out, t) {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func hasToleration(ts []corev1.Toleration, t corev1.Toleration) bool {
+	for _, e := range ts {
+		if e.MatchToleration(&t) && pointer.Int64Equal(e.TolerationSeconds, t.TolerationSeconds) {
+			return true
+		}
+	}
+	return false
+}
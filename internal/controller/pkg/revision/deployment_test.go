@@ -17,6 +17,7 @@ limitations under the License.
 package revision
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -25,6 +26,11 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
@@ -63,6 +69,75 @@ func withAdditionalPort(port corev1.ContainerPort) deploymentModifier {
 	}
 }
 
+func withHealthCheck(path string, port int) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		probe := &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{Path: path, Port: intstr.FromInt(port)},
+			},
+		}
+		d.Spec.Template.Spec.Containers[0].ReadinessProbe = probe
+		d.Spec.Template.Spec.Containers[0].LivenessProbe = probe
+	}
+}
+
+func withPriorityClassName(name string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.PriorityClassName = name
+	}
+}
+
+func withTolerations(tolerations ...corev1.Toleration) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Tolerations = tolerations
+	}
+}
+
+func withAffinity(affinity *corev1.Affinity) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.Affinity = affinity
+	}
+}
+
+func withNodeSelector(selector map[string]string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.NodeSelector = selector
+	}
+}
+
+func withReadinessGates(gates ...corev1.PodReadinessGate) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Spec.ReadinessGates = gates
+	}
+}
+
+func withPodTemplateAnnotations(annotations map[string]string) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Template.Annotations = annotations
+	}
+}
+
+func withMaxUnavailable(mu intstr.IntOrString) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Strategy = appsv1.DeploymentStrategy{
+			Type:          appsv1.RollingUpdateDeploymentStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: &mu},
+		}
+	}
+}
+
+func withMinReadySeconds(seconds int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.MinReadySeconds = seconds
+	}
+}
+
+func withReplicas(n int32) deploymentModifier {
+	return func(d *appsv1.Deployment) {
+		d.Spec.Replicas = &n
+	}
+}
+
 const (
 	namespace = "ns"
 )
@@ -308,6 +383,233 @@ func TestBuildProviderDeployment(t *testing.T) {
 		},
 	}
 
+	revisionWithVerificationCert := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			VerificationCertRef: &corev1.SecretReference{Name: "root-ca"},
+		},
+	}
+
+	maxUnavailable := intstr.FromString("25%")
+	revisionWithMaxUnavailable := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			MaxUnavailable:      &maxUnavailable,
+		},
+	}
+
+	minReadySeconds := int32(10)
+	revisionWithMinReadySeconds := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			MinReadySeconds:     &minReadySeconds,
+		},
+	}
+
+	replicaCount := int32(3)
+	leaderElection := true
+	revisionWithReplicas := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			Replicas:            &replicaCount,
+			LeaderElection:      &leaderElection,
+		},
+	}
+
+	caVolume := corev1.Volume{
+		Name: "ca-bundle",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "ca-bundle"}},
+		},
+	}
+	caVolumeMount := corev1.VolumeMount{Name: "ca-bundle", MountPath: "/certs/ca", ReadOnly: true}
+	revisionWithExtraVolumes := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			ExtraVolumes:        []corev1.Volume{caVolume},
+			ExtraVolumeMounts:   []corev1.VolumeMount{caVolumeMount},
+		},
+	}
+
+	revisionWithHealthCheck := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			HealthCheck:         &v1.HealthCheck{Path: "/healthz", Port: 8081},
+		},
+	}
+
+	revisionWithPriorityClass := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			PriorityClassName:   pointer.String("high"),
+		},
+	}
+
+	revisionWithAnnotations := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:                pkgImg,
+			Revision:               3,
+			TLSServerSecretName:    &tlsServerSecretName,
+			TLSClientSecretName:    &tlsClientSecretName,
+			PodTemplateAnnotations: map[string]string{"iam.gke.io/gcp-service-account": "provider@example.iam.gserviceaccount.com"},
+		},
+	}
+
+	revisionWithCCAndAnnotations := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			ControllerConfigReference: &v1.ControllerConfigReference{Name: "cc"},
+			Package:                   pkgImg,
+			Revision:                  3,
+			TLSServerSecretName:       &tlsServerSecretName,
+			TLSClientSecretName:       &tlsClientSecretName,
+			PodTemplateAnnotations: map[string]string{
+				"iam.gke.io/gcp-service-account": "provider@example.iam.gserviceaccount.com",
+				"cool":                           "revision",
+			},
+		},
+	}
+
+	gpu := corev1.Toleration{Key: "nvidia.com/gpu", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	spot := corev1.Toleration{Key: "spot", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoSchedule}
+
+	credentialsInjected := corev1.PodReadinessGate{ConditionType: "provider.example.org/credentials-injected"}
+
+	revisionWithReadinessGates := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			ReadinessGates:      []corev1.PodReadinessGate{credentialsInjected},
+		},
+	}
+
+	revisionWithCCAndTolerations := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			ControllerConfigReference: &v1.ControllerConfigReference{Name: "cc"},
+			Package:                   pkgImg,
+			Revision:                  3,
+			TLSServerSecretName:       &tlsServerSecretName,
+			TLSClientSecretName:       &tlsClientSecretName,
+			Tolerations:               []corev1.Toleration{gpu, spot},
+		},
+	}
+
+	family := &corev1.Affinity{PodAffinity: &corev1.PodAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{
+			Weight: 100,
+			PodAffinityTerm: corev1.PodAffinityTerm{
+				LabelSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"pkg.crossplane.io/provider-family": "family-aws"}},
+				TopologyKey:   "kubernetes.io/hostname",
+			},
+		}},
+	}}
+	zonal := &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+				MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "topology.kubernetes.io/zone", Operator: corev1.NodeSelectorOpIn, Values: []string{"us-east-1a"}}},
+			}},
+		},
+	}}
+
+	revisionWithAffinity := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			Package:             pkgImg,
+			Revision:            3,
+			TLSServerSecretName: &tlsServerSecretName,
+			TLSClientSecretName: &tlsClientSecretName,
+			Affinity:            family,
+		},
+	}
+
+	revisionWithCCAndAffinity := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			ControllerConfigReference: &v1.ControllerConfigReference{Name: "cc"},
+			Package:                   pkgImg,
+			Revision:                  3,
+			TLSServerSecretName:       &tlsServerSecretName,
+			TLSClientSecretName:       &tlsClientSecretName,
+			Affinity:                  family,
+		},
+	}
+
+	revisionWithCCAndNodeSelector := &v1.ProviderRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "rev-123",
+		},
+		Spec: v1.PackageRevisionSpec{
+			ControllerConfigReference: &v1.ControllerConfigReference{Name: "cc"},
+			Package:                   pkgImg,
+			Revision:                  3,
+			TLSServerSecretName:       &tlsServerSecretName,
+			TLSClientSecretName:       &tlsClientSecretName,
+			NodeSelector: map[string]string{
+				"kubernetes.io/arch": "arm64",
+				"example.org/pool":   "providers",
+			},
+		},
+	}
+
 	revisionWithCC := &v1.ProviderRevision{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: "rev-123",
@@ -335,6 +637,49 @@ func TestBuildProviderDeployment(t *testing.T) {
 		},
 	}
 
+	ccWithTolerations := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Tolerations: []corev1.Toleration{spot},
+		},
+	}
+
+	ccWithAffinity := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Affinity: zonal,
+		},
+	}
+
+	ccWithNodeSelector := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			NodeSelector: map[string]string{
+				"kubernetes.io/arch": "amd64",
+				"kubernetes.io/os":   "linux",
+			},
+		},
+	}
+
+	ccWithAnnotations := &v1alpha1.ControllerConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: revisionWithCC.Name,
+		},
+		Spec: v1alpha1.ControllerConfigSpec{
+			Metadata: &v1alpha1.PodObjectMeta{
+				Annotations: map[string]string{
+					"cool": "controllerconfig",
+				},
+			},
+		},
+	}
+
 	ccWithVolumes := &v1alpha1.ControllerConfig{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: revisionWithCC.Name,
@@ -377,6 +722,249 @@ func TestBuildProviderDeployment(t *testing.T) {
 				cs:  secretClient(revisionWithoutCC),
 			},
 		},
+		"MaxUnavailable": {
+			reason: "If the revision specifies a maximum number of unavailable pods, the deployment should roll out using it.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithMaxUnavailable,
+				cc:       nil,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithMaxUnavailable),
+				d:   deployment(providerWithoutImage, revisionWithMaxUnavailable.GetName(), pkgImg, withMaxUnavailable(maxUnavailable)),
+				svc: service(providerWithoutImage, revisionWithMaxUnavailable),
+				ss:  secretServer(revisionWithMaxUnavailable),
+				cs:  secretClient(revisionWithMaxUnavailable),
+			},
+		},
+		"MinReadySeconds": {
+			reason: "If the revision specifies a minimum number of seconds pods must be ready, the deployment should use it.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithMinReadySeconds,
+				cc:       nil,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithMinReadySeconds),
+				d:   deployment(providerWithoutImage, revisionWithMinReadySeconds.GetName(), pkgImg, withMinReadySeconds(minReadySeconds)),
+				svc: service(providerWithoutImage, revisionWithMinReadySeconds),
+				ss:  secretServer(revisionWithMinReadySeconds),
+				cs:  secretClient(revisionWithMinReadySeconds),
+			},
+		},
+		"ReplicasWithLeaderElection": {
+			reason: "If the revision specifies replicas and leader election, the deployment should run that many replicas with leader election enabled.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithReplicas,
+				cc:       nil,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithReplicas),
+				d: deployment(providerWithoutImage, revisionWithReplicas.GetName(), pkgImg,
+					withReplicas(replicaCount),
+					withAdditionalEnvVar(corev1.EnvVar{Name: leaderElectionEnvVar, Value: "true"}),
+				),
+				svc: service(providerWithoutImage, revisionWithReplicas),
+				ss:  secretServer(revisionWithReplicas),
+				cs:  secretClient(revisionWithReplicas),
+			},
+		},
+		"ExtraVolumes": {
+			reason: "If the revision specifies extra volumes and volume mounts, they should be added to the deployment.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithExtraVolumes,
+				cc:       nil,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithExtraVolumes),
+				d: deployment(providerWithoutImage, revisionWithExtraVolumes.GetName(), pkgImg,
+					withAdditionalVolume(caVolume),
+					withAdditionalVolumeMount(caVolumeMount),
+				),
+				svc: service(providerWithoutImage, revisionWithExtraVolumes),
+				ss:  secretServer(revisionWithExtraVolumes),
+				cs:  secretClient(revisionWithExtraVolumes),
+			},
+		},
+		"HealthCheck": {
+			reason: "If the revision specifies a health check, the deployment should probe it.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithHealthCheck,
+				cc:       nil,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithHealthCheck),
+				d:   deployment(providerWithoutImage, revisionWithHealthCheck.GetName(), pkgImg, withHealthCheck("/healthz", 8081)),
+				svc: service(providerWithoutImage, revisionWithHealthCheck),
+				ss:  secretServer(revisionWithHealthCheck),
+				cs:  secretClient(revisionWithHealthCheck),
+			},
+		},
+		"PriorityClassName": {
+			reason: "If the revision specifies a priority class, the deployment's pods should use it.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithPriorityClass,
+				cc:       nil,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithPriorityClass),
+				d:   deployment(providerWithoutImage, revisionWithPriorityClass.GetName(), pkgImg, withPriorityClassName("high")),
+				svc: service(providerWithoutImage, revisionWithPriorityClass),
+				ss:  secretServer(revisionWithPriorityClass),
+				cs:  secretClient(revisionWithPriorityClass),
+			},
+		},
+		"PodTemplateAnnotations": {
+			reason: "If the revision specifies pod template annotations, the deployment's pods should be annotated with them.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithAnnotations,
+				cc:       nil,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithAnnotations),
+				d: deployment(providerWithoutImage, revisionWithAnnotations.GetName(), pkgImg, withPodTemplateAnnotations(map[string]string{
+					"iam.gke.io/gcp-service-account": "provider@example.iam.gserviceaccount.com",
+				})),
+				svc: service(providerWithoutImage, revisionWithAnnotations),
+				ss:  secretServer(revisionWithAnnotations),
+				cs:  secretClient(revisionWithAnnotations),
+			},
+		},
+		"PodTemplateAnnotationsCC": {
+			reason: "Pod template annotations set by a ControllerConfig should take precedence over those set by the revision.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithCCAndAnnotations,
+				cc:       ccWithAnnotations,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithCCAndAnnotations),
+				d: deployment(providerWithoutImage, revisionWithCCAndAnnotations.GetName(), pkgImg, withPodTemplateAnnotations(map[string]string{
+					"iam.gke.io/gcp-service-account": "provider@example.iam.gserviceaccount.com",
+					"cool":                           "controllerconfig",
+				})),
+				svc: service(providerWithoutImage, revisionWithCCAndAnnotations),
+				ss:  secretServer(revisionWithCCAndAnnotations),
+				cs:  secretClient(revisionWithCCAndAnnotations),
+			},
+		},
+		"TolerationsCC": {
+			reason: "Tolerations set by the revision should be merged with those set by a ControllerConfig, without duplicates.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithCCAndTolerations,
+				cc:       ccWithTolerations,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithCCAndTolerations),
+				d:   deployment(providerWithoutImage, revisionWithCCAndTolerations.GetName(), pkgImg, withTolerations(spot, gpu)),
+				svc: service(providerWithoutImage, revisionWithCCAndTolerations),
+				ss:  secretServer(revisionWithCCAndTolerations),
+				cs:  secretClient(revisionWithCCAndTolerations),
+			},
+		},
+		"Affinity": {
+			reason: "The affinity of the revision should be used if no ControllerConfig sets one.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithAffinity,
+				cc:       nil,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithAffinity),
+				d:   deployment(providerWithoutImage, revisionWithAffinity.GetName(), pkgImg, withAffinity(family)),
+				svc: service(providerWithoutImage, revisionWithAffinity),
+				ss:  secretServer(revisionWithAffinity),
+				cs:  secretClient(revisionWithAffinity),
+			},
+		},
+		"AffinityCC": {
+			reason: "The affinity set by a ControllerConfig should take precedence over that of the revision.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithCCAndAffinity,
+				cc:       ccWithAffinity,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithCCAndAffinity),
+				d:   deployment(providerWithoutImage, revisionWithCCAndAffinity.GetName(), pkgImg, withAffinity(zonal)),
+				svc: service(providerWithoutImage, revisionWithCCAndAffinity),
+				ss:  secretServer(revisionWithCCAndAffinity),
+				cs:  secretClient(revisionWithCCAndAffinity),
+			},
+		},
+		"NodeSelectorCC": {
+			reason: "The node selector of the revision should be merged over that set by a ControllerConfig.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithCCAndNodeSelector,
+				cc:       ccWithNodeSelector,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithCCAndNodeSelector),
+				d: deployment(providerWithoutImage, revisionWithCCAndNodeSelector.GetName(), pkgImg, withNodeSelector(map[string]string{
+					"kubernetes.io/arch": "arm64",
+					"kubernetes.io/os":   "linux",
+					"example.org/pool":   "providers",
+				})),
+				svc: service(providerWithoutImage, revisionWithCCAndNodeSelector),
+				ss:  secretServer(revisionWithCCAndNodeSelector),
+				cs:  secretClient(revisionWithCCAndNodeSelector),
+			},
+		},
+		"ReadinessGates": {
+			reason: "If the revision specifies readiness gates, the deployment's pods should have them.",
+			fields: args{
+				provider: providerWithoutImage,
+				revision: revisionWithReadinessGates,
+				cc:       nil,
+			},
+			want: want{
+				sa:  serviceaccount(revisionWithReadinessGates),
+				d:   deployment(providerWithoutImage, revisionWithReadinessGates.GetName(), pkgImg, withReadinessGates(credentialsInjected)),
+				svc: service(providerWithoutImage, revisionWithReadinessGates),
+				ss:  secretServer(revisionWithReadinessGates),
+				cs:  secretClient(revisionWithReadinessGates),
+			},
+		},
+		"VerificationCert": {
+			reason: "If a verification certificate is referenced, only its certificate should be mounted to the deployment.",
+			fields: args{
+				provider: providerWithImage,
+				revision: revisionWithVerificationCert,
+				cc:       nil,
+			},
+			want: want{
+				sa: serviceaccount(revisionWithVerificationCert),
+				d: deployment(providerWithImage, revisionWithVerificationCert.GetName(), img,
+					withAdditionalVolume(corev1.Volume{
+						Name: tlsVerificationCertsVolumeName,
+						VolumeSource: corev1.VolumeSource{
+							Secret: &corev1.SecretVolumeSource{
+								SecretName: "root-ca",
+								Items: []corev1.KeyToPath{
+									{Key: "tls.crt", Path: "ca.crt"},
+								},
+							},
+						},
+					}),
+					withAdditionalVolumeMount(corev1.VolumeMount{
+						Name:      tlsVerificationCertsVolumeName,
+						ReadOnly:  true,
+						MountPath: tlsVerificationCertsDir,
+					}),
+					withAdditionalEnvVar(corev1.EnvVar{Name: tlsVerificationCertDirEnvVar, Value: tlsVerificationCertsDir}),
+				),
+				svc: service(providerWithImage, revisionWithVerificationCert),
+				ss:  secretServer(revisionWithVerificationCert),
+				cs:  secretClient(revisionWithVerificationCert),
+			},
+		},
 		"ImgNoCCWithWebhookTLS": {
 			reason: "If the webhook tls secret name is given, then the deployment should be configured to serve behind the given service.",
 			fields: args{
@@ -494,3 +1082,119 @@ func TestBuildProviderDeployment(t *testing.T) {
 	}
 
 }
+
+func TestValidateMaxUnavailable(t *testing.T) {
+	ptr := func(v intstr.IntOrString) *intstr.IntOrString { return &v }
+
+	cases := map[string]struct {
+		reason string
+		mu     *intstr.IntOrString
+		want   error
+	}{
+		"Nil": {
+			reason: "An unspecified maximum should be valid.",
+		},
+		"Integer": {
+			reason: "A non-negative integer should be valid.",
+			mu:     ptr(intstr.FromInt(1)),
+		},
+		"NegativeInteger": {
+			reason: "A negative integer should be invalid.",
+			mu:     ptr(intstr.FromInt(-1)),
+			want:   errors.Errorf(errFmtNegativeMaxUnavailable, -1),
+		},
+		"Percentage": {
+			reason: "A percentage should be valid.",
+			mu:     ptr(intstr.FromString("25%")),
+		},
+		"NotAPercentage": {
+			reason: "A string that isn't a percentage should be invalid.",
+			mu:     ptr(intstr.FromString("25")),
+			want:   errors.Errorf(errFmtInvalidMaxUnavailable, "25"),
+		},
+		"NotANumber": {
+			reason: "A percentage that isn't a number should be invalid.",
+			mu:     ptr(intstr.FromString("many%")),
+			want:   errors.Errorf(errFmtInvalidMaxUnavailable, "many%"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateMaxUnavailable(tc.mu)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateMaxUnavailable(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateHealthCheck(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		hc     *v1.HealthCheck
+		want   error
+	}{
+		"Nil": {
+			reason: "An unspecified health check should be valid.",
+		},
+		"Valid": {
+			reason: "A health check with a valid port and an absolute path should be valid.",
+			hc:     &v1.HealthCheck{Path: "/healthz", Port: 8081},
+		},
+		"PortTooLow": {
+			reason: "A port below 1 should be invalid.",
+			hc:     &v1.HealthCheck{Path: "/healthz", Port: 0},
+			want:   errors.Errorf(errFmtInvalidHealthCheckPort, 0),
+		},
+		"PortTooHigh": {
+			reason: "A port above 65535 should be invalid.",
+			hc:     &v1.HealthCheck{Path: "/healthz", Port: 65536},
+			want:   errors.Errorf(errFmtInvalidHealthCheckPort, 65536),
+		},
+		"RelativePath": {
+			reason: "A path that isn't absolute should be invalid.",
+			hc:     &v1.HealthCheck{Path: "healthz", Port: 8081},
+			want:   errors.Errorf(errFmtInvalidHealthCheckPath, "healthz"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateHealthCheck(tc.hc)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateHealthCheck(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateNodeSelector(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		ns     map[string]string
+		want   error
+	}{
+		"Nil": {
+			reason: "An unspecified node selector should be valid.",
+		},
+		"Valid": {
+			reason: "A node selector whose keys are valid label keys should be valid.",
+			ns:     map[string]string{"kubernetes.io/arch": "arm64", "pool": "providers"},
+		},
+		"InvalidKey": {
+			reason: "A node selector with a key that isn't a valid label key should be invalid.",
+			ns:     map[string]string{"not a/valid/key": "value"},
+			want:   errors.Errorf(errFmtInvalidNodeSelectorKey, "not a/valid/key", strings.Join(validation.IsQualifiedName("not a/valid/key"), "; ")),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := validateNodeSelector(tc.ns)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nvalidateNodeSelector(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
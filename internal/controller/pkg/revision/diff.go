@@ -0,0 +1,161 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+const (
+	errBuildMetaScheme   = "cannot build package metadata scheme"
+	errBuildObjectScheme = "cannot build package object scheme"
+	errFmtFetchDiff      = "cannot fetch package %q"
+	errFmtParseDiff      = "cannot parse package %q"
+	errFmtIdentifyObject = "cannot identify object in package %q"
+)
+
+// UpgradeDiff returns references to the objects, for example CRDs and
+// Compositions, that are added, removed, and changed by upgrading from one
+// package image to another. Images are fetched using the supplied Fetcher.
+// Images that don't specify a registry are fetched from the supplied default
+// registry. Returned errors identify the image that couldn't be fetched or
+// parsed.
+func UpgradeDiff(ctx context.Context, f xpkg.Fetcher, registry, from, to string) (added, removed, changed []xpv1.TypedReference, err error) {
+	metaScheme, err := xpkg.BuildMetaScheme()
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errBuildMetaScheme)
+	}
+	objScheme, err := xpkg.BuildObjectScheme()
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, errBuildObjectScheme)
+	}
+
+	b := NewImageBackend(f, WithDefaultRegistry(registry))
+	p := parser.New(metaScheme, objScheme)
+
+	objects := func(pkg string) ([]runtime.Object, error) {
+		// The image backend reads the package to fetch from a revision.
+		rc, err := b.Init(ctx, PackageRevision(&v1.ConfigurationRevision{Spec: v1.PackageRevisionSpec{Package: pkg}}))
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtFetchDiff, pkg)
+		}
+		defer rc.Close() //nolint:errcheck // We only read from rc.
+		parsed, err := p.Parse(ctx, rc)
+		if err != nil {
+			return nil, errors.Wrapf(err, errFmtParseDiff, pkg)
+		}
+		return parsed.GetObjects(), nil
+	}
+
+	fromObjs, err := objects(from)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	toObjs, err := objects(to)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	fi, err := index(objScheme, fromObjs)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, errFmtIdentifyObject, from)
+	}
+	ti, err := index(objScheme, toObjs)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, errFmtIdentifyObject, to)
+	}
+	added, removed, changed = diffObjects(fi, ti)
+	return added, removed, changed, nil
+}
+
+// An objectKey identifies an object in a package regardless of its API
+// version, so that an object that moves to a new API version is considered
+// changed rather than removed and added.
+type objectKey struct {
+	gk   schema.GroupKind
+	name string
+}
+
+type indexedObject struct {
+	ref xpv1.TypedReference
+	obj runtime.Object
+}
+
+// index returns the supplied objects indexed by their group, kind, and name.
+func index(s *runtime.Scheme, objs []runtime.Object) (map[objectKey]indexedObject, error) {
+	idx := make(map[objectKey]indexedObject, len(objs))
+	for _, o := range objs {
+		gvk, err := apiutil.GVKForObject(o, s)
+		if err != nil {
+			return nil, err
+		}
+		a, err := kmeta.Accessor(o)
+		if err != nil {
+			return nil, err
+		}
+		ref := xpv1.TypedReference{APIVersion: gvk.GroupVersion().String(), Kind: gvk.Kind, Name: a.GetName()}
+		idx[objectKey{gk: gvk.GroupKind(), name: a.GetName()}] = indexedObject{ref: ref, obj: o}
+	}
+	return idx, nil
+}
+
+// diffObjects returns references to the objects that were added, removed, and
+// changed between the supplied indexes of objects. References are sorted by
+// kind, then by name.
+func diffObjects(from, to map[objectKey]indexedObject) (added, removed, changed []xpv1.TypedReference) {
+	for k, t := range to {
+		f, ok := from[k]
+		switch {
+		case !ok:
+			added = append(added, t.ref)
+		case !equality.Semantic.DeepEqual(f.obj, t.obj):
+			changed = append(changed, t.ref)
+		}
+	}
+	for k, f := range from {
+		if _, ok := to[k]; !ok {
+			removed = append(removed, f.ref)
+		}
+	}
+	for _, refs := range [][]xpv1.TypedReference{added, removed, changed} {
+		sortReferences(refs)
+	}
+	return added, removed, changed
+}
+
+func sortReferences(refs []xpv1.TypedReference) {
+	sort.Slice(refs, func(i, j int) bool {
+		if refs[i].Kind != refs[j].Kind {
+			return refs[i].Kind < refs[j].Kind
+		}
+		return refs[i].Name < refs[j].Name
+	})
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	xpextv1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+	"github.com/crossplane/crossplane/internal/xpkg/fake"
+)
+
+func TestUpgradeDiff(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		f        xpkg.Fetcher
+		from, to string
+	}
+	type want struct {
+		added, removed, changed []xpv1.TypedReference
+		err                     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"FetchError": {
+			reason: "We should return an error identifying the package we couldn't fetch.",
+			args: args{
+				f:    &fake.MockFetcher{MockFetch: fake.NewMockFetchFn(nil, errBoom)},
+				from: "crossplane/config:v1.0.0",
+				to:   "crossplane/config:v1.1.0",
+			},
+			want: want{
+				err: errors.Wrapf(errors.Wrap(errBoom, errFetchPackage), errFmtFetchDiff, "crossplane/config:v1.0.0"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			added, removed, changed, err := UpgradeDiff(context.Background(), tc.args.f, "", tc.args.from, tc.args.to)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpgradeDiff(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.added, added); diff != "" {
+				t.Errorf("\n%s\nUpgradeDiff(...): -want added, +got added:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.removed, removed); diff != "" {
+				t.Errorf("\n%s\nUpgradeDiff(...): -want removed, +got removed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\nUpgradeDiff(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDiffObjects(t *testing.T) {
+	s, _ := xpkg.BuildObjectScheme()
+
+	crd := func(name, group string) *extv1.CustomResourceDefinition {
+		return &extv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       extv1.CustomResourceDefinitionSpec{Group: group},
+		}
+	}
+	comp := func(name, kind string) *xpextv1.Composition {
+		return &xpextv1.Composition{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       xpextv1.CompositionSpec{CompositeTypeRef: xpextv1.TypeReference{APIVersion: "example.org/v1", Kind: kind}},
+		}
+	}
+
+	type args struct {
+		from, to []runtime.Object
+	}
+	type want struct {
+		added, removed, changed []xpv1.TypedReference
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "Identical packages should have no differences.",
+			args: args{
+				from: []runtime.Object{crd("a.example.org", "example.org"), comp("a", "XA")},
+				to:   []runtime.Object{crd("a.example.org", "example.org"), comp("a", "XA")},
+			},
+			want: want{},
+		},
+		"Differences": {
+			reason: "We should return sorted references to the objects that were added, removed, and changed.",
+			args: args{
+				from: []runtime.Object{
+					crd("a.example.org", "example.org"),
+					crd("b.example.org", "example.org"),
+					comp("a", "XA"),
+				},
+				to: []runtime.Object{
+					crd("c.example.org", "example.org"),
+					crd("a.example.org", "example.net"),
+					comp("a", "XB"),
+					comp("b", "XB"),
+				},
+			},
+			want: want{
+				added: []xpv1.TypedReference{
+					{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "b"},
+					{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "c.example.org"},
+				},
+				removed: []xpv1.TypedReference{
+					{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "b.example.org"},
+				},
+				changed: []xpv1.TypedReference{
+					{APIVersion: "apiextensions.crossplane.io/v1", Kind: "Composition", Name: "a"},
+					{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "a.example.org"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fi, err := index(s, tc.args.from)
+			if err != nil {
+				t.Fatalf("index(...): %s", err)
+			}
+			ti, err := index(s, tc.args.to)
+			if err != nil {
+				t.Fatalf("index(...): %s", err)
+			}
+			added, removed, changed := diffObjects(fi, ti)
+			if diff := cmp.Diff(tc.want.added, added); diff != "" {
+				t.Errorf("\n%s\ndiffObjects(...): -want added, +got added:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.removed, removed); diff != "" {
+				t.Errorf("\n%s\ndiffObjects(...): -want removed, +got removed:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.changed, changed); diff != "" {
+				t.Errorf("\n%s\ndiffObjects(...): -want changed, +got changed:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
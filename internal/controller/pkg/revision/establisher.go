@@ -54,6 +54,18 @@ const (
 	errWebhookSecretWithoutCABundle = "the value for the key tls.crt cannot be empty"
 )
 
+// A CRDsNotEstablishedError is returned by an Establisher that can't yet
+// establish custom resources because the API server hasn't established the
+// CRDs that define them.
+type CRDsNotEstablishedError struct {
+	// CRDs that aren't yet established.
+	CRDs []string
+}
+
+func (e *CRDsNotEstablishedError) Error() string {
+	return fmt.Sprintf(fmtAwaitingCRDs, strings.Join(e.CRDs, ", "))
+}
+
 // An Establisher establishes control or ownership of a set of resources in the
 // API server by checking that control or ownership can be established for all
 // resources and then establishing it.
@@ -99,20 +111,48 @@ type currentDesired struct {
 }
 
 // Establish checks that control or ownership of resources can be established by
-// parent, then establishes it.
+// parent, then establishes it. Resources are established in the order returned
+// by OrderObjects. It returns a *CRDsNotEstablishedError if custom resources
+// can't be established until the CRDs that define them are.
 func (e *APIEstablisher) Establish(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) ([]xpv1.TypedReference, error) {
 	err := e.addLabels(objs, parent)
 	if err != nil {
 		return nil, err
 	}
-	allObjs, err := e.validate(ctx, objs, parent, control)
+
+	webhookTLSCert, err := e.getWebhookTLSCert(ctx, parent)
 	if err != nil {
 		return nil, err
 	}
 
-	resourceRefs, err := e.establish(ctx, allObjs, parent, control)
-	if err != nil {
-		return nil, err
+	// Custom resources can't be validated, let alone established, until the
+	// API server is serving their CRDs. We establish objects in stages so that
+	// a package may include both CRDs and custom resources of their kinds.
+	kinds := DefinedKinds(objs)
+	resourceRefs := []xpv1.TypedReference{}
+	for _, stage := range OrderObjects(objs) {
+		// We only create objects that we control, so there's no point
+		// waiting for CRDs that we won't create.
+		if control {
+			pending, err := unestablishedCRDs(ctx, e.client, requiredCRDs(stage, kinds))
+			if err != nil {
+				return nil, errors.Wrap(err, errGetCRD)
+			}
+			if len(pending) > 0 {
+				return nil, &CRDsNotEstablishedError{CRDs: pending}
+			}
+		}
+
+		allObjs, err := e.validate(ctx, stage, parent, webhookTLSCert, control)
+		if err != nil {
+			return nil, err
+		}
+
+		refs, err := e.establish(ctx, allObjs, parent, control)
+		if err != nil {
+			return nil, err
+		}
+		resourceRefs = append(resourceRefs, refs...)
 	}
 	return resourceRefs, nil
 }
@@ -137,19 +177,22 @@ func (e *APIEstablisher) addLabels(objs []runtime.Object, parent v1.PackageRevis
 	return nil
 }
 
-func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, control bool) ([]currentDesired, error) { //nolint:gocyclo // TODO(negz): Refactor this to break up complexity.
-	var webhookTLSCert []byte
-	if parent.GetWebhookTLSSecretName() != nil {
-		s := &corev1.Secret{}
-		nn := types.NamespacedName{Name: *parent.GetWebhookTLSSecretName(), Namespace: e.namespace}
-		if err := e.client.Get(ctx, nn, s); err != nil {
-			return nil, errors.Wrap(err, errGetWebhookTLSSecret)
-		}
-		if len(s.Data["tls.crt"]) == 0 {
-			return nil, errors.New(errWebhookSecretWithoutCABundle)
-		}
-		webhookTLSCert = s.Data["tls.crt"]
+func (e *APIEstablisher) getWebhookTLSCert(ctx context.Context, parent v1.PackageRevision) ([]byte, error) {
+	if parent.GetWebhookTLSSecretName() == nil {
+		return nil, nil
+	}
+	s := &corev1.Secret{}
+	nn := types.NamespacedName{Name: *parent.GetWebhookTLSSecretName(), Namespace: e.namespace}
+	if err := e.client.Get(ctx, nn, s); err != nil {
+		return nil, errors.Wrap(err, errGetWebhookTLSSecret)
 	}
+	if len(s.Data["tls.crt"]) == 0 {
+		return nil, errors.New(errWebhookSecretWithoutCABundle)
+	}
+	return s.Data["tls.crt"], nil
+}
+
+func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, parent v1.PackageRevision, webhookTLSCert []byte, control bool) ([]currentDesired, error) { //nolint:gocyclo // TODO(negz): Refactor this to break up complexity.
 	g, ctx := errgroup.WithContext(ctx)
 	g.SetLimit(maxConcurrentEstablishers)
 	out := make(chan currentDesired, len(objs))
@@ -185,6 +228,10 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 					conf.Webhooks[i].ClientConfig.Service.Name = parent.GetName()
 					conf.Webhooks[i].ClientConfig.Service.Namespace = e.namespace
 					conf.Webhooks[i].ClientConfig.Service.Port = pointer.Int32(webhookPort)
+					if fp := parent.GetAdmissionWebhookFailurePolicy(); fp != nil {
+						p := *fp
+						conf.Webhooks[i].FailurePolicy = &p
+					}
 				}
 			case *admv1.MutatingWebhookConfiguration:
 				if len(webhookTLSCert) == 0 {
@@ -201,6 +248,10 @@ func (e *APIEstablisher) validate(ctx context.Context, objs []runtime.Object, pa
 					conf.Webhooks[i].ClientConfig.Service.Name = parent.GetName()
 					conf.Webhooks[i].ClientConfig.Service.Namespace = e.namespace
 					conf.Webhooks[i].ClientConfig.Service.Port = pointer.Int32(webhookPort)
+					if fp := parent.GetAdmissionWebhookFailurePolicy(); fp != nil {
+						p := *fp
+						conf.Webhooks[i].FailurePolicy = &p
+					}
 				}
 			case *extv1.CustomResourceDefinition:
 				if conf.Spec.Conversion != nil && conf.Spec.Conversion.Strategy == extv1.WebhookConverter {
@@ -27,6 +27,7 @@ import (
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
@@ -46,6 +47,11 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 	errBoom := errors.New("boom")
 	webhookTLSSecretName := "webhook-tls"
 	caBundle := []byte("CABUNDLE")
+	ignore := admv1.Ignore
+
+	// Set when the CRD in the SuccessfulEstablishCRDBeforeCustomResource case
+	// is updated.
+	crdUpdated := false
 
 	type args struct {
 		est     *APIEstablisher
@@ -215,6 +221,205 @@ func TestAPIEstablisherEstablish(t *testing.T) {
 				},
 			},
 		},
+		"SuccessfulWebhookFailurePolicyOverride": {
+			reason: "The failure policy of the parent revision should be applied to the webhooks of the webhook configurations we establish.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+							if s, ok := obj.(*corev1.Secret); ok {
+								(&corev1.Secret{
+									Data: map[string][]byte{
+										"tls.crt": caBundle,
+									},
+								}).DeepCopyInto(s)
+								return nil
+							}
+							return kerrors.NewNotFound(schema.GroupResource{}, "")
+						},
+						MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+							var got []*admv1.FailurePolicyType
+							switch conf := obj.(type) {
+							case *admv1.MutatingWebhookConfiguration:
+								for _, wh := range conf.Webhooks {
+									got = append(got, wh.FailurePolicy)
+								}
+							case *admv1.ValidatingWebhookConfiguration:
+								for _, wh := range conf.Webhooks {
+									got = append(got, wh.FailurePolicy)
+								}
+							}
+							for _, fp := range got {
+								if diff := cmp.Diff(&ignore, fp); diff != "" {
+									t.Errorf("Create(...): -want failure policy, +got failure policy:\n%s", diff)
+								}
+							}
+							return nil
+						},
+					},
+				},
+				objs: []runtime.Object{
+					&admv1.MutatingWebhookConfiguration{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "crossplane-providerrevision-provider-name",
+						},
+						Webhooks: []admv1.MutatingWebhook{
+							{
+								Name: "some-webhook",
+							},
+						},
+					},
+					&admv1.ValidatingWebhookConfiguration{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "crossplane-providerrevision-provider-name",
+						},
+						Webhooks: []admv1.ValidatingWebhook{
+							{
+								Name: "some-webhook",
+							},
+						},
+					},
+				},
+				parent: &v1.ProviderRevision{
+					TypeMeta: metav1.TypeMeta{
+						Kind: "ProviderRevision",
+					},
+					ObjectMeta: metav1.ObjectMeta{
+						OwnerReferences: []metav1.OwnerReference{
+							{
+								Kind: "Provider",
+								Name: "provider-name",
+								UID:  "some-unique-uid-2312",
+							},
+						},
+						Labels: map[string]string{
+							v1.LabelParentPackage: "provider-name",
+						},
+					},
+					Spec: v1.PackageRevisionSpec{
+						WebhookTLSSecretName:          &webhookTLSSecretName,
+						AdmissionWebhookFailurePolicy: &ignore,
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{
+					{Name: "crossplane-provider-provider-name"},
+					{Name: "crossplane-provider-provider-name"},
+				},
+			},
+		},
+		"SuccessfulEstablishCRDBeforeCustomResource": {
+			reason: "Custom resources should be established after the CRDs that define them.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+							if crd, ok := obj.(*extv1.CustomResourceDefinition); ok {
+								crd.Status.Conditions = []extv1.CustomResourceDefinitionCondition{{Type: extv1.Established, Status: extv1.ConditionTrue}}
+								return nil
+							}
+							return kerrors.NewNotFound(schema.GroupResource{}, "")
+						},
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							if _, ok := obj.(*extv1.CustomResourceDefinition); !ok {
+								return errBoom
+							}
+							crdUpdated = true
+							return nil
+						},
+						MockCreate: func(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+							if !crdUpdated {
+								return errors.New("custom resource created before its CRD")
+							}
+							return nil
+						},
+					},
+				},
+				objs: []runtime.Object{
+					func() runtime.Object {
+						u := &unstructured.Unstructured{}
+						u.SetAPIVersion("example.org/v1")
+						u.SetKind("Example")
+						u.SetName("cool-example")
+						return u
+					}(),
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "examples.example.org",
+						},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Group: "example.org",
+							Names: extv1.CustomResourceDefinitionNames{Kind: "Example", Plural: "examples"},
+						},
+					},
+				},
+				parent: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				refs: []xpv1.TypedReference{
+					{APIVersion: "example.org/v1", Kind: "Example", Name: "cool-example"},
+					{Name: "examples.example.org"},
+				},
+			},
+		},
+		"CRDNotEstablished": {
+			reason: "We should return a CRDsNotEstablishedError if the CRDs that define custom resources aren't yet established.",
+			args: args{
+				est: &APIEstablisher{
+					client: &test.MockClient{
+						MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+							if _, ok := obj.(*extv1.CustomResourceDefinition); ok {
+								return nil
+							}
+							return kerrors.NewNotFound(schema.GroupResource{}, "")
+						},
+						MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+							if _, ok := obj.(*extv1.CustomResourceDefinition); !ok {
+								return errBoom
+							}
+							return nil
+						},
+						MockCreate: func(_ context.Context, _ client.Object, _ ...client.CreateOption) error {
+							return errors.New("custom resource created before its CRD was established")
+						},
+					},
+				},
+				objs: []runtime.Object{
+					func() runtime.Object {
+						u := &unstructured.Unstructured{}
+						u.SetAPIVersion("example.org/v1")
+						u.SetKind("Example")
+						u.SetName("cool-example")
+						return u
+					}(),
+					&extv1.CustomResourceDefinition{
+						ObjectMeta: metav1.ObjectMeta{
+							Name: "examples.example.org",
+						},
+						Spec: extv1.CustomResourceDefinitionSpec{
+							Group: "example.org",
+							Names: extv1.CustomResourceDefinitionNames{Kind: "Example", Plural: "examples"},
+						},
+					},
+				},
+				parent: &v1.ConfigurationRevision{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "test",
+					},
+				},
+				control: true,
+			},
+			want: want{
+				err: &CRDsNotEstablishedError{CRDs: []string{"examples.example.org"}},
+			},
+		},
 		"SuccessfulExistsEstablishOwnership": {
 			reason: "Establishment should be successful if we can establish ownership for a parent of existing objects.",
 			args: args{
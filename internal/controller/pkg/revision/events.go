@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+)
+
+// A compactedEvent is the last event recorded about an object.
+type compactedEvent struct {
+	event.Event
+
+	// obj is the object the event is about.
+	obj runtime.Object
+
+	// recorded is when the event was last passed to the wrapped recorder.
+	recorded time.Time
+
+	// seen is when an identical event was last suppressed.
+	seen time.Time
+
+	// suppressed is how many identical events have been suppressed since.
+	suppressed int
+}
+
+// summary returns an event noting how many times this event was suppressed.
+func (e *compactedEvent) summary() event.Event {
+	s := e.Event
+	s.Message = fmt.Sprintf("%s (repeated %d times in the last %s)", e.Message, e.suppressed, e.seen.Sub(e.recorded).Round(time.Second))
+	return s
+}
+
+// A CompactingRecorder collapses identical consecutive events about an object
+// that are recorded within a window into one. The first identical event
+// recorded once the window has elapsed notes how many events were collapsed.
+// If a different event is recorded about the object instead, or the event is
+// forgotten, a summary of the collapsed events is recorded first. Package
+// revisions may fail to reconcile in the same way many times during a rollout;
+// compacting their events reduces load on the API server.
+type CompactingRecorder struct {
+	wrapped event.Recorder
+	window  time.Duration
+	now     func() time.Time
+
+	mx   sync.Mutex
+	last map[string]*compactedEvent
+}
+
+// NewCompactingRecorder returns a CompactingRecorder that records events using
+// the supplied Recorder, collapsing identical events within the supplied
+// window.
+func NewCompactingRecorder(r event.Recorder, window time.Duration) *CompactingRecorder {
+	return &CompactingRecorder{
+		wrapped: r,
+		window:  window,
+		now:     time.Now,
+		last:    make(map[string]*compactedEvent),
+	}
+}
+
+// Event records the supplied event, unless an identical event was recorded
+// about the supplied object within the window.
+func (r *CompactingRecorder) Event(obj runtime.Object, e event.Event) {
+	a, err := kmeta.Accessor(obj)
+	if err != nil {
+		r.wrapped.Event(obj, e)
+		return
+	}
+	key := a.GetNamespace() + "/" + a.GetName() + "/" + string(a.GetUID())
+	now := r.now()
+
+	r.mx.Lock()
+	pending := r.prune(now)
+	last, ok := r.last[key]
+	if ok && sameEvent(last.Event, e) && now.Sub(last.recorded) < r.window {
+		last.suppressed++
+		last.seen = now
+		r.mx.Unlock()
+		r.summarize(pending)
+		return
+	}
+	suppressed := 0
+	if ok && sameEvent(last.Event, e) {
+		suppressed = last.suppressed
+	}
+	if ok && !sameEvent(last.Event, e) && last.suppressed > 0 {
+		pending = append(pending, last)
+	}
+	r.last[key] = &compactedEvent{Event: e, obj: obj, recorded: now}
+	r.mx.Unlock()
+
+	r.summarize(pending)
+	if suppressed > 0 {
+		e.Message = fmt.Sprintf("%s (repeated %d times in the last %s)", e.Message, suppressed+1, now.Sub(last.recorded).Round(time.Second))
+	}
+	r.wrapped.Event(obj, e)
+}
+
+// WithAnnotations returns a new CompactingRecorder that includes the supplied
+// annotations with all recorded events.
+func (r *CompactingRecorder) WithAnnotations(keysAndValues ...string) event.Recorder {
+	cr := NewCompactingRecorder(r.wrapped.WithAnnotations(keysAndValues...), r.window)
+	cr.now = r.now
+	return cr
+}
+
+// prune forgets events that were recorded long enough ago that they could not
+// be compacted with a new event. It returns any forgotten events that were
+// suppressed, so that they can be summarized. It must be called with the lock
+// held.
+func (r *CompactingRecorder) prune(now time.Time) []*compactedEvent {
+	var forgotten []*compactedEvent
+	for k, e := range r.last {
+		if now.Sub(e.recorded) >= 2*r.window {
+			if e.suppressed > 0 {
+				forgotten = append(forgotten, e)
+			}
+			delete(r.last, k)
+		}
+	}
+	return forgotten
+}
+
+// summarize records a summary of each of the supplied suppressed events. It
+// must be called without the lock held.
+func (r *CompactingRecorder) summarize(es []*compactedEvent) {
+	for _, e := range es {
+		r.wrapped.Event(e.obj, e.summary())
+	}
+}
+
+func sameEvent(a, b event.Event) bool {
+	if a.Type != b.Type || a.Reason != b.Reason || a.Message != b.Message || len(a.Annotations) != len(b.Annotations) {
+		return false
+	}
+	for k, v := range a.Annotations {
+		if b.Annotations[k] != v {
+			return false
+		}
+	}
+	return true
+}
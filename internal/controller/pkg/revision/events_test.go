@@ -0,0 +1,163 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+type recordedEvents struct {
+	events []event.Event
+}
+
+func (r *recordedEvents) Event(_ runtime.Object, e event.Event) {
+	r.events = append(r.events, e)
+}
+
+func (r *recordedEvents) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestCompactingRecorder(t *testing.T) {
+	errBoom := errors.New("boom")
+	epoch := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	a := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "a", UID: "a-uid"}}
+	b := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "b", UID: "b-uid"}}
+
+	type record struct {
+		after time.Duration
+		obj   runtime.Object
+		e     event.Event
+	}
+
+	// failures returns n identical reconcile failures about a, one a second.
+	failures := func(n int) []record {
+		rs := make([]record, n)
+		for i := range rs {
+			rs[i] = record{after: time.Duration(i) * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)}
+		}
+		return rs
+	}
+	success := event.Normal(reasonSync, "Successfully configured package revision")
+
+	cases := map[string]struct {
+		reason  string
+		window  time.Duration
+		records []record
+		want    []event.Event
+	}{
+		"RepeatedFailures": {
+			reason: "Identical reconcile failures within the window should be recorded as one event.",
+			window: time.Minute,
+			records: []record{
+				{after: 0, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: 10 * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: 20 * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: 30 * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)},
+			},
+			want: []event.Event{
+				event.Warning(reasonSync, errBoom),
+			},
+		},
+		"WindowElapsed": {
+			reason: "An identical event recorded after the window has elapsed should note how many times it was repeated.",
+			window: time.Minute,
+			records: []record{
+				{after: 0, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: 30 * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: 90 * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)},
+			},
+			want: []event.Event{
+				event.Warning(reasonSync, errBoom),
+				event.Warning(reasonSync, errors.New("boom (repeated 2 times in the last 1m30s)")),
+			},
+		},
+		"DifferentEvents": {
+			reason: "Different events should not be compacted.",
+			window: time.Minute,
+			records: []record{
+				{after: 0, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: time.Second, obj: a, e: event.Warning(reasonParse, errBoom)},
+				{after: 2 * time.Second, obj: a, e: event.Warning(reasonSync, errBoom)},
+			},
+			want: []event.Event{
+				event.Warning(reasonSync, errBoom),
+				event.Warning(reasonParse, errBoom),
+				event.Warning(reasonSync, errBoom),
+			},
+		},
+		"RepeatedFailuresThenSuccess": {
+			reason:  "A different event should be preceded by a summary of the identical events that were collapsed before it.",
+			window:  time.Minute,
+			records: append(failures(50), record{after: 50 * time.Second, obj: a, e: success}),
+			want: []event.Event{
+				event.Warning(reasonSync, errBoom),
+				event.Warning(reasonSync, errors.New("boom (repeated 49 times in the last 49s)")),
+				success,
+			},
+		},
+		"CollapsedEventsForgotten": {
+			reason:  "A summary of collapsed events should be recorded when they are forgotten.",
+			window:  time.Minute,
+			records: append(failures(3), record{after: 3 * time.Minute, obj: b, e: event.Warning(reasonSync, errBoom)}),
+			want: []event.Event{
+				event.Warning(reasonSync, errBoom),
+				event.Warning(reasonSync, errors.New("boom (repeated 2 times in the last 2s)")),
+				event.Warning(reasonSync, errBoom),
+			},
+		},
+		"DifferentObjects": {
+			reason: "Identical events about different objects should not be compacted.",
+			window: time.Minute,
+			records: []record{
+				{after: 0, obj: a, e: event.Warning(reasonSync, errBoom)},
+				{after: time.Second, obj: b, e: event.Warning(reasonSync, errBoom)},
+			},
+			want: []event.Event{
+				event.Warning(reasonSync, errBoom),
+				event.Warning(reasonSync, errBoom),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			wrapped := &recordedEvents{}
+			r := NewCompactingRecorder(wrapped, tc.window)
+
+			now := epoch
+			r.now = func() time.Time { return now }
+			for _, rec := range tc.records {
+				now = epoch.Add(rec.after)
+				r.Event(rec.obj, rec.e)
+			}
+
+			if diff := cmp.Diff(tc.want, wrapped.events); diff != "" {
+				t.Errorf("\n%s\nr.Event(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errTrackFailures = "cannot track package revision reconciliation failures"
+)
+
+// A FailureTracker tracks how many times in a row a package revision has
+// failed to reconcile.
+type FailureTracker interface {
+	// Track the result of reconciling the named package revision. The
+	// supplied error is nil if the package revision reconciled successfully.
+	Track(ctx context.Context, nn types.NamespacedName, err error) error
+}
+
+// A FailureTrackerFn tracks how many times in a row a package revision has
+// failed to reconcile.
+type FailureTrackerFn func(ctx context.Context, nn types.NamespacedName, err error) error
+
+// Track the result of reconciling the named package revision.
+func (fn FailureTrackerFn) Track(ctx context.Context, nn types.NamespacedName, err error) error {
+	return fn(ctx, nn, err)
+}
+
+// NopFailureTracker does not track failures.
+type NopFailureTracker struct{}
+
+// Track does nothing.
+func (NopFailureTracker) Track(_ context.Context, _ types.NamespacedName, _ error) error {
+	return nil
+}
+
+// An APIFailureTracker tracks how many times in a row a package revision has
+// failed to reconcile in the package revision's status.
+type APIFailureTracker struct {
+	client      client.Client
+	newRevision func() v1.PackageRevision
+}
+
+// NewAPIFailureTracker returns a FailureTracker that tracks failures in the
+// status of package revisions returned by the supplied function.
+func NewAPIFailureTracker(c client.Client, nr func() v1.PackageRevision) *APIFailureTracker {
+	return &APIFailureTracker{client: c, newRevision: nr}
+}
+
+// Track the result of reconciling the named package revision. The failure
+// count is incremented if the supplied error is not nil, and reset to zero
+// otherwise. The package revision's status is only patched if the count
+// changes.
+func (t *APIFailureTracker) Track(ctx context.Context, nn types.NamespacedName, err error) error {
+	pr := t.newRevision()
+	if err := t.client.Get(ctx, nn, pr); err != nil {
+		// There's nothing to track if the revision no longer exists.
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetPackageRevision)
+	}
+
+	n := int64(0)
+	if err != nil {
+		n = pr.GetReconciliationFailureCount() + 1
+	}
+	if n == pr.GetReconciliationFailureCount() {
+		return nil
+	}
+
+	// We patch rather than update because the revision's status may have been
+	// updated since we read it. We only want to change the failure count.
+	orig := pr.DeepCopyObject().(v1.PackageRevision)
+	pr.SetReconciliationFailureCount(n)
+	return errors.Wrap(t.client.Status().Patch(ctx, pr, client.MergeFrom(orig)), errTrackFailures)
+}
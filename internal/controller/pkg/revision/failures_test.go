@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+var (
+	_ FailureTracker = NopFailureTracker{}
+	_ FailureTracker = FailureTrackerFn(NopFailureTracker{}.Track)
+	_ FailureTracker = &APIFailureTracker{}
+)
+
+func TestAPIFailureTrackerTrack(t *testing.T) {
+	errBoom := errors.New("boom")
+	nr := func() v1.PackageRevision { return &v1.ProviderRevision{} }
+
+	withCount := func(n int64) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			obj.(*v1.ProviderRevision).SetReconciliationFailureCount(n)
+			return nil
+		}
+	}
+
+	type args struct {
+		client client.Client
+		err    error
+	}
+	type want struct {
+		err   error
+		count *int64
+	}
+
+	one, zero := int64(1), int64(0)
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"RevisionNotFound": {
+			reason: "We should not return an error if the revision no longer exists.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "")),
+				},
+				err: errBoom,
+			},
+		},
+		"GetError": {
+			reason: "We should return any other error encountered getting the revision.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetPackageRevision),
+			},
+		},
+		"IncrementOnFailure": {
+			reason: "We should increment the failure count if the revision failed to reconcile.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+				err: errBoom,
+			},
+			want: want{
+				count: &one,
+			},
+		},
+		"ResetOnSuccess": {
+			reason: "We should reset the failure count if the revision reconciled successfully.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, withCount(3)),
+				},
+			},
+			want: want{
+				count: &zero,
+			},
+		},
+		"NoChange": {
+			reason: "We should not patch the revision if its failure count would not change.",
+			args: args{
+				client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil),
+				},
+			},
+		},
+		"PatchError": {
+			reason: "We should return any error encountered patching the revision's status.",
+			args: args{
+				client: &test.MockClient{
+					MockGet:         test.NewMockGetFn(nil),
+					MockStatusPatch: test.NewMockSubResourcePatchFn(errBoom),
+				},
+				err: errBoom,
+			},
+			want: want{
+				err:   errors.Wrap(errBoom, errTrackFailures),
+				count: &one,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var patched *int64
+			c := tc.args.client.(*test.MockClient)
+			mp := c.MockStatusPatch
+			c.MockStatusPatch = func(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				n := obj.(*v1.ProviderRevision).GetReconciliationFailureCount()
+				patched = &n
+				if mp != nil {
+					return mp(ctx, obj, patch, opts...)
+				}
+				return nil
+			}
+
+			ft := NewAPIFailureTracker(c, nr)
+			err := ft.Track(context.Background(), types.NamespacedName{Name: "cool"}, tc.args.err)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nTrack(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.count, patched); diff != "" {
+				t.Errorf("\n%s\nTrack(...): -want patched count, +got patched count:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errGetStoredRevision = "cannot get stored package revision to record condition history"
+)
+
+// historyConditionTypes are the types of condition whose transitions are
+// recorded in a package revision's condition history.
+var historyConditionTypes = []xpv1.ConditionType{v1.TypeInstalled, v1.TypeHealthy, v1.TypeObjectsExcluded}
+
+// A conditionHistoryClient records the condition transitions of the package
+// revisions it updates the status of in their condition history.
+type conditionHistoryClient struct {
+	client.Client
+	limit int
+}
+
+// Status returns a SubResourceWriter that records condition transitions.
+func (c *conditionHistoryClient) Status() client.SubResourceWriter {
+	return &conditionHistoryWriter{SubResourceWriter: c.Client.Status(), reader: c.Client, limit: c.limit}
+}
+
+type conditionHistoryWriter struct {
+	client.SubResourceWriter
+	reader client.Reader
+	limit  int
+}
+
+// Update the status of the supplied object. If the object is a package
+// revision, any of its conditions that differ from those of the stored package
+// revision are first appended to its condition history.
+func (w *conditionHistoryWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	pr, ok := obj.(v1.PackageRevision)
+	if !ok {
+		return w.SubResourceWriter.Update(ctx, obj, opts...)
+	}
+	stored := pr.DeepCopyObject().(v1.PackageRevision)
+	if err := w.reader.Get(ctx, client.ObjectKeyFromObject(pr), stored); err != nil {
+		return errors.Wrap(err, errGetStoredRevision)
+	}
+	RecordConditionTransitions(pr, stored, w.limit)
+	return w.SubResourceWriter.Update(ctx, obj, opts...)
+}
+
+// RecordConditionTransitions appends each condition of the supplied package
+// revision that has transitioned since the supplied previous version of the
+// package revision to its condition history. Conditions are considered to
+// have transitioned using the same logic SetConditions uses to decide whether
+// to update a condition. The history is a ring buffer; only the most recent
+// transitions up to the supplied limit are kept.
+func RecordConditionTransitions(pr, prev v1.PackageRevision, limit int) {
+	h := pr.GetConditionHistory()
+	for _, t := range historyConditionTypes {
+		c := pr.GetCondition(t)
+		if c.Reason == "" || c.Equal(prev.GetCondition(t)) {
+			continue
+		}
+		h = append(h, c)
+	}
+	if len(h) > limit {
+		h = append([]xpv1.Condition(nil), h[len(h)-limit:]...)
+	}
+	pr.SetConditionHistory(h)
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestRecordConditionTransitions(t *testing.T) {
+	revision := func(h []xpv1.Condition, cs ...xpv1.Condition) *v1.ProviderRevision {
+		pr := &v1.ProviderRevision{}
+		pr.SetConditions(cs...)
+		pr.SetConditionHistory(h)
+		return pr
+	}
+
+	type args struct {
+		pr    *v1.ProviderRevision
+		prev  *v1.ProviderRevision
+		limit int
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []xpv1.Condition
+	}{
+		"NoTransitions": {
+			reason: "Conditions that haven't changed should not be recorded.",
+			args: args{
+				pr:    revision(nil, v1.Healthy(), v1.Active()),
+				prev:  revision(nil, v1.Healthy(), v1.Active()),
+				limit: 3,
+			},
+			want: nil,
+		},
+		"Transitions": {
+			reason: "Conditions that have changed should be appended to the history.",
+			args: args{
+				pr:    revision([]xpv1.Condition{v1.Unhealthy()}, v1.Healthy(), v1.Active()),
+				prev:  revision(nil, v1.Unhealthy(), v1.Active()),
+				limit: 3,
+			},
+			want: []xpv1.Condition{v1.Unhealthy(), v1.Healthy()},
+		},
+		"MessageChanged": {
+			reason: "A condition whose message changed should be recorded.",
+			args: args{
+				pr:    revision(nil, v1.Unhealthy().WithMessage("new")),
+				prev:  revision(nil, v1.Unhealthy().WithMessage("old")),
+				limit: 3,
+			},
+			want: []xpv1.Condition{v1.Unhealthy().WithMessage("new")},
+		},
+		"Rollover": {
+			reason: "The oldest transitions should be dropped once the history is full.",
+			args: args{
+				pr:    revision([]xpv1.Condition{v1.UnknownHealth(), v1.Unhealthy(), v1.Healthy()}, v1.Unhealthy(), v1.Inactive()),
+				prev:  revision(nil, v1.Healthy(), v1.Active()),
+				limit: 3,
+			},
+			want: []xpv1.Condition{v1.Healthy(), v1.Inactive(), v1.Unhealthy()},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			RecordConditionTransitions(tc.args.pr, tc.args.prev, tc.args.limit)
+			if diff := cmp.Diff(tc.want, tc.args.pr.GetConditionHistory(), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nRecordConditionTransitions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConditionHistoryWriterUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		c  client.Client
+		pr *v1.ProviderRevision
+	}
+	type want struct {
+		err error
+		h   []xpv1.Condition
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrGetStored": {
+			reason: "We should return any error encountered getting the stored package revision.",
+			args: args{
+				c:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				pr: &v1.ProviderRevision{},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetStoredRevision),
+			},
+		},
+		"RecordTransition": {
+			reason: "We should record transitions relative to the stored package revision before updating status.",
+			args: args{
+				c: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						o.(*v1.ProviderRevision).SetConditions(v1.UnknownHealth())
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+						if len(o.(*v1.ProviderRevision).GetConditionHistory()) != 1 {
+							t.Errorf("condition history was not recorded before status was updated")
+						}
+						return nil
+					}),
+				},
+				pr: func() *v1.ProviderRevision {
+					pr := &v1.ProviderRevision{}
+					pr.SetConditions(v1.Healthy())
+					return pr
+				}(),
+			},
+			want: want{
+				h: []xpv1.Condition{v1.Healthy()},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &conditionHistoryClient{Client: tc.args.c, limit: 10}
+			err := c.Status().Update(context.Background(), tc.args.pr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.h, tc.args.pr.GetConditionHistory(), test.EquateConditions()); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want history, +got history:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
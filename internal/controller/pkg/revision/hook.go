@@ -21,6 +21,8 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	schedulingv1 "k8s.io/api/scheduling/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -45,11 +47,20 @@ const (
 	errDeleteProviderSA              = "cannot delete provider package service account"
 	errDeleteProviderService         = "cannot delete provider package service"
 	errDeleteProviderSecret          = "cannot delete provider package TLS secret"
+	errDeleteProviderPDB             = "cannot delete provider package pod disruption budget"
 	errApplyProviderDeployment       = "cannot apply provider package deployment"
 	errApplyProviderSecret           = "cannot apply provider package secret"
 	errApplyProviderSA               = "cannot apply provider package service account"
 	errApplyProviderService          = "cannot apply provider package service"
+	errApplyProviderPDB              = "cannot apply provider package pod disruption budget"
 	errUnavailableProviderDeployment = "provider package deployment is unavailable"
+	errInvalidMaxUnavailable         = "invalid maxUnavailable"
+	errInvalidHealthCheck            = "invalid healthCheck"
+	errInvalidNodeSelector           = "invalid nodeSelector"
+	errGetPriorityClass              = "cannot get provider package priority class"
+
+	errFmtPriorityClassNotFound     = "priority class %q does not exist"
+	errFmtVerificationCertNamespace = "verification certificate secret must be in namespace %q"
 )
 
 // A Hooks performs operations before and after a revision establishes objects.
@@ -104,6 +115,9 @@ func (h *ProviderHooks) Pre(ctx context.Context, pkg runtime.Object, pr v1.Packa
 	// NOTE(hasheddan): we avoid fetching pull secrets and controller config as
 	// they aren't needed to delete Deployment, ServiceAccount, and Service.
 	s, d, svc, secSer, secCli := buildProviderDeployment(pkgProvider, pr, nil, h.namespace, []corev1.LocalObjectReference{})
+	if err := h.client.Delete(ctx, buildProviderPodDisruptionBudget(pr, d)); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errDeleteProviderPDB)
+	}
 	if err := h.client.Delete(ctx, d); resource.IgnoreNotFound(err) != nil {
 		return errors.Wrap(err, errDeleteProviderDeployment)
 	}
@@ -133,6 +147,21 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	if pr.GetDesiredState() != v1.PackageRevisionActive {
 		return nil
 	}
+	if err := validateMaxUnavailable(pr.GetMaxUnavailable()); err != nil {
+		return errors.Wrap(err, errInvalidMaxUnavailable)
+	}
+	if err := validateHealthCheck(pr.GetHealthCheck()); err != nil {
+		return errors.Wrap(err, errInvalidHealthCheck)
+	}
+	if err := validateNodeSelector(pr.GetNodeSelector()); err != nil {
+		return errors.Wrap(err, errInvalidNodeSelector)
+	}
+	if err := h.validatePriorityClass(ctx, pr.GetPriorityClassName()); err != nil {
+		return err
+	}
+	if ref := pr.GetVerificationCertRef(); ref != nil && ref.Namespace != "" && ref.Namespace != h.namespace {
+		return errors.Errorf(errFmtVerificationCertNamespace, h.namespace)
+	}
 	cc, err := h.getControllerConfig(ctx, pr)
 	if err != nil {
 		return err
@@ -163,6 +192,9 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 			return errors.Wrap(err, errApplyProviderService)
 		}
 	}
+	if err := h.applyPodDisruptionBudget(ctx, pr, d); err != nil {
+		return err
+	}
 	pr.SetControllerReference(v1.ControllerReference{Name: d.GetName()})
 
 	for _, c := range d.Status.Conditions {
@@ -176,6 +208,16 @@ func (h *ProviderHooks) Post(ctx context.Context, pkg runtime.Object, pr v1.Pack
 	return nil
 }
 
+// applyPodDisruptionBudget applies the PodDisruptionBudget of the supplied
+// Deployment if the revision configures one, and deletes it otherwise.
+func (h *ProviderHooks) applyPodDisruptionBudget(ctx context.Context, pr v1.PackageRevision, d *appsv1.Deployment) error {
+	pdb := buildProviderPodDisruptionBudget(pr, d)
+	if pr.GetPodDisruptionBudget() == nil {
+		return errors.Wrap(resource.IgnoreNotFound(h.client.Delete(ctx, pdb)), errDeleteProviderPDB)
+	}
+	return errors.Wrap(h.client.Apply(ctx, pdb), errApplyProviderPDB)
+}
+
 func (h *ProviderHooks) getSAPullSecrets(ctx context.Context) ([]corev1.LocalObjectReference, error) {
 	sa := &corev1.ServiceAccount{}
 	if err := h.client.Get(ctx, types.NamespacedName{
@@ -196,6 +238,19 @@ func (h *ProviderHooks) getControllerConfig(ctx context.Context, pr v1.PackageRe
 	return cc, errors.Wrap(err, errGetControllerConfig)
 }
 
+// validatePriorityClass returns an error if the supplied priority class
+// doesn't exist. A nil priority class is valid.
+func (h *ProviderHooks) validatePriorityClass(ctx context.Context, name *string) error {
+	if name == nil {
+		return nil
+	}
+	err := h.client.Get(ctx, types.NamespacedName{Name: *name}, &schedulingv1.PriorityClass{})
+	if kerrors.IsNotFound(err) {
+		return errors.Errorf(errFmtPriorityClassNotFound, *name)
+	}
+	return errors.Wrap(err, errGetPriorityClass)
+}
+
 // ConfigurationHooks performs operations for a configuration package before and
 // after the revision establishes objects.
 type ConfigurationHooks struct{}
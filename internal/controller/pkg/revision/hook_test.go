@@ -23,7 +23,12 @@ import (
 	"github.com/google/go-cmp/cmp"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
@@ -198,6 +203,41 @@ func TestHookPre(t *testing.T) {
 				},
 			},
 		},
+		"ErrProviderDeletePDB": {
+			reason: "Should return error if we fail to delete pod disruption budget for inactive provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*policyv1.PodDisruptionBudget); ok {
+									return errBoom
+								}
+								return nil
+							}),
+						},
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:        v1.PackageRevisionInactive,
+						TLSServerSecretName: &tlsServerSecret,
+						TLSClientSecretName: &tlsClientSecret,
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:        v1.PackageRevisionInactive,
+						TLSServerSecretName: &tlsServerSecret,
+						TLSClientSecretName: &tlsClientSecret,
+					},
+				},
+				err: errors.Wrap(errBoom, errDeleteProviderPDB),
+			},
+		},
 		"ErrProviderDeleteDeployment": {
 			reason: "Should return error if we fail to delete deployment for inactive provider revision.",
 			args: args{
@@ -361,6 +401,7 @@ func TestHookPost(t *testing.T) {
 	errBoom := errors.New("boom")
 	saName := "crossplane"
 	saNamespace := "crossplane-system"
+	maxUnavailable := intstr.FromInt(1)
 
 	type args struct {
 		hook Hooks
@@ -638,6 +679,7 @@ func TestHookPost(t *testing.T) {
 							return nil
 						}),
 						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil),
 							MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 								switch o := obj.(type) {
 								case *corev1.ServiceAccount:
@@ -692,6 +734,156 @@ func TestHookPost(t *testing.T) {
 				err: errors.Errorf("%s: %s", errUnavailableProviderDeployment, errBoom.Error()),
 			},
 		},
+		"ErrProviderApplyPDB": {
+			reason: "Should return error if we fail to apply pod disruption budget for active provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					namespace:      saNamespace,
+					serviceAccount: saName,
+					client: resource.ClientApplicator{
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*policyv1.PodDisruptionBudget); ok {
+								return errBoom
+							}
+							return nil
+						}),
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+								switch o := obj.(type) {
+								case *corev1.ServiceAccount:
+									if key.Name != saName {
+										t.Errorf("unexpected ServiceAccount name: %s", key.Name)
+									}
+									if key.Namespace != saNamespace {
+										t.Errorf("unexpected ServiceAccount Namespace: %s", key.Namespace)
+									}
+									*o = corev1.ServiceAccount{
+										ImagePullSecrets: []corev1.LocalObjectReference{{}},
+									}
+									return nil
+								case *corev1.Secret:
+									if key.Name != caSecret && key.Name != tlsServerSecret && key.Name != tlsClientSecret {
+										t.Errorf("unexpected Secret name: %s", key.Name)
+									}
+									if key.Namespace != tlsSecretNamespace {
+										t.Errorf("unexpected Secret Namespace: %s", key.Namespace)
+									}
+									*o = corev1.Secret{
+										Data: map[string][]byte{
+											corev1.TLSCertKey:       []byte(caCert),
+											corev1.TLSPrivateKeyKey: []byte(caKey),
+										},
+									}
+									return nil
+								default:
+									return errBoom
+								}
+							},
+						},
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:        v1.PackageRevisionActive,
+						TLSServerSecretName: &tlsServerSecret,
+						TLSClientSecretName: &tlsClientSecret,
+						PodDisruptionBudget: &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable},
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:        v1.PackageRevisionActive,
+						TLSServerSecretName: &tlsServerSecret,
+						TLSClientSecretName: &tlsClientSecret,
+						PodDisruptionBudget: &policyv1.PodDisruptionBudgetSpec{MaxUnavailable: &maxUnavailable},
+					},
+				},
+				err: errors.Wrap(errBoom, errApplyProviderPDB),
+			},
+		},
+		"ErrPriorityClassNotFound": {
+			reason: "Should return error if the priority class of an active provider revision doesn't exist.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "high")),
+						},
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:      v1.PackageRevisionActive,
+						PriorityClassName: pointer.String("high"),
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:      v1.PackageRevisionActive,
+						PriorityClassName: pointer.String("high"),
+					},
+				},
+				err: errors.Errorf(errFmtPriorityClassNotFound, "high"),
+			},
+		},
+		"ErrVerificationCertNamespace": {
+			reason: "Should return error if an active provider revision's verification certificate is in another namespace.",
+			args: args{
+				hook: &ProviderHooks{
+					namespace: "crossplane-system",
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:        v1.PackageRevisionActive,
+						VerificationCertRef: &corev1.SecretReference{Name: "ca", Namespace: "elsewhere"},
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:        v1.PackageRevisionActive,
+						VerificationCertRef: &corev1.SecretReference{Name: "ca", Namespace: "elsewhere"},
+					},
+				},
+				err: errors.Errorf(errFmtVerificationCertNamespace, "crossplane-system"),
+			},
+		},
+		"ErrGetPriorityClass": {
+			reason: "Should return error if we fail to get the priority class of an active provider revision.",
+			args: args{
+				hook: &ProviderHooks{
+					client: resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(errBoom),
+						},
+					},
+				},
+				pkg: &pkgmetav1.Provider{},
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:      v1.PackageRevisionActive,
+						PriorityClassName: pointer.String("high"),
+					},
+				},
+			},
+			want: want{
+				rev: &v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						DesiredState:      v1.PackageRevisionActive,
+						PriorityClassName: pointer.String("high"),
+					},
+				},
+				err: errors.Wrap(errBoom, errGetPriorityClass),
+			},
+		},
 		"SuccessfulProviderApply": {
 			reason: "Should not return error if successfully applied service account and deployment for active provider revision.",
 			args: args{
@@ -703,6 +895,7 @@ func TestHookPost(t *testing.T) {
 							return nil
 						}),
 						Client: &test.MockClient{
+							MockDelete: test.NewMockDeleteFn(nil),
 							MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
 								switch o := obj.(type) {
 								case *corev1.ServiceAccount:
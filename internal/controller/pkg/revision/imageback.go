@@ -20,8 +20,10 @@ import (
 	"archive/tar"
 	"context"
 	"io"
+	"sync"
 
 	"github.com/google/go-containerregistry/pkg/name"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/validate"
 
@@ -43,6 +45,10 @@ const (
 	errFmtMaxManifestLayers    = "package has %d layers, but only %d are allowed"
 	errValidateLayer           = "invalid package layer"
 	errValidateImage           = "invalid package image"
+	errNoPackageStream         = "image does not contain a package.yaml file"
+
+	errFmtNotAPackageManifest = "image manifest has media type %q, which is not an image manifest"
+	errFmtNotAPackageConfig   = "image config has media type %q, which is not an image config"
 )
 
 const (
@@ -52,6 +58,43 @@ const (
 	maxLayers = 256
 )
 
+// A notAPackage error indicates that an OCI image is not a Crossplane package.
+type notAPackage struct {
+	error
+}
+
+// IsNotAPackage returns true if the supplied error indicates that an OCI image
+// is not a Crossplane package.
+func IsNotAPackage(err error) bool {
+	return errors.As(err, &notAPackage{})
+}
+
+// validateManifest returns an error if the supplied manifest can't be that of
+// a Crossplane package. Packages are images, so other OCI artifacts (e.g. Helm
+// charts or signatures) can be detected by their manifest and config media
+// types.
+func validateManifest(m *ociv1.Manifest) error {
+	if m.MediaType != "" && !m.MediaType.IsImage() {
+		return notAPackage{errors.Errorf(errFmtNotAPackageManifest, m.MediaType)}
+	}
+	if m.Config.MediaType != "" && !m.Config.MediaType.IsConfig() {
+		return notAPackage{errors.Errorf(errFmtNotAPackageConfig, m.Config.MediaType)}
+	}
+	return nil
+}
+
+// layersToPull returns the number of layers that must be pulled to read the
+// package described by the supplied manifest. Only the base layer must be
+// pulled if one is annotated, otherwise every layer must be.
+func layersToPull(m *ociv1.Manifest) int {
+	for _, l := range m.Layers {
+		if l.Annotations[layerAnnotation] == baseAnnotationValue {
+			return 1
+		}
+	}
+	return len(m.Layers)
+}
+
 // ImageBackend is a backend for parser.
 type ImageBackend struct {
 	registry string
@@ -80,7 +123,7 @@ func NewImageBackend(fetcher xpkg.Fetcher, opts ...ImageBackendOption) *ImageBac
 }
 
 // Init initializes an ImageBackend.
-func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) { //nolint:gocyclo // TODO(negz): Can this be made less complex?
+func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io.ReadCloser, error) {
 	// NOTE(hasheddan): we use nestedBackend here because simultaneous
 	// reconciles of providers or configurations can lead to the package
 	// revision being overwritten mid-execution in the shared image backend when
@@ -102,17 +145,35 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	if err != nil {
 		return nil, errors.Wrap(err, errFetchPackage)
 	}
+	return readPackageStream(img, n.progress)
+}
+
+// readPackageStream returns the package YAML stream of the supplied package
+// image, reporting progress as its layers are read if progress is non-nil.
+func readPackageStream(img ociv1.Image, progress PullProgressFn) (io.ReadCloser, error) { //nolint:gocyclo // TODO(negz): Can this be made less complex?
 	// Get image manifest.
 	manifest, err := img.Manifest()
 	if err != nil {
 		return nil, errors.Wrap(err, errGetManifest)
 	}
 
+	// Check that the image could be a package before we try to read it.
+	if err := validateManifest(manifest); err != nil {
+		return nil, err
+	}
+
 	// Check that the image has less than the maximum allowed number of layers.
 	if nLayers := len(manifest.Layers); nLayers > maxLayers {
 		return nil, errors.Errorf(errFmtMaxManifestLayers, nLayers, maxLayers)
 	}
 
+	// Report progress as layers are pulled, if we were asked to.
+	var p *pullProgress
+	if progress != nil {
+		p = newPullProgress(layersToPull(manifest), progress)
+		img = p.image(img)
+	}
+
 	// Determine if the image is using annotated layers.
 	var tarc io.ReadCloser
 	foundAnnotated := false
@@ -156,6 +217,11 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 	t := tar.NewReader(tarc)
 	for {
 		h, err := t.Next()
+		if errors.Is(err, io.EOF) && !foundAnnotated {
+			// An image without an annotated layer or a package.yaml file is
+			// most likely a plain container image.
+			return nil, notAPackage{errors.New(errNoPackageStream)}
+		}
 		if err != nil {
 			return nil, errors.Wrap(err, errOpenPackageStream)
 		}
@@ -176,7 +242,8 @@ func (i *ImageBackend) Init(ctx context.Context, bo ...parser.BackendOption) (io
 // options.
 // NOTE(hasheddan): see usage in ImageBackend Init() for reasoning.
 type nestedBackend struct {
-	pr v1.PackageRevision
+	pr       v1.PackageRevision
+	progress PullProgressFn
 }
 
 // Init is a nop because nestedBackend does not actually meant to act as a
@@ -195,3 +262,118 @@ func PackageRevision(pr v1.PackageRevision) parser.BackendOption {
 		i.pr = pr
 	}
 }
+
+// A PullProgressFn is called as the layers of a package revision's image are
+// pulled, with the number of layers that have been pulled so far and the total
+// number of layers that must be pulled.
+type PullProgressFn func(pulled, total int)
+
+// PullProgress sets a function that ImageBackend will call as it pulls the
+// layers of a package revision's image.
+func PullProgress(fn PullProgressFn) parser.BackendOption {
+	return func(p parser.Backend) {
+		i, ok := p.(*nestedBackend)
+		if !ok {
+			return
+		}
+		i.progress = fn
+	}
+}
+
+// pullProgress tracks how many of an image's layers have been pulled. A layer
+// is considered pulled once it has been read in full.
+type pullProgress struct {
+	mu     sync.Mutex
+	total  int
+	pulled map[ociv1.Hash]bool
+	report PullProgressFn
+}
+
+func newPullProgress(total int, fn PullProgressFn) *pullProgress {
+	fn(0, total)
+	return &pullProgress{total: total, pulled: make(map[ociv1.Hash]bool), report: fn}
+}
+
+func (p *pullProgress) pulledLayer(h ociv1.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Layers may be read more than once, for example to validate them.
+	if p.pulled[h] {
+		return
+	}
+	p.pulled[h] = true
+	p.report(len(p.pulled), p.total)
+}
+
+// image wraps the supplied image such that reading any of its layers in full
+// is reported as progress.
+func (p *pullProgress) image(img ociv1.Image) ociv1.Image {
+	return &progressImage{Image: img, progress: p}
+}
+
+type progressImage struct {
+	ociv1.Image
+	progress *pullProgress
+}
+
+func (i *progressImage) Layers() ([]ociv1.Layer, error) {
+	ls, err := i.Image.Layers()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]ociv1.Layer, len(ls))
+	for j := range ls {
+		out[j] = &progressLayer{Layer: ls[j], progress: i.progress}
+	}
+	return out, nil
+}
+
+func (i *progressImage) LayerByDigest(h ociv1.Hash) (ociv1.Layer, error) {
+	l, err := i.Image.LayerByDigest(h)
+	if err != nil {
+		return nil, err
+	}
+	return &progressLayer{Layer: l, progress: i.progress}, nil
+}
+
+type progressLayer struct {
+	ociv1.Layer
+	progress *pullProgress
+}
+
+func (l *progressLayer) Compressed() (io.ReadCloser, error) {
+	rc, err := l.Layer.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	return &eofReadCloser{ReadCloser: rc, eof: l.pulled}, nil
+}
+
+func (l *progressLayer) Uncompressed() (io.ReadCloser, error) {
+	rc, err := l.Layer.Uncompressed()
+	if err != nil {
+		return nil, err
+	}
+	return &eofReadCloser{ReadCloser: rc, eof: l.pulled}, nil
+}
+
+func (l *progressLayer) pulled() {
+	if h, err := l.Digest(); err == nil {
+		l.progress.pulledLayer(h)
+	}
+}
+
+// An eofReadCloser calls a function the first time it reaches EOF.
+type eofReadCloser struct {
+	io.ReadCloser
+	eof  func()
+	once sync.Once
+}
+
+func (r *eofReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) {
+		r.once.Do(r.eof)
+	}
+	return n, err
+}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	ociv1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/empty"
 	"github.com/google/go-containerregistry/pkg/v1/mutate"
 	"github.com/google/go-containerregistry/pkg/v1/random"
@@ -46,6 +47,14 @@ func TestImageBackend(t *testing.T) {
 		},
 	})
 
+	// A plain container image has layers, but none are annotated and none
+	// contain a package.yaml file.
+	plainImg, _ := random.Image(int64(1000), 1)
+
+	// A Helm chart is an OCI artifact, but not an image.
+	helmConfig := types.MediaType("application/vnd.cncf.helm.config.v1+json")
+	helmImg := mutate.ConfigMediaType(mutate.MediaType(empty.Image, types.OCIManifestSchema1), helmConfig)
+
 	randImgDup, _ := mutate.Append(randImg, mutate.Addendum{
 		Layer: randLayer,
 		Annotations: map[string]string{
@@ -133,7 +142,35 @@ func TestImageBackend(t *testing.T) {
 					},
 				})},
 			},
-			want: errors.Wrap(io.EOF, errOpenPackageStream),
+			want: notAPackage{errors.New(errNoPackageStream)},
+		},
+		"ErrPlainContainerImage": {
+			reason: "Should return a not a package error if image is a plain container image.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(plainImg, nil),
+				},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package: "test/test:latest",
+					},
+				})},
+			},
+			want: notAPackage{errors.New(errNoPackageStream)},
+		},
+		"ErrNotAnImageConfig": {
+			reason: "Should return a not a package error if image config is not an image config.",
+			args: args{
+				f: &fake.MockFetcher{
+					MockFetch: fake.NewMockFetchFn(helmImg, nil),
+				},
+				opts: []parser.BackendOption{PackageRevision(&v1.ProviderRevision{
+					Spec: v1.PackageRevisionSpec{
+						Package: "test/test:latest",
+					},
+				})},
+			},
+			want: notAPackage{errors.Errorf(errFmtNotAPackageConfig, helmConfig)},
 		},
 		"ErrFetchPackage": {
 			reason: "Should return error if package is not in cache and we fail to fetch it.",
@@ -178,3 +215,57 @@ func TestImageBackend(t *testing.T) {
 		})
 	}
 }
+
+func TestImageBackendPullProgress(t *testing.T) {
+	randLayer, _ := random.Layer(int64(1000), types.DockerLayer)
+	otherLayer, _ := random.Layer(int64(1000), types.DockerLayer)
+	annotatedImg, _ := mutate.Append(empty.Image,
+		mutate.Addendum{Layer: otherLayer},
+		mutate.Addendum{
+			Layer: randLayer,
+			Annotations: map[string]string{
+				layerAnnotation: baseAnnotationValue,
+			},
+		},
+	)
+	plainImg, _ := random.Image(int64(1000), 3)
+
+	type progress struct {
+		pulled int
+		total  int
+	}
+
+	cases := map[string]struct {
+		reason string
+		img    ociv1.Image
+		want   []progress
+	}{
+		"AnnotatedLayer": {
+			reason: "Only the annotated layer should need to be pulled if there is one.",
+			img:    annotatedImg,
+			want:   []progress{{0, 1}, {1, 1}},
+		},
+		"AllLayers": {
+			reason: "Every layer should need to be pulled if none are annotated, and each should be reported once.",
+			img:    plainImg,
+			want:   []progress{{0, 3}, {1, 3}, {2, 3}, {3, 3}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := []progress{}
+			b := NewImageBackend(&fake.MockFetcher{MockFetch: fake.NewMockFetchFn(tc.img, nil)})
+			rc, _ := b.Init(context.TODO(),
+				PackageRevision(&v1.ProviderRevision{Spec: v1.PackageRevisionSpec{Package: "test/test:latest"}}),
+				PullProgress(func(pulled, total int) { got = append(got, progress{pulled, total}) }),
+			)
+			if rc != nil {
+				_, _ = io.ReadAll(rc)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(progress{})); diff != "" {
+				t.Errorf("\n%s\nb.Init(...): -want progress, +got progress:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
+)
+
+// Annotations of package metadata that are summarized in a package revision's
+// status.
+const (
+	AnnotationKeyMaintainer  = "meta.crossplane.io/maintainer"
+	AnnotationKeyDescription = "meta.crossplane.io/description"
+)
+
+// packageMeta summarizes the supplied package metadata, i.e. the parsed
+// crossplane.yaml of a package. It returns nil if the supplied object is not
+// package metadata.
+func packageMeta(o runtime.Object) *v1.PackageMeta {
+	pkg, ok := o.(pkgmetav1.Pkg)
+	if !ok {
+		return nil
+	}
+	pm := &v1.PackageMeta{}
+	if mo, ok := o.(metav1.Object); ok {
+		pm.Maintainer = mo.GetAnnotations()[AnnotationKeyMaintainer]
+		pm.Description = mo.GetAnnotations()[AnnotationKeyDescription]
+	}
+	if c := pkg.GetCrossplaneConstraints(); c != nil {
+		pm.Crossplane = c.Version
+	}
+	for _, dep := range pkg.GetDependencies() {
+		d := v1.PackageMetaDependency{Version: dep.Version}
+		if dep.Configuration != nil {
+			d.Package = *dep.Configuration
+			d.Type = string(v1beta1.ConfigurationPackageType)
+		} else if dep.Provider != nil {
+			d.Package = *dep.Provider
+			d.Type = string(v1beta1.ProviderPackageType)
+		}
+		pm.DependsOn = append(pm.DependsOn, d)
+	}
+	return pm
+}
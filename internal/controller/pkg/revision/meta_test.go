@@ -0,0 +1,104 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/parser"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/xpkg"
+)
+
+func TestPackageMeta(t *testing.T) {
+	metaScheme, _ := xpkg.BuildMetaScheme()
+	objScheme, _ := xpkg.BuildObjectScheme()
+
+	cases := map[string]struct {
+		reason string
+		yaml   string
+		want   *v1.PackageMeta
+	}{
+		"Configuration": {
+			reason: "We should summarize the metadata declared by a Configuration's crossplane.yaml.",
+			yaml: `apiVersion: meta.pkg.crossplane.io/v1
+kind: Configuration
+metadata:
+  name: getting-started-with-aws
+  annotations:
+    meta.crossplane.io/maintainer: Crossplane Maintainers <info@crossplane.io>
+    meta.crossplane.io/description: An introductory example to Crossplane and Composition for AWS.
+spec:
+  crossplane:
+    version: ">=v1.12.0"
+  dependsOn:
+  - provider: xpkg.upbound.io/crossplane-contrib/provider-aws
+    version: ">=v0.36.0"
+  - configuration: xpkg.upbound.io/crossplane/networking
+    version: "v1.0.0"
+`,
+			want: &v1.PackageMeta{
+				Maintainer:  "Crossplane Maintainers <info@crossplane.io>",
+				Description: "An introductory example to Crossplane and Composition for AWS.",
+				Crossplane:  ">=v1.12.0",
+				DependsOn: []v1.PackageMetaDependency{
+					{Package: "xpkg.upbound.io/crossplane-contrib/provider-aws", Type: "Provider", Version: ">=v0.36.0"},
+					{Package: "xpkg.upbound.io/crossplane/networking", Type: "Configuration", Version: "v1.0.0"},
+				},
+			},
+		},
+		"MinimalProvider": {
+			reason: "We should return an empty summary for a Provider that declares no optional metadata.",
+			yaml: `apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: provider-nop
+spec:
+  controller:
+    image: crossplane/provider-nop:v0.1.0
+`,
+			want: &v1.PackageMeta{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkg, err := parser.New(metaScheme, objScheme).Parse(context.Background(), io.NopCloser(strings.NewReader(tc.yaml)))
+			if err != nil {
+				t.Fatalf("Parse(...): %s", err)
+			}
+			m, _ := xpkg.TryConvert(pkg.GetMeta()[0], &pkgmetav1.Provider{}, &pkgmetav1.Configuration{})
+			got := packageMeta(m)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\npackageMeta(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+
+	t.Run("NotMeta", func(t *testing.T) {
+		if got := packageMeta(&v1.Provider{}); got != nil {
+			t.Errorf("packageMeta(...): want nil for an object that is not package metadata, got %v", got)
+		}
+	})
+}
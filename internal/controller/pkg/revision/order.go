@@ -0,0 +1,88 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// OrderObjects returns the supplied objects in the order they should be
+// established, as a series of stages. Objects in a stage may be established
+// concurrently, but only once the CRDs in earlier stages are established.
+// Custom resources of a kind defined by one of the supplied CRDs are therefore
+// ordered after the CRDs. Empty stages are omitted.
+func OrderObjects(objs []runtime.Object) [][]runtime.Object {
+	crds := DefinedKinds(objs)
+
+	first := make([]runtime.Object, 0, len(objs))
+	dependents := make([]runtime.Object, 0)
+	for _, o := range objs {
+		if _, ok := crds[o.GetObjectKind().GroupVersionKind().GroupKind()]; ok {
+			dependents = append(dependents, o)
+			continue
+		}
+		first = append(first, o)
+	}
+
+	stages := make([][]runtime.Object, 0, 2)
+	for _, s := range [][]runtime.Object{first, dependents} {
+		if len(s) > 0 {
+			stages = append(stages, s)
+		}
+	}
+	return stages
+}
+
+// DefinedKinds returns the names of the supplied CRDs, keyed by the kind each
+// defines. Objects that aren't CRDs are ignored.
+func DefinedKinds(objs []runtime.Object) map[schema.GroupKind]string {
+	out := make(map[schema.GroupKind]string)
+	for _, o := range objs {
+		var gk schema.GroupKind
+		var name string
+		switch crd := o.(type) {
+		case *extv1.CustomResourceDefinition:
+			gk, name = schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}, crd.GetName()
+		case *extv1beta1.CustomResourceDefinition:
+			gk, name = schema.GroupKind{Group: crd.Spec.Group, Kind: crd.Spec.Names.Kind}, crd.GetName()
+		}
+		if gk.Kind == "" {
+			continue
+		}
+		out[gk] = name
+	}
+	return out
+}
+
+// requiredCRDs returns the names of the CRDs that must be established before
+// the supplied objects can be.
+func requiredCRDs(objs []runtime.Object, kinds map[schema.GroupKind]string) []string {
+	seen := make(map[string]bool)
+	out := make([]string, 0)
+	for _, o := range objs {
+		name, ok := kinds[o.GetObjectKind().GroupVersionKind().GroupKind()]
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		out = append(out, name)
+	}
+	return out
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestOrderObjects(t *testing.T) {
+	crd := &extv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "examples.example.org"},
+		Spec: extv1.CustomResourceDefinitionSpec{
+			Group: "example.org",
+			Names: extv1.CustomResourceDefinitionNames{Kind: "Example", Plural: "examples"},
+		},
+	}
+	cr := &unstructured.Unstructured{}
+	cr.SetAPIVersion("example.org/v1")
+	cr.SetKind("Example")
+	cr.SetName("cool-example")
+
+	xrd := &v1.CompositeResourceDefinition{}
+	xrd.SetGroupVersionKind(v1.CompositeResourceDefinitionGroupVersionKind)
+
+	cases := map[string]struct {
+		reason string
+		objs   []runtime.Object
+		want   [][]runtime.Object
+	}{
+		"NoObjects": {
+			reason: "There should be no stages if there are no objects.",
+			want:   [][]runtime.Object{},
+		},
+		"NoCustomResources": {
+			reason: "All objects should be established together if none are custom resources defined by the package.",
+			objs:   []runtime.Object{crd, xrd},
+			want:   [][]runtime.Object{{crd, xrd}},
+		},
+		"CRDAndCustomResource": {
+			reason: "Custom resources should be established after the CRDs that define them.",
+			objs:   []runtime.Object{cr, xrd, crd},
+			want:   [][]runtime.Object{{xrd, crd}, {cr}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := OrderObjects(tc.objs)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nOrderObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
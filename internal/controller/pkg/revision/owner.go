@@ -0,0 +1,140 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"sort"
+
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+const (
+	errGetOwnerPackage         = "cannot get owner package"
+	errFmtNoOwnerPackageLabel  = "package revision has no %q label"
+	errFmtOwnerPackageNotFound = "owner package %q not found"
+	errFmtUnknownRevisionKind  = "cannot determine owner package of package revision of type %T"
+	errListRevisions           = "cannot list package revisions"
+)
+
+// An ownerPackageNotFound error indicates that a package revision's owner
+// package does not exist.
+type ownerPackageNotFound struct {
+	error
+}
+
+// IsOwnerPackageNotFound returns true if the supplied error indicates that a
+// package revision's owner package does not exist.
+func IsOwnerPackageNotFound(err error) bool {
+	return errors.As(err, &ownerPackageNotFound{})
+}
+
+// OwnerPackage returns the package that owns the supplied revision, as
+// indicated by its parent package label. Provider revisions are owned by
+// providers, and configuration revisions by configurations. An error for
+// which IsOwnerPackageNotFound returns true is returned if the revision has no
+// parent package label, or the package it names does not exist.
+func OwnerPackage(ctx context.Context, c client.Reader, r v1.PackageRevision) (v1.Package, error) {
+	var p v1.Package
+	switch r.(type) {
+	case *v1.ProviderRevision:
+		p = &v1.Provider{}
+	case *v1.ConfigurationRevision:
+		p = &v1.Configuration{}
+	default:
+		return nil, errors.Errorf(errFmtUnknownRevisionKind, r)
+	}
+
+	name := r.GetLabels()[v1.LabelParentPackage]
+	if name == "" {
+		return nil, ownerPackageNotFound{errors.Errorf(errFmtNoOwnerPackageLabel, v1.LabelParentPackage)}
+	}
+
+	if err := c.Get(ctx, types.NamespacedName{Name: name}, p); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, ownerPackageNotFound{errors.Errorf(errFmtOwnerPackageNotFound, name)}
+		}
+		return nil, errors.Wrap(err, errGetOwnerPackage)
+	}
+	return p, nil
+}
+
+// OrphanedRevisions returns all provider and configuration revisions whose
+// parent package label names a package that does not exist, for example
+// because the package was force-deleted. Revisions without a parent package
+// label are not considered orphaned.
+func OrphanedRevisions(ctx context.Context, c client.Reader) ([]v1.PackageRevision, error) {
+	orphaned := make([]v1.PackageRevision, 0)
+	for _, l := range []v1.PackageRevisionList{&v1.ProviderRevisionList{}, &v1.ConfigurationRevisionList{}} {
+		if err := c.List(ctx, l, client.HasLabels{v1.LabelParentPackage}); err != nil {
+			return nil, errors.Wrap(err, errListRevisions)
+		}
+		for _, r := range l.GetRevisions() {
+			if r.GetLabels()[v1.LabelParentPackage] == "" {
+				continue
+			}
+			_, err := OwnerPackage(ctx, c, r)
+			if IsOwnerPackageNotFound(err) {
+				orphaned = append(orphaned, r)
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return orphaned, nil
+}
+
+// PackagesByFamily returns the providers whose active revision belongs to the
+// supplied provider family, sorted by name. The family label is set only on
+// revisions, so each matching revision is joined back to its owner provider.
+// Revisions whose owner provider does not exist are ignored.
+func PackagesByFamily(ctx context.Context, c client.Reader, family string) ([]v1.Package, error) {
+	l := &v1.ProviderRevisionList{}
+	if err := c.List(ctx, l, client.MatchingLabels{v1.LabelProviderFamily: family}); err != nil {
+		return nil, errors.Wrap(err, errListRevisions)
+	}
+
+	seen := make(map[string]bool)
+	pkgs := make([]v1.Package, 0)
+	for _, r := range l.GetRevisions() {
+		if r.GetDesiredState() != v1.PackageRevisionActive {
+			continue
+		}
+		p, err := OwnerPackage(ctx, c, r)
+		if IsOwnerPackageNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if seen[p.GetName()] {
+			continue
+		}
+		seen[p.GetName()] = true
+		pkgs = append(pkgs, p)
+	}
+	sort.Slice(pkgs, func(i, j int) bool { return pkgs[i].GetName() < pkgs[j].GetName() })
+	return pkgs, nil
+}
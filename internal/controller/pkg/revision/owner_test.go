@@ -0,0 +1,330 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+func TestOwnerPackage(t *testing.T) {
+	errBoom := errors.New("boom")
+	labels := map[string]string{v1.LabelParentPackage: "cool"}
+
+	type args struct {
+		c client.Reader
+		r v1.PackageRevision
+	}
+	type want struct {
+		p        v1.Package
+		err      error
+		notFound bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoParentPackageLabel": {
+			reason: "We should return a not found error if the revision has no parent package label.",
+			args: args{
+				r: &v1.ProviderRevision{},
+			},
+			want: want{
+				err:      ownerPackageNotFound{errors.Errorf(errFmtNoOwnerPackageLabel, v1.LabelParentPackage)},
+				notFound: true,
+			},
+		},
+		"OwnerNotFound": {
+			reason: "We should return a not found error if the owner package does not exist.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "cool"))},
+				r: &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+			want: want{
+				err:      ownerPackageNotFound{errors.Errorf(errFmtOwnerPackageNotFound, "cool")},
+				notFound: true,
+			},
+		},
+		"GetError": {
+			reason: "We should return any other error encountered getting the owner package.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				r: &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetOwnerPackage),
+			},
+		},
+		"Provider": {
+			reason: "We should return the provider that owns a provider revision.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					if _, ok := obj.(*v1.Provider); !ok {
+						return errBoom
+					}
+					obj.SetName("cool")
+					return nil
+				})},
+				r: &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+			want: want{
+				p: &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+		},
+		"Configuration": {
+			reason: "We should return the configuration that owns a configuration revision.",
+			args: args{
+				c: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					if _, ok := obj.(*v1.Configuration); !ok {
+						return errBoom
+					}
+					obj.SetName("cool")
+					return nil
+				})},
+				r: &v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Labels: labels}},
+			},
+			want: want{
+				p: &v1.Configuration{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p, err := OwnerPackage(context.Background(), tc.args.c, tc.args.r)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOwnerPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notFound, IsOwnerPackageNotFound(err)); diff != "" {
+				t.Errorf("\n%s\nIsOwnerPackageNotFound(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, p); diff != "" {
+				t.Errorf("\n%s\nOwnerPackage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestOrphanedRevisions(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	prOrphan := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "pr-orphan", Labels: map[string]string{v1.LabelParentPackage: "gone"}}}
+	prOwned := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: "pr-owned", Labels: map[string]string{v1.LabelParentPackage: "cool"}}}
+	crOrphan := v1.ConfigurationRevision{ObjectMeta: metav1.ObjectMeta{Name: "cr-orphan", Labels: map[string]string{v1.LabelParentPackage: "gone"}}}
+
+	list := test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		switch l := obj.(type) {
+		case *v1.ProviderRevisionList:
+			l.Items = []v1.ProviderRevision{prOrphan, prOwned}
+		case *v1.ConfigurationRevisionList:
+			l.Items = []v1.ConfigurationRevision{crOrphan}
+		}
+		return nil
+	})
+
+	type args struct {
+		c client.Reader
+	}
+	type want struct {
+		revs []v1.PackageRevision
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing revisions.",
+			args: args{
+				c: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRevisions),
+			},
+		},
+		"GetError": {
+			reason: "We should return any error other than not found encountered getting a parent package.",
+			args: args{
+				c: &test.MockClient{
+					MockList: list,
+					MockGet:  test.NewMockGetFn(errBoom),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetOwnerPackage),
+			},
+		},
+		"Orphaned": {
+			reason: "We should return provider and configuration revisions whose parent package does not exist.",
+			args: args{
+				c: &test.MockClient{
+					MockList: list,
+					MockGet: func(_ context.Context, key client.ObjectKey, _ client.Object) error {
+						if key.Name == "gone" {
+							return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+						}
+						return nil
+					},
+				},
+			},
+			want: want{
+				revs: []v1.PackageRevision{&prOrphan, &crOrphan},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			revs, err := OrphanedRevisions(context.Background(), tc.args.c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOrphanedRevisions(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.revs, revs); diff != "" {
+				t.Errorf("\n%s\nOrphanedRevisions(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPackagesByFamily(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	rev := func(name, family, parent string, state v1.PackageRevisionDesiredState) v1.ProviderRevision {
+		pr := v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{v1.LabelProviderFamily: family, v1.LabelParentPackage: parent},
+		}}
+		pr.SetDesiredState(state)
+		return pr
+	}
+	revs := []v1.ProviderRevision{
+		rev("provider-aws-s3-1", "family-aws", "provider-aws-s3", v1.PackageRevisionActive),
+		rev("provider-aws-ec2-2", "family-aws", "provider-aws-ec2", v1.PackageRevisionActive),
+		rev("provider-aws-ec2-1", "family-aws", "provider-aws-ec2", v1.PackageRevisionInactive),
+		rev("provider-aws-rds-1", "family-aws", "provider-aws-rds", v1.PackageRevisionInactive),
+		rev("provider-aws-gone-1", "family-aws", "gone", v1.PackageRevisionActive),
+		rev("provider-gcp-storage-1", "family-gcp", "provider-gcp-storage", v1.PackageRevisionActive),
+	}
+
+	c := &test.MockClient{
+		MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			lo := &client.ListOptions{}
+			lo.ApplyOptions(opts)
+			l := obj.(*v1.ProviderRevisionList)
+			for _, r := range revs {
+				if lo.LabelSelector.Matches(labels.Set(r.GetLabels())) {
+					l.Items = append(l.Items, r)
+				}
+			}
+			return nil
+		},
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if key.Name == "gone" {
+				return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+			}
+			obj.SetName(key.Name)
+			return nil
+		},
+	}
+	provider := func(name string) v1.Package {
+		return &v1.Provider{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	}
+
+	type args struct {
+		c      client.Reader
+		family string
+	}
+	type want struct {
+		pkgs []v1.Package
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ListError": {
+			reason: "We should return any error encountered listing revisions.",
+			args: args{
+				c:      &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				family: "family-aws",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRevisions),
+			},
+		},
+		"AWS": {
+			reason: "We should return each provider whose active revision is in the family once, sorted by name.",
+			args: args{
+				c:      c,
+				family: "family-aws",
+			},
+			want: want{
+				pkgs: []v1.Package{provider("provider-aws-ec2"), provider("provider-aws-s3")},
+			},
+		},
+		"GCP": {
+			reason: "We should not return providers in other families.",
+			args: args{
+				c:      c,
+				family: "family-gcp",
+			},
+			want: want{
+				pkgs: []v1.Package{provider("provider-gcp-storage")},
+			},
+		},
+		"UnknownFamily": {
+			reason: "We should return no providers if no active revision is in the family.",
+			args: args{
+				c:      c,
+				family: "family-azure",
+			},
+			want: want{
+				pkgs: []v1.Package{},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pkgs, err := PackagesByFamily(context.Background(), tc.args.c, tc.args.family)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPackagesByFamily(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pkgs, pkgs); diff != "" {
+				t.Errorf("\n%s\nPackagesByFamily(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -19,18 +19,29 @@ package revision
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/google/go-containerregistry/pkg/name"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	extv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
@@ -47,13 +58,22 @@ import (
 	"github.com/crossplane/crossplane/apis/pkg/v1alpha1"
 	"github.com/crossplane/crossplane/apis/pkg/v1beta1"
 	"github.com/crossplane/crossplane/internal/controller/pkg/controller"
+	"github.com/crossplane/crossplane/internal/controller/rbac/provider/roles"
 	"github.com/crossplane/crossplane/internal/dag"
+	"github.com/crossplane/crossplane/internal/features"
 	"github.com/crossplane/crossplane/internal/version"
+	"github.com/crossplane/crossplane/internal/xcrd"
 	"github.com/crossplane/crossplane/internal/xpkg"
 )
 
 const (
 	reconcileTimeout = 3 * time.Minute
+	// establishWait is how long we wait before checking again whether the
+	// API server has established a package revision's CRDs.
+	establishWait = 5 * time.Second
+	// pullProgressInterval is the minimum interval between status updates
+	// reporting how much of a package revision's image has been pulled.
+	pullProgressInterval = 10 * time.Second
 	// the max size of a package parsed by the parser
 	maxPackageSize = 200 << 20 // 100 MB
 )
@@ -73,6 +93,7 @@ const (
 	errRemoveFinalizer = "cannot remove package revision finalizer"
 
 	errInitParserBackend = "cannot initialize parser backend"
+	errNotAPackage       = "image is not a Crossplane package"
 	errParsePackage      = "cannot parse package contents"
 	errLintPackage       = "linting package contents failed"
 	errNotOneMeta        = "cannot install package with multiple meta types"
@@ -82,6 +103,13 @@ const (
 	errPostHook = "cannot run post establish hook for package"
 
 	errEstablishControl = "cannot establish control of object"
+	errGetCRD           = "cannot get package revision CRD"
+	fmtAwaitingCRDs     = "waiting for CRDs to be established: %s"
+	fmtObjectsExcluded  = "objects excluded by object selector: %s"
+
+	errParseExtraObjects   = "cannot parse package extra objects"
+	errGetExtraObject      = "cannot get package extra object"
+	errFmtExtraObjectOwned = "extra object %s %q already exists and is not owned by this package"
 
 	errUpdateMeta = "cannot update package revision object metadata"
 
@@ -188,6 +216,14 @@ func WithLinter(l parser.Linter) ReconcilerOption {
 	}
 }
 
+// WithNamespace specifies the namespace in which the Reconciler should create
+// namespaced extra objects that don't specify a namespace.
+func WithNamespace(n string) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.namespace = n
+	}
+}
+
 // WithVersioner specifies how the Reconciler should fetch the current
 // Crossplane version.
 func WithVersioner(v version.Operations) ReconcilerOption {
@@ -196,6 +232,31 @@ func WithVersioner(v version.Operations) ReconcilerOption {
 	}
 }
 
+// WithFailureTracker specifies how the Reconciler should track how many times
+// in a row a package revision has failed to reconcile.
+func WithFailureTracker(t FailureTracker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.failures = t
+	}
+}
+
+// WithConflictChecker specifies how the Reconciler should check whether the
+// objects of a package revision conflict with those of other packages.
+func WithConflictChecker(c ConflictChecker) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.conflicts = c
+	}
+}
+
+// WithConditionHistoryLimit specifies that the Reconciler should record up to
+// the supplied number of condition transitions in the status of each package
+// revision. Condition history is not recorded if the limit is zero.
+func WithConditionHistoryLimit(n int) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.historyLimit = n
+	}
+}
+
 // uniqueResourceIdentifier returns a unique identifier for a resource in a
 // package, consisting of the group, version, kind, and name.
 func uniqueResourceIdentifier(ref xpv1.TypedReference) string {
@@ -214,9 +275,14 @@ type Reconciler struct {
 	linter    parser.Linter
 	versioner version.Operations
 	backend   parser.Backend
+	namespace string
+	failures  FailureTracker
+	conflicts ConflictChecker
 	log       logging.Logger
 	record    event.Recorder
 
+	historyLimit int
+
 	newPackageRevision func() v1.PackageRevision
 }
 
@@ -243,6 +309,11 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, "cannot build fetcher for package parser")
 	}
 
+	var backend parser.Backend = NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))
+	if o.Features.Enabled(features.EnableAlphaPackageConfigMapSources) {
+		backend = NewConfigMapBackend(mgr.GetAPIReader(), backend)
+	}
+
 	r := NewReconciler(mgr,
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ProviderPackageType)),
@@ -251,17 +322,21 @@ func SetupProviderRevision(mgr ctrl.Manager, o controller.Options) error {
 			Applicator: resource.NewAPIPatchingApplicator(mgr.GetClient()),
 		}, o.Namespace, o.ServiceAccount)),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
+		WithNamespace(o.Namespace),
 		WithNewPackageRevisionFn(nr),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(fetcher, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(backend),
 		WithLinter(xpkg.NewProviderLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithFailureTracker(NewAPIFailureTracker(mgr.GetClient(), nr)),
+		WithConflictChecker(NewAPIConflictChecker(mgr.GetClient())),
+		WithConditionHistoryLimit(o.RevisionConditionHistoryLimit),
+		WithRecorder(newRecorder(mgr, name, o)),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		For(&v1.ProviderRevision{}).
+		For(&v1.ProviderRevision{}, builder.WithPredicates(ignoreStatusChanges())).
 		Owns(&appsv1.Deployment{}).
 		Watches(&v1alpha1.ControllerConfig{}, &EnqueueRequestForReferencingProviderRevisions{
 			client: mgr.GetClient(),
@@ -293,26 +368,53 @@ func SetupConfigurationRevision(mgr ctrl.Manager, o controller.Options) error {
 		return errors.Wrap(err, "cannot build fetcher for package parser")
 	}
 
+	var backend parser.Backend = NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))
+	if o.Features.Enabled(features.EnableAlphaPackageConfigMapSources) {
+		backend = NewConfigMapBackend(mgr.GetAPIReader(), backend)
+	}
+
 	r := NewReconciler(mgr,
 		WithCache(o.Cache),
 		WithDependencyManager(NewPackageDependencyManager(mgr.GetClient(), dag.NewMapDag, v1beta1.ConfigurationPackageType)),
 		WithHooks(NewConfigurationHooks()),
 		WithNewPackageRevisionFn(nr),
 		WithEstablisher(NewAPIEstablisher(mgr.GetClient(), o.Namespace)),
+		WithNamespace(o.Namespace),
 		WithParser(parser.New(metaScheme, objScheme)),
-		WithParserBackend(NewImageBackend(f, WithDefaultRegistry(o.DefaultRegistry))),
+		WithParserBackend(backend),
 		WithLinter(xpkg.NewConfigurationLinter()),
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+		WithFailureTracker(NewAPIFailureTracker(mgr.GetClient(), nr)),
+		WithConflictChecker(NewAPIConflictChecker(mgr.GetClient())),
+		WithConditionHistoryLimit(o.RevisionConditionHistoryLimit),
+		WithRecorder(newRecorder(mgr, name, o)),
 	)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
-		For(&v1.ConfigurationRevision{}).
+		For(&v1.ConfigurationRevision{}, builder.WithPredicates(ignoreStatusChanges())).
 		WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 }
 
+// ignoreStatusChanges returns a predicate that filters out updates to a
+// package revision that only change its status. We update the status of the
+// revisions we reconcile (e.g. to track reconciliation failures), and don't
+// want those updates to requeue the revision without backing off.
+func ignoreStatusChanges() predicate.Predicate {
+	return predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{}, predicate.AnnotationChangedPredicate{})
+}
+
+// newRecorder returns a Recorder that records events about package revisions,
+// compacting them if configured to do so.
+func newRecorder(mgr ctrl.Manager, name string, o controller.Options) event.Recorder {
+	var r event.Recorder = event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	if o.RevisionEventCompactionWindow > 0 {
+		r = NewCompactingRecorder(r, o.RevisionEventCompactionWindow)
+	}
+	return r
+}
+
 // NewReconciler creates a new package revision reconciler.
 func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 
@@ -325,6 +427,8 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		parser:    parser.New(nil, nil),
 		linter:    parser.NewPackageLinter(nil, nil, nil),
 		versioner: version.New(),
+		failures:  NopFailureTracker{},
+		conflicts: NopConflictChecker{},
 		log:       logging.NewNopLogger(),
 		record:    event.NewNopRecorder(),
 	}
@@ -333,11 +437,24 @@ func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 		f(r)
 	}
 
+	if r.historyLimit > 0 {
+		r.client = &conditionHistoryClient{Client: r.client, limit: r.historyLimit}
+	}
+
 	return r
 }
 
-// Reconcile package revision.
-func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo // Reconcilers are often very complex.
+// Reconcile package revision, tracking how many times in a row doing so has
+// failed.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := r.reconcileRevision(ctx, req)
+	if terr := r.failures.Track(ctx, req.NamespacedName, err); terr != nil {
+		r.log.Debug(errTrackFailures, "request", req, "error", terr)
+	}
+	return result, err
+}
+
+func (r *Reconciler) reconcileRevision(ctx context.Context, req reconcile.Request) (reconcile.Result, error) { //nolint:gocyclo // Reconcilers are often very complex.
 	log := r.log.WithValues("request", req)
 	log.Debug("Reconciling")
 
@@ -352,6 +469,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetPackageRevision)
 	}
 
+	// Any conditions we set from here on are set against the current spec.
+	pr.SetObservedGeneration(pr.GetGeneration())
+
 	if meta.WasDeleted(pr) {
 		// NOTE(hasheddan): In the event that a pre-cached package was
 		// used for this revision, delete will not remove the pre-cached
@@ -445,10 +565,29 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Pulling a large image can take a while, so we report progress while we
+	// pull it. Progress must be stopped before we update the revision again.
+	progress := newPullProgressReporter(ctx, r.client, pr, pullProgressInterval)
+	defer progress.Stop(pr)
+
 	// If we didn't get a ReadCloser from cache, we need to get it from image.
+	var pulled *metav1.Time
 	if rc == nil {
 		// Initialize parser backend to obtain package contents.
-		imgrc, err := r.backend.Init(ctx, PackageRevision(pr))
+		imgrc, err := r.backend.Init(ctx, PackageRevision(pr), PullProgress(progress.Report))
+		if err != nil {
+			progress.Stop(pr)
+		}
+		if IsNotAPackage(err) {
+			// No need to requeue if the image isn't a package. The
+			// package source will need to be updated, which will
+			// trigger a new reconcile.
+			log.Debug(errNotAPackage, "error", err)
+			err = errors.Wrap(err, errNotAPackage)
+			pr.SetConditions(v1.NotAPackage().WithMessage(err.Error()))
+			r.record.Event(pr, event.Warning(reasonParse, err))
+			return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+		}
 		if err != nil {
 			pr.SetConditions(v1.Unhealthy())
 			_ = r.client.Status().Update(ctx, pr)
@@ -461,6 +600,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			return reconcile.Result{}, err
 		}
 
+		// Record when we last successfully pulled the package image.
+		now := metav1.Now()
+		pulled = &now
+		pr.SetLastPullTime(pulled)
+
 		// Package is not in cache, so we write it to the cache while parsing.
 		pipeR, pipeW := io.Pipe()
 		rc = xpkg.TeeReadCloser(imgrc, pipeW)
@@ -492,8 +636,10 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			log.Debug(errDeleteCache, "error", err)
 		}
 	}
+	progress.Stop(pr)
 	if err != nil {
 		pr.SetConditions(v1.Unhealthy())
+		pr.SetParseError(parseError(v1.PackageParsePhaseParse, errParsePackage, err))
 		_ = r.client.Status().Update(ctx, pr)
 		log.Debug(errParsePackage, "error", err)
 
@@ -504,7 +650,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	// Lint package using package-specific linter.
 	if err := r.linter.Lint(pkg); err != nil {
+		phase := v1.PackageParsePhaseLint
+		if len(pkg.GetMeta()) != 1 {
+			phase = v1.PackageParsePhaseMetadata
+		}
 		pr.SetConditions(v1.Unhealthy())
+		pr.SetParseError(parseError(phase, errLintPackage, err))
 		_ = r.client.Status().Update(ctx, pr)
 
 		// NOTE(hasheddan): a failed lint typically will require manual
@@ -522,6 +673,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	// we check here to avoid a potential panic on 0 index below.
 	if len(pkg.GetMeta()) != 1 {
 		pr.SetConditions(v1.Unhealthy())
+		pr.SetParseError(parseError(v1.PackageParsePhaseMetadata, errNotOneMeta, nil))
 		_ = r.client.Status().Update(ctx, pr)
 
 		log.Debug(errNotOneMeta)
@@ -535,6 +687,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	pmo := pkgMeta.(metav1.Object)
 	meta.AddLabels(pr, pmo.GetLabels())
 	meta.AddAnnotations(pr, pmo.GetAnnotations())
+
+	// Tell the RBAC manager whether the provider's RBAC should be scoped to
+	// a set of namespaces.
+	ns, err := r.rbacNamespaces(ctx, pr)
+	if err != nil {
+		pr.SetConditions(v1.Unhealthy())
+		_ = r.client.Status().Update(ctx, pr)
+
+		log.Debug(errGetControllerConfig, "error", err)
+		r.record.Event(pr, event.Warning(reasonSync, err))
+		return reconcile.Result{}, err
+	}
+	setRBACNamespaces(pr, ns)
+
 	if err := r.client.Update(ctx, pr); err != nil {
 		pr.SetConditions(v1.Unhealthy())
 		_ = r.client.Status().Update(ctx, pr)
@@ -545,6 +711,40 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Updating the revision's metadata overwrites our in-memory copy of its
+	// status with what the API server has, so record the pull time and
+	// observed generation again.
+	if pulled != nil {
+		pr.SetLastPullTime(pulled)
+	}
+	pr.SetObservedGeneration(pr.GetGeneration())
+
+	// The package was parsed successfully, so any previous parse error no
+	// longer applies.
+	pr.SetParseError(nil)
+
+	// Record the package's Crossplane version constraint so that it can be
+	// evaluated without pulling the package again.
+	var constraint string
+	if p, ok := pkgMeta.(pkgmetav1.Pkg); ok && p.GetCrossplaneConstraints() != nil {
+		constraint = p.GetCrossplaneConstraints().Version
+	}
+	pr.SetCrossplaneVersionConstraint(constraint)
+
+	// Record a summary of the package's metadata so that tools may inspect it
+	// without pulling the package.
+	pr.SetPackageMeta(packageMeta(pkgMeta))
+
+	// Record the semantic version of the package so that dependency
+	// resolution can compare versions rather than tags. Package metadata
+	// doesn't declare a version, so the source's tag is the only place we
+	// can get one from.
+	var version string
+	if ref, err := name.ParseReference(pr.GetSource(), name.WithDefaultRegistry("")); err == nil {
+		version = xpkg.ParsePackageVersionFromReference(ref)
+	}
+	pr.SetInstalledVersion(version)
+
 	// Check Crossplane constraints if they exist.
 	if pr.GetIgnoreCrossplaneConstraints() == nil || !*pr.GetIgnoreCrossplaneConstraints() {
 		if err := xpkg.PackageCrossplaneCompatible(r.versioner)(pkgMeta); err != nil {
@@ -575,6 +775,9 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			r.record.Event(pr, event.Warning(reasonDependencies, err))
 			return reconcile.Result{}, err
 		}
+		if deps := pr.GetResolvedDependencies(); len(deps) > 0 {
+			r.record.Event(pr, event.Normal(reasonDependencies, "Resolved dependencies: "+describeResolvedDependencies(deps)))
+		}
 	}
 
 	if err := r.hook.Pre(ctx, pkgMeta, pr); err != nil {
@@ -587,8 +790,82 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// Users may choose not to apply some of a package's objects. We never
+	// establish control or ownership of excluded objects, so they're never
+	// recorded as objects of this revision. Any excluded objects that already
+	// exist, for example because an earlier revision applied them, are left
+	// as they are. Such objects are also owned by the package, so they're
+	// not garbage collected along with the revision that applied them.
+	pkgObjs, excluded := selectObjects(pr.GetObjectSelector(), pkg.GetObjects())
+
+	// Record the RBAC rules a provider's controller will be granted, so that
+	// they may be reviewed before the revision is activated.
+	if p, ok := pkgMeta.(*pkgmetav1.Provider); ok {
+		pr.SetRequiredPermissions(roles.RenderSystemRules(definedResources(pkgObjs), p.Spec.Controller.PermissionRequests))
+	}
+
+	// Packages may declare extra objects in their metadata. We establish
+	// these alongside the objects in the package, but only if doing so
+	// wouldn't take over an object the package doesn't already own.
+	var extra []runtime.Object
+	if p, ok := pkgMeta.(pkgmetav1.Pkg); ok {
+		extra, err = xpkg.ParseExtraObjects(p, r.namespace)
+	}
+	if err != nil {
+		pr.SetConditions(v1.Unhealthy())
+		_ = r.client.Status().Update(ctx, pr)
+
+		log.Debug(errParseExtraObjects, "error", err)
+		err = errors.Wrap(err, errParseExtraObjects)
+		r.record.Event(pr, event.Warning(reasonSync, err))
+		return reconcile.Result{}, err
+	}
+	extra, excludedExtra := selectObjects(pr.GetObjectSelector(), extra)
+	excluded = append(excluded, excludedExtra...)
+
+	switch {
+	case len(excluded) > 0:
+		pr.SetConditions(v1.ObjectsExcluded().WithMessage(fmt.Sprintf(fmtObjectsExcluded, strings.Join(excluded, ", "))))
+	case pr.GetCondition(v1.TypeObjectsExcluded).Reason != "":
+		// Only revisions that previously excluded objects need to say
+		// that they no longer do.
+		pr.SetConditions(v1.NoObjectsExcluded())
+	}
+
+	if err := r.checkExtraObjects(ctx, extra, pr); err != nil {
+		pr.SetConditions(v1.ExtraObjectConflict().WithMessage(err.Error()))
+		_ = r.client.Status().Update(ctx, pr)
+
+		log.Debug(err.Error())
+		r.record.Event(pr, event.Warning(reasonSync, err))
+		return reconcile.Result{}, err
+	}
+
+	// Only active revisions control their objects, so only they may fight
+	// over them with the active revisions of other packages.
+	if pr.GetDesiredState() == v1.PackageRevisionActive {
+		if err := r.conflicts.CheckConflicts(ctx, pr, pkgObjs); err != nil {
+			pr.SetConditions(v1.ObjectConflict().WithMessage(err.Error()))
+			_ = r.client.Status().Update(ctx, pr)
+
+			log.Debug(err.Error())
+			r.record.Event(pr, event.Warning(reasonSync, err))
+			return reconcile.Result{}, err
+		}
+	}
+
+	objs := make([]runtime.Object, 0, len(pkgObjs)+len(extra))
+	objs = append(objs, pkgObjs...)
+	objs = append(objs, extra...)
+
 	// Establish control or ownership of objects.
-	refs, err := r.objects.Establish(ctx, pkg.GetObjects(), pr, pr.GetDesiredState() == v1.PackageRevisionActive)
+	refs, err := r.objects.Establish(ctx, objs, pr, pr.GetDesiredState() == v1.PackageRevisionActive)
+	var pending *CRDsNotEstablishedError
+	if errors.As(err, &pending) {
+		log.Debug("Waiting for CRDs to be established before establishing custom resources", "crds", pending.CRDs)
+		pr.SetConditions(v1.AwaitingCRDs().WithMessage(pending.Error()))
+		return reconcile.Result{RequeueAfter: establishWait}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	}
 	if err != nil {
 		pr.SetConditions(v1.Unhealthy())
 		_ = r.client.Status().Update(ctx, pr)
@@ -626,7 +903,206 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		return reconcile.Result{}, err
 	}
 
+	// The revision isn't healthy until the API server is serving all of its
+	// CRDs, so wait for them to be established.
+	unestablished, err := unestablishedCRDs(ctx, r.client, v1.CRDNames(refs))
+	if err != nil {
+		pr.SetConditions(v1.UnknownHealth())
+		_ = r.client.Status().Update(ctx, pr)
+
+		log.Debug(errGetCRD, "error", err)
+		err = errors.Wrap(err, errGetCRD)
+		r.record.Event(pr, event.Warning(reasonSync, err))
+		return reconcile.Result{}, err
+	}
+	if len(unestablished) > 0 {
+		log.Debug("Waiting for CRDs to be established", "crds", unestablished)
+		pr.SetConditions(v1.AwaitingCRDs().WithMessage(fmt.Sprintf(fmtAwaitingCRDs, strings.Join(unestablished, ", "))))
+		return reconcile.Result{RequeueAfter: establishWait}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
+	}
+
 	r.record.Event(pr, event.Normal(reasonSync, "Successfully configured package revision"))
 	pr.SetConditions(v1.Healthy())
 	return reconcile.Result{Requeue: false}, errors.Wrap(r.client.Status().Update(ctx, pr), errUpdateStatus)
 }
+
+// unestablishedCRDs returns the names of any of the named CRDs that the API
+// server has not yet established.
+func unestablishedCRDs(ctx context.Context, c client.Reader, names []string) ([]string, error) {
+	pending := make([]string, 0)
+	for _, name := range names {
+		crd := &extv1.CustomResourceDefinition{}
+		err := c.Get(ctx, types.NamespacedName{Name: name}, crd)
+		if resource.IgnoreNotFound(err) != nil {
+			return nil, err
+		}
+		if kerrors.IsNotFound(err) || !xcrd.IsEstablished(crd.Status) {
+			pending = append(pending, name)
+		}
+	}
+	return pending, nil
+}
+
+// definedResources returns the resources defined by any CRDs in the supplied
+// objects.
+func definedResources(objs []runtime.Object) []roles.Resource {
+	out := make([]roles.Resource, 0)
+	for _, o := range objs {
+		switch crd := o.(type) {
+		case *extv1.CustomResourceDefinition:
+			out = append(out, roles.Resource{Group: crd.Spec.Group, Plural: crd.Spec.Names.Plural})
+		case *extv1beta1.CustomResourceDefinition:
+			out = append(out, roles.Resource{Group: crd.Spec.Group, Plural: crd.Spec.Names.Plural})
+		}
+	}
+	return out
+}
+
+// selectObjects returns the supplied objects that are selected by the supplied
+// selector, and a description of each object that isn't. A nil selector
+// selects all objects.
+func selectObjects(s *v1.ObjectSelector, objs []runtime.Object) ([]runtime.Object, []string) {
+	if s == nil {
+		return objs, nil
+	}
+	selected := make([]runtime.Object, 0, len(objs))
+	excluded := make([]string, 0)
+	for _, o := range objs {
+		gvk := o.GetObjectKind().GroupVersionKind()
+		var name string
+		if m, ok := o.(metav1.Object); ok {
+			name = m.GetName()
+		}
+		if !s.Selects(gvk, name) {
+			excluded = append(excluded, fmt.Sprintf("%s/%s", gvk.Kind, name))
+			continue
+		}
+		selected = append(selected, o)
+	}
+	return selected, excluded
+}
+
+// checkExtraObjects returns an error if any of the supplied extra objects
+// already exists but is owned by neither the supplied revision nor its parent
+// package. Objects created by other revisions of the same package are owned by
+// the package, so they don't conflict.
+func (r *Reconciler) checkExtraObjects(ctx context.Context, objs []runtime.Object, pr v1.PackageRevision) error {
+	pkgRef, _ := GetPackageOwnerReference(pr)
+	for _, o := range objs {
+		d, ok := o.(*kunstructured.Unstructured)
+		if !ok {
+			return errors.New(errConfResourceObject)
+		}
+		current := &kunstructured.Unstructured{}
+		current.SetGroupVersionKind(d.GroupVersionKind())
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: d.GetNamespace(), Name: d.GetName()}, current)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetExtraObject)
+		}
+		owned := false
+		for _, ref := range current.GetOwnerReferences() {
+			if ref.UID == pr.GetUID() || (pkgRef.UID != "" && ref.UID == pkgRef.UID) {
+				owned = true
+				break
+			}
+		}
+		if !owned {
+			return errors.Errorf(errFmtExtraObjectOwned, d.GetKind(), d.GetName())
+		}
+	}
+	return nil
+}
+
+// A pullProgressReporter reports how much of a package revision's image has
+// been pulled in the revision's status. It updates a copy of the revision, and
+// does so at most once per interval to avoid thrashing its status.
+type pullProgressReporter struct {
+	ctx      context.Context
+	client   client.Client
+	interval time.Duration
+
+	mu      sync.Mutex
+	pr      v1.PackageRevision
+	last    time.Time
+	stopped bool
+	updated bool
+}
+
+func newPullProgressReporter(ctx context.Context, c client.Client, pr v1.PackageRevision, interval time.Duration) *pullProgressReporter {
+	return &pullProgressReporter{
+		ctx:      ctx,
+		client:   c,
+		interval: interval,
+		pr:       pr.DeepCopyObject().(v1.PackageRevision),
+	}
+}
+
+// Report that the supplied number of layers have been pulled. Reporting is
+// best effort; errors updating the revision's status are ignored.
+func (p *pullProgressReporter) Report(pulled, total int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped || time.Since(p.last) < p.interval {
+		return
+	}
+	p.last = time.Now()
+	p.pr.SetConditions(v1.Pulling().WithMessage(fmt.Sprintf("pulled %d/%d layers", pulled, total)))
+	if err := p.client.Status().Update(p.ctx, p.pr); err != nil {
+		return
+	}
+	p.updated = true
+}
+
+// Stop reporting progress. Any subsequent reports are ignored. Reporting
+// progress updates the revision, so Stop updates the supplied revision's
+// resource version to match. This allows the supplied revision to be updated
+// without conflict, unless something else updated it in the meantime. It is
+// safe to call Stop more than once.
+func (p *pullProgressReporter) Stop(pr v1.PackageRevision) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopped {
+		return
+	}
+	p.stopped = true
+	if p.updated {
+		pr.SetResourceVersion(p.pr.GetResourceVersion())
+	}
+}
+
+// parseError describes a failure to parse a package. The error that caused it
+// may be nil.
+func parseError(phase v1.PackageParsePhase, detail string, err error) *v1.PackageParseError {
+	pe := &v1.PackageParseError{Phase: phase, Detail: detail}
+	if err != nil {
+		pe.CausedBy = err.Error()
+	}
+	return pe
+}
+
+// rbacNamespaces returns the namespaces the ControllerConfig referenced by the
+// supplied revision scopes its RBAC to, if any.
+func (r *Reconciler) rbacNamespaces(ctx context.Context, pr v1.PackageRevision) ([]string, error) {
+	ref := pr.GetControllerConfigRef()
+	if ref == nil {
+		return nil, nil
+	}
+	cc := &v1alpha1.ControllerConfig{}
+	if err := r.client.Get(ctx, types.NamespacedName{Name: ref.Name}, cc); err != nil {
+		return nil, errors.Wrap(err, errGetControllerConfig)
+	}
+	return cc.Spec.RBACNamespaces, nil
+}
+
+// setRBACNamespaces annotates the supplied revision with the supplied
+// namespaces, or removes the annotation if there are none.
+func setRBACNamespaces(pr v1.PackageRevision, ns []string) {
+	if len(ns) == 0 {
+		meta.RemoveAnnotations(pr, v1.AnnotationRBACNamespaces)
+		return
+	}
+	meta.AddAnnotations(pr, map[string]string{v1.AnnotationRBACNamespaces: strings.Join(ns, ",")})
+}
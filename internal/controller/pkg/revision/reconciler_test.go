@@ -21,16 +21,21 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlevent "sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -44,6 +49,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/provider/roles"
 	verfake "github.com/crossplane/crossplane/internal/version/fake"
 	"github.com/crossplane/crossplane/internal/xpkg"
 	xpkgfake "github.com/crossplane/crossplane/internal/xpkg/fake"
@@ -153,6 +159,40 @@ spec:
   crossplane:
     version: ">v0.13.0"`)
 
+var providerExtraObjectsBytes = []byte(`apiVersion: meta.pkg.crossplane.io/v1
+kind: Provider
+metadata:
+  name: test
+  annotations:
+    author: crossplane
+spec:
+  controller:
+    image: crossplane/provider-test-controller:v0.0.1
+  crossplane:
+    version: ">v0.13.0"
+  extraObjects:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: cool-config`)
+
+var providerCRDBytes = []byte(string(providerBytes) + `
+---
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: examples.example.org
+spec:
+  group: example.org
+  names:
+    kind: Example
+    plural: examples
+  scope: Cluster
+  versions:
+  - name: v1
+    served: true
+    storage: true`)
+
 func TestReconcile(t *testing.T) {
 	errBoom := errors.New("boom")
 	testLog := logging.NewLogrLogger(zap.New(zap.UseDevMode(true), zap.WriteTo(io.Discard)).WithName("testlog"))
@@ -160,6 +200,10 @@ func TestReconcile(t *testing.T) {
 	pullPolicy := corev1.PullNever
 	trueVal := true
 
+	// The last pull time is set to the current time whenever we pull a package
+	// image, so we only check whether it was set where it matters.
+	ignoreLastPullTime := cmpopts.IgnoreFields(v1.PackageRevisionStatus{}, "LastPullTime")
+
 	metaScheme, _ := xpkg.BuildMetaScheme()
 	objScheme, _ := xpkg.BuildObjectScheme()
 
@@ -260,7 +304,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDeletionTimestamp(&now)
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -373,7 +417,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -415,7 +459,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -437,7 +481,7 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 		"ErrNotInCachePullPolicyNever": {
-			reason: "We should return an error if package content is not in cache and pull policy is Never.",
+			reason: "We should return an error if package content is not in cache and pull policy is Never, and record the generation the condition was set against.",
 			args: args{
 				mgr: &fake.Manager{},
 				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
@@ -455,15 +499,18 @@ func TestReconcile(t *testing.T) {
 								pr := o.(*v1.ConfigurationRevision)
 								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetGeneration(3)
 								return nil
 							}),
 							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
 								want := &v1.ConfigurationRevision{}
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetGeneration(3)
+								want.SetObservedGeneration(3)
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -503,7 +550,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -523,6 +570,47 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errInitParserBackend),
 			},
 		},
+		"ErrNotAPackage": {
+			reason: "We should report that the revision is not healthy, without requeueing, if its image is not a package.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetConditions(v1.NotAPackage().WithMessage(errors.Wrap(notAPackage{errBoom}, errNotAPackage).Error()))
+
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+					}),
+					WithParserBackend(&ErrBackend{err: notAPackage{errBoom}}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"ErrParseFromCache": {
 			reason: "We should return an error if fail to parse the package from the cache.",
 			args: args{
@@ -543,8 +631,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
+								want.SetParseError(&v1.PackageParseError{Phase: v1.PackageParsePhaseParse, Detail: errParsePackage, CausedBy: errBoom.Error()})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -585,8 +674,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
+								want.SetParseError(&v1.PackageParseError{Phase: v1.PackageParsePhaseParse, Detail: errParsePackage, CausedBy: errBoom.Error()})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -628,8 +718,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
+								want.SetParseError(&v1.PackageParseError{Phase: v1.PackageParsePhaseParse, Detail: errParsePackage, CausedBy: errBoom.Error()})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -672,8 +763,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
+								want.SetParseError(&v1.PackageParseError{Phase: v1.PackageParsePhaseParse, Detail: errParsePackage, CausedBy: errBoom.Error()})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -716,8 +808,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
+								want.SetParseError(&v1.PackageParseError{Phase: v1.PackageParsePhaseLint, Detail: errLintPackage, CausedBy: errBoom.Error()})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -764,8 +857,10 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -775,7 +870,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -825,8 +920,9 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
+								want.SetParseError(&v1.PackageParseError{Phase: v1.PackageParsePhaseMetadata, Detail: errNotOneMeta})
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -872,7 +968,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -923,9 +1019,11 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetSkipDependencyResolution(pointer.Bool(false))
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.UnknownHealth())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -936,7 +1034,7 @@ func TestReconcile(t *testing.T) {
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
 								want.SetSkipDependencyResolution(pointer.Bool(false))
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -983,9 +1081,11 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -995,7 +1095,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1045,9 +1145,11 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1057,7 +1159,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1089,6 +1191,72 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errPostHook),
 			},
 		},
+		"ErrExtraObjectConflict": {
+			reason: "We should return an error if an extra object already exists and is not owned by the package.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								if pr, ok := o.(*v1.ProviderRevision); ok {
+									pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+									pr.SetDesiredState(v1.PackageRevisionActive)
+									pr.SetUID("cool-revision")
+									return nil
+								}
+								// The extra object exists, but is owned by
+								// something else.
+								o.SetOwnerReferences([]metav1.OwnerReference{{UID: "someone-else"}})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetUID("cool-revision")
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
+								want.SetConditions(v1.ExtraObjectConflict().WithMessage(errors.Errorf(errFmtExtraObjectOwned, "ConfigMap", "cool-config").Error()))
+
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithHooks(NewNopHooks()),
+					WithEstablisher(&MockEstablisher{
+						MockEstablish: func() ([]xpv1.TypedReference, error) {
+							t.Errorf("Establish should not be called when an extra object conflicts")
+							return nil, nil
+						},
+					}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerExtraObjectsBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				err: errors.Errorf(errFmtExtraObjectOwned, "ConfigMap", "cool-config"),
+			},
+		},
 		"SuccessfulActiveRevision": {
 			reason: "An active revision should establish control of all of its resources.",
 			args: args{
@@ -1109,11 +1277,16 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Healthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
+								if o.(*v1.ConfigurationRevision).GetLastPullTime() == nil {
+									t.Errorf("LastPullTime was not set after pulling the package image")
+								}
 								return nil
 							}),
 							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
@@ -1121,7 +1294,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1152,6 +1325,211 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"SuccessfulActiveRevisionObservedGeneration": {
+			reason: "A healthy revision should record the generation it observed, even though updating its metadata overwrites its status.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetGeneration(3)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetGeneration(3)
+								want.SetObservedGeneration(3)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
+								want.SetConditions(v1.Healthy())
+
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								// The API server returns the status it has
+								// stored, which predates this reconcile.
+								o.(*v1.ConfigurationRevision).Status = v1.PackageRevisionStatus{}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithHooks(NewNopHooks()),
+					WithEstablisher(NewMockEstablisher()),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulActiveProviderRevisionRequiredPermissions": {
+			reason: "A provider revision should record the RBAC rules its controller will be granted.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
+								want.SetRequiredPermissions(roles.RenderSystemRules([]roles.Resource{{Group: "example.org", Plural: "examples"}}, nil))
+								want.SetConditions(v1.Healthy())
+
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								if o.(*v1.ProviderRevision).GetLastPullTime() == nil {
+									t.Errorf("LastPullTime was not set after pulling the package image")
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithHooks(NewNopHooks()),
+					WithEstablisher(NewMockEstablisher()),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerCRDBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"SuccessfulActiveProviderRevisionObjectSelector": {
+			reason: "A provider revision should neither apply nor grant its controller permissions for objects its object selector excludes.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ProviderRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								pr.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionActive)
+								pr.SetObjectSelector(&v1.ObjectSelector{Exclude: []v1.ObjectFilter{{Kind: "CustomResourceDefinition"}}})
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
+								want.SetObjectSelector(&v1.ObjectSelector{Exclude: []v1.ObjectFilter{{Kind: "CustomResourceDefinition"}}})
+								want.SetRequiredPermissions(roles.RenderSystemRules([]roles.Resource{}, nil))
+								want.SetConditions(v1.ObjectsExcluded().WithMessage("objects excluded by object selector: CustomResourceDefinition/examples.example.org"))
+								want.SetConditions(v1.Healthy())
+
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								if o.(*v1.ProviderRevision).GetLastPullTime() == nil {
+									t.Errorf("LastPullTime was not set after pulling the package image")
+								}
+								return nil
+							}),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ProviderRevision{}
+								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionActive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetObjectSelector(&v1.ObjectSelector{Exclude: []v1.ObjectFilter{{Kind: "CustomResourceDefinition"}}})
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithHooks(NewNopHooks()),
+					WithEstablisher(NewMockEstablisher()),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerCRDBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulActiveRevisionIgnoreConstraints": {
 			reason: "An active revision with incompatible Crossplane version should install successfully when constraints ignored.",
 			args: args{
@@ -1173,10 +1551,12 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Healthy())
 								want.SetIgnoreCrossplaneConstraints(&trueVal)
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1188,7 +1568,7 @@ func TestReconcile(t *testing.T) {
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
 								want.SetIgnoreCrossplaneConstraints(&trueVal)
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1239,9 +1619,11 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1252,7 +1634,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ProviderRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionActive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1303,9 +1685,11 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Healthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1315,7 +1699,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1366,9 +1750,11 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
 								want.SetConditions(v1.Unhealthy())
 
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1379,7 +1765,7 @@ func TestReconcile(t *testing.T) {
 								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
 								want.SetDesiredState(v1.PackageRevisionInactive)
 								want.SetAnnotations(map[string]string{"author": "crossplane"})
-								if diff := cmp.Diff(want, o); diff != "" {
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
 									t.Errorf("-want, +got:\n%s", diff)
 								}
 								return nil
@@ -1410,6 +1796,72 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errEstablishControl),
 			},
 		},
+		"AwaitCRDsBeforeEstablishing": {
+			reason: "We should requeue and report that we're waiting if we can't establish custom resources until their CRDs are established.",
+			args: args{
+				mgr: &fake.Manager{},
+				req: reconcile.Request{NamespacedName: types.NamespacedName{Name: "test"}},
+				rec: []ReconcilerOption{
+					WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ConfigurationRevision)
+								pr.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								pr.SetDesiredState(v1.PackageRevisionInactive)
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionInactive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								want.SetCrossplaneVersionConstraint(">v0.13.0")
+								want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
+								want.SetConditions(v1.AwaitingCRDs().WithMessage("waiting for CRDs to be established: things.example.org"))
+
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil),
+							MockUpdate: test.NewMockUpdateFn(nil, func(o client.Object) error {
+								want := &v1.ConfigurationRevision{}
+								want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+								want.SetDesiredState(v1.PackageRevisionInactive)
+								want.SetAnnotations(map[string]string{"author": "crossplane"})
+								if diff := cmp.Diff(want, o, ignoreLastPullTime); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
+						},
+					}),
+					WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+						return nil
+					}}),
+					WithHooks(NewNopHooks()),
+					WithEstablisher(&MockEstablisher{
+						MockEstablish: NewMockEstablishFn(nil, &CRDsNotEstablishedError{CRDs: []string{"things.example.org"}}),
+					}),
+					WithParser(parser.New(metaScheme, objScheme)),
+					WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+					WithCache(&xpkgfake.MockCache{
+						MockHas: xpkgfake.NewMockCacheHasFn(false),
+						MockStore: func(s string, rc io.ReadCloser) error {
+							_, err := io.ReadAll(rc)
+							return err
+						},
+					}),
+					WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+					WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{RequeueAfter: establishWait},
+			},
+		},
 	}
 
 	for name, tc := range cases {
@@ -1426,3 +1878,286 @@ func TestReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileAwaitsEstablishedCRDs(t *testing.T) {
+	errBoom := errors.New("boom")
+	metaScheme, _ := xpkg.BuildMetaScheme()
+	objScheme, _ := xpkg.BuildObjectScheme()
+	ignoreLastPullTime := cmpopts.IgnoreFields(v1.PackageRevisionStatus{}, "LastPullTime")
+
+	refs := []xpv1.TypedReference{{
+		APIVersion: extv1.SchemeGroupVersion.String(),
+		Kind:       "CustomResourceDefinition",
+		Name:       "things.example.org",
+	}}
+
+	// crd is the fake CRD the API server serves. Steps may mutate it to
+	// transition it to Established.
+	crd := &extv1.CustomResourceDefinition{}
+	established := func() {
+		crd.Status.Conditions = []extv1.CustomResourceDefinitionCondition{{
+			Type:   extv1.Established,
+			Status: extv1.ConditionTrue,
+		}}
+	}
+
+	type want struct {
+		r    reconcile.Result
+		err  error
+		cond xpv1.Condition
+	}
+
+	steps := []struct {
+		reason string
+		before func()
+		getErr error
+		want   want
+	}{
+		{
+			reason: "We should report that we're waiting for CRDs that aren't yet established.",
+			want: want{
+				r:    reconcile.Result{RequeueAfter: establishWait},
+				cond: v1.AwaitingCRDs().WithMessage("waiting for CRDs to be established: things.example.org"),
+			},
+		},
+		{
+			reason: "We should return any error encountered getting a CRD.",
+			getErr: errBoom,
+			want: want{
+				err:  errors.Wrap(errBoom, errGetCRD),
+				cond: v1.UnknownHealth(),
+			},
+		},
+		{
+			reason: "We should report the revision healthy once its CRDs are established.",
+			before: established,
+			want: want{
+				r:    reconcile.Result{Requeue: false},
+				cond: v1.Healthy(),
+			},
+		},
+	}
+
+	for i, step := range steps {
+		if step.before != nil {
+			step.before()
+		}
+		rec := NewReconciler(&fake.Manager{},
+			WithLogger(logging.NewNopLogger()),
+			WithNewPackageRevisionFn(func() v1.PackageRevision { return &v1.ConfigurationRevision{} }),
+			WithClientApplicator(resource.ClientApplicator{
+				Client: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+						switch o := o.(type) {
+						case *v1.ConfigurationRevision:
+							o.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+							o.SetDesiredState(v1.PackageRevisionActive)
+						case *extv1.CustomResourceDefinition:
+							if step.getErr != nil {
+								return step.getErr
+							}
+							crd.DeepCopyInto(o)
+						}
+						return nil
+					}),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+						want := &v1.ConfigurationRevision{}
+						want.SetGroupVersionKind(v1.ConfigurationRevisionGroupVersionKind)
+						want.SetDesiredState(v1.PackageRevisionActive)
+						want.SetAnnotations(map[string]string{"author": "crossplane"})
+						want.SetCrossplaneVersionConstraint(">v0.13.0")
+						want.SetPackageMeta(&v1.PackageMeta{Crossplane: ">v0.13.0"})
+						want.SetObjects(refs)
+						want.SetConditions(step.want.cond)
+
+						if diff := cmp.Diff(want, o, ignoreLastPullTime, test.EquateConditions()); diff != "" {
+							t.Errorf("step %d: -want, +got:\n%s", i, diff)
+						}
+						return nil
+					}),
+					MockUpdate: test.NewMockUpdateFn(nil),
+				},
+			}),
+			WithFinalizer(resource.FinalizerFns{AddFinalizerFn: func(_ context.Context, _ resource.Object) error {
+				return nil
+			}}),
+			WithHooks(NewNopHooks()),
+			WithEstablisher(&MockEstablisher{MockEstablish: NewMockEstablishFn(refs, nil)}),
+			WithParser(parser.New(metaScheme, objScheme)),
+			WithParserBackend(parser.NewEchoBackend(string(providerBytes))),
+			WithCache(&xpkgfake.MockCache{
+				MockHas: xpkgfake.NewMockCacheHasFn(false),
+				MockStore: func(s string, rc io.ReadCloser) error {
+					_, err := io.ReadAll(rc)
+					return err
+				},
+			}),
+			WithLinter(&MockLinter{MockLint: NewMockLintFn(nil)}),
+			WithVersioner(&verfake.MockVersioner{MockInConstraints: verfake.NewMockInConstraintsFn(true, nil)}),
+		)
+
+		got, err := rec.Reconcile(context.Background(), reconcile.Request{})
+		if diff := cmp.Diff(step.want.err, err, test.EquateErrors()); diff != "" {
+			t.Errorf("\nstep %d: %s\nr.Reconcile(...): -want error, +got error:\n%s", i, step.reason, diff)
+		}
+		if diff := cmp.Diff(step.want.r, got); diff != "" {
+			t.Errorf("\nstep %d: %s\nr.Reconcile(...): -want, +got:\n%s", i, step.reason, diff)
+		}
+	}
+}
+
+func TestPullProgressReporter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		messages        []string
+		resourceVersion string
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Throttled": {
+			reason: "Progress should be reported at most once per interval, and the revision's resource version should be updated to match.",
+			want: want{
+				messages:        []string{"pulled 0/3 layers"},
+				resourceVersion: "2",
+			},
+		},
+		"UpdateError": {
+			reason: "The revision's resource version should not be updated if progress could not be reported.",
+			err:    errBoom,
+			want: want{
+				messages:        []string{"pulled 0/3 layers"},
+				resourceVersion: "1",
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			messages := []string{}
+			c := &test.MockClient{
+				MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+					pr := obj.(*v1.ProviderRevision)
+					messages = append(messages, pr.GetCondition(v1.TypeHealthy).Message)
+					pr.SetResourceVersion("2")
+					return tc.err
+				},
+			}
+			pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"}}
+
+			p := newPullProgressReporter(context.Background(), c, pr, time.Hour)
+			p.Report(0, 3)
+			p.Report(1, 3)
+			p.Stop(pr)
+			p.Report(3, 3)
+
+			if diff := cmp.Diff(tc.want.messages, messages); diff != "" {
+				t.Errorf("\n%s\nReport(...): -want messages, +got messages:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.resourceVersion, pr.GetResourceVersion()); diff != "" {
+				t.Errorf("\n%s\nStop(...): -want resource version, +got resource version:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSelectObjects(t *testing.T) {
+	crd := &extv1.CustomResourceDefinition{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition"},
+		ObjectMeta: metav1.ObjectMeta{Name: "examples.example.org"},
+	}
+	cm := &kunstructured.Unstructured{}
+	cm.SetAPIVersion("v1")
+	cm.SetKind("ConfigMap")
+	cm.SetName("cool")
+
+	type want struct {
+		selected []runtime.Object
+		excluded []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		s      *v1.ObjectSelector
+		objs   []runtime.Object
+		want   want
+	}{
+		"NilSelector": {
+			reason: "All objects should be selected if there is no selector.",
+			objs:   []runtime.Object{crd, cm},
+			want: want{
+				selected: []runtime.Object{crd, cm},
+			},
+		},
+		"Excluded": {
+			reason: "Objects the selector doesn't select should be described, not returned.",
+			s:      &v1.ObjectSelector{Exclude: []v1.ObjectFilter{{APIVersion: "v1", Kind: "ConfigMap"}}},
+			objs:   []runtime.Object{crd, cm},
+			want: want{
+				selected: []runtime.Object{crd},
+				excluded: []string{"ConfigMap/cool"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			selected, excluded := selectObjects(tc.s, tc.objs)
+			if diff := cmp.Diff(tc.want.selected, selected); diff != "" {
+				t.Errorf("\n%s\nselectObjects(...): -want selected, +got selected:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.excluded, excluded, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nselectObjects(...): -want excluded, +got excluded:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestIgnoreStatusChanges(t *testing.T) {
+	rev := func(generation int64, failures int64, labels map[string]string) *v1.ProviderRevision {
+		pr := &v1.ProviderRevision{}
+		pr.SetGeneration(generation)
+		pr.SetLabels(labels)
+		pr.SetReconciliationFailureCount(failures)
+		return pr
+	}
+
+	cases := map[string]struct {
+		reason string
+		old    client.Object
+		new    client.Object
+		want   bool
+	}{
+		"StatusChanged": {
+			reason: "An update that only changes the revision's status should be ignored.",
+			old:    rev(1, 0, nil),
+			new:    rev(1, 1, nil),
+			want:   false,
+		},
+		"SpecChanged": {
+			reason: "An update that changes the revision's spec should not be ignored.",
+			old:    rev(1, 0, nil),
+			new:    rev(2, 0, nil),
+			want:   true,
+		},
+		"LabelsChanged": {
+			reason: "An update that changes the revision's labels should not be ignored.",
+			old:    rev(1, 0, nil),
+			new:    rev(1, 0, map[string]string{v1.LabelParentPackage: "cool"}),
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ignoreStatusChanges().Update(ctrlevent.UpdateEvent{ObjectOld: tc.old, ObjectNew: tc.new})
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nUpdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
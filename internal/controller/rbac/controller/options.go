@@ -49,6 +49,22 @@ type Options struct {
 	// provider may request any permission that appears in the named role.
 	AllowClusterRole string
 
+	// AllowedProviderAPIGroups are the API groups Providers may be granted
+	// access to via their system ClusterRoles. Providers may be granted access
+	// to any API group if none are specified.
+	AllowedProviderAPIGroups []string
+
+	// DeniedProviderAPIGroups are the API groups Providers may not be granted
+	// access to via their system ClusterRoles. Denial takes precedence over
+	// AllowedProviderAPIGroups.
+	DeniedProviderAPIGroups []string
+
+	// DisableOfferedRoleBindings stops the RBAC manager from binding the
+	// ClusterRoles that grant access to all offered claims in namespaces that
+	// are labelled for self-service. Useful for clusters that manage these
+	// bindings externally.
+	DisableOfferedRoleBindings bool
+
 	// DefaultRegistry used by the package manager to pull packages. Must match
 	// the package manager's DefaultRegistry in order for the RBAC manager to be
 	// able to determine whether two packages are part of the same registry and
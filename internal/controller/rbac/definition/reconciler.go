@@ -212,12 +212,14 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 }
 
 // ClusterRolesDiffer returns true if the supplied objects are different
-// ClusterRoles. We consider ClusterRoles to be different if their labels and
-// rules do not match.
+// ClusterRoles. We consider ClusterRoles to be different if their labels,
+// claim namespaces, or rules do not match.
 func ClusterRolesDiffer(current, desired runtime.Object) bool {
 	c := current.(*rbacv1.ClusterRole)
 	d := desired.(*rbacv1.ClusterRole)
-	return !cmp.Equal(c.GetLabels(), d.GetLabels()) || !cmp.Equal(c.Rules, d.Rules)
+	return !cmp.Equal(c.GetLabels(), d.GetLabels()) ||
+		c.GetAnnotations()[keyClaimNamespaces] != d.GetAnnotations()[keyClaimNamespaces] ||
+		!cmp.Equal(c.Rules, d.Rules)
 }
 
 func firstNAndSomeMore(names []string) string {
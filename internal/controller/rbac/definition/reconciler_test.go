@@ -232,6 +232,22 @@ func TestClusterRolesDiffer(t *testing.T) {
 			},
 			want: true,
 		},
+		"ClaimNamespacesDiffer": {
+			current: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels:      map[string]string{"a": "a"},
+					Annotations: map[string]string{keyClaimNamespaces: `{"names":["a"]}`},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			desired: &rbacv1.ClusterRole{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"a": "a"},
+				},
+				Rules: []rbacv1.PolicyRule{{}},
+			},
+			want: true,
+		},
 		"RulesDiffer": {
 			current: &rbacv1.ClusterRole{
 				ObjectMeta: metav1.ObjectMeta{
@@ -17,6 +17,8 @@ limitations under the License.
 package definition
 
 import (
+	"encoding/json"
+
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -32,6 +34,10 @@ const (
 	nameSuffixView   = ":aggregate-to-view"
 	nameSuffixBrowse = ":aggregate-to-browse"
 
+	namePrefixOffered     = "crossplane:offered:"
+	nameSuffixOfferedEdit = ":edit"
+	nameSuffixOfferedView = ":view"
+
 	keyAggregateToSystem = "rbac.crossplane.io/aggregate-to-crossplane"
 
 	keyAggregateToAdmin   = "rbac.crossplane.io/aggregate-to-admin"
@@ -45,8 +51,21 @@ const (
 
 	keyAggregateToBrowse = "rbac.crossplane.io/aggregate-to-browse"
 
+	keyAggregateToOfferedEdit = "rbac.crossplane.io/aggregate-to-offered-edit"
+	keyAggregateToOfferedView = "rbac.crossplane.io/aggregate-to-offered-view"
+
+	// Kubernetes aggregates ClusterRoles with these labels into its built-in
+	// admin, edit, and view ClusterRoles.
+	keyAggregateToKubeAdmin = "rbac.authorization.k8s.io/aggregate-to-admin"
+	keyAggregateToKubeEdit  = "rbac.authorization.k8s.io/aggregate-to-edit"
+	keyAggregateToKubeView  = "rbac.authorization.k8s.io/aggregate-to-view"
+
 	keyXRD = "rbac.crossplane.io/xrd"
 
+	// The RBAC manager only aggregates rules from ClusterRoles with this
+	// annotation into the Roles of the namespaces it allows.
+	keyClaimNamespaces = "rbac.crossplane.io/claim-namespaces"
+
 	valTrue = "true"
 
 	suffixStatus     = "/status"
@@ -176,11 +195,75 @@ func RenderClusterRoles(d *v1.CompositeResourceDefinition) []rbacv1.ClusterRole
 		})
 
 		// The browse role only includes composite resources; not claims.
+
+		if d.Spec.ClaimNamespaces != nil {
+			// Marshalling a struct of strings and a label selector can't fail.
+			j, _ := json.Marshal(d.Spec.ClaimNamespaces)
+			meta.AddAnnotations(edit, map[string]string{keyClaimNamespaces: string(j)})
+			meta.AddAnnotations(view, map[string]string{keyClaimNamespaces: string(j)})
+		}
 	}
 
-	for _, o := range []metav1.Object{system, edit, view, browse} {
+	roles := []*rbacv1.ClusterRole{system, edit, view, browse}
+	if d.Spec.ClaimNames != nil {
+		roles = append(roles, renderOfferedClusterRoles(d)...)
+	}
+
+	out := make([]rbacv1.ClusterRole, len(roles))
+	for i, o := range roles {
 		meta.AddOwnerReference(o, meta.AsController(meta.TypedReferenceTo(d, v1.CompositeResourceDefinitionGroupVersionKind)))
+		out[i] = *o
+	}
+
+	return out
+}
+
+// renderOfferedClusterRoles returns ClusterRoles that grant access to the claim
+// the supplied XRD offers. They aggregate to the built-in Kubernetes admin,
+// edit, and view ClusterRoles, so that anyone who may edit or view a namespace
+// may edit or view the claims in it. They also aggregate to the Crossplane
+// offered edit and view ClusterRoles, which the RBAC manager binds in
+// namespaces that are labelled for self-service. Claim rules don't specify a
+// version, so they don't change when the XRD's served versions do.
+func renderOfferedClusterRoles(d *v1.CompositeResourceDefinition) []*rbacv1.ClusterRole {
+	edit := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namePrefixOffered + d.GetName() + nameSuffixOfferedEdit,
+			Labels: map[string]string{
+				keyAggregateToKubeAdmin:   valTrue,
+				keyAggregateToKubeEdit:    valTrue,
+				keyAggregateToOfferedEdit: valTrue,
+
+				keyXRD: d.GetName(),
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{d.Spec.Group},
+				Resources: []string{d.Spec.ClaimNames.Plural},
+				Verbs:     verbsEdit,
+			},
+		},
+	}
+
+	view := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: namePrefixOffered + d.GetName() + nameSuffixOfferedView,
+			Labels: map[string]string{
+				keyAggregateToKubeView:    valTrue,
+				keyAggregateToOfferedView: valTrue,
+
+				keyXRD: d.GetName(),
+			},
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{d.Spec.Group},
+				Resources: []string{d.Spec.ClaimNames.Plural},
+				Verbs:     verbsView,
+			},
+		},
 	}
 
-	return []rbacv1.ClusterRole{*system, *edit, *view, *browse}
+	return []*rbacv1.ClusterRole{edit, view}
 }
@@ -241,6 +241,43 @@ func TestRenderClusterRoles(t *testing.T) {
 						},
 					},
 				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            namePrefixOffered + name + nameSuffixOfferedEdit,
+						OwnerReferences: []metav1.OwnerReference{owner},
+						Labels: map[string]string{
+							keyAggregateToKubeAdmin:   valTrue,
+							keyAggregateToKubeEdit:    valTrue,
+							keyAggregateToOfferedEdit: valTrue,
+							keyXRD:                    name,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXRC},
+							Verbs:     verbsEdit,
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:            namePrefixOffered + name + nameSuffixOfferedView,
+						OwnerReferences: []metav1.OwnerReference{owner},
+						Labels: map[string]string{
+							keyAggregateToKubeView:    valTrue,
+							keyAggregateToOfferedView: valTrue,
+							keyXRD:                    name,
+						},
+					},
+					Rules: []rbacv1.PolicyRule{
+						{
+							APIGroups: []string{group},
+							Resources: []string{pluralXRC},
+							Verbs:     verbsView,
+						},
+					},
+				},
 			},
 		},
 	}
@@ -254,3 +291,35 @@ func TestRenderClusterRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderClusterRolesClaimNamespaces(t *testing.T) {
+	d := &v1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "coolcomposites.example.org"},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:           "example.org",
+			Names:           extv1.CustomResourceDefinitionNames{Plural: "coolcomposites"},
+			ClaimNames:      &extv1.CustomResourceDefinitionNames{Plural: "coolclaims"},
+			ClaimNamespaces: &v1.ClaimNamespaces{Names: []string{"tenant-a"}},
+		},
+	}
+
+	// Only the edit and view roles include claims, so only they should be
+	// restricted to the namespaces that allow claims. The offered roles are
+	// aggregated by Kubernetes, which can't restrict them to namespaces.
+	want := map[string]string{
+		namePrefix + d.GetName() + nameSuffixSystem:             "",
+		namePrefix + d.GetName() + nameSuffixEdit:               `{"names":["tenant-a"]}`,
+		namePrefix + d.GetName() + nameSuffixView:               `{"names":["tenant-a"]}`,
+		namePrefix + d.GetName() + nameSuffixBrowse:             "",
+		namePrefixOffered + d.GetName() + nameSuffixOfferedEdit: "",
+		namePrefixOffered + d.GetName() + nameSuffixOfferedView: "",
+	}
+
+	got := map[string]string{}
+	for _, cr := range RenderClusterRoles(d) {
+		got[cr.GetName()] = cr.GetAnnotations()[keyClaimNamespaces]
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RenderClusterRoles(...): -want claim namespaces annotation, +got:\n%s", diff)
+	}
+}
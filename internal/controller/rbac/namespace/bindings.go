@@ -0,0 +1,95 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"fmt"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+)
+
+const (
+	// The RBAC manager aggregates the ClusterRoles it renders for each claim
+	// offered by an XRD into these ClusterRoles.
+	nameOfferedEdit = "crossplane:offered:edit"
+	nameOfferedView = "crossplane:offered:view"
+
+	// The RBAC manager binds the offered ClusterRoles in namespaces with this
+	// label set to "true".
+	keySelfService = keyPrefix + "self-service"
+
+	// The offered ClusterRoles are bound to groups named for the namespace,
+	// e.g. crossplane:offered:tenant-a:edit.
+	fmtOfferedGroup = "crossplane:offered:%s:%s"
+)
+
+// SelfService returns true if the supplied namespace is labelled for
+// self-service, i.e. if the offered ClusterRoles should be bound in it.
+func SelfService(ns *corev1.Namespace) bool {
+	return ns.GetLabels()[keySelfService] == valTrue
+}
+
+// RenderRoleBindings for the supplied namespace. The RoleBindings bind the
+// offered edit and view ClusterRoles, which grant access to all claims offered
+// by XRDs, to groups named for the namespace. Identity providers may add users
+// to these groups to let them manage claims in the namespace.
+func RenderRoleBindings(ns *corev1.Namespace) []rbacv1.RoleBinding {
+	gvk := schema.GroupVersionKind{Version: "v1", Kind: "Namespace"}
+
+	bindings := make([]rbacv1.RoleBinding, 0, 2)
+	for _, b := range []struct{ role, access string }{
+		{role: nameOfferedEdit, access: "edit"},
+		{role: nameOfferedView, access: "view"},
+	} {
+		rb := &rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns.GetName(),
+				Name:      b.role,
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     "ClusterRole",
+				Name:     b.role,
+			},
+			Subjects: []rbacv1.Subject{{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.GroupKind,
+				Name:     fmt.Sprintf(fmtOfferedGroup, ns.GetName(), b.access),
+			}},
+		}
+		meta.AddOwnerReference(rb, meta.AsController(meta.TypedReferenceTo(ns, gvk)))
+		bindings = append(bindings, *rb)
+	}
+
+	return bindings
+}
+
+// RoleBindingsDiffer returns true if the supplied objects are different
+// RoleBindings. We consider RoleBindings to be different if their subjects do
+// not match. Their role references are immutable.
+func RoleBindingsDiffer(current, desired runtime.Object) bool {
+	c := current.(*rbacv1.RoleBinding)
+	d := desired.(*rbacv1.RoleBinding)
+	return !cmp.Equal(c.Subjects, d.Subjects)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package namespace
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+)
+
+func TestRenderRoleBindings(t *testing.T) {
+	name := "tenant-a"
+	uid := types.UID("no-you-id")
+
+	owner := metav1.OwnerReference{
+		APIVersion:         "v1",
+		Kind:               "Namespace",
+		Name:               name,
+		UID:                uid,
+		Controller:         pointer.Bool(true),
+		BlockOwnerDeletion: pointer.Bool(true),
+	}
+
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, UID: uid}}
+	want := []rbacv1.RoleBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       name,
+				Name:            nameOfferedEdit,
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: nameOfferedEdit},
+			Subjects: []rbacv1.Subject{{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.GroupKind,
+				Name:     "crossplane:offered:tenant-a:edit",
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       name,
+				Name:            nameOfferedView,
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			RoleRef: rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "ClusterRole", Name: nameOfferedView},
+			Subjects: []rbacv1.Subject{{
+				APIGroup: rbacv1.GroupName,
+				Kind:     rbacv1.GroupKind,
+				Name:     "crossplane:offered:tenant-a:view",
+			}},
+		},
+	}
+
+	got := RenderRoleBindings(ns)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("RenderRoleBindings(...): -want, +got:\n%s", diff)
+	}
+}
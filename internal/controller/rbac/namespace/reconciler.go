@@ -27,7 +27,10 @@ import (
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -48,11 +51,16 @@ const (
 	errGetNamespace = "cannot get Namespace"
 	errApplyRole    = "cannot apply Roles"
 	errListRoles    = "cannot list ClusterRoles"
+
+	errApplyRoleBinding  = "cannot apply RoleBindings"
+	errGetRoleBinding    = "cannot get RoleBinding"
+	errDeleteRoleBinding = "cannot delete RoleBinding"
 )
 
 // Event reasons.
 const (
-	reasonApplyRoles event.Reason = "ApplyRoles"
+	reasonApplyRoles        event.Reason = "ApplyRoles"
+	reasonApplyRoleBindings event.Reason = "ApplyRoleBindings"
 )
 
 // A RoleRenderer renders Roles for a given Namespace.
@@ -75,14 +83,20 @@ func (fn RoleRenderFn) RenderRoles(d *corev1.Namespace, crs []rbacv1.ClusterRole
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := "rbac/namespace"
 
-	r := NewReconciler(mgr,
+	opts := []ReconcilerOption{
 		WithLogger(o.Logger.WithValues("controller", name)),
-		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+	}
+	if !o.DisableOfferedRoleBindings {
+		opts = append(opts, WithOfferedRoleBindings())
+	}
+	r := NewReconciler(mgr, opts...)
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&corev1.Namespace{}).
 		Owns(&rbacv1.Role{}).
+		Owns(&rbacv1.RoleBinding{}).
 		Watches(&rbacv1.ClusterRole{}, &EnqueueRequestForNamespaces{client: mgr.GetClient()}).
 		WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
@@ -121,6 +135,14 @@ func WithRoleRenderer(rr RoleRenderer) ReconcilerOption {
 	}
 }
 
+// WithOfferedRoleBindings specifies that the Reconciler should bind the offered
+// ClusterRoles in namespaces that are labelled for self-service.
+func WithOfferedRoleBindings() ReconcilerOption {
+	return func(r *Reconciler) {
+		r.bindOffered = true
+	}
+}
+
 // NewReconciler returns a Reconciler of Namespaces.
 func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
@@ -147,6 +169,8 @@ type Reconciler struct {
 	client resource.ClientApplicator
 	rbac   RoleRenderer
 
+	bindOffered bool
+
 	log    logging.Logger
 	record event.Recorder
 }
@@ -221,9 +245,63 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(ns, event.Normal(reasonApplyRoles, fmt.Sprintf("Applied RBAC Roles: %s", firstNAndSomeMore(applied))))
 	}
 
+	if !r.bindOffered || !SelfService(ns) {
+		// Delete any RoleBindings we created before the namespace stopped
+		// being labelled for self-service, or binding was disabled.
+		return reconcile.Result{Requeue: false}, r.unbindOffered(ctx, ns)
+	}
+
+	applied = nil
+	for _, rb := range RenderRoleBindings(ns) {
+		log := log.WithValues("role-binding-name", rb.GetName())
+		rb := rb // Pin range variable so we can take its address.
+
+		err := r.client.Apply(ctx, &rb, resource.MustBeControllableBy(ns.GetUID()), resource.AllowUpdateIf(RoleBindingsDiffer))
+		if resource.IsNotAllowed(err) {
+			log.Debug("Skipped no-op RBAC RoleBinding apply")
+			continue
+		}
+		if err != nil {
+			log.Debug(errApplyRoleBinding, "error", err)
+			err = errors.Wrap(err, errApplyRoleBinding)
+			r.record.Event(ns, event.Warning(reasonApplyRoleBindings, err))
+			return reconcile.Result{}, err
+		}
+
+		log.Debug("Applied RBAC RoleBinding")
+		applied = append(applied, rb.GetName())
+	}
+
+	if len(applied) > 0 {
+		r.record.Event(ns, event.Normal(reasonApplyRoleBindings, fmt.Sprintf("Applied RBAC RoleBindings: %s", strings.Join(applied, ", "))))
+	}
+
 	return reconcile.Result{Requeue: false}, nil
 }
 
+// unbindOffered deletes any offered ClusterRole RoleBindings the supplied
+// namespace controls. RoleBindings with the same names that were created by
+// something else are left alone.
+func (r *Reconciler) unbindOffered(ctx context.Context, ns *corev1.Namespace) error {
+	for _, name := range []string{nameOfferedEdit, nameOfferedView} {
+		rb := &rbacv1.RoleBinding{}
+		err := r.client.Get(ctx, types.NamespacedName{Namespace: ns.GetName(), Name: name}, rb)
+		if kerrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrap(err, errGetRoleBinding)
+		}
+		if !metav1.IsControlledBy(rb, ns) {
+			continue
+		}
+		if err := r.client.Delete(ctx, rb); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteRoleBinding)
+		}
+	}
+	return nil
+}
+
 // RolesDiffer returns true if the supplied objects are different Roles. We
 // consider Roles to be different if their crossplane annotations or rules do not match.
 func RolesDiffer(current, desired runtime.Object) bool {
@@ -28,6 +28,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -198,6 +199,101 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"ApplyRoleBindingError": {
+			reason: "We should return an error encountered applying a RoleBinding in a namespace labelled for self-service.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithOfferedRoleBindings(),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.SetLabels(map[string]string{keySelfService: valTrue})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*rbacv1.RoleBinding); ok {
+								return errBoom
+							}
+							return nil
+						}),
+					}),
+					WithRoleRenderer(RoleRenderFn(func(*corev1.Namespace, []rbacv1.ClusterRole) []rbacv1.Role {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errApplyRoleBinding),
+			},
+		},
+		"SuccessfulUnbind": {
+			reason: "We should delete RoleBindings we control in a namespace that is not labelled for self-service.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithOfferedRoleBindings(),
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch o := o.(type) {
+								case *corev1.Namespace:
+									o.SetUID("ns-uid")
+								case *rbacv1.RoleBinding:
+									o.SetOwnerReferences([]metav1.OwnerReference{{UID: "ns-uid", Controller: pointer.Bool(true)}})
+								}
+								return nil
+							}),
+							MockList:   test.NewMockListFn(nil),
+							MockDelete: test.NewMockDeleteFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							if _, ok := o.(*rbacv1.RoleBinding); ok {
+								t.Errorf("unexpected RoleBinding apply")
+							}
+							return nil
+						}),
+					}),
+					WithRoleRenderer(RoleRenderFn(func(*corev1.Namespace, []rbacv1.ClusterRole) []rbacv1.Role {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"DeleteRoleBindingError": {
+			reason: "We should return an error encountered deleting a RoleBinding we control.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch o := o.(type) {
+								case *corev1.Namespace:
+									o.SetUID("ns-uid")
+								case *rbacv1.RoleBinding:
+									o.SetOwnerReferences([]metav1.OwnerReference{{UID: "ns-uid", Controller: pointer.Bool(true)}})
+								}
+								return nil
+							}),
+							MockList:   test.NewMockListFn(nil),
+							MockDelete: test.NewMockDeleteFn(errBoom),
+						},
+					}),
+					WithRoleRenderer(RoleRenderFn(func(*corev1.Namespace, []rbacv1.ClusterRole) []rbacv1.Role {
+						return nil
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errDeleteRoleBinding),
+			},
+		},
 	}
 
 	for name, tc := range cases {
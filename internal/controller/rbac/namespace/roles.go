@@ -17,6 +17,7 @@ limitations under the License.
 package namespace
 
 import (
+	"encoding/json"
 	"sort"
 	"strings"
 
@@ -26,6 +27,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
 )
 
 const (
@@ -43,7 +46,8 @@ const (
 	keyBaseOfEdit  = keyPrefix + "base-of-ns-edit"
 	keyBaseOfView  = keyPrefix + "base-of-ns-view"
 
-	keyXRD = keyPrefix + "xrd"
+	keyXRD             = keyPrefix + "xrd"
+	keyClaimNamespaces = keyPrefix + "claim-namespaces"
 
 	keyAggregated = "aggregated-by-crossplane"
 
@@ -92,9 +96,9 @@ func RenderRoles(ns *corev1.Namespace, crs []rbacv1.ClusterRole) []rbacv1.Role {
 		}
 	}
 
-	acrs := crSelector{keyAggToAdmin, keyBaseOfAdmin, accepts}
-	ecrs := crSelector{keyAggToEdit, keyBaseOfEdit, accepts}
-	vcrs := crSelector{keyAggToView, keyBaseOfView, accepts}
+	acrs := crSelector{keyAggToAdmin, keyBaseOfAdmin, accepts, ns}
+	ecrs := crSelector{keyAggToEdit, keyBaseOfEdit, accepts, ns}
+	vcrs := crSelector{keyAggToView, keyBaseOfView, accepts, ns}
 
 	// TODO(negz): Annotate rendered Roles to indicate which ClusterRoles they
 	// are aggregating rules from? This aggregation is likely to be surprising
@@ -120,6 +124,7 @@ type crSelector struct {
 	keyAgg  string
 	keyBase string
 	accepts map[string]bool
+	ns      *corev1.Namespace
 }
 
 func (s crSelector) Select(cr rbacv1.ClusterRole) bool {
@@ -132,5 +137,24 @@ func (s crSelector) Select(cr rbacv1.ClusterRole) bool {
 
 	// Cluster roles must either be the base of this role, or pertain to an XRD
 	// that this namespace accepts a claim from.
-	return l[s.keyBase] == valTrue || s.accepts[l[keyXRD]]
+	if l[s.keyBase] == valTrue {
+		return true
+	}
+	return s.accepts[l[keyXRD]] && s.allows(cr)
+}
+
+// allows returns true if the XRD the supplied cluster role pertains to allows
+// claims in this namespace. Cluster roles with claim namespaces we can't parse
+// aren't selected.
+func (s crSelector) allows(cr rbacv1.ClusterRole) bool {
+	a, ok := cr.GetAnnotations()[keyClaimNamespaces]
+	if !ok {
+		return true
+	}
+	cn := &v1.ClaimNamespaces{}
+	if err := json.Unmarshal([]byte(a), cn); err != nil {
+		return false
+	}
+	allowed, err := cn.Allows(s.ns.GetName(), s.ns.GetLabels())
+	return err == nil && allowed
 }
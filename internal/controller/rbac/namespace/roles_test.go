@@ -33,6 +33,7 @@ func TestCRSelector(t *testing.T) {
 		keyAgg  string
 		keyBase string
 		accepts map[string]bool
+		ns      *corev1.Namespace
 	}
 
 	cases := map[string]struct {
@@ -104,11 +105,45 @@ func TestCRSelector(t *testing.T) {
 			}}},
 			want: false,
 		},
+		"IsAllowedXRDRole": {
+			reason: "ClusterRoles of an accepted XRD that allows claims in this namespace should be selected",
+			fields: fields{
+				keyAgg:  keyAggToAdmin,
+				keyBase: keyBaseOfAdmin,
+				accepts: map[string]bool{xrdName: true},
+				ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-a", Labels: map[string]string{"tenant": "true"}}},
+			},
+			cr: rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					keyAggToAdmin: valTrue,
+					keyXRD:        xrdName,
+				},
+				Annotations: map[string]string{keyClaimNamespaces: `{"selector":{"matchLabels":{"tenant":"true"}}}`},
+			}},
+			want: true,
+		},
+		"IsDisallowedXRDRole": {
+			reason: "ClusterRoles of an accepted XRD that doesn't allow claims in this namespace should be ignored",
+			fields: fields{
+				keyAgg:  keyAggToAdmin,
+				keyBase: keyBaseOfAdmin,
+				accepts: map[string]bool{xrdName: true},
+				ns:      &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			},
+			cr: rbacv1.ClusterRole{ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{
+					keyAggToAdmin: valTrue,
+					keyXRD:        xrdName,
+				},
+				Annotations: map[string]string{keyClaimNamespaces: `{"names":["tenant-a"]}`},
+			}},
+			want: false,
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			crs := crSelector{tc.fields.keyAgg, tc.fields.keyBase, tc.fields.accepts}
+			crs := crSelector{tc.fields.keyAgg, tc.fields.keyBase, tc.fields.accepts, tc.fields.ns}
 			got := crs.Select(tc.cr)
 			if diff := cmp.Diff(tc.want, got); diff != "" {
 				t.Errorf("crs.Select(...): -want, +got:\n%s\n", diff)
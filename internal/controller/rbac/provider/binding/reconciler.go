@@ -29,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -51,8 +52,12 @@ const (
 	errGetPR        = "cannot get ProviderRevision"
 	errListSAs      = "cannot list ServiceAccounts"
 	errApplyBinding = "cannot apply ClusterRoleBinding"
+	errApplyRB      = "cannot apply RoleBinding"
+	errListRBs      = "cannot list RoleBindings"
+	errDeleteRB     = "cannot delete RoleBinding"
 
 	kindClusterRole = "ClusterRole"
+	kindRole        = "Role"
 )
 
 // Event reasons.
@@ -74,6 +79,7 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		Named(name).
 		For(&v1.ProviderRevision{}).
 		Owns(&rbacv1.ClusterRoleBinding{}).
+		Owns(&rbacv1.RoleBinding{}).
 		Watches(&corev1.ServiceAccount{}, handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &v1.ProviderRevision{})).
 		WithOptions(o.ForControllerRuntime()).
 		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
@@ -167,6 +173,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug(errListSAs, "error", err)
 		err = errors.Wrap(err, errListSAs)
 		r.record.Event(pr, event.Warning(reasonBind, err))
+		roles.CountError(roles.StageBind)
 		return reconcile.Result{}, err
 	}
 
@@ -190,6 +197,18 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 
 	n := roles.SystemClusterRoleName(pr.GetName())
 	ref := meta.AsController(meta.TypedReferenceTo(pr, v1.ProviderRevisionGroupVersionKind))
+
+	// A provider whose RBAC is scoped to namespaces is also bound to a
+	// 'system' Role in each namespace. Its 'system' ClusterRole grants access
+	// only to the cluster scoped resources it defines.
+	namespaces := roles.Namespaces(pr)
+	if err := r.applyRoleBindings(ctx, pr, RenderRoleBindings(pr, subjects, namespaces)); err != nil {
+		log.Debug("Cannot apply RoleBindings", "error", err)
+		r.record.Event(pr, event.Warning(reasonBind, err))
+		roles.CountError(roles.StageBind)
+		return reconcile.Result{}, err
+	}
+
 	rb := &rbacv1.ClusterRoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:            n,
@@ -218,6 +237,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug(errApplyBinding, "error", err)
 		err = errors.Wrap(err, errApplyBinding)
 		r.record.Event(pr, event.Warning(reasonBind, err))
+		roles.CountError(roles.StageBind)
 		return reconcile.Result{}, err
 	}
 
@@ -228,6 +248,67 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 	return reconcile.Result{Requeue: false}, nil
 }
 
+// RenderRoleBindings returns a RoleBinding in each of the supplied namespaces
+// that binds the supplied subjects to the supplied revision's 'system' Role.
+func RenderRoleBindings(pr *v1.ProviderRevision, subjects []rbacv1.Subject, namespaces []string) []rbacv1.RoleBinding {
+	n := roles.SystemClusterRoleName(pr.GetName())
+	rbs := make([]rbacv1.RoleBinding, 0, len(namespaces))
+	for _, ns := range namespaces {
+		rbs = append(rbs, rbacv1.RoleBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ns,
+				Name:            n,
+				Labels:          map[string]string{roles.KeyProviderRevision: pr.GetName()},
+				OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(pr, v1.ProviderRevisionGroupVersionKind))},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: rbacv1.GroupName,
+				Kind:     kindRole,
+				Name:     n,
+			},
+			Subjects: subjects,
+		})
+	}
+	return rbs
+}
+
+// applyRoleBindings applies the supplied RoleBindings of the supplied
+// revision, and deletes any of its RoleBindings that are no longer desired.
+func (r *Reconciler) applyRoleBindings(ctx context.Context, pr *v1.ProviderRevision, rbs []rbacv1.RoleBinding) error {
+	desired := make(map[string]bool, len(rbs))
+	for _, rb := range rbs {
+		rb := rb // Pin range variable so we can take its address.
+		desired[rb.GetNamespace()] = true
+		err := r.client.Apply(ctx, &rb, resource.MustBeControllableBy(pr.GetUID()), resource.AllowUpdateIf(RoleBindingsDiffer))
+		if err != nil && !resource.IsNotAllowed(err) {
+			return errors.Wrap(err, errApplyRB)
+		}
+	}
+
+	l := &rbacv1.RoleBindingList{}
+	if err := r.client.List(ctx, l, client.MatchingLabels{roles.KeyProviderRevision: pr.GetName()}); err != nil {
+		return errors.Wrap(err, errListRBs)
+	}
+	for i := range l.Items {
+		if desired[l.Items[i].GetNamespace()] {
+			continue
+		}
+		if err := r.client.Delete(ctx, &l.Items[i]); resource.IgnoreNotFound(err) != nil {
+			return errors.Wrap(err, errDeleteRB)
+		}
+	}
+	return nil
+}
+
+// RoleBindingsDiffer returns true if the supplied objects are different
+// RoleBindings. We consider RoleBindings to be different if the subjects, the
+// roleRefs, or the owner ref is different.
+func RoleBindingsDiffer(current, desired runtime.Object) bool {
+	c := current.(*rbacv1.RoleBinding)
+	d := desired.(*rbacv1.RoleBinding)
+	return !cmp.Equal(c.Subjects, d.Subjects) || !cmp.Equal(c.RoleRef, d.RoleRef) || !cmp.Equal(c.GetOwnerReferences(), d.GetOwnerReferences())
+}
+
 // ClusterRoleBindingsDiffer returns true if the supplied objects are different ClusterRoleBindings. We
 // consider ClusterRoleBindings to be different if the subjects, the roleRefs, or the owner ref
 // is different.
@@ -23,6 +23,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -38,6 +39,7 @@ import (
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 
 	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"github.com/crossplane/crossplane/internal/controller/rbac/provider/roles"
 )
 
 func TestReconcile(t *testing.T) {
@@ -157,6 +159,53 @@ func TestReconcile(t *testing.T) {
 				err: errors.Wrap(errBoom, errApplyBinding),
 			},
 		},
+		"ScopedToNamespaces": {
+			reason: "We should bind the system Role in each namespace as well as the system ClusterRole, and delete any RoleBindings that are no longer desired.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.SetName("cool")
+								o.SetAnnotations(map[string]string{v1.AnnotationRBACNamespaces: "a"})
+								return nil
+							}),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								if l, ok := o.(*rbacv1.RoleBindingList); ok {
+									l.Items = []rbacv1.RoleBinding{{ObjectMeta: metav1.ObjectMeta{Namespace: "b", Name: roles.SystemClusterRoleName("cool")}}}
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*rbacv1.RoleBinding); !ok || o.GetNamespace() != "b" {
+									t.Errorf("deleted %T %s/%s, want only the undesired RoleBinding in namespace b", o, o.GetNamespace(), o.GetName())
+								}
+								return nil
+							}),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							switch rb := o.(type) {
+							case *rbacv1.RoleBinding:
+								if rb.GetNamespace() != "a" || rb.RoleRef.Kind != kindRole {
+									t.Errorf("applied RoleBinding %s/%s to %s, want a RoleBinding in namespace a to a Role", rb.GetNamespace(), rb.GetName(), rb.RoleRef.Kind)
+								}
+							case *rbacv1.ClusterRoleBinding:
+								if rb.RoleRef.Kind != kindClusterRole {
+									t.Errorf("applied ClusterRoleBinding %s to %s, want a ClusterRoleBinding to a ClusterRole", rb.GetName(), rb.RoleRef.Kind)
+								}
+							default:
+								t.Errorf("applied unexpected %T", o)
+							}
+							return nil
+						}),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulApply": {
 			reason: "We should not requeue when we successfully apply our ClusterRoleBindings.",
 			args: args{
@@ -177,7 +226,10 @@ func TestReconcile(t *testing.T) {
 								// owned's UID matches that of the
 								// ProviderRevision because they're both the
 								// empty string.
-								l := o.(*corev1.ServiceAccountList)
+								l, ok := o.(*corev1.ServiceAccountList)
+								if !ok {
+									return nil
+								}
 								l.Items = []corev1.ServiceAccount{{
 									ObjectMeta: metav1.ObjectMeta{OwnerReferences: []metav1.OwnerReference{{}}},
 								}}
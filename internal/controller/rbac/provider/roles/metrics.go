@@ -0,0 +1,59 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Stages of granting a provider RBAC permissions that may encounter errors.
+const (
+	StageList     = "list"
+	StageValidate = "validate"
+	StageApply    = "apply"
+	StageBind     = "bind"
+	StageStatus   = "status"
+)
+
+var (
+	rbacErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "crossplane_rbac_provider_errors_total",
+		Help: "The number of errors encountered rendering, applying, or binding the RBAC ClusterRoles of ProviderRevisions.",
+	}, []string{"stage"})
+
+	rulesApplied = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "crossplane_rbac_provider_cluster_role_rules_applied",
+		Help:    "The number of rules in each RBAC ClusterRole applied for a ProviderRevision.",
+		Buckets: []float64{0, 1, 5, 10, 25, 50, 100, 250},
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(rbacErrors, rulesApplied)
+}
+
+// CountError records an error granting a provider RBAC permissions at the
+// supplied stage.
+func CountError(stage string) {
+	rbacErrors.WithLabelValues(stage).Inc()
+}
+
+// ObserveRulesApplied records the number of rules in an applied ClusterRole.
+func ObserveRulesApplied(n int) {
+	rulesApplied.Observe(float64(n))
+}
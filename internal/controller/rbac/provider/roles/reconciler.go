@@ -28,8 +28,11 @@ import (
 	"github.com/google/go-containerregistry/pkg/name"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -53,8 +56,15 @@ const (
 	errGetPR               = "cannot get ProviderRevision"
 	errListPRs             = "cannot list ProviderRevisions"
 	errApplyRole           = "cannot apply ClusterRole"
+	errApplyNSRole         = "cannot apply Role"
+	errListRoles           = "cannot list Roles"
+	errDeleteRole          = "cannot delete Role"
+	errGetCRD              = "cannot get CustomResourceDefinition"
 	errValidatePermissions = "cannot validate permission requests"
-	errRejectedPermission  = "refusing to apply any RBAC roles due to request for disallowed permission"
+	errUpdateStatus        = "cannot update ProviderRevision status"
+	errRejectedPermission  = "refusing to grant disallowed permission"
+
+	errFmtWithheldRules = "withheld RBAC permissions for denied API groups from ClusterRole %s: %s"
 )
 
 // Event reasons.
@@ -99,16 +109,19 @@ func (fn ClusterRoleRenderFn) RenderClusterRoles(pr *v1.ProviderRevision, rs []R
 // resources it defines.
 func Setup(mgr ctrl.Manager, o controller.Options) error {
 	name := "rbac/" + strings.ToLower(v1.ProviderRevisionGroupKind)
+	f := APIGroupFilter{Allowed: o.AllowedProviderAPIGroups, Denied: o.DeniedProviderAPIGroups}
 
 	if o.AllowClusterRole == "" {
 		r := NewReconciler(mgr,
 			WithLogger(o.Logger.WithValues("controller", name)),
-			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+			WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
+			WithAPIGroupFilter(f))
 
 		return ctrl.NewControllerManagedBy(mgr).
 			Named(name).
 			For(&v1.ProviderRevision{}).
 			Owns(&rbacv1.ClusterRole{}).
+			Owns(&rbacv1.Role{}).
 			WithOptions(o.ForControllerRuntime()).
 			Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
 	}
@@ -125,12 +138,14 @@ func Setup(mgr ctrl.Manager, o controller.Options) error {
 		WithLogger(o.Logger.WithValues("controller", name)),
 		WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
 		WithPermissionRequestsValidator(NewClusterRoleBackedValidator(mgr.GetClient(), o.AllowClusterRole)),
-		WithOrgDiffer(OrgDiffer{DefaultRegistry: o.DefaultRegistry}))
+		WithOrgDiffer(OrgDiffer{DefaultRegistry: o.DefaultRegistry}),
+		WithAPIGroupFilter(f))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		For(&v1.ProviderRevision{}).
 		Owns(&rbacv1.ClusterRole{}).
+		Owns(&rbacv1.Role{}).
 		Watches(&rbacv1.ClusterRole{}, wrh).
 		Watches(&v1.ProviderRevision{}, sfh).
 		WithOptions(o.ForControllerRuntime()).
@@ -187,6 +202,15 @@ func WithOrgDiffer(d OrgDiffer) ReconcilerOption {
 	}
 }
 
+// WithAPIGroupFilter specifies which API groups the Reconciler may grant a
+// provider access to via its 'system' ClusterRole. Rules for any other API
+// groups are withheld.
+func WithAPIGroupFilter(f APIGroupFilter) ReconcilerOption {
+	return func(r *Reconciler) {
+		r.groups = f
+	}
+}
+
 // NewReconciler returns a Reconciler of ProviderRevisions.
 func NewReconciler(mgr manager.Manager, opts ...ReconcilerOption) *Reconciler {
 	r := &Reconciler{
@@ -221,6 +245,7 @@ type Reconciler struct {
 	client resource.ClientApplicator
 	rbac   rbac
 	org    OrgDiffer
+	groups APIGroupFilter
 
 	log    logging.Logger
 	record event.Recorder
@@ -274,6 +299,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 			log.Debug(errListPRs, "error", err)
 			err = errors.Wrap(err, errListPRs)
 			r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+			CountError(StageList)
 			return reconcile.Result{}, err
 		}
 
@@ -306,6 +332,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		log.Debug(errValidatePermissions, "error", err)
 		err = errors.Wrap(err, errValidatePermissions)
 		r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+		CountError(StageValidate)
 		return reconcile.Result{}, err
 	}
 
@@ -314,20 +341,56 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		r.record.Event(pr, event.Warning(reasonApplyRoles, errors.Errorf("%s %s", errRejectedPermission, rule)))
 	}
 
-	// We return early and don't grant _any_ RBAC permissions if we would reject
-	// any requested permission. It's better for the provider to be completely
-	// and obviously broken than for it to be subtly broken in a way that may
-	// not surface immediately, i.e. due to missing an RBAC permission it only
-	// occasionally needs. There's no need to requeue - the revisions requests
+	// We don't grant any permission request that includes a rejected
+	// permission, but the provider still gets its base permissions. There's
+	// no need to requeue if requests are rejected - the revision's requests
 	// won't change, and we're watching the ClusterRole of allowed requests.
-	if len(rejected) > 0 {
-		return reconcile.Result{Requeue: false}, nil
+	accepted, refused, err := PartitionPermissionRequests(ctx, pr.Status.PermissionRequests, rejected)
+	if err != nil {
+		log.Debug(errValidatePermissions, "error", err)
+		err = errors.Wrap(err, errValidatePermissions)
+		r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+		CountError(StageValidate)
+		return reconcile.Result{}, err
+	}
+
+	status := pr.Status.DeepCopy()
+	pr.Status.AcceptedPermissionRequests = accepted
+	pr.Status.RejectedPermissionRequests = refused
+
+	// A provider whose RBAC is scoped to namespaces gets a 'system' Role in
+	// each namespace granting access to namespaced resources. Its 'system'
+	// ClusterRole grants access only to the cluster scoped resources it
+	// defines, which a Role can't grant access to.
+	namespaces := Namespaces(pr)
+	var clusterRules, systemRules []rbacv1.PolicyRule
+	if len(namespaces) > 0 {
+		cluster, namespaced, err := r.partitionByScope(ctx, resources)
+		if err != nil {
+			log.Debug(errGetCRD, "error", err)
+			r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+			CountError(StageList)
+			return reconcile.Result{}, err
+		}
+		clusterRules, systemRules = RenderScopedSystemRules(cluster, namespaced, pr.Status.AcceptedPermissionRequests)
 	}
 
 	applied := make([]string, 0)
 	for _, cr := range r.rbac.RenderClusterRoles(pr, resources) {
 		cr := cr // Pin range variable so we can take its address.
 		log := log.WithValues("role-name", cr.GetName())
+
+		// Operators may deny providers access to some API groups, for
+		// example the core API group, which includes Secrets. A provider
+		// that needs a withheld permission will likely become unhealthy.
+		if cr.GetName() == SystemClusterRoleName(pr.GetName()) {
+			if len(namespaces) > 0 {
+				cr.Rules = clusterRules
+				systemRules = r.filter(log, pr, cr.GetName(), systemRules)
+			}
+			cr.Rules = r.filter(log, pr, cr.GetName(), cr.Rules)
+		}
+
 		err := r.client.Apply(ctx, &cr, resource.MustBeControllableBy(pr.GetUID()), resource.AllowUpdateIf(ClusterRolesDiffer))
 		if resource.IsNotAllowed(err) {
 			log.Debug("Skipped no-op RBAC ClusterRole apply")
@@ -336,25 +399,128 @@ func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reco
 		if err != nil {
 			log.Debug(errApplyRole, "error", err)
 			err = errors.Wrap(err, errApplyRole)
-			r.record.Event(pr, event.Warning(reasonApplyRoles, err))
-			return reconcile.Result{}, err
+			return r.applyFailed(ctx, log, pr, err)
 		}
 		log.Debug("Applied RBAC ClusterRole")
+		ObserveRulesApplied(len(cr.Rules))
 		applied = append(applied, cr.GetName())
 	}
 
+	names, err := r.applyRoles(ctx, pr, RenderRoles(pr, systemRules, namespaces))
+	if err != nil {
+		log.Debug("Cannot apply RBAC Roles", "error", err)
+		return r.applyFailed(ctx, log, pr, err)
+	}
+	applied = append(applied, names...)
+
 	if len(applied) > 0 {
 		sort.Strings(applied)
 		r.record.Event(pr, event.Normal(reasonApplyRoles, fmt.Sprintf("Applied RBAC ClusterRoles: %s", firstNAndSomeMore(applied))))
 	}
 
-	// TODO(negz): Add a condition that indicates the RBAC manager is
-	// managing cluster roles for this ProviderRevision?
+	pr.SetConditions(v1.ClusterRolesApplied())
+
+	// Only update our status if it changed. Otherwise we'd trigger another
+	// reconcile by updating the ProviderRevision we watch.
+	if !equality.Semantic.DeepEqual(*status, pr.Status) {
+		if err := r.client.Status().Update(ctx, pr); err != nil {
+			log.Debug(errUpdateStatus, "error", err)
+			err = errors.Wrap(err, errUpdateStatus)
+			r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+			CountError(StageStatus)
+			return reconcile.Result{}, err
+		}
+	}
 
 	// There's no need to requeue explicitly - we're watching all PRs.
 	return reconcile.Result{Requeue: false}, nil
 }
 
+// filter withholds any of the supplied rules of the supplied revision's role
+// that grant access to a denied API group.
+func (r *Reconciler) filter(log logging.Logger, pr *v1.ProviderRevision, role string, rules []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	kept, withheld := r.groups.Filter(rules)
+	if len(withheld) > 0 {
+		err := errors.Errorf(errFmtWithheldRules, role, strings.Join(DescribeRules(withheld), ", "))
+		log.Debug("Withheld RBAC rules", "error", err)
+		r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+	}
+	return kept
+}
+
+// partitionByScope partitions the supplied resources into those that are
+// cluster scoped and those that are namespaced, per their CRDs. A resource
+// whose CRD doesn't exist is considered namespaced, so that it's never
+// granted cluster wide access.
+func (r *Reconciler) partitionByScope(ctx context.Context, rs []Resource) (cluster, namespaced []Resource, err error) {
+	for _, res := range rs {
+		crd := &extv1.CustomResourceDefinition{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: res.Plural + "." + res.Group}, crd)
+		if resource.IgnoreNotFound(err) != nil {
+			return nil, nil, errors.Wrap(err, errGetCRD)
+		}
+		if err == nil && crd.Spec.Scope == extv1.ClusterScoped {
+			cluster = append(cluster, res)
+			continue
+		}
+		namespaced = append(namespaced, res)
+	}
+	return cluster, namespaced, nil
+}
+
+// applyFailed surfaces a failure to apply the supplied revision's RBAC roles.
+func (r *Reconciler) applyFailed(ctx context.Context, log logging.Logger, pr *v1.ProviderRevision, err error) (reconcile.Result, error) {
+	r.record.Event(pr, event.Warning(reasonApplyRoles, err))
+	CountError(StageApply)
+
+	// Without its roles a provider will likely encounter Forbidden errors, so
+	// we surface the failure on the revision. We return the apply error, not
+	// nil, so that we're requeued with backoff.
+	pr.SetConditions(v1.ApplyClusterRolesFailed().WithMessage(err.Error()))
+	if err := r.client.Status().Update(ctx, pr); err != nil {
+		log.Debug(errUpdateStatus, "error", err)
+		CountError(StageStatus)
+		return reconcile.Result{}, errors.Wrap(err, errUpdateStatus)
+	}
+	return reconcile.Result{}, err
+}
+
+// applyRoles applies the supplied 'system' Roles of the supplied revision, and
+// deletes any of its Roles that are no longer desired, so that a provider may
+// be switched between namespaced and cluster scoped RBAC. It returns the names
+// of the Roles it applied.
+func (r *Reconciler) applyRoles(ctx context.Context, pr *v1.ProviderRevision, roles []rbacv1.Role) ([]string, error) {
+	applied := make([]string, 0, len(roles))
+	desired := make(map[string]bool, len(roles))
+	for _, rl := range roles {
+		rl := rl // Pin range variable so we can take its address.
+		desired[rl.GetNamespace()] = true
+		err := r.client.Apply(ctx, &rl, resource.MustBeControllableBy(pr.GetUID()), resource.AllowUpdateIf(RolesDiffer))
+		if resource.IsNotAllowed(err) {
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, errApplyNSRole)
+		}
+		ObserveRulesApplied(len(rl.Rules))
+		applied = append(applied, rl.GetNamespace()+"/"+rl.GetName())
+	}
+
+	l := &rbacv1.RoleList{}
+	if err := r.client.List(ctx, l, client.MatchingLabels{KeyProviderRevision: pr.GetName()}); err != nil {
+		return nil, errors.Wrap(err, errListRoles)
+	}
+	for i := range l.Items {
+		if desired[l.Items[i].GetNamespace()] {
+			continue
+		}
+		if err := r.client.Delete(ctx, &l.Items[i]); resource.IgnoreNotFound(err) != nil {
+			return nil, errors.Wrap(err, errDeleteRole)
+		}
+	}
+	return applied, nil
+}
+
 // DefinedResources returns the resources defined by the supplied references.
 func DefinedResources(refs []xpv1.TypedReference) []Resource {
 	out := make([]Resource, 0, len(refs))
@@ -390,6 +556,14 @@ func ClusterRolesDiffer(current, desired runtime.Object) bool {
 	return !cmp.Equal(c.GetLabels(), d.GetLabels()) || !cmp.Equal(c.Rules, d.Rules)
 }
 
+// RolesDiffer returns true if the supplied objects are different Roles. We
+// consider Roles to be different if their labels and rules do not match.
+func RolesDiffer(current, desired runtime.Object) bool {
+	c := current.(*rbacv1.Role)
+	d := desired.(*rbacv1.Role)
+	return !cmp.Equal(c.GetLabels(), d.GetLabels()) || !cmp.Equal(c.Rules, d.Rules)
+}
+
 // An OrgDiffer determines whether two references are part of the same org. In
 // this context we consider an org to consist of:
 //
@@ -51,6 +51,9 @@ func TestReconcile(t *testing.T) {
 	family := "litfam"
 
 	ourUID := types.UID("our-own-uid")
+
+	secrets := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get"}}
+	nodes := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"nodes"}, Verbs: []string{"get"}}
 	familyUID := types.UID("uid-of-another-provider-in-our-family")
 
 	type args struct {
@@ -161,18 +164,40 @@ func TestReconcile(t *testing.T) {
 			},
 		},
 		"PermissionRequestRejected": {
-			reason: "We should return early without requeuing when a permission request is rejected.",
+			reason: "We should record rejected permission requests and apply ClusterRoles with only the accepted requests.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet:  test.NewMockGetFn(nil),
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.ProviderRevision).Status.PermissionRequests = []rbacv1.PolicyRule{secrets, nodes}
+								return nil
+							}),
 							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								pr := o.(*v1.ProviderRevision)
+								if diff := cmp.Diff([]rbacv1.PolicyRule{nodes}, pr.Status.AcceptedPermissionRequests); diff != "" {
+									t.Errorf("-want accepted, +got accepted:\n%s", diff)
+								}
+								if diff := cmp.Diff([]rbacv1.PolicyRule{secrets}, pr.Status.RejectedPermissionRequests); diff != "" {
+									t.Errorf("-want rejected, +got rejected:\n%s", diff)
+								}
+								return nil
+							}),
 						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							return nil
+						}),
 					}),
 					WithPermissionRequestsValidator(PermissionRequestsValidatorFn(func(ctx context.Context, requested ...rbacv1.PolicyRule) ([]Rule, error) {
-						return []Rule{{}}, nil
+						return []Rule{{APIGroup: "", Resource: "secrets", ResourceName: "*", Verb: "get"}}, nil
+					})),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(pr *v1.ProviderRevision, _ []Resource) []rbacv1.ClusterRole {
+						if diff := cmp.Diff([]rbacv1.PolicyRule{nodes}, pr.Status.AcceptedPermissionRequests); diff != "" {
+							t.Errorf("-want accepted, +got accepted:\n%s", diff)
+						}
+						return []rbacv1.ClusterRole{{}}
 					})),
 				},
 			},
@@ -180,8 +205,32 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"UpdateStatusError": {
+			reason: "We should return an error encountered updating the ProviderRevision's status.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.ProviderRevision).Status.PermissionRequests = []rbacv1.PolicyRule{nodes}
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+						},
+					}),
+					WithPermissionRequestsValidator(PermissionRequestsValidatorFn(func(ctx context.Context, requested ...rbacv1.PolicyRule) ([]Rule, error) {
+						return nil, nil
+					})),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errUpdateStatus),
+			},
+		},
 		"ApplyClusterRoleError": {
-			reason: "We should return an error encountered applying a ClusterRole.",
+			reason: "We should set a condition and return an error encountered applying a ClusterRole.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -189,6 +238,13 @@ func TestReconcile(t *testing.T) {
 						Client: &test.MockClient{
 							MockGet:  test.NewMockGetFn(nil),
 							MockList: test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								want := v1.ApplyClusterRolesFailed().WithMessage(errors.Wrap(errBoom, errApplyRole).Error())
+								if diff := cmp.Diff(want, o.(*v1.ProviderRevision).GetCondition(v1.TypeRBACApplied), test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
 						},
 						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
 							return errBoom
@@ -210,8 +266,9 @@ func TestReconcile(t *testing.T) {
 				opts: []ReconcilerOption{
 					WithClientApplicator(resource.ClientApplicator{
 						Client: &test.MockClient{
-							MockGet:  test.NewMockGetFn(nil),
-							MockList: test.NewMockListFn(nil),
+							MockGet:          test.NewMockGetFn(nil),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
 						},
 						Applicator: resource.ApplyFn(func(ctx context.Context, o client.Object, ao ...resource.ApplyOption) error {
 							// Simulate a no-op change by not allowing the update.
@@ -227,8 +284,197 @@ func TestReconcile(t *testing.T) {
 				r: reconcile.Result{Requeue: false},
 			},
 		},
+		"WithholdDeniedAPIGroups": {
+			reason: "We should withhold rules for denied API groups from the system ClusterRole we apply.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.SetName("cool")
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							want := []rbacv1.PolicyRule{{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{pluralLeases}}}
+							if diff := cmp.Diff(want, o.(*rbacv1.ClusterRole).Rules); diff != "" {
+								t.Errorf("-want, +got:\n%s", diff)
+							}
+							return nil
+						}),
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(*v1.ProviderRevision, []Resource) []rbacv1.ClusterRole {
+						return []rbacv1.ClusterRole{{
+							ObjectMeta: metav1.ObjectMeta{Name: SystemClusterRoleName("cool")},
+							Rules: []rbacv1.PolicyRule{
+								{APIGroups: []string{""}, Resources: []string{pluralSecrets, pluralEvents}},
+								{APIGroups: []string{"coordination.k8s.io"}, Resources: []string{pluralLeases}},
+							},
+						}}
+					})),
+					WithAPIGroupFilter(APIGroupFilter{Denied: []string{""}}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"ListRolesError": {
+			reason: "We should set a condition and return an error encountered listing Roles.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil),
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								if _, ok := o.(*rbacv1.RoleList); ok {
+									return errBoom
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errListRoles),
+			},
+		},
+		"GetCRDError": {
+			reason: "We should return an error encountered getting a CRD to determine whether it's cluster scoped.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								switch pr := o.(type) {
+								case *v1.ProviderRevision:
+									pr.SetName("cool")
+									pr.SetAnnotations(map[string]string{v1.AnnotationRBACNamespaces: "a"})
+									pr.Status.ObjectRefs = []xpv1.TypedReference{
+										{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "coolresources.example.org"},
+									}
+								case *extv1.CustomResourceDefinition:
+									return errBoom
+								}
+								return nil
+							}),
+						},
+					}),
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCRD),
+			},
+		},
+		"ScopedToNamespaces": {
+			reason: "We should apply a system Role granting access to namespaced resources in each namespace, limit the system ClusterRole to cluster scoped resources, and delete any Roles that are no longer desired.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: func(_ context.Context, key client.ObjectKey, o client.Object) error {
+								switch o := o.(type) {
+								case *v1.ProviderRevision:
+									o.SetName("cool")
+									o.SetAnnotations(map[string]string{v1.AnnotationRBACNamespaces: "a, b"})
+									o.Status.ObjectRefs = []xpv1.TypedReference{
+										{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "coolresources.example.org"},
+										{APIVersion: "apiextensions.k8s.io/v1", Kind: "CustomResourceDefinition", Name: "clusterresources.example.org"},
+									}
+								case *extv1.CustomResourceDefinition:
+									o.Spec.Scope = extv1.NamespaceScoped
+									if key.Name == "clusterresources.example.org" {
+										o.Spec.Scope = extv1.ClusterScoped
+									}
+								}
+								return nil
+							},
+							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
+								if l, ok := o.(*rbacv1.RoleList); ok {
+									l.Items = []rbacv1.Role{
+										{ObjectMeta: metav1.ObjectMeta{Namespace: "a", Name: SystemClusterRoleName("cool")}},
+										{ObjectMeta: metav1.ObjectMeta{Namespace: "c", Name: SystemClusterRoleName("cool")}},
+									}
+								}
+								return nil
+							}),
+							MockDelete: test.NewMockDeleteFn(nil, func(o client.Object) error {
+								if _, ok := o.(*rbacv1.Role); !ok || o.GetNamespace() != "c" {
+									t.Errorf("deleted %T %s/%s, want only the undesired Role in namespace c", o, o.GetNamespace(), o.GetName())
+								}
+								return nil
+							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+						},
+						Applicator: resource.ApplyFn(func(_ context.Context, o client.Object, _ ...resource.ApplyOption) error {
+							switch o := o.(type) {
+							case *rbacv1.Role:
+								want := []rbacv1.PolicyRule{
+									{APIGroups: []string{"example.org"}, Resources: []string{"coolresources", "coolresources/status"}, Verbs: verbsSystem},
+									{APIGroups: []string{"example.org"}, Resources: []string{"*/finalizers"}, Verbs: verbsUpdate},
+								}
+								want = append(want, rulesSystemExtra...)
+								if diff := cmp.Diff(want, o.Rules); diff != "" {
+									t.Errorf("Role %s/%s rules: -want, +got:\n%s", o.GetNamespace(), o.GetName(), diff)
+								}
+							case *rbacv1.ClusterRole:
+								if o.GetName() != SystemClusterRoleName("cool") {
+									return nil
+								}
+								want := []rbacv1.PolicyRule{
+									{APIGroups: []string{"example.org"}, Resources: []string{"clusterresources", "clusterresources/status"}, Verbs: verbsSystem},
+									{APIGroups: []string{"example.org"}, Resources: []string{"*/finalizers"}, Verbs: verbsUpdate},
+								}
+								if diff := cmp.Diff(want, o.Rules); diff != "" {
+									t.Errorf("ClusterRole %s rules: -want, +got:\n%s", o.GetName(), diff)
+								}
+							}
+							return nil
+						}),
+					}),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
+		"StatusUnchanged": {
+			reason: "We should not update our status if it hasn't changed.",
+			args: args{
+				mgr: &fake.Manager{},
+				opts: []ReconcilerOption{
+					WithClientApplicator(resource.ClientApplicator{
+						Client: &test.MockClient{
+							MockGet: test.NewMockGetFn(nil, func(o client.Object) error {
+								o.(*v1.ProviderRevision).SetConditions(v1.ClusterRolesApplied())
+								return nil
+							}),
+							MockList:         test.NewMockListFn(nil),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+						},
+						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
+							return nil
+						}),
+					}),
+					WithClusterRoleRenderer(ClusterRoleRenderFn(func(*v1.ProviderRevision, []Resource) []rbacv1.ClusterRole {
+						return []rbacv1.ClusterRole{{}}
+					})),
+				},
+			},
+			want: want{
+				r: reconcile.Result{Requeue: false},
+			},
+		},
 		"SuccessfulApply": {
-			reason: "We should not requeue when we successfully apply our ClusterRoles.",
+			reason: "We should set a condition and not requeue when we successfully apply our ClusterRoles.",
 			args: args{
 				mgr: &fake.Manager{},
 				opts: []ReconcilerOption{
@@ -242,7 +488,10 @@ func TestReconcile(t *testing.T) {
 								return nil
 							}),
 							MockList: test.NewMockListFn(nil, func(o client.ObjectList) error {
-								l := o.(*v1.ProviderRevisionList)
+								l, ok := o.(*v1.ProviderRevisionList)
+								if !ok {
+									return nil
+								}
 								l.Items = []v1.ProviderRevision{
 									{
 										ObjectMeta: metav1.ObjectMeta{UID: familyUID},
@@ -255,6 +504,12 @@ func TestReconcile(t *testing.T) {
 								}
 								return nil
 							}),
+							MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil, func(o client.Object) error {
+								if diff := cmp.Diff(v1.ClusterRolesApplied(), o.(*v1.ProviderRevision).GetCondition(v1.TypeRBACApplied), test.EquateConditions()); diff != "" {
+									t.Errorf("-want, +got:\n%s", diff)
+								}
+								return nil
+							}),
 						},
 						Applicator: resource.ApplyFn(func(context.Context, client.Object, ...resource.ApplyOption) error {
 							return nil
@@ -213,6 +213,36 @@ func (v *ClusterRoleBackedValidator) ValidatePermissionRequests(ctx context.Cont
 	return rejected, nil
 }
 
+// PartitionPermissionRequests partitions the supplied permission requests into
+// those that were accepted and those that were rejected. A request is rejected
+// if any of the granular rules it expands to was rejected.
+func PartitionPermissionRequests(ctx context.Context, requests []rbacv1.PolicyRule, rejected []Rule) (accepted, refused []rbacv1.PolicyRule, err error) {
+	r := make(map[Rule]bool, len(rejected))
+	for _, rule := range rejected {
+		r[rule] = true
+	}
+
+	for _, req := range requests {
+		expanded, err := Expand(ctx, req)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, errExpandPermissionRequests)
+		}
+		ok := true
+		for _, rule := range expanded {
+			if r[rule] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			accepted = append(accepted, req)
+			continue
+		}
+		refused = append(refused, req)
+	}
+	return accepted, refused, nil
+}
+
 // VerySecureValidator is a PermissionRequestsValidatorFn that rejects all
 // requested permissions.
 func VerySecureValidator(ctx context.Context, requests ...rbacv1.PolicyRule) ([]Rule, error) {
@@ -411,3 +411,77 @@ func TestValidatePermissionRequests(t *testing.T) {
 		})
 	}
 }
+
+func TestPartitionPermissionRequests(t *testing.T) {
+	secrets := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"get", "list"}}
+	events := rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"create"}}
+
+	type args struct {
+		ctx      context.Context
+		requests []rbacv1.PolicyRule
+		rejected []Rule
+	}
+	type want struct {
+		accepted []rbacv1.PolicyRule
+		refused  []rbacv1.PolicyRule
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllAccepted": {
+			reason: "All requests should be accepted if no rules were rejected.",
+			args: args{
+				ctx:      context.Background(),
+				requests: []rbacv1.PolicyRule{secrets, events},
+			},
+			want: want{
+				accepted: []rbacv1.PolicyRule{secrets, events},
+			},
+		},
+		"PartiallyRejected": {
+			reason: "A request should be rejected if any of the rules it expands to was rejected.",
+			args: args{
+				ctx:      context.Background(),
+				requests: []rbacv1.PolicyRule{secrets, events},
+				rejected: []Rule{{APIGroup: "", Resource: "secrets", ResourceName: "*", Verb: "list"}},
+			},
+			want: want{
+				accepted: []rbacv1.PolicyRule{events},
+				refused:  []rbacv1.PolicyRule{secrets},
+			},
+		},
+		"ExpandError": {
+			reason: "We should return any error encountered expanding requests.",
+			args: args{
+				ctx: func() context.Context {
+					ctx, cancel := context.WithCancel(context.Background())
+					cancel()
+					return ctx
+				}(),
+				requests: []rbacv1.PolicyRule{secrets},
+			},
+			want: want{
+				err: errors.Wrap(context.Canceled, errExpandPermissionRequests),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			accepted, refused, err := PartitionPermissionRequests(tc.args.ctx, tc.args.requests, tc.args.rejected)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nPartitionPermissionRequests(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.accepted, accepted); diff != "" {
+				t.Errorf("\n%s\nPartitionPermissionRequests(...): -want accepted, +got accepted:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.refused, refused); diff != "" {
+				t.Errorf("\n%s\nPartitionPermissionRequests(...): -want refused, +got refused:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
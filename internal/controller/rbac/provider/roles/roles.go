@@ -18,6 +18,7 @@ package roles
 
 import (
 	"sort"
+	"strings"
 
 	coordinationv1 "k8s.io/api/coordination/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -41,6 +42,10 @@ const (
 
 	valTrue = "true"
 
+	// KeyProviderRevision labels the Roles and RoleBindings the RBAC manager
+	// renders for a provider revision whose RBAC is scoped to namespaces.
+	KeyProviderRevision = "rbac.crossplane.io/provider-revision"
+
 	suffixStatus     = "/status"
 	suffixFinalizers = "/finalizers"
 
@@ -48,6 +53,8 @@ const (
 	pluralConfigmaps = "configmaps"
 	pluralSecrets    = "secrets"
 	pluralLeases     = "leases"
+
+	groupCore = "core"
 )
 
 var (
@@ -79,6 +86,18 @@ func SystemClusterRoleName(revisionName string) string {
 	return namePrefix + revisionName + nameSuffixSystem
 }
 
+// Namespaces returns the namespaces the supplied revision's RBAC is scoped to,
+// or nil if the revision should be granted ClusterRoles.
+func Namespaces(pr *v1.ProviderRevision) []string {
+	var ns []string
+	for _, n := range strings.Split(pr.GetAnnotations()[v1.AnnotationRBACNamespaces], ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			ns = append(ns, n)
+		}
+	}
+	return ns
+}
+
 // A Resource is a Kubernetes API resource.
 type Resource struct {
 	// Group is the unversioned API group of this resource.
@@ -89,45 +108,15 @@ type Resource struct {
 }
 
 // RenderClusterRoles returns ClusterRoles for the supplied ProviderRevision.
+// The 'system' ClusterRole includes only the revision's accepted permission
+// requests.
 func RenderClusterRoles(pr *v1.ProviderRevision, rs []Resource) []rbacv1.ClusterRole {
 	// Return early if we have no resources to render roles for.
 	if len(rs) == 0 {
 		return nil
 	}
 
-	// Our list of resources has no guaranteed order, so we sort them in order
-	// to ensure we don't reorder our RBAC rules on each update.
-	sort.Slice(rs, func(i, j int) bool {
-		return rs[i].Plural+rs[i].Group < rs[j].Plural+rs[j].Group
-	})
-
-	groups := make([]string, 0)            // Allows deterministic iteration over groups.
-	resources := make(map[string][]string) // Resources by group.
-	for _, r := range rs {
-		if _, ok := resources[r.Group]; !ok {
-			resources[r.Group] = make([]string, 0)
-			groups = append(groups, r.Group)
-		}
-		resources[r.Group] = append(resources[r.Group], r.Plural, r.Plural+suffixStatus)
-	}
-
-	rules := []rbacv1.PolicyRule{}
-	for _, g := range groups {
-		rules = append(rules, rbacv1.PolicyRule{
-			APIGroups: []string{g},
-			Resources: resources[g],
-		})
-	}
-
-	// Provider pods may create Kubernetes secrets containing managed resource connection details.
-	// These secrets are controlled (in the owner reference sense) by the managed resource.
-	// Crossplane needs permission to set finalizers on managed resources in order to create secrets
-	// that block their deletion when the OwnerReferencesPermissionEnforcement admission controller is enabled.
-	ruleFinalizers := rbacv1.PolicyRule{
-		APIGroups: groups,
-		Resources: []string{rbacv1.ResourceAll + suffixFinalizers},
-		Verbs:     verbsUpdate,
-	}
+	groups, rules := resourceRules(rs)
 
 	edit := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{
@@ -162,7 +151,7 @@ func RenderClusterRoles(pr *v1.ProviderRevision, rs []Resource) []rbacv1.Cluster
 	// directly to the service account tha provider runs as.
 	system := &rbacv1.ClusterRole{
 		ObjectMeta: metav1.ObjectMeta{Name: SystemClusterRoleName(pr.GetName())},
-		Rules:      append(append(append(withVerbs(rules, verbsSystem), ruleFinalizers), rulesSystemExtra...), pr.Status.PermissionRequests...),
+		Rules:      systemRules(groups, rules, pr.Status.AcceptedPermissionRequests),
 	}
 
 	roles := []rbacv1.ClusterRole{*edit, *view, *system}
@@ -173,6 +162,107 @@ func RenderClusterRoles(pr *v1.ProviderRevision, rs []Resource) []rbacv1.Cluster
 	return roles
 }
 
+// RenderRoles returns a 'system' Role with the supplied rules in each of the
+// supplied namespaces. These Roles grant a revision whose RBAC is scoped to
+// namespaces access to namespaced resources in place of its 'system'
+// ClusterRole.
+func RenderRoles(pr *v1.ProviderRevision, rules []rbacv1.PolicyRule, namespaces []string) []rbacv1.Role {
+	// Return early if we have no rules to render roles for.
+	if len(rules) == 0 {
+		return nil
+	}
+
+	roles := make([]rbacv1.Role, 0, len(namespaces))
+	for _, ns := range namespaces {
+		roles = append(roles, rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ns,
+				Name:            SystemClusterRoleName(pr.GetName()),
+				Labels:          map[string]string{KeyProviderRevision: pr.GetName()},
+				OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(pr, v1.ProviderRevisionGroupVersionKind))},
+			},
+			Rules: rules,
+		})
+	}
+	return roles
+}
+
+// RenderSystemRules returns the rules of the 'system' ClusterRole that would be
+// rendered for a provider that defines the supplied resources and makes the
+// supplied permission requests.
+func RenderSystemRules(rs []Resource, requests []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	if len(rs) == 0 {
+		return nil
+	}
+	groups, rules := resourceRules(rs)
+	return systemRules(groups, rules, requests)
+}
+
+// resourceRules returns the API groups of the supplied resources, and a rule
+// (without verbs) per API group covering its resources.
+func resourceRules(rs []Resource) ([]string, []rbacv1.PolicyRule) {
+	// Our list of resources has no guaranteed order, so we sort them in order
+	// to ensure we don't reorder our RBAC rules on each update.
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].Plural+rs[i].Group < rs[j].Plural+rs[j].Group
+	})
+
+	groups := make([]string, 0)            // Allows deterministic iteration over groups.
+	resources := make(map[string][]string) // Resources by group.
+	for _, r := range rs {
+		if _, ok := resources[r.Group]; !ok {
+			resources[r.Group] = make([]string, 0)
+			groups = append(groups, r.Group)
+		}
+		resources[r.Group] = append(resources[r.Group], r.Plural, r.Plural+suffixStatus)
+	}
+
+	rules := []rbacv1.PolicyRule{}
+	for _, g := range groups {
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups: []string{g},
+			Resources: resources[g],
+		})
+	}
+	return groups, rules
+}
+
+// RenderScopedSystemRules returns the rules of the 'system' ClusterRole and
+// the 'system' Roles of a provider whose RBAC is scoped to namespaces. A Role
+// can't grant access to cluster scoped resources, so the ClusterRole grants
+// access to the supplied cluster scoped resources. The Roles grant access to
+// the supplied namespaced resources, the core resources all providers need,
+// and the supplied permission requests.
+func RenderScopedSystemRules(cluster, namespaced []Resource, requests []rbacv1.PolicyRule) (clusterRules, roleRules []rbacv1.PolicyRule) {
+	if len(cluster) > 0 {
+		groups, rules := resourceRules(cluster)
+		clusterRules = append(withVerbs(rules, verbsSystem), finalizersRule(groups))
+	}
+
+	groups, rules := resourceRules(namespaced)
+	roleRules = withVerbs(rules, verbsSystem)
+	if len(groups) > 0 {
+		roleRules = append(roleRules, finalizersRule(groups))
+	}
+	return clusterRules, append(append(roleRules, rulesSystemExtra...), requests...)
+}
+
+func systemRules(groups []string, rules, requests []rbacv1.PolicyRule) []rbacv1.PolicyRule {
+	return append(append(append(withVerbs(rules, verbsSystem), finalizersRule(groups)), rulesSystemExtra...), requests...)
+}
+
+func finalizersRule(groups []string) rbacv1.PolicyRule {
+	// Provider pods may create Kubernetes secrets containing managed resource connection details.
+	// These secrets are controlled (in the owner reference sense) by the managed resource.
+	// Crossplane needs permission to set finalizers on managed resources in order to create secrets
+	// that block their deletion when the OwnerReferencesPermissionEnforcement admission controller is enabled.
+	return rbacv1.PolicyRule{
+		APIGroups: groups,
+		Resources: []string{rbacv1.ResourceAll + suffixFinalizers},
+		Verbs:     verbsUpdate,
+	}
+}
+
 func withVerbs(r []rbacv1.PolicyRule, verbs []string) []rbacv1.PolicyRule {
 	verbal := make([]rbacv1.PolicyRule, len(r))
 	for i := range r {
@@ -181,3 +271,104 @@ func withVerbs(r []rbacv1.PolicyRule, verbs []string) []rbacv1.PolicyRule {
 	}
 	return verbal
 }
+
+// An APIGroupFilter restricts the API groups a provider's 'system' ClusterRole
+// may grant access to. An API group is permitted if it is allowed and not
+// denied. All API groups are allowed if no allowed groups are specified. The
+// empty string denotes the core Kubernetes API group, and '*' any API group.
+type APIGroupFilter struct {
+	// Allowed API groups.
+	Allowed []string
+
+	// Denied API groups. Denial takes precedence over allowance.
+	Denied []string
+}
+
+// Filter the supplied rules, removing any API groups the filter doesn't
+// permit. Filter returns the rules that remain, and rules describing what was
+// withheld. Rules that don't pertain to API groups (i.e. non-resource URL
+// rules) are always kept.
+func (f APIGroupFilter) Filter(rules []rbacv1.PolicyRule) (kept, withheld []rbacv1.PolicyRule) {
+	if len(f.Allowed) == 0 && len(f.Denied) == 0 {
+		return rules, nil
+	}
+
+	kept = make([]rbacv1.PolicyRule, 0, len(rules))
+	for _, r := range rules {
+		if len(r.APIGroups) == 0 {
+			kept = append(kept, r)
+			continue
+		}
+
+		permitted, denied := make([]string, 0), make([]string, 0)
+		for _, g := range r.APIGroups {
+			if f.permits(g) {
+				permitted = append(permitted, g)
+				continue
+			}
+			denied = append(denied, g)
+		}
+
+		if len(permitted) > 0 {
+			k := *r.DeepCopy()
+			k.APIGroups = permitted
+			kept = append(kept, k)
+		}
+		if len(denied) > 0 {
+			w := *r.DeepCopy()
+			w.APIGroups = denied
+			withheld = append(withheld, w)
+		}
+	}
+	return kept, withheld
+}
+
+func (f APIGroupFilter) permits(group string) bool {
+	if contains(f.Denied, rbacv1.APIGroupAll) {
+		return false
+	}
+
+	// A rule granting access to all API groups would grant access to any
+	// denied API group, so we only permit it if nothing is denied.
+	if group == rbacv1.APIGroupAll {
+		return len(f.Denied) == 0 && (len(f.Allowed) == 0 || contains(f.Allowed, rbacv1.APIGroupAll))
+	}
+
+	if contains(f.Denied, group) {
+		return false
+	}
+	return len(f.Allowed) == 0 || contains(f.Allowed, group) || contains(f.Allowed, rbacv1.APIGroupAll)
+}
+
+// DescribeRules returns a sorted, human-readable list of the API group and
+// resource pairs the supplied rules pertain to, e.g. "core/secrets".
+func DescribeRules(rules []rbacv1.PolicyRule) []string {
+	seen := make(map[string]bool)
+	out := make([]string, 0)
+	for _, r := range rules {
+		for _, g := range r.APIGroups {
+			if g == "" {
+				g = groupCore
+			}
+			for _, res := range r.Resources {
+				d := strings.Join([]string{g, res}, "/")
+				if seen[d] {
+					continue
+				}
+				seen[d] = true
+				out = append(out, d)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func contains(s []string, v string) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
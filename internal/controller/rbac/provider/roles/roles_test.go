@@ -20,6 +20,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -170,3 +171,345 @@ func TestRenderClusterRoles(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderSystemRules(t *testing.T) {
+	request := rbacv1.PolicyRule{
+		APIGroups: []string{"apps"},
+		Resources: []string{"deployments"},
+		Verbs:     []string{"get"},
+	}
+
+	type args struct {
+		resources []Resource
+		requests  []rbacv1.PolicyRule
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []rbacv1.PolicyRule
+	}{
+		"NoResources": {
+			reason: "If there are no resources there should be no rules.",
+			args: args{
+				requests: []rbacv1.PolicyRule{request},
+			},
+		},
+		"Rules": {
+			reason: "We should render the rules of the 'system' ClusterRole, including any permission requests.",
+			args: args{
+				resources: []Resource{{Group: "example.org", Plural: "examples"}},
+				requests:  []rbacv1.PolicyRule{request},
+			},
+			want: append(append([]rbacv1.PolicyRule{
+				{
+					APIGroups: []string{"example.org"},
+					Resources: []string{"examples", "examples" + suffixStatus},
+					Verbs:     verbsSystem,
+				},
+				{
+					APIGroups: []string{"example.org"},
+					Resources: []string{rbacv1.ResourceAll + suffixFinalizers},
+					Verbs:     verbsUpdate,
+				},
+			}, rulesSystemExtra...), request),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RenderSystemRules(tc.args.resources, tc.args.requests)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRenderSystemRules(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestNamespaces(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		want        []string
+	}{
+		"NotScoped": {
+			reason: "A revision without the annotation should not be scoped to any namespaces.",
+		},
+		"Scoped": {
+			reason: "We should ignore whitespace and empty entries.",
+			annotations: map[string]string{
+				v1.AnnotationRBACNamespaces: " a,b,, c ",
+			},
+			want: []string{"a", "b", "c"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations}}
+			if diff := cmp.Diff(tc.want, Namespaces(pr)); diff != "" {
+				t.Errorf("\n%s\nNamespaces(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderRoles(t *testing.T) {
+	prName := "revision"
+	prUID := types.UID("uid")
+	ctrl := true
+	rules := []rbacv1.PolicyRule{{APIGroups: []string{"example.org"}, Resources: []string{"examples"}, Verbs: verbsSystem}}
+	pr := &v1.ProviderRevision{ObjectMeta: metav1.ObjectMeta{Name: prName, UID: prUID}}
+
+	role := func(ns string) rbacv1.Role {
+		return rbacv1.Role{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: ns,
+				Name:      SystemClusterRoleName(prName),
+				Labels:    map[string]string{KeyProviderRevision: prName},
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion:         v1.ProviderRevisionGroupVersionKind.GroupVersion().String(),
+					Kind:               v1.ProviderRevisionKind,
+					Name:               prName,
+					UID:                prUID,
+					Controller:         &ctrl,
+					BlockOwnerDeletion: &ctrl,
+				}},
+			},
+			Rules: rules,
+		}
+	}
+
+	type args struct {
+		rules      []rbacv1.PolicyRule
+		namespaces []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []rbacv1.Role
+	}{
+		"NoRules": {
+			reason: "If there are no rules there should be no Roles.",
+			args: args{
+				namespaces: []string{"a"},
+			},
+		},
+		"Roles": {
+			reason: "We should render a 'system' Role in each namespace.",
+			args: args{
+				rules:      rules,
+				namespaces: []string{"a", "b"},
+			},
+			want: []rbacv1.Role{role("a"), role("b")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := RenderRoles(pr, tc.args.rules, tc.args.namespaces)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRenderRoles(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRenderScopedSystemRules(t *testing.T) {
+	request := rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: verbsView}
+
+	type args struct {
+		cluster    []Resource
+		namespaced []Resource
+		requests   []rbacv1.PolicyRule
+	}
+	type want struct {
+		cluster []rbacv1.PolicyRule
+		roles   []rbacv1.PolicyRule
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoResources": {
+			reason: "Without resources only the Roles should have rules, granting the core resources all providers need and any permission requests.",
+			args: args{
+				requests: []rbacv1.PolicyRule{request},
+			},
+			want: want{
+				roles: append(append([]rbacv1.PolicyRule{}, rulesSystemExtra...), request),
+			},
+		},
+		"ClusterAndNamespacedResources": {
+			reason: "The ClusterRole should grant access to cluster scoped resources, and the Roles to namespaced resources.",
+			args: args{
+				cluster:    []Resource{{Group: "example.org", Plural: "clusterwidgets"}},
+				namespaced: []Resource{{Group: "example.org", Plural: "widgets"}},
+				requests:   []rbacv1.PolicyRule{request},
+			},
+			want: want{
+				cluster: []rbacv1.PolicyRule{
+					{APIGroups: []string{"example.org"}, Resources: []string{"clusterwidgets", "clusterwidgets" + suffixStatus}, Verbs: verbsSystem},
+					{APIGroups: []string{"example.org"}, Resources: []string{rbacv1.ResourceAll + suffixFinalizers}, Verbs: verbsUpdate},
+				},
+				roles: append(append([]rbacv1.PolicyRule{
+					{APIGroups: []string{"example.org"}, Resources: []string{"widgets", "widgets" + suffixStatus}, Verbs: verbsSystem},
+					{APIGroups: []string{"example.org"}, Resources: []string{rbacv1.ResourceAll + suffixFinalizers}, Verbs: verbsUpdate},
+				}, rulesSystemExtra...), request),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cluster, roles := RenderScopedSystemRules(tc.args.cluster, tc.args.namespaced, tc.args.requests)
+			if diff := cmp.Diff(tc.want.cluster, cluster, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nRenderScopedSystemRules(...): -want cluster rules, +got cluster rules:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.roles, roles, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nRenderScopedSystemRules(...): -want role rules, +got role rules:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAPIGroupFilter(t *testing.T) {
+	core := rbacv1.PolicyRule{
+		APIGroups: []string{""},
+		Resources: []string{pluralSecrets, pluralEvents},
+		Verbs:     verbsEdit,
+	}
+	mixed := rbacv1.PolicyRule{
+		APIGroups: []string{"", "coordination.k8s.io"},
+		Resources: []string{pluralSecrets, pluralEvents, pluralLeases},
+		Verbs:     verbsEdit,
+	}
+	defined := rbacv1.PolicyRule{
+		APIGroups: []string{"example.org"},
+		Resources: []string{"widgets", "widgets/status"},
+		Verbs:     verbsSystem,
+	}
+	all := rbacv1.PolicyRule{
+		APIGroups: []string{rbacv1.APIGroupAll},
+		Resources: []string{rbacv1.ResourceAll},
+		Verbs:     verbsView,
+	}
+	url := rbacv1.PolicyRule{
+		NonResourceURLs: []string{"/healthz"},
+		Verbs:           []string{"get"},
+	}
+
+	type args struct {
+		f     APIGroupFilter
+		rules []rbacv1.PolicyRule
+	}
+	type want struct {
+		kept     []rbacv1.PolicyRule
+		withheld []rbacv1.PolicyRule
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoFilter": {
+			reason: "All rules should be kept if no API groups are allowed or denied.",
+			args: args{
+				rules: []rbacv1.PolicyRule{core, defined, all},
+			},
+			want: want{
+				kept: []rbacv1.PolicyRule{core, defined, all},
+			},
+		},
+		"DenyCoreGroup": {
+			reason: "Rules for core group resources like secrets and events should be withheld if the core group is denied.",
+			args: args{
+				f:     APIGroupFilter{Denied: []string{""}},
+				rules: []rbacv1.PolicyRule{core, defined, url},
+			},
+			want: want{
+				kept:     []rbacv1.PolicyRule{defined, url},
+				withheld: []rbacv1.PolicyRule{core},
+			},
+		},
+		"DenyCoreGroupOfMixedRule": {
+			reason: "Only the denied API groups of a rule spanning several groups should be withheld.",
+			args: args{
+				f:     APIGroupFilter{Denied: []string{""}},
+				rules: []rbacv1.PolicyRule{mixed},
+			},
+			want: want{
+				kept: []rbacv1.PolicyRule{{
+					APIGroups: []string{"coordination.k8s.io"},
+					Resources: []string{pluralSecrets, pluralEvents, pluralLeases},
+					Verbs:     verbsEdit,
+				}},
+				withheld: []rbacv1.PolicyRule{{
+					APIGroups: []string{""},
+					Resources: []string{pluralSecrets, pluralEvents, pluralLeases},
+					Verbs:     verbsEdit,
+				}},
+			},
+		},
+		"AllowCoreGroup": {
+			reason: "Rules for API groups that aren't allowed should be withheld.",
+			args: args{
+				f:     APIGroupFilter{Allowed: []string{""}},
+				rules: []rbacv1.PolicyRule{core, defined},
+			},
+			want: want{
+				kept:     []rbacv1.PolicyRule{core},
+				withheld: []rbacv1.PolicyRule{defined},
+			},
+		},
+		"DenyTakesPrecedence": {
+			reason: "An API group that is both allowed and denied should be withheld.",
+			args: args{
+				f:     APIGroupFilter{Allowed: []string{"", "example.org"}, Denied: []string{""}},
+				rules: []rbacv1.PolicyRule{core, defined},
+			},
+			want: want{
+				kept:     []rbacv1.PolicyRule{defined},
+				withheld: []rbacv1.PolicyRule{core},
+			},
+		},
+		"WildcardGroupWithDenial": {
+			reason: "A rule for all API groups should be withheld if any API group is denied, since it would grant access to the denied group.",
+			args: args{
+				f:     APIGroupFilter{Denied: []string{""}},
+				rules: []rbacv1.PolicyRule{all},
+			},
+			want: want{
+				kept:     []rbacv1.PolicyRule{},
+				withheld: []rbacv1.PolicyRule{all},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			kept, withheld := tc.args.f.Filter(tc.args.rules)
+			if diff := cmp.Diff(tc.want.kept, kept); diff != "" {
+				t.Errorf("\n%s\nFilter(...): -want kept, +got kept:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.withheld, withheld); diff != "" {
+				t.Errorf("\n%s\nFilter(...): -want withheld, +got withheld:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDescribeRules(t *testing.T) {
+	rules := []rbacv1.PolicyRule{
+		{APIGroups: []string{"", "coordination.k8s.io"}, Resources: []string{pluralSecrets, pluralLeases}},
+		{APIGroups: []string{""}, Resources: []string{pluralEvents, pluralSecrets}},
+	}
+	want := []string{"coordination.k8s.io/leases", "coordination.k8s.io/secrets", "core/events", "core/leases", "core/secrets"}
+	if diff := cmp.Diff(want, DescribeRules(rules)); diff != "" {
+		t.Errorf("DescribeRules(...): -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package xrd contains the conversion webhook for the composite resources and
+// claims defined by v1.CompositeResourceDefinitions.
+package xrd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errDecodeReview     = "cannot decode conversion review"
+	errNoRequest        = "conversion review has no request"
+	errGetXRD           = "cannot get CompositeResourceDefinition"
+	errFmtDecodeObject  = "cannot decode object %d"
+	errFmtConvertObject = "cannot convert object %d"
+	errFmtEncodeObject  = "cannot encode object %d"
+)
+
+// SetupWebhookWithManager registers the conversion webhook with the manager's
+// webhook server. Conversion requests are routed to the webhook by the name of
+// the XRD that defines the converted resources, per xcrd.ConversionWebhookPath.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(xcrd.ConversionWebhookPathPrefix, NewHandler(mgr.GetClient()))
+	return nil
+}
+
+// A Handler converts composite resources and claims between versions by
+// applying the field conversions of the XRD that defines them.
+type Handler struct {
+	client client.Reader
+}
+
+// NewHandler returns a conversion webhook Handler that reads XRDs using the
+// supplied client.
+func NewHandler(c client.Reader) *Handler {
+	return &Handler{client: c}
+}
+
+// ServeHTTP serves a conversion review. The name of the XRD that defines the
+// resources to convert is read from the request path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &extv1.ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil {
+		http.Error(w, errors.Wrap(err, errDecodeReview).Error(), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, errNoRequest, http.StatusBadRequest)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, xcrd.ConversionWebhookPathPrefix)
+	review.Response = h.Convert(r.Context(), name, review.Request)
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+// Convert the objects of the supplied conversion request by applying the field
+// conversions of the named XRD.
+func (h *Handler) Convert(ctx context.Context, xrd string, req *extv1.ConversionRequest) *extv1.ConversionResponse {
+	rsp := &extv1.ConversionResponse{UID: req.UID}
+
+	d := &v1.CompositeResourceDefinition{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: xrd}, d); err != nil {
+		rsp.Result = failure(errors.Wrap(err, errGetXRD))
+		return rsp
+	}
+
+	objs := make([]runtime.RawExtension, len(req.Objects))
+	for i, o := range req.Objects {
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(o.Raw); err != nil {
+			rsp.Result = failure(errors.Wrapf(err, errFmtDecodeObject, i))
+			return rsp
+		}
+		if err := xcrd.Convert(d, u, req.DesiredAPIVersion); err != nil {
+			rsp.Result = failure(errors.Wrapf(err, errFmtConvertObject, i))
+			return rsp
+		}
+		raw, err := json.Marshal(u.Object)
+		if err != nil {
+			rsp.Result = failure(errors.Wrapf(err, errFmtEncodeObject, i))
+			return rsp
+		}
+		objs[i] = runtime.RawExtension{Raw: raw}
+	}
+
+	rsp.ConvertedObjects = objs
+	rsp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return rsp
+}
+
+func failure(err error) metav1.Status {
+	return metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xrd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+var _ http.Handler = &Handler{}
+
+func TestConvert(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withFieldConversions := test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*v1.CompositeResourceDefinition).Spec.FieldConversions = []v1.FieldConversion{
+			{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+		}
+		return nil
+	})
+
+	type args struct {
+		client client.Reader
+		req    *extv1.ConversionRequest
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   *extv1.ConversionResponse
+	}{
+		"GetXRDError": {
+			reason: "We should return a failure if we can't get the XRD.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				req:    &extv1.ConversionRequest{UID: types.UID("cool")},
+			},
+			want: &extv1.ConversionResponse{
+				UID:    types.UID("cool"),
+				Result: failure(errors.Wrap(errBoom, errGetXRD)),
+			},
+		},
+		"DecodeObjectError": {
+			reason: "We should return a failure if we can't decode an object.",
+			args: args{
+				client: &test.MockClient{MockGet: withFieldConversions},
+				req: &extv1.ConversionRequest{
+					UID:               types.UID("cool"),
+					DesiredAPIVersion: "example.org/v2",
+					Objects:           []runtime.RawExtension{{Raw: []byte("{")}},
+				},
+			},
+			want: &extv1.ConversionResponse{
+				UID:    types.UID("cool"),
+				Result: failure(errors.Wrapf(errors.New("unexpected end of JSON input"), errFmtDecodeObject, 0)),
+			},
+		},
+		"Success": {
+			reason: "We should convert all objects to the desired version.",
+			args: args{
+				client: &test.MockClient{MockGet: withFieldConversions},
+				req: &extv1.ConversionRequest{
+					UID:               types.UID("cool"),
+					DesiredAPIVersion: "example.org/v2",
+					Objects: []runtime.RawExtension{
+						{Raw: []byte(`{"apiVersion":"example.org/v1","kind":"XExample","spec":{"size":"large"}}`)},
+						{Raw: []byte(`{"apiVersion":"example.org/v2","kind":"XExample","spec":{"parameters":{"size":"small"}}}`)},
+					},
+				},
+			},
+			want: &extv1.ConversionResponse{
+				UID: types.UID("cool"),
+				ConvertedObjects: []runtime.RawExtension{
+					{Raw: []byte(`{"apiVersion":"example.org/v2","kind":"XExample","spec":{"parameters":{"size":"large"}}}`)},
+					{Raw: []byte(`{"apiVersion":"example.org/v2","kind":"XExample","spec":{"parameters":{"size":"small"}}}`)},
+				},
+				Result: metav1.Status{Status: metav1.StatusSuccess},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := NewHandler(tc.args.client).Convert(context.Background(), "cool", tc.args.req)
+			if diff := cmp.Diff(tc.want, got, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTPRoundTrip(t *testing.T) {
+	var requested string
+	c := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		requested = key.Name
+		obj.(*v1.CompositeResourceDefinition).Spec.FieldConversions = []v1.FieldConversion{
+			{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+			{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.region", ToFieldPath: "spec.location"},
+		}
+		return nil
+	}}
+	h := NewHandler(c)
+
+	convert := func(obj []byte, apiVersion string) []byte {
+		t.Helper()
+		body, err := json.Marshal(&extv1.ConversionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+			Request: &extv1.ConversionRequest{
+				UID:               types.UID("cool"),
+				DesiredAPIVersion: apiVersion,
+				Objects:           []runtime.RawExtension{{Raw: obj}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("json.Marshal(...): %s", err)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, xcrd.ConversionWebhookPath("xexamples.example.org"), bytes.NewReader(body)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("ServeHTTP(...): want status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		rv := &extv1.ConversionReview{}
+		if err := json.NewDecoder(w.Body).Decode(rv); err != nil {
+			t.Fatalf("json.Decode(...): %s", err)
+		}
+		if rv.Response == nil || rv.Response.Result.Status != metav1.StatusSuccess || len(rv.Response.ConvertedObjects) != 1 {
+			t.Fatalf("ServeHTTP(...): unexpected response: %+v", rv.Response)
+		}
+		if rv.Response.UID != types.UID("cool") {
+			t.Errorf("ServeHTTP(...): want response UID %q, got %q", "cool", rv.Response.UID)
+		}
+		return rv.Response.ConvertedObjects[0].Raw
+	}
+
+	v1Obj := []byte(`{"apiVersion":"example.org/v1","kind":"XExample","metadata":{"name":"cool-xr"},"spec":{"other":"preserved","region":"us-east-1","size":"large"}}`)
+	v2Obj := []byte(`{"apiVersion":"example.org/v2","kind":"XExample","metadata":{"name":"cool-xr"},"spec":{"location":"us-east-1","other":"preserved","parameters":{"size":"large"}}}`)
+
+	if diff := cmp.Diff(string(v2Obj), string(convert(v1Obj, "example.org/v2"))); diff != "" {
+		t.Errorf("ServeHTTP(...): converting v1 to v2: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff(string(v1Obj), string(convert(v2Obj, "example.org/v1"))); diff != "" {
+		t.Errorf("ServeHTTP(...): converting v2 to v1: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff("xexamples.example.org", requested); diff != "" {
+		t.Errorf("ServeHTTP(...): -want XRD, +got XRD:\n%s", diff)
+	}
+}
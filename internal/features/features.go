@@ -40,4 +40,36 @@ const (
 	// details.
 	// https://github.com/crossplane/crossplane/blob/f32496bed53a393c8239376fd8266ddf2ef84d61/design/design-doc-composition-validating-webhook.md
 	EnableAlphaCompositionWebhookSchemaValidation feature.Flag = "EnableAlphaCompositionWebhookSchemaValidation"
+
+	// EnableAlphaComposedResourceStatus enables alpha support for summarizing
+	// the state of each composed resource in the status of its composite
+	// resource, and how many are ready in the status of its claim. This
+	// increases the size of composite resources with many composed resources.
+	EnableAlphaComposedResourceStatus feature.Flag = "EnableAlphaComposedResourceStatus"
+
+	// EnableAlphaClaimCrossNamespaceConnectionSecrets enables alpha support for
+	// claims writing their connection secret to a namespace other than their
+	// own. Crossplane can write secrets to any namespace, so enabling this
+	// allows anyone who can create a claim to do so too.
+	EnableAlphaClaimCrossNamespaceConnectionSecrets feature.Flag = "EnableAlphaClaimCrossNamespaceConnectionSecrets"
+
+	// EnableAlphaUsages enables alpha support for Usages, which block deletion
+	// of a resource while it is in use by another resource, or for a reason.
+	EnableAlphaUsages feature.Flag = "EnableAlphaUsages"
+
+	// EnableAlphaClaimValidationWebhook enables alpha support for validating
+	// claims against the schema of their XRD using a webhook, which reports
+	// errors in terms of the claim's fields and supports strict validation.
+	EnableAlphaClaimValidationWebhook feature.Flag = "EnableAlphaClaimValidationWebhook"
+
+	// EnableAlphaPackageConfigMapSources enables alpha support for installing
+	// packages from a ConfigMap, using a source like configmap://ns/name.
+	// This is intended for testing packages without a registry.
+	EnableAlphaPackageConfigMapSources feature.Flag = "EnableAlphaPackageConfigMapSources"
+
+	// EnableAlphaRealtimeCompositions enables alpha support for watching
+	// composed resources, so that composite resources are reconciled as soon
+	// as one of their composed resources changes rather than when they're next
+	// polled. Each kind of composed resource watched is cached in memory.
+	EnableAlphaRealtimeCompositions feature.Flag = "EnableAlphaRealtimeCompositions"
 )
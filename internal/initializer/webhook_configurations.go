@@ -112,7 +112,9 @@ func (c *WebhookConfigurations) Run(ctx context.Context, kube client.Client) err
 				conf.Webhooks[i].ClientConfig.Service.Port = c.ServiceReference.Port
 			}
 			// See https://github.com/kubernetes-sigs/controller-tools/issues/658
-			conf.SetName("crossplane")
+			if conf.GetName() == "validating-webhook-configuration" {
+				conf.SetName("crossplane")
+			}
 		case *admv1.MutatingWebhookConfiguration:
 			for i := range conf.Webhooks {
 				conf.Webhooks[i].ClientConfig.CABundle = caBundle
@@ -121,7 +123,9 @@ func (c *WebhookConfigurations) Run(ctx context.Context, kube client.Client) err
 				conf.Webhooks[i].ClientConfig.Service.Port = c.ServiceReference.Port
 			}
 			// See https://github.com/kubernetes-sigs/controller-tools/issues/658
-			conf.SetName("crossplane")
+			if conf.GetName() == "mutating-webhook-configuration" {
+				conf.SetName("crossplane")
+			}
 		default:
 			return errors.Errorf("only MutatingWebhookConfiguration and ValidatingWebhookConfiguration kinds are accepted, got %T", obj)
 		}
@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package usage contains the admission webhook that blocks deletion of
+// resources that are in use, per v1alpha1.Usages.
+package usage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/json"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+const (
+	// InUseIndexKey is the key of the field index of Usages by the resource
+	// they are of.
+	InUseIndexKey = "inuse.apiversion.kind.name"
+
+	// LabelKeyInUse is added to resources that are in use. The webhook only
+	// intercepts deletion of resources with this label.
+	LabelKeyInUse = "crossplane.io/in-use"
+
+	// AnnotationKeyDeletionAttempt is added to the Usages that blocked an
+	// attempt to delete the resource they are of. Its value is the
+	// propagation policy of the attempt, which is replayed once the resource
+	// is no longer in use.
+	AnnotationKeyDeletionAttempt = "usage.crossplane.io/deletion-attempt-with-policy"
+
+	// WebhookPath is the path the webhook is served at.
+	WebhookPath = "/validate-no-usages"
+)
+
+// Error strings.
+const (
+	errFmtUnexpectedOp = "unexpected operation %q, expected DELETE"
+	errDecodeObject    = "cannot decode object"
+	errDecodeOptions   = "cannot decode delete options"
+	errListUsages      = "cannot list Usages"
+	errAnnotateUsage   = "cannot annotate Usage with deletion attempt"
+)
+
+// IndexValueForObject returns the InUseIndexKey value of the supplied object.
+func IndexValueForObject(u *unstructured.Unstructured) string {
+	return indexValue(u.GetAPIVersion(), u.GetKind(), u.GetName())
+}
+
+// IndexUsageOf indexes a Usage by the resource it is of. Usages that don't
+// yet reference a resource by name are not indexed.
+func IndexUsageOf(o client.Object) []string {
+	u, ok := o.(*v1alpha1.Usage)
+	if !ok || u.Spec.Of.ResourceRef == nil || u.Spec.Of.ResourceRef.Name == "" {
+		return nil
+	}
+	return []string{indexValue(u.Spec.Of.APIVersion, u.Spec.Of.Kind, u.Spec.Of.ResourceRef.Name)}
+}
+
+func indexValue(apiVersion, kind, name string) string {
+	return fmt.Sprintf("%s.%s.%s", apiVersion, kind, name)
+}
+
+// SetupWebhookWithManager registers the webhook with the manager's webhook
+// server. The manager's cache must index Usages by InUseIndexKey.
+func SetupWebhookWithManager(mgr ctrl.Manager, log logging.Logger) error {
+	mgr.GetWebhookServer().Register(WebhookPath, &webhook.Admission{Handler: NewHandler(mgr.GetClient(), WithLogger(log))})
+	return nil
+}
+
+// HandlerOption configures a Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger specifies how the Handler should log messages.
+func WithLogger(log logging.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.log = log
+	}
+}
+
+// A Handler rejects deletion of resources that are in use.
+type Handler struct {
+	client client.Client
+	log    logging.Logger
+}
+
+// NewHandler returns a Handler that finds Usages using the supplied client.
+func NewHandler(c client.Client, opts ...HandlerOption) *Handler {
+	h := &Handler{client: c, log: logging.NewNopLogger()}
+	for _, f := range opts {
+		f(h)
+	}
+	return h
+}
+
+// Handle an admission request. Deletion of a resource is rejected while any
+// Usage of it exists. The Usages that block a deletion are annotated so that
+// the deletion may be replayed once they are gone.
+func (h *Handler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Delete {
+		return admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, req.Operation))
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(req.OldObject.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeObject))
+	}
+
+	opts := &metav1.DeleteOptions{}
+	if len(req.Options.Raw) > 0 {
+		if err := json.Unmarshal(req.Options.Raw, opts); err != nil {
+			return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeOptions))
+		}
+	}
+
+	l := &v1alpha1.UsageList{}
+	if err := h.client.List(ctx, l, client.MatchingFields{InUseIndexKey: IndexValueForObject(u)}); err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errListUsages))
+	}
+	if len(l.Items) == 0 {
+		return admission.Allowed("")
+	}
+
+	dryRun := req.DryRun != nil && *req.DryRun
+	if !dryRun {
+		policy := metav1.DeletePropagationBackground
+		if opts.PropagationPolicy != nil {
+			policy = *opts.PropagationPolicy
+		}
+		for i := range l.Items {
+			us := &l.Items[i]
+			if us.GetAnnotations()[AnnotationKeyDeletionAttempt] == string(policy) {
+				continue
+			}
+			p := client.MergeFrom(us.DeepCopy())
+			meta.AddAnnotations(us, map[string]string{AnnotationKeyDeletionAttempt: string(policy)})
+			if err := h.client.Patch(ctx, us, p); err != nil {
+				return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errAnnotateUsage))
+			}
+		}
+	}
+
+	h.log.Debug("Rejected deletion of resource in use", "apiVersion", u.GetAPIVersion(), "kind", u.GetKind(), "name", u.GetName(), "usages", len(l.Items))
+	return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Code:    http.StatusConflict,
+			Reason:  metav1.StatusReasonConflict,
+			Message: inUseMessage(l.Items),
+		},
+	}}
+}
+
+func inUseMessage(usages []v1alpha1.Usage) string {
+	first := usages[0]
+	if first.Spec.By != nil && first.Spec.By.ResourceRef != nil {
+		return fmt.Sprintf("This resource is in use by %d Usage(s), including the Usage %q by resource %s/%s.", len(usages), first.GetName(), first.Spec.By.Kind, first.Spec.By.ResourceRef.Name)
+	}
+	if first.Spec.Reason != nil {
+		return fmt.Sprintf("This resource is in use by %d Usage(s), including the Usage %q with reason: %q.", len(usages), first.GetName(), *first.Spec.Reason)
+	}
+	return fmt.Sprintf("This resource is in use by %d Usage(s), including the Usage %q.", len(usages), first.GetName())
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/crossplane/crossplane/apis/apiextensions/v1alpha1"
+)
+
+var _ admission.Handler = &Handler{}
+
+func TestIndexUsageOf(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      client.Object
+		want   []string
+	}{
+		"NotAUsage": {
+			reason: "Objects that aren't Usages should not be indexed.",
+			o:      &v1alpha1.EnvironmentConfig{},
+		},
+		"NoResourceRef": {
+			reason: "Usages that only select the resource they are of should not be indexed.",
+			o: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{Of: v1alpha1.Resource{
+				APIVersion:       "example.org/v1",
+				Kind:             "Cool",
+				ResourceSelector: &v1alpha1.ResourceSelector{MatchLabels: map[string]string{"cool": "true"}},
+			}}},
+		},
+		"ResourceRef": {
+			reason: "Usages should be indexed by the apiVersion, kind, and name of the resource they are of.",
+			o: &v1alpha1.Usage{Spec: v1alpha1.UsageSpec{Of: v1alpha1.Resource{
+				APIVersion:  "example.org/v1",
+				Kind:        "Cool",
+				ResourceRef: &v1alpha1.ResourceRef{Name: "cool"},
+			}}},
+			want: []string{"example.org/v1.Cool.cool"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexUsageOf(tc.o)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIndexUsageOf(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHandle(t *testing.T) {
+	errBoom := errors.New("boom")
+	obj := []byte(`{"apiVersion":"example.org/v1","kind":"Cool","metadata":{"name":"cool"}}`)
+	usage := v1alpha1.Usage{
+		ObjectMeta: metav1.ObjectMeta{Name: "cool-usage"},
+		Spec: v1alpha1.UsageSpec{
+			Of: v1alpha1.Resource{APIVersion: "example.org/v1", Kind: "Cool", ResourceRef: &v1alpha1.ResourceRef{Name: "cool"}},
+			By: &v1alpha1.Resource{APIVersion: "example.org/v1", Kind: "User", ResourceRef: &v1alpha1.ResourceRef{Name: "cool-user"}},
+		},
+	}
+	listUsages := func(u ...v1alpha1.Usage) test.MockListFn {
+		return func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+			want := []client.ListOption{client.MatchingFields{InUseIndexKey: "example.org/v1.Cool.cool"}}
+			if diff := cmp.Diff(want, opts); diff != "" {
+				t.Errorf("List(...): -want options, +got options:\n%s", diff)
+			}
+			obj.(*v1alpha1.UsageList).Items = u
+			return nil
+		}
+	}
+	denied := admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Code:    http.StatusConflict,
+			Reason:  metav1.StatusReasonConflict,
+			Message: `This resource is in use by 1 Usage(s), including the Usage "cool-usage" by resource User/cool-user.`,
+		},
+	}}
+	dryRun := true
+
+	type args struct {
+		client client.Client
+		req    admission.Request
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   admission.Response
+	}{
+		"UnexpectedOperation": {
+			reason: "We should return an error if the request isn't a deletion.",
+			args: args{
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: admissionv1.Create}},
+			},
+			want: admission.Errored(http.StatusBadRequest, errors.Errorf(errFmtUnexpectedOp, admissionv1.Create)),
+		},
+		"ListError": {
+			reason: "We should return an error if we can't list Usages.",
+			args: args{
+				client: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					OldObject: runtime.RawExtension{Raw: obj},
+				}},
+			},
+			want: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errListUsages)),
+		},
+		"NotInUse": {
+			reason: "We should allow deletion of a resource that no Usage is of.",
+			args: args{
+				client: &test.MockClient{MockList: listUsages()},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					OldObject: runtime.RawExtension{Raw: obj},
+				}},
+			},
+			want: admission.Allowed(""),
+		},
+		"InUse": {
+			reason: "We should reject deletion of a resource that is in use, and record the deletion attempt.",
+			args: args{
+				client: &test.MockClient{
+					MockList: listUsages(usage),
+					MockPatch: func(_ context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+						want := map[string]string{AnnotationKeyDeletionAttempt: string(metav1.DeletePropagationForeground)}
+						if diff := cmp.Diff(want, obj.GetAnnotations()); diff != "" {
+							t.Errorf("Patch(...): -want annotations, +got annotations:\n%s", diff)
+						}
+						return nil
+					},
+				},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					OldObject: runtime.RawExtension{Raw: obj},
+					Options:   runtime.RawExtension{Raw: []byte(`{"propagationPolicy":"Foreground"}`)},
+				}},
+			},
+			want: denied,
+		},
+		"InUseDryRun": {
+			reason: "We should reject a dry run deletion of a resource that is in use without recording the attempt.",
+			args: args{
+				client: &test.MockClient{
+					MockList:  listUsages(usage),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					OldObject: runtime.RawExtension{Raw: obj},
+					DryRun:    &dryRun,
+				}},
+			},
+			want: denied,
+		},
+		"AnnotateError": {
+			reason: "We should return an error if we can't record the deletion attempt.",
+			args: args{
+				client: &test.MockClient{
+					MockList:  listUsages(usage),
+					MockPatch: test.NewMockPatchFn(errBoom),
+				},
+				req: admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+					Operation: admissionv1.Delete,
+					OldObject: runtime.RawExtension{Raw: obj},
+				}},
+			},
+			want: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errAnnotateUsage)),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.client)
+			got := h.Handle(context.Background(), tc.args.req)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nHandle(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package claim contains the validating webhook for the composite resource
+// claims defined by v1.CompositeResourceDefinitions.
+package claim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	"github.com/crossplane/crossplane/internal/xcrd"
+)
+
+// Error strings.
+const (
+	errDecodeObject = "cannot decode object"
+	errGetXRD       = "cannot get CompositeResourceDefinition"
+	errValidate     = "cannot validate claim"
+	errGetNamespace = "cannot get claim namespace"
+	errAllowed      = "cannot determine whether claims are allowed in namespace"
+
+	errFmtNamespaceNotAllowed = "claims of this kind may not be created in namespace %q"
+)
+
+// SetupWebhookWithManager registers the claim validation webhook with the
+// manager's webhook server. Validation requests are routed to the webhook by
+// the name of the XRD that defines the validated claims, per
+// xcrd.ClaimValidationWebhookPath.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	mgr.GetWebhookServer().Register(xcrd.ClaimValidationWebhookPathPrefix, NewHandler(mgr.GetClient()))
+	return nil
+}
+
+// A Handler validates claims against the schema of the XRD that defines them.
+type Handler struct {
+	client client.Reader
+}
+
+// NewHandler returns a claim validation webhook Handler that reads XRDs using
+// the supplied client.
+func NewHandler(c client.Reader) *Handler {
+	return &Handler{client: c}
+}
+
+// ServeHTTP serves an admission review. The name of the XRD that defines the
+// claim to validate is read from the request path.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, xcrd.ClaimValidationWebhookPathPrefix)
+	wh := &webhook.Admission{Handler: admission.HandlerFunc(func(ctx context.Context, req admission.Request) admission.Response {
+		return h.Validate(ctx, name, req)
+	})}
+	wh.ServeHTTP(w, r)
+}
+
+// Validate the claim of the supplied admission request against the schema of
+// the named XRD. New claims are rejected if the XRD doesn't allow claims in
+// their namespace. Claims that are being deleted are always allowed, as are
+// claims whose XRD no longer exists.
+func (h *Handler) Validate(ctx context.Context, xrd string, req admission.Request) admission.Response {
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return admission.Allowed("")
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := u.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, errors.Wrap(err, errDecodeObject))
+	}
+
+	// Don't block removing finalizers from a claim that is being deleted.
+	if meta.WasDeleted(u) {
+		return admission.Allowed("")
+	}
+
+	d := &v1.CompositeResourceDefinition{}
+	if err := h.client.Get(ctx, types.NamespacedName{Name: xrd}, d); err != nil {
+		if kerrors.IsNotFound(err) {
+			return admission.Allowed("")
+		}
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errGetXRD))
+	}
+
+	// Existing claims in namespaces that are no longer allowed may still be
+	// updated. The claim reconciler marks them with a condition instead.
+	if req.Operation == admissionv1.Create && d.Spec.ClaimNamespaces != nil {
+		if resp, ok := h.validateNamespace(ctx, d, u.GetNamespace()); !ok {
+			return resp
+		}
+	}
+
+	errs, err := xcrd.ValidateClaim(d, u)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errValidate))
+	}
+	if len(errs) == 0 {
+		return admission.Allowed("")
+	}
+
+	gk := schema.FromAPIVersionAndKind(u.GetAPIVersion(), u.GetKind()).GroupKind()
+	s := kerrors.NewInvalid(gk, u.GetName(), errs).Status()
+	return admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &s}}
+}
+
+// validateNamespace returns false, and a response rejecting the claim, if the
+// supplied XRD doesn't allow claims in the named namespace.
+func (h *Handler) validateNamespace(ctx context.Context, d *v1.CompositeResourceDefinition, name string) (admission.Response, bool) {
+	var l map[string]string
+	if d.Spec.ClaimNamespaces.Selector != nil {
+		ns := &corev1.Namespace{}
+		if err := h.client.Get(ctx, types.NamespacedName{Name: name}, ns); err != nil {
+			return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errGetNamespace)), false
+		}
+		l = ns.GetLabels()
+	}
+	ok, err := d.Spec.ClaimNamespaces.Allows(name, l)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, errors.Wrap(err, errAllowed)), false
+	}
+	if !ok {
+		return admission.Denied(fmt.Sprintf(errFmtNamespaceNotAllowed, name)), false
+	}
+	return admission.Allowed(""), true
+}
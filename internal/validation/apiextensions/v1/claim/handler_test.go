@@ -0,0 +1,206 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package claim
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+var _ http.Handler = &Handler{}
+
+func TestValidate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	withXRD := test.NewMockGetFn(nil, func(obj client.Object) error {
+		d := obj.(*v1.CompositeResourceDefinition)
+		d.Spec.Group = "example.org"
+		d.Spec.Names = extv1.CustomResourceDefinitionNames{Plural: "xexamples", Kind: "XExample"}
+		d.Spec.ClaimNames = &extv1.CustomResourceDefinitionNames{Plural: "examples", Kind: "Example"}
+		d.Spec.ClaimValidation = &v1.ClaimValidation{Strict: true}
+		d.Spec.Versions = []v1.CompositeResourceDefinitionVersion{{
+			Name:          "v1",
+			Referenceable: true,
+			Served:        true,
+			Schema: &v1.CompositeResourceValidation{
+				OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"spec":{"type":"object","properties":{"size":{"type":"integer"}}}}}`)},
+			},
+		}}
+		return nil
+	})
+	// withNamespaces returns an XRD that only allows claims in the supplied
+	// namespaces, and a namespace with the supplied labels.
+	withNamespaces := func(cn *v1.ClaimNamespaces, labels map[string]string, err error) test.MockGetFn {
+		return func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1.CompositeResourceDefinition:
+				if err := withXRD(ctx, key, o); err != nil {
+					return err
+				}
+				o.Spec.ClaimNamespaces = cn
+			case *corev1.Namespace:
+				o.SetLabels(labels)
+				return err
+			}
+			return nil
+		}
+	}
+	request := func(op admissionv1.Operation, obj string) admission.Request {
+		return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: op,
+			Object:    runtime.RawExtension{Raw: []byte(obj)},
+		}}
+	}
+
+	invalid := kerrors.NewInvalid(schema.GroupKind{Group: "example.org", Kind: "Example"}, "cool", field.ErrorList{
+		field.Forbidden(field.NewPath("spec.colour"), "field is not declared by the claim's schema"),
+	}).Status()
+
+	type args struct {
+		client client.Reader
+		req    admission.Request
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   admission.Response
+	}{
+		"DeleteIsAllowed": {
+			reason: "We should allow operations other than create and update.",
+			args: args{
+				req: request(admissionv1.Delete, ""),
+			},
+			want: admission.Allowed(""),
+		},
+		"DecodeObjectError": {
+			reason: "We should return an error if we can't decode the claim.",
+			args: args{
+				req: request(admissionv1.Create, "{"),
+			},
+			want: admission.Errored(http.StatusBadRequest, errors.Wrap(errors.New("unexpected end of JSON input"), errDecodeObject)),
+		},
+		"BeingDeleted": {
+			reason: "We should allow updates to a claim that is being deleted.",
+			args: args{
+				req: request(admissionv1.Update, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"name":"cool","deletionTimestamp":"2023-01-01T00:00:00Z"},"spec":{"colour":"blue"}}`),
+			},
+			want: admission.Allowed(""),
+		},
+		"XRDNotFound": {
+			reason: "We should allow a claim whose XRD no longer exists.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "xexamples.example.org"))},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"name":"cool"}}`),
+			},
+			want: admission.Allowed(""),
+		},
+		"GetXRDError": {
+			reason: "We should return an error if we can't get the XRD.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"name":"cool"}}`),
+			},
+			want: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errGetXRD)),
+		},
+		"Valid": {
+			reason: "We should allow a claim that matches its schema.",
+			args: args{
+				client: &test.MockClient{MockGet: withXRD},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"name":"cool"},"spec":{"size":2}}`),
+			},
+			want: admission.Allowed(""),
+		},
+		"Invalid": {
+			reason: "We should reject a claim that doesn't match its schema, referencing its invalid fields.",
+			args: args{
+				client: &test.MockClient{MockGet: withXRD},
+				req:    request(admissionv1.Update, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"name":"cool"},"spec":{"size":2,"colour":"blue"}}`),
+			},
+			want: admission.Response{AdmissionResponse: admissionv1.AdmissionResponse{Allowed: false, Result: &invalid}},
+		},
+		"NamespaceNotAllowed": {
+			reason: "We should reject a new claim in a namespace the XRD doesn't allow.",
+			args: args{
+				client: &test.MockClient{MockGet: withNamespaces(&v1.ClaimNamespaces{Names: []string{"tenant-a"}}, nil, nil)},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"namespace":"default","name":"cool"},"spec":{"size":2}}`),
+			},
+			want: admission.Denied(fmt.Sprintf(errFmtNamespaceNotAllowed, "default")),
+		},
+		"NamespaceAllowedByName": {
+			reason: "We should allow a new claim in a namespace the XRD lists.",
+			args: args{
+				client: &test.MockClient{MockGet: withNamespaces(&v1.ClaimNamespaces{Names: []string{"tenant-a"}}, nil, nil)},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"namespace":"tenant-a","name":"cool"},"spec":{"size":2}}`),
+			},
+			want: admission.Allowed(""),
+		},
+		"NamespaceAllowedBySelector": {
+			reason: "We should allow a new claim in a namespace whose labels match the XRD's selector.",
+			args: args{
+				client: &test.MockClient{MockGet: withNamespaces(&v1.ClaimNamespaces{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"tenant": "true"}}}, map[string]string{"tenant": "true"}, nil)},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"namespace":"tenant-a","name":"cool"},"spec":{"size":2}}`),
+			},
+			want: admission.Allowed(""),
+		},
+		"GetNamespaceError": {
+			reason: "We should return an error if we can't get the claim's namespace.",
+			args: args{
+				client: &test.MockClient{MockGet: withNamespaces(&v1.ClaimNamespaces{Selector: &metav1.LabelSelector{}}, nil, errBoom)},
+				req:    request(admissionv1.Create, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"namespace":"tenant-a","name":"cool"},"spec":{"size":2}}`),
+			},
+			want: admission.Errored(http.StatusInternalServerError, errors.Wrap(errBoom, errGetNamespace)),
+		},
+		"UpdateInNamespaceNotAllowed": {
+			reason: "We should allow updates to an existing claim in a namespace the XRD no longer allows.",
+			args: args{
+				client: &test.MockClient{MockGet: withNamespaces(&v1.ClaimNamespaces{Names: []string{"tenant-a"}}, nil, nil)},
+				req:    request(admissionv1.Update, `{"apiVersion":"example.org/v1","kind":"Example","metadata":{"namespace":"default","name":"cool"},"spec":{"size":2}}`),
+			},
+			want: admission.Allowed(""),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(tc.args.client)
+			got := h.Validate(context.Background(), "xexamples.example.org", tc.args.req)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nValidate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -52,6 +53,10 @@ const (
 
 	errFmtTooManyCRDs = "more than one CRD found for %s.%s: %v"
 	errFmtGetCRDs     = "cannot get the needed CRDs: %v"
+
+	errGetFunctionConfigCRDs = "cannot get the CRDs of function configs"
+
+	warnFmtNoFunctionConfigCRD = "config of function %q is not validated: no CRD defines kind %s"
 )
 
 // SetupWebhookWithManager sets up the webhook with the manager.
@@ -80,7 +85,7 @@ type validator struct {
 }
 
 // ValidateCreate validates a Composition.
-func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) { //nolint:gocyclo // Currently only at 11
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) { //nolint:gocyclo // Currently only at 13
 	comp, ok := obj.(*v1.Composition)
 	if !ok {
 		return nil, errors.New(errNotComposition)
@@ -124,6 +129,18 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 		return warns, nil
 	}
 
+	// Function configs are validated against the CRD that defines their kind,
+	// if one is installed. Changes to that CRD are caught the next time the
+	// Composition is updated.
+	fnCRDs, fnWarns, err := v.getFunctionConfigCRDs(ctx, comp)
+	if err != nil {
+		return warns, errors.Wrap(err, errGetFunctionConfigCRDs)
+	}
+	warns = append(warns, fnWarns...)
+	for gk, crd := range fnCRDs {
+		gkToCRD[gk] = crd
+	}
+
 	cv, err := composition.NewValidator(
 		composition.WithCRDGetterFromMap(gkToCRD),
 		// We disable logical Validation as this has already been done above.
@@ -140,8 +157,22 @@ func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (adm
 	return warns, nil
 }
 
-// ValidateUpdate implements the same logic as ValidateCreate.
-func (v *validator) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements the same logic as ValidateCreate if the
+// Composition's spec changed. Compositions that were created before a
+// validation rule was introduced may otherwise be updated, e.g. to change
+// their labels or annotations.
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(*v1.Composition)
+	if !ok {
+		return nil, errors.New(errNotComposition)
+	}
+	n, ok := newObj.(*v1.Composition)
+	if !ok {
+		return nil, errors.New(errNotComposition)
+	}
+	if equality.Semantic.DeepEqual(o.Spec, n.Spec) {
+		return nil, nil
+	}
 	return v.ValidateCreate(ctx, newObj)
 }
 
@@ -205,6 +236,35 @@ func (v *validator) getNeededCRDs(ctx context.Context, comp *v1.Composition) (ma
 	return neededCrds, resultErrs
 }
 
+// getFunctionConfigCRDs returns the CRDs that define the kinds of the
+// Composition's function configs. It returns a warning for each function
+// config whose kind isn't defined by a CRD.
+func (v *validator) getFunctionConfigCRDs(ctx context.Context, comp *v1.Composition) (map[schema.GroupKind]apiextensions.CustomResourceDefinition, []string, error) {
+	crds := make(map[schema.GroupKind]apiextensions.CustomResourceDefinition)
+	var warns []string
+	for i, fn := range comp.Spec.Functions {
+		gvk, err := composition.GetFunctionConfigGVK(&comp.Spec.Functions[i])
+		if err != nil {
+			// Invalid configs are reported by the Validator.
+			continue
+		}
+		if gvk.Empty() {
+			continue
+		}
+		gk := gvk.GroupKind()
+		crd, err := v.getCRD(ctx, &gk)
+		switch {
+		case apierrors.IsNotFound(err):
+			warns = append(warns, fmt.Sprintf(warnFmtNoFunctionConfigCRD, fn.Name, gk))
+		case err != nil:
+			return nil, nil, err
+		case crd != nil:
+			crds[gk] = *crd
+		}
+	}
+	return crds, warns, nil
+}
+
 // getCRD returns the validation schema for the given GVK, by looking up the CRD
 // by group and kind using the provided client.
 func (v *validator) getCRD(ctx context.Context, gk *schema.GroupKind) (*apiextensions.CustomResourceDefinition, error) {
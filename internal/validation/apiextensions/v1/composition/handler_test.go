@@ -16,6 +16,95 @@ limitations under the License.
 
 package composition
 
-import "sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
 
 var _ admission.CustomValidator = &validator{}
+
+func TestValidateUpdate(t *testing.T) {
+	// A Composition with duplicate resource names, which is invalid.
+	invalid := func(labels map[string]string) *v1.Composition {
+		return &v1.Composition{
+			TypeMeta:   metav1.TypeMeta{APIVersion: v1.SchemeGroupVersion.String(), Kind: v1.CompositionKind},
+			ObjectMeta: metav1.ObjectMeta{Name: "cool", Labels: labels},
+			Spec: v1.CompositionSpec{
+				Resources: []v1.ComposedTemplate{
+					{Name: pointer.String("foo")},
+					{Name: pointer.String("foo")},
+				},
+			},
+		}
+	}
+
+	_, errs := invalid(nil).Validate()
+
+	type args struct {
+		oldObj runtime.Object
+		newObj runtime.Object
+	}
+	type want struct {
+		warns admission.Warnings
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NotAComposition": {
+			reason: "We should return an error if the supplied object is not a Composition.",
+			args: args{
+				oldObj: &v1.CompositionRevision{},
+				newObj: &v1.Composition{},
+			},
+			want: want{
+				err: errors.New(errNotComposition),
+			},
+		},
+		"SpecUnchanged": {
+			reason: "We should not validate a Composition whose spec did not change.",
+			args: args{
+				oldObj: invalid(nil),
+				newObj: invalid(map[string]string{"cool": "true"}),
+			},
+		},
+		"SpecChanged": {
+			reason: "We should validate a Composition whose spec changed.",
+			args: args{
+				oldObj: &v1.Composition{ObjectMeta: metav1.ObjectMeta{Name: "cool"}},
+				newObj: invalid(nil),
+			},
+			want: want{
+				err: apierrors.NewInvalid(v1.CompositionGroupVersionKind.GroupKind(), "cool", errs),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{}
+			warns, err := v.ValidateUpdate(context.Background(), tc.args.oldObj, tc.args.newObj)
+			if diff := cmp.Diff(tc.want.warns, warns); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want warnings, +got warnings:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateUpdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package definition contains the validating webhook for the
+// v1.CompositeResourceDefinition type.
+package definition
+
+import (
+	"context"
+
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// Error strings.
+const (
+	errNotXRD  = "supplied object was not a CompositeResourceDefinition"
+	errListXRs = "cannot list defined composite resources"
+
+	errFmtDeletionBlocked = "cannot delete CompositeResourceDefinition with deletion policy %s: composite resources of kind %s still exist"
+)
+
+// SetupWebhookWithManager sets up the webhook with the manager.
+func SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(&validator{reader: mgr.GetClient()}).
+		For(&v1.CompositeResourceDefinition{}).
+		Complete()
+}
+
+type validator struct {
+	reader client.Reader
+}
+
+// ValidateCreate validates a CompositeResourceDefinition.
+func (v *validator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	d, ok := obj.(*v1.CompositeResourceDefinition)
+	if !ok {
+		return nil, errors.New(errNotXRD)
+	}
+	return d.ValidateCreate()
+}
+
+// ValidateUpdate validates an update to a CompositeResourceDefinition.
+func (v *validator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	d, ok := newObj.(*v1.CompositeResourceDefinition)
+	if !ok {
+		return nil, errors.New(errNotXRD)
+	}
+	return d.ValidateUpdate(oldObj)
+}
+
+// ValidateDelete rejects the deletion of a CompositeResourceDefinition with
+// the Block deletion policy while composite resources of the kind it defines
+// exist.
+func (v *validator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	d, ok := obj.(*v1.CompositeResourceDefinition)
+	if !ok {
+		return nil, errors.New(errNotXRD)
+	}
+	if d.GetDeletionPolicy() != v1.XRDDeletionBlock {
+		return nil, nil
+	}
+
+	l := &kunstructured.UnstructuredList{}
+	l.SetGroupVersionKind(d.GetCompositeGroupVersionKind())
+	if err := v.reader.List(ctx, l, client.Limit(1)); err != nil {
+		// The composite resource CRD doesn't exist, so neither can any
+		// composite resources.
+		if kmeta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, errListXRs)
+	}
+	if len(l.Items) > 0 {
+		return nil, errors.Errorf(errFmtDeletionBlocked, v1.XRDDeletionBlock, d.GetCompositeGroupVersionKind().GroupKind())
+	}
+	return nil, nil
+}
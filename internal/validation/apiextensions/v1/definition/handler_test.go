@@ -0,0 +1,134 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package definition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	kmeta "k8s.io/apimachinery/pkg/api/meta"
+	kunstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestValidateDelete(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	block := v1.XRDDeletionBlock
+	deleteInstances := v1.XRDDeletionDeleteInstances
+
+	xrd := func(p *v1.XRDDeletionPolicy) *v1.CompositeResourceDefinition {
+		return &v1.CompositeResourceDefinition{
+			Spec: v1.CompositeResourceDefinitionSpec{
+				Group: "example.org",
+				Names: extv1.CustomResourceDefinitionNames{Kind: "XCoolResource"},
+				Versions: []v1.CompositeResourceDefinitionVersion{
+					{Name: "v1", Referenceable: true},
+				},
+				DeletionPolicy: p,
+			},
+		}
+	}
+
+	withXRs := test.NewMockListFn(nil, func(o client.ObjectList) error {
+		l := o.(*kunstructured.UnstructuredList)
+		l.Items = []kunstructured.Unstructured{{}}
+		return nil
+	})
+
+	type args struct {
+		reader client.Reader
+		obj    runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotXRD": {
+			reason: "We should return an error if the supplied object isn't an XRD.",
+			args: args{
+				obj: &v1.Composition{},
+			},
+			want: errors.New(errNotXRD),
+		},
+		"DeleteInstances": {
+			reason: "We should allow deleting an XRD with the DeleteInstances policy even if composite resources exist.",
+			args: args{
+				reader: &test.MockClient{MockList: withXRs},
+				obj:    xrd(&deleteInstances),
+			},
+		},
+		"DefaultPolicy": {
+			reason: "We should allow deleting an XRD with no deletion policy even if composite resources exist.",
+			args: args{
+				reader: &test.MockClient{MockList: withXRs},
+				obj:    xrd(nil),
+			},
+		},
+		"ListError": {
+			reason: "We should return any error encountered listing composite resources.",
+			args: args{
+				reader: &test.MockClient{MockList: test.NewMockListFn(errBoom)},
+				obj:    xrd(&block),
+			},
+			want: errors.Wrap(errBoom, errListXRs),
+		},
+		"NoCRD": {
+			reason: "We should allow deleting an XRD with the Block policy if its composite resource CRD doesn't exist.",
+			args: args{
+				reader: &test.MockClient{MockList: test.NewMockListFn(&kmeta.NoKindMatchError{})},
+				obj:    xrd(&block),
+			},
+		},
+		"NoXRs": {
+			reason: "We should allow deleting an XRD with the Block policy if no composite resources exist.",
+			args: args{
+				reader: &test.MockClient{MockList: test.NewMockListFn(nil)},
+				obj:    xrd(&block),
+			},
+		},
+		"Blocked": {
+			reason: "We should reject deleting an XRD with the Block policy while composite resources exist.",
+			args: args{
+				reader: &test.MockClient{MockList: withXRs},
+				obj:    xrd(&block),
+			},
+			want: errors.Errorf(errFmtDeletionBlocked, v1.XRDDeletionBlock, schema.GroupKind{Group: "example.org", Kind: "XCoolResource"}),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{reader: tc.args.reader}
+			_, err := v.ValidateDelete(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateDelete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
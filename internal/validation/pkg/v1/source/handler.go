@@ -0,0 +1,209 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package source contains internal logic linked to the validation of the
+// sources of v1.Provider and v1.Configuration packages.
+package source
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+// Keys of the data of a package source policy ConfigMap. Each value is a list
+// of registries separated by commas or newlines.
+const (
+	KeyAllowedRegistries = "allowedRegistries"
+	KeyDeniedRegistries  = "deniedRegistries"
+)
+
+// Error strings.
+const (
+	errNotPackage = "supplied object was not a package"
+	errGetPolicy  = "cannot get package source policy"
+
+	errFmtDenied     = "registry %q is denied by the package source policy"
+	errFmtNotAllowed = "registry %q is not allowed by the package source policy"
+)
+
+// A Policy constrains the registries packages may be installed from.
+type Policy struct {
+	// AllowedRegistries from which packages may be installed. Packages may be
+	// installed from any registry that isn't denied if this is empty.
+	AllowedRegistries []string
+
+	// DeniedRegistries from which packages may not be installed. A denied
+	// registry is denied even if it is also allowed.
+	DeniedRegistries []string
+}
+
+// Check returns an error naming the supplied registry if the Policy does not
+// allow packages to be installed from it.
+func (p *Policy) Check(registry string) error {
+	if contains(p.DeniedRegistries, registry) {
+		return errors.Errorf(errFmtDenied, registry)
+	}
+	if len(p.AllowedRegistries) > 0 && !contains(p.AllowedRegistries, registry) {
+		return errors.Errorf(errFmtNotAllowed, registry)
+	}
+	return nil
+}
+
+func contains(registries []string, registry string) bool {
+	for _, r := range registries {
+		if strings.EqualFold(r, registry) {
+			return true
+		}
+	}
+	return false
+}
+
+// A PolicyGetter gets the current package source policy.
+type PolicyGetter interface {
+	// GetSourcePolicy returns the current package source policy, or nil if
+	// there is no policy.
+	GetSourcePolicy(ctx context.Context) (*Policy, error)
+}
+
+// A PolicyGetterFn gets the current package source policy.
+type PolicyGetterFn func(ctx context.Context) (*Policy, error)
+
+// GetSourcePolicy returns the current package source policy.
+func (fn PolicyGetterFn) GetSourcePolicy(ctx context.Context) (*Policy, error) {
+	return fn(ctx)
+}
+
+// A ConfigMapPolicyGetter gets the package source policy from a ConfigMap.
+type ConfigMapPolicyGetter struct {
+	reader client.Reader
+	ref    types.NamespacedName
+}
+
+// NewConfigMapPolicyGetter returns a PolicyGetter that gets the package source
+// policy from the supplied ConfigMap. The ConfigMap is read each time the
+// policy is needed, so the policy may be changed without restarting Crossplane.
+func NewConfigMapPolicyGetter(r client.Reader, ref types.NamespacedName) *ConfigMapPolicyGetter {
+	return &ConfigMapPolicyGetter{reader: r, ref: ref}
+}
+
+// GetSourcePolicy returns the package source policy, or nil if the ConfigMap
+// does not exist.
+func (g *ConfigMapPolicyGetter) GetSourcePolicy(ctx context.Context) (*Policy, error) {
+	cm := &corev1.ConfigMap{}
+	if err := g.reader.Get(ctx, g.ref, cm); err != nil {
+		return nil, resource.IgnoreNotFound(err)
+	}
+	return &Policy{
+		AllowedRegistries: parseRegistries(cm.Data[KeyAllowedRegistries]),
+		DeniedRegistries:  parseRegistries(cm.Data[KeyDeniedRegistries]),
+	}, nil
+}
+
+func parseRegistries(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' })
+	out := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// SetupWebhookWithManager sets up the Provider and Configuration webhooks with
+// the manager. Packages that don't specify a registry are assumed to be from
+// the supplied default registry.
+func SetupWebhookWithManager(mgr ctrl.Manager, pg PolicyGetter, defaultRegistry string) error {
+	v := &validator{policy: pg, registry: defaultRegistry}
+	if err := ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(v).
+		For(&v1.Provider{}).
+		Complete(); err != nil {
+		return err
+	}
+	return ctrl.NewWebhookManagedBy(mgr).
+		WithValidator(v).
+		For(&v1.Configuration{}).
+		Complete()
+}
+
+type validator struct {
+	policy   PolicyGetter
+	registry string
+}
+
+// ValidateCreate validates the source of a package.
+func (v *validator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	p, ok := obj.(v1.Package)
+	if !ok {
+		return nil, errors.New(errNotPackage)
+	}
+
+	pol, err := v.policy.GetSourcePolicy(ctx)
+	if err != nil {
+		return nil, apierrors.NewInternalError(errors.Wrap(err, errGetPolicy))
+	}
+	if pol == nil {
+		return nil, nil
+	}
+
+	path := field.NewPath("spec", "package")
+	ref, err := name.ParseReference(p.GetSource(), name.WithDefaultRegistry(v.registry))
+	if err != nil {
+		return nil, apierrors.NewInvalid(p.GetObjectKind().GroupVersionKind().GroupKind(), p.GetName(), field.ErrorList{field.Invalid(path, p.GetSource(), err.Error())})
+	}
+	if err := pol.Check(ref.Context().RegistryStr()); err != nil {
+		return nil, apierrors.NewInvalid(p.GetObjectKind().GroupVersionKind().GroupKind(), p.GetName(), field.ErrorList{field.Forbidden(path, err.Error())})
+	}
+	return nil, nil
+}
+
+// ValidateUpdate validates the source of a package if it changed. Packages
+// that were installed before the policy changed may otherwise be updated.
+func (v *validator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	o, ok := oldObj.(v1.Package)
+	if !ok {
+		return nil, errors.New(errNotPackage)
+	}
+	n, ok := newObj.(v1.Package)
+	if !ok {
+		return nil, errors.New(errNotPackage)
+	}
+	if o.GetSource() == n.GetSource() {
+		return nil, nil
+	}
+	return v.ValidateCreate(ctx, newObj)
+}
+
+// ValidateDelete always allows delete requests.
+func (v *validator) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/pkg/v1"
+)
+
+var (
+	_ admission.CustomValidator = &validator{}
+	_ PolicyGetter              = &ConfigMapPolicyGetter{}
+)
+
+func TestConfigMapPolicyGetter(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		p   *Policy
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		reader client.Reader
+		want   want
+	}{
+		"NotFound": {
+			reason: "There should be no policy if the ConfigMap does not exist.",
+			reader: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, ""))},
+		},
+		"GetError": {
+			reason: "We should return any other error encountered getting the ConfigMap.",
+			reader: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				err: errBoom,
+			},
+		},
+		"Success": {
+			reason: "We should parse comma and newline separated registries.",
+			reader: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*corev1.ConfigMap).Data = map[string]string{
+					KeyAllowedRegistries: "xpkg.upbound.io, registry.example.org\nghcr.io\n",
+					KeyDeniedRegistries:  "index.docker.io",
+				}
+				return nil
+			})},
+			want: want{
+				p: &Policy{
+					AllowedRegistries: []string{"xpkg.upbound.io", "registry.example.org", "ghcr.io"},
+					DeniedRegistries:  []string{"index.docker.io"},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			g := NewConfigMapPolicyGetter(tc.reader, client.ObjectKey{Namespace: "crossplane-system", Name: "cool"})
+			p, err := g.GetSourcePolicy(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nGetSourcePolicy(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.p, p); diff != "" {
+				t.Errorf("\n%s\nGetSourcePolicy(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestValidateCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+	policy := func(p *Policy) PolicyGetter {
+		return PolicyGetterFn(func(_ context.Context) (*Policy, error) { return p, nil })
+	}
+	provider := func(source string) runtime.Object {
+		p := &v1.Provider{}
+		p.SetGroupVersionKind(v1.ProviderGroupVersionKind)
+		p.SetName("cool")
+		p.SetSource(source)
+		return p
+	}
+	forbidden := func(msg string) error {
+		return kerrors.NewInvalid(v1.ProviderGroupVersionKind.GroupKind(), "cool", field.ErrorList{field.Forbidden(field.NewPath("spec", "package"), msg)})
+	}
+
+	type args struct {
+		policy PolicyGetter
+		obj    runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   error
+	}{
+		"NotAPackage": {
+			reason: "We should return an error if the supplied object is not a package.",
+			args: args{
+				policy: policy(nil),
+				obj:    &v1.ProviderRevision{},
+			},
+			want: errors.New(errNotPackage),
+		},
+		"GetPolicyError": {
+			reason: "We should return an error if we can't get the policy.",
+			args: args{
+				policy: PolicyGetterFn(func(_ context.Context) (*Policy, error) { return nil, errBoom }),
+				obj:    provider("xpkg.upbound.io/cool/provider:v1.0.0"),
+			},
+			want: kerrors.NewInternalError(errors.Wrap(errBoom, errGetPolicy)),
+		},
+		"NoPolicy": {
+			reason: "We should allow any package if there is no policy.",
+			args: args{
+				policy: policy(nil),
+				obj:    provider("registry.example.org/cool/provider:v1.0.0"),
+			},
+		},
+		"Allowed": {
+			reason: "We should allow a package from an allowed registry.",
+			args: args{
+				policy: policy(&Policy{AllowedRegistries: []string{"registry.example.org"}}),
+				obj:    provider("registry.example.org/cool/provider:v1.0.0"),
+			},
+		},
+		"NotAllowed": {
+			reason: "We should reject a package from a registry that isn't allowed.",
+			args: args{
+				policy: policy(&Policy{AllowedRegistries: []string{"registry.example.org"}}),
+				obj:    provider("ghcr.io/cool/provider:v1.0.0"),
+			},
+			want: forbidden(`registry "ghcr.io" is not allowed by the package source policy`),
+		},
+		"Denied": {
+			reason: "We should reject a package from a denied registry, even if it is allowed.",
+			args: args{
+				policy: policy(&Policy{AllowedRegistries: []string{"ghcr.io"}, DeniedRegistries: []string{"ghcr.io"}}),
+				obj:    provider("ghcr.io/cool/provider:v1.0.0"),
+			},
+			want: forbidden(`registry "ghcr.io" is denied by the package source policy`),
+		},
+		"DefaultRegistry": {
+			reason: "We should check the default registry if the package does not specify one.",
+			args: args{
+				policy: policy(&Policy{DeniedRegistries: []string{"xpkg.upbound.io"}}),
+				obj:    provider("cool/provider:v1.0.0"),
+			},
+			want: forbidden(`registry "xpkg.upbound.io" is denied by the package source policy`),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v := &validator{policy: tc.args.policy, registry: "xpkg.upbound.io"}
+			_, err := v.ValidateCreate(context.Background(), tc.args.obj)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateCreate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
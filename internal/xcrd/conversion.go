@@ -0,0 +1,159 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// ConversionWebhookPathPrefix is the path under which Crossplane serves
+// requests to convert composite resources and claims between versions.
+const ConversionWebhookPathPrefix = "/convert/"
+
+const (
+	errFmtParseAPIVersion = "cannot parse apiVersion %q"
+	errFmtGetField        = "cannot get field %q"
+	errFmtDeleteField     = "cannot delete field %q"
+	errFmtSetField        = "cannot set field %q"
+)
+
+// ConversionWebhookPath returns the path at which Crossplane serves requests
+// to convert the composite resources and claims defined by the named
+// CompositeResourceDefinition.
+func ConversionWebhookPath(xrd string) string {
+	return ConversionWebhookPathPrefix + xrd
+}
+
+// A ConversionWebhook configures how the API server calls Crossplane's
+// conversion webhook.
+type ConversionWebhook struct {
+	// Service that serves Crossplane's webhooks. Its path is ignored.
+	Service extv1.ServiceReference
+
+	// CABundle used to verify the certificate of Crossplane's webhook server.
+	CABundle []byte
+}
+
+// For returns a webhook conversion that applies the field conversions of the
+// supplied CompositeResourceDefinition.
+func (w *ConversionWebhook) For(xrd *v1.CompositeResourceDefinition) *extv1.CustomResourceConversion {
+	svc := w.Service.DeepCopy()
+	path := ConversionWebhookPath(xrd.GetName())
+	svc.Path = &path
+	return &extv1.CustomResourceConversion{
+		Strategy: extv1.WebhookConverter,
+		Webhook: &extv1.WebhookConversion{
+			ClientConfig: &extv1.WebhookClientConfig{
+				Service:  svc,
+				CABundle: w.CABundle,
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+}
+
+// Convert the supplied composite resource or claim to the supplied API
+// version by applying the field conversions of the supplied
+// CompositeResourceDefinition. Conversions from the resource's current version
+// to the desired version move fields from their FromFieldPath to their
+// ToFieldPath, while conversions from the desired version to the current
+// version move them back. Fields are moved only if they are set. All fields
+// are read before any are moved, so conversions may swap fields.
+func Convert(xrd *v1.CompositeResourceDefinition, u *unstructured.Unstructured, apiVersion string) error {
+	from, err := schema.ParseGroupVersion(u.GetAPIVersion())
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseAPIVersion, u.GetAPIVersion())
+	}
+	to, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return errors.Wrapf(err, errFmtParseAPIVersion, apiVersion)
+	}
+
+	type move struct {
+		src, dst string
+		value    any
+	}
+	moves := make([]move, 0, len(xrd.Spec.FieldConversions))
+	for _, fc := range xrd.Spec.FieldConversions {
+		switch {
+		case fc.FromVersion == from.Version && fc.ToVersion == to.Version:
+			moves = append(moves, move{src: fc.FromFieldPath, dst: fc.ToFieldPath})
+		case fc.FromVersion == to.Version && fc.ToVersion == from.Version:
+			moves = append(moves, move{src: fc.ToFieldPath, dst: fc.FromFieldPath})
+		}
+	}
+
+	p := fieldpath.Pave(u.Object)
+	set := make([]move, 0, len(moves))
+	for _, m := range moves {
+		v, err := p.GetValue(m.src)
+		if fieldpath.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, errFmtGetField, m.src)
+		}
+		m.value = v
+		set = append(set, m)
+	}
+	for _, m := range set {
+		if err := deleteField(p, m.src); err != nil {
+			return errors.Wrapf(err, errFmtDeleteField, m.src)
+		}
+	}
+	for _, m := range set {
+		if err := p.SetValue(m.dst, m.value); err != nil {
+			return errors.Wrapf(err, errFmtSetField, m.dst)
+		}
+	}
+
+	u.SetAPIVersion(apiVersion)
+	return nil
+}
+
+// deleteField deletes the supplied field, along with any parent objects that
+// are left empty by deleting it.
+func deleteField(p *fieldpath.Paved, path string) error {
+	s, err := fieldpath.Parse(path)
+	if err != nil {
+		return err
+	}
+	if err := p.DeleteField(path); err != nil {
+		return err
+	}
+	for i := len(s) - 1; i > 0; i-- {
+		parent := s[:i].String()
+		v, err := p.GetValue(parent)
+		if err != nil {
+			return err
+		}
+		if o, ok := v.(map[string]any); !ok || len(o) > 0 {
+			return nil
+		}
+		if err := p.DeleteField(parent); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestConversionWebhookFor(t *testing.T) {
+	w := &ConversionWebhook{
+		Service: extv1.ServiceReference{
+			Namespace: "crossplane-system",
+			Name:      "crossplane-webhooks",
+			Port:      pointer.Int32(9443),
+		},
+		CABundle: []byte("cool-ca"),
+	}
+	xrd := &v1.CompositeResourceDefinition{ObjectMeta: metav1.ObjectMeta{Name: "xexamples.example.org"}}
+
+	want := &extv1.CustomResourceConversion{
+		Strategy: extv1.WebhookConverter,
+		Webhook: &extv1.WebhookConversion{
+			ClientConfig: &extv1.WebhookClientConfig{
+				Service: &extv1.ServiceReference{
+					Namespace: "crossplane-system",
+					Name:      "crossplane-webhooks",
+					Port:      pointer.Int32(9443),
+					Path:      pointer.String("/convert/xexamples.example.org"),
+				},
+				CABundle: []byte("cool-ca"),
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+
+	got := w.For(xrd)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("For(...): -want, +got:\n%s", diff)
+	}
+	if w.Service.Path != nil {
+		t.Errorf("For(...): unexpectedly modified the webhook service path")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	xrd := &v1.CompositeResourceDefinition{
+		Spec: v1.CompositeResourceDefinitionSpec{
+			FieldConversions: []v1.FieldConversion{
+				{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+				{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.region", ToFieldPath: "spec.location"},
+				{FromVersion: "v2", ToVersion: "v1", FromFieldPath: "status.address", ToFieldPath: "status.endpoint"},
+				{FromVersion: "v1", ToVersion: "v3", FromFieldPath: "spec.size", ToFieldPath: "spec.capacity"},
+			},
+		},
+	}
+	v1Obj := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v1",
+			"kind":       "XExample",
+			"metadata":   map[string]any{"name": "cool-xr"},
+			"spec": map[string]any{
+				"size":   "large",
+				"region": "us-east-1",
+				"other":  "preserved",
+			},
+			"status": map[string]any{
+				"endpoint": "example.org",
+			},
+		}}
+	}
+	v2Obj := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "example.org/v2",
+			"kind":       "XExample",
+			"metadata":   map[string]any{"name": "cool-xr"},
+			"spec": map[string]any{
+				"parameters": map[string]any{"size": "large"},
+				"location":   "us-east-1",
+				"other":      "preserved",
+			},
+			"status": map[string]any{
+				"address": "example.org",
+			},
+		}}
+	}
+
+	type args struct {
+		u          *unstructured.Unstructured
+		apiVersion string
+	}
+	type want struct {
+		u   *unstructured.Unstructured
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Upgrade": {
+			reason: "Fields should be moved from their FromFieldPath to their ToFieldPath when converting to the ToVersion.",
+			args: args{
+				u:          v1Obj(),
+				apiVersion: "example.org/v2",
+			},
+			want: want{
+				u: v2Obj(),
+			},
+		},
+		"Downgrade": {
+			reason: "Fields should be moved from their ToFieldPath back to their FromFieldPath when converting to the FromVersion.",
+			args: args{
+				u:          v2Obj(),
+				apiVersion: "example.org/v1",
+			},
+			want: want{
+				u: v1Obj(),
+			},
+		},
+		"UnsetFields": {
+			reason: "Fields that aren't set should not be moved.",
+			args: args{
+				u: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v1",
+					"kind":       "XExample",
+					"spec":       map[string]any{"region": "us-east-1"},
+				}},
+				apiVersion: "example.org/v2",
+			},
+			want: want{
+				u: &unstructured.Unstructured{Object: map[string]any{
+					"apiVersion": "example.org/v2",
+					"kind":       "XExample",
+					"spec":       map[string]any{"location": "us-east-1"},
+				}},
+			},
+		},
+		"NoConversions": {
+			reason: "Only the apiVersion should change when there are no conversions between two versions.",
+			args: args{
+				u:          v2Obj(),
+				apiVersion: "example.org/v3",
+			},
+			want: want{
+				u: func() *unstructured.Unstructured {
+					u := v2Obj()
+					u.SetAPIVersion("example.org/v3")
+					return u
+				}(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := Convert(xrd, tc.args.u, tc.args.apiVersion)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.u, tc.args.u); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	xrd := &v1.CompositeResourceDefinition{
+		Spec: v1.CompositeResourceDefinitionSpec{
+			FieldConversions: []v1.FieldConversion{
+				{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.size", ToFieldPath: "spec.parameters.size"},
+				{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.a", ToFieldPath: "spec.b"},
+				{FromVersion: "v1", ToVersion: "v2", FromFieldPath: "spec.b", ToFieldPath: "spec.a"},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		reason string
+		u      *unstructured.Unstructured
+		via    string
+	}{
+		"FromV1": {
+			reason: "A v1 resource should be unchanged after converting it to v2 and back, even if conversions swap fields.",
+			u: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "example.org/v1",
+				"kind":       "XExample",
+				"spec":       map[string]any{"size": "large", "a": "cool", "b": int64(42)},
+			}},
+			via: "example.org/v2",
+		},
+		"FromV2": {
+			reason: "A v2 resource should be unchanged after converting it to v1 and back.",
+			u: &unstructured.Unstructured{Object: map[string]any{
+				"apiVersion": "example.org/v2",
+				"kind":       "XExample",
+				"spec":       map[string]any{"parameters": map[string]any{"size": "large"}, "a": "cool"},
+			}},
+			via: "example.org/v1",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			want := tc.u.DeepCopy()
+			got := tc.u.DeepCopy()
+			if err := Convert(xrd, got, tc.via); err != nil {
+				t.Fatalf("\n%s\nConvert(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(want, got); diff == "" {
+				t.Errorf("\n%s\nConvert(...): conversion did not change the resource", tc.reason)
+			}
+			if err := Convert(xrd, got, want.GetAPIVersion()); err != nil {
+				t.Fatalf("\n%s\nConvert(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,9 @@ package xcrd
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
 
 	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -47,11 +50,91 @@ const (
 	errInvalidClaimNames       = "invalid resource claim names"
 	errMissingClaimNames       = "missing names"
 	errFmtConflictingClaimName = "%q conflicts with composite resource name"
+	errFmtEnforcedComposition  = "compositionRef.name must be %q because CompositeResourceDefinition %q enforces it"
 )
 
+// A CRDOption configures the CustomResourceDefinitions derived from a
+// CompositeResourceDefinition.
+type CRDOption func(o *crdOptions)
+
+type crdOptions struct {
+	composedResourceStatus bool
+	resolvedEnvironment    bool
+}
+
+// WithComposedResourceStatus adds the status fields Crossplane uses to
+// summarize the state of composed resources to derived CRDs. XRDs may define
+// fields of the same name, so these are only added when Crossplane populates
+// them.
+func WithComposedResourceStatus() CRDOption {
+	return func(o *crdOptions) {
+		o.composedResourceStatus = true
+	}
+}
+
+// WithResolvedEnvironment adds the status field Crossplane uses to record the
+// environment used to compose a composite resource to derived CRDs. XRDs may
+// define a field of the same name, so it's only added when Crossplane may
+// populate it.
+func WithResolvedEnvironment() CRDOption {
+	return func(o *crdOptions) {
+		o.resolvedEnvironment = true
+	}
+}
+
+// CompositeResourceStatusPropsFor returns the status fields Crossplane adds to
+// the CRD of a composite resource, given the supplied options.
+func CompositeResourceStatusPropsFor(opts ...CRDOption) map[string]extv1.JSONSchemaProps {
+	o := &crdOptions{}
+	for _, fn := range opts {
+		fn(o)
+	}
+	props := map[string]extv1.JSONSchemaProps{}
+	if o.composedResourceStatus {
+		for k, v := range CompositeResourceComposedStatusProps() {
+			props[k] = v
+		}
+	}
+	if o.resolvedEnvironment {
+		for k, v := range CompositeResourceResolvedEnvironmentStatusProps() {
+			props[k] = v
+		}
+	}
+	return props
+}
+
+// CompositeResourceClaimStatusPropsFor returns the status fields Crossplane
+// adds to the CRD of a composite resource claim, given the supplied options.
+func CompositeResourceClaimStatusPropsFor(opts ...CRDOption) map[string]extv1.JSONSchemaProps {
+	o := &crdOptions{}
+	for _, fn := range opts {
+		fn(o)
+	}
+	if !o.composedResourceStatus {
+		return map[string]extv1.JSONSchemaProps{}
+	}
+	return CompositeResourceClaimStatusProps()
+}
+
+// CompositeResourceOnlyStatusFields returns the status fields Crossplane adds
+// to the CRD of a composite resource, but not to its claim, given the supplied
+// options. These fields aren't propagated from a composite resource to its
+// claim.
+func CompositeResourceOnlyStatusFields(opts ...CRDOption) []string {
+	claim := CompositeResourceClaimStatusPropsFor(opts...)
+	out := make([]string, 0)
+	for k := range CompositeResourceStatusPropsFor(opts...) {
+		if _, ok := claim[k]; !ok {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
 // ForCompositeResource derives the CustomResourceDefinition for a composite
 // resource from the supplied CompositeResourceDefinition.
-func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+func ForCompositeResource(xrd *v1.CompositeResourceDefinition, opts ...CRDOption) (*extv1.CustomResourceDefinition, error) {
 	crd := &extv1.CustomResourceDefinition{
 		Spec: extv1.CustomResourceDefinitionSpec{
 			Scope:      extv1.ClusterScoped,
@@ -75,10 +158,14 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 		if err != nil {
 			return nil, errors.Wrapf(err, errFmtGenCrd, "Composite Resource", xrd.Name)
 		}
-		crdv.AdditionalPrinterColumns = append(crdv.AdditionalPrinterColumns, CompositeResourcePrinterColumns()...)
+		crdv.AdditionalPrinterColumns = printerColumns(vr.AdditionalPrinterColumns, CompositeResourcePrinterColumns(),
+			onlyIn("spec", CompositeResourceClaimSpecProps(), CompositeResourceSpecProps()))
 		for k, v := range CompositeResourceSpecProps() {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
+		for k, v := range CompositeResourceStatusPropsFor(opts...) {
+			crdv.Schema.OpenAPIV3Schema.Properties["status"].Properties[k] = v
+		}
 		crd.Spec.Versions[i] = *crdv
 	}
 
@@ -87,7 +174,7 @@ func ForCompositeResource(xrd *v1.CompositeResourceDefinition) (*extv1.CustomRes
 
 // ForCompositeResourceClaim derives the CustomResourceDefinition for a
 // composite resource claim from the supplied CompositeResourceDefinition.
-func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.CustomResourceDefinition, error) {
+func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition, opts ...CRDOption) (*extv1.CustomResourceDefinition, error) {
 	if err := validateClaimNames(xrd); err != nil {
 		return nil, errors.Wrap(err, errInvalidClaimNames)
 	}
@@ -115,24 +202,46 @@ func ForCompositeResourceClaim(xrd *v1.CompositeResourceDefinition) (*extv1.Cust
 		if err != nil {
 			return nil, errors.Wrapf(err, errFmtGenCrd, "Composite Resource Claim", xrd.Name)
 		}
-		crdv.AdditionalPrinterColumns = append(crdv.AdditionalPrinterColumns, CompositeResourceClaimPrinterColumns()...)
+		crdv.AdditionalPrinterColumns = printerColumns(vr.AdditionalPrinterColumns, CompositeResourceClaimPrinterColumns(),
+			append(onlyIn("spec", CompositeResourceSpecProps(), CompositeResourceClaimSpecProps()),
+				onlyIn("status", CompositeResourceStatusPropsFor(opts...), CompositeResourceClaimStatusPropsFor(opts...))...))
 		for k, v := range CompositeResourceClaimSpecProps() {
 			crdv.Schema.OpenAPIV3Schema.Properties["spec"].Properties[k] = v
 		}
+		cSpec := crdv.Schema.OpenAPIV3Schema.Properties["spec"]
+		cSpec.XValidations = append(cSpec.XValidations, enforcedCompositionRules(xrd)...)
+		crdv.Schema.OpenAPIV3Schema.Properties["spec"] = cSpec
+		for k, v := range CompositeResourceClaimStatusPropsFor(opts...) {
+			crdv.Schema.OpenAPIV3Schema.Properties["status"].Properties[k] = v
+		}
 		crd.Spec.Versions[i] = *crdv
 	}
 
 	return crd, nil
 }
 
+// enforcedCompositionRules returns validation rules that reject a claim that
+// references a Composition other than the one the supplied XRD enforces, if
+// any. The claim's composite resource would use the enforced Composition
+// regardless, so we tell the author as early as possible.
+func enforcedCompositionRules(xrd *v1.CompositeResourceDefinition) extv1.ValidationRules {
+	if xrd.Spec.EnforcedCompositionRef == nil {
+		return nil
+	}
+	name := xrd.Spec.EnforcedCompositionRef.Name
+	return extv1.ValidationRules{{
+		Rule:    fmt.Sprintf("!has(self.compositionRef) || self.compositionRef.name == %q", name),
+		Message: fmt.Sprintf(errFmtEnforcedComposition, name, xrd.GetName()),
+	}}
+}
+
 func genCrdVersion(vr v1.CompositeResourceDefinitionVersion) (*extv1.CustomResourceDefinitionVersion, error) {
 	crdv := extv1.CustomResourceDefinitionVersion{
-		Name:                     vr.Name,
-		Served:                   vr.Served,
-		Storage:                  vr.Referenceable,
-		Deprecated:               pointer.BoolDeref(vr.Deprecated, false),
-		DeprecationWarning:       vr.DeprecationWarning,
-		AdditionalPrinterColumns: vr.AdditionalPrinterColumns,
+		Name:               vr.Name,
+		Served:             vr.Served,
+		Storage:            vr.Referenceable,
+		Deprecated:         pointer.BoolDeref(vr.Deprecated, false),
+		DeprecationWarning: vr.DeprecationWarning,
 		Schema: &extv1.CustomResourceValidation{
 			OpenAPIV3Schema: BaseProps(),
 		},
@@ -172,6 +281,53 @@ func genCrdVersion(vr v1.CompositeResourceDefinitionVersion) (*extv1.CustomResou
 	return &crdv, nil
 }
 
+// printerColumns returns the supplied XRD printer columns followed by the
+// supplied default printer columns. A default column is omitted if an XRD
+// column has the same name. An XRD column is omitted if its JSONPath references
+// one of the supplied fields, which don't exist in the generated CRD. This
+// allows one set of XRD columns to be used for both XR and claim CRDs.
+func printerColumns(xrd, defaults []extv1.CustomResourceColumnDefinition, omit []string) []extv1.CustomResourceColumnDefinition {
+	out := make([]extv1.CustomResourceColumnDefinition, 0, len(xrd)+len(defaults))
+	names := map[string]bool{}
+	for _, c := range xrd {
+		if referencesAny(c.JSONPath, omit) {
+			continue
+		}
+		names[c.Name] = true
+		out = append(out, c)
+	}
+	for _, c := range defaults {
+		if names[c.Name] {
+			continue
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// onlyIn returns the JSONPaths of the supplied parent's fields that are in a
+// but not in b, e.g. ".spec.resourceRefs".
+func onlyIn(parent string, a, b map[string]extv1.JSONSchemaProps) []string {
+	out := make([]string, 0, len(a))
+	for k := range a {
+		if _, ok := b[k]; !ok {
+			out = append(out, "."+parent+"."+k)
+		}
+	}
+	return out
+}
+
+// referencesAny returns true if the supplied JSONPath references any of the
+// supplied fields, or anything nested beneath them.
+func referencesAny(jsonPath string, fields []string) bool {
+	for _, f := range fields {
+		if jsonPath == f || strings.HasPrefix(jsonPath, f+".") || strings.HasPrefix(jsonPath, f+"[") {
+			return true
+		}
+	}
+	return false
+}
+
 func validateClaimNames(d *v1.CompositeResourceDefinition) error {
 	if d.Spec.ClaimNames == nil {
 		return errors.New(errMissingClaimNames)
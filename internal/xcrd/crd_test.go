@@ -30,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
 
 	"github.com/crossplane/crossplane-runtime/pkg/errors"
 	"github.com/crossplane/crossplane-runtime/pkg/meta"
@@ -406,6 +407,41 @@ func TestForCompositeResource(t *testing.T) {
 										Type: "object",
 										Properties: map[string]extv1.JSONSchemaProps{
 											"lastPublishedTime": {Type: "string", Format: "date-time"},
+											"count":             {Type: "integer"},
+										},
+									},
+
+									// From CompositeResourceComposedStatusProps()
+									"resources": {
+										Description: "Resources summarizes the state of each composed resource.",
+										Type:        "array",
+										Items: &extv1.JSONSchemaPropsOrArray{
+											Schema: &extv1.JSONSchemaProps{
+												Type:     "object",
+												Required: []string{"name", "ready", "synced"},
+												Properties: map[string]extv1.JSONSchemaProps{
+													"name":         {Type: "string"},
+													"apiVersion":   {Type: "string"},
+													"kind":         {Type: "string"},
+													"resourceName": {Type: "string"},
+													"ready":        {Type: "boolean"},
+													"synced":       {Type: "boolean"},
+													"message":      {Type: "string"},
+												},
+											},
+										},
+									},
+									"resourcesReady": {
+										Description: "ResourcesReady summarizes how many composed resources are ready.",
+										Type:        "string",
+									},
+									"resolvedEnvironment": {
+										Description: "ResolvedEnvironment records the environment used to compose the resource, if the Composition's environment.recordResolved is true.",
+										Type:        "object",
+										Properties: map[string]extv1.JSONSchemaProps{
+											"data":      {Type: "object", XPreserveUnknownFields: pointer.Bool(true)},
+											"hash":      {Type: "string"},
+											"truncated": {Type: "boolean"},
 										},
 									},
 								},
@@ -423,7 +459,7 @@ func TestForCompositeResource(t *testing.T) {
 		},
 	}
 
-	got, err := ForCompositeResource(d)
+	got, err := ForCompositeResource(d, WithComposedResourceStatus(), WithResolvedEnvironment())
 	if err != nil {
 		t.Fatalf("ForCompositeResource(...): %s", err)
 	}
@@ -702,6 +738,7 @@ func TestForCompositeResourceEmptyXrd(t *testing.T) {
 										Type: "object",
 										Properties: map[string]extv1.JSONSchemaProps{
 											"lastPublishedTime": {Type: "string", Format: "date-time"},
+											"count":             {Type: "integer"},
 										},
 									},
 								},
@@ -1104,7 +1141,8 @@ func TestForCompositeResourceClaim(t *testing.T) {
 											Type:     "object",
 											Required: []string{"name"},
 											Properties: map[string]extv1.JSONSchemaProps{
-												"name": {Type: "string"},
+												"name":      {Type: "string"},
+												"namespace": {Type: "string"},
 											},
 										},
 									},
@@ -1143,8 +1181,15 @@ func TestForCompositeResourceClaim(t *testing.T) {
 											Type: "object",
 											Properties: map[string]extv1.JSONSchemaProps{
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
+												"count":             {Type: "integer"},
 											},
 										},
+
+										// From CompositeResourceClaimStatusProps()
+										"resourcesReady": {
+											Description: "ResourcesReady summarizes how many composed resources are ready.",
+											Type:        "string",
+										},
 									},
 									XValidations: extv1.ValidationRules{
 										{
@@ -1161,7 +1206,7 @@ func TestForCompositeResourceClaim(t *testing.T) {
 		},
 	}
 
-	got, err := ForCompositeResourceClaim(d)
+	got, err := ForCompositeResourceClaim(d, WithComposedResourceStatus(), WithResolvedEnvironment())
 	if err != nil {
 		t.Fatalf("ForCompositeResourceClaim(...): %s", err)
 	}
@@ -1396,7 +1441,8 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 											Type:     "object",
 											Required: []string{"name"},
 											Properties: map[string]extv1.JSONSchemaProps{
-												"name": {Type: "string"},
+												"name":      {Type: "string"},
+												"namespace": {Type: "string"},
 											},
 										},
 									},
@@ -1427,6 +1473,7 @@ func TestForCompositeResourceClaimEmptyXrd(t *testing.T) {
 											Type: "object",
 											Properties: map[string]extv1.JSONSchemaProps{
 												"lastPublishedTime": {Type: "string", Format: "date-time"},
+												"count":             {Type: "integer"},
 											},
 										},
 									},
@@ -1634,3 +1681,101 @@ func TestSetCrdMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestPrinterColumns(t *testing.T) {
+	type args struct {
+		xrd      []extv1.CustomResourceColumnDefinition
+		defaults []extv1.CustomResourceColumnDefinition
+		omit     []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   []extv1.CustomResourceColumnDefinition
+	}{
+		"NoXRDColumns": {
+			reason: "We should return the default columns if the XRD specifies none.",
+			args: args{
+				defaults: []extv1.CustomResourceColumnDefinition{{Name: "AGE", JSONPath: ".metadata.creationTimestamp"}},
+			},
+			want: []extv1.CustomResourceColumnDefinition{{Name: "AGE", JSONPath: ".metadata.creationTimestamp"}},
+		},
+		"Merge": {
+			reason: "We should return XRD columns followed by default columns, omitting defaults that an XRD column replaces.",
+			args: args{
+				xrd: []extv1.CustomResourceColumnDefinition{
+					{Name: "ENDPOINT", JSONPath: ".status.endpoint"},
+					{Name: "READY", JSONPath: ".status.ready"},
+				},
+				defaults: []extv1.CustomResourceColumnDefinition{
+					{Name: "READY", JSONPath: ".status.conditions[?(@.type=='Ready')].status"},
+					{Name: "AGE", JSONPath: ".metadata.creationTimestamp"},
+				},
+			},
+			want: []extv1.CustomResourceColumnDefinition{
+				{Name: "ENDPOINT", JSONPath: ".status.endpoint"},
+				{Name: "READY", JSONPath: ".status.ready"},
+				{Name: "AGE", JSONPath: ".metadata.creationTimestamp"},
+			},
+		},
+		"OmitMissingFields": {
+			reason: "We should omit XRD columns that reference fields that don't exist in the generated CRD.",
+			args: args{
+				xrd: []extv1.CustomResourceColumnDefinition{
+					{Name: "SIZE", JSONPath: ".spec.parameters.size"},
+					{Name: "RESOURCES", JSONPath: ".spec.resourceRefs[*].name"},
+					{Name: "CLAIM", JSONPath: ".spec.claimRef.name"},
+				},
+				omit: onlyIn("spec", CompositeResourceSpecProps(), CompositeResourceClaimSpecProps()),
+			},
+			want: []extv1.CustomResourceColumnDefinition{
+				{Name: "SIZE", JSONPath: ".spec.parameters.size"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := printerColumns(tc.args.xrd, tc.args.defaults, tc.args.omit)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nprinterColumns(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEnforcedCompositionRules(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		xrd    *v1.CompositeResourceDefinition
+		want   extv1.ValidationRules
+	}{
+		"NotEnforced": {
+			reason: "There should be no rules if the XRD doesn't enforce a Composition.",
+			xrd:    &v1.CompositeResourceDefinition{},
+		},
+		"Enforced": {
+			reason: "Claims should be required to reference the enforced Composition, if they reference one.",
+			xrd: &v1.CompositeResourceDefinition{
+				ObjectMeta: metav1.ObjectMeta{Name: "coolcomposites.example.org"},
+				Spec: v1.CompositeResourceDefinitionSpec{
+					EnforcedCompositionRef: &v1.CompositionReference{Name: "cool"},
+				},
+			},
+			want: extv1.ValidationRules{{
+				Rule:    `!has(self.compositionRef) || self.compositionRef.name == "cool"`,
+				Message: `compositionRef.name must be "cool" because CompositeResourceDefinition "coolcomposites.example.org" enforces it`,
+			}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := enforcedCompositionRules(tc.xrd)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nenforcedCompositionRules(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	"context"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// crossplaneDomain is the domain of the label and annotation keys managed by
+// Crossplane itself.
+const crossplaneDomain = "crossplane.io"
+
+// IsPropagatedMetadataKey returns true if a label or annotation with the
+// supplied key should be propagated from a claim to its composite resource, and
+// from the composite resource to its composed resources. Keys are propagated if
+// they start with one of the supplied prefixes, unless they're managed by
+// Crossplane.
+func IsPropagatedMetadataKey(key string, prefixes []string) bool {
+	if IsCrossplaneMetadataKey(key) {
+		return false
+	}
+	for _, p := range prefixes {
+		if strings.HasPrefix(key, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCrossplaneMetadataKey returns true if the supplied label or annotation key
+// is in the crossplane.io domain, or one of its subdomains.
+func IsCrossplaneMetadataKey(key string) bool {
+	domain, _, ok := strings.Cut(key, "/")
+	if !ok {
+		return false
+	}
+	return domain == crossplaneDomain || strings.HasSuffix(domain, "."+crossplaneDomain)
+}
+
+// PropagatedMetadata returns the subset of the supplied labels or annotations
+// that should be propagated.
+func PropagatedMetadata(m map[string]string, prefixes []string) map[string]string {
+	out := map[string]string{}
+	for k, v := range m {
+		if IsPropagatedMetadataKey(k, prefixes) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// RemoveStaleMetadata returns an ApplyOption that removes propagated labels and
+// annotations that are present on the current object but absent from the
+// desired object. Applicators that patch an object would otherwise leave them
+// in place. The option has no effect unless the desired object is unstructured.
+func RemoveStaleMetadata(prefixes ...string) resource.ApplyOption {
+	return func(_ context.Context, current, desired runtime.Object) error {
+		cm, ok := current.(metav1.Object)
+		if !ok {
+			return nil
+		}
+		dm, ok := desired.(metav1.Object)
+		if !ok {
+			return nil
+		}
+		u, ok := desired.(runtime.Unstructured)
+		if !ok {
+			return nil
+		}
+
+		// A null value removes a key when the desired object is sent as a
+		// JSON merge patch.
+		for _, k := range StaleMetadataKeys(cm.GetLabels(), dm.GetLabels(), prefixes) {
+			if err := unstructured.SetNestedField(u.UnstructuredContent(), nil, "metadata", "labels", k); err != nil {
+				return err
+			}
+		}
+		for _, k := range StaleMetadataKeys(cm.GetAnnotations(), dm.GetAnnotations(), prefixes) {
+			if err := unstructured.SetNestedField(u.UnstructuredContent(), nil, "metadata", "annotations", k); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// StaleMetadataKeys returns the propagated keys of the supplied current labels
+// or annotations that are absent from the supplied desired labels or
+// annotations.
+func StaleMetadataKeys(current, desired map[string]string, prefixes []string) []string {
+	stale := make([]string, 0)
+	for k := range current {
+		if _, ok := desired[k]; !ok && IsPropagatedMetadataKey(k, prefixes) {
+			stale = append(stale, k)
+		}
+	}
+	return stale
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/unstructured/composed"
+)
+
+func TestIsPropagatedMetadataKey(t *testing.T) {
+	type args struct {
+		key      string
+		prefixes []string
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   bool
+	}{
+		"NoPrefixes": {
+			reason: "Keys should not be propagated if no prefixes are configured.",
+			args: args{
+				key: "cost-center",
+			},
+			want: false,
+		},
+		"MatchingPrefix": {
+			reason: "Keys that start with a configured prefix should be propagated.",
+			args: args{
+				key:      "example.org/cost-center",
+				prefixes: []string{"team", "example.org/"},
+			},
+			want: true,
+		},
+		"NoMatchingPrefix": {
+			reason: "Keys that don't start with a configured prefix should not be propagated.",
+			args: args{
+				key:      "cost-center",
+				prefixes: []string{"team", "example.org/"},
+			},
+			want: false,
+		},
+		"CrossplaneKey": {
+			reason: "Keys in the crossplane.io domain should never be propagated.",
+			args: args{
+				key:      LabelKeyClaimName,
+				prefixes: []string{""},
+			},
+			want: false,
+		},
+		"CrossplaneSubdomainKey": {
+			reason: "Keys in subdomains of the crossplane.io domain should never be propagated.",
+			args: args{
+				key:      "pkg.crossplane.io/revision",
+				prefixes: []string{"pkg."},
+			},
+			want: false,
+		},
+		"SimilarDomainKey": {
+			reason: "Keys in domains that merely end in crossplane.io should be propagated.",
+			args: args{
+				key:      "notcrossplane.io/cool",
+				prefixes: []string{"notcrossplane.io/"},
+			},
+			want: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IsPropagatedMetadataKey(tc.args.key, tc.args.prefixes)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nIsPropagatedMetadataKey(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRemoveStaleMetadata(t *testing.T) {
+	type args struct {
+		prefixes []string
+		current  runtime.Object
+		desired  runtime.Object
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   runtime.Object
+	}{
+		"RemoveStale": {
+			reason: "Propagated keys that are present on the current object but absent from the desired object should be nulled.",
+			args: args{
+				prefixes: []string{"example.org/"},
+				current: func() runtime.Object {
+					cd := composed.New()
+					cd.SetLabels(map[string]string{"example.org/team": "cool", "other": "cool"})
+					cd.SetAnnotations(map[string]string{"example.org/owner": "cool"})
+					return cd
+				}(),
+				desired: func() runtime.Object {
+					cd := composed.New()
+					cd.SetLabels(map[string]string{"example.org/cost-center": "cool"})
+					return cd
+				}(),
+			},
+			want: func() runtime.Object {
+				cd := composed.New()
+				cd.Object["metadata"] = map[string]any{
+					"labels": map[string]any{
+						"example.org/cost-center": "cool",
+						"example.org/team":        nil,
+					},
+					"annotations": map[string]any{
+						"example.org/owner": nil,
+					},
+				}
+				return cd
+			}(),
+		},
+		"NothingStale": {
+			reason: "The desired object should be unchanged if nothing is stale.",
+			args: args{
+				prefixes: []string{"example.org/"},
+				current: func() runtime.Object {
+					cd := composed.New()
+					cd.SetLabels(map[string]string{"example.org/team": "cool", LabelKeyClaimName: "cool"})
+					return cd
+				}(),
+				desired: func() runtime.Object {
+					cd := composed.New()
+					cd.SetLabels(map[string]string{"example.org/team": "cool"})
+					return cd
+				}(),
+			},
+			want: func() runtime.Object {
+				cd := composed.New()
+				cd.SetLabels(map[string]string{"example.org/team": "cool"})
+				return cd
+			}(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := RemoveStaleMetadata(tc.args.prefixes...)(context.Background(), tc.args.current, tc.args.desired)
+			if err != nil {
+				t.Fatalf("\n%s\nRemoveStaleMetadata(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, tc.args.desired); diff != "" {
+				t.Errorf("\n%s\nRemoveStaleMetadata(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -16,7 +16,10 @@ limitations under the License.
 
 package xcrd
 
-import extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+import (
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/pointer"
+)
 
 // Label keys.
 const (
@@ -314,6 +317,10 @@ func CompositeResourceClaimSpecProps() map[string]extv1.JSONSchemaProps {
 			Required: []string{"name"},
 			Properties: map[string]extv1.JSONSchemaProps{
 				"name": {Type: "string"},
+				// The namespace is only honored if cross namespace
+				// connection secrets are enabled. It defaults to the
+				// claim's namespace.
+				"namespace": {Type: "string"},
 			},
 		},
 	}
@@ -345,8 +352,70 @@ func CompositeResourceStatusProps() map[string]extv1.JSONSchemaProps {
 			Type: "object",
 			Properties: map[string]extv1.JSONSchemaProps{
 				"lastPublishedTime": {Type: "string", Format: "date-time"},
+				"count":             {Type: "integer"},
+			},
+		},
+	}
+}
+
+// CompositeResourceComposedStatusProps is a partial OpenAPIV3Schema for the
+// status fields that Crossplane uses to summarize the state of a composite
+// resource's composed resources.
+func CompositeResourceComposedStatusProps() map[string]extv1.JSONSchemaProps {
+	return map[string]extv1.JSONSchemaProps{
+		"resources": {
+			Description: "Resources summarizes the state of each composed resource.",
+			Type:        "array",
+			Items: &extv1.JSONSchemaPropsOrArray{
+				Schema: &extv1.JSONSchemaProps{
+					Type:     "object",
+					Required: []string{"name", "ready", "synced"},
+					Properties: map[string]extv1.JSONSchemaProps{
+						"name":         {Type: "string"},
+						"apiVersion":   {Type: "string"},
+						"kind":         {Type: "string"},
+						"resourceName": {Type: "string"},
+						"ready":        {Type: "boolean"},
+						"synced":       {Type: "boolean"},
+						"message":      {Type: "string"},
+					},
+				},
 			},
 		},
+		"resourcesReady": resourcesReadyProps(),
+	}
+}
+
+// CompositeResourceResolvedEnvironmentStatusProps is a partial OpenAPIV3Schema
+// for the status field that Crossplane uses to record the environment used to
+// compose a composite resource.
+func CompositeResourceResolvedEnvironmentStatusProps() map[string]extv1.JSONSchemaProps {
+	return map[string]extv1.JSONSchemaProps{
+		"resolvedEnvironment": {
+			Description: "ResolvedEnvironment records the environment used to compose the resource, if the Composition's environment.recordResolved is true.",
+			Type:        "object",
+			Properties: map[string]extv1.JSONSchemaProps{
+				"data":      {Type: "object", XPreserveUnknownFields: pointer.Bool(true)},
+				"hash":      {Type: "string"},
+				"truncated": {Type: "boolean"},
+			},
+		},
+	}
+}
+
+// CompositeResourceClaimStatusProps is a partial OpenAPIV3Schema for the status
+// fields that Crossplane expects to be present for all published
+// infrastructure resources.
+func CompositeResourceClaimStatusProps() map[string]extv1.JSONSchemaProps {
+	return map[string]extv1.JSONSchemaProps{
+		"resourcesReady": resourcesReadyProps(),
+	}
+}
+
+func resourcesReadyProps() extv1.JSONSchemaProps {
+	return extv1.JSONSchemaProps{
+		Description: "ResourcesReady summarizes how many composed resources are ready.",
+		Type:        "string",
 	}
 }
 
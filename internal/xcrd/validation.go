@@ -0,0 +1,188 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	"strings"
+
+	admv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+// ClaimValidationWebhookPathPrefix is the path under which Crossplane serves
+// requests to validate claims.
+const ClaimValidationWebhookPathPrefix = "/validate-claims/"
+
+const (
+	errFmtUnknownClaimVersion = "version %q is not defined by the claim schema"
+	errConvertSchema          = "cannot convert claim schema"
+	errNewSchemaValidator     = "cannot build claim schema validator"
+	errNewStructuralSchema    = "cannot build structural claim schema"
+
+	msgUnknownField = "field is not declared by the claim's schema"
+)
+
+// ClaimValidationWebhookPath returns the path at which Crossplane serves
+// requests to validate the claims defined by the named
+// CompositeResourceDefinition.
+func ClaimValidationWebhookPath(xrd string) string {
+	return ClaimValidationWebhookPathPrefix + xrd
+}
+
+// ClaimValidationWebhookConfigurationName returns the name of the
+// ValidatingWebhookConfiguration that configures the API server to validate
+// the claims defined by the named CompositeResourceDefinition.
+func ClaimValidationWebhookConfigurationName(xrd string) string {
+	return "crossplane-claims-" + xrd
+}
+
+// A ClaimValidationWebhook configures how the API server calls Crossplane's
+// claim validation webhook.
+type ClaimValidationWebhook struct {
+	// Service that serves Crossplane's webhooks. Its path is ignored.
+	Service extv1.ServiceReference
+
+	// CABundle used to verify the certificate of Crossplane's webhook server.
+	CABundle []byte
+}
+
+// For returns a ValidatingWebhookConfiguration that calls Crossplane's claim
+// validation webhook when a claim defined by the supplied
+// CompositeResourceDefinition is created or updated. The configuration is
+// controlled by the CompositeResourceDefinition.
+func (w *ClaimValidationWebhook) For(xrd *v1.CompositeResourceDefinition) *admv1.ValidatingWebhookConfiguration {
+	path := ClaimValidationWebhookPath(xrd.GetName())
+	svc := &admv1.ServiceReference{
+		Name:      w.Service.Name,
+		Namespace: w.Service.Namespace,
+		Port:      w.Service.Port,
+		Path:      &path,
+	}
+
+	versions := make([]string, 0, len(xrd.Spec.Versions))
+	for _, v := range xrd.Spec.Versions {
+		if v.Served {
+			versions = append(versions, v.Name)
+		}
+	}
+
+	var plural string
+	if xrd.Spec.ClaimNames != nil {
+		plural = xrd.Spec.ClaimNames.Plural
+	}
+
+	scope := admv1.NamespacedScope
+	fail := admv1.Fail
+	none := admv1.SideEffectClassNone
+
+	wc := &admv1.ValidatingWebhookConfiguration{
+		Webhooks: []admv1.ValidatingWebhook{{
+			Name: "claims." + xrd.GetName(),
+			ClientConfig: admv1.WebhookClientConfig{
+				Service:  svc,
+				CABundle: w.CABundle,
+			},
+			Rules: []admv1.RuleWithOperations{{
+				Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+				Rule: admv1.Rule{
+					APIGroups:   []string{xrd.Spec.Group},
+					APIVersions: versions,
+					Resources:   []string{plural},
+					Scope:       &scope,
+				},
+			}},
+			FailurePolicy:           &fail,
+			SideEffects:             &none,
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+	wc.SetName(ClaimValidationWebhookConfigurationName(xrd.GetName()))
+	wc.SetOwnerReferences([]metav1.OwnerReference{meta.AsController(
+		meta.TypedReferenceTo(xrd, v1.CompositeResourceDefinitionGroupVersionKind),
+	)})
+	return wc
+}
+
+// ValidateClaim validates the supplied claim against the schema the supplied
+// CompositeResourceDefinition declares for the claim's version. Errors
+// reference the claim's fields by their path. If the
+// CompositeResourceDefinition enables strict claim validation, fields under
+// the claim's spec that aren't declared by its schema are also reported,
+// rather than silently pruned.
+func ValidateClaim(xrd *v1.CompositeResourceDefinition, u *unstructured.Unstructured) (field.ErrorList, error) {
+	crd, err := ForCompositeResourceClaim(xrd)
+	if err != nil {
+		return nil, err
+	}
+	gv, err := schema.ParseGroupVersion(u.GetAPIVersion())
+	if err != nil {
+		return nil, errors.Wrapf(err, errFmtParseAPIVersion, u.GetAPIVersion())
+	}
+
+	var s *extv1.JSONSchemaProps
+	for _, v := range crd.Spec.Versions {
+		if v.Name == gv.Version && v.Schema != nil {
+			s = v.Schema.OpenAPIV3Schema
+		}
+	}
+	if s == nil {
+		return nil, errors.Errorf(errFmtUnknownClaimVersion, gv.Version)
+	}
+
+	in := &apiextensions.JSONSchemaProps{}
+	if err := extv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(s, in, nil); err != nil {
+		return nil, errors.Wrap(err, errConvertSchema)
+	}
+	sv, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: in})
+	if err != nil {
+		return nil, errors.Wrap(err, errNewSchemaValidator)
+	}
+	errs := validation.ValidateCustomResource(nil, u.UnstructuredContent(), sv)
+
+	if xrd.Spec.ClaimValidation == nil || !xrd.Spec.ClaimValidation.Strict {
+		return errs, nil
+	}
+
+	ss, err := structuralschema.NewStructural(in)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewStructuralSchema)
+	}
+
+	// Pruning a copy of the claim tells us which fields the API server
+	// would silently drop.
+	unknown := pruning.PruneWithOptions(u.DeepCopy().UnstructuredContent(), ss, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})
+	for _, p := range unknown {
+		if !strings.HasPrefix(p, "spec.") {
+			continue
+		}
+		errs = append(errs, field.Forbidden(field.NewPath(p), msgUnknownField))
+	}
+	return errs, nil
+}
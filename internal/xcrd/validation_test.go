@@ -0,0 +1,246 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xcrd
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	admv1 "k8s.io/api/admissionregistration/v1"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func TestClaimValidationWebhookFor(t *testing.T) {
+	w := &ClaimValidationWebhook{
+		Service: extv1.ServiceReference{
+			Namespace: "crossplane-system",
+			Name:      "crossplane-webhooks",
+			Port:      pointer.Int32(9443),
+		},
+		CABundle: []byte("cool-ca"),
+	}
+	xrd := &v1.CompositeResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "xexamples.example.org", UID: "cool-uid"},
+		Spec: v1.CompositeResourceDefinitionSpec{
+			Group:      "example.org",
+			ClaimNames: &extv1.CustomResourceDefinitionNames{Plural: "examples"},
+			Versions: []v1.CompositeResourceDefinitionVersion{
+				{Name: "v1", Served: true},
+				{Name: "v2", Served: false},
+			},
+		},
+	}
+
+	scope := admv1.NamespacedScope
+	fail := admv1.Fail
+	none := admv1.SideEffectClassNone
+	want := &admv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "crossplane-claims-xexamples.example.org",
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion:         v1.CompositeResourceDefinitionGroupVersionKind.GroupVersion().String(),
+				Kind:               v1.CompositeResourceDefinitionKind,
+				Name:               "xexamples.example.org",
+				UID:                "cool-uid",
+				Controller:         pointer.Bool(true),
+				BlockOwnerDeletion: pointer.Bool(true),
+			}},
+		},
+		Webhooks: []admv1.ValidatingWebhook{{
+			Name: "claims.xexamples.example.org",
+			ClientConfig: admv1.WebhookClientConfig{
+				Service: &admv1.ServiceReference{
+					Namespace: "crossplane-system",
+					Name:      "crossplane-webhooks",
+					Port:      pointer.Int32(9443),
+					Path:      pointer.String("/validate-claims/xexamples.example.org"),
+				},
+				CABundle: []byte("cool-ca"),
+			},
+			Rules: []admv1.RuleWithOperations{{
+				Operations: []admv1.OperationType{admv1.Create, admv1.Update},
+				Rule: admv1.Rule{
+					APIGroups:   []string{"example.org"},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"examples"},
+					Scope:       &scope,
+				},
+			}},
+			FailurePolicy:           &fail,
+			SideEffects:             &none,
+			AdmissionReviewVersions: []string{"v1"},
+		}},
+	}
+
+	got := w.For(xrd)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("For(...): -want, +got:\n%s", diff)
+	}
+	if w.Service.Path != nil {
+		t.Errorf("For(...): unexpectedly modified the webhook service path")
+	}
+}
+
+func TestValidateClaim(t *testing.T) {
+	schema := `
+{
+  "properties": {
+    "spec": {
+      "properties": {
+        "parameters": {
+          "properties": {
+            "size": {"type": "integer", "minimum": 1},
+            "region": {"type": "string", "enum": ["us", "eu"]}
+          },
+          "required": ["size"],
+          "type": "object"
+        }
+      },
+      "type": "object"
+    }
+  },
+  "type": "object"
+}`
+	xrd := func(strict bool) *v1.CompositeResourceDefinition {
+		return &v1.CompositeResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "xexamples.example.org"},
+			Spec: v1.CompositeResourceDefinitionSpec{
+				Group:           "example.org",
+				Names:           extv1.CustomResourceDefinitionNames{Plural: "xexamples", Kind: "XExample"},
+				ClaimNames:      &extv1.CustomResourceDefinitionNames{Plural: "examples", Kind: "Example"},
+				ClaimValidation: &v1.ClaimValidation{Strict: strict},
+				Versions: []v1.CompositeResourceDefinitionVersion{{
+					Name:          "v1",
+					Referenceable: true,
+					Served:        true,
+					Schema: &v1.CompositeResourceValidation{
+						OpenAPIV3Schema: runtime.RawExtension{Raw: []byte(schema)},
+					},
+				}},
+			},
+		}
+	}
+	claim := func(apiVersion string, spec map[string]any) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": apiVersion,
+			"kind":       "Example",
+			"metadata":   map[string]any{"name": "cool", "namespace": "default"},
+			"spec":       spec,
+		}}
+	}
+
+	type args struct {
+		xrd *v1.CompositeResourceDefinition
+		u   *unstructured.Unstructured
+	}
+	type want struct {
+		errs field.ErrorList
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"UnknownVersion": {
+			reason: "We should return an error if the claim's version isn't defined by the XRD.",
+			args: args{
+				xrd: xrd(false),
+				u:   claim("example.org/v2", map[string]any{}),
+			},
+			want: want{
+				err: errors.Errorf(errFmtUnknownClaimVersion, "v2"),
+			},
+		},
+		"Valid": {
+			reason: "A claim that matches its schema should be valid.",
+			args: args{
+				xrd: xrd(true),
+				u: claim("example.org/v1", map[string]any{
+					"parameters":                 map[string]any{"size": int64(2), "region": "eu"},
+					"compositionRef":             map[string]any{"name": "cool"},
+					"writeConnectionSecretToRef": map[string]any{"name": "cool"},
+				}),
+			},
+		},
+		"Invalid": {
+			reason: "Fields that don't match the schema should be reported by their path in the claim.",
+			args: args{
+				xrd: xrd(false),
+				u: claim("example.org/v1", map[string]any{
+					"parameters": map[string]any{"region": "mars"},
+				}),
+			},
+			want: want{
+				errs: field.ErrorList{
+					field.NotSupported(field.NewPath("spec", "parameters", "region"), "mars", []string{"us", "eu"}),
+					field.Required(field.NewPath("spec", "parameters", "size"), ""),
+				},
+			},
+		},
+		"UnknownFieldLenient": {
+			reason: "Unknown fields should be allowed unless the XRD enables strict claim validation.",
+			args: args{
+				xrd: xrd(false),
+				u: claim("example.org/v1", map[string]any{
+					"parameters": map[string]any{"size": int64(2), "colour": "blue"},
+				}),
+			},
+		},
+		"UnknownFieldStrict": {
+			reason: "Unknown fields under the claim's spec should be reported if the XRD enables strict claim validation.",
+			args: args{
+				xrd: xrd(true),
+				u: claim("example.org/v1", map[string]any{
+					"parameters": map[string]any{"size": int64(2), "colour": "blue"},
+					"tier":       "gold",
+				}),
+			},
+			want: want{
+				errs: field.ErrorList{
+					field.Forbidden(field.NewPath("spec.parameters.colour"), msgUnknownField),
+					field.Forbidden(field.NewPath("spec.tier"), msgUnknownField),
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			errs, err := ValidateClaim(tc.args.xrd, tc.args.u)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nValidateClaim(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			sort := cmpopts.SortSlices(func(a, b *field.Error) bool { return a.Error() < b.Error() })
+			if diff := cmp.Diff(tc.want.errs, errs, sort, cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("\n%s\nValidateClaim(...): -want errors, +got errors:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+)
+
+const (
+	// ConfigMapSourcePrefix is the prefix of a package source that refers to
+	// a ConfigMap containing the package, in the form
+	// configmap://namespace/name. ConfigMap sources are intended for testing
+	// packages without a registry.
+	ConfigMapSourcePrefix = "configmap://"
+
+	// ConfigMapPackageKey is the key of the ConfigMap data or binary data
+	// that contains a package. The package is a compiled .xpkg file, which
+	// must be base64 encoded if it's stored in the ConfigMap's data.
+	ConfigMapPackageKey = "package" + XpkgExtension
+)
+
+const (
+	errFmtInvalidConfigMapSource = "invalid ConfigMap package source %q: must be of the form configmap://namespace/name"
+	errGetConfigMap              = "cannot get package ConfigMap"
+	errFmtNoConfigMapPackage     = "package ConfigMap has no %q key"
+	errDecodeConfigMapPackage    = "cannot decode base64 encoded package"
+)
+
+// IsConfigMapSource returns true if the supplied package source refers to a
+// ConfigMap.
+func IsConfigMapSource(source string) bool {
+	return strings.HasPrefix(source, ConfigMapSourcePrefix)
+}
+
+// ParseConfigMapSource returns the namespace and name of the ConfigMap the
+// supplied package source refers to.
+func ParseConfigMapSource(source string) (types.NamespacedName, error) {
+	ns, name, ok := strings.Cut(strings.TrimPrefix(source, ConfigMapSourcePrefix), "/")
+	if !IsConfigMapSource(source) || !ok || ns == "" || name == "" || strings.Contains(name, "/") {
+		return types.NamespacedName{}, errors.Errorf(errFmtInvalidConfigMapSource, source)
+	}
+	return types.NamespacedName{Namespace: ns, Name: name}, nil
+}
+
+// ReadConfigMapPackage returns the compiled package contained by the ConfigMap
+// the supplied package source refers to.
+func ReadConfigMapPackage(ctx context.Context, c client.Reader, source string) ([]byte, error) {
+	nn, err := ParseConfigMapSource(source)
+	if err != nil {
+		return nil, err
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, nn, cm); err != nil {
+		return nil, errors.Wrap(err, errGetConfigMap)
+	}
+	if b, ok := cm.BinaryData[ConfigMapPackageKey]; ok {
+		return b, nil
+	}
+	s, ok := cm.Data[ConfigMapPackageKey]
+	if !ok {
+		return nil, errors.Errorf(errFmtNoConfigMapPackage, ConfigMapPackageKey)
+	}
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	return b, errors.Wrap(err, errDecodeConfigMapPackage)
+}
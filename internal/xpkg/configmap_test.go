@@ -0,0 +1,178 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestParseConfigMapSource(t *testing.T) {
+	type want struct {
+		nn  types.NamespacedName
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		source string
+		want   want
+	}{
+		"NotAConfigMap": {
+			reason: "A source that isn't a ConfigMap source should be invalid.",
+			source: "xpkg.upbound.io/crossplane/provider-nop:v0.2.0",
+			want: want{
+				err: errors.Errorf(errFmtInvalidConfigMapSource, "xpkg.upbound.io/crossplane/provider-nop:v0.2.0"),
+			},
+		},
+		"MissingName": {
+			reason: "A ConfigMap source must include a name.",
+			source: "configmap://default",
+			want: want{
+				err: errors.Errorf(errFmtInvalidConfigMapSource, "configmap://default"),
+			},
+		},
+		"TooManySegments": {
+			reason: "A ConfigMap source must include only a namespace and a name.",
+			source: "configmap://default/cool/package",
+			want: want{
+				err: errors.Errorf(errFmtInvalidConfigMapSource, "configmap://default/cool/package"),
+			},
+		},
+		"Valid": {
+			reason: "A valid ConfigMap source should be parsed into a namespace and name.",
+			source: "configmap://default/cool-package",
+			want: want{
+				nn: types.NamespacedName{Namespace: "default", Name: "cool-package"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			nn, err := ParseConfigMapSource(tc.source)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseConfigMapSource(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.nn, nn); diff != "" {
+				t.Errorf("\n%s\nParseConfigMapSource(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReadConfigMapPackage(t *testing.T) {
+	errBoom := errors.New("boom")
+	pkg := []byte("cool-package")
+
+	withConfigMap := func(cm corev1.ConfigMap) test.MockGetFn {
+		return test.NewMockGetFn(nil, func(obj client.Object) error {
+			*obj.(*corev1.ConfigMap) = cm
+			return nil
+		})
+	}
+
+	type args struct {
+		client client.Reader
+		source string
+	}
+	type want struct {
+		pkg []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"InvalidSource": {
+			reason: "We should return an error if the source is invalid.",
+			args: args{
+				source: "configmap://default",
+			},
+			want: want{
+				err: errors.Errorf(errFmtInvalidConfigMapSource, "configmap://default"),
+			},
+		},
+		"GetError": {
+			reason: "We should return an error if we can't get the ConfigMap.",
+			args: args{
+				client: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				source: "configmap://default/cool",
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetConfigMap),
+			},
+		},
+		"NoPackage": {
+			reason: "We should return an error if the ConfigMap doesn't contain a package.",
+			args: args{
+				client: &test.MockClient{MockGet: withConfigMap(corev1.ConfigMap{})},
+				source: "configmap://default/cool",
+			},
+			want: want{
+				err: errors.Errorf(errFmtNoConfigMapPackage, ConfigMapPackageKey),
+			},
+		},
+		"BinaryData": {
+			reason: "We should return a package stored in the ConfigMap's binary data.",
+			args: args{
+				client: &test.MockClient{MockGet: withConfigMap(corev1.ConfigMap{
+					BinaryData: map[string][]byte{ConfigMapPackageKey: pkg},
+				})},
+				source: "configmap://default/cool",
+			},
+			want: want{
+				pkg: pkg,
+			},
+		},
+		"Base64Data": {
+			reason: "We should decode a package stored base64 encoded in the ConfigMap's data.",
+			args: args{
+				client: &test.MockClient{MockGet: withConfigMap(corev1.ConfigMap{
+					Data: map[string]string{ConfigMapPackageKey: base64.StdEncoding.EncodeToString(pkg) + "\n"},
+				})},
+				source: "configmap://default/cool",
+			},
+			want: want{
+				pkg: pkg,
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReadConfigMapPackage(context.Background(), tc.args.client, tc.args.source)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nReadConfigMapPackage(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.pkg, got); diff != "" {
+				t.Errorf("\n%s\nReadConfigMapPackage(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/spf13/afero"
+	"sigs.k8s.io/yaml"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+const (
+	// LabelEmbeddedDependencies is the label set on the config of a package
+	// image built with its dependencies embedded. Its value is a comma
+	// separated list of the digests the dependencies' version constraints
+	// resolved to, e.g. xpkg.upbound.io/crossplane/provider-aws@sha256:...
+	LabelEmbeddedDependencies = "io.crossplane.xpkg.embedded-dependencies"
+)
+
+const (
+	errInvalidDependency     = "invalid dependency"
+	errNoDependencyImage     = "dependency does not specify a provider or configuration package image"
+	errFmtInvalidConstraints = "invalid version constraints %q"
+	errFmtNoMatchingVersion  = "no version of %s satisfies constraints %q"
+	errFetchDependencyTags   = "failed to fetch dependency tags"
+	errHeadDependency        = "failed to fetch dependency descriptor"
+	errGetConfigFile         = "failed to get package image config"
+	errSetConfigFile         = "failed to set package image config"
+)
+
+type metaDependencies struct {
+	Spec struct {
+		DependsOn []pkgmetav1.Dependency `json:"dependsOn"`
+	}
+}
+
+// ParseDependenciesFromMeta extracts the dependencies of a package from its
+// meta file.
+func ParseDependenciesFromMeta(fs afero.Fs, path string) ([]pkgmetav1.Dependency, error) {
+	bs, err := afero.ReadFile(fs, filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	m := &metaDependencies{}
+	if err := yaml.Unmarshal(bs, m); err != nil {
+		return nil, err
+	}
+	return m.Spec.DependsOn, nil
+}
+
+// A ResolvedDependency is a dependency whose version constraints have been
+// resolved to a concrete version.
+type ResolvedDependency struct {
+	// Tag is the newest tag of the dependency's image that satisfies its
+	// version constraints.
+	Tag name.Tag
+
+	// Digest is the digest the tag pointed to when it was resolved.
+	Digest name.Digest
+}
+
+// ResolveDependency resolves the version constraints of the supplied
+// dependency to the newest matching version of its image, and the digest of
+// that version.
+func ResolveDependency(ctx context.Context, f Fetcher, dep pkgmetav1.Dependency) (ResolvedDependency, error) {
+	var pkg string
+	switch {
+	case dep.Provider != nil:
+		pkg = *dep.Provider
+	case dep.Configuration != nil:
+		pkg = *dep.Configuration
+	default:
+		return ResolvedDependency{}, errors.New(errNoDependencyImage)
+	}
+
+	repo, err := name.NewRepository(pkg)
+	if err != nil {
+		return ResolvedDependency{}, errors.Wrap(err, errInvalidDependency)
+	}
+	c, err := semver.NewConstraint(dep.Version)
+	if err != nil {
+		return ResolvedDependency{}, errors.Wrapf(err, errFmtInvalidConstraints, dep.Version)
+	}
+
+	tags, err := f.Tags(ctx, repo.Tag(name.DefaultTag))
+	if err != nil {
+		return ResolvedDependency{}, errors.Wrap(err, errFetchDependencyTags)
+	}
+	vs := []*semver.Version{}
+	for _, t := range tags {
+		v, err := semver.NewVersion(t)
+		if err != nil {
+			// We skip any tags that are not valid semantic versions.
+			continue
+		}
+		vs = append(vs, v)
+	}
+	sort.Sort(semver.Collection(vs))
+	var version string
+	for _, v := range vs {
+		if c.Check(v) {
+			version = v.Original()
+		}
+	}
+	if version == "" {
+		return ResolvedDependency{}, errors.Errorf(errFmtNoMatchingVersion, pkg, dep.Version)
+	}
+
+	tag := repo.Tag(version)
+	d, err := f.Head(ctx, tag)
+	if err != nil {
+		return ResolvedDependency{}, errors.Wrap(err, errHeadDependency)
+	}
+	return ResolvedDependency{Tag: tag, Digest: repo.Digest(d.Digest.String())}, nil
+}
+
+// LabelEmbeddedDependencyDigests returns a copy of the supplied package image
+// that records the supplied dependency digests. The digests are recorded in
+// the image config, rather than as a manifest annotation, so that they are
+// preserved when the image is written to a package file.
+func LabelEmbeddedDependencyDigests(img v1.Image, digests []name.Digest) (v1.Image, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, errors.Wrap(err, errGetConfigFile)
+	}
+	cfg = cfg.DeepCopy()
+	if cfg.Config.Labels == nil {
+		cfg.Config.Labels = map[string]string{}
+	}
+	ds := make([]string, len(digests))
+	for i, d := range digests {
+		ds[i] = d.String()
+	}
+	sort.Strings(ds)
+	cfg.Config.Labels[LabelEmbeddedDependencies] = strings.Join(ds, ",")
+	img, err = mutate.ConfigFile(img, cfg)
+	return img, errors.Wrap(err, errSetConfigFile)
+}
+
+// EmbeddedDependencyDigests returns the dependency digests recorded by the
+// supplied package image, and whether it records any at all. Only a package
+// image built with its dependencies embedded records them.
+func EmbeddedDependencyDigests(img v1.Image) ([]string, bool, error) {
+	cfg, err := img.ConfigFile()
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetConfigFile)
+	}
+	v, ok := cfg.Config.Labels[LabelEmbeddedDependencies]
+	if !ok {
+		return nil, false, nil
+	}
+	if v == "" {
+		return []string{}, true, nil
+	}
+	return strings.Split(v, ","), true, nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+// tagsFetcher is a Fetcher that returns a fixed set of tags and digest.
+type tagsFetcher struct {
+	NopFetcher
+	tags   []string
+	digest v1.Hash
+	err    error
+}
+
+func (f *tagsFetcher) Tags(_ context.Context, _ name.Reference, _ ...string) ([]string, error) {
+	return f.tags, f.err
+}
+
+func (f *tagsFetcher) Head(_ context.Context, _ name.Reference, _ ...string) (*v1.Descriptor, error) {
+	return &v1.Descriptor{Digest: f.digest}, nil
+}
+
+func TestResolveDependency(t *testing.T) {
+	errBoom := errors.New("boom")
+	provider := "xpkg.upbound.io/crossplane/provider-aws"
+	digest := v1.Hash{Algorithm: "sha256", Hex: "8f7b3fc4bd7b6e0b9b6e8bdf9c2f5a5a1e2d6b0d5c0a9c8b7a6f5e4d3c2b1a09"}
+
+	type want struct {
+		tag    string
+		digest string
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		f      Fetcher
+		dep    pkgmetav1.Dependency
+		want   want
+	}{
+		"NoPackage": {
+			reason: "We should return an error if the dependency doesn't specify a package.",
+			f:      &tagsFetcher{},
+			dep:    pkgmetav1.Dependency{Version: ">=v1.0.0"},
+			want: want{
+				err: errors.New(errNoDependencyImage),
+			},
+		},
+		"TagsError": {
+			reason: "We should return any error encountered fetching tags.",
+			f:      &tagsFetcher{err: errBoom},
+			dep:    pkgmetav1.Dependency{Provider: &provider, Version: ">=v1.0.0"},
+			want: want{
+				err: errors.Wrap(errBoom, errFetchDependencyTags),
+			},
+		},
+		"NoMatchingVersion": {
+			reason: "We should return an error if no tag satisfies the version constraints.",
+			f:      &tagsFetcher{tags: []string{"v0.1.0", "latest"}},
+			dep:    pkgmetav1.Dependency{Provider: &provider, Version: ">=v1.0.0"},
+			want: want{
+				err: errors.Errorf(errFmtNoMatchingVersion, provider, ">=v1.0.0"),
+			},
+		},
+		"Resolved": {
+			reason: "We should resolve the newest tag that satisfies the version constraints to its digest.",
+			f:      &tagsFetcher{tags: []string{"v1.2.0", "latest", "v2.0.0", "v1.10.0", "v0.1.0"}, digest: digest},
+			dep:    pkgmetav1.Dependency{Provider: &provider, Version: ">=v1.0.0, <v2.0.0"},
+			want: want{
+				tag:    provider + ":v1.10.0",
+				digest: provider + "@" + digest.String(),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r, err := ResolveDependency(context.Background(), tc.f, tc.dep)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nResolveDependency(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.tag, r.Tag.String()); diff != "" {
+				t.Errorf("\n%s\nResolveDependency(...): -want tag, +got tag:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.digest, r.Digest.String()); diff != "" {
+				t.Errorf("\n%s\nResolveDependency(...): -want digest, +got digest:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEmbeddedDependencyDigests(t *testing.T) {
+	a, _ := name.NewDigest("xpkg.upbound.io/crossplane/provider-b@sha256:8f7b3fc4bd7b6e0b9b6e8bdf9c2f5a5a1e2d6b0d5c0a9c8b7a6f5e4d3c2b1a09")
+	b, _ := name.NewDigest("xpkg.upbound.io/crossplane/provider-a@sha256:8f7b3fc4bd7b6e0b9b6e8bdf9c2f5a5a1e2d6b0d5c0a9c8b7a6f5e4d3c2b1a09")
+
+	type want struct {
+		digests []string
+		ok      bool
+	}
+
+	cases := map[string]struct {
+		reason  string
+		digests []name.Digest
+		label   bool
+		want    want
+	}{
+		"NotLabelled": {
+			reason: "An image that wasn't built with embedded dependencies should not record any.",
+			want:   want{},
+		},
+		"NoDependencies": {
+			reason: "An image built with embedded dependencies but no dependencies should record none.",
+			label:  true,
+			want:   want{digests: []string{}, ok: true},
+		},
+		"Dependencies": {
+			reason:  "An image built with embedded dependencies should record their sorted digests.",
+			digests: []name.Digest{a, b},
+			label:   true,
+			want:    want{digests: []string{b.String(), a.String()}, ok: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			img := empty.Image
+			if tc.label {
+				var err error
+				img, err = LabelEmbeddedDependencyDigests(img, tc.digests)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			digests, ok, err := EmbeddedDependencyDigests(img)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, want{digests: digests, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Errorf("\n%s\nEmbeddedDependencyDigests(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
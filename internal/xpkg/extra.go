@@ -0,0 +1,62 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+const (
+	errFmtParseExtraObject  = "cannot parse extra object at index %d"
+	errFmtExtraObjectNoKind = "extra object at index %d must specify an apiVersion and kind"
+	errFmtExtraObjectNoName = "extra object at index %d must specify a name"
+)
+
+// ParseExtraObjects parses the extra objects declared in the supplied package
+// metadata. Objects that don't specify a namespace are placed in the supplied
+// namespace. This doesn't know which objects are cluster scoped, so it sets
+// the namespace of those too. The API server ignores it when they're created.
+func ParseExtraObjects(pkg pkgmetav1.Pkg, namespace string) ([]runtime.Object, error) {
+	raw := pkg.GetExtraObjects()
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	objs := make([]runtime.Object, 0, len(raw))
+	for i, r := range raw {
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(r.Raw); err != nil {
+			return nil, errors.Wrapf(err, errFmtParseExtraObject, i)
+		}
+		if u.GetAPIVersion() == "" || u.GetKind() == "" {
+			return nil, errors.Errorf(errFmtExtraObjectNoKind, i)
+		}
+		if u.GetName() == "" {
+			return nil, errors.Errorf(errFmtExtraObjectNoName, i)
+		}
+		if u.GetNamespace() == "" {
+			u.SetNamespace(namespace)
+		}
+		objs = append(objs, u)
+	}
+	return objs, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	pkgmetav1 "github.com/crossplane/crossplane/apis/pkg/meta/v1"
+)
+
+func TestParseExtraObjects(t *testing.T) {
+	withExtraObjects := func(raw ...string) pkgmetav1.Pkg {
+		p := &pkgmetav1.Provider{}
+		for _, r := range raw {
+			p.Spec.ExtraObjects = append(p.Spec.ExtraObjects, runtime.RawExtension{Raw: []byte(r)})
+		}
+		return p
+	}
+
+	type args struct {
+		pkg       pkgmetav1.Pkg
+		namespace string
+	}
+	type want struct {
+		objs []runtime.Object
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoExtraObjects": {
+			reason: "Packages that don't declare extra objects should produce no objects.",
+			args: args{
+				pkg: withExtraObjects(),
+			},
+			want: want{},
+		},
+		"InvalidObject": {
+			reason: "We should return an error if an extra object can't be parsed.",
+			args: args{
+				pkg: withExtraObjects(`{"apiVersion":"v1"}`),
+			},
+			want: want{
+				err: errors.Wrapf(errors.New("Object 'Kind' is missing in '{\"apiVersion\":\"v1\"}'"), errFmtParseExtraObject, 0),
+			},
+		},
+		"NoName": {
+			reason: "We should return an error if an extra object doesn't specify a name.",
+			args: args{
+				pkg: withExtraObjects(`{"apiVersion":"v1","kind":"ConfigMap"}`),
+			},
+			want: want{
+				err: errors.Errorf(errFmtExtraObjectNoName, 0),
+			},
+		},
+		"DefaultNamespace": {
+			reason: "Extra objects that don't specify a namespace should be placed in the supplied namespace.",
+			args: args{
+				pkg:       withExtraObjects(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cool-config"}}`),
+				namespace: "crossplane-system",
+			},
+			want: want{
+				objs: []runtime.Object{
+					&unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata": map[string]any{
+							"name":      "cool-config",
+							"namespace": "crossplane-system",
+						},
+					}},
+				},
+			},
+		},
+		"ExplicitNamespace": {
+			reason: "Extra objects that specify a namespace should keep it.",
+			args: args{
+				pkg:       withExtraObjects(`{"apiVersion":"v1","kind":"ServiceAccount","metadata":{"name":"cool-sa","namespace":"cool-ns"}}`),
+				namespace: "crossplane-system",
+			},
+			want: want{
+				objs: []runtime.Object{
+					&unstructured.Unstructured{Object: map[string]any{
+						"apiVersion": "v1",
+						"kind":       "ServiceAccount",
+						"metadata": map[string]any{
+							"name":      "cool-sa",
+							"namespace": "cool-ns",
+						},
+					}},
+				},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			objs, err := ParseExtraObjects(tc.args.pkg, tc.args.namespace)
+
+			if diff := cmp.Diff(tc.want.objs, objs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nParseExtraObjects(...): -want, +got:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nParseExtraObjects(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
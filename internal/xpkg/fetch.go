@@ -22,7 +22,11 @@ import (
 	"crypto/x509"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
+	"github.com/google/go-containerregistry/pkg/authn"
 	"github.com/google/go-containerregistry/pkg/authn/k8schain"
 	"github.com/google/go-containerregistry/pkg/name"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -57,6 +61,16 @@ type K8sFetcher struct {
 	serviceAccount string
 	transport      http.RoundTripper
 	userAgent      string
+
+	authRefreshInterval time.Duration
+	now                 func() time.Time
+	mu                  sync.Mutex
+	keychains           map[string]cachedKeychain
+}
+
+type cachedKeychain struct {
+	authn.Keychain
+	created time.Time
 }
 
 // FetcherOpt can be used to add optional parameters to NewK8sFetcher
@@ -106,11 +120,25 @@ func WithServiceAccount(sa string) FetcherOpt {
 	}
 }
 
-// NewK8sFetcher creates a new K8sFetcher.
+// WithAuthRefreshInterval is a FetcherOpt that caches the credentials read
+// from package pull secrets for the supplied interval, after which they are
+// read again. Credentials are read every time a package is fetched if the
+// interval is zero.
+func WithAuthRefreshInterval(d time.Duration) FetcherOpt {
+	return func(k *K8sFetcher) error {
+		k.authRefreshInterval = d
+		return nil
+	}
+}
+
+// NewK8sFetcher creates a new K8sFetcher. The supplied client may be nil, in
+// which case package pull secrets are ignored.
 func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher, error) {
 	k := &K8sFetcher{
 		client:    client,
 		transport: remote.DefaultTransport.(*http.Transport).Clone(),
+		now:       time.Now,
+		keychains: map[string]cachedKeychain{},
 	}
 
 	for _, o := range opts {
@@ -122,13 +150,57 @@ func NewK8sFetcher(client kubernetes.Interface, opts ...FetcherOpt) (*K8sFetcher
 	return k, nil
 }
 
-// Fetch fetches a package image.
-func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
+// keychain returns the keychain used to authenticate to registries. Pull
+// secrets can only be read from the API server, so a K8sFetcher without a
+// Kubernetes client authenticates using only the local Docker config and
+// cloud provider credential helpers.
+func (i *K8sFetcher) keychain(ctx context.Context, secrets ...string) (authn.Keychain, error) {
+	if i.client == nil {
+		return k8schain.NewNoClient(ctx)
+	}
+	if i.authRefreshInterval <= 0 {
+		return i.newKeychain(ctx, secrets...)
+	}
+
+	key := strings.Join(secrets, ",")
+	i.mu.Lock()
+	kc, ok := i.keychains[key]
+	i.mu.Unlock()
+	if ok && i.now().Sub(kc.created) < i.authRefreshInterval {
+		return kc.Keychain, nil
+	}
+
+	// We build the keychain without holding the lock, because doing so reads
+	// from the API server. This means concurrent fetches may each build one
+	// when the cached keychain expires, but they won't block other fetches.
+	nkc, err := i.newKeychain(ctx, secrets...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := i.now()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for k, kc := range i.keychains {
+		if now.Sub(kc.created) >= i.authRefreshInterval {
+			delete(i.keychains, k)
+		}
+	}
+	i.keychains[key] = cachedKeychain{Keychain: nkc, created: now}
+	return nkc, nil
+}
+
+func (i *K8sFetcher) newKeychain(ctx context.Context, secrets ...string) (authn.Keychain, error) {
+	return k8schain.New(ctx, i.client, k8schain.Options{
 		Namespace:          i.namespace,
 		ServiceAccountName: i.serviceAccount,
 		ImagePullSecrets:   secrets,
 	})
+}
+
+// Fetch fetches a package image.
+func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...string) (v1.Image, error) {
+	auth, err := i.keychain(ctx, secrets...)
 	if err != nil {
 		return nil, err
 	}
@@ -142,11 +214,7 @@ func (i *K8sFetcher) Fetch(ctx context.Context, ref name.Reference, secrets ...s
 
 // Head fetches a package descriptor.
 func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...string) (*v1.Descriptor, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
-	})
+	auth, err := i.keychain(ctx, secrets...)
 	if err != nil {
 		return nil, err
 	}
@@ -173,11 +241,7 @@ func (i *K8sFetcher) Head(ctx context.Context, ref name.Reference, secrets ...st
 
 // Tags fetches a package's tags.
 func (i *K8sFetcher) Tags(ctx context.Context, ref name.Reference, secrets ...string) ([]string, error) {
-	auth, err := k8schain.New(ctx, i.client, k8schain.Options{
-		Namespace:          i.namespace,
-		ServiceAccountName: i.serviceAccount,
-		ImagePullSecrets:   secrets,
-	})
+	auth, err := i.keychain(ctx, secrets...)
 	if err != nil {
 		return nil, err
 	}
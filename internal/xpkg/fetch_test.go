@@ -0,0 +1,145 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package xpkg
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-containerregistry/pkg/name"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestKeychainAuthRefresh(t *testing.T) {
+	secret := func(password string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "crossplane-system", Name: "pull-secret"},
+			Type:       corev1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{
+				corev1.DockerConfigJsonKey: []byte(fmt.Sprintf(`{"auths":{"registry.example.org":{"username":"cool","password":%q}}}`, password)),
+			},
+		}
+	}
+	repo, _ := name.NewRepository("registry.example.org/cool/provider")
+
+	type want struct {
+		beforeRefresh string
+		afterRefresh  string
+	}
+
+	cases := map[string]struct {
+		reason   string
+		interval time.Duration
+		elapsed  time.Duration
+		want     want
+	}{
+		"NoInterval": {
+			reason:   "A changed secret should take effect immediately if credentials aren't cached.",
+			interval: 0,
+			want:     want{beforeRefresh: "new", afterRefresh: "new"},
+		},
+		"WithinInterval": {
+			reason:   "A changed secret should not take effect until the refresh interval has elapsed.",
+			interval: time.Minute,
+			elapsed:  30 * time.Second,
+			want:     want{beforeRefresh: "old", afterRefresh: "new"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			cs := fake.NewSimpleClientset(secret("old"))
+			now := time.Now()
+
+			f, err := NewK8sFetcher(cs, WithNamespace("crossplane-system"), WithServiceAccount("crossplane"), WithAuthRefreshInterval(tc.interval))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.now = func() time.Time { return now }
+
+			password := func() string {
+				kc, err := f.keychain(ctx, "pull-secret")
+				if err != nil {
+					t.Fatal(err)
+				}
+				a, err := kc.Resolve(repo)
+				if err != nil {
+					t.Fatal(err)
+				}
+				cfg, err := a.Authorization()
+				if err != nil {
+					t.Fatal(err)
+				}
+				return cfg.Password
+			}
+
+			// Prime the cache, then change the secret.
+			_ = password()
+			if _, err := cs.CoreV1().Secrets("crossplane-system").Update(ctx, secret("new"), metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			now = now.Add(tc.elapsed)
+			if diff := cmp.Diff(tc.want.beforeRefresh, password()); diff != "" {
+				t.Errorf("\n%s\nkeychain(...): -want password before refresh, +got:\n%s", tc.reason, diff)
+			}
+
+			now = now.Add(tc.interval)
+			if diff := cmp.Diff(tc.want.afterRefresh, password()); diff != "" {
+				t.Errorf("\n%s\nkeychain(...): -want password after refresh, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestKeychainCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+
+	f, err := NewK8sFetcher(fake.NewSimpleClientset(), WithNamespace("crossplane-system"), WithServiceAccount("crossplane"), WithAuthRefreshInterval(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.now = func() time.Time { return now }
+
+	for _, secrets := range [][]string{{"a"}, {"a", "b"}} {
+		if _, err := f.keychain(ctx, secrets...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Once the refresh interval has elapsed, building a keychain for one set of
+	// pull secrets should drop the expired keychains for all others.
+	now = now.Add(time.Minute)
+	if _, err := f.keychain(ctx, "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	got := make([]string, 0, len(f.keychains))
+	for k := range f.keychains {
+		got = append(got, k)
+	}
+	if diff := cmp.Diff([]string{"c"}, got); diff != "" {
+		t.Errorf("keychain(...): -want cached keychains, +got:\n%s", diff)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/Masterminds/semver"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/spf13/afero"
 	"sigs.k8s.io/yaml"
@@ -59,7 +60,12 @@ func truncate(str string, num int) string {
 }
 
 // FriendlyID builds a valid DNS label string made up of the name of a package
-// and its image digest.
+// and its image digest. It is the canonical name of a package revision; all
+// package revisions are named using it. A revision's name is derived from the
+// content it installs, not from its revision number, so the same content
+// always produces the same revision name. Packages are cluster scoped, and each
+// kind of package has its own kind of revision, so names need not encode a
+// namespace or kind.
 func FriendlyID(name, hash string) string {
 	return ToDNSLabel(strings.Join([]string{truncate(name, 50), truncate(hash, 12)}, "-"))
 }
@@ -113,6 +119,23 @@ func ParsePackageSourceFromReference(ref name.Reference) string {
 	return strings.TrimRight(strings.TrimSuffix(ref.String(), ref.Identifier()), identifierDelimeters)
 }
 
+// ParsePackageVersionFromReference parses the semantic version of a package
+// from the tag of its reference. The version is normalized, such that v1.2.0
+// and 1.2.0 are parsed as the same version. An empty string is returned if the
+// reference is not tagged with a semantic version, for example because it is a
+// digest.
+func ParsePackageVersionFromReference(ref name.Reference) string {
+	t, ok := ref.(name.Tag)
+	if !ok {
+		return ""
+	}
+	v, err := semver.NewVersion(t.TagStr())
+	if err != nil {
+		return ""
+	}
+	return v.String()
+}
+
 type metaPkg struct {
 	Metadata struct {
 		Name string `json:"name"`
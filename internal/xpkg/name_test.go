@@ -180,6 +180,57 @@ func TestSourceFromReference(t *testing.T) {
 	}
 }
 
+func TestVersionFromReference(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		arg    name.Reference
+		want   string
+	}{
+		"SemanticVersionTag": {
+			reason: "A semantic version tag should be parsed as a version.",
+			arg: func() name.Reference {
+				ref, _ := name.ParseReference("hasheddan/xpkg-test:1.2.0")
+				return ref
+			}(),
+			want: "1.2.0",
+		},
+		"PrefixedSemanticVersionTag": {
+			reason: "A semantic version tag prefixed with v should be parsed as the same version as one that is not.",
+			arg: func() name.Reference {
+				ref, _ := name.ParseReference("hasheddan/xpkg-test:v1.2.0")
+				return ref
+			}(),
+			want: "1.2.0",
+		},
+		"NonSemanticVersionTag": {
+			reason: "A tag that is not a semantic version should not be parsed as a version.",
+			arg: func() name.Reference {
+				ref, _ := name.ParseReference("hasheddan/xpkg-test:latest")
+				return ref
+			}(),
+			want: "",
+		},
+		"Digest": {
+			reason: "A digest should not be parsed as a version.",
+			arg: func() name.Reference {
+				ref, _ := name.ParseReference("hasheddan/xpkg-test@sha256:c88b938d6e7b2ed43d40b71e5a55df9c60fa653bea0c0961f3294fac46d5b56e")
+				return ref
+			}(),
+			want: "",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ParsePackageVersionFromReference(tc.arg)
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nParsePackageVersionFromReference(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
 func TestBuildPath(t *testing.T) {
 	type args struct {
 		path string
@@ -0,0 +1,143 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"encoding/json"
+
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/schema/pruning"
+	"k8s.io/apiextensions-apiserver/pkg/apiserver/validation"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"github.com/crossplane/crossplane-runtime/pkg/errors"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+	verrors "github.com/crossplane/crossplane/internal/validation/errors"
+)
+
+const (
+	errUnableToParseConfig       = "cannot parse function config"
+	errNewConfigSchemaValidator  = "cannot build function config schema validator"
+	errNewConfigStructuralSchema = "cannot build structural function config schema"
+
+	errFmtConfigVersionUndefined = "version %q is not defined by the CRD of kind %s"
+
+	msgUnknownConfigField = "field is not declared by the function config's schema"
+)
+
+// GetFunctionConfig returns the config of the supplied Function, or nil if it
+// has none.
+func GetFunctionConfig(fn *v1.Function) (*unstructured.Unstructured, error) {
+	if fn.Config == nil {
+		return nil, nil
+	}
+	u := &unstructured.Unstructured{}
+	if err := json.Unmarshal(fn.Config.Raw, u); err != nil {
+		return nil, errors.Wrap(err, errUnableToParseConfig)
+	}
+	return u, nil
+}
+
+// GetFunctionConfigGVK returns the GroupVersionKind of the config of the
+// supplied Function. It returns an empty GroupVersionKind if the Function has
+// no config.
+func GetFunctionConfigGVK(fn *v1.Function) (schema.GroupVersionKind, error) {
+	u, err := GetFunctionConfig(fn)
+	if err != nil || u == nil {
+		return schema.GroupVersionKind{}, err
+	}
+	return u.GroupVersionKind(), nil
+}
+
+// validateFunctionConfigsWithSchemas validates the config of each of the
+// Composition's Functions against the schema of the CRD that defines its kind.
+// Fields that aren't declared by the schema are rejected. Configs whose kind
+// isn't defined by a CRD aren't validated.
+func (v *Validator) validateFunctionConfigsWithSchemas(ctx context.Context, comp *v1.Composition) (errs field.ErrorList) {
+	for i := range comp.Spec.Functions {
+		p := field.NewPath("spec", "functions").Index(i).Child("config")
+		u, err := GetFunctionConfig(&comp.Spec.Functions[i])
+		if err != nil {
+			errs = append(errs, field.Invalid(p, string(comp.Spec.Functions[i].Config.Raw), err.Error()))
+			continue
+		}
+		if u == nil {
+			continue
+		}
+		gvk := u.GroupVersionKind()
+		crd, err := v.crdGetter.Get(ctx, gvk.GroupKind())
+		if apierrors.IsNotFound(err) || (err == nil && crd == nil) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, field.InternalError(p, err))
+			continue
+		}
+		if !definesVersion(crd, gvk.Version) {
+			errs = append(errs, field.Invalid(p.Child("apiVersion"), u.GetAPIVersion(), errors.Errorf(errFmtConfigVersionUndefined, gvk.Version, gvk.GroupKind()).Error()))
+			continue
+		}
+		s := getSchemaForVersion(crd, gvk.Version)
+		if s == nil {
+			continue
+		}
+		cerrs, err := validateFunctionConfig(u, s)
+		if err != nil {
+			errs = append(errs, field.InternalError(p, err))
+			continue
+		}
+		errs = append(errs, verrors.WrapFieldErrorList(cerrs, p)...)
+	}
+	return errs
+}
+
+func definesVersion(crd *apiextensions.CustomResourceDefinition, version string) bool {
+	for _, v := range crd.Spec.Versions {
+		if v.Name == version {
+			return true
+		}
+	}
+	return false
+}
+
+// validateFunctionConfig validates the supplied function config against the
+// supplied schema, as the API server would validate a custom resource.
+// Fields that aren't declared by the schema, and that the API server would
+// therefore silently prune, are reported too.
+func validateFunctionConfig(u *unstructured.Unstructured, s *apiextensions.JSONSchemaProps) (field.ErrorList, error) {
+	sv, _, err := validation.NewSchemaValidator(&apiextensions.CustomResourceValidation{OpenAPIV3Schema: s})
+	if err != nil {
+		return nil, errors.Wrap(err, errNewConfigSchemaValidator)
+	}
+	errs := validation.ValidateCustomResource(nil, u.UnstructuredContent(), sv)
+
+	ss, err := structuralschema.NewStructural(s)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewConfigStructuralSchema)
+	}
+	unknown := pruning.PruneWithOptions(u.DeepCopy().UnstructuredContent(), ss, true, structuralschema.UnknownFieldPathOptions{TrackUnknownFieldPaths: true})
+	for _, p := range unknown {
+		errs = append(errs, field.Forbidden(field.NewPath(p), msgUnknownConfigField))
+	}
+	return errs, nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2023 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package composition
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	extv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	v1 "github.com/crossplane/crossplane/apis/apiextensions/v1"
+)
+
+func withFunctionConfig(t *testing.T, config map[string]any) compositionBuilderOption {
+	t.Helper()
+	return func(c *v1.Composition) {
+		c.Spec.Functions = append(c.Spec.Functions, v1.Function{
+			Name:   "fn",
+			Type:   v1.FunctionTypeContainer,
+			Config: &runtime.RawExtension{Raw: marshalJSON(t, config)},
+		})
+	}
+}
+
+func TestValidateFunctionConfigsWithSchemas(t *testing.T) {
+	configCRD := newCRDBuilder("Config", "v1").withOption(specSchemaOption("v1", extv1.JSONSchemaProps{
+		Type:     "object",
+		Required: []string{"region"},
+		Properties: map[string]extv1.JSONSchemaProps{
+			"region": {Type: "string"},
+			"size":   {Type: "integer"},
+		},
+	})).build()
+
+	config := func(spec map[string]any) map[string]any {
+		return map[string]any{
+			"apiVersion": testGroup + "/v1",
+			"kind":       "Config",
+			"spec":       spec,
+		}
+	}
+
+	type args struct {
+		comp    *v1.Composition
+		gkToCRD map[schema.GroupKind]apiextensions.CustomResourceDefinition
+	}
+	type want struct {
+		errs field.ErrorList
+	}
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoFunctions": {
+			reason: "Should accept a Composition with no functions.",
+			args: args{
+				comp:    buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil),
+				gkToCRD: buildGkToCRDs(configCRD),
+			},
+		},
+		"NoCRD": {
+			reason: "Should not validate a function config whose kind isn't defined by a CRD.",
+			args: args{
+				comp:    buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil, withFunctionConfig(t, config(map[string]any{"regoin": "us-west-2"}))),
+				gkToCRD: buildGkToCRDs(),
+			},
+		},
+		"ValidConfig": {
+			reason: "Should accept a function config that is valid according to its CRD.",
+			args: args{
+				comp:    buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil, withFunctionConfig(t, config(map[string]any{"region": "us-west-2", "size": 3}))),
+				gkToCRD: buildGkToCRDs(configCRD),
+			},
+		},
+		"InvalidConfig": {
+			reason: "Should reject a function config with fields that are invalid according to its CRD.",
+			args: args{
+				comp:    buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil, withFunctionConfig(t, config(map[string]any{"region": "us-west-2", "size": "large"}))),
+				gkToCRD: buildGkToCRDs(configCRD),
+			},
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeTypeInvalid,
+						Field: "spec.functions[0].config.spec.size",
+					},
+				},
+			},
+		},
+		"UnknownField": {
+			reason: "Should reject a function config with fields that aren't declared by its CRD, e.g. typos.",
+			args: args{
+				comp:    buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil, withFunctionConfig(t, config(map[string]any{"region": "us-west-2", "szie": 3}))),
+				gkToCRD: buildGkToCRDs(configCRD),
+			},
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeForbidden,
+						Field: "spec.functions[0].config.spec.szie",
+					},
+				},
+			},
+		},
+		"UndefinedVersion": {
+			reason: "Should reject a function config whose version isn't defined by its CRD.",
+			args: args{
+				comp: buildDefaultComposition(t, v1.CompositionValidationModeStrict, nil, withFunctionConfig(t, map[string]any{
+					"apiVersion": testGroup + "/v2",
+					"kind":       "Config",
+				})),
+				gkToCRD: buildGkToCRDs(configCRD),
+			},
+			want: want{
+				errs: field.ErrorList{
+					{
+						Type:  field.ErrorTypeInvalid,
+						Field: "spec.functions[0].config.apiVersion",
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			v, err := NewValidator(WithCRDGetterFromMap(tc.args.gkToCRD))
+			if err != nil {
+				t.Fatalf("NewValidator(...) = %v", err)
+			}
+			got := v.validateFunctionConfigsWithSchemas(context.TODO(), tc.args.comp)
+			if diff := cmp.Diff(tc.want.errs, got, sortFieldErrors(), cmpopts.IgnoreFields(field.Error{}, "Detail", "BadValue")); diff != "" {
+				t.Errorf("%s\nvalidateFunctionConfigsWithSchemas(...) = -want, +got\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -123,8 +123,9 @@ func (v *Validator) Validate(ctx context.Context, obj runtime.Object) (warns []s
 
 	// Validate the Composition itself
 	if v.logicalValidation != nil {
-		if warns, errs := v.logicalValidation(comp); len(errs) != 0 {
-			return warns, errs
+		var lerrs field.ErrorList
+		if warns, lerrs = v.logicalValidation(comp); len(lerrs) != 0 {
+			return warns, lerrs
 		}
 	}
 
@@ -134,11 +135,12 @@ func (v *Validator) Validate(ctx context.Context, obj runtime.Object) (warns []s
 		v.validateReadinessChecksWithSchemas,
 		v.validateConnectionDetailsWithSchemas,
 		v.validateEnvironmentPatchesWithSchemas,
+		v.validateFunctionConfigsWithSchemas,
 		// TODO(phisco): add more phase 2 validation here
 	} {
 		errs = append(errs, f(ctx, comp)...)
 	}
 
 	// TODO(phisco): add more  phase 3 validation here
-	return nil, errs
+	return warns, errs
 }